@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// budget of a benchmark, 0 leaves a measure unchecked. Allocations don't
+// depend on the machine so their budget is tight, time budgets leave room for
+// slower runners
+type threshold struct {
+	NsPerOp     float64 `json:"nsPerOp"`
+	AllocsPerOp int64   `json:"allocsPerOp"`
+}
+
+type measure struct {
+	NsPerOp     float64
+	AllocsPerOp int64
+}
+
+// "BenchmarkDecodeWebhook-8  7182  171106 ns/op  158.79 MB/s  19732 B/op  596 allocs/op"
+var (
+	benchmarkLine = regexp.MustCompile(`^(Benchmark[^\s-]+)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op`)
+	allocsPerOp   = regexp.MustCompile(`\s(\d+) allocs/op`)
+)
+
+// fails when a benchmark of the go test -bench output on stdin is over its
+// threshold, or a benchmark with a threshold didn't run
+//
+//	go test -run '^$' -bench . -benchmem ./... | go run ./cmd/benchcheck -thresholds ../../tools/scripts/benchmarks.json
+func main() {
+	path := flag.String("thresholds", "benchmarks.json", "thresholds by benchmark name")
+	flag.Parse()
+
+	data, err := os.ReadFile(*path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	thresholds := map[string]threshold{}
+	if err := json.Unmarshal(data, &thresholds); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *path, err)
+		os.Exit(2)
+	}
+
+	measures, err := parse(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	regressions := check(measures, thresholds)
+	for _, regression := range regressions {
+		fmt.Println(regression)
+	}
+	if len(regressions) > 0 {
+		os.Exit(1)
+	}
+	fmt.Printf("%d benchmarks within their thresholds\n", len(measures))
+}
+
+// best run of each benchmark, go test -count repeats them
+func parse(output io.Reader) (map[string]measure, error) {
+	measures := map[string]measure{}
+
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		line := scanner.Text()
+		match := benchmarkLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		ns, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			return nil, err
+		}
+		current := measure{NsPerOp: ns, AllocsPerOp: -1}
+		if allocs := allocsPerOp.FindStringSubmatch(line); allocs != nil {
+			current.AllocsPerOp, _ = strconv.ParseInt(allocs[1], 10, 64)
+		}
+
+		if best, seen := measures[match[1]]; seen {
+			current.NsPerOp = min(current.NsPerOp, best.NsPerOp)
+			if current.AllocsPerOp < 0 || (best.AllocsPerOp >= 0 && best.AllocsPerOp < current.AllocsPerOp) {
+				current.AllocsPerOp = best.AllocsPerOp
+			}
+		}
+		measures[match[1]] = current
+	}
+	return measures, scanner.Err()
+}
+
+// regressions sorted by benchmark
+func check(measures map[string]measure, thresholds map[string]threshold) []string {
+	names := []string{}
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	regressions := []string{}
+	for _, name := range names {
+		limit := thresholds[name]
+		got, ok := measures[name]
+		if !ok {
+			regressions = append(regressions, fmt.Sprintf("%s: not run", name))
+			continue
+		}
+
+		if limit.NsPerOp > 0 && got.NsPerOp > limit.NsPerOp {
+			regressions = append(regressions, fmt.Sprintf("%s: %.0f ns/op over %.0f", name, got.NsPerOp, limit.NsPerOp))
+		}
+		if limit.AllocsPerOp > 0 && got.AllocsPerOp < 0 {
+			regressions = append(regressions, fmt.Sprintf("%s: no allocs/op, run with -benchmem", name))
+		} else if limit.AllocsPerOp > 0 && got.AllocsPerOp > limit.AllocsPerOp {
+			regressions = append(regressions, fmt.Sprintf("%s: %d allocs/op over %d", name, got.AllocsPerOp, limit.AllocsPerOp))
+		}
+	}
+	return regressions
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const output = `goos: linux
+goarch: amd64
+pkg: slack-pr-lambda/types
+BenchmarkDecodeWebhook-8   	    7182	    171106 ns/op	 158.79 MB/s	   19732 B/op	     596 allocs/op
+BenchmarkDecodeWebhook-8   	    7000	    165000 ns/op	 160.01 MB/s	   19732 B/op	     596 allocs/op
+BenchmarkDrift             	    1836	    863956 ns/op	  31.45 MB/s
+PASS
+ok  	slack-pr-lambda/types	2.907s
+`
+
+func TestParse(t *testing.T) {
+	measures, err := parse(strings.NewReader(output))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]measure{
+		"BenchmarkDecodeWebhook": {NsPerOp: 165000, AllocsPerOp: 596},
+		"BenchmarkDrift":         {NsPerOp: 863956, AllocsPerOp: -1},
+	}, measures)
+}
+
+func TestCheck(t *testing.T) {
+	measures, err := parse(strings.NewReader(output))
+	assert.NoError(t, err)
+
+	assert.Empty(t, check(measures, map[string]threshold{
+		"BenchmarkDecodeWebhook": {NsPerOp: 500000, AllocsPerOp: 650},
+		"BenchmarkDrift":         {NsPerOp: 2000000},
+	}))
+
+	assert.Equal(t, []string{
+		"BenchmarkDecodeWebhook: 165000 ns/op over 100000",
+		"BenchmarkDecodeWebhook: 596 allocs/op over 500",
+		"BenchmarkDrift: no allocs/op, run with -benchmem",
+		"BenchmarkHomeBlocks: not run",
+	}, check(measures, map[string]threshold{
+		"BenchmarkDecodeWebhook": {NsPerOp: 100000, AllocsPerOp: 500},
+		"BenchmarkDrift":         {AllocsPerOp: 3000},
+		"BenchmarkHomeBlocks":    {AllocsPerOp: 1200},
+	}))
+}
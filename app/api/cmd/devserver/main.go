@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slack-pr-lambda/api/routes"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/slackstub"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// defaults for running without AWS / Slack credentials, already set variables win
+func devEnv(slackApiUrl string) map[string]string {
+	return map[string]string{
+		"ENV":                   "local",
+		"DB_ENDPOINT":           "http://localhost:8000",
+		"REGION":                "us-east-1",
+		"AWS_ACCESS_KEY_ID":     "local",
+		"AWS_SECRET_ACCESS_KEY": "local",
+		"SLACK_API_URL":         slackApiUrl,
+		"SLACK_TOKEN":           "xoxb-dev",
+		"SLACK_CHANNEL":         "CDEV",
+	}
+}
+
+// handlers wired against dynamodb-local and the Slack stub
+//
+//	docker-compose up -d
+//	go run ./cmd/devserver
+func main() {
+	zapLog, err := logger.Base()
+	if err != nil {
+		log.Fatalf("error building the logger. %v\n", err)
+	}
+	ports := constants.Port()
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	stubAddress := fmt.Sprintf(":%d", ports.SlackStub)
+	apiAddress := fmt.Sprintf(":%d", ports.MainApi)
+
+	for key, value := range devEnv(fmt.Sprintf("http://localhost%s/api/", stubAddress)) {
+		if _, exists := os.LookupEnv(key); !exists {
+			os.Setenv(key, value)
+		}
+	}
+
+	go func() {
+		if err := http.ListenAndServe(stubAddress, slackstub.NewServer()); err != nil && err != http.ErrServerClosed {
+			zapLog.Fatal("error serve slack stub",
+				zap.String("port", stubAddress),
+				zap.Error(err),
+			)
+		}
+	}()
+
+	mux := http.NewServeMux()
+	routes.MainRoutes(mux)
+
+	zapLog.Info("running at 🚀⚙️",
+		zap.String("link", fmt.Sprintf("http://localhost%s", apiAddress)),
+		zap.String("slack", fmt.Sprintf("http://localhost%s", stubAddress)),
+	)
+
+	if err := http.ListenAndServe(apiAddress, mux); err != nil && err != http.ErrServerClosed {
+		zapLog.Fatal("error serve api",
+			zap.String("port", apiAddress),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+func TestDevEnv(t *testing.T) {
+	result := devEnv("http://localhost:8081/api/")
+
+	if result["SLACK_API_URL"] != "http://localhost:8081/api/" {
+		t.Errorf("Expected the stub url, got %s", result["SLACK_API_URL"])
+	}
+	if result["ENV"] != "local" {
+		t.Errorf("Expected local env, got %s", result["ENV"])
+	}
+	if result["DB_ENDPOINT"] != "http://localhost:8000" {
+		t.Errorf("Expected dynamodb-local, got %s", result["DB_ENDPOINT"])
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slack-pr-lambda/api/routes"
+	"slack-pr-lambda/archive"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/notifier"
+	"strings"
+)
+
+type attributeDefinition struct {
+	AttributeName string `json:"AttributeName"`
+	AttributeType string `json:"AttributeType"`
+}
+
+type keySchemaElement struct {
+	AttributeName string `json:"AttributeName"`
+	KeyType       string `json:"KeyType"`
+}
+
+type provisionedThroughput struct {
+	ReadCapacityUnits  int `json:"ReadCapacityUnits"`
+	WriteCapacityUnits int `json:"WriteCapacityUnits"`
+}
+
+type projection struct {
+	ProjectionType string `json:"ProjectionType"`
+}
+
+type globalSecondaryIndex struct {
+	IndexName             string                `json:"IndexName"`
+	KeySchema             []keySchemaElement    `json:"KeySchema"`
+	Projection            projection            `json:"Projection"`
+	ProvisionedThroughput provisionedThroughput `json:"ProvisionedThroughput"`
+}
+
+type timeToLiveSpecification struct {
+	AttributeName string `json:"AttributeName"`
+	Enabled       bool   `json:"Enabled"`
+}
+
+// `aws dynamodb create-table` input, TimeToLiveSpecification is applied with
+// `aws dynamodb update-time-to-live` once the table exists
+type tableDefinition struct {
+	TableName               string                   `json:"TableName"`
+	KeySchema               []keySchemaElement       `json:"KeySchema"`
+	AttributeDefinitions    []attributeDefinition    `json:"AttributeDefinitions"`
+	ProvisionedThroughput   provisionedThroughput    `json:"ProvisionedThroughput"`
+	GlobalSecondaryIndexes  []globalSecondaryIndex   `json:"GlobalSecondaryIndexes,omitempty"`
+	TimeToLiveSpecification *timeToLiveSpecification `json:"TimeToLiveSpecification,omitempty"`
+}
+
+type policyStatement struct {
+	Effect   string   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource []string `json:"Resource"`
+}
+
+type policyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+func keySchema(hashKey db.KeyAttribute, rangeKey *db.KeyAttribute) []keySchemaElement {
+	result := []keySchemaElement{{AttributeName: hashKey.Name, KeyType: "HASH"}}
+	if rangeKey != nil {
+		result = append(result, keySchemaElement{AttributeName: rangeKey.Name, KeyType: "RANGE"})
+	}
+	return result
+}
+
+func tableDefinitionOf(table db.Table, name string) tableDefinition {
+	capacity := provisionedThroughput{ReadCapacityUnits: table.Capacity, WriteCapacityUnits: table.Capacity}
+	definition := tableDefinition{
+		TableName:             name,
+		KeySchema:             keySchema(table.HashKey, table.RangeKey),
+		ProvisionedThroughput: capacity,
+	}
+
+	// key attributes of the table and its indexes, each defined once
+	seen := map[string]bool{}
+	define := func(attribute *db.KeyAttribute) {
+		if attribute == nil || seen[attribute.Name] {
+			return
+		}
+		seen[attribute.Name] = true
+		definition.AttributeDefinitions = append(definition.AttributeDefinitions, attributeDefinition{AttributeName: attribute.Name, AttributeType: attribute.Type})
+	}
+	define(&table.HashKey)
+	define(table.RangeKey)
+
+	for _, index := range table.Indexes {
+		define(&index.HashKey)
+		define(index.RangeKey)
+		definition.GlobalSecondaryIndexes = append(definition.GlobalSecondaryIndexes, globalSecondaryIndex{
+			IndexName:             index.Name,
+			KeySchema:             keySchema(index.HashKey, index.RangeKey),
+			Projection:            projection{ProjectionType: "ALL"},
+			ProvisionedThroughput: capacity,
+		})
+	}
+
+	if table.TtlAttribute != "" {
+		definition.TimeToLiveSpecification = &timeToLiveSpecification{AttributeName: table.TtlAttribute, Enabled: true}
+	}
+
+	return definition
+}
+
+// least privilege policy of the lambda on the tables of every region, the
+// archive bucket, SES, the event bus and the Firehose stream when they are set
+func policyOf(tables []db.Table, regions []string, account string, archiveBucket string, emailFrom string, eventBus string, firehoseStream string) policyDocument {
+	resources := []string{}
+	for _, region := range regions {
+		for _, table := range tables {
+			arn := fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/%s", region, account, table.Name())
+			resources = append(resources, arn)
+			if len(table.Indexes) > 0 {
+				resources = append(resources, arn+"/index/*")
+			}
+		}
+	}
+
+	policy := policyDocument{
+		Version: "2012-10-17",
+		Statement: []policyStatement{
+			{Effect: "Allow", Action: db.Actions(), Resource: resources},
+		},
+	}
+	if archiveBucket != "" {
+		policy.Statement = append(policy.Statement, policyStatement{
+			Effect:   "Allow",
+			Action:   archive.Actions(),
+			Resource: []string{fmt.Sprintf("arn:aws:s3:::%s", archiveBucket), fmt.Sprintf("arn:aws:s3:::%s/*", archiveBucket)},
+		})
+	}
+	if emailFrom != "" {
+		policy.Statement = append(policy.Statement, policyStatement{
+			Effect:   "Allow",
+			Action:   notifier.EmailActions(),
+			Resource: []string{"*"},
+		})
+	}
+	if eventBus != "" {
+		resources := []string{}
+		for _, region := range regions {
+			resources = append(resources, eventBusArn(eventBus, region, account))
+		}
+		policy.Statement = append(policy.Statement, policyStatement{
+			Effect:   "Allow",
+			Action:   notifier.EventBusActions(),
+			Resource: resources,
+		})
+	}
+	if firehoseStream != "" {
+		resources := []string{}
+		for _, region := range regions {
+			resources = append(resources, fmt.Sprintf("arn:aws:firehose:%s:%s:deliverystream/%s", region, account, firehoseStream))
+		}
+		policy.Statement = append(policy.Statement, policyStatement{
+			Effect:   "Allow",
+			Action:   notifier.FirehoseActions(),
+			Resource: resources,
+		})
+	}
+	return policy
+}
+
+// EVENT_BUS_NAME is a name or already an ARN
+func eventBusArn(eventBus string, region string, account string) string {
+	if strings.HasPrefix(eventBus, "arn:") {
+		return eventBus
+	}
+	return fmt.Sprintf("arn:aws:events:%s:%s:event-bus/%s", region, account, eventBus)
+}
+
+// infrastructure used by the code for Terraform / CDK / CloudFormation stacks,
+// table names follow the *_TABLE_NAME variables
+//
+//	go run ./cmd/infra tables
+//	go run ./cmd/infra -region ap-southeast-2 -account 123456789012 iam
+//	go run ./cmd/infra openapi
+func main() {
+	region := flag.String("region", env.GetEnv("REGION", "*"), "region of the IAM resources")
+	account := flag.String("account", "*", "AWS account id of the IAM resources")
+	flag.Parse()
+
+	var output any
+	switch flag.Arg(0) {
+	case "tables":
+		definitions := []tableDefinition{}
+		for _, table := range db.Tables() {
+			definitions = append(definitions, tableDefinitionOf(table, table.Name()))
+		}
+		output = definitions
+	case "iam":
+		regions := []string{*region}
+		// replica of the global tables the lambda fails over to
+		if failover := env.GetEnv("FAILOVER_REGION", ""); failover != "" && *region != "*" {
+			regions = append(regions, failover)
+		}
+		output = policyOf(db.Tables(), regions, *account, env.GetEnv("ARCHIVE_BUCKET", ""), env.GetEnv("EMAIL_FROM", ""), notifier.EventBusName(), notifier.FirehoseStream())
+	case "openapi":
+		output = routes.OpenAPI(routes.Routes())
+	default:
+		fmt.Fprintln(os.Stderr, "usage: infra [-region region] [-account id] tables|iam|openapi")
+		os.Exit(2)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	db "slack-pr-lambda/dynamodb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the create-table inputs of dynamodb-local are generated from the same tables
+func TestTableDefinitions(t *testing.T) {
+	files, err := filepath.Glob("../../infra/dynamodb/*.json")
+	assert.NoError(t, err)
+
+	local := map[string]tableDefinition{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		assert.NoError(t, err)
+
+		var definition tableDefinition
+		assert.NoError(t, json.Unmarshal(data, &definition), file)
+		local[definition.TableName] = definition
+	}
+
+	tables := db.Tables()
+	assert.Len(t, local, len(tables))
+	for _, table := range tables {
+		definition := tableDefinitionOf(table, table.DefaultName)
+		definition.TimeToLiveSpecification = nil
+
+		assert.Equal(t, local[table.DefaultName], definition, table.DefaultName)
+	}
+}
+
+func TestTableDefinitionTtl(t *testing.T) {
+	definition := tableDefinitionOf(db.Table{
+		HashKey:      db.KeyAttribute{Name: "id", Type: "S"},
+		Capacity:     5,
+		TtlAttribute: "expiresAt",
+	}, "CommentBatches")
+
+	assert.Equal(t, &timeToLiveSpecification{AttributeName: "expiresAt", Enabled: true}, definition.TimeToLiveSpecification)
+}
+
+func TestPolicy(t *testing.T) {
+	t.Setenv("TABLE_NAME", "PullRequests")
+	t.Setenv("MUTE_TABLE_NAME", "Mutes")
+	tables := []db.Table{
+		{EnvName: "TABLE_NAME", Indexes: []db.Index{{Name: "PullRequestIdIndex"}}},
+		{EnvName: "MUTE_TABLE_NAME"},
+	}
+
+	policy := policyOf(tables, []string{"ap-southeast-2"}, "123456789012", "", "", "", "")
+	assert.Len(t, policy.Statement, 1)
+	assert.Equal(t, []string{
+		"arn:aws:dynamodb:ap-southeast-2:123456789012:table/PullRequests",
+		"arn:aws:dynamodb:ap-southeast-2:123456789012:table/PullRequests/index/*",
+		"arn:aws:dynamodb:ap-southeast-2:123456789012:table/Mutes",
+	}, policy.Statement[0].Resource)
+	assert.Equal(t, db.Actions(), policy.Statement[0].Action)
+
+	policy = policyOf(tables, []string{"ap-southeast-2", "us-west-2"}, "123456789012", "", "", "", "")
+	assert.Len(t, policy.Statement[0].Resource, 6)
+	assert.Equal(t, "arn:aws:dynamodb:us-west-2:123456789012:table/Mutes", policy.Statement[0].Resource[5])
+
+	policy = policyOf(tables, []string{"*"}, "*", "archives", "pulls@acme.com", "pulls", "warehouse")
+	assert.Len(t, policy.Statement, 5)
+	assert.Equal(t, []string{"arn:aws:s3:::archives", "arn:aws:s3:::archives/*"}, policy.Statement[1].Resource)
+	assert.Equal(t, []string{"ses:SendEmail"}, policy.Statement[2].Action)
+	assert.Equal(t, []string{"events:PutEvents"}, policy.Statement[3].Action)
+	assert.Equal(t, []string{"arn:aws:events:*:*:event-bus/pulls"}, policy.Statement[3].Resource)
+	assert.Equal(t, []string{"arn:aws:firehose:*:*:deliverystream/warehouse"}, policy.Statement[4].Resource)
+
+	policy = policyOf(tables, []string{"ap-southeast-2"}, "123456789012", "", "", "arn:aws:events:us-east-1:123456789012:event-bus/org", "")
+	assert.Equal(t, []string{"arn:aws:events:us-east-1:123456789012:event-bus/org"}, policy.Statement[1].Resource)
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/notifier"
+	"slack-pr-lambda/types"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// a little of everything, pushes and comments dominate like on a busy repository
+const defaultMix = "opened=1,synchronize=4,review_requested=2,submitted=2,created=3,closed=1"
+
+// senders and reviewers of the synthesized deliveries
+var logins = []string{"alice", "bob", "carol", "dave", "erin"}
+
+var reviewStates = []string{"approved", "commented", "changes_requested"}
+
+// GitHub event of the actions outside of pull_request
+var actionEvents = map[string]string{
+	"submitted": "pull_request_review",
+	"created":   "issue_comment",
+}
+
+// action of a synthesized delivery and how often it is picked
+type weighted struct {
+	Action string
+	Weight int
+}
+
+type config struct {
+	Url         string
+	Secret      string
+	Events      int
+	Concurrency int
+	// deliveries per second, 0 sends as fast as the workers allow
+	Rate         float64
+	Mix          []weighted
+	Repositories []string
+	// open pull requests per repository the deliveries are spread over
+	PullRequests int
+	Seed         int64
+}
+
+type result struct {
+	Status  int
+	Latency time.Duration
+	Err     error
+}
+
+type report struct {
+	Sent     int
+	Failed   int
+	Statuses map[int]int
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+	// deliveries per second over the whole run
+	Throughput float64
+}
+
+// synthesized GitHub webhook bursts against an endpoint, reports the latency
+// percentiles and error rate
+//
+//	go run ./cmd/loadgen -url http://localhost:8080/pull-request -events 1000 -concurrency 20
+func main() {
+	ports := constants.Port()
+
+	url := flag.String("url", fmt.Sprintf("http://localhost:%d/pull-request", ports.MainApi), "webhook endpoint to load")
+	events := flag.Int("events", 500, "deliveries to send")
+	concurrency := flag.Int("concurrency", 10, "deliveries in flight")
+	rate := flag.Float64("rate", 0, "deliveries per second, 0 for no limit")
+	mix := flag.String("mix", defaultMix, "actions and their weights")
+	repositories := flag.String("repos", "api,web,infra", "repositories of the deliveries")
+	pullRequests := flag.Int("prs", 50, "open pull requests per repository")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed, fixed to repeat a run")
+	maxErrorRate := flag.Float64("max-error-rate", 0.01, "failed share of the deliveries above which the run fails")
+	flag.Parse()
+
+	weights, err := parseMix(*mix)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *events <= 0 || *concurrency <= 0 || *pullRequests <= 0 {
+		fmt.Fprintln(os.Stderr, "usage: loadgen [-url url] [-events n] [-concurrency n] [-rate n] [-mix opened=1,...] [-repos a,b] [-prs n]")
+		os.Exit(2)
+	}
+
+	repos := []string{}
+	for _, repository := range strings.Split(*repositories, ",") {
+		if repository = strings.TrimSpace(repository); repository != "" {
+			repos = append(repos, repository)
+		}
+	}
+	if len(repos) == 0 {
+		fmt.Fprintln(os.Stderr, "no repository in -repos")
+		os.Exit(2)
+	}
+
+	cfg := config{
+		Url:          *url,
+		Secret:       env.GetEnv("GITHUB_WEBHOOK_SECRET", ""),
+		Events:       *events,
+		Concurrency:  *concurrency,
+		Rate:         *rate,
+		Mix:          weights,
+		Repositories: repos,
+		PullRequests: *pullRequests,
+		Seed:         *seed,
+	}
+
+	started := time.Now()
+	summary := summarize(run(cfg), time.Since(started))
+	fmt.Print(summary.String())
+
+	if float64(summary.Failed) > *maxErrorRate*float64(summary.Sent) {
+		os.Exit(1)
+	}
+}
+
+// "opened=1,synchronize=4", weights are relative
+func parseMix(mix string) ([]weighted, error) {
+	weights := []weighted{}
+	for _, entry := range strings.Split(mix, ",") {
+		action, weight, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid mix entry %q, expected action=weight", entry)
+		}
+		n, err := strconv.Atoi(weight)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid weight of %s: %q", action, weight)
+		}
+		if n > 0 {
+			weights = append(weights, weighted{Action: action, Weight: n})
+		}
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("empty mix %q", mix)
+	}
+	return weights, nil
+}
+
+func pick(mix []weighted, rnd *rand.Rand) string {
+	total := 0
+	for _, entry := range mix {
+		total += entry.Weight
+	}
+
+	n := rnd.Intn(total)
+	for _, entry := range mix {
+		if n < entry.Weight {
+			return entry.Action
+		}
+		n -= entry.Weight
+	}
+	return mix[len(mix)-1].Action
+}
+
+// X-GitHub-Event and body of a delivery of the action
+func delivery(action string, repository string, number int, rnd *rand.Rand) (string, []byte, error) {
+	author := logins[number%len(logins)]
+	sender := logins[rnd.Intn(len(logins))]
+	htmlUrl := fmt.Sprintf("https://github.com/loadgen/%s/pull/%d", repository, number)
+
+	event := types.WebhookEvent{
+		Action: action,
+		Number: number,
+		PullRequest: &github.PullRequest{
+			ID:        github.Int64(int64(number)),
+			Number:    github.Int(number),
+			Title:     github.String(fmt.Sprintf("Load test change %d", number)),
+			HTMLURL:   github.String(htmlUrl),
+			User:      &github.User{Login: github.String(author)},
+			Head:      &github.PullRequestBranch{Ref: github.String(fmt.Sprintf("loadgen-%d", number))},
+			Base:      &github.PullRequestBranch{Ref: github.String("main")},
+			Additions: github.Int(rnd.Intn(500)),
+			Deletions: github.Int(rnd.Intn(200)),
+		},
+		Repository: &github.Repository{Name: github.String(repository), FullName: github.String("loadgen/" + repository)},
+		Sender:     &github.User{Login: github.String(sender)},
+	}
+
+	switch action {
+	case "synchronize":
+		event.After = fmt.Sprintf("%040x", rnd.Uint64())
+	case "review_requested":
+		event.RequestedReviewer = &github.User{Login: github.String(logins[rnd.Intn(len(logins))])}
+	case "submitted":
+		event.Number = 0
+		event.Review = &github.PullRequestReview{
+			State:   github.String(reviewStates[rnd.Intn(len(reviewStates))]),
+			HTMLURL: github.String(htmlUrl + "#pullrequestreview-1"),
+			User:    &github.User{Login: github.String(sender)},
+		}
+	case "created":
+		event.Number = 0
+		event.PullRequest = nil
+		event.Issue = &github.Issue{
+			ID:               github.Int64(int64(number)),
+			Number:           github.Int(number),
+			PullRequestLinks: &github.PullRequestLinks{HTMLURL: github.String(htmlUrl)},
+		}
+		event.Comment = &github.PullRequestComment{
+			Body:    github.String("Looks good, one question inline."),
+			HTMLURL: github.String(htmlUrl + "#issuecomment-1"),
+			User:    &github.User{Login: github.String(sender)},
+		}
+	case "closed":
+		event.PullRequest.Merged = github.Bool(rnd.Intn(4) > 0)
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return "", nil, err
+	}
+
+	githubEvent, ok := actionEvents[action]
+	if !ok {
+		githubEvent = "pull_request"
+	}
+	return githubEvent, body, nil
+}
+
+// deliveries spread over the workers, paced by the rate when it is set
+func run(cfg config) []result {
+	rnd := rand.New(rand.NewSource(cfg.Seed))
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	type job struct {
+		event string
+		body  []byte
+		id    string
+	}
+	jobs := make(chan job)
+	results := make(chan result, cfg.Events)
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- send(client, cfg.Url, cfg.Secret, j.event, j.id, j.body)
+			}
+		}()
+	}
+
+	var interval time.Duration
+	if cfg.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / cfg.Rate)
+	}
+
+	for i := 0; i < cfg.Events; i++ {
+		if i > 0 && interval > 0 {
+			time.Sleep(interval)
+		}
+
+		action := pick(cfg.Mix, rnd)
+		repository := cfg.Repositories[rnd.Intn(len(cfg.Repositories))]
+		event, body, err := delivery(action, repository, rnd.Intn(cfg.PullRequests)+1, rnd)
+		if err != nil {
+			results <- result{Err: err}
+			continue
+		}
+		jobs <- job{event: event, body: body, id: fmt.Sprintf("loadgen-%d-%d", cfg.Seed, i)}
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+
+	all := []result{}
+	for r := range results {
+		all = append(all, r)
+	}
+	return all
+}
+
+// a delivery signed like GitHub's when GITHUB_WEBHOOK_SECRET is set
+func send(client *http.Client, url string, secret string, event string, deliveryId string, body []byte) result {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return result{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", deliveryId)
+	if secret != "" {
+		req.Header.Set("X-Hub-Signature-256", notifier.ForwardSignature(secret, body))
+	}
+
+	started := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(started)
+	if err != nil {
+		return result{Latency: latency, Err: err}
+	}
+	resp.Body.Close()
+
+	return result{Status: resp.StatusCode, Latency: latency}
+}
+
+// transport errors and non-2xx answers count as failed
+func summarize(results []result, took time.Duration) report {
+	summary := report{Sent: len(results), Statuses: map[int]int{}}
+
+	latencies := []time.Duration{}
+	for _, r := range results {
+		if r.Err != nil || r.Status < 200 || r.Status >= 300 {
+			summary.Failed++
+		}
+		if r.Err == nil {
+			summary.Statuses[r.Status]++
+			latencies = append(latencies, r.Latency)
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	summary.P50 = percentile(latencies, 50)
+	summary.P90 = percentile(latencies, 90)
+	summary.P99 = percentile(latencies, 99)
+	if len(latencies) > 0 {
+		summary.Max = latencies[len(latencies)-1]
+	}
+	if took > 0 {
+		summary.Throughput = float64(summary.Sent) / took.Seconds()
+	}
+	return summary
+}
+
+// nearest rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+func (r report) String() string {
+	errorRate := 0.0
+	if r.Sent > 0 {
+		errorRate = float64(r.Failed) / float64(r.Sent) * 100
+	}
+
+	statuses := []int{}
+	for status := range r.Statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	counts := []string{}
+	for _, status := range statuses {
+		counts = append(counts, fmt.Sprintf("%d=%d", status, r.Statuses[status]))
+	}
+
+	return fmt.Sprintf("sent %d, failed %d (%.2f%%), %.1f/s\nstatus %s\nlatency p50 %s, p90 %s, p99 %s, max %s\n",
+		r.Sent, r.Failed, errorRate, r.Throughput,
+		strings.Join(counts, " "),
+		r.P50, r.P90, r.P99, r.Max,
+	)
+}
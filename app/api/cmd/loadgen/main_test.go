@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/notifier"
+	"slack-pr-lambda/types"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMix(t *testing.T) {
+	mix, err := parseMix("opened=1, closed=3,synchronize=0")
+	assert.NoError(t, err)
+	assert.Equal(t, []weighted{{Action: "opened", Weight: 1}, {Action: "closed", Weight: 3}}, mix)
+
+	for _, invalid := range []string{"", "opened", "opened=x", "opened=-1", "opened=0"} {
+		_, err := parseMix(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	_, err = parseMix(defaultMix)
+	assert.NoError(t, err)
+}
+
+func TestPick(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	mix := []weighted{{Action: "opened", Weight: 1}, {Action: "synchronize", Weight: 9}}
+
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		counts[pick(mix, rnd)]++
+	}
+	assert.Greater(t, counts["synchronize"], counts["opened"]*4)
+	assert.Greater(t, counts["opened"], 0)
+}
+
+func TestDelivery(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for action, expected := range map[string]string{
+		"opened":           "pull_request",
+		"synchronize":      "pull_request",
+		"review_requested": "pull_request",
+		"submitted":        "pull_request_review",
+		"created":          "issue_comment",
+		"closed":           "pull_request",
+	} {
+		githubEvent, body, err := delivery(action, "api", 7, rnd)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, githubEvent, action)
+
+		var event types.WebhookEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, action, event.Action)
+		assert.Equal(t, "api", event.Repository.GetName())
+		if action == "created" {
+			assert.Equal(t, 7, event.Issue.GetNumber())
+		} else {
+			assert.Equal(t, 7, event.PullRequestNumber(), action)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, time.Duration(0), percentile(nil, 50))
+
+	sorted := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	assert.Equal(t, 99*time.Millisecond, percentile(sorted, 99))
+	assert.Equal(t, 1*time.Millisecond, percentile(sorted[:1], 99))
+}
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	deliveries := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		deliveries[r.Header.Get("X-GitHub-Delivery")] = true
+		mu.Unlock()
+
+		if r.Header.Get("X-Hub-Signature-256") != notifier.ForwardSignature("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// every closed pull request fails
+		var event types.WebhookEvent
+		if json.Unmarshal(body, &event) == nil && event.Action == "closed" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	mix, err := parseMix("opened=1,closed=1")
+	assert.NoError(t, err)
+
+	results := run(config{
+		Url:          server.URL,
+		Secret:       "secret",
+		Events:       40,
+		Concurrency:  4,
+		Mix:          mix,
+		Repositories: []string{"api", "web"},
+		PullRequests: 5,
+		Seed:         1,
+	})
+	assert.Len(t, results, 40)
+	assert.Len(t, deliveries, 40)
+
+	summary := summarize(results, time.Second)
+	assert.Equal(t, 40, summary.Sent)
+	assert.Equal(t, summary.Statuses[http.StatusInternalServerError], summary.Failed)
+	assert.Equal(t, 40, summary.Statuses[http.StatusOK]+summary.Statuses[http.StatusInternalServerError])
+	assert.Greater(t, summary.Failed, 0)
+	assert.Equal(t, 40.0, summary.Throughput)
+	assert.Contains(t, summary.String(), "sent 40")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/recorder"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// re-send webhooks recorded with RECORD_DIR to a local server
+//
+//	go run ./cmd/replay -url http://localhost:8080/pull-request ./recordings
+func main() {
+	zapLog, err := logger.Base()
+	if err != nil {
+		log.Fatalf("error building the logger. %v\n", err)
+	}
+	ports := constants.Port()
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	url := flag.String("url", fmt.Sprintf("http://localhost:%d/pull-request", ports.MainApi), "webhook endpoint to replay to")
+	delay := flag.Duration("delay", 500*time.Millisecond, "pause between events")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: replay [-url url] [-delay duration] <file or directory>...")
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		events, err := recorder.Load(path)
+		if err != nil {
+			zapLog.Fatal("error load recorded events",
+				zap.String("path", path),
+				zap.Error(err),
+			)
+		}
+
+		for i, event := range events {
+			if i > 0 {
+				time.Sleep(*delay)
+			}
+
+			status, err := recorder.Replay(*url, event)
+			if err != nil {
+				zapLog.Fatal("error replay event",
+					zap.String("url", *url),
+					zap.Error(err),
+				)
+			}
+
+			zapLog.Info("replayed event",
+				zap.String("event", event.Headers["X-GitHub-Event"]),
+				zap.String("receivedAt", event.ReceivedAt),
+				zap.Int("status", status),
+			)
+			if status >= 400 {
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,169 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// re-render the pinned "Open PRs" message of SLACK_CHANNEL, posted and pinned
+// on the first refresh or once it was deleted in Slack
+func Refresh(zapLog *zap.Logger) error {
+	channel := env.GetEnv("SLACK_CHANNEL", "")
+
+	svc := db.DynamoDbConnection()
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return err
+	}
+
+	requested := RequestedReviewers(items, "", zapLog)
+
+	slackUsersMap := mapstruct.StructToMap(*constants.SlackUsers())
+	message := Message(items, requested, slackUsersMap, time.Now())
+
+	dashboard, err := db.GetDashboard(svc, channel)
+	if err != nil && !errors.Is(err, db.ErrNoDataFound) {
+		return err
+	}
+	if dashboard != nil {
+		err := slack.SlackUpdateMessage(dashboard.SlackTimeStamp, message)
+		if err == nil || err.Error() != "message_not_found" {
+			return err
+		}
+		if err := db.DeleteDashboard(svc, channel); err != nil {
+			return err
+		}
+	}
+
+	return post(svc, channel, message)
+}
+
+func post(svc *awsdynamodb.DynamoDB, channel string, message string) error {
+	timeStamp, err := slack.SlackSendMessage(types.OpenPullRequest{}, message)
+	if err != nil {
+		return err
+	}
+
+	err = db.InsertDashboard(svc, &types.TableDashboardData{
+		Channel:        channel,
+		SlackTimeStamp: timeStamp,
+		UpdatedAt:      time.Now().Format(time.RFC3339),
+	})
+	// another event posted the dashboard first, keep a single message
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == awsdynamodb.ErrCodeConditionalCheckFailedException {
+		return slack.SlackDeleteMessage(timeStamp)
+	}
+	if err != nil {
+		return err
+	}
+
+	return slack.SlackPinMessage(timeStamp)
+}
+
+// open pull requests oldest first with their age, approvals and the reviewers
+// still requested, keyed by record id
+func Message(items []types.TablePullRequestData, requested map[string][]string, slackUsersMap map[string]interface{}, now time.Time) string {
+	emoji := constants.Emoji()
+
+	open := tracked(items)
+
+	lines := []string{fmt.Sprintf("%s *Open PRs* (%d)", emoji.PullRequest, len(open))}
+	if len(open) == 0 {
+		lines = append(lines, "No open pull requests.")
+	}
+
+	for _, item := range open {
+		line := fmt.Sprintf("• <%s|%s#%d>", github.PullRequestUrl(item.Repository, item.PullRequestId), item.Repository, item.PullRequestId)
+
+		if created, err := time.Parse(time.RFC3339, item.CreatedAt); err == nil {
+			line += fmt.Sprintf(" · %s %s", age(now.Sub(created)), messages.AgeEmoji(messages.AgeBadge(item.CreatedAt, now)))
+		}
+		line += " · " + messages.ApprovalsLine(item.Approvals, item.RequiredApprovals)
+
+		if logins := requested[item.ID]; len(logins) > 0 {
+			mentions := []string{}
+			for _, login := range logins {
+				mentions = append(mentions, fmt.Sprintf("<@%s>", slackUsersMap[login]))
+			}
+			line += " · Reviewers: " + strings.Join(mentions, " ")
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// reviewers still requested on GitHub keyed by record id, of repository or every
+// repository when empty. Pull requests failing to load are left without reviewers
+func RequestedReviewers(items []types.TablePullRequestData, repository string, zapLog *zap.Logger) map[string][]string {
+	var mu sync.Mutex
+	requested := map[string][]string{}
+
+	tasks := []func() error{}
+	for _, item := range items {
+		if item.Repository == "" || (repository != "" && item.Repository != repository) {
+			continue
+		}
+		tasks = append(tasks, func() error {
+			logins, err := github.GetRequestedReviewers(item.Repository, item.PullRequestId)
+			if err != nil {
+				zapLog.Warn("error get requested reviewers",
+					zap.String("repository", item.Repository),
+					zap.Int("number", item.PullRequestId),
+					zap.Error(err),
+				)
+				return nil
+			}
+
+			mu.Lock()
+			requested[item.ID] = logins
+			mu.Unlock()
+			return nil
+		})
+	}
+	pool.Run(pool.Size(), tasks)
+
+	return requested
+}
+
+// pull requests with a repository, oldest first
+func tracked(items []types.TablePullRequestData) []types.TablePullRequestData {
+	result := []types.TablePullRequestData{}
+	for _, item := range items {
+		// records created before the dashboard existed have no repository
+		if item.Repository != "" {
+			result = append(result, item)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt < result[j].CreatedAt
+	})
+	return result
+}
+
+// "5h" under a day, "3d" after
+func age(duration time.Duration) string {
+	if duration < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(duration.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(duration.Hours()/24))
+}
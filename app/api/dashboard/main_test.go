@@ -0,0 +1,50 @@
+package dashboard
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestMessage(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "rodentskie")
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	items := []types.TablePullRequestData{
+		{ID: "2", PullRequestId: 8, Repository: "web", CreatedAt: "2024-03-10T07:00:00Z", RequiredApprovals: 1},
+		{ID: "1", PullRequestId: 7, Repository: "api", CreatedAt: "2024-03-05T12:00:00Z", Approvals: 1, RequiredApprovals: 2},
+		{ID: "3", PullRequestId: 9},
+	}
+	requested := map[string][]string{
+		"1": {"alice", "bob"},
+	}
+	slackUsersMap := map[string]interface{}{
+		"alice": "UA",
+		"bob":   "UB",
+	}
+
+	result := Message(items, requested, slackUsersMap, now)
+
+	expected := ":pull-request: *Open PRs* (2)\n" +
+		"• <https://github.com/rodentskie/api/pull/7|api#7> · 5d :red_circle: · Approvals: 1/2 · Reviewers: <@UA> <@UB>\n" +
+		"• <https://github.com/rodentskie/web/pull/8|web#8> · 5h :large_green_circle: · Approvals: 0/1"
+	if result != expected {
+		t.Errorf("got\n%s\nwant\n%s", result, expected)
+	}
+}
+
+func TestMessageEmpty(t *testing.T) {
+	result := Message(nil, nil, nil, time.Now())
+
+	expected := ":pull-request: *Open PRs* (0)\nNo open pull requests."
+	if result != expected {
+		t.Errorf("got %q want %q", result, expected)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
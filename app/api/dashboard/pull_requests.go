@@ -0,0 +1,96 @@
+package dashboard
+
+import (
+	"fmt"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/types"
+	"strings"
+	"time"
+)
+
+type PullRequest struct {
+	Repository        string   `json:"repository"`
+	Number            int      `json:"number"`
+	State             string   `json:"state"`
+	Approvals         int      `json:"approvals"`
+	RequiredApprovals int      `json:"requiredApprovals"`
+	Reviewers         []string `json:"reviewers"`
+	CreatedAt         string   `json:"createdAt"`
+	AgeHours          int      `json:"ageHours"`
+	AgeBadge          string   `json:"ageBadge"`
+	Url               string   `json:"url"`
+	SlackUrl          string   `json:"slackUrl"`
+}
+
+const (
+	StateInReview = "in_review"
+	StateApproved = "approved"
+)
+
+// tracked pull requests of repository (every repository when empty) in the
+// shape served to dashboards, oldest first
+func PullRequests(items []types.TablePullRequestData, repository string, requested map[string][]string, now time.Time) []PullRequest {
+	result := []PullRequest{}
+	for _, item := range tracked(items) {
+		if repository != "" && item.Repository != repository {
+			continue
+		}
+
+		state := StateInReview
+		if item.RequiredApprovals > 0 && item.Approvals >= item.RequiredApprovals {
+			state = StateApproved
+		}
+
+		reviewers := requested[item.ID]
+		if reviewers == nil {
+			reviewers = []string{}
+		}
+
+		pullRequest := PullRequest{
+			Repository:        item.Repository,
+			Number:            item.PullRequestId,
+			State:             state,
+			Approvals:         item.Approvals,
+			RequiredApprovals: item.RequiredApprovals,
+			Reviewers:         reviewers,
+			CreatedAt:         item.CreatedAt,
+			AgeBadge:          messages.AgeBadge(item.CreatedAt, now),
+			Url:               github.PullRequestUrl(item.Repository, item.PullRequestId),
+			SlackUrl:          ThreadUrl(item),
+		}
+		if created, err := time.Parse(time.RFC3339, item.CreatedAt); err == nil {
+			pullRequest.AgeHours = int(now.Sub(created).Hours())
+		}
+
+		result = append(result, pullRequest)
+	}
+	return result
+}
+
+// stored permalink of the parent message, built from its timestamp for records
+// tracked before permalinks were stored
+func ThreadUrl(item types.TablePullRequestData) string {
+	if item.Permalink != "" {
+		return item.Permalink
+	}
+	return SlackUrl(item.SlackTimeStamp)
+}
+
+// permalink of the parent message, SLACK_WORKSPACE_URL redirects to the workspace.
+// Empty for an Unrouted pull request without one
+func SlackUrl(timeStamp string) string {
+	if timeStamp == "" || timeStamp == audit.Unrouted {
+		return ""
+	}
+
+	// the pulumi config sets an empty slackWorkspaceUrl when unset
+	workspace := strings.TrimSuffix(env.GetEnv("SLACK_WORKSPACE_URL", ""), "/")
+	if workspace == "" {
+		workspace = "https://slack.com"
+	}
+	channel := env.GetEnv("SLACK_CHANNEL", "")
+	return fmt.Sprintf("%s/archives/%s/p%s", workspace, channel, strings.ReplaceAll(timeStamp, ".", ""))
+}
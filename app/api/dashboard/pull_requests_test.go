@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/client"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequests(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "rodentskie")
+	t.Setenv("SLACK_CHANNEL", "C123")
+	t.Setenv("SLACK_WORKSPACE_URL", "https://acme.slack.com/")
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	items := []types.TablePullRequestData{
+		{ID: "2", PullRequestId: 8, Repository: "web", CreatedAt: "2024-03-10T07:00:00Z", SlackTimeStamp: "1710054000.000200", Approvals: 1, RequiredApprovals: 1},
+		{ID: "1", PullRequestId: 7, Repository: "api", CreatedAt: "2024-03-05T12:00:00Z", RequiredApprovals: 2},
+		{ID: "3", PullRequestId: 9},
+	}
+	requested := map[string][]string{
+		"1": {"alice"},
+	}
+
+	result := PullRequests(items, "", requested, now)
+
+	assert.Equal(t, []PullRequest{
+		{
+			Repository:        "api",
+			Number:            7,
+			State:             StateInReview,
+			RequiredApprovals: 2,
+			Reviewers:         []string{"alice"},
+			CreatedAt:         "2024-03-05T12:00:00Z",
+			AgeHours:          120,
+			AgeBadge:          messages.AgeStale,
+			Url:               "https://github.com/rodentskie/api/pull/7",
+		},
+		{
+			Repository:        "web",
+			Number:            8,
+			State:             StateApproved,
+			Approvals:         1,
+			RequiredApprovals: 1,
+			Reviewers:         []string{},
+			CreatedAt:         "2024-03-10T07:00:00Z",
+			AgeHours:          5,
+			AgeBadge:          messages.AgeFresh,
+			Url:               "https://github.com/rodentskie/web/pull/8",
+			SlackUrl:          "https://acme.slack.com/archives/C123/p1710054000000200",
+		},
+	}, result)
+
+	filtered := PullRequests(items, "web", requested, now)
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, 8, filtered[0].Number)
+}
+
+func TestThreadUrl(t *testing.T) {
+	t.Setenv("SLACK_CHANNEL", "C123")
+	t.Setenv("SLACK_WORKSPACE_URL", "https://acme.slack.com/")
+
+	stored := types.TablePullRequestData{SlackTimeStamp: "1710054000.000200", Permalink: "https://acme.slack.com/archives/C123/p1710054000000200?thread_ts=1"}
+	assert.Equal(t, stored.Permalink, ThreadUrl(stored))
+
+	// records tracked before permalinks were stored
+	computed := types.TablePullRequestData{SlackTimeStamp: "1710054000.000200"}
+	assert.Equal(t, "https://acme.slack.com/archives/C123/p1710054000000200", ThreadUrl(computed))
+
+	// an empty SLACK_WORKSPACE_URL still builds an absolute link
+	t.Setenv("SLACK_WORKSPACE_URL", "")
+	assert.Equal(t, "https://slack.com/archives/C123/p1710054000000200", ThreadUrl(computed))
+}
+
+// the client package decodes every field served by GET /prs
+func TestClientPullRequest(t *testing.T) {
+	j, err := json.Marshal(PullRequest{Reviewers: []string{"alice"}})
+	assert.NoError(t, err)
+
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	decoder.DisallowUnknownFields()
+	var decoded client.PullRequest
+	assert.NoError(t, decoder.Decode(&decoded))
+}
@@ -4,7 +4,9 @@ go 1.22
 
 require (
 	github.com/aws/aws-lambda-go v1.46.0
+	github.com/aws/aws-sdk-go v1.51.0
 	github.com/awslabs/aws-lambda-go-api-proxy v0.16.1
+	github.com/google/go-github/v39 v39.2.0
 	github.com/pulumi/pulumi-aws-apigateway/sdk/v2 v2.4.0
 	github.com/pulumi/pulumi-aws/sdk/v6 v6.25.1
 	github.com/pulumi/pulumi/sdk/v3 v3.109.0
@@ -47,6 +49,7 @@ require (
 	github.com/hashicorp/hcl/v2 v2.17.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/jbenet/go-context v0.0.0-20150711004518-d14ea06fba99 // indirect
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	github.com/kevinburke/ssh_config v1.2.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// replaces the text of bot messages on DELETE .../messages?mode=redact
+const redactedMessage = "_This message was removed._"
+
+// ADMIN_TOKEN as bearer token, the admin API is disabled without it
+func adminAuthorized(r *http.Request) bool {
+	token := env.GetEnv("ADMIN_TOKEN", "")
+	if token == "" {
+		return false
+	}
+
+	bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
+
+// tracked pull request, the record is keyed by the GitHub id
+func adminPullRequest(repository string, number int) (*types.TablePullRequestData, error) {
+	id, err := github.GetPullRequestId(repository, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetPullRequest(db.DynamoDbConnection(), int(id), number)
+}
+
+// re-post the parent message of a pull request, e.g. after it was deleted in
+// Slack, later events are threaded under the new message
+func AdminResendHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	item, err := adminPullRequest(r.PathValue("repository"), number)
+	if errors.Is(err, db.ErrNoDataFound) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		zapLog.Error("error get pull request",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+	if item.ParentMessage == "" {
+		writeResponse(w, "No parent message stored for this pull request.")
+		return
+	}
+
+	out := audit.Messenger{
+		Source:     "admin_resend",
+		Repository: item.Repository,
+		Number:     item.PullRequestId,
+		Log:        zapLog,
+	}
+	if _, err := resendParent(out, item, zapLog); err != nil {
+		zapLog.Error("error resend parent message",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	writeResponse(w, "Parent message resent.")
+}
+
+// restore the soft-deleted record of a closed pull request, e.g. deleted by
+// mistake, until the purge job removes it
+func AdminRestoreHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	id, err := github.GetPullRequestId(r.PathValue("repository"), number)
+	if err != nil {
+		zapLog.Error("error get pull request id",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	err = db.RestoreItem(db.DynamoDbConnection(), int(id), number)
+	if errors.Is(err, db.ErrNoDataFound) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		zapLog.Error("error restore pull request",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	writeResponse(w, "Pull request restored.")
+}
+
+// delete every bot message of a pull request found in the audit log, or only
+// replace their text with ?mode=redact, the pull request is no longer tracked
+// afterwards so later events don't reply to removed messages
+func AdminDeleteMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	redact := r.URL.Query().Get("mode") == "redact"
+
+	svc := db.DynamoDbConnection()
+	records, err := db.ListAudits(svc, audit.PullRequestKey(r.PathValue("repository"), number))
+	if err != nil {
+		zapLog.Error("error list audit records",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	var removed atomic.Int32
+	tasks := []func() error{}
+	for _, timeStamp := range sentTimeStamps(records) {
+		tasks = append(tasks, func() error {
+			var err error
+			if redact {
+				err = slack.SlackUpdateMessage(timeStamp, redactedMessage)
+			} else {
+				err = slack.SlackDeleteMessage(timeStamp)
+			}
+			// already removed in Slack
+			if err != nil && err.Error() == "message_not_found" {
+				return nil
+			}
+			if err != nil {
+				zapLog.Error("error slack remove message",
+					zap.String("timeStamp", timeStamp),
+					zap.Error(err),
+				)
+				return err
+			}
+			removed.Add(1)
+			return nil
+		})
+	}
+	if err := pool.Run(pool.Size(), tasks); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	item, err := adminPullRequest(r.PathValue("repository"), number)
+	if err != nil && !errors.Is(err, db.ErrNoDataFound) {
+		zapLog.Error("error get pull request",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+	if item != nil {
+		id, err := strconv.Atoi(item.ID)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if err := db.DeleteItem(svc, id, item.PullRequestId); err != nil {
+			zapLog.Error("error delete data",
+				zap.Error(err),
+			)
+			writeError(w, err)
+			return
+		}
+	}
+
+	writeResponse(w, fmt.Sprintf("Removed %d messages.", removed.Load()))
+}
+
+// timestamps of the messages the bot posted, updates share the timestamp of
+// the message they edited
+func sentTimeStamps(records []types.TableAuditData) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, record := range records {
+		if record.Type == "update" || record.TimeStamp == "" || record.TimeStamp == "dry-run" || seen[record.TimeStamp] {
+			continue
+		}
+		seen[record.TimeStamp] = true
+		result = append(result, record.TimeStamp)
+	}
+	return result
+}
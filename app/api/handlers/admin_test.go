@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"testing"
+)
+
+func TestAdminAuthorized(t *testing.T) {
+	t.Run("disabled without token", func(t *testing.T) {
+		t.Setenv("ADMIN_TOKEN", "")
+
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("Authorization", "Bearer ")
+		if adminAuthorized(req) {
+			t.Errorf("Expected the admin API to be disabled")
+		}
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		t.Setenv("ADMIN_TOKEN", "secret")
+
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		if !adminAuthorized(req) {
+			t.Errorf("Expected the token to be accepted")
+		}
+
+		req.Header.Set("Authorization", "Bearer other")
+		if adminAuthorized(req) {
+			t.Errorf("Expected a wrong token to be rejected")
+		}
+	})
+}
+
+func adminMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/pull-requests/{repository}/{number}/resend", AdminResendHandler)
+	mux.HandleFunc("DELETE /admin/pull-requests/{repository}/{number}/messages", AdminDeleteMessagesHandler)
+	mux.HandleFunc("POST /admin/pull-requests/{repository}/{number}/restore", AdminRestoreHandler)
+	mux.HandleFunc("GET /admin/pull-requests/{repository}/{number}/events", AdminEventsHandler)
+	mux.HandleFunc("GET /admin/archives/{repository}/{number}", AdminArchiveHandler)
+	mux.HandleFunc("DELETE /admin/users/{slackUserId}", AdminDeleteUserHandler)
+	return mux
+}
+
+func TestAdminHandlersUnauthorized(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/admin/pull-requests/api/7/resend", nil),
+		httptest.NewRequest("DELETE", "/admin/pull-requests/api/7/messages", nil),
+		httptest.NewRequest("POST", "/admin/pull-requests/api/7/restore", nil),
+		httptest.NewRequest("GET", "/admin/pull-requests/api/7/events", nil),
+		httptest.NewRequest("GET", "/admin/archives/api/7", nil),
+		httptest.NewRequest("DELETE", "/admin/users/U1", nil),
+	} {
+		rr := httptest.NewRecorder()
+		adminMux().ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s returned %v, expected %v", req.Method, req.URL.Path, rr.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestAdminHandlersBadNumber(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/admin/pull-requests/api/seven/resend", nil),
+		httptest.NewRequest("DELETE", "/admin/pull-requests/api/seven/messages", nil),
+		httptest.NewRequest("POST", "/admin/pull-requests/api/seven/restore", nil),
+		httptest.NewRequest("GET", "/admin/pull-requests/api/seven/events", nil),
+		httptest.NewRequest("GET", "/admin/archives/api/seven", nil),
+	} {
+		req.Header.Set("Authorization", "Bearer secret")
+		rr := httptest.NewRecorder()
+		adminMux().ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s %s returned %v, expected %v", req.Method, req.URL.Path, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestSentTimeStamps(t *testing.T) {
+	records := []types.TableAuditData{
+		{Type: "parent", TimeStamp: "1.000001"},
+		{Type: "thread", TimeStamp: "1.000002", ThreadTimeStamp: "1.000001"},
+		{Type: "update", TimeStamp: "1.000001"},
+		{Type: "thread", TimeStamp: "dry-run"},
+		{Type: "buttons", TimeStamp: "1.000003", ThreadTimeStamp: "1.000001"},
+	}
+
+	result := sentTimeStamps(records)
+	expected := []string{"1.000001", "1.000002", "1.000003"}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, result)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"slack-pr-lambda/alert"
+
+	"go.uber.org/zap"
+)
+
+// keeps the status code so failed webhooks can be reported once handled
+type failureWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *failureWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// 5xx responses count towards the ops channel alert
+func reportFailure(w *failureWriter, repository string, action string, zapLog *zap.Logger) {
+	if w.status < http.StatusInternalServerError {
+		return
+	}
+
+	if err := alert.Failure(repository, action); err != nil {
+		zapLog.Error("error send failure alert",
+			zap.Error(err),
+		)
+	}
+}
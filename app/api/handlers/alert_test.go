@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFailureWriter(t *testing.T) {
+	rr := httptest.NewRecorder()
+	w := &failureWriter{ResponseWriter: rr}
+
+	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+	if w.status != http.StatusInternalServerError {
+		t.Errorf("Expected the status to be kept, got %d", w.status)
+	}
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected the status to be written, got %d", rr.Code)
+	}
+}
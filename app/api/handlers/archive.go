@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slack-pr-lambda/archive"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strconv"
+	"syscall"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// thread and lifecycle of a closing pull request, the closing event is not yet
+// recorded in the review metrics so it is taken from the webhook
+func archiveDocument(item types.TablePullRequestData, lifecycle types.TableReviewMetricsData, messages []slack.ThreadMessage, closedAt string, mergedAt string, now time.Time) archive.Document {
+	lifecycle.ClosedAt = closedAt
+	lifecycle.MergedAt = mergedAt
+	lifecycle.State = db.StateAbandoned
+	if mergedAt != "" {
+		lifecycle.State = db.StateMerged
+	}
+
+	return archive.Document{
+		PullRequest: audit.PullRequestKey(item.Repository, item.PullRequestId),
+		Repository:  item.Repository,
+		Number:      item.PullRequestId,
+		ArchivedAt:  now.Format(time.RFC3339),
+		Record:      item,
+		Lifecycle:   lifecycle,
+		Messages:    messages,
+	}
+}
+
+// export the closed pull request to ARCHIVE_BUCKET before its record is deleted,
+// the webhook doesn't fail on archive errors and untracked pull requests are
+// skipped
+func archivePullRequest(svc *awsdynamodb.DynamoDB, input types.ClosedPullRequest, repository string, zapLog *zap.Logger) {
+	if !archive.Enabled() {
+		return
+	}
+
+	err := func() error {
+		item, err := db.GetPullRequest(svc, int(input.PullRequest.GetID()), input.Number)
+		if errors.Is(err, db.ErrNoDataFound) {
+			zapLog.Info("skip archive of untracked pull request",
+				zap.String("repository", repository),
+				zap.Int("number", input.Number),
+			)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		lifecycle, err := db.GetReviewMetrics(svc, audit.PullRequestKey(repository, input.Number))
+		if errors.Is(err, db.ErrNoDataFound) {
+			lifecycle = &types.TableReviewMetricsData{PullRequest: audit.PullRequestKey(repository, input.Number), Repository: repository, Number: input.Number}
+		} else if err != nil {
+			return err
+		}
+
+		// an Unrouted pull request has no thread in SLACK_CHANNEL
+		messages := []slack.ThreadMessage{}
+		if item.SlackTimeStamp != audit.Unrouted {
+			messages, err = slack.SlackThreadReplies(item.SlackTimeStamp)
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		return archive.Put(archiveDocument(*item, *lifecycle, messages, orDefault(types.FormatTime(input.PullRequest.ClosedAt), now.Format(time.RFC3339)), types.FormatTime(input.PullRequest.MergedAt), now))
+	}()
+	if err != nil {
+		zapLog.Error("error archive pull request",
+			zap.String("repository", repository),
+			zap.Int("number", input.Number),
+			zap.Error(err),
+		)
+	}
+}
+
+// archived thread and lifecycle of a closed pull request as JSON
+func AdminArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	if !archive.Enabled() {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	document, err := archive.Get(r.PathValue("repository"), number)
+	if errors.Is(err, archive.ErrNotFound) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		zapLog.Error("error get archive",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestArchiveDocument(t *testing.T) {
+	now := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	item := types.TablePullRequestData{ID: "100", PullRequestId: 7, Repository: "api", SlackTimeStamp: "1.000001"}
+	lifecycle := types.TableReviewMetricsData{PullRequest: "api#7", OpenedAt: "2024-05-01T10:00:00Z", State: db.StateOpen}
+	messages := []slack.ThreadMessage{{BotId: "B1", Text: "opened", TimeStamp: "1.000001"}}
+
+	document := archiveDocument(item, lifecycle, messages, "2024-05-02T09:00:00Z", "2024-05-02T09:00:00Z", now)
+	if document.PullRequest != "api#7" || document.Repository != "api" || document.Number != 7 {
+		t.Errorf("archiveDocument() returned key %v %v %v", document.PullRequest, document.Repository, document.Number)
+	}
+	if document.ArchivedAt != "2024-05-02T10:00:00Z" {
+		t.Errorf("archiveDocument() archived at %v", document.ArchivedAt)
+	}
+	if document.Lifecycle.State != db.StateMerged || document.Lifecycle.MergedAt != "2024-05-02T09:00:00Z" || document.Lifecycle.OpenedAt != "2024-05-01T10:00:00Z" {
+		t.Errorf("archiveDocument() returned lifecycle %+v", document.Lifecycle)
+	}
+	if len(document.Messages) != 1 {
+		t.Errorf("archiveDocument() returned %v messages, expected 1", len(document.Messages))
+	}
+
+	document = archiveDocument(item, lifecycle, messages, "2024-05-02T09:00:00Z", "", now)
+	if document.Lifecycle.State != db.StateAbandoned || document.Lifecycle.ClosedAt != "2024-05-02T09:00:00Z" {
+		t.Errorf("archiveDocument() returned lifecycle %+v", document.Lifecycle)
+	}
+}
+
+func TestAdminArchiveHandlerDisabled(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	t.Setenv("ARCHIVE_BUCKET", "")
+
+	req := httptest.NewRequest("GET", "/admin/archives/api/7", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr := httptest.NewRecorder()
+	adminMux().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("handler returned %v, expected %v", rr.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/types"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// share of the changed files a class needs to annotate the parent message
+const mostlyPercent = 80
+
+var getPullRequestFiles = github.GetPullRequestFiles
+
+// changed files of the parent message, nil when the lookup fails
+func changedFiles(repo string, number int, zapLog *zap.Logger) []string {
+	files, err := getPullRequestFiles(repo, number)
+	if err != nil {
+		zapLog.Warn("error list pull request files",
+			zap.String("repository", repo),
+			zap.Int("number", number),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return files
+}
+
+// "Only lockfiles" or "Mostly generated files (14/16)" when a file class of
+// the repository covers most of the diff, empty otherwise
+func diffClass(repo string, files []string, zapLog *zap.Logger) string {
+	classes := config.DefaultFileClasses
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+	} else {
+		classes = conf.Repo(repo).GetFileClasses()
+	}
+
+	counts := map[string]int{}
+	for _, file := range files {
+		if class := config.FileClassOf(classes, file); class != "" {
+			counts[class]++
+		}
+	}
+
+	// ties go to the class listed first
+	largest := ""
+	for _, class := range classes {
+		if counts[class.Name] > counts[largest] {
+			largest = class.Name
+		}
+	}
+
+	count := counts[largest]
+	switch {
+	case count == 0:
+		return ""
+	case count == len(files):
+		return "Only " + largest
+	case count*100 >= len(files)*mostlyPercent:
+		return fmt.Sprintf("Mostly %s (%d/%d)", largest, count, len(files))
+	}
+	return ""
+}
+
+// a push may change the files of the pull request, the parent message is only
+// re-rendered when the summary changed
+func changedFilesPush(svc *awsdynamodb.DynamoDB, out audit.Messenger, id int, number int, zapLog *zap.Logger) error {
+	files := changedFiles(out.Repository, number, zapLog)
+	if files == nil {
+		return nil
+	}
+	directories := types.ChangedDirectories(files)
+	class := diffClass(out.Repository, files, zapLog)
+
+	item, err := db.GetPullRequest(svc, id, number)
+	if errors.Is(err, db.ErrNoDataFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if maps.Equal(directories, item.ChangedDirectories) && class == item.DiffClass {
+		out.Trail.Skip("changed files unchanged")
+		return nil
+	}
+
+	if err := db.UpdateChangedFiles(svc, id, number, directories, class, item.Version); err != nil {
+		return err
+	}
+	item.ChangedDirectories = directories
+	item.DiffClass = class
+
+	// tracked before the parent message was stored
+	if item.ParentMessage == "" {
+		return nil
+	}
+	return out.UpdateMessage(item.SlackTimeStamp, messages.ParentMessage(item, time.Now()))
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"slack-pr-lambda/audit"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func stubPullRequestFiles(t *testing.T, files []string, err error) {
+	original := getPullRequestFiles
+	getPullRequestFiles = func(repo string, prNumber int) ([]string, error) {
+		return files, err
+	}
+	t.Cleanup(func() {
+		getPullRequestFiles = original
+	})
+}
+
+func TestChangedFiles(t *testing.T) {
+	stubPullRequestFiles(t, nil, errors.New("rate limited"))
+
+	if files := changedFiles("api", 7, zap.NewNop()); files != nil {
+		t.Errorf("Expected no files, got %v", files)
+	}
+
+	// the summary of the parent message is kept when the files can't be listed
+	trail := &audit.Trail{}
+	if err := changedFilesPush(nil, audit.Messenger{Repository: "api", Log: zap.NewNop(), Trail: trail}, 1, 7, zap.NewNop()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiffClass(t *testing.T) {
+	generated := []string{"api/user.pb.go", "api/order.pb.go", "api/item.pb.go", "api/cart.pb.go"}
+
+	tests := []struct {
+		name     string
+		files    []string
+		expected string
+	}{
+		{"lockfile only", []string{"go.sum", "web/yarn.lock"}, "Only lockfiles"},
+		{"mostly generated", append(generated, "api/main.go"), "Mostly generated files (4/5)"},
+		{"regular diff", []string{"api/main.go", "api/user.pb.go"}, ""},
+		{"no files", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := diffClass("api", tt.files, zap.NewNop()); result != tt.expected {
+				t.Errorf("got %q want %q", result, tt.expected)
+			}
+		})
+	}
+
+	t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"fileClasses": [{"name": "migrations", "paths": ["db/migrations"]}]}}}`)
+	if result := diffClass("api", []string{"db/migrations/001_init.sql"}, zap.NewNop()); result != "Only migrations" {
+		t.Errorf("Expected the configured class, got %q", result)
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// body edits checking or adding checkboxes of the pull request template
+// re-render the parent message
+func checklistEdit(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent) error {
+	if event.Changes.GetBody() == nil || event.PullRequest == nil {
+		out.Trail.Skip("not a body edit")
+		return nil
+	}
+
+	done, total := types.Checklist(event.PullRequest.GetBody())
+	doneBefore, totalBefore := types.Checklist(event.Changes.GetBody().GetFrom())
+	if done == doneBefore && total == totalBefore {
+		out.Trail.Skip("checklist unchanged")
+		return nil
+	}
+
+	id := int(event.PullRequest.GetID())
+	number := event.PullRequest.GetNumber()
+	item, err := db.GetPullRequest(svc, id, number)
+	if errors.Is(err, db.ErrNoDataFound) {
+		out.Trail.Skip("pull request not tracked")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := db.UpdateChecklist(svc, id, number, done, total, item.Version); err != nil {
+		return err
+	}
+	item.ChecklistDone = done
+	item.ChecklistTotal = total
+
+	// tracked before the parent message was stored
+	if item.ParentMessage == "" {
+		return nil
+	}
+	return out.UpdateMessage(item.SlackTimeStamp, messages.ParentMessage(item, time.Now()))
+}
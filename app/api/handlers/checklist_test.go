@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+func TestChecklistEdit(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   types.WebhookEvent
+		skipped string
+	}{
+		{
+			name:    "title edit",
+			event:   types.WebhookEvent{PullRequest: &gogithub.PullRequest{}, Changes: &gogithub.EditChange{Title: &gogithub.EditTitle{}}},
+			skipped: "not a body edit",
+		},
+		{
+			name: "same checklist",
+			event: types.WebhookEvent{
+				PullRequest: &gogithub.PullRequest{Body: gogithub.String("Adds the client.\n\n- [x] Bug fix\n- [ ] New feature")},
+				Changes:     &gogithub.EditChange{Body: &gogithub.EditBody{From: gogithub.String("- [ ] Bug fix\n- [x] New feature")}},
+			},
+			skipped: "checklist unchanged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trail := &audit.Trail{}
+			out := audit.Messenger{Repository: "web", Log: zap.NewNop(), Trail: trail}
+
+			if err := checklistEdit(nil, out, tt.event); err != nil {
+				t.Fatal(err)
+			}
+			if skipped := trail.Skipped(); len(skipped) != 1 || skipped[0] != tt.skipped {
+				t.Errorf("Expected %q to be skipped, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/types"
+	"strings"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// comments whose first line starts with it are commands, e.g. "/slack mute"
+const commentCommandPrefix = "/slack"
+
+const commentCommandUsage = "Usage: `/slack remind`, `/slack mute [off]` or `/slack route <channel id> [off]`."
+
+var hasWriteAccess = github.HasWriteAccess
+
+var getRequestedReviewers = github.GetRequestedReviewers
+
+var reactComment = github.CreateCommentReaction
+
+// Slack channel id, or a channel link copied from Slack
+var channelId = regexp.MustCompile(`^<?#?([CG][A-Z0-9]{6,})(\|[^>]*)?>?$`)
+
+type commentCommand struct {
+	Name string
+	Args []string
+}
+
+// command of the first line of a comment, false for regular comments
+func parseCommentCommand(body string) (commentCommand, bool) {
+	line, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != commentCommandPrefix {
+		return commentCommand{}, false
+	}
+	if len(fields) == 1 {
+		return commentCommand{}, true
+	}
+	return commentCommand{Name: strings.ToLower(fields[1]), Args: fields[2:]}, true
+}
+
+// runs a "/slack <command>" comment of a user with write access, or a remind
+// of the author, if the repository allows the command. The comment gets a +1
+// reaction, refused or invalid commands are answered with a comment
+func runCommentCommand(svc *awsdynamodb.DynamoDB, out audit.Messenger, timeStamp string, prId int, event types.WebhookEvent, command commentCommand, slackUsersMap map[string]interface{}, zapLog *zap.Logger) error {
+	repository := event.Repository.GetName()
+	number := event.Issue.GetNumber()
+	login := event.Comment.GetUser().GetLogin()
+
+	reply, err := commentCommandReply(svc, out, timeStamp, prId, event, command, slackUsersMap, zapLog)
+	if err != nil {
+		return err
+	}
+
+	if reply != "" {
+		return createComment(repository, number, fmt.Sprintf("@%s %s", login, reply))
+	}
+	if err := reactComment(repository, event.Comment.GetID(), "+1"); err != nil {
+		zapLog.Warn("error react to comment command",
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// answer of a command that could not run, empty when it ran
+func commentCommandReply(svc *awsdynamodb.DynamoDB, out audit.Messenger, timeStamp string, prId int, event types.WebhookEvent, command commentCommand, slackUsersMap map[string]interface{}, zapLog *zap.Logger) (string, error) {
+	repository := event.Repository.GetName()
+	number := event.Issue.GetNumber()
+	login := event.Comment.GetUser().GetLogin()
+
+	if !commentCommandAllowed(repository, command.Name, zapLog) {
+		return commentCommandUsage, nil
+	}
+
+	// authors may ask for a reminder, mute and route need write access: on a
+	// public repository a fork contributor could route into any channel
+	if login != event.Issue.GetUser().GetLogin() || command.Name != "remind" {
+		canWrite, err := hasWriteAccess(repository, login)
+		if err != nil {
+			return "", err
+		}
+		if !canWrite {
+			return fmt.Sprintf("You need write access to `%s` to run `/slack %s`.", repository, command.Name), nil
+		}
+	}
+
+	key := audit.PullRequestKey(repository, number)
+	off := len(command.Args) > 0 && strings.ToLower(command.Args[len(command.Args)-1]) == "off"
+
+	switch command.Name {
+	case "remind":
+		if timeStamp == "" {
+			return "This pull request has no Slack thread.", nil
+		}
+		reviewers, err := getRequestedReviewers(repository, number)
+		if err != nil {
+			return "", err
+		}
+		reviewers, _ = withoutAuthor(reviewers, event.Issue.GetUser().GetLogin())
+		if len(reviewers) == 0 {
+			return "No reviewers are requested.", nil
+		}
+		ooo := outOfOffice(svc, zapLog)
+		alternates := reviewAlternates(repository, number, reviewers, event.Issue.GetUser().GetLogin(), ooo, false, time.Now(), zapLog)
+		message := fmt.Sprintf("%s %s", constants.Emoji().Reminder, reviewRequestMessage(reviewers, alternates, slackUsersMap, ooo, nil, time.Now()))
+		return "", pingReviewers(svc, out, timeStamp, prId, number, reviewers, message, zapLog)
+	case "mute":
+		if off {
+			return "", db.DeleteMute(svc, key, db.MuteChannel)
+		}
+		return "", db.InsertMute(svc, &types.TableMuteData{
+			PullRequest: key,
+			SlackUserId: db.MuteChannel,
+			MutedAt:     time.Now().Format(time.RFC3339),
+		})
+	case "route":
+		if len(command.Args) == 0 {
+			return commentCommandUsage, nil
+		}
+		match := channelId.FindStringSubmatch(command.Args[0])
+		if match == nil {
+			return fmt.Sprintf("`%s` is not a Slack channel id, e.g. `C0123456789`.", command.Args[0]), nil
+		}
+		if off {
+			return "", db.DeleteSubscription(svc, key, match[1])
+		}
+		return "", db.InsertSubscription(svc, &types.TableSubscriptionData{
+			PullRequest:  key,
+			SlackUserId:  match[1],
+			SubscribedAt: time.Now().Format(time.RFC3339),
+		})
+	}
+	return commentCommandUsage, nil
+}
+
+// unknown commands are not allowed, a missing repository config allows the
+// default commands
+func commentCommandAllowed(repository string, name string, zapLog *zap.Logger) bool {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		return (config.RepoConfig{}).AllowsCommentCommand(name)
+	}
+	return conf.Repo(repository).AllowsCommentCommand(name)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestParseCommentCommand(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected commentCommand
+		ok       bool
+	}{
+		{body: "/slack mute", expected: commentCommand{Name: "mute", Args: []string{}}, ok: true},
+		{body: "  /slack Route C0123456789 off\nthanks", expected: commentCommand{Name: "route", Args: []string{"C0123456789", "off"}}, ok: true},
+		{body: "/slack", ok: true},
+		{body: "LGTM\n/slack mute"},
+		{body: "/slackbot mute"},
+	}
+
+	for _, tt := range tests {
+		command, ok := parseCommentCommand(tt.body)
+		assert.Equal(t, tt.ok, ok, tt.body)
+		assert.Equal(t, tt.expected, command, tt.body)
+	}
+}
+
+func TestCommentCommandReply(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"repositories": {"web": {"commentCommands": ["none"]}}}`)
+	original := hasWriteAccess
+	hasWriteAccess = func(repo string, login string) (bool, error) {
+		return login == "maintainer", nil
+	}
+	t.Cleanup(func() {
+		hasWriteAccess = original
+	})
+
+	event := func(repository string, commenter string, body string) types.WebhookEvent {
+		return types.WebhookEvent{
+			Action:     "created",
+			Repository: &github.Repository{Name: github.String(repository)},
+			Issue:      &github.Issue{Number: github.Int(7), User: &github.User{Login: github.String("alice")}},
+			Comment:    &github.PullRequestComment{ID: github.Int64(1), Body: github.String(body), User: &github.User{Login: github.String(commenter)}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		event    types.WebhookEvent
+		expected string
+	}{
+		{name: "unknown command", event: event("api", "alice", "/slack deploy"), expected: commentCommandUsage},
+		{name: "not allowed", event: event("web", "alice", "/slack mute"), expected: commentCommandUsage},
+		{name: "no write access", event: event("api", "bob", "/slack mute"), expected: "You need write access to `api` to run `/slack mute`."},
+		{name: "no thread", event: event("api", "maintainer", "/slack remind"), expected: "This pull request has no Slack thread."},
+		{name: "not a channel", event: event("api", "maintainer", "/slack route general"), expected: "`general` is not a Slack channel id, e.g. `C0123456789`."},
+		{name: "author remind", event: event("api", "alice", "/slack remind"), expected: "This pull request has no Slack thread."},
+		{name: "author route", event: event("api", "alice", "/slack route C0123456789"), expected: "You need write access to `api` to run `/slack route`."},
+		{name: "author mute", event: event("api", "alice", "/slack mute"), expected: "You need write access to `api` to run `/slack mute`."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := audit.Messenger{Repository: tt.event.Repository.GetName(), Log: zap.NewNop(), Trail: &audit.Trail{}}
+			command, _ := parseCommentCommand(tt.event.Comment.GetBody())
+
+			reply, err := commentCommandReply(nil, out, "", 1, tt.event, command, map[string]interface{}{}, zap.NewNop())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, reply)
+		})
+	}
+}
+
+func TestRunCommentCommandReplies(t *testing.T) {
+	comments := []string{}
+	originalComment := createComment
+	createComment = func(repo string, number int, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+	t.Cleanup(func() {
+		createComment = originalComment
+	})
+
+	event := types.WebhookEvent{
+		Repository: &github.Repository{Name: github.String("api")},
+		Issue:      &github.Issue{Number: github.Int(7), User: &github.User{Login: github.String("alice")}},
+		Comment:    &github.PullRequestComment{ID: github.Int64(1), User: &github.User{Login: github.String("alice")}},
+	}
+	out := audit.Messenger{Repository: "api", Log: zap.NewNop(), Trail: &audit.Trail{}}
+
+	assert.NoError(t, runCommentCommand(nil, out, "", 1, event, commentCommand{Name: "remind"}, map[string]interface{}{}, zap.NewNop()))
+	assert.Equal(t, []string{"@alice This pull request has no Slack thread."}, comments)
+}
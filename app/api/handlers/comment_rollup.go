@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"slack-pr-lambda/config"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"strconv"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// comment notifications in a thread before they are rolled up, the repository
+// commentRollupAfter or COMMENT_ROLLUP_AFTER, 0 when the repository opted out
+func commentRollupAfter(repository string) int {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		conf, _ = config.ParseConfig("")
+	}
+
+	repo := conf.Repo(repository)
+	if repo.DisableCommentRollup {
+		return 0
+	}
+	if repo.CommentRollupAfter > 0 {
+		return repo.CommentRollupAfter
+	}
+
+	after, err := strconv.Atoi(env.GetEnv("COMMENT_ROLLUP_AFTER", "20"))
+	if err != nil || after <= 0 {
+		after = 20
+	}
+	return after
+}
+
+// counts the comment notification of the thread, past the threshold the
+// comment is kept for the rollup job instead and true is returned
+func rollupComment(svc *awsdynamodb.DynamoDB, id int, pullRequestId int, repository string, now time.Time) (bool, error) {
+	after := commentRollupAfter(repository)
+	if after == 0 {
+		return false, nil
+	}
+
+	count, err := db.AddCommentNotification(svc, id, pullRequestId)
+	if err != nil {
+		return false, err
+	}
+	if count <= after {
+		return false, nil
+	}
+
+	return true, db.AddPendingComment(svc, id, pullRequestId, now.Format(time.RFC3339))
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommentRollupAfter(t *testing.T) {
+	t.Setenv("COMMENT_ROLLUP_AFTER", "12")
+	t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"commentRollupAfter": 5}, "web": {"disableCommentRollup": true}}}`)
+
+	tests := map[string]int{
+		"api":    5,
+		"web":    0,
+		"worker": 12,
+	}
+
+	for repository, expected := range tests {
+		if after := commentRollupAfter(repository); after != expected {
+			t.Errorf("%s: expected %d, got %d", repository, expected, after)
+		}
+	}
+}
+
+func TestRollupCommentDisabled(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"default": {"disableCommentRollup": true}}`)
+
+	// opted out repositories never touch the counter
+	rolledUp, err := rollupComment(nil, 1, 1, "api", time.Now())
+	if err != nil || rolledUp {
+		t.Errorf("Expected the comment to be notified, got %v %v", rolledUp, err)
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slack-pr-lambda/logger"
+	"strings"
+)
+
+// remembers whether the handler answered a plain text error, e.g. with
+// http.Error
+type correlatedWriter struct {
+	http.ResponseWriter
+	plainError bool
+}
+
+func (w *correlatedWriter) WriteHeader(status int) {
+	w.plainError = status >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain")
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// the GitHub delivery id of a webhook, a random id for other requests. It is
+// logged by every logger of the request context, answered in X-Correlation-Id
+// and appended to the body of plain text errors so a failed request reported
+// by a user can be found in the logs
+func Correlated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-GitHub-Delivery")
+		if id == "" {
+			id = logger.NewCorrelationId()
+		}
+		w.Header().Set("X-Correlation-Id", id)
+
+		correlated := &correlatedWriter{ResponseWriter: w}
+		next(correlated, r.WithContext(logger.WithCorrelationId(r.Context(), id)))
+
+		if correlated.plainError {
+			fmt.Fprintf(w, "correlation id: %s\n", id)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelated(t *testing.T) {
+	var seen string
+	handler := Correlated(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.CorrelationId(r.Context())
+		if r.URL.Query().Get("fail") != "" {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"error"}`))
+	})
+
+	t.Run("delivery id", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/pull-request?fail=1", nil)
+		req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		assert.Equal(t, "72d3162e-cc78-11e3-81ab-4c9367dc0958", seen)
+		assert.Equal(t, "72d3162e-cc78-11e3-81ab-4c9367dc0958", rr.Header().Get("X-Correlation-Id"))
+		assert.Equal(t, "Internal Server Error\ncorrelation id: 72d3162e-cc78-11e3-81ab-4c9367dc0958\n", rr.Body.String())
+	})
+
+	t.Run("generated id", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("GET", "/healthz", nil))
+
+		assert.NotEmpty(t, seen)
+		assert.Equal(t, seen, rr.Header().Get("X-Correlation-Id"))
+		// JSON bodies are left as is
+		assert.Equal(t, `{"status":"error"}`, rr.Body.String())
+	})
+}
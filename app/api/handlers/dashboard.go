@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"slack-pr-lambda/api/dashboard"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+var refreshDashboard = dashboard.Refresh
+
+// actions changing the open pull requests, their age or their reviewers
+var dashboardActions = []string{"opened", "reopened", "closed", "review_requested", "submitted", "dismissed"}
+
+// the pinned dashboard is refreshed once the event is handled, failing to do so
+// doesn't fail the webhook
+func updateDashboard(w *failureWriter, action string, zapLog *zap.Logger) {
+	if w.status >= 400 || !slices.Contains(dashboardActions, action) {
+		return
+	}
+
+	if err := refreshDashboard(zapLog); err != nil {
+		zapLog.Error("error refresh dashboard",
+			zap.Error(err),
+		)
+	}
+}
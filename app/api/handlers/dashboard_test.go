@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestUpdateDashboard(t *testing.T) {
+	calls := 0
+	original := refreshDashboard
+	refreshDashboard = func(zapLog *zap.Logger) error {
+		calls++
+		return nil
+	}
+	t.Cleanup(func() {
+		refreshDashboard = original
+	})
+
+	ok := &failureWriter{ResponseWriter: httptest.NewRecorder()}
+	updateDashboard(ok, "opened", zap.NewNop())
+	updateDashboard(ok, "synchronize", zap.NewNop())
+
+	failed := &failureWriter{ResponseWriter: httptest.NewRecorder()}
+	failed.WriteHeader(http.StatusInternalServerError)
+	updateDashboard(failed, "closed", zap.NewNop())
+
+	if calls != 1 {
+		t.Errorf("Expected 1 refresh, got %d", calls)
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// body edits declaring or dropping "Depends on org/repo#123" re-render the
+// parent message
+func dependenciesEdit(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent) error {
+	if event.Changes.GetBody() == nil || event.PullRequest == nil {
+		out.Trail.Skip("not a body edit")
+		return nil
+	}
+
+	dependencies := types.Dependencies(event.PullRequest.GetBody())
+	if slices.Equal(dependencies, types.Dependencies(event.Changes.GetBody().GetFrom())) {
+		out.Trail.Skip("dependencies unchanged")
+		return nil
+	}
+
+	id := int(event.PullRequest.GetID())
+	number := event.PullRequest.GetNumber()
+	item, err := db.GetPullRequest(svc, id, number)
+	if errors.Is(err, db.ErrNoDataFound) {
+		out.Trail.Skip("pull request not tracked")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := db.UpdateDependencies(svc, id, number, dependencies, item.Version); err != nil {
+		return err
+	}
+	item.Dependencies = dependencies
+
+	// tracked before the parent message was stored
+	if item.ParentMessage == "" {
+		return nil
+	}
+	return out.UpdateMessage(item.SlackTimeStamp, messages.ParentMessage(item, time.Now()))
+}
+
+// a merged pull request unblocks the tracked pull requests depending on it,
+// failures are only logged as the merge itself was notified
+func unblockDependents(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent, zapLog *zap.Logger) {
+	reference := fmt.Sprintf("%s#%d", event.Repository.GetFullName(), event.PullRequest.GetNumber())
+
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		zapLog.Warn("error list pull requests",
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, item := range items {
+		dependency, ok := dependsOn(item, reference)
+		if !ok {
+			continue
+		}
+		if err := unblockDependent(svc, out, item, dependency); err != nil {
+			zapLog.Warn("error unblock dependent pull request",
+				zap.String("dependency", reference),
+				zap.String("repository", item.Repository),
+				zap.Int("number", item.PullRequestId),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// dependency of the item as it was declared, references are case insensitive
+func dependsOn(item types.TablePullRequestData, reference string) (string, bool) {
+	for _, dependency := range item.Dependencies {
+		if strings.EqualFold(dependency, reference) {
+			return dependency, true
+		}
+	}
+	return "", false
+}
+
+func unblockDependent(svc *awsdynamodb.DynamoDB, out audit.Messenger, item types.TablePullRequestData, dependency string) error {
+	id, err := strconv.Atoi(item.ID)
+	if err != nil {
+		return err
+	}
+
+	merged, err := db.AddMergedDependency(svc, id, item.PullRequestId, dependency)
+	if err != nil || !merged {
+		return err
+	}
+
+	// messages of the dependent pull request
+	out.Repository = item.Repository
+	out.Number = item.PullRequestId
+
+	mergedDependencies := item.MergedDependencies
+	message := func(language string) string {
+		return messages.DependencyMergedMessage(language, dependency, item.Dependencies, mergedDependencies)
+	}
+	item.MergedDependencies = append(item.MergedDependencies, dependency)
+	if item.ParentMessage != "" {
+		if err := out.UpdateMessage(item.SlackTimeStamp, messages.ParentMessage(&item, time.Now())); err != nil {
+			return err
+		}
+	}
+	return out.Localized(message).SendMessageThread(item.SlackTimeStamp, message(messages.Language()))
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+func TestDependenciesEdit(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   types.WebhookEvent
+		skipped string
+	}{
+		{
+			name:    "title edit",
+			event:   types.WebhookEvent{PullRequest: &gogithub.PullRequest{}, Changes: &gogithub.EditChange{Title: &gogithub.EditTitle{}}},
+			skipped: "not a body edit",
+		},
+		{
+			name: "same dependencies",
+			event: types.WebhookEvent{
+				PullRequest: &gogithub.PullRequest{Body: gogithub.String("Adds the client.\n\nDepends on acme/api#12")},
+				Changes:     &gogithub.EditChange{Body: &gogithub.EditBody{From: gogithub.String("Depends on acme/api#12")}},
+			},
+			skipped: "dependencies unchanged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trail := &audit.Trail{}
+			out := audit.Messenger{Repository: "web", Log: zap.NewNop(), Trail: trail}
+
+			if err := dependenciesEdit(nil, out, tt.event); err != nil {
+				t.Fatal(err)
+			}
+			if skipped := trail.Skipped(); len(skipped) != 1 || skipped[0] != tt.skipped {
+				t.Errorf("Expected %q to be skipped, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+}
+
+func TestDependsOn(t *testing.T) {
+	item := types.TablePullRequestData{Dependencies: []string{"Acme/API#12", "acme/web#7"}}
+
+	if dependency, ok := dependsOn(item, "acme/api#12"); !ok || dependency != "Acme/API#12" {
+		t.Errorf("Expected the declared dependency, got %q %v", dependency, ok)
+	}
+	if _, ok := dependsOn(item, "acme/api#7"); ok {
+		t.Errorf("Expected acme/api#7 not to be a dependency")
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"slack-pr-lambda/api/mail"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/types"
+	"strings"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// whether the body misses the description required by the repository config,
+// with the missing sections. A broken config requires nothing
+func incompleteDescription(repo string, body string, zapLog *zap.Logger) (bool, []string) {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		return false, nil
+	}
+	return conf.Repo(repo).IncompleteDescription(body)
+}
+
+// thread note asking the author to fill in the description, the review pings
+// are held until then
+func descriptionNudge(author string, missing []string, slackUsersMap map[string]interface{}) string {
+	text := fmt.Sprintf("%s please complete the description of the pull request, reviewers are pinged once it is filled in.", mail.Mention(author, slackUsersMap, nil))
+	if len(missing) > 0 {
+		text += fmt.Sprintf(" Missing sections: `%s`.", strings.Join(missing, "`, `"))
+	}
+	return text
+}
+
+// body edits completing the required description ping the requested
+// reviewers that were held back
+func descriptionEdit(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent, slackUsersMap map[string]interface{}, zapLog *zap.Logger) error {
+	if event.Changes.GetBody() == nil || event.PullRequest == nil {
+		out.Trail.Skip("not a body edit")
+		return nil
+	}
+
+	repo := event.Repository.GetName()
+	wasIncomplete, _ := incompleteDescription(repo, event.Changes.GetBody().GetFrom(), zapLog)
+	incomplete, _ := incompleteDescription(repo, event.PullRequest.GetBody(), zapLog)
+	if !wasIncomplete || incomplete {
+		out.Trail.Skip("description not completed")
+		return nil
+	}
+	if types.WorkInProgress(event.PullRequest.GetTitle()) {
+		out.Trail.Skip("work in progress")
+		return nil
+	}
+
+	id := int(event.PullRequest.GetID())
+	number := event.PullRequest.GetNumber()
+	timeStamp, err := slackTimeStamp(svc, out.Trail, id, number)
+	if err != nil || !tracked(out.Trail, timeStamp) {
+		return err
+	}
+	return pingHeldReviewers(svc, out, event, timeStamp, slackUsersMap, zapLog)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+func TestDescriptionEdit(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"requiredSections": ["(?m)^## Testing"]}}}`)
+
+	original := getSlackTimeStamp
+	getSlackTimeStamp = func(svc *awsdynamodb.DynamoDB, id int, pullRequestId int) (string, error) {
+		return "", nil
+	}
+	t.Cleanup(func() {
+		getSlackTimeStamp = original
+	})
+
+	repository := &gogithub.Repository{Name: gogithub.String("api")}
+	tests := []struct {
+		name    string
+		event   types.WebhookEvent
+		skipped string
+	}{
+		{
+			name:    "title edit",
+			event:   types.WebhookEvent{Repository: repository, PullRequest: &gogithub.PullRequest{}, Changes: &gogithub.EditChange{Title: &gogithub.EditTitle{}}},
+			skipped: "not a body edit",
+		},
+		{
+			name: "still incomplete",
+			event: types.WebhookEvent{
+				Repository:  repository,
+				PullRequest: &gogithub.PullRequest{Body: gogithub.String("Adds the client.")},
+				Changes:     &gogithub.EditChange{Body: &gogithub.EditBody{From: gogithub.String("")}},
+			},
+			skipped: "description not completed",
+		},
+		{
+			name: "already complete",
+			event: types.WebhookEvent{
+				Repository:  repository,
+				PullRequest: &gogithub.PullRequest{Body: gogithub.String("## Testing\nUnit tests, and more.")},
+				Changes:     &gogithub.EditChange{Body: &gogithub.EditBody{From: gogithub.String("## Testing\nUnit tests")}},
+			},
+			skipped: "description not completed",
+		},
+		{
+			name: "work in progress",
+			event: types.WebhookEvent{
+				Repository:  repository,
+				PullRequest: &gogithub.PullRequest{Title: gogithub.String("WIP: client"), Body: gogithub.String("## Testing\nUnit tests")},
+				Changes:     &gogithub.EditChange{Body: &gogithub.EditBody{From: gogithub.String("")}},
+			},
+			skipped: "work in progress",
+		},
+		{
+			name: "completed",
+			event: types.WebhookEvent{
+				Repository:  repository,
+				PullRequest: &gogithub.PullRequest{Title: gogithub.String("Add the client"), Body: gogithub.String("## Testing\nUnit tests")},
+				Changes:     &gogithub.EditChange{Body: &gogithub.EditBody{From: gogithub.String("")}},
+			},
+			skipped: "pull request not tracked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trail := &audit.Trail{}
+			out := audit.Messenger{Repository: "api", Log: zap.NewNop(), Trail: trail}
+
+			if err := descriptionEdit(nil, out, tt.event, map[string]interface{}{}, zap.NewNop()); err != nil {
+				t.Fatal(err)
+			}
+			if skipped := trail.Skipped(); len(skipped) != 1 || skipped[0] != tt.skipped {
+				t.Errorf("Expected %q to be skipped, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+}
+
+func TestDescriptionNudge(t *testing.T) {
+	slackUsersMap := map[string]interface{}{"alice": "U1"}
+
+	expected := "<@U1> please complete the description of the pull request, reviewers are pinged once it is filled in."
+	if nudge := descriptionNudge("alice", nil, slackUsersMap); nudge != expected {
+		t.Errorf("got %q want %q", nudge, expected)
+	}
+
+	expected += " Missing sections: `(?m)^## Testing`, `## Types`."
+	if nudge := descriptionNudge("alice", []string{"(?m)^## Testing", "## Types"}, slackUsersMap); nudge != expected {
+		t.Errorf("got %q want %q", nudge, expected)
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"go.uber.org/zap"
+)
+
+// STRICT_DECODING, deliveries are checked against the types they are decoded
+// into so payload drift from GitHub shows up as warnings before it turns into
+// empty Slack messages
+func strictDecoding() bool {
+	return env.GetEnv("STRICT_DECODING", "false") == "true"
+}
+
+// the delivery is still handled, each difference is only logged and counted
+func reportDrift(action string, body []byte, zapLog *zap.Logger) {
+	problems, err := types.Drift(action, body)
+	if err != nil {
+		zapLog.Warn("error check payload drift",
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, problem := range problems {
+		payloadDrift.Inc(action)
+		zapLog.Warn("payload drift",
+			zap.String("action", action),
+			zap.String("problem", problem),
+			zap.Int("schemaVersion", types.WebhookSchemaVersion),
+		)
+	}
+}
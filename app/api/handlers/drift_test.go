@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestReportDrift(t *testing.T) {
+	before := payloadDrift.Value("submitted")
+
+	reportDrift("submitted", []byte(`{"pull_request": {"id": 1, "number": 7, "user": {"login": "alice"}}, "review": {"user": {"login": "bob"}, "html_url": "https://github.com/o/api/pull/7#review"}}`), zap.NewNop())
+	if value := payloadDrift.Value("submitted") - before; value != 1 {
+		t.Errorf("Expected 1 drift for the missing review state, got %v", value)
+	}
+
+	// an invalid body is only logged
+	reportDrift("submitted", []byte(`not json`), zap.NewNop())
+	if value := payloadDrift.Value("submitted") - before; value != 1 {
+		t.Errorf("Expected no drift for an invalid body, got %v", value)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/api/mail"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strings"
+	"time"
+)
+
+const emailUsage = "Usage: `/pr-email on | off | status`."
+
+// replaced in tests
+var slackUserEmail = slack.SlackUserEmail
+
+// /pr-email slash command, review requests and reminders of the user are
+// emailed to the email of their Slack profile and named without a Slack
+// mention. Slack verified that address, a typed one could be anybody's
+func emailCommand(slackUserId string, text string) (string, error) {
+	command := strings.ToLower(strings.TrimSpace(text))
+	if command != "on" && command != "off" && command != "status" {
+		return emailUsage, nil
+	}
+
+	if !mail.Enabled() {
+		return "Emails are not enabled on this workspace.", nil
+	}
+
+	login := githubLogin(slackUserId)
+	if login == "" {
+		return "Your Slack account is not linked to a GitHub user.", nil
+	}
+
+	svc := db.DynamoDbConnection()
+	switch command {
+	case "off":
+		if err := db.DeleteEmailPreference(svc, login); err != nil {
+			return "", err
+		}
+		mail.Forget(login)
+		return "Review requests are no longer emailed to you.", nil
+	case "status":
+		item, err := db.GetEmailPreference(svc, login)
+		if errors.Is(err, db.ErrNoDataFound) {
+			return "Review requests are not emailed to you.", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		since := item.OptedInAt
+		if optedInAt, err := time.Parse(time.RFC3339, item.OptedInAt); err == nil {
+			since = slack.SlackDate(optedInAt, slack.DateTokens)
+		}
+		return fmt.Sprintf("Review requests are emailed to %s since %s.", item.Email, since), nil
+	}
+
+	address, err := slackUserEmail(slackUserId)
+	if err != nil {
+		return "", err
+	}
+	if address == "" {
+		return "Your Slack profile has no email.", nil
+	}
+
+	item := &types.TableEmailPreferenceData{
+		GithubLogin: login,
+		Email:       address,
+		OptedInAt:   time.Now().Format(time.RFC3339),
+	}
+	if err := db.InsertEmailPreference(svc, item); err != nil {
+		return "", err
+	}
+	mail.Forget(login)
+
+	return fmt.Sprintf("Review requests and reminders are now emailed to %s. `/pr-email off` stops it.", address), nil
+}
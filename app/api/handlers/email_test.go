@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmailCommandUsage(t *testing.T) {
+	for _, text := range []string{"", "bob@acme.com", "Bob <bob@acme.com>"} {
+		reply, err := emailCommand("U1", text)
+		if err != nil || reply != emailUsage {
+			t.Errorf("%q: expected the usage, got %q %v", text, reply, err)
+		}
+	}
+}
+
+func TestEmailCommandDisabled(t *testing.T) {
+	t.Setenv("EMAIL_FROM", "")
+
+	for _, text := range []string{"on", "off", "status"} {
+		reply, err := emailCommand("U1", text)
+		if err != nil || reply != "Emails are not enabled on this workspace." {
+			t.Errorf("%q: expected emails to be disabled, got %q %v", text, reply, err)
+		}
+	}
+}
+
+func TestEmailCommandProfile(t *testing.T) {
+	t.Setenv("EMAIL_FROM", "bot@acme.com")
+	t.Setenv("DRY_RUN", "true")
+	original := slackUserEmail
+	t.Cleanup(func() {
+		slackUserEmail = original
+	})
+
+	slackUserEmail = func(userId string) (string, error) {
+		return "", nil
+	}
+	reply, err := emailCommand("U06Q5GKADME", "on")
+	if err != nil || reply != "Your Slack profile has no email." {
+		t.Errorf("Expected no email, got %q %v", reply, err)
+	}
+
+	slackUserEmail = func(userId string) (string, error) {
+		return "rodentskie@acme.com", nil
+	}
+	reply, err = emailCommand("U06Q5GKADME", "on")
+	if err != nil || !strings.Contains(reply, "emailed to rodentskie@acme.com") {
+		t.Errorf("Expected the profile email, got %q %v", reply, err)
+	}
+}
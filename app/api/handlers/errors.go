@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"slack-pr-lambda/apperrors"
+	"slack-pr-lambda/metrics"
+	"strconv"
+)
+
+var handlerErrors = metrics.NewCounter("handler_errors_total", "Errors answered by the handlers by kind, see apperrors.Kind.", "kind")
+
+// answers err with the status of its kind and counts it, with a Retry-After
+// when Slack or DynamoDB asked to slow down
+func writeError(w http.ResponseWriter, err error) {
+	status := apperrors.Status(err)
+	handlerErrors.Inc(apperrors.Kind(err))
+
+	if after := apperrors.RetryAfter(err); after > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(after.Seconds()))))
+	}
+	http.Error(w, http.StatusText(status), status)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/apperrors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteError(t *testing.T) {
+	rr := httptest.NewRecorder()
+	writeError(rr, errors.New("boom"))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "Internal Server Error\n", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	limited := &apperrors.Error{Kind: apperrors.ErrSlackRateLimited, Op: "slack.chat.postMessage", Err: errors.New("429"), RetryAfter: 1500 * time.Millisecond}
+	writeError(rr, limited)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+	assert.Equal(t, float64(1), handlerErrors.Value("slack_rate_limited"))
+
+	rr = httptest.NewRecorder()
+	writeError(rr, apperrors.Wrap(apperrors.ErrPRNotFound, "github.pull_request", errors.New("404")))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Empty(t, rr.Header().Get("Retry-After"))
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"slack-pr-lambda/notifier"
+	"slack-pr-lambda/types"
+
+	"go.uber.org/zap"
+)
+
+var publish = notifier.Publish
+
+// publishes the processed webhook to EVENT_BUS_NAME
+func publishEvent(w *failureWriter, githubEvent string, eventId string, event types.WebhookEvent, zapLog *zap.Logger) {
+	sendProcessed("publish", notifier.EventBusName() != "", w, githubEvent, eventId, event, publish, zapLog)
+}
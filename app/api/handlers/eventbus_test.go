@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func stubPublish(t *testing.T, err error) *[]types.ForwardedEvent {
+	published := []types.ForwardedEvent{}
+	original := publish
+	publish = func(event types.ForwardedEvent) error {
+		published = append(published, event)
+		return err
+	}
+	t.Cleanup(func() {
+		publish = original
+	})
+	return &published
+}
+
+func TestPublishEvent(t *testing.T) {
+	event := types.WebhookEvent{
+		Action:     "closed",
+		Number:     7,
+		Repository: &github.Repository{Name: github.String("api")},
+		Sender:     &github.User{Login: github.String("octocat")},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		published := stubPublish(t, nil)
+		publishEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request", "delivery", event, zap.NewNop())
+		assert.Empty(t, *published)
+	})
+
+	t.Setenv("EVENT_BUS_NAME", "pulls")
+
+	t.Run("published", func(t *testing.T) {
+		published := stubPublish(t, nil)
+		publishEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request", "delivery", event, zap.NewNop())
+		if assert.Len(t, *published, 1) {
+			item := (*published)[0]
+			assert.Equal(t, "delivery", item.DeliveryId)
+			assert.Equal(t, "closed", item.Action)
+			assert.Equal(t, "octocat", item.Actor)
+		}
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		published := stubPublish(t, nil)
+		publishEvent(&failureWriter{ResponseWriter: httptest.NewRecorder(), status: 500}, "pull_request", "delivery", event, zap.NewNop())
+		assert.Empty(t, *published)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		published := stubPublish(t, errors.New("throttled"))
+		publishEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request", "delivery", event, zap.NewNop())
+		assert.Len(t, *published, 1)
+	})
+}
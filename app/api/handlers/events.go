@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var appendEvent = func(item *types.TableEventData) error {
+	return db.AppendEvent(db.DynamoDbConnection(), item)
+}
+
+// EVENT_SOURCING=true appends every processed webhook to the events of its
+// pull request
+func eventSourcing() bool {
+	return env.GetEnv("EVENT_SOURCING", "false") == "true"
+}
+
+// failed deliveries are redelivered by GitHub and appended then, redeliveries
+// of a recorded delivery are skipped
+func recordEvent(w *failureWriter, githubEvent string, eventId string, event types.WebhookEvent, zapLog *zap.Logger) {
+	repository := event.Repository.GetName()
+	number := event.PullRequestNumber()
+	if !eventSourcing() || w.status >= 400 || repository == "" || number == 0 {
+		return
+	}
+
+	err := appendEvent(&types.TableEventData{
+		PullRequest: audit.PullRequestKey(repository, number),
+		ReceivedAt:  time.Now().UTC().Format(types.SortableTime),
+		EventId:     eventId,
+		Event:       githubEvent,
+		Action:      event.Action,
+		Actor:       event.Sender.GetLogin(),
+	})
+	if errors.Is(err, db.ErrEventRecorded) {
+		zapLog.Info("event already recorded",
+			zap.String("eventId", eventId),
+		)
+		return
+	}
+	if err != nil {
+		zapLog.Error("error append event",
+			zap.Error(err),
+		)
+	}
+}
+
+// events of a pull request as JSON, oldest first
+func AdminEventsHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	events, err := db.ListEvents(db.DynamoDbConnection(), audit.PullRequestKey(r.PathValue("repository"), number))
+	if err != nil {
+		zapLog.Error("error list events",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	j, err := json.Marshal(events)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(j)
+}
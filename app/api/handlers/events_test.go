@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func stubAppendEvent(t *testing.T, err error) *[]types.TableEventData {
+	appended := []types.TableEventData{}
+	original := appendEvent
+	appendEvent = func(item *types.TableEventData) error {
+		appended = append(appended, *item)
+		return err
+	}
+	t.Cleanup(func() {
+		appendEvent = original
+	})
+	return &appended
+}
+
+func TestRecordEvent(t *testing.T) {
+	event := types.WebhookEvent{
+		Action:     "submitted",
+		Number:     7,
+		Repository: &github.Repository{Name: github.String("api")},
+		Sender:     &github.User{Login: github.String("octocat")},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		appended := stubAppendEvent(t, nil)
+		recordEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request_review", "delivery", event, zap.NewNop())
+		assert.Empty(t, *appended)
+	})
+
+	t.Setenv("EVENT_SOURCING", "true")
+
+	t.Run("appended", func(t *testing.T) {
+		appended := stubAppendEvent(t, nil)
+		recordEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request_review", "delivery", event, zap.NewNop())
+		if assert.Len(t, *appended, 1) {
+			item := (*appended)[0]
+			assert.Equal(t, "api#7", item.PullRequest)
+			assert.Equal(t, "delivery", item.EventId)
+			assert.Equal(t, "pull_request_review", item.Event)
+			assert.Equal(t, "submitted", item.Action)
+			assert.Equal(t, "octocat", item.Actor)
+			// fixed width so the range keys sort in time order
+			assert.Len(t, item.ReceivedAt, len("2006-01-02T15:04:05.000000000Z"))
+		}
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		appended := stubAppendEvent(t, nil)
+		recordEvent(&failureWriter{ResponseWriter: httptest.NewRecorder(), status: 500}, "pull_request_review", "delivery", event, zap.NewNop())
+		assert.Empty(t, *appended)
+	})
+
+	t.Run("no pull request", func(t *testing.T) {
+		appended := stubAppendEvent(t, nil)
+		recordEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "workflow_run", "delivery", types.WebhookEvent{Action: "completed", Repository: event.Repository}, zap.NewNop())
+		assert.Empty(t, *appended)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		appended := stubAppendEvent(t, errors.New("throttled"))
+		recordEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request_review", "delivery", event, zap.NewNop())
+		assert.Len(t, *appended, 1)
+	})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slack-pr-lambda/config"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// whether the feature flag is on for the repository, off when the config
+// fails to load so a new processor doesn't run by accident
+//
+//	if featureEnabled("ci_integration", repository, zapLog) { ... }
+func featureEnabled(feature string, repository string, zapLog *zap.Logger) bool {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		return false
+	}
+	return conf.Enabled(feature, repository)
+}
+
+// every flag of the config document and whether it is on for the repository
+func featureStates(conf *config.Config, repository string) map[string]bool {
+	states := map[string]bool{}
+	for feature := range conf.Features {
+		states[feature] = conf.Enabled(feature, repository)
+	}
+	return states
+}
+
+// feature flags of a repository, to check a rollout
+func AdminFeaturesHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Error("error load repository config",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	j, err := json.Marshal(featureStates(conf, r.PathValue("repository")))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(j)
+}
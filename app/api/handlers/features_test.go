@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestFeatureEnabled(t *testing.T) {
+	t.Setenv("CONFIG_TABLE_NAME", "")
+	t.Setenv("REPO_CONFIG", `{"features": {"ci_integration": {"repositories": ["api"]}}}`)
+
+	assert.True(t, featureEnabled("ci_integration", "api", zap.NewNop()))
+	assert.False(t, featureEnabled("ci_integration", "web", zap.NewNop()))
+
+	t.Setenv("REPO_CONFIG", "{")
+	assert.False(t, featureEnabled("ci_integration", "api", zap.NewNop()))
+}
+
+func TestFeatureStates(t *testing.T) {
+	conf := &config.Config{Features: map[string]config.Feature{
+		"ci_integration": {Enabled: true},
+		"two_way_sync":   {Repositories: []string{"web"}},
+	}}
+
+	assert.Equal(t, map[string]bool{"ci_integration": true, "two_way_sync": false}, featureStates(conf, "api"))
+}
+
+func TestAdminFeaturesHandler(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	t.Setenv("CONFIG_TABLE_NAME", "")
+	t.Setenv("REPO_CONFIG", `{"features": {"two_way_sync": {"repositories": ["api"]}}}`)
+
+	req := httptest.NewRequest("GET", "/admin/features/api", nil)
+	req.SetPathValue("repository", "api")
+	rr := httptest.NewRecorder()
+	AdminFeaturesHandler(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req.Header.Set("Authorization", "Bearer secret")
+	rr = httptest.NewRecorder()
+	AdminFeaturesHandler(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"two_way_sync": true}`, rr.Body.String())
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"slack-pr-lambda/notifier"
+	"slack-pr-lambda/types"
+
+	"go.uber.org/zap"
+)
+
+var stream = notifier.Stream
+
+// streams the processed webhook to FIREHOSE_STREAM for the data warehouse
+// before the delivery ends
+func streamEvent(w *failureWriter, githubEvent string, eventId string, event types.WebhookEvent, zapLog *zap.Logger) {
+	sendProcessed("stream", notifier.FirehoseStream() != "", w, githubEvent, eventId, event, func(forwarded types.ForwardedEvent) error {
+		return stream(forwarded)
+	}, zapLog)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func stubStream(t *testing.T) *[]types.ForwardedEvent {
+	streamed := []types.ForwardedEvent{}
+	original := stream
+	stream = func(events ...types.ForwardedEvent) error {
+		streamed = append(streamed, events...)
+		return nil
+	}
+	t.Cleanup(func() {
+		stream = original
+	})
+	return &streamed
+}
+
+func TestStreamEvent(t *testing.T) {
+	event := types.WebhookEvent{
+		Action:     "synchronize",
+		Number:     7,
+		Repository: &github.Repository{Name: github.String("api")},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		streamed := stubStream(t)
+		streamEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request", "delivery", event, zap.NewNop())
+		assert.Empty(t, *streamed)
+	})
+
+	t.Setenv("FIREHOSE_STREAM", "pulls")
+
+	t.Run("streamed", func(t *testing.T) {
+		streamed := stubStream(t)
+		streamEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request", "delivery", event, zap.NewNop())
+		if assert.Len(t, *streamed, 1) {
+			assert.Equal(t, "synchronize", (*streamed)[0].Action)
+			assert.Equal(t, 7, (*streamed)[0].Number)
+		}
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		streamed := stubStream(t)
+		streamEvent(&failureWriter{ResponseWriter: httptest.NewRecorder(), status: 500}, "pull_request", "delivery", event, zap.NewNop())
+		assert.Empty(t, *streamed)
+	})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slack-pr-lambda/api/mail"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/types"
+	"syscall"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// feature flag of the celebration, enabled once the contributors of the
+// repository are seeded so its regular authors aren't celebrated
+const firstContributorFeature = "first_contributor"
+
+var hasAuthored = db.HasAuthored
+
+var recordContributor = db.RecordContributor
+
+var getPullRequestAuthors = github.GetPullRequestAuthors
+
+// the author has no other pull request of the repository in the contributors,
+// bots are never first-time contributors. A failed lookup is not celebrated.
+// New authors are recorded whether the celebration is enabled or not
+func firstContribution(svc *awsdynamodb.DynamoDB, input types.OpenPullRequest, zapLog *zap.Logger) bool {
+	author := input.PullRequest.GetUser()
+	if author.GetLogin() == "" || author.GetType() == "Bot" {
+		return false
+	}
+
+	repository := input.Repository.GetName()
+	pullRequest := audit.PullRequestKey(repository, input.Number)
+	authored, err := hasAuthored(svc, repository, author.GetLogin(), pullRequest)
+	if err != nil {
+		zapLog.Warn("error lookup previous pull requests",
+			zap.Error(err),
+		)
+		return false
+	}
+	if authored {
+		return false
+	}
+
+	err = recordContributor(svc, &types.TableContributorData{
+		Repository:  repository,
+		Author:      author.GetLogin(),
+		PullRequest: pullRequest,
+		RecordedAt:  time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		zapLog.Warn("error record contributor",
+			zap.Error(err),
+		)
+	}
+	return featureEnabled(firstContributorFeature, repository, zapLog)
+}
+
+// records the authors of every pull request of the repository on GitHub, run
+// before enabling the first_contributor flag
+func AdminSeedContributorsHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	repository := r.PathValue("repository")
+	authors, err := getPullRequestAuthors(repository)
+	if err != nil {
+		zapLog.Error("error list pull request authors",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	svc := db.DynamoDbConnection()
+	recordedAt := time.Now().UTC().Format(time.RFC3339)
+	for author, number := range authors {
+		err := recordContributor(svc, &types.TableContributorData{
+			Repository:  repository,
+			Author:      author,
+			PullRequest: audit.PullRequestKey(repository, number),
+			RecordedAt:  recordedAt,
+		})
+		if err != nil {
+			zapLog.Error("error record contributor",
+				zap.Error(err),
+			)
+			writeError(w, err)
+			return
+		}
+	}
+
+	j, err := json.Marshal(map[string]interface{}{"repository": repository, "contributors": len(authors)})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(j)
+}
+
+// line added to the parent message of a first-time contributor
+func firstContributionLine(language string, login string, slackUsersMap map[string]interface{}) string {
+	return messages.In(language, "first_contribution", constants.Emoji().FirstContributor, mail.Mention(login, slackUsersMap, nil))
+}
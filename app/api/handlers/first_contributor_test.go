@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"testing"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+func stubContributors(t *testing.T) *[]types.TableContributorData {
+	recorded := []types.TableContributorData{}
+	originalAuthored, originalRecord, originalAuthors := hasAuthored, recordContributor, getPullRequestAuthors
+	recordContributor = func(svc *awsdynamodb.DynamoDB, item *types.TableContributorData) error {
+		recorded = append(recorded, *item)
+		return nil
+	}
+	t.Cleanup(func() {
+		hasAuthored, recordContributor, getPullRequestAuthors = originalAuthored, originalRecord, originalAuthors
+	})
+	return &recorded
+}
+
+func TestFirstContribution(t *testing.T) {
+	recorded := stubContributors(t)
+	t.Setenv("REPO_CONFIG", `{"features": {"first_contributor": {"repositories": ["api"]}}}`)
+
+	input := types.OpenPullRequest{
+		Number:      7,
+		Repository:  &gogithub.Repository{Name: gogithub.String("api")},
+		PullRequest: &gogithub.PullRequest{User: &gogithub.User{Login: gogithub.String("alice"), Type: gogithub.String("User")}},
+	}
+
+	hasAuthored = func(svc *awsdynamodb.DynamoDB, repository string, author string, pullRequest string) (bool, error) {
+		if repository != "api" || author != "alice" || pullRequest != "api#7" {
+			t.Errorf("Unexpected lookup %s %s %s", repository, author, pullRequest)
+		}
+		return false, nil
+	}
+	if !firstContribution(nil, input, zap.NewNop()) {
+		t.Errorf("Expected a first contribution")
+	}
+	if len(*recorded) != 1 || (*recorded)[0].Author != "alice" || (*recorded)[0].PullRequest != "api#7" {
+		t.Errorf("Expected alice to be recorded, got %v", *recorded)
+	}
+
+	// recorded but not celebrated before the repository is seeded
+	t.Setenv("REPO_CONFIG", `{"features": {"first_contributor": {"repositories": ["web"]}}}`)
+	if firstContribution(nil, input, zap.NewNop()) {
+		t.Errorf("Expected no celebration without the flag")
+	}
+	if len(*recorded) != 2 {
+		t.Errorf("Expected alice to be recorded without the flag, got %v", *recorded)
+	}
+	t.Setenv("REPO_CONFIG", `{"features": {"first_contributor": {"enabled": true}}}`)
+
+	hasAuthored = func(svc *awsdynamodb.DynamoDB, repository string, author string, pullRequest string) (bool, error) {
+		return true, nil
+	}
+	if firstContribution(nil, input, zap.NewNop()) || len(*recorded) != 2 {
+		t.Errorf("Expected a known contributor to be neither celebrated nor recorded, got %v", *recorded)
+	}
+
+	hasAuthored = func(svc *awsdynamodb.DynamoDB, repository string, author string, pullRequest string) (bool, error) {
+		return false, errors.New("ResourceNotFoundException")
+	}
+	if firstContribution(nil, input, zap.NewNop()) {
+		t.Errorf("Expected a failed lookup not to be celebrated")
+	}
+
+	hasAuthored = func(svc *awsdynamodb.DynamoDB, repository string, author string, pullRequest string) (bool, error) {
+		return false, nil
+	}
+	input.PullRequest.User.Type = gogithub.String("Bot")
+	if firstContribution(nil, input, zap.NewNop()) {
+		t.Errorf("Expected bots to be skipped")
+	}
+}
+
+func TestAdminSeedContributorsHandler(t *testing.T) {
+	recorded := stubContributors(t)
+	getPullRequestAuthors = func(repo string) (map[string]int, error) {
+		return map[string]int{"alice": 3, "bob": 12}, nil
+	}
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/contributors/{repository}/seed", AdminSeedContributorsHandler)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("POST", "/admin/contributors/api/seed", nil))
+	if rr.Code != http.StatusUnauthorized || len(*recorded) != 0 {
+		t.Errorf("Expected 401 without the token, got %v %v", rr.Code, *recorded)
+	}
+
+	req := httptest.NewRequest("POST", "/admin/contributors/api/seed", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK || rr.Body.String() != `{"contributors":2,"repository":"api"}` {
+		t.Errorf("Unexpected answer %v %s", rr.Code, rr.Body.String())
+	}
+	first := map[string]string{}
+	for _, item := range *recorded {
+		first[item.Author] = item.PullRequest
+	}
+	if first["alice"] != "api#3" || first["bob"] != "api#12" {
+		t.Errorf("Expected the first pull requests to be recorded, got %v", *recorded)
+	}
+}
+
+func TestFirstContributionLine(t *testing.T) {
+	result := firstContributionLine("en", "alice", map[string]interface{}{"alice": "UA"})
+	if result != ":tada: first PR from <@UA>!" {
+		t.Errorf("got %q", result)
+	}
+}
+
+func TestLocalizedParent(t *testing.T) {
+	input := types.OpenPullRequest{
+		Number:      7,
+		PullRequest: &gogithub.PullRequest{HTMLURL: gogithub.String("https://github.com/acme/api/pull/7"), User: &gogithub.User{Login: gogithub.String("alice")}},
+		Repository:  &gogithub.Repository{Name: gogithub.String("api")},
+		Sender:      &gogithub.User{Login: gogithub.String("alice")},
+	}
+	item := &types.TablePullRequestData{ParentMessage: "opened", RequiredApprovals: 2}
+
+	parent := localizedParent(input, "opened", item, true, map[string]interface{}{"alice": "UA"})("fr")
+	expected := "<@UA> :opened: a ouvert une nouvelle <https://github.com/acme/api/pull/7|pull request> dans `api`.\n:tada: première PR de <@UA> !\nApprobations : 0/2"
+	if parent != expected {
+		t.Errorf("got %q want %q", parent, expected)
+	}
+	if item.ParentMessage != "opened" {
+		t.Errorf("Expected the record to keep its text, got %q", item.ParentMessage)
+	}
+}
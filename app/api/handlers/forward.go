@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"slack-pr-lambda/notifier"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/types"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var forward = notifier.Forward
+
+var forwardUrls = notifier.ForwardUrls
+
+// hands the summary of a processed pull request webhook to a sink (forward,
+// publish, stream) once the delivery succeeded. Errors of the sink are only
+// logged so they never fail the delivery
+func sendProcessed(sink string, enabled bool, w *failureWriter, githubEvent string, eventId string, event types.WebhookEvent, send func(types.ForwardedEvent) error, zapLog *zap.Logger) {
+	if !enabled || w.status >= 400 || event.Repository.GetName() == "" || event.PullRequestNumber() == 0 {
+		return
+	}
+
+	if err := send(event.Forwarded(githubEvent, eventId, time.Now().UTC().Format(time.RFC3339Nano))); err != nil {
+		zapLog.Error("error "+sink+" event",
+			zap.Error(err),
+		)
+	}
+}
+
+// fan-out of the processed webhook to FORWARD_URLS, a failing url is retried
+// by the notifier
+func forwardEvent(w *failureWriter, githubEvent string, eventId string, event types.WebhookEvent, zapLog *zap.Logger) {
+	urls := forwardUrls()
+	sendProcessed("forward", len(urls) > 0, w, githubEvent, eventId, event, func(forwarded types.ForwardedEvent) error {
+		tasks := []func() error{}
+		for _, url := range urls {
+			tasks = append(tasks, func() error {
+				return forward(url, forwarded)
+			})
+		}
+		return pool.Run(pool.Size(), tasks)
+	}, zapLog)
+}
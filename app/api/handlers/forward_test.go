@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func stubForward(t *testing.T, urls []string, err error) *map[string]types.ForwardedEvent {
+	var mu sync.Mutex
+	forwarded := map[string]types.ForwardedEvent{}
+	originalForward, originalUrls := forward, forwardUrls
+	forward = func(url string, event types.ForwardedEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		forwarded[url] = event
+		return err
+	}
+	forwardUrls = func() []string { return urls }
+	t.Cleanup(func() {
+		forward, forwardUrls = originalForward, originalUrls
+	})
+	return &forwarded
+}
+
+func TestForwardEvent(t *testing.T) {
+	event := types.WebhookEvent{
+		Action:     "opened",
+		Number:     7,
+		Repository: &github.Repository{Name: github.String("api")},
+		Sender:     &github.User{Login: github.String("octocat")},
+	}
+	urls := []string{"https://a.example.com/hook", "https://b.example.com/hook"}
+
+	t.Run("no urls", func(t *testing.T) {
+		forwarded := stubForward(t, []string{}, nil)
+		forwardEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request", "delivery", event, zap.NewNop())
+		assert.Empty(t, *forwarded)
+	})
+
+	t.Run("forwarded", func(t *testing.T) {
+		forwarded := stubForward(t, urls, nil)
+		forwardEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request", "delivery", event, zap.NewNop())
+		if assert.Len(t, *forwarded, 2) {
+			item := (*forwarded)["https://b.example.com/hook"]
+			assert.Equal(t, "delivery", item.DeliveryId)
+			assert.Equal(t, "pull_request", item.Event)
+			assert.Equal(t, "api", item.Repository)
+			assert.Equal(t, 7, item.Number)
+			assert.NotEmpty(t, item.ReceivedAt)
+		}
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		forwarded := stubForward(t, urls, nil)
+		forwardEvent(&failureWriter{ResponseWriter: httptest.NewRecorder(), status: 500}, "pull_request", "delivery", event, zap.NewNop())
+		assert.Empty(t, *forwarded)
+	})
+
+	t.Run("no pull request", func(t *testing.T) {
+		forwarded := stubForward(t, urls, nil)
+		forwardEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "workflow_run", "delivery", types.WebhookEvent{Action: "completed", Repository: event.Repository}, zap.NewNop())
+		assert.Empty(t, *forwarded)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		forwarded := stubForward(t, urls, errors.New("timeout"))
+		forwardEvent(&failureWriter{ResponseWriter: httptest.NewRecorder()}, "pull_request", "delivery", event, zap.NewNop())
+		assert.Len(t, *forwarded, 2)
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slack-pr-lambda/slack"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+var tokenScopes = slack.SlackTokenScopes
+
+type Health struct {
+	Status        string   `json:"status"`
+	MissingScopes []string `json:"missingScopes,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// scopes the bot token is missing, logged as one error so a wrong install
+// shows up before the first send fails
+func SlackScopeCheck(zapLog *zap.Logger) Health {
+	scopes, err := tokenScopes()
+	if err != nil {
+		zapLog.Error("error slack auth test",
+			zap.Error(err),
+		)
+		return Health{Status: "error", Error: err.Error()}
+	}
+	if scopes == nil {
+		zapLog.Warn("slack token scopes not reported")
+		return Health{Status: "ok"}
+	}
+
+	if missing := slack.MissingScopes(scopes); len(missing) > 0 {
+		zapLog.Error("slack token missing scopes",
+			zap.Strings("missingScopes", missing),
+		)
+		return Health{Status: "missing_scopes", MissingScopes: missing}
+	}
+	return Health{Status: "ok"}
+}
+
+// 503 when the Slack token is rejected or misses scopes
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	health := SlackScopeCheck(zapLog)
+
+	j, err := json.Marshal(health)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if health.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(j)
+}
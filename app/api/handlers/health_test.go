@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/slack"
+	"strings"
+	"testing"
+)
+
+func stubTokenScopes(t *testing.T, scopes []string, err error) {
+	original := tokenScopes
+	tokenScopes = func() ([]string, error) {
+		return scopes, err
+	}
+	t.Cleanup(func() {
+		tokenScopes = original
+	})
+}
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		err    error
+		status int
+		body   string
+	}{
+		{"every scope", slack.RequiredScopes, nil, http.StatusOK, `{"status":"ok"}`},
+		{"not reported", nil, nil, http.StatusOK, `{"status":"ok"}`},
+		{"missing scopes", []string{"chat:write", "commands"}, nil, http.StatusServiceUnavailable, `"missingScopes":["channels:history","channels:join","channels:read","links:read","links:write","pins:write","reactions:read","reactions:write","users:read","users:read.email"]`},
+		{"rejected token", nil, errors.New("invalid_auth"), http.StatusServiceUnavailable, `{"status":"error","error":"invalid_auth"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubTokenScopes(t, tt.scopes, tt.err)
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(HealthHandler).ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+
+			if rr.Code != tt.status {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.status)
+			}
+			if !strings.Contains(rr.Body.String(), tt.body) {
+				t.Errorf("Expected %s in the body, got %s", tt.body, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// the repository threadImages, otherwise THREAD_IMAGES, images of a
+// description or comment shown in the thread. 0 turns them off
+func threadImageLimit(repository string) int {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		conf, _ = config.ParseConfig("")
+	}
+	if limit := conf.Repo(repository).ThreadImages; limit != nil && *limit >= 0 {
+		return *limit
+	}
+
+	limit, err := strconv.Atoi(env.GetEnv("THREAD_IMAGES", "3"))
+	if err != nil || limit < 0 {
+		return 3
+	}
+	return limit
+}
+
+// the first images of the body, up to the limit of the repository
+func threadImages(repository string, body string) []types.Image {
+	images := types.Images(body)
+	if limit := threadImageLimit(repository); len(images) > limit {
+		return images[:limit]
+	}
+	return images
+}
+
+// the images of a description or comment below a link to it, so UI changes
+// can be reviewed from the thread. Slack fetches them itself and refuses those
+// it can't, e.g. attachments of private repositories, failures are only logged
+func postImages(out audit.Messenger, timeStamp string, body string, url string, source string, zapLog *zap.Logger) {
+	images := threadImages(out.Repository, body)
+	if len(images) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Images of the <%s|%s>:", url, source)
+	if err := out.SendImagesThread(timeStamp, message, images); err != nil {
+		zapLog.Warn("error slack send images",
+			zap.String("url", url),
+			zap.Error(err),
+		)
+	}
+}
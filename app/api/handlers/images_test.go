@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"testing"
+)
+
+func TestThreadImages(t *testing.T) {
+	body := "![one](https://example.com/1.png) ![two](https://example.com/2.png)\n" +
+		"![three](https://example.com/3.png) ![four](https://example.com/4.png)"
+
+	if images := threadImages("api", body); len(images) != 3 || images[2].Url != "https://example.com/3.png" {
+		t.Errorf("Expected the first 3 images, got %v", images)
+	}
+
+	t.Setenv("THREAD_IMAGES", "1")
+	if images := threadImages("api", body); len(images) != 1 || images[0].Alt != "one" {
+		t.Errorf("Expected the first image, got %v", images)
+	}
+
+	t.Setenv("THREAD_IMAGES", "0")
+	if images := threadImages("api", body); len(images) != 0 {
+		t.Errorf("Expected no images, got %v", images)
+	}
+
+	t.Setenv("REPO_CONFIG", `{"default": {"threadImages": 2}, "repositories": {"web": {"threadImages": null}}}`)
+	if images := threadImages("api", body); len(images) != 2 {
+		t.Errorf("Expected the 2 images of the config, got %v", images)
+	}
+	if images := threadImages("web", body); len(images) != 0 {
+		t.Errorf("Expected THREAD_IMAGES once reset, got %v", images)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"slack-pr-lambda/logger"
 	"syscall"
 )
 
@@ -14,8 +13,10 @@ type Response struct {
 }
 
 func IndexRequestHandler(w http.ResponseWriter, r *http.Request) {
-	l := logger.LoggerConfig()
-	zapLog, _ := l.Build()
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
 
 	defer func() {
 		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slack-pr-lambda/api/jobs"
+	"slack-pr-lambda/env"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// run a scheduled job on demand, deployed jobs are triggered by EventBridge
+// so this is only available locally
+func JobHandler(w http.ResponseWriter, r *http.Request) {
+	env := env.GetEnv("ENV", "local")
+
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if env != "local" {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	name := r.PathValue("name")
+	if err := jobs.Run(name); err != nil {
+		zapLog.Error("error run job",
+			zap.String("job", name),
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	bodyBytes := Response{
+		Message: "Job done.",
+	}
+
+	j, err := json.Marshal(bodyBytes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(j)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJobHandler(t *testing.T) {
+	t.Run("unknown job", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /jobs/{name}", JobHandler)
+
+		req, err := http.NewRequest("POST", "/jobs/unknown", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusInternalServerError {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("not local", func(t *testing.T) {
+		t.Setenv("ENV", "stage")
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /jobs/{name}", JobHandler)
+
+		req, err := http.NewRequest("POST", "/jobs/reminders", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNotFound {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusNotFound)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"slack-pr-lambda/api/dashboard"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/types"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+var requestedReviewers = dashboard.RequestedReviewers
+
+// cells of the heat bar, one per open review request or authored pull request
+const loadBarMax = 10
+
+type memberLoad struct {
+	Login    string
+	Reviews  int
+	Authored int
+}
+
+// /pr-load [repository], open review requests and authored open pull requests
+// of every team member, busiest first
+func loadCommand(text string, zapLog *zap.Logger) (string, error) {
+	repository := strings.TrimSpace(text)
+
+	items, err := db.ListPullRequests(db.DynamoDbConnection())
+	if err != nil {
+		return "", err
+	}
+
+	open := []types.TablePullRequestData{}
+	for _, item := range items {
+		if item.Repository != "" && (repository == "" || item.Repository == repository) {
+			open = append(open, item)
+		}
+	}
+
+	members := []string{}
+	for login := range mapstruct.StructToMap(*constants.SlackUsers()) {
+		members = append(members, login)
+	}
+
+	return loadTable(reviewLoad(open, requestedReviewers(open, repository, zapLog), members)), nil
+}
+
+// load of the members and of anyone else requested or authoring, logins
+// compare case-insensitively like on GitHub
+func reviewLoad(items []types.TablePullRequestData, requested map[string][]string, members []string) []memberLoad {
+	loads := map[string]*memberLoad{}
+	load := func(login string) *memberLoad {
+		key := strings.ToLower(login)
+		if loads[key] == nil {
+			loads[key] = &memberLoad{Login: login}
+		}
+		return loads[key]
+	}
+
+	for _, member := range members {
+		load(member)
+	}
+	for _, item := range items {
+		if item.Author != "" {
+			load(item.Author).Authored++
+		}
+		for _, login := range requested[item.ID] {
+			load(login).Reviews++
+		}
+	}
+
+	result := []memberLoad{}
+	for _, entry := range loads {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Reviews+result[i].Authored != result[j].Reviews+result[j].Authored {
+			return result[i].Reviews+result[i].Authored > result[j].Reviews+result[j].Authored
+		}
+		if result[i].Reviews != result[j].Reviews {
+			return result[i].Reviews > result[j].Reviews
+		}
+		return strings.ToLower(result[i].Login) < strings.ToLower(result[j].Login)
+	})
+	return result
+}
+
+// fixed-width table in a code block, the bar fills with the reviews then the
+// authored pull requests
+func loadTable(loads []memberLoad) string {
+	width := len("Member")
+	for _, entry := range loads {
+		width = max(width, len(entry.Login))
+	}
+
+	lines := []string{fmt.Sprintf("%-*s  Reviews  Authored", width, "Member")}
+	for _, entry := range loads {
+		reviews := min(entry.Reviews, loadBarMax)
+		authored := min(entry.Authored, loadBarMax-reviews)
+		bar := strings.Repeat("█", reviews) + strings.Repeat("▒", authored) + strings.Repeat("·", loadBarMax-reviews-authored)
+		lines = append(lines, fmt.Sprintf("%-*s  %7d  %8d  %s", width, entry.Login, entry.Reviews, entry.Authored, bar))
+	}
+
+	return fmt.Sprintf("*Review load* (█ review requests, ▒ authored)\n```\n%s\n```", strings.Join(lines, "\n"))
+}
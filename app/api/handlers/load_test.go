@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewLoad(t *testing.T) {
+	items := []types.TablePullRequestData{
+		{ID: "1", Author: "alice"},
+		{ID: "2", Author: "bob"},
+		{ID: "3", Author: "Alice"},
+	}
+	requested := map[string][]string{
+		"1": {"bob", "carol"},
+		"2": {"carol"},
+	}
+
+	loads := reviewLoad(items, requested, []string{"alice", "bob", "carol", "dave"})
+	assert.Equal(t, []memberLoad{
+		{Login: "carol", Reviews: 2},
+		{Login: "bob", Reviews: 1, Authored: 1},
+		{Login: "alice", Authored: 2},
+		{Login: "dave"},
+	}, loads)
+}
+
+func TestLoadTable(t *testing.T) {
+	table := loadTable([]memberLoad{
+		{Login: "carol", Reviews: 12},
+		{Login: "bob", Reviews: 1, Authored: 1},
+		{Login: "dave"},
+	})
+
+	expected := "*Review load* (█ review requests, ▒ authored)\n```\n" +
+		"Member  Reviews  Authored\n" +
+		"carol        12         0  ██████████\n" +
+		"bob           1         1  █▒········\n" +
+		"dave          0         0  ··········\n" +
+		"```"
+	assert.Equal(t, expected, table)
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"slack-pr-lambda/logger"
+
+	"go.uber.org/zap"
+)
+
+// shared logger with the request id, answers 500 when it can't be built
+func requestLogger(w http.ResponseWriter, r *http.Request) (*zap.Logger, bool) {
+	zapLog, err := logger.New(r.Context())
+	if err != nil {
+		log.Printf("error building the logger. %v\n", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return zapLog, true
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/metrics"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+var invocationAllocations = metrics.NewHistogram("invocation_allocated_bytes", "Bytes allocated per invocation, with ALLOC_LOGGING.",
+	[]float64{1 << 16, 1 << 18, 1 << 20, 1 << 22, 1 << 24, 1 << 26}, "invocation")
+
+// replaced in tests
+var memoryLogger = logger.New
+
+// ALLOC_LOGGING=true logs the allocations of every invocation, off by default
+// since runtime.ReadMemStats stops the world twice per invocation
+func allocLogging() bool {
+	return env.GetEnv("ALLOC_LOGGING", "false") == "true"
+}
+
+// starts measuring an invocation, the returned func logs its allocations as
+// "invocation memory". Lambda serves one invocation at a time per instance,
+// the deltas of concurrent local requests overlap
+//
+//	defer handlers.TrackMemory(ctx, "job:reminder")()
+func TrackMemory(ctx context.Context, invocation string) func() {
+	if !allocLogging() {
+		return func() {}
+	}
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	return func() {
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+
+		allocated := after.TotalAlloc - before.TotalAlloc
+		invocationAllocations.Observe(float64(allocated), invocation)
+
+		zapLog, err := memoryLogger(ctx)
+		if err != nil {
+			return
+		}
+		zapLog.Info("invocation memory", memoryFields(invocation, &before, &after)...)
+	}
+}
+
+// sysBytes is the memory held from the OS, what the lambda memory setting
+// has to cover, next to memoryLimitMb when served by lambda
+func memoryFields(invocation string, before *runtime.MemStats, after *runtime.MemStats) []zap.Field {
+	fields := []zap.Field{
+		zap.String("invocation", invocation),
+		zap.Uint64("allocatedBytes", after.TotalAlloc-before.TotalAlloc),
+		zap.Uint64("allocations", after.Mallocs-before.Mallocs),
+		zap.Uint32("gcCycles", after.NumGC-before.NumGC),
+		zap.Uint64("heapInuseBytes", after.HeapInuse),
+		zap.Uint64("sysBytes", after.Sys),
+	}
+	if limit, err := strconv.Atoi(env.GetEnv("AWS_LAMBDA_FUNCTION_MEMORY_SIZE", "")); err == nil {
+		fields = append(fields, zap.Int("memoryLimitMb", limit))
+	}
+	return fields
+}
+
+// TrackMemory around a route
+func MemoryTracked(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer TrackMemory(r.Context(), route)()
+		next(w, r)
+	}
+}
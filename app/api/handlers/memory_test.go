@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observeMemory(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zap.InfoLevel)
+	original := memoryLogger
+	memoryLogger = func(ctx context.Context) (*zap.Logger, error) {
+		return zap.New(core), nil
+	}
+	t.Cleanup(func() {
+		memoryLogger = original
+	})
+	return logs
+}
+
+var sink [][]byte
+
+func TestTrackMemory(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("ALLOC_LOGGING", "")
+		logs := observeMemory(t)
+
+		TrackMemory(context.Background(), "job:reminder")()
+		assert.Equal(t, 0, logs.Len())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv("ALLOC_LOGGING", "true")
+		t.Setenv("AWS_LAMBDA_FUNCTION_MEMORY_SIZE", "256")
+		logs := observeMemory(t)
+
+		done := TrackMemory(context.Background(), "job:reminder")
+		for i := 0; i < 16; i++ {
+			sink = append(sink, make([]byte, 1<<16))
+		}
+		done()
+		sink = nil
+
+		entries := logs.FilterMessage("invocation memory").All()
+		assert.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "job:reminder", fields["invocation"])
+		assert.GreaterOrEqual(t, fields["allocatedBytes"], uint64(16<<16))
+		assert.Greater(t, fields["allocations"], uint64(0))
+		assert.Equal(t, int64(256), fields["memoryLimitMb"])
+		assert.Equal(t, uint64(1), invocationAllocations.Count("job:reminder"))
+	})
+}
+
+func TestMemoryTracked(t *testing.T) {
+	t.Setenv("ALLOC_LOGGING", "true")
+	logs := observeMemory(t)
+
+	handler := MemoryTracked("GET /prs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest("GET", "/prs", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, 1, logs.FilterField(zap.String("invocation", "GET /prs")).Len())
+}
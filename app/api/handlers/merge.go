@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// posted in the thread once the approval quorum is met
+func mergeReadyMessage(item *types.TablePullRequestData) (string, []slack.SlackButton, error) {
+	emoji := constants.Emoji()
+
+	value, err := json.Marshal(types.PullRequestActionValue{
+		ID:            item.ID,
+		PullRequestId: item.PullRequestId,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	buttons := []slack.SlackButton{
+		{ActionId: "pr_merge", Text: "Merge", Value: string(value)},
+		{ActionId: "pr_status", Text: "Status", Value: string(value)},
+	}
+
+	return fmt.Sprintf("Approval quorum met %s", emoji.Approved), buttons, nil
+}
+
+// merge button, only linked users with write access to the repository can
+// merge, the outcome is reported in the thread
+func mergeAction(slackUserId string, value string, zapLog *zap.Logger) (string, error) {
+	emoji := constants.Emoji()
+
+	login := githubLogin(slackUserId)
+	if login == "" {
+		return "Your Slack account is not linked to a GitHub user.", nil
+	}
+
+	var input types.PullRequestActionValue
+	if err := json.Unmarshal([]byte(value), &input); err != nil {
+		return "", err
+	}
+
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return "", err
+	}
+
+	svc := db.DynamoDbConnection()
+	item, err := db.GetPullRequest(svc, id, input.PullRequestId)
+	if errors.Is(err, db.ErrNoDataFound) {
+		return "This pull request is no longer tracked.", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	canWrite, err := github.HasWriteAccess(item.Repository, login)
+	if err != nil {
+		return "", err
+	}
+	if !canWrite {
+		return fmt.Sprintf("You need write access to `%s` to merge.", item.Repository), nil
+	}
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	method := conf.Repo(item.Repository).GetMergeMethod()
+
+	message := func(language string) string {
+		return messages.In(language, "merge.slack", slackUserId, method, emoji.Merged)
+	}
+	result := "Merged."
+	if _, err := github.MergePullRequest(item.Repository, item.PullRequestId, method); err != nil {
+		zapLog.Warn("error merge pull request",
+			zap.String("repository", item.Repository),
+			zap.Int("number", item.PullRequestId),
+			zap.Error(err),
+		)
+		message = func(language string) string {
+			return messages.In(language, "merge.failed", slackUserId, emoji.CheckFailed, err.Error())
+		}
+		result = "Merge failed, see the thread for details."
+	}
+
+	out := audit.Messenger{
+		Source:     "pr_merge",
+		Repository: item.Repository,
+		Number:     item.PullRequestId,
+		Log:        zapLog,
+	}
+	out.Resend = parentResender(out, zapLog)
+	if err := out.Localized(message).SendMessageThread(item.SlackTimeStamp, message(messages.Language())); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
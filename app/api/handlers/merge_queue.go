@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/types"
+	"strings"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+var getMergeQueuePosition = github.GetMergeQueuePosition
+
+// thread of a tracked pull request found by its number, merge_group
+// deliveries carry no pull request id
+var mergeGroupThread = func(svc *awsdynamodb.DynamoDB, repository string, number int) (string, error) {
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return "", err
+	}
+	for _, item := range items {
+		if item.Repository == repository && item.PullRequestId == number {
+			return item.SlackTimeStamp, nil
+		}
+	}
+	return "", nil
+}
+
+// pull requests entering the merge queue post their position in the thread,
+// leaving it posts the reason and the checks of their merge group post once
+// they start. Merged ones are announced by the closed action
+func mergeQueueEvent(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent, slackUsersMap map[string]interface{}, zapLog *zap.Logger) error {
+	if event.Action == "dequeued" && event.Reason == "MERGE" {
+		out.Trail.Skip("merged from the merge queue")
+		return nil
+	}
+
+	var timeStamp string
+	var err error
+	if event.MergeGroup != nil {
+		if event.MergeGroup.Number() == 0 {
+			out.Trail.Skip("merge group without pull request")
+			return nil
+		}
+		timeStamp, err = mergeGroupThread(svc, out.Repository, event.MergeGroup.Number())
+	} else {
+		timeStamp, err = db.GetSlackTimeStamp(svc, int(event.PullRequest.GetID()), event.PullRequest.GetNumber())
+	}
+	if err != nil && !errors.Is(err, db.ErrNoDataFound) {
+		return err
+	}
+	if !tracked(out.Trail, timeStamp) {
+		return nil
+	}
+
+	position := 0
+	if event.Action == "enqueued" {
+		// the enqueued delivery carries no position
+		if position, err = getMergeQueuePosition(out.Repository, event.PullRequest.GetNumber()); err != nil {
+			zapLog.Warn("error get merge queue position",
+				zap.Error(err),
+			)
+		}
+	}
+
+	message := func(language string) string {
+		return mergeQueueMessage(language, event, position, slackUsersMap)
+	}
+	return out.Localized(message).SendMessageThread(timeStamp, message(messages.Language()))
+}
+
+func mergeQueueMessage(language string, event types.WebhookEvent, position int, slackUsersMap map[string]interface{}) string {
+	emoji := constants.Emoji()
+
+	switch event.Action {
+	case "enqueued":
+		user := slackUsersMap[event.Sender.GetLogin()]
+		if position > 0 {
+			return messages.In(language, "merge_queue.position", emoji.MergeQueued, user, position)
+		}
+		return messages.In(language, "merge_queue.enqueued", emoji.MergeQueued, user)
+	case "dequeued":
+		return messages.In(language, "merge_queue.dequeued", emoji.MergeDequeued, dequeueReason(language, event.Reason))
+	default:
+		return messages.In(language, "merge_queue.checks", emoji.MergeQueued)
+	}
+}
+
+// readable reason of a dequeued pull request, e.g. CI_FAILURE is "CI failed"
+func dequeueReason(language string, reason string) string {
+	if reason == "" {
+		reason = "unknown"
+	}
+	key := "merge_queue.reason." + strings.ToLower(reason)
+	if text := messages.In(language, key); text != key {
+		return text
+	}
+	return strings.ToLower(strings.ReplaceAll(reason, "_", " "))
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	gogithub "github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestMergeQueueEventSkipped(t *testing.T) {
+	original := mergeGroupThread
+	mergeGroupThread = func(svc *awsdynamodb.DynamoDB, repository string, number int) (string, error) {
+		return "", nil
+	}
+	t.Cleanup(func() {
+		mergeGroupThread = original
+	})
+
+	tests := []struct {
+		name    string
+		event   types.WebhookEvent
+		skipped string
+	}{
+		{
+			name:    "merged",
+			event:   types.WebhookEvent{Action: "dequeued", Reason: "MERGE"},
+			skipped: "merged from the merge queue",
+		},
+		{
+			name:    "merge group of another branch",
+			event:   types.WebhookEvent{Action: "checks_requested", MergeGroup: &types.MergeGroup{HeadRef: "refs/heads/main"}},
+			skipped: "merge group without pull request",
+		},
+		{
+			name:    "untracked merge group",
+			event:   types.WebhookEvent{Action: "checks_requested", MergeGroup: &types.MergeGroup{HeadRef: "refs/heads/gh-readonly-queue/main/pr-7-f95f852"}},
+			skipped: "pull request not tracked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trail := &audit.Trail{}
+			out := audit.Messenger{Repository: "api", Log: zap.NewNop(), Trail: trail}
+
+			if err := mergeQueueEvent(nil, out, tt.event, map[string]interface{}{}, zap.NewNop()); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, []string{tt.skipped}, trail.Skipped())
+		})
+	}
+}
+
+func TestMergeQueueMessage(t *testing.T) {
+	slackUsersMap := map[string]interface{}{"octocat": "U1"}
+	sender := &gogithub.User{Login: gogithub.String("octocat")}
+
+	tests := []struct {
+		name     string
+		event    types.WebhookEvent
+		position int
+		expected string
+	}{
+		{
+			name:     "enqueued",
+			event:    types.WebhookEvent{Action: "enqueued", Sender: sender},
+			position: 2,
+			expected: ":train: <@U1> added the pull request to the merge queue, position 2.",
+		},
+		{
+			name:     "enqueued without position",
+			event:    types.WebhookEvent{Action: "enqueued", Sender: sender},
+			expected: ":train: <@U1> added the pull request to the merge queue.",
+		},
+		{
+			name:     "ci failure",
+			event:    types.WebhookEvent{Action: "dequeued", Reason: "CI_FAILURE"},
+			expected: ":no_entry: Removed from the merge queue: CI failed.",
+		},
+		{
+			name:     "new reason",
+			event:    types.WebhookEvent{Action: "dequeued", Reason: "SOME_NEW_REASON"},
+			expected: ":no_entry: Removed from the merge queue: some new reason.",
+		},
+		{
+			name:     "checks",
+			event:    types.WebhookEvent{Action: "checks_requested"},
+			expected: ":train: Merge queue checks started.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeQueueMessage("en", tt.event, tt.position, slackUsersMap))
+		})
+	}
+}
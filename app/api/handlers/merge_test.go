@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMergeReadyMessage(t *testing.T) {
+	message, buttons, err := mergeReadyMessage(&types.TablePullRequestData{ID: "123", PullRequestId: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message != "Approval quorum met :approved:" {
+		t.Errorf("unexpected message %q", message)
+	}
+	if len(buttons) != 2 || buttons[0].ActionId != "pr_merge" {
+		t.Fatalf("Expected merge and status buttons, got %+v", buttons)
+	}
+
+	var value types.PullRequestActionValue
+	if err := json.Unmarshal([]byte(buttons[0].Value), &value); err != nil {
+		t.Fatal(err)
+	}
+	if value.ID != "123" || value.PullRequestId != 7 {
+		t.Errorf("Unexpected button value %+v", value)
+	}
+}
+
+func TestMergeAction(t *testing.T) {
+	t.Run("not linked", func(t *testing.T) {
+		text, err := mergeAction("UNKNOWN", `{"id":"1","pullRequestId":1}`, zap.NewNop())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != "Your Slack account is not linked to a GitHub user." {
+			t.Errorf("unexpected response %q", text)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		if _, err := mergeAction("U06Q5GKADME", "invalid", zap.NewNop()); err == nil {
+			t.Errorf("Expected error for invalid button value")
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"net/http"
+	"slack-pr-lambda/metrics"
+)
+
+var (
+	webhookEvents   = metrics.NewCounter("webhook_events_total", "GitHub webhook events received by action.", "action")
+	droppedEvents   = metrics.NewCounter("webhook_events_dropped_total", "GitHub webhook events ignored by the repository allowlist.", "action")
+	unhandledEvents = metrics.NewCounter("webhook_events_unhandled_total", "GitHub webhook events with an action no handler acts on.", "action")
+	payloadDrift    = metrics.NewCounter("webhook_payload_drift_total", "Missing or unknown fields of GitHub webhook payloads, with STRICT_DECODING.", "action")
+	webhookPanics   = metrics.NewCounter("webhook_panics_total", "GitHub webhook actions recovered from a panic.", "action")
+	metricsHandler  = metrics.Handler()
+)
+
+// Prometheus text format, the counters are per lambda instance
+func MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	metricsHandler.ServeHTTP(w, r)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"strconv"
+	"strings"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+const muteUsage = "Usage: `/pr-mute <pull request url | repository#number | number> [--channel] [off]`."
+
+type muteArgs struct {
+	// "<repository> <number>", "<repository>#<number>", a pull request url or a number
+	Ref     string
+	Channel bool
+	Off     bool
+}
+
+func parseMuteArgs(text string) (muteArgs, error) {
+	args := muteArgs{}
+	ref := []string{}
+	for _, field := range strings.Fields(text) {
+		switch strings.ToLower(field) {
+		case "--channel":
+			args.Channel = true
+		case "off":
+			args.Off = true
+		default:
+			ref = append(ref, field)
+		}
+	}
+
+	if len(ref) == 0 {
+		return args, errors.New(muteUsage)
+	}
+	args.Ref = strings.Join(ref, " ")
+	return args, nil
+}
+
+// repository of the tracked pull request with that number, an error message
+// when none or several repositories match
+func trackedRepository(items []types.TablePullRequestData, number int) (string, error) {
+	repositories := []string{}
+	for _, item := range items {
+		if item.PullRequestId == number && item.Repository != "" {
+			repositories = append(repositories, item.Repository)
+		}
+	}
+
+	switch len(repositories) {
+	case 0:
+		return "", fmt.Errorf("No tracked pull request #%d.", number)
+	case 1:
+		return repositories[0], nil
+	}
+	return "", fmt.Errorf("Several repositories have a pull request #%d, use `<repository>#%d`.", number, number)
+}
+
+// repository and number of a pull request reference, a bare number is looked
+// up in the tracked pull requests. reply is set when it can't be resolved
+func resolvePullRequest(svc *awsdynamodb.DynamoDB, ref string, usage string) (string, int, string, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		repo, number, err := parseStatusArgs(ref)
+		if err != nil {
+			return "", 0, usage, nil
+		}
+		return repo, number, "", nil
+	}
+
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return "", 0, "", err
+	}
+	repo, err := trackedRepository(items, number)
+	if err != nil {
+		return "", 0, err.Error(), nil
+	}
+	return repo, number, "", nil
+}
+
+// /pr-mute slash command, mutes the thread notifications of a pull request for
+// the user or with --channel for everyone
+func muteCommand(slackUserId string, text string) (string, error) {
+	args, err := parseMuteArgs(text)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	svc := db.DynamoDbConnection()
+	repo, number, reply, err := resolvePullRequest(svc, args.Ref, muteUsage)
+	if err != nil || reply != "" {
+		return reply, err
+	}
+
+	target := slackUserId
+	scope := "you"
+	if args.Channel {
+		target = db.MuteChannel
+		scope = "the channel"
+	}
+
+	key := audit.PullRequestKey(repo, number)
+	if args.Off {
+		if err := db.DeleteMute(svc, key, target); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Thread notifications of %s are back on for %s.", key, scope), nil
+	}
+
+	item := &types.TableMuteData{
+		PullRequest: key,
+		SlackUserId: target,
+		MutedAt:     time.Now().Format(time.RFC3339),
+	}
+	if err := db.InsertMute(svc, item); err != nil {
+		return "", err
+	}
+
+	if args.Channel {
+		return fmt.Sprintf("Thread notifications of %s are muted for the channel, `/pr-mute %s --channel off` turns them back on.", key, key), nil
+	}
+	return fmt.Sprintf("You will no longer be mentioned in the thread of %s, `/pr-mute %s off` turns it back on.", key, key), nil
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+)
+
+func TestParseMuteArgs(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected muteArgs
+		valid    bool
+	}{
+		{"api#7", muteArgs{Ref: "api#7"}, true},
+		{"api 7 --channel", muteArgs{Ref: "api 7", Channel: true}, true},
+		{"https://github.com/rodentskie/api/pull/7 off", muteArgs{Ref: "https://github.com/rodentskie/api/pull/7", Off: true}, true},
+		{"#7 --channel off", muteArgs{Ref: "#7", Channel: true, Off: true}, true},
+		{"--channel", muteArgs{}, false},
+		{"", muteArgs{}, false},
+	}
+
+	for _, tt := range tests {
+		args, err := parseMuteArgs(tt.text)
+		if (err == nil) != tt.valid {
+			t.Errorf("%q: expected valid %v, got %v", tt.text, tt.valid, err)
+			continue
+		}
+		if tt.valid && args != tt.expected {
+			t.Errorf("%q: got %+v want %+v", tt.text, args, tt.expected)
+		}
+	}
+}
+
+func TestTrackedRepository(t *testing.T) {
+	items := []types.TablePullRequestData{
+		{PullRequestId: 7, Repository: "api"},
+		{PullRequestId: 8, Repository: "api"},
+		{PullRequestId: 8, Repository: "web"},
+		{PullRequestId: 9},
+	}
+
+	if repo, err := trackedRepository(items, 7); err != nil || repo != "api" {
+		t.Errorf("Expected api, got %q %v", repo, err)
+	}
+	if _, err := trackedRepository(items, 8); err == nil || err.Error() != "Several repositories have a pull request #8, use `<repository>#8`." {
+		t.Errorf("Expected an ambiguous number, got %v", err)
+	}
+	if _, err := trackedRepository(items, 9); err == nil || err.Error() != "No tracked pull request #9." {
+		t.Errorf("Expected no tracked pull request, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/reviewers"
+	"slack-pr-lambda/types"
+	"strings"
+	"time"
+)
+
+const outOfOfficeUsage = "Usage: `/pr-ooo <start YYYY-MM-DD> [end YYYY-MM-DD]`, `/pr-ooo status` or `/pr-ooo off`."
+
+// github login linked to the slack user id, empty when not linked
+func githubLogin(slackUserId string) string {
+	slackUsersMap := mapstruct.StructToMap(*constants.SlackUsers())
+	for login, id := range slackUsersMap {
+		if id == slackUserId {
+			return login
+		}
+	}
+	return ""
+}
+
+// parse "<start> [end]", a single date marks only that day
+func parseOutOfOfficeRange(text string, now time.Time) (string, string, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", "", errors.New(outOfOfficeUsage)
+	}
+
+	start, err := time.Parse(reviewers.DateLayout, fields[0])
+	if err != nil {
+		return "", "", errors.New(outOfOfficeUsage)
+	}
+	end := start
+	if len(fields) == 2 {
+		end, err = time.Parse(reviewers.DateLayout, fields[1])
+		if err != nil {
+			return "", "", errors.New(outOfOfficeUsage)
+		}
+	}
+
+	if end.Before(start) {
+		return "", "", errors.New("End date must not be before the start date.")
+	}
+
+	today, _ := time.Parse(reviewers.DateLayout, now.UTC().Format(reviewers.DateLayout))
+	if end.Before(today) {
+		return "", "", errors.New("End date is already in the past.")
+	}
+
+	return start.Format(reviewers.DateLayout), end.Format(reviewers.DateLayout), nil
+}
+
+// /pr-ooo slash command, the returned text is shown only to the user
+func outOfOfficeCommand(slackUserId string, text string) (string, error) {
+	login := githubLogin(slackUserId)
+	if login == "" {
+		return "Your Slack account is not linked to a GitHub user.", nil
+	}
+
+	text = strings.TrimSpace(text)
+	svc := db.DynamoDbConnection()
+
+	switch strings.ToLower(text) {
+	case "", "status":
+		item, err := db.GetOutOfOffice(svc, slackUserId)
+		if errors.Is(err, db.ErrNoDataFound) {
+			return "You are not marked out of office.", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if reviewers.IsOutOfOffice(*item, time.Now()) {
+			return fmt.Sprintf("You are out of office until %s.", item.EndDate), nil
+		}
+		return fmt.Sprintf("Your out of office is set from %s to %s.", item.StartDate, item.EndDate), nil
+
+	case "off", "clear":
+		if err := db.DeleteOutOfOffice(svc, slackUserId); err != nil {
+			return "", err
+		}
+		return "Welcome back! You are no longer marked out of office.", nil
+	}
+
+	start, end, err := parseOutOfOfficeRange(text, time.Now())
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	item := &types.TableOutOfOfficeData{
+		SlackUserId: slackUserId,
+		GithubLogin: login,
+		StartDate:   start,
+		EndDate:     end,
+	}
+	if err := db.InsertOutOfOffice(svc, item); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("You are marked out of office from %s to %s, review pings will skip you.", start, end), nil
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGithubLogin(t *testing.T) {
+	if login := githubLogin("U06Q5GKADME"); login != "rodentskie" {
+		t.Errorf("got %q want %q", login, "rodentskie")
+	}
+	if login := githubLogin("UNKNOWN"); login != "" {
+		t.Errorf("got %q want empty login", login)
+	}
+}
+
+func TestParseOutOfOfficeRange(t *testing.T) {
+	now, _ := time.Parse("2006-01-02", "2024-03-11")
+
+	data := []struct {
+		text  string
+		start string
+		end   string
+		err   bool
+	}{
+		{"2024-03-12", "2024-03-12", "2024-03-12", false},
+		{"2024-03-10 2024-03-15", "2024-03-10", "2024-03-15", false},
+		{"2024-03-15 2024-03-10", "", "", true},
+		{"2024-03-01 2024-03-05", "", "", true},
+		{"tomorrow", "", "", true},
+		{"2024-03-10 2024-03-15 2024-03-20", "", "", true},
+	}
+
+	for _, e := range data {
+		start, end, err := parseOutOfOfficeRange(e.text, now)
+		if (err != nil) != e.err {
+			t.Errorf("%q: unexpected error %v", e.text, err)
+		}
+		if start != e.start || end != e.end {
+			t.Errorf("%q: got %s - %s want %s - %s", e.text, start, end, e.start, e.end)
+		}
+	}
+}
+
+func TestOutOfOfficeCommandNotLinked(t *testing.T) {
+	text, err := outOfOfficeCommand("UNKNOWN", "off")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Your Slack account is not linked to a GitHub user." {
+		t.Errorf("unexpected response %q", text)
+	}
+}
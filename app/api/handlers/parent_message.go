@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"strconv"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// approvals needed for the pull request, the repository config wins over the
+// branch protection rule, DEFAULT_REQUIRED_APPROVALS when neither is set
+func requiredApprovals(repo string, branch string, zapLog *zap.Logger) int {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+	} else if required := conf.Repo(repo).RequiredApprovals; required > 0 {
+		return required
+	}
+
+	required, err := github.GetRequiredApprovals(repo, branch)
+	if err != nil {
+		zapLog.Warn("error get branch protection",
+			zap.String("repository", repo),
+			zap.String("branch", branch),
+			zap.Error(err),
+		)
+	} else if required > 0 {
+		return required
+	}
+
+	required, err = strconv.Atoi(env.GetEnv("DEFAULT_REQUIRED_APPROVALS", "1"))
+	if err != nil {
+		return 1
+	}
+	return required
+}
+
+// recount the approvals from GitHub and re-render the parent message, the
+// merge button is posted when the quorum is met, with the ready to merge ping
+// once the required checks passed too
+func updateApprovals(svc *awsdynamodb.DynamoDB, out audit.Messenger, id int, number int) error {
+	item, err := db.GetPullRequest(svc, id, number)
+	if err != nil {
+		return err
+	}
+
+	// tracked before approvals were rendered on the parent message
+	if item.ParentMessage == "" {
+		return nil
+	}
+
+	approvals, err := github.GetApprovals(out.Repository, number)
+	if err != nil {
+		return err
+	}
+	if approvals == item.Approvals {
+		return nil
+	}
+
+	if err := db.UpdateApprovals(svc, id, number, approvals, item.RequiredApprovals, item.Version); err != nil {
+		return err
+	}
+	quorumMet := item.RequiredApprovals > 0 && item.Approvals < item.RequiredApprovals && approvals >= item.RequiredApprovals
+	item.Approvals = approvals
+
+	if err := out.UpdateMessage(item.SlackTimeStamp, messages.ParentMessage(item, time.Now())); err != nil {
+		return err
+	}
+
+	if quorumMet {
+		message, buttons, err := mergeReadyMessage(item)
+		if err != nil {
+			return err
+		}
+		if err := out.SendMessageThreadWithButtons(item.SlackTimeStamp, message, buttons); err != nil {
+			return err
+		}
+		return readyToMerge(svc, out, id, number, item)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRequiredApprovals(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"default": {"requiredApprovals": 1}, "repositories": {"api": {"requiredApprovals": 3}}}`)
+
+	zapLog := zap.NewNop()
+	if result := requiredApprovals("api", "main", zapLog); result != 3 {
+		t.Errorf("got %d want 3", result)
+	}
+	if result := requiredApprovals("web", "main", zapLog); result != 1 {
+		t.Errorf("got %d want 1", result)
+	}
+}
+
+func TestUpdateApprovals(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
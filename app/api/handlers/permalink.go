@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slack-pr-lambda/api/dashboard"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strconv"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+var getPermalink = slack.SlackGetPermalink
+var createComment = github.CreateComment
+
+// permalink of a parent message, empty when Slack can't resolve it
+func threadPermalink(timeStamp string, zapLog *zap.Logger) string {
+	permalink, err := getPermalink(timeStamp)
+	if err != nil {
+		zapLog.Warn("error slack get permalink",
+			zap.String("timeStamp", timeStamp),
+			zap.Error(err),
+		)
+		return ""
+	}
+	return permalink
+}
+
+// links the Slack thread from the pull request, for repositories with
+// threadComment enabled. Once per delivery, a redelivery replaying the parent
+// message doesn't comment again
+func threadComment(out audit.Messenger, repo string, number int, permalink string, zapLog *zap.Logger) {
+	if permalink == "" {
+		return
+	}
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		return
+	}
+	if !conf.Repo(repo).ThreadComment {
+		return
+	}
+
+	err = out.Once("github.thread_comment", func() error {
+		return createComment(repo, number, "Slack thread: "+permalink)
+	})
+	if err != nil {
+		zapLog.Warn("error create thread comment",
+			zap.String("repository", repo),
+			zap.Int("number", number),
+			zap.Error(err),
+		)
+	}
+}
+
+// parent message of a new record was posted, an Unrouted one has no thread
+func threadPosted(out audit.Messenger, item *types.TablePullRequestData, timeStamp string, zapLog *zap.Logger) {
+	item.SlackTimeStamp = timeStamp
+	if timeStamp == audit.Unrouted {
+		return
+	}
+	item.Permalink = threadPermalink(timeStamp, zapLog)
+	threadComment(out, item.Repository, item.PullRequestId, item.Permalink, zapLog)
+}
+
+// short link to the Slack thread of a tracked pull request, e.g. for
+// references outside Slack
+func ThreadHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	item, err := adminPullRequest(r.PathValue("repository"), number)
+	if errors.Is(err, db.ErrNoDataFound) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		zapLog.Error("error get pull request",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, dashboard.ThreadUrl(*item), http.StatusFound)
+}
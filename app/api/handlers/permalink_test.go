@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func stubThreadLinks(t *testing.T, permalink string, err error) *[]string {
+	comments := []string{}
+	originalPermalink := getPermalink
+	originalComment := createComment
+	getPermalink = func(timeStamp string) (string, error) {
+		return permalink, err
+	}
+	createComment = func(repo string, prNumber int, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+	t.Cleanup(func() {
+		getPermalink = originalPermalink
+		createComment = originalComment
+	})
+	return &comments
+}
+
+func TestThreadPosted(t *testing.T) {
+	permalink := "https://acme.slack.com/archives/C123/p1710054000000200"
+
+	t.Run("stored", func(t *testing.T) {
+		comments := stubThreadLinks(t, permalink, nil)
+		t.Setenv("REPO_CONFIG", "")
+
+		item := &types.TablePullRequestData{Repository: "api", PullRequestId: 7}
+		threadPosted(audit.Messenger{}, item, "1710054000.000200", zap.NewNop())
+		if item.SlackTimeStamp != "1710054000.000200" || item.Permalink != permalink {
+			t.Errorf("Expected the timestamp and the permalink stored, got %+v", item)
+		}
+		if len(*comments) != 0 {
+			t.Errorf("Expected no comment without threadComment, got %v", *comments)
+		}
+	})
+
+	t.Run("thread comment", func(t *testing.T) {
+		comments := stubThreadLinks(t, permalink, nil)
+		t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"threadComment": true}}}`)
+
+		threadPosted(audit.Messenger{}, &types.TablePullRequestData{Repository: "api", PullRequestId: 7}, "1710054000.000200", zap.NewNop())
+		if len(*comments) != 1 || (*comments)[0] != "Slack thread: "+permalink {
+			t.Errorf("Expected the thread linked from the pull request, got %v", *comments)
+		}
+	})
+
+	t.Run("permalink error", func(t *testing.T) {
+		comments := stubThreadLinks(t, "", errors.New("channel_not_found"))
+
+		item := &types.TablePullRequestData{Repository: "api", PullRequestId: 7}
+		threadPosted(audit.Messenger{}, item, "1710054000.000200", zap.NewNop())
+		if item.SlackTimeStamp != "1710054000.000200" || item.Permalink != "" || len(*comments) != 0 {
+			t.Errorf("Expected only the timestamp stored, got %+v %v", item, *comments)
+		}
+	})
+}
+
+func TestThreadHandlerBadNumber(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /threads/{repository}/{number}", ThreadHandler)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/threads/api/seven", nil))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusBadRequest)
+	}
+}
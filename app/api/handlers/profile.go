@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"slack-pr-lambda/env"
+)
+
+// runtime profiles of GET /admin/debug/pprof/{profile}, profile is the CPU
+// profile of ?seconds= and trace the execution trace
+var Profiles = []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate", "profile", "trace"}
+
+// PPROF_ENABLED=true serves the profiles, off by default since a CPU profile
+// or trace holds the lambda for its duration
+func pprofEnabled() bool {
+	return env.GetEnv("PPROF_ENABLED", "false") == "true"
+}
+
+// runtime profile of the lambda instance serving the request, read with
+// go tool pprof. ?debug=1 answers the text format instead
+func AdminProfileHandler(w http.ResponseWriter, r *http.Request) {
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !pprofEnabled() {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	switch name := r.PathValue("profile"); name {
+	case "profile":
+		pprof.Profile(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	default:
+		pprof.Handler(name).ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func profileRequest(profile string, token string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/debug/pprof/{profile}", AdminProfileHandler)
+
+	req := httptest.NewRequest("GET", "/admin/debug/pprof/"+profile+"?debug=1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestAdminProfileHandler(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	t.Run("unauthorized", func(t *testing.T) {
+		t.Setenv("PPROF_ENABLED", "true")
+		assert.Equal(t, http.StatusUnauthorized, profileRequest("heap", "other").Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("PPROF_ENABLED", "")
+		assert.Equal(t, http.StatusNotFound, profileRequest("heap", "secret").Code)
+	})
+
+	t.Run("heap", func(t *testing.T) {
+		t.Setenv("PPROF_ENABLED", "true")
+		rr := profileRequest("heap", "secret")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, strings.HasPrefix(rr.Body.String(), "heap profile:"), rr.Body.String())
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		t.Setenv("PPROF_ENABLED", "true")
+		assert.Equal(t, http.StatusNotFound, profileRequest("unknown", "secret").Code)
+	})
+}
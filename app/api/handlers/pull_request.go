@@ -7,25 +7,43 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"slack-pr-lambda/api/mail"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/apperrors"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
 	"slack-pr-lambda/constants"
 	db "slack-pr-lambda/dynamodb"
 	"slack-pr-lambda/env"
 	"slack-pr-lambda/github"
-	"slack-pr-lambda/logger"
 	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/recorder"
+	"slack-pr-lambda/redact"
 	"slack-pr-lambda/slack"
 	"slack-pr-lambda/types"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
 	"go.uber.org/zap"
 )
 
+// replaced in tests
+var insertItemAudited = db.InsertItemAudited
+
+var insertItem = db.InsertItem
+
 func PullRequestHandler(w http.ResponseWriter, r *http.Request) {
 	env := env.GetEnv("ENV", "local")
 
-	l := logger.LoggerConfig()
-	zapLog, _ := l.Build()
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
 
 	slackUsers := constants.SlackUsers()
 	slackUsersMap := mapstruct.StructToMap(*slackUsers)
@@ -45,8 +63,16 @@ func PullRequestHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	// read request body
-	body, err := io.ReadAll(r.Body)
+	// read request body, read at once for the signature and the recording
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes()))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		zapLog.Error("error request body too large",
+			zap.Int64("limit", tooLarge.Limit),
+		)
+		http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+		return
+	}
 	if err != nil {
 
 		zapLog.Error("error read request body",
@@ -56,513 +82,788 @@ func PullRequestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := github.VerifyWebhook(r.Header, body); err != nil {
+		zapLog.Warn("error verify webhook",
+			zap.Error(err),
+		)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if recorder.Enabled() {
+		if _, err := recorder.Record(r.Header, body); err != nil {
+			zapLog.Warn("error record webhook",
+				zap.Error(err),
+			)
+		}
+	}
+
+	body, err = webhookPayload(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		zapLog.Error("error parse form payload",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	// GitHub Enterprise Server deliveries name their instance
+	if host := r.Header.Get("X-GitHub-Enterprise-Host"); host != "" {
+		zapLog = zapLog.With(zap.String("enterpriseHost", host))
+	}
+
 	if env != "local" {
-		fmt.Printf("Payload %v", string(body))
+		fmt.Printf("Payload %v", string(redact.JSON(body)))
 	}
 
-	// partial parse into map string JSON
-	var result map[string]json.RawMessage
-	if err := json.Unmarshal(body, &result); err != nil {
-		zapLog.Error("error unmarshal JSON raw message",
+	// decoded once, each action reads its typed payload from the envelope
+	var event types.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		zapLog.Error("error unmarshal JSON",
 			zap.Error(err),
 		)
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		writeError(w, apperrors.Wrap(apperrors.ErrBadPayload, "webhook.decode", err))
+		return
+	}
+
+	action := event.Action
+	if action == "" {
+		zapLog.Error("error parse action from req body")
+		writeError(w, apperrors.Wrap(apperrors.ErrBadPayload, "webhook.decode", errors.New("missing action")))
+		return
+	}
+	webhookEvents.Inc(action)
+
+	githubEvent := r.Header.Get("X-GitHub-Event")
+
+	// alert payloads are not part of the pull request envelope
+	if strictDecoding() && !securityEvent(githubEvent) {
+		reportDrift(action, body, zapLog)
+	}
+
+	repository := event.Repository.GetName()
+
+	failures := &failureWriter{ResponseWriter: w}
+	w = failures
+	defer reportFailure(failures, repository, action, zapLog)
+
+	trail := &audit.Trail{}
+	defer reportSteps(failures, trail, githubEvent, action, time.Now(), zapLog)
+	out := audit.Messenger{
+		EventId:    r.Header.Get("X-GitHub-Delivery"),
+		Source:     action,
+		Repository: repository,
+		Number:     event.PullRequestNumber(),
+		Log:        zapLog,
+		Trail:      trail,
+		Files:      &audit.ChangedFiles{},
+	}
+	out.Resend = parentResender(out, zapLog)
+
+	// drop actions the repository did not opt into before any Slack call
+	if !allowedEvent(githubEvent, action, repository, zapLog) {
+		droppedEvents.Inc(action)
+		trail.Skip("not allowed by the repository config")
+		writeWebhookResponse(w, "Webhook ignored.", githubEvent, action, trail)
+		return
+	}
+
+	// security alerts go to the security channel, apart from the pull request pipeline
+	if securityEvent(githubEvent) {
+		securityDelivery(failures, out, githubEvent, event, zapLog)
+		return
+	}
+
+	// actions no handler acts on are reported instead of silently acknowledged
+	if !handledAction(githubEvent, action) {
+		reportUnhandled(githubEvent, action, repository, body, zapLog)
+		if rejectUnhandled() {
+			http.Error(w, "Unprocessable Entity", http.StatusUnprocessableEntity)
+			return
+		}
+		trail.Skip("unhandled action")
+		writeWebhookResponse(w, "Webhook ignored.", githubEvent, action, trail)
 		return
 	}
 
-	// get unique action key
-	var action string
-	if err := json.Unmarshal(result["action"], &action); err != nil {
-		zapLog.Error("error parse action from req body",
+	svc, err := db.Connection()
+	if err != nil {
+		zapLog.Error("error dynamodb connection",
 			zap.Error(err),
 		)
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
+	defer updateDashboard(failures, action, zapLog)
+	defer recordReviewMetrics(failures, event, zapLog)
+	defer recordEvent(failures, githubEvent, out.EventId, event, zapLog)
+	defer forwardEvent(failures, githubEvent, out.EventId, event, zapLog)
+	defer publishEvent(failures, githubEvent, out.EventId, event, zapLog)
+	defer streamEvent(failures, githubEvent, out.EventId, event, zapLog)
+	// registered last so it recovers before the deferred reporting runs
+	defer recoverPanic(failures, githubEvent, action, trail, zapLog)
 
 	// Opened new pull request
 	if action == "opened" {
-		// parse request
-		var input types.OpenPullRequest
-		err = json.Unmarshal(body, &input)
-		if err != nil {
-			zapLog.Error("error unmarshal JSON",
-				zap.Error(err),
-			)
-			http.Error(w, "Bad Request", http.StatusBadRequest)
-			return
+		input := event.OpenPullRequest()
+		item := openedItem(input, "opened", slackUsersMap, time.Now(), zapLog)
+		first := firstContribution(svc, input, zapLog)
+		if first {
+			item.ParentMessage += "\n" + firstContributionLine(messages.Language(), input.PullRequest.GetUser().GetLogin(), slackUsersMap)
 		}
 
-		user := slackUsersMap[input.Sender.Login]
-		if input.Sender.Login == "dependabot[bot]" {
-			user = "dependabot[bot]"
+		// a pull request outside the paths of SLACK_CHANNEL is still tracked,
+		// its events are copied to the routed destinations
+		parent := localizedParent(input, "opened", item, first, slackUsersMap)
+		timeStamp, entry, err := out.Localized(parent).SendParentMessage(input, messages.ParentMessage(item, time.Now()))
+		if errors.Is(err, audit.ErrNotRouted) {
+			timeStamp, err = audit.Unrouted, nil
 		}
-
-		messageText := fmt.Sprintf("<@%s> %s opened new <%s|pull request> in `%s`.", user, emoji.Opened, input.PullRequest.HtmlUrl, input.Repository.Name)
-		timeStamp, err := slack.SlackSendMessage(input, messageText)
 		if err != nil {
 			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
-		if len(input.PullRequest.RequestedReviewers) > 0 {
-			reviewers := []string{}
-			for _, reviewer := range input.PullRequest.RequestedReviewers {
-				reviewers = append(reviewers, reviewer.Login)
-			}
 
-			var slackMention string = "Please review: "
-			for _, user := range reviewers {
-				slackMention += fmt.Sprintf("<@%s> %s", slackUsersMap[user], emoji.RequestReview)
-			}
-			if err = slack.SlackSendMessageThread(timeStamp, slackMention); err != nil {
-				zapLog.Error("error slack send message",
-					zap.Error(err),
-				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
-		}
+		threadPosted(out, item, timeStamp, zapLog)
 
-		if err := slack.SlackAddReaction(timeStamp, strings.ReplaceAll(emoji.Opened, ":", "")); err != nil {
-			zapLog.Error("error slack add reaction",
+		if err := storeParent(svc, out, item, entry); err != nil {
+			zapLog.Error("error insert data",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
-
-		svc := db.DynamoDbConnection()
-		item := &types.TablePullRequestData{
-			ID:             fmt.Sprintf("%d", input.PullRequest.ID),
-			PullRequestId:  input.Number,
-			SlackTimeStamp: timeStamp,
-		}
-
-		err = db.InsertItem(svc, item)
-		if err != nil {
-			zapLog.Error("error insert data",
+		if err := openedThread(svc, out, timeStamp, input, slackUsersMap, zapLog); err != nil {
+			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 	}
 
 	// Add new reviewer
 	if action == "review_requested" {
-		// parse request
-		var input types.ReviewRequestPullRequest
-		err = json.Unmarshal(body, &input)
+		input := event.ReviewRequestPullRequest()
+
+		timeStamp, err := slackTimeStamp(svc, trail, int(input.PullRequest.GetID()), input.Number)
 		if err != nil {
-			zapLog.Error("error unmarshal JSON",
+			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Bad Request", http.StatusBadRequest)
+			writeError(w, err)
 			return
 		}
 
-		svc := db.DynamoDbConnection()
-		timeStamp, err := db.GetSlackTimeStamp(svc, input.PullRequest.ID, input.Number)
+		// teams have no Slack mapping, only requested users are pinged
+		if input.RequestedReviewer == nil {
+			trail.Skip("team review request")
+		} else if types.WorkInProgress(input.PullRequest.GetTitle()) {
+			trail.Skip("work in progress")
+		} else if incomplete, _ := incompleteDescription(repository, input.PullRequest.GetBody(), zapLog); incomplete {
+			trail.Skip("incomplete description")
+		} else if tracked(trail, timeStamp) {
+			author := input.PullRequest.GetUser().GetLogin()
+			reviewers, self := withoutAuthor([]string{input.RequestedReviewer.GetLogin()}, author)
+			if self {
+				others, _ := withoutAuthor(requestedLogins(input.PullRequest), author)
+				if err = out.SendMessageThread(timeStamp, selfReviewNotice(author, len(others) == 0, slackUsersMap)); err != nil {
+					zapLog.Error("error slack send message",
+						zap.Error(err),
+					)
+					writeError(w, err)
+					return
+				}
+				trail.Skip("self review request")
+			}
+
+			if len(reviewers) > 0 {
+				ooo := outOfOffice(svc, zapLog)
+				entry := mail.Entry{Repository: repository, Number: input.Number, CreatedAt: types.FormatTime(input.PullRequest.CreatedAt)}
+				emailed := emailReviewRequest(out, reviewers, entry, slackUsersMap, ooo, time.Now(), zapLog)
+				alternates := reviewAlternates(repository, input.Number, reviewers, author, ooo, true, time.Now(), zapLog)
+				slackMention := reviewRequestMessage(reviewers, alternates, slackUsersMap, ooo, emailed, time.Now())
+				if err = pingReviewers(svc, out, timeStamp, int(input.PullRequest.GetID()), input.Number, reviewers, slackMention, zapLog); err != nil {
+					zapLog.Error("error slack send message",
+						zap.Error(err),
+					)
+					writeError(w, err)
+					return
+				}
+			}
+		}
+	}
+
+	// inline review comment, bursts of a reviewer are batched into one reply
+	if action == "created" && event.Comment.GetPath() != "" {
+		timeStamp, err := slackTimeStamp(svc, trail, int(event.PullRequest.GetID()), event.PullRequest.GetNumber())
 		if err != nil {
 			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
-		if timeStamp != "" {
-			reviewers := []string{input.RequestedReviewer.Login}
-			var slackMention string = "Please review: "
-			for _, user := range reviewers {
-				slackMention += fmt.Sprintf("<@%s> %s", slackUsersMap[user], emoji.RequestReview)
-			}
-			if err = slack.SlackSendMessageThread(timeStamp, slackMention); err != nil {
-				zapLog.Error("error slack send message",
+		if tracked(trail, timeStamp) {
+			if err := reviewComment(svc, out, timeStamp, event, slackUsersMap, time.Now()); err != nil {
+				zapLog.Error("error slack send review comment",
 					zap.Error(err),
 				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				writeError(w, err)
 				return
 			}
-
 		}
 	}
 
 	// Directly commented in the PR issue
-	if action == "created" {
-		// parse request
-		var input types.CommentPullRequest
-		err = json.Unmarshal(body, &input)
-		if err != nil {
-			zapLog.Error("error unmarshal JSON",
-				zap.Error(err),
-			)
-			http.Error(w, "Bad Request", http.StatusBadRequest)
-			return
-		}
+	if action == "created" && event.Comment.GetPath() == "" {
+		input := event.CommentPullRequest()
 
-		svc := db.DynamoDbConnection()
-		prId, err := github.GetPullRequestId(input.Repository.Name, input.Issue.Number)
+		prId, err := github.GetPullRequestId(input.Repository.GetName(), input.Issue.GetNumber())
 		if err != nil {
 			zapLog.Error("error get pull request id",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
-		timeStamp, err := db.GetSlackTimeStamp(svc, int(prId), input.Issue.Number)
+		timeStamp, err := slackTimeStamp(svc, trail, int(prId), input.Issue.GetNumber())
 		if err != nil {
 			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
-		if timeStamp != "" {
-			message := fmt.Sprintf("<@%s> %s submitted an issue <%s|comment>. \n", slackUsersMap[input.Comment.User.Login], emoji.Comment, input.Comment.HtmlUrl)
-			message += fmt.Sprintf("```%s```\n", input.Comment.Body)
-			if err = slack.SlackSendMessageThread(timeStamp, message); err != nil {
+		// "/slack <command>" comments control the notifications instead of being posted
+		command, isCommand := parseCommentCommand(input.Comment.GetBody())
+		if isCommand {
+			trail.Skip("comment command")
+			if err := runCommentCommand(svc, out, timeStamp, int(prId), event, command, slackUsersMap, zapLog); err != nil {
+				zapLog.Error("error comment command",
+					zap.Error(err),
+				)
+				writeError(w, err)
+				return
+			}
+		}
+
+		rolledUp := false
+		if !isCommand && tracked(trail, timeStamp) {
+			rolledUp, err = rollupComment(svc, int(prId), input.Issue.GetNumber(), input.Repository.GetName(), time.Now())
+			if err != nil {
+				zapLog.Error("error rollup comment",
+					zap.Error(err),
+				)
+				writeError(w, err)
+				return
+			}
+		}
+
+		if rolledUp {
+			trail.Skip("comment rolled up")
+		}
+
+		if timeStamp != "" && !rolledUp && !isCommand {
+			message := func(language string) string {
+				return messages.In(language, "comment.issue", slackUsersMap[input.Comment.GetUser().GetLogin()], emoji.Comment, input.Comment.GetHTMLURL()) + messages.Quote(input.Comment.GetBody(), slackUsersMap)
+			}
+			if err = out.Localized(message).SendMessageThread(timeStamp, message(messages.Language())); err != nil {
 				zapLog.Error("error slack send message",
 					zap.Error(err),
 				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				writeError(w, err)
 				return
 			}
+			postImages(out, timeStamp, input.Comment.GetBody(), input.Comment.GetHTMLURL(), "comment", zapLog)
 		}
 	}
 
 	// closed / merged PR
 	if action == "closed" {
-		// parse request
-		var input types.ClosedPullRequest
-		err = json.Unmarshal(body, &input)
-		if err != nil {
-			zapLog.Error("error unmarshal JSON",
-				zap.Error(err),
-			)
-			http.Error(w, "Bad Request", http.StatusBadRequest)
-			return
-		}
+		input := event.ClosedPullRequest()
 
-		svc := db.DynamoDbConnection()
-		timeStamp, err := db.GetSlackTimeStamp(svc, input.PullRequest.ID, input.Number)
+		timeStamp, err := slackTimeStamp(svc, trail, int(input.PullRequest.GetID()), input.Number)
 		if err != nil {
 			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
-		if timeStamp != "" {
+		if tracked(trail, timeStamp) {
 			closeEmoji := emoji.Closed
-			message := fmt.Sprintf("<@%s> closed the pull request %s. ", slackUsersMap[input.Sender.Login], emoji.Closed)
-			if len(input.PullRequest.MergedAt) > 0 {
+			closeEvent := "closed"
+			message := func(language string) string {
+				return messages.In(language, "closed", slackUsersMap[input.Sender.GetLogin()], emoji.Closed)
+			}
+			if input.PullRequest.MergedAt != nil {
 				closeEmoji = emoji.Merged
-				message = fmt.Sprintf("<@%s> merged the pull request %s. ", slackUsersMap[input.Sender.Login], emoji.Merged)
+				closeEvent = "merged"
+				message = func(language string) string {
+					return messages.In(language, "merged", slackUsersMap[input.Sender.GetLogin()], emoji.Merged)
+				}
 			}
 
-			if err := slack.SlackAddReaction(timeStamp, strings.ReplaceAll(closeEmoji, ":", "")); err != nil {
+			if err := addReaction(timeStamp, closeEmoji); err != nil {
 				zapLog.Error("error slack add reaction",
 					zap.Error(err),
 				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				writeError(w, err)
 				return
 			}
-			if err := slack.SlackSendMessageThread(timeStamp, message); err != nil {
+			if err := out.Localized(message).SendEventThread(closeEvent, timeStamp, message(messages.Language())); err != nil {
 				zapLog.Error("error slack send message",
 					zap.Error(err),
 				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				writeError(w, err)
 				return
 			}
+			if input.PullRequest.MergedAt != nil {
+				notifyWatchers(svc, repository, input.Number, slackUsersMap[input.Sender.GetLogin()], watchMergedMessage(repository, input.Number, slackUsersMap[input.Sender.GetLogin()]), zapLog)
+			}
+
+			archivePullRequest(svc, input, repository, zapLog)
 
 			// Delete PR in dynamodb Table
-			svc := db.DynamoDbConnection()
-			err = db.DeleteItem(svc, input.PullRequest.ID, input.Number)
+			err = db.DeleteItem(svc, int(input.PullRequest.GetID()), input.Number)
 			if err != nil {
 				zapLog.Error("error delete data",
 					zap.Error(err),
 				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				writeError(w, err)
 				return
 			}
 		}
+
+		// dependencies merged in untracked repositories unblock too
+		if input.PullRequest.MergedAt != nil {
+			unblockDependents(svc, out, event, zapLog)
+		}
 	}
 
 	// submitted a PR review
 	if action == "submitted" {
-		// parse request
-		var input types.SubmitReviewPullRequest
-		err = json.Unmarshal(body, &input)
-		if err != nil {
-			zapLog.Error("error unmarshal JSON",
-				zap.Error(err),
-			)
-			http.Error(w, "Bad Request", http.StatusBadRequest)
-			return
-		}
+		input := event.SubmitReviewPullRequest()
 
-		svc := db.DynamoDbConnection()
-		timeStamp, err := db.GetSlackTimeStamp(svc, input.PullRequest.ID, input.PullRequest.Number)
+		timeStamp, err := slackTimeStamp(svc, trail, int(input.PullRequest.GetID()), input.PullRequest.GetNumber())
 		if err != nil {
 			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
-		if timeStamp != "" {
-			if input.Review.State == "commented" {
-				message := fmt.Sprintf("<@%s> submitted a review <%s|comment> %s. \n ", slackUsersMap[input.Review.User.Login], input.Review.HtmlUrl, emoji.Reviewed)
-				if len(input.Review.Body) > 0 {
-					message += fmt.Sprintf("```%s```\n", input.Review.Body)
+		if tracked(trail, timeStamp) {
+			// answering review comments on your own pull request is not a first response
+			if input.Review.GetUser().GetLogin() != input.PullRequest.GetUser().GetLogin() {
+				if err := db.RecordFirstReview(svc, int(input.PullRequest.GetID()), input.PullRequest.GetNumber(), orDefault(types.FormatTime(input.Review.SubmittedAt), time.Now().Format(time.RFC3339))); err != nil {
+					zapLog.Error("error record first review",
+						zap.Error(err),
+					)
+					writeError(w, err)
+					return
 				}
-				if err := slack.SlackSendMessageThread(timeStamp, message); err != nil {
+			}
+
+			if input.Review.GetState() == "commented" {
+				message := reviewMessage(input, "review.commented", emoji.Reviewed, slackUsersMap)
+				if err := out.Localized(message).SendMessageThread(timeStamp, message(messages.Language())); err != nil {
 					zapLog.Error("error slack send message",
 						zap.Error(err),
 					)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeError(w, err)
 					return
 				}
 			}
 
-			if input.Review.State == "approved" {
-				message := fmt.Sprintf("<@%s> approved the pull <%s|request> %s. \n", slackUsersMap[input.Review.User.Login], input.Review.HtmlUrl, emoji.Approved)
-				if len(input.Review.Body) > 0 {
-					message += fmt.Sprintf("```%s```\n", input.Review.Body)
-				}
+			if input.Review.GetState() == "approved" {
+				message := reviewMessage(input, "review.approved", emoji.Approved, slackUsersMap)
 
-				if err := slack.SlackAddReaction(timeStamp, strings.ReplaceAll(emoji.Approved, ":", "")); err != nil {
+				if err := addReaction(timeStamp, emoji.Approved); err != nil {
 					zapLog.Error("error slack add reaction",
 						zap.Error(err),
 					)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeError(w, err)
 					return
 				}
-				if err := slack.SlackSendMessageThread(timeStamp, message); err != nil {
+				if err := out.Localized(message).SendEventThread("approved", timeStamp, message(messages.Language())); err != nil {
 					zapLog.Error("error slack send message",
 						zap.Error(err),
 					)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeError(w, err)
 					return
 				}
+				reviewer := slackUsersMap[input.Review.GetUser().GetLogin()]
+				notifyWatchers(svc, repository, input.PullRequest.GetNumber(), reviewer, watchApprovedMessage(repository, input.PullRequest.GetNumber(), reviewer), zapLog)
 			}
 
-			if input.Review.State == "changes_requested" {
-				message := fmt.Sprintf("<@%s> requested a change <%s|comment> %s. \n ", slackUsersMap[input.Review.User.Login], input.Review.HtmlUrl, emoji.RequestedChanges)
-				if len(input.Review.Body) > 0 {
-					message += fmt.Sprintf("```%s```\n", input.Review.Body)
-				}
-				if err := slack.SlackSendMessageThread(timeStamp, message); err != nil {
+			if input.Review.GetState() == "changes_requested" {
+				message := reviewMessage(input, "review.changes_requested", emoji.RequestedChanges, slackUsersMap)
+				if err := out.Localized(message).SendEventThread("changes_requested", timeStamp, message(messages.Language())); err != nil {
 					zapLog.Error("error slack send message",
 						zap.Error(err),
 					)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeError(w, err)
+					return
+				}
+
+				// the author is nudged when no commits follow, see the follow-ups job
+				err = db.UpdateChangesRequested(svc, int(input.PullRequest.GetID()), input.PullRequest.GetNumber(), input.PullRequest.GetUser().GetLogin(), input.Review.GetUser().GetLogin(), input.Review.GetHTMLURL(), orDefault(types.FormatTime(input.Review.SubmittedAt), time.Now().Format(time.RFC3339)))
+				if err != nil {
+					zapLog.Error("error update changes requested",
+						zap.Error(err),
+					)
+					writeError(w, err)
 					return
 				}
 			}
+
+			// the reviewer answered, their "Please review" ping is stale
+			if err := clearReviewPings(svc, out, int(input.PullRequest.GetID()), input.PullRequest.GetNumber(), input.Review.GetUser().GetLogin(), slackUsersMap, zapLog); err != nil {
+				zapLog.Warn("error clear review pings",
+					zap.Error(err),
+				)
+			}
+
+			err := retryConflict(func() error {
+				return updateApprovals(svc, out, int(input.PullRequest.GetID()), input.PullRequest.GetNumber())
+			})
+			if err != nil {
+				zapLog.Error("error update approvals",
+					zap.Error(err),
+				)
+				writeError(w, err)
+				return
+			}
 		}
 	}
 
-	// added commits to the PR branch
-	if action == "synchronize" {
-		// parse request
-		var input types.PushPullRequestSync
-		err = json.Unmarshal(body, &input)
+	// labels are matched against the review SLA rules
+	if action == "labeled" || action == "unlabeled" {
+		if err := db.UpdateLabels(svc, int(event.PullRequest.GetID()), event.PullRequest.GetNumber(), types.LabelNames(event.PullRequest)); err != nil {
+			zapLog.Error("error update labels",
+				zap.Error(err),
+			)
+			writeError(w, err)
+			return
+		}
+	}
+
+	// title edits, removing the WIP prefix pings the reviewers held back
+	if action == "edited" {
+		err := retryConflict(func() error {
+			return workInProgressEdit(svc, out, event, slackUsersMap, zapLog)
+		})
+		if err != nil {
+			zapLog.Error("error update work in progress",
+				zap.Error(err),
+			)
+			writeError(w, err)
+			return
+		}
+	}
+
+	// body edits, the declared dependencies of the pull request
+	if pullRequestEdit(githubEvent, action) {
+		err := retryConflict(func() error {
+			return dependenciesEdit(svc, out, event)
+		})
 		if err != nil {
-			zapLog.Error("error unmarshal JSON",
+			zapLog.Error("error update dependencies",
 				zap.Error(err),
 			)
-			http.Error(w, "Bad Request", http.StatusBadRequest)
+			writeError(w, err)
 			return
 		}
+	}
 
-		svc := db.DynamoDbConnection()
-		timeStamp, err := db.GetSlackTimeStamp(svc, input.PullRequest.ID, input.PullRequest.Number)
+	// body edits, completing the required description pings the reviewers
+	if pullRequestEdit(githubEvent, action) {
+		if err := descriptionEdit(svc, out, event, slackUsersMap, zapLog); err != nil {
+			zapLog.Error("error ping held reviewers",
+				zap.Error(err),
+			)
+			writeError(w, err)
+			return
+		}
+	}
+
+	// body edits, the template checklist of the pull request
+	if pullRequestEdit(githubEvent, action) {
+		err := retryConflict(func() error {
+			return checklistEdit(svc, out, event)
+		})
+		if err != nil {
+			zapLog.Error("error update checklist",
+				zap.Error(err),
+			)
+			writeError(w, err)
+			return
+		}
+	}
+
+	// dismissed a PR review, the approval no longer counts
+	if action == "dismissed" {
+		input := event.SubmitReviewPullRequest()
+
+		timeStamp, err := slackTimeStamp(svc, trail, int(input.PullRequest.GetID()), input.PullRequest.GetNumber())
 		if err != nil {
 			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
-		if timeStamp != "" {
-			commitLink := fmt.Sprintf("%s/commits/%s", input.PullRequest.HtmlUrl, input.After)
-			message := fmt.Sprintf("<@%s> %s pushed a <%s|change>.", slackUsersMap[input.Sender.Login], emoji.Pushed, commitLink)
-			if err = slack.SlackSendMessageThread(timeStamp, message); err != nil {
-				zapLog.Error("error slack send message",
+		if tracked(trail, timeStamp) {
+			err := retryConflict(func() error {
+				return updateApprovals(svc, out, int(input.PullRequest.GetID()), input.PullRequest.GetNumber())
+			})
+			if err != nil {
+				zapLog.Error("error update approvals",
 					zap.Error(err),
 				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				writeError(w, err)
 				return
 			}
 		}
 	}
 
-	// check run completed
-	if action == "completed" {
-		// parse request
-		var input types.CheckRunPullRequest
-		err = json.Unmarshal(body, &input)
+	// added commits to the PR branch
+	if action == "synchronize" {
+		input := event.PushPullRequestSync()
+
+		timeStamp, err := slackTimeStamp(svc, trail, int(input.PullRequest.GetID()), input.PullRequest.GetNumber())
 		if err != nil {
-			zapLog.Error("error unmarshal JSON",
+			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Bad Request", http.StatusBadRequest)
+			writeError(w, err)
 			return
 		}
 
-		svc := db.DynamoDbConnection()
+		if tracked(trail, timeStamp) {
+			commitLink := fmt.Sprintf("%s/commits/%s", input.PullRequest.GetHTMLURL(), input.After)
+			message := func(language string) string {
+				return messages.In(language, "pushed", slackUsersMap[input.Sender.GetLogin()], emoji.Pushed, commitLink)
+			}
+			if err = out.Localized(message).SendMessageThread(timeStamp, message(messages.Language())); err != nil {
+				zapLog.Error("error slack send message",
+					zap.Error(err),
+				)
+				writeError(w, err)
+				return
+			}
+
+			if err := db.ClearChangesRequested(svc, int(input.PullRequest.GetID()), input.PullRequest.GetNumber()); err != nil {
+				zapLog.Error("error clear changes requested",
+					zap.Error(err),
+				)
+				writeError(w, err)
+				return
+			}
+
+			// required checks run again on the new head
+			if err := db.ClearPassedChecks(svc, int(input.PullRequest.GetID()), input.PullRequest.GetNumber(), input.PullRequest.GetHead().GetSHA()); err != nil {
+				zapLog.Error("error clear passed checks",
+					zap.Error(err),
+				)
+				writeError(w, err)
+				return
+			}
+
+			err := retryConflict(func() error {
+				return changedFilesPush(svc, out, int(input.PullRequest.GetID()), input.PullRequest.GetNumber(), zapLog)
+			})
+			if err != nil {
+				zapLog.Error("error update changed files",
+					zap.Error(err),
+				)
+				writeError(w, err)
+				return
+			}
+		}
+	}
+
+	// check run completed
+	if action == "completed" && event.WorkflowRun == nil {
+		input := event.CheckRunPullRequest()
+
 		var pullRequestNumber int
 		var pullRequestId int
 
-		// should always only have one element
-		for _, e := range input.CheckRun.PullRequests {
-			pullRequestId = e.ID
-			pullRequestNumber = e.Number
+		// should always only have one element, none for check runs of other
+		// events completing
+		if input.CheckRun != nil {
+			for _, e := range input.CheckRun.PullRequests {
+				pullRequestId = int(e.GetID())
+				pullRequestNumber = e.GetNumber()
+			}
 		}
 
-		timeStamp, err := db.GetSlackTimeStamp(svc, pullRequestId, pullRequestNumber)
+		timeStamp, err := slackTimeStamp(svc, trail, pullRequestId, pullRequestNumber)
 		if err != nil {
 			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
-		if timeStamp != "" {
-			if input.CheckRun.Status == "completed" && len(input.CheckRun.CompletedAt) > 0 {
-				message := fmt.Sprintf("Check run <%s|%s> %s.", input.CheckRun.HtmlUrl, input.CheckRun.Name, emoji.CheckPassed)
-				if input.CheckRun.Conclusion == "failure" {
-					message = fmt.Sprintf("Check run <%s|%s> %s.", input.CheckRun.HtmlUrl, input.CheckRun.Name, emoji.CheckFailed)
+		if tracked(trail, timeStamp) {
+			if input.CheckRun.GetStatus() == "completed" && input.CheckRun.CompletedAt != nil {
+				checkEmoji := emoji.CheckPassed
+				if input.CheckRun.GetConclusion() == "failure" {
+					checkEmoji = emoji.CheckFailed
 				}
 
-				if input.CheckRun.Conclusion == "cancelled" {
-					message = fmt.Sprintf("Check run <%s|%s> %s.", input.CheckRun.HtmlUrl, input.CheckRun.Name, emoji.CheckCanceled)
+				if input.CheckRun.GetConclusion() == "cancelled" {
+					checkEmoji = emoji.CheckCanceled
 				}
 
-				if err := slack.SlackSendMessageThread(timeStamp, message); err != nil {
+				message := func(language string) string {
+					return messages.In(language, "check_run", input.CheckRun.GetHTMLURL(), input.CheckRun.GetName(), checkEmoji)
+				}
+				if err := out.Localized(message).SendMessageThread(timeStamp, message(messages.Language())); err != nil {
 					zapLog.Error("error slack send message",
 						zap.Error(err),
 					)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeError(w, err)
 					return
 				}
 			}
 
-			if input.CheckRun.CheckSuite.Status == "completed" && input.CheckRun.CheckSuite.Conclusion == "success" {
-				message := fmt.Sprintf("All checks have passed. %s", emoji.CheckPassed)
-				if err := slack.SlackSendMessageThread(timeStamp, message); err != nil {
+			if input.CheckRun.GetCheckSuite().GetStatus() == "completed" && input.CheckRun.GetCheckSuite().GetConclusion() == "success" {
+				message := func(language string) string {
+					return messages.In(language, "checks.passed", emoji.CheckPassed)
+				}
+				if err := out.Localized(message).SendEventThread("checks_passed", timeStamp, message(messages.Language())); err != nil {
 					zapLog.Error("error slack send message",
 						zap.Error(err),
 					)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeError(w, err)
 					return
 				}
 			}
 
-			if input.CheckRun.CheckSuite.Status == "completed" && input.CheckRun.CheckSuite.Conclusion == "failure" {
-				message := fmt.Sprintf("Some checks were not successful. %s", emoji.CheckFailed)
-				if err := slack.SlackSendMessageThread(timeStamp, message); err != nil {
+			if input.CheckRun.GetCheckSuite().GetStatus() == "completed" && input.CheckRun.GetCheckSuite().GetConclusion() == "failure" {
+				message := func(language string) string {
+					return messages.In(language, "checks.failed", emoji.CheckFailed)
+				}
+				if err := out.Localized(message).SendEventThread("checks_failed", timeStamp, message(messages.Language())); err != nil {
 					zapLog.Error("error slack send message",
 						zap.Error(err),
 					)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeError(w, err)
 					return
 				}
+				notifyWatchers(svc, repository, pullRequestNumber, nil, watchChecksFailedMessage(repository, pullRequestNumber), zapLog)
 			}
 
-			if input.CheckRun.CheckSuite.Status == "completed" && input.CheckRun.CheckSuite.Conclusion == "cancelled" {
-				message := fmt.Sprintf("Some checks were cancelled. %s", emoji.CheckCanceled)
-				if err := slack.SlackSendMessageThread(timeStamp, message); err != nil {
+			if input.CheckRun.GetCheckSuite().GetStatus() == "completed" && input.CheckRun.GetCheckSuite().GetConclusion() == "cancelled" {
+				message := func(language string) string {
+					return messages.In(language, "checks.canceled", emoji.CheckCanceled)
+				}
+				if err := out.Localized(message).SendMessageThread(timeStamp, message(messages.Language())); err != nil {
 					zapLog.Error("error slack send message",
 						zap.Error(err),
 					)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					writeError(w, err)
+					return
+				}
+			}
+
+			if input.CheckRun.GetStatus() == "completed" {
+				if err := requiredCheckCompleted(svc, out, pullRequestId, pullRequestNumber, input.CheckRun); err != nil {
+					zapLog.Error("error update required checks",
+						zap.Error(err),
+					)
+					writeError(w, err)
 					return
 				}
 			}
 		}
 	}
 
-	// PR reopened
-	if action == "reopened" {
-		// parse request
-		var input types.OpenPullRequest
-		err = json.Unmarshal(body, &input)
-		if err != nil {
-			zapLog.Error("error unmarshal JSON",
+	// workflow run completed, failures and recoveries of the workflow
+	if action == "completed" && event.WorkflowRun != nil {
+		if err := workflowRunCompleted(svc, out, event, zapLog); err != nil {
+			zapLog.Error("error workflow run",
 				zap.Error(err),
 			)
-			http.Error(w, "Bad Request", http.StatusBadRequest)
+			writeError(w, err)
 			return
 		}
+	}
 
-		messageText := fmt.Sprintf("<@%s> %s Reopened <%s|pull request> in `%s`.", slackUsersMap[input.Sender.Login], emoji.Opened, input.PullRequest.HtmlUrl, input.Repository.Name)
-
-		timeStamp, err := slack.SlackSendMessage(input, messageText)
-		if err != nil {
-			zapLog.Error("error slack send message",
+	// merge queue entries and the checks of their merge group
+	if action == "enqueued" || action == "dequeued" || action == "checks_requested" {
+		if err := mergeQueueEvent(svc, out, event, slackUsersMap, zapLog); err != nil {
+			zapLog.Error("error merge queue",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
-		if len(input.PullRequest.RequestedReviewers) > 0 {
-			reviewers := []string{}
-			for _, reviewer := range input.PullRequest.RequestedReviewers {
-				reviewers = append(reviewers, reviewer.Login)
-			}
+	}
 
-			var slackMention string = "Please review: "
-			for _, user := range reviewers {
-				slackMention += fmt.Sprintf("<@%s> %s", slackUsersMap[user], emoji.RequestReview)
-			}
-			if err = slack.SlackSendMessageThread(timeStamp, slackMention); err != nil {
-				zapLog.Error("error slack send message",
-					zap.Error(err),
-				)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
+	// PR reopened
+	if action == "reopened" {
+		input := event.OpenPullRequest()
 
+		item := openedItem(input, "reopened", slackUsersMap, time.Now(), zapLog)
+		first := firstContribution(svc, input, zapLog)
+		if first {
+			item.ParentMessage += "\n" + firstContributionLine(messages.Language(), input.PullRequest.GetUser().GetLogin(), slackUsersMap)
 		}
 
-		if err := slack.SlackAddReaction(timeStamp, strings.ReplaceAll(emoji.Opened, ":", "")); err != nil {
-			zapLog.Error("error slack add reaction",
+		// a pull request outside the paths of SLACK_CHANNEL is still tracked,
+		// its events are copied to the routed destinations
+		parent := localizedParent(input, "reopened", item, first, slackUsersMap)
+		timeStamp, entry, err := out.Localized(parent).SendParentMessage(input, messages.ParentMessage(item, time.Now()))
+		if errors.Is(err, audit.ErrNotRouted) {
+			timeStamp, err = audit.Unrouted, nil
+		}
+		if err != nil {
+			zapLog.Error("error slack send message",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
-		svc := db.DynamoDbConnection()
-		item := &types.TablePullRequestData{
-			ID:             fmt.Sprintf("%d", input.PullRequest.ID),
-			PullRequestId:  input.Number,
-			SlackTimeStamp: timeStamp,
-		}
+		threadPosted(out, item, timeStamp, zapLog)
 
-		err = db.InsertItem(svc, item)
-		if err != nil {
+		if err := storeParent(svc, out, item, entry); err != nil {
 			zapLog.Error("error insert data",
 				zap.Error(err),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeError(w, err)
+			return
+		}
+		if err := openedThread(svc, out, timeStamp, input, slackUsersMap, zapLog); err != nil {
+			zapLog.Error("error slack send message",
+				zap.Error(err),
+			)
+			writeError(w, err)
 			return
 		}
 	}
 
+	writeWebhookResponse(w, "Webhook done.", githubEvent, action, trail)
+}
+
+func writeResponse(w http.ResponseWriter, message string) {
 	bodyBytes := Response{
-		Message: "Webhook done.",
+		Message: message,
 	}
 
 	j, err := json.Marshal(bodyBytes)
@@ -574,3 +875,232 @@ func PullRequestHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(j)
 }
+
+// body of a webhook response, shown in the recent deliveries of the GitHub
+// webhook to debug redeliveries: the Slack messages posted and the steps skipped
+type webhookResponse struct {
+	Message    string         `json:"message"`
+	Event      string         `json:"event,omitempty"`
+	Action     string         `json:"action"`
+	Recognized bool           `json:"recognized"`
+	Posted     []audit.Posted `json:"posted"`
+	Steps      []audit.Step   `json:"steps"`
+	Skipped    []string       `json:"skipped"`
+}
+
+func writeWebhookResponse(w http.ResponseWriter, message string, event string, action string, trail *audit.Trail) {
+	bodyBytes := webhookResponse{
+		Message:    message,
+		Event:      event,
+		Action:     action,
+		Recognized: handledAction(event, action) || securityEvent(event),
+		Posted:     trail.Posted(),
+		Steps:      trail.Steps(),
+		Skipped:    trail.Skipped(),
+	}
+
+	j, err := json.Marshal(bodyBytes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(j)
+}
+
+// pull requests opened before the webhook was set up have no parent message
+// emoji reaction on the parent message, an Unrouted pull request has none
+func addReaction(timeStamp string, emoji string) error {
+	if timeStamp == audit.Unrouted {
+		return nil
+	}
+	return slack.SlackAddReaction(timeStamp, strings.ReplaceAll(emoji, ":", ""))
+}
+
+func tracked(trail *audit.Trail, timeStamp string) bool {
+	if timeStamp == "" {
+		trail.Skip("pull request not tracked")
+		return false
+	}
+	return true
+}
+
+// thread message of a submitted review quoting its body, rendered in the
+// language of each destination
+func reviewMessage(input types.SubmitReviewPullRequest, key string, reviewEmoji string, slackUsersMap map[string]interface{}) func(language string) string {
+	return func(language string) string {
+		message := messages.In(language, key, slackUsersMap[input.Review.GetUser().GetLogin()], input.Review.GetHTMLURL(), reviewEmoji)
+		if len(input.Review.GetBody()) > 0 {
+			message += messages.Quote(input.Review.GetBody(), slackUsersMap)
+		}
+		return message
+	}
+}
+
+// "opened" or "reopened" notification of a parent message
+func openedMessage(language string, input types.OpenPullRequest, action string, slackUsersMap map[string]interface{}) string {
+	user := slackUsersMap[input.Sender.GetLogin()]
+	if input.Sender.GetLogin() == "dependabot[bot]" {
+		user = "dependabot[bot]"
+	}
+	return messages.In(language, action, user, constants.Emoji().Opened, input.PullRequest.GetHTMLURL(), input.Repository.GetName())
+}
+
+// parent message of a new record in the language of each destination, the
+// record only stores the notification text of SLACK_CHANNEL
+func localizedParent(input types.OpenPullRequest, action string, item *types.TablePullRequestData, firstContribution bool, slackUsersMap map[string]interface{}) func(language string) string {
+	return func(language string) string {
+		localized := *item
+		localized.ParentMessage = openedMessage(language, input, action, slackUsersMap)
+		if firstContribution {
+			localized.ParentMessage += "\n" + firstContributionLine(language, input.PullRequest.GetUser().GetLogin(), slackUsersMap)
+		}
+		return messages.ParentMessageIn(language, &localized, time.Now())
+	}
+}
+
+// record of a newly tracked pull request with its parent message text
+func openedItem(input types.OpenPullRequest, action string, slackUsersMap map[string]interface{}, createdAt time.Time, zapLog *zap.Logger) *types.TablePullRequestData {
+	messageText := openedMessage(messages.Language(), input, action, slackUsersMap)
+	files := changedFiles(input.Repository.GetName(), input.Number, zapLog)
+	checklistDone, checklistTotal := types.Checklist(input.PullRequest.GetBody())
+	return &types.TablePullRequestData{
+		ID:                 fmt.Sprintf("%d", input.PullRequest.GetID()),
+		PullRequestId:      input.Number,
+		Repository:         input.Repository.GetName(),
+		CreatedAt:          createdAt.Format(time.RFC3339),
+		AgeBadge:           messages.AgeBadge(createdAt.Format(time.RFC3339), time.Now()),
+		ParentMessage:      messageText,
+		RequiredApprovals:  requiredApprovals(input.Repository.GetName(), input.PullRequest.GetBase().GetRef(), zapLog),
+		HeadSha:            input.PullRequest.GetHead().GetSHA(),
+		RequiredChecks:     requiredChecks(input.Repository.GetName(), input.PullRequest.GetBase().GetRef(), zapLog),
+		Author:             input.PullRequest.GetUser().GetLogin(),
+		Labels:             types.LabelNames(input.PullRequest),
+		WorkInProgress:     types.WorkInProgress(input.PullRequest.GetTitle()),
+		Dependencies:       types.Dependencies(input.PullRequest.GetBody()),
+		ChangedDirectories: types.ChangedDirectories(files),
+		DiffClass:          diffClass(input.Repository.GetName(), files, zapLog),
+		ChecklistDone:      checklistDone,
+		ChecklistTotal:     checklistTotal,
+	}
+}
+
+// record of a new parent message, stored with its audit entry before any thread
+// message so a posted parent message is never left without its timestamp. A
+// failed transaction still writes the entry alone, the message can be found
+// in the audit log
+func storeParent(svc *awsdynamodb.DynamoDB, out audit.Messenger, item *types.TablePullRequestData, entry *types.TableAuditData) error {
+	// an Unrouted parent has no audit entry
+	if entry == nil {
+		return step(out.Trail, "dynamo.write", func() error {
+			return insertItem(svc, item)
+		})
+	}
+
+	err := step(out.Trail, "dynamo.write", func() error {
+		return insertItemAudited(svc, item, entry)
+	})
+	if err != nil {
+		out.Write(entry)
+		return err
+	}
+	return nil
+}
+
+// review request mention and opened reaction of a new parent message, sent
+// concurrently
+func openedThread(svc *awsdynamodb.DynamoDB, out audit.Messenger, timeStamp string, input types.OpenPullRequest, slackUsersMap map[string]interface{}, zapLog *zap.Logger) error {
+	emoji := constants.Emoji()
+
+	tasks := []func() error{
+		func() error {
+			return addReaction(timeStamp, emoji.Opened)
+		},
+		func() error {
+			postImages(out, timeStamp, input.PullRequest.GetBody(), input.PullRequest.GetHTMLURL(), "description", zapLog)
+			return nil
+		},
+	}
+
+	// WIP titles hold the review pings until the prefix is removed
+	if types.WorkInProgress(input.PullRequest.GetTitle()) {
+		out.Trail.Skip("work in progress")
+		return pool.Run(pool.Size(), tasks)
+	}
+
+	author := input.PullRequest.GetUser().GetLogin()
+
+	// and incomplete descriptions until the body is filled in
+	if incomplete, missing := incompleteDescription(input.Repository.GetName(), input.PullRequest.GetBody(), zapLog); incomplete {
+		out.Trail.Skip("incomplete description")
+		nudge := descriptionNudge(author, missing, slackUsersMap)
+		tasks = append(tasks, func() error {
+			return out.SendMessageThread(timeStamp, nudge)
+		})
+		return pool.Run(pool.Size(), tasks)
+	}
+
+	reviewers, self := withoutAuthor(requestedLogins(input.PullRequest), author)
+	if self {
+		notice := selfReviewNotice(author, len(reviewers) == 0, slackUsersMap)
+		tasks = append(tasks, func() error {
+			return out.SendMessageThread(timeStamp, notice)
+		})
+	}
+
+	if len(reviewers) > 0 {
+		ooo := outOfOffice(svc, zapLog)
+		entry := mail.Entry{Repository: input.Repository.GetName(), Number: input.Number, CreatedAt: types.FormatTime(input.PullRequest.CreatedAt)}
+		emailed := emailReviewRequest(out, reviewers, entry, slackUsersMap, ooo, time.Now(), zapLog)
+		alternates := reviewAlternates(input.Repository.GetName(), input.Number, reviewers, author, ooo, true, time.Now(), zapLog)
+		slackMention := reviewRequestMessage(reviewers, alternates, slackUsersMap, ooo, emailed, time.Now())
+		tasks = append(tasks, func() error {
+			return pingReviewers(svc, out, timeStamp, int(input.PullRequest.GetID()), input.Number, reviewers, slackMention, zapLog)
+		})
+	}
+
+	return pool.Run(pool.Size(), tasks)
+}
+
+// WEBHOOK_MAX_BYTES, GitHub caps payloads at 25 MB
+func maxWebhookBytes() int64 {
+	limit, err := strconv.ParseInt(env.GetEnv("WEBHOOK_MAX_BYTES", "26214400"), 10, 64)
+	if err != nil || limit <= 0 {
+		return 26214400
+	}
+	return limit
+}
+
+// JSON of the delivery, webhooks created with the `form` content type (the
+// default of the API, also on GitHub Enterprise Server) send it as `payload=`
+func webhookPayload(contentType string, body []byte) ([]byte, error) {
+	if !strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return body, nil
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrBadPayload, "webhook.form", err)
+	}
+	return []byte(values.Get("payload")), nil
+}
+
+// edit of the pull request itself. Edited review comments and reviews carry
+// the pull request too, but their changes are the ones of the comment
+func pullRequestEdit(event string, action string) bool {
+	return event == "pull_request" && action == "edited"
+}
+
+// per repository event allowlist, a broken config lets everything through
+func allowedEvent(event string, action string, repository string, zapLog *zap.Logger) bool {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		return true
+	}
+
+	return conf.Repo(repository).Allows(event, action)
+}
@@ -3,10 +3,16 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
 	"strings"
 	"testing"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
 )
 
 func TestPullRequestHandler(t *testing.T) {
@@ -32,9 +38,198 @@ func TestPullRequestHandler(t *testing.T) {
 			status, http.StatusOK)
 	}
 
-	expected := `{"message":"Webhook done."}`
+	expected := `{"message":"Webhook ignored.","action":"test","recognized":false,"posted":[],"steps":[],"skipped":["unhandled action"]}`
 	if !strings.Contains(rr.Body.String(), expected) {
 		t.Errorf("handler returned unexpected body: got %v want %v",
 			rr.Body.String(), expected)
 	}
 }
+
+func TestPullRequestHandlerIgnoredEvent(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"events": ["pull_request.closed"]}}}`)
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"action":     "opened",
+		"repository": map[string]string{"name": "api"},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal request body: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", "/", bytes.NewBuffer(requestBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-GitHub-Event", "pull_request")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(PullRequestHandler)
+
+	handler.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusOK)
+	}
+
+	expected := `{"message":"Webhook ignored.","event":"pull_request","action":"opened","recognized":true,"posted":[],"steps":[],"skipped":["not allowed by the repository config"]}`
+	if !strings.Contains(rr.Body.String(), expected) {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+
+	if value := droppedEvents.Value("opened"); value != 1 {
+		t.Errorf("Expected 1 dropped event, got %v", value)
+	}
+}
+
+func TestPullRequestNumber(t *testing.T) {
+	tests := map[string]int{
+		`{"number": 7}`:                                           7,
+		`{"pull_request": {"number": 8}}`:                         8,
+		`{"check_run": {"pull_requests": [{"number": 9}]}}`:       9,
+		`{"workflow_run": {"pull_requests": [{"number": 11}]}}`:   11,
+		`{"check_run": {"pull_requests": []}, "action": "other"}`: 0,
+	}
+
+	for body, expected := range tests {
+		var event types.WebhookEvent
+		if err := json.Unmarshal([]byte(body), &event); err != nil {
+			t.Fatal(err)
+		}
+		if number := event.PullRequestNumber(); number != expected {
+			t.Errorf("%s: expected %d, got %d", body, expected, number)
+		}
+	}
+}
+
+func TestPullRequestEdit(t *testing.T) {
+	if !pullRequestEdit("pull_request", "edited") {
+		t.Errorf("Expected a pull request edit")
+	}
+	for _, event := range []string{"pull_request_review_comment", "pull_request_review", ""} {
+		if pullRequestEdit(event, "edited") {
+			t.Errorf("Expected %q edits not to be pull request edits", event)
+		}
+	}
+	if pullRequestEdit("pull_request", "opened") {
+		t.Errorf("Expected only edits")
+	}
+}
+
+func TestWriteWebhookResponse(t *testing.T) {
+	trail := &audit.Trail{}
+	if tracked(trail, "") {
+		t.Errorf("Expected a pull request without parent message not to be tracked")
+	}
+	if !tracked(trail, "1.000001") {
+		t.Errorf("Expected a pull request with a parent message to be tracked")
+	}
+
+	rr := httptest.NewRecorder()
+	writeWebhookResponse(rr, "Webhook done.", "pull_request", "closed", trail)
+
+	expected := `{"message":"Webhook done.","event":"pull_request","action":"closed","recognized":true,"posted":[],"steps":[],"skipped":["pull request not tracked"]}`
+	if rr.Body.String() != expected {
+		t.Errorf("handler returned unexpected body: got %v want %v",
+			rr.Body.String(), expected)
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	body, err := webhookPayload("application/x-www-form-urlencoded", []byte(`payload=%7B%22action%22%3A%22opened%22%7D`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"action":"opened"}` {
+		t.Errorf("Expected the decoded payload, got %s", body)
+	}
+
+	body, _ = webhookPayload("application/json", []byte(`{"action":"closed"}`))
+	if string(body) != `{"action":"closed"}` {
+		t.Errorf("Expected the body as is, got %s", body)
+	}
+}
+
+func TestPullRequestHandlerUnauthenticated(t *testing.T) {
+	t.Setenv("WEBHOOK_TOKEN", "shared")
+
+	req, err := http.NewRequest("POST", "/", strings.NewReader(`{"action": "test"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Webhook-Token", "other")
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(PullRequestHandler).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusUnauthorized)
+	}
+}
+
+func TestPullRequestHandlerTooLarge(t *testing.T) {
+	t.Setenv("WEBHOOK_MAX_BYTES", "16")
+
+	req, err := http.NewRequest("POST", "/", strings.NewReader(`{"action": "test", "number": 1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(PullRequestHandler).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusRequestEntityTooLarge {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestStoreParent(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	var stored *types.TableAuditData
+	original := insertItemAudited
+	insertItemAudited = func(svc *awsdynamodb.DynamoDB, item *types.TablePullRequestData, entry *types.TableAuditData) error {
+		stored = entry
+		return errors.New("transaction canceled")
+	}
+	t.Cleanup(func() {
+		insertItemAudited = original
+	})
+
+	out := audit.Messenger{Repository: "api", Number: 7, Log: zap.NewNop()}
+	entry := &types.TableAuditData{PullRequest: "api#7", Type: "parent", TimeStamp: "1.000001"}
+
+	// the entry is written alone, the failure still fails the webhook
+	if err := storeParent(nil, out, &types.TablePullRequestData{SlackTimeStamp: "1.000001"}, entry); err == nil {
+		t.Error("Expected the transaction error")
+	}
+	if stored != entry {
+		t.Errorf("Expected the audit entry in the transaction, got %+v", stored)
+	}
+}
+
+func TestStoreParentUnrouted(t *testing.T) {
+	var inserted *types.TablePullRequestData
+	original := insertItem
+	insertItem = func(svc *awsdynamodb.DynamoDB, item *types.TablePullRequestData) error {
+		inserted = item
+		return nil
+	}
+	t.Cleanup(func() {
+		insertItem = original
+	})
+
+	// tracked without a parent message, its events reach the destinations
+	item := &types.TablePullRequestData{}
+	threadPosted(audit.Messenger{}, item, audit.Unrouted, zap.NewNop())
+	if err := storeParent(nil, audit.Messenger{}, item, nil); err != nil {
+		t.Fatal(err)
+	}
+	if inserted != item || inserted.SlackTimeStamp != audit.Unrouted || inserted.Permalink != "" {
+		t.Errorf("Expected the Unrouted record, got %+v", inserted)
+	}
+}
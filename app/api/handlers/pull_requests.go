@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slack-pr-lambda/api/dashboard"
+	db "slack-pr-lambda/dynamodb"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// read-only JSON of the tracked pull requests, `?repo=` narrows to a repository
+func PullRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	svc := db.DynamoDbConnection()
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		zapLog.Error("error list pull requests",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	repository := r.URL.Query().Get("repo")
+	requested := dashboard.RequestedReviewers(items, repository, zapLog)
+	pullRequests := dashboard.PullRequests(items, repository, requested, time.Now())
+
+	j, err := json.Marshal(pullRequests)
+	if err != nil {
+		zapLog.Error("error marshal pull requests",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(j)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPullRequestsHandler(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	t.Run("unauthorized", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		PullRequestsHandler(rr, httptest.NewRequest("GET", "/prs?repo=api", nil))
+
+		if status := rr.Code; status != http.StatusUnauthorized {
+			t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/prs?repo=api", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rr := httptest.NewRecorder()
+		PullRequestsHandler(rr, req)
+
+		// answers with the tracked pull requests from dynamodb-local, or fails without it
+		if status := rr.Code; status != http.StatusOK && status != http.StatusInternalServerError {
+			t.Errorf("handler returned unexpected status code: got %v", status)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/types"
+	"slices"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+var getRequiredChecks = github.GetRequiredChecks
+
+// check runs gating the ready to merge ping, the repository config wins over
+// the branch protection rule
+func requiredChecks(repo string, branch string, zapLog *zap.Logger) []string {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+	} else if required := conf.Repo(repo).RequiredChecks; len(required) > 0 {
+		return required
+	}
+
+	required, err := getRequiredChecks(repo, branch)
+	if err != nil {
+		zapLog.Warn("error get branch protection",
+			zap.String("repository", repo),
+			zap.String("branch", branch),
+			zap.Error(err),
+		)
+	}
+	return required
+}
+
+// outcome of a required check run on the head of the pull request is kept on
+// it, a pass may complete the ready to merge gate. The gate is evaluated on the
+// updated record so the last two checks finishing together see each other
+func requiredCheckCompleted(svc *awsdynamodb.DynamoDB, out audit.Messenger, id int, number int, run *gogithub.CheckRun) error {
+	item, err := db.GetPullRequest(svc, id, number)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(item.RequiredChecks, run.GetName()) {
+		return nil
+	}
+	if item.HeadSha != "" && run.GetHeadSHA() != item.HeadSha {
+		out.Trail.Skip("check run of an older head")
+		return nil
+	}
+
+	passed := checkPassed(run.GetConclusion())
+	updated, err := db.UpdatePassedCheck(svc, id, number, run.GetName(), run.GetHeadSHA(), passed)
+	if err != nil || !passed {
+		return err
+	}
+	if updated == nil {
+		out.Trail.Skip("check run of an older head")
+		return nil
+	}
+	return readyToMerge(svc, out, id, number, updated)
+}
+
+// skipped and neutral runs don't block the merge
+func checkPassed(conclusion string) bool {
+	switch conclusion {
+	case "success", "neutral", "skipped":
+		return true
+	}
+	return false
+}
+
+// single ping of the author once the required checks passed and the approvals
+// met the quorum. Without required checks the quorum message is enough
+func readyToMerge(svc *awsdynamodb.DynamoDB, out audit.Messenger, id int, number int, item *types.TablePullRequestData) error {
+	if item.ReadyToMergeAt != "" {
+		out.Trail.Skip("ready to merge already posted")
+		return nil
+	}
+	if len(item.RequiredChecks) == 0 {
+		out.Trail.Skip("no required checks")
+		return nil
+	}
+	if item.WorkInProgress {
+		out.Trail.Skip("work in progress")
+		return nil
+	}
+	if item.Approvals < item.RequiredApprovals {
+		out.Trail.Skip("approval quorum not met")
+		return nil
+	}
+	if len(pendingChecks(item)) > 0 {
+		out.Trail.Skip("required checks pending")
+		return nil
+	}
+
+	ready, err := db.MarkReadyToMerge(svc, id, number, time.Now().Format(time.RFC3339))
+	if err != nil || !ready {
+		return err
+	}
+	return out.SendMessageThread(item.SlackTimeStamp, readyToMergeMessage(item, mapstruct.StructToMap(*constants.SlackUsers())))
+}
+
+// required checks that did not pass on the head yet
+func pendingChecks(item *types.TablePullRequestData) []string {
+	pending := []string{}
+	for _, check := range item.RequiredChecks {
+		if !slices.Contains(item.PassedChecks, check) {
+			pending = append(pending, check)
+		}
+	}
+	return pending
+}
+
+func readyToMergeMessage(item *types.TablePullRequestData, slackUsersMap map[string]interface{}) string {
+	emoji := constants.Emoji()
+
+	author := fmt.Sprintf("`%s`", item.Author)
+	if user, ok := slackUsersMap[item.Author]; ok {
+		author = fmt.Sprintf("<@%s>", user)
+	}
+	return fmt.Sprintf("%s %s ready to merge: required checks passed and approvals met the quorum.", emoji.ReadyToMerge, author)
+}
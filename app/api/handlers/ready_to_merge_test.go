@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"reflect"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRequiredChecks(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"requiredChecks": ["build"]}}}`)
+
+	original := getRequiredChecks
+	getRequiredChecks = func(repo string, branch string) ([]string, error) {
+		return []string{"test", "lint"}, nil
+	}
+	t.Cleanup(func() {
+		getRequiredChecks = original
+	})
+
+	zapLog := zap.NewNop()
+	if result := requiredChecks("api", "main", zapLog); !reflect.DeepEqual(result, []string{"build"}) {
+		t.Errorf("got %v want [build]", result)
+	}
+	if result := requiredChecks("web", "main", zapLog); !reflect.DeepEqual(result, []string{"test", "lint"}) {
+		t.Errorf("got %v want [test lint]", result)
+	}
+}
+
+func TestReadyToMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    types.TablePullRequestData
+		skipped string
+	}{
+		{
+			name:    "already posted",
+			item:    types.TablePullRequestData{ReadyToMergeAt: "2024-03-08T12:00:00Z", RequiredChecks: []string{"build"}},
+			skipped: "ready to merge already posted",
+		},
+		{
+			name:    "unprotected branch",
+			item:    types.TablePullRequestData{Approvals: 1, RequiredApprovals: 1},
+			skipped: "no required checks",
+		},
+		{
+			name:    "work in progress",
+			item:    types.TablePullRequestData{RequiredChecks: []string{"build"}, PassedChecks: []string{"build"}, WorkInProgress: true},
+			skipped: "work in progress",
+		},
+		{
+			name:    "missing approval",
+			item:    types.TablePullRequestData{Approvals: 1, RequiredApprovals: 2, RequiredChecks: []string{"build"}, PassedChecks: []string{"build"}},
+			skipped: "approval quorum not met",
+		},
+		{
+			name:    "check pending",
+			item:    types.TablePullRequestData{Approvals: 2, RequiredApprovals: 2, RequiredChecks: []string{"build", "test"}, PassedChecks: []string{"build"}},
+			skipped: "required checks pending",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trail := &audit.Trail{}
+			out := audit.Messenger{Repository: "api", Log: zap.NewNop(), Trail: trail}
+
+			if err := readyToMerge(nil, out, 1, 2, &tt.item); err != nil {
+				t.Fatal(err)
+			}
+			if skipped := trail.Skipped(); len(skipped) != 1 || skipped[0] != tt.skipped {
+				t.Errorf("Expected %q to be skipped, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+}
+
+func TestCheckPassed(t *testing.T) {
+	for conclusion, expected := range map[string]bool{"success": true, "skipped": true, "neutral": true, "failure": false, "cancelled": false, "timed_out": false} {
+		if result := checkPassed(conclusion); result != expected {
+			t.Errorf("%s: got %v want %v", conclusion, result, expected)
+		}
+	}
+}
+
+func TestReadyToMergeMessage(t *testing.T) {
+	item := &types.TablePullRequestData{Author: "alice"}
+
+	if message := readyToMergeMessage(item, map[string]interface{}{"alice": "U1"}); message != ":rocket: <@U1> ready to merge: required checks passed and approvals met the quorum." {
+		t.Errorf("got %q", message)
+	}
+	if message := readyToMergeMessage(item, map[string]interface{}{}); message != ":rocket: `alice` ready to merge: required checks passed and approvals met the quorum." {
+		t.Errorf("got %q", message)
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/env"
+
+	"go.uber.org/zap"
+)
+
+// PANIC_RESPONSE, retry answers a panicking action with 500 so GitHub
+// redelivers it, ack (the default) answers 200 so a deterministic bug doesn't
+// turn into a retry storm
+func retryPanics() bool {
+	return env.GetEnv("PANIC_RESPONSE", "ack") == "retry"
+}
+
+// deferred around the action processors, a panic is logged with its stack and
+// counted instead of crashing the lambda. Nothing is written when the response
+// was already sent
+func recoverPanic(w *failureWriter, event string, action string, trail *audit.Trail, zapLog *zap.Logger) {
+	p := recover()
+	if p == nil {
+		return
+	}
+
+	webhookPanics.Inc(action)
+	zapLog.Error("panic in action",
+		zap.String("event", event),
+		zap.String("action", action),
+		zap.String("panic", fmt.Sprint(p)),
+		zap.ByteString("stack", debug.Stack()),
+	)
+
+	if w.status != 0 {
+		return
+	}
+	if retryPanics() {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	trail.Skip("action panicked")
+	writeWebhookResponse(w, "Webhook failed.", event, action, trail)
+}
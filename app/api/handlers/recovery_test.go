@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/audit"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func panicking(w *failureWriter, action string) {
+	defer recoverPanic(w, "pull_request", action, &audit.Trail{}, zap.NewNop())
+	panic("nil map")
+}
+
+func TestRecoverPanic(t *testing.T) {
+	rr := httptest.NewRecorder()
+	panicking(&failureWriter{ResponseWriter: rr}, "labeled")
+
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"skipped":["action panicked"]`) {
+		t.Errorf("Expected the webhook to be acknowledged, got %v %s", rr.Code, rr.Body.String())
+	}
+	if value := webhookPanics.Value("labeled"); value != 1 {
+		t.Errorf("Expected 1 panic, got %v", value)
+	}
+
+	t.Setenv("PANIC_RESPONSE", "retry")
+	rr = httptest.NewRecorder()
+	panicking(&failureWriter{ResponseWriter: rr}, "unlabeled")
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 so GitHub retries, got %v", rr.Code)
+	}
+}
+
+func TestRecoverPanicAnswered(t *testing.T) {
+	rr := httptest.NewRecorder()
+	w := &failureWriter{ResponseWriter: rr}
+	func() {
+		defer recoverPanic(w, "pull_request", "closed", &audit.Trail{}, zap.NewNop())
+		w.WriteHeader(http.StatusAccepted)
+		panic("after the response")
+	}()
+
+	if rr.Code != http.StatusAccepted || rr.Body.Len() != 0 {
+		t.Errorf("Expected the response to be kept, got %v %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errNoParentMessage = errors.New("no parent message stored for this pull request")
+
+// replaced in tests
+var resendPullRequest = adminPullRequest
+
+var updateSlackTimeStamp = func(id int, pullRequestId int, oldSlackTimeStamp string, slackTimeStamp string) error {
+	return db.UpdateSlackTimeStamp(db.DynamoDbConnection(), id, pullRequestId, oldSlackTimeStamp, slackTimeStamp)
+}
+
+var deleteResentParent = slack.SlackDeleteMessage
+
+// re-post the parent message of a pull request, later events are threaded
+// under the new message. Concurrent re-posts keep the first stored one
+func resendParent(out audit.Messenger, item *types.TablePullRequestData, zapLog *zap.Logger) (string, error) {
+	if item.ParentMessage == "" {
+		return "", errNoParentMessage
+	}
+
+	id, err := strconv.Atoi(item.ID)
+	if err != nil {
+		return "", err
+	}
+
+	timeStamp, err := out.SendMessage(types.OpenPullRequest{}, messages.ParentMessage(item, time.Now()))
+	if err != nil {
+		return "", err
+	}
+	err = updateSlackTimeStamp(id, item.PullRequestId, item.SlackTimeStamp, timeStamp)
+	if errors.Is(err, db.ErrParentReposted) {
+		// another delivery re-posted it first, its message is the one kept
+		if err := deleteResentParent(timeStamp); err != nil {
+			zapLog.Warn("error slack delete message",
+				zap.String("timeStamp", timeStamp),
+				zap.Error(err),
+			)
+		}
+		current, err := resendPullRequest(item.Repository, item.PullRequestId)
+		if err != nil {
+			return "", err
+		}
+		return current.SlackTimeStamp, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// the link of the old parent message is dead
+	permalink := threadPermalink(timeStamp, zapLog)
+	if err := db.UpdatePermalink(db.DynamoDbConnection(), id, item.PullRequestId, permalink); err != nil {
+		zapLog.Warn("error update permalink",
+			zap.Error(err),
+		)
+	}
+	threadComment(out, item.Repository, item.PullRequestId, permalink, zapLog)
+	return timeStamp, nil
+}
+
+// Resend hook of the messenger, a reply to a parent message deleted in Slack
+// re-posts it. The record is read again so the later replies of the event, or
+// a delivery that re-posted it in the meantime, continue under the stored
+// timestamp instead of posting another parent message
+func parentResender(out audit.Messenger, zapLog *zap.Logger) func(timeStamp string) (string, error) {
+	if out.Repository == "" || out.Number == 0 {
+		return nil
+	}
+
+	return func(timeStamp string) (string, error) {
+		item, err := resendPullRequest(out.Repository, out.Number)
+		if err != nil {
+			return "", err
+		}
+		if item.SlackTimeStamp != timeStamp && item.SlackTimeStamp != "" {
+			return item.SlackTimeStamp, nil
+		}
+
+		zapLog.Info("resend deleted parent message",
+			zap.String("pullRequest", audit.PullRequestKey(out.Repository, out.Number)),
+			zap.String("timeStamp", timeStamp),
+		)
+		return resendParent(out, item, zapLog)
+	}
+}
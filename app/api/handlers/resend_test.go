@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func stubResendPullRequest(t *testing.T, item *types.TablePullRequestData) {
+	original := resendPullRequest
+	resendPullRequest = func(repository string, number int) (*types.TablePullRequestData, error) {
+		return item, nil
+	}
+	t.Cleanup(func() {
+		resendPullRequest = original
+	})
+}
+
+func TestParentResender(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+	stubThreadLinks(t, "", nil)
+
+	out := audit.Messenger{Source: "created", Repository: "api", Number: 7, Log: zap.NewNop()}
+
+	t.Run("without pull request", func(t *testing.T) {
+		if resend := parentResender(audit.Messenger{Source: "dashboard"}, zap.NewNop()); resend != nil {
+			t.Errorf("Expected no resend hook")
+		}
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		stubResendPullRequest(t, &types.TablePullRequestData{ID: "42", PullRequestId: 7, Repository: "api", SlackTimeStamp: "1.000001", ParentMessage: "Opened pull request"})
+
+		timeStamp, err := parentResender(out, zap.NewNop())("1.000001")
+		if err != nil || timeStamp != "dry-run" {
+			t.Errorf("Expected the parent message re-posted, got %q %v", timeStamp, err)
+		}
+	})
+
+	t.Run("already resent", func(t *testing.T) {
+		stubResendPullRequest(t, &types.TablePullRequestData{ID: "42", PullRequestId: 7, Repository: "api", SlackTimeStamp: "1.000009", ParentMessage: "Opened pull request"})
+
+		timeStamp, err := parentResender(out, zap.NewNop())("1.000001")
+		if err != nil || timeStamp != "1.000009" {
+			t.Errorf("Expected the stored parent message, got %q %v", timeStamp, err)
+		}
+	})
+
+	t.Run("no parent message", func(t *testing.T) {
+		stubResendPullRequest(t, &types.TablePullRequestData{ID: "42", PullRequestId: 7, Repository: "api", SlackTimeStamp: "1.000001"})
+
+		if _, err := parentResender(out, zap.NewNop())("1.000001"); !errors.Is(err, errNoParentMessage) {
+			t.Errorf("Expected no parent message, got %v", err)
+		}
+	})
+}
+
+func TestResendParentConcurrent(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+	stubThreadLinks(t, "", nil)
+	stubResendPullRequest(t, &types.TablePullRequestData{ID: "42", PullRequestId: 7, Repository: "api", SlackTimeStamp: "1.000009"})
+
+	deleted := []string{}
+	originalUpdate, originalDelete := updateSlackTimeStamp, deleteResentParent
+	updateSlackTimeStamp = func(id int, pullRequestId int, oldSlackTimeStamp string, slackTimeStamp string) error {
+		return db.ErrParentReposted
+	}
+	deleteResentParent = func(timeStamp string) error {
+		deleted = append(deleted, timeStamp)
+		return nil
+	}
+	t.Cleanup(func() {
+		updateSlackTimeStamp, deleteResentParent = originalUpdate, originalDelete
+	})
+
+	out := audit.Messenger{Source: "created", Repository: "api", Number: 7, Log: zap.NewNop()}
+	item := &types.TablePullRequestData{ID: "42", PullRequestId: 7, Repository: "api", SlackTimeStamp: "1.000001", ParentMessage: "Opened pull request"}
+
+	timeStamp, err := resendParent(out, item, zap.NewNop())
+	if err != nil || timeStamp != "1.000009" {
+		t.Errorf("Expected the parent message of the other delivery, got %q %v", timeStamp, err)
+	}
+	if len(deleted) != 1 || deleted[0] != "dry-run" {
+		t.Errorf("Expected the duplicate parent message deleted, got %v", deleted)
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// COMMENT_BATCH_SECONDS, inline comments of a review arrive as a burst of deliveries
+func commentBatchWindow() time.Duration {
+	seconds, err := strconv.Atoi(env.GetEnv("COMMENT_BATCH_SECONDS", "10"))
+	if err != nil || seconds < 0 {
+		seconds = 10
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// inline review comments of a reviewer within the batch window share one thread
+// reply, edited as comments arrive ("left 7 review comments on 3 files"). Past
+// the rollup threshold new batches are left to the rollup job
+func reviewComment(svc *awsdynamodb.DynamoDB, out audit.Messenger, timeStamp string, event types.WebhookEvent, slackUsersMap map[string]interface{}, now time.Time) error {
+	login := event.Comment.GetUser().GetLogin()
+	id := fmt.Sprintf("%s#%s", audit.PullRequestKey(event.Repository.GetName(), event.PullRequest.GetNumber()), login)
+
+	windowStart := now.Add(-commentBatchWindow()).Unix()
+
+	batch, err := db.AddToCommentBatch(svc, id, event.Comment.GetPath(), windowStart)
+	if err == nil {
+		return updateCommentBatch(out, batch, event, slackUsersMap)
+	}
+	if !errors.Is(err, db.ErrCommentBatchClosed) {
+		return err
+	}
+
+	// a new batch is a comment notification of the thread
+	rolledUp, err := rollupComment(svc, int(event.PullRequest.GetID()), event.PullRequest.GetNumber(), event.Repository.GetName(), now)
+	if err != nil || rolledUp {
+		return err
+	}
+
+	// the batch is claimed before the reply so the other comments of the burst
+	// are counted in it instead of posting their own reply
+	err = db.ClaimCommentBatch(svc, &types.TableCommentBatchData{
+		ID:        id,
+		StartedAt: now.Unix(),
+		Comments:  1,
+		Files:     []string{event.Comment.GetPath()},
+	}, windowStart)
+	if errors.Is(err, db.ErrCommentBatchOpen) {
+		batch, err := db.AddToCommentBatch(svc, id, event.Comment.GetPath(), windowStart)
+		if err != nil {
+			return err
+		}
+		return updateCommentBatch(out, batch, event, slackUsersMap)
+	}
+	if err != nil {
+		return err
+	}
+
+	message := func(language string) string {
+		return commentBatchMessage(language, slackUsersMap, event, 1, 1)
+	}
+	reply, err := out.Localized(message).Reply(timeStamp, message(messages.Language()))
+	if err != nil {
+		return errors.Join(err, db.DeleteCommentBatch(svc, id))
+	}
+
+	batch, err = db.UpdateCommentBatchTimeStamp(svc, id, reply)
+	if err != nil || batch.Comments <= 1 {
+		return err
+	}
+	return out.UpdateMessage(reply, commentBatchMessage(messages.Language(), slackUsersMap, event, batch.Comments, len(batch.Files)))
+}
+
+// the reply of the batch counts the comment, the first comment of the batch
+// posting the reply counts it once the reply is stored
+func updateCommentBatch(out audit.Messenger, batch *types.TableCommentBatchData, event types.WebhookEvent, slackUsersMap map[string]interface{}) error {
+	if batch.SlackTimeStamp == "" {
+		return nil
+	}
+	return out.UpdateMessage(batch.SlackTimeStamp, commentBatchMessage(messages.Language(), slackUsersMap, event, batch.Comments, len(batch.Files)))
+}
+
+// a single comment is quoted, a batch is summarized with a link to the changes
+func commentBatchMessage(language string, slackUsersMap map[string]interface{}, event types.WebhookEvent, comments int, files int) string {
+	emoji := constants.Emoji()
+	user := slackUsersMap[event.Comment.GetUser().GetLogin()]
+
+	if comments <= 1 {
+		message := messages.In(language, "comment.review", user, emoji.Comment, event.Comment.GetHTMLURL(), event.Comment.GetPath())
+		if len(event.Comment.GetBody()) > 0 {
+			message += messages.Quote(event.Comment.GetBody(), slackUsersMap)
+		}
+		return message
+	}
+
+	key := "comment.review.batch"
+	if files == 1 {
+		key = "comment.review.batch_one"
+	}
+	return messages.In(language, key, user, emoji.Comment, comments, event.PullRequest.GetHTMLURL(), files)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestCommentBatchWindow(t *testing.T) {
+	if window := commentBatchWindow(); window != 10*time.Second {
+		t.Errorf("Expected the default window, got %v", window)
+	}
+
+	t.Setenv("COMMENT_BATCH_SECONDS", "30")
+	if window := commentBatchWindow(); window != 30*time.Second {
+		t.Errorf("Expected 30s, got %v", window)
+	}
+}
+
+func TestCommentBatchMessage(t *testing.T) {
+	var event types.WebhookEvent
+	body := `{"action": "created", "comment": {"html_url": "https://github.com/o/api/pull/7#r1", "body": "nit @bob", "path": "main.go", "user": {"login": "alice"}}, "pull_request": {"number": 7, "html_url": "https://github.com/o/api/pull/7"}}`
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	slackUsersMap := map[string]interface{}{"alice": "UA", "bob": "UB"}
+
+	single := commentBatchMessage("en", slackUsersMap, event, 1, 1)
+	expected := "<@UA> :writing_hand: left a review <https://github.com/o/api/pull/7#r1|comment> on `main.go`. \n```nit @bob```\ncc <@UB>\n"
+	if single != expected {
+		t.Errorf("got %q want %q", single, expected)
+	}
+
+	batch := commentBatchMessage("en", slackUsersMap, event, 7, 3)
+	expected = "<@UA> :writing_hand: left 7 review <https://github.com/o/api/pull/7/files|comments> on 3 files."
+	if batch != expected {
+		t.Errorf("got %q want %q", batch, expected)
+	}
+}
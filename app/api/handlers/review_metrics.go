@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type reviewMetricsRow struct {
+	Repository             string   `json:"repository"`
+	Number                 int      `json:"number"`
+	Author                 string   `json:"author"`
+	OpenedAt               string   `json:"openedAt"`
+	FirstReviewAt          string   `json:"firstReviewAt"`
+	MergedAt               string   `json:"mergedAt"`
+	ClosedAt               string   `json:"closedAt"`
+	State                  string   `json:"state"`
+	Reviewers              []string `json:"reviewers"`
+	TimeToFirstReviewHours *float64 `json:"timeToFirstReviewHours"`
+	TimeToMergeHours       *float64 `json:"timeToMergeHours"`
+}
+
+var reviewMetricsHeader = []string{"repository", "number", "author", "openedAt", "firstReviewAt", "mergedAt", "closedAt", "reviewers", "timeToFirstReviewHours", "timeToMergeHours", "state"}
+
+// lifecycle timestamps of the pull request, kept once it is closed for the
+// review metrics export. Only handled events are recorded
+func recordReviewMetrics(w *failureWriter, event types.WebhookEvent, zapLog *zap.Logger) {
+	repository := event.Repository.GetName()
+	if w.status >= 400 || repository == "" {
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	svc := db.DynamoDbConnection()
+
+	var err error
+	switch event.Action {
+	case "opened", "reopened":
+		input := event.OpenPullRequest()
+		err = db.RecordOpened(svc, audit.PullRequestKey(repository, input.Number), repository, input.Number, input.PullRequest.GetUser().GetLogin(), orDefault(types.FormatTime(input.PullRequest.CreatedAt), now))
+	case "submitted":
+		input := event.SubmitReviewPullRequest()
+		// answering review comments on your own pull request is not a review
+		if input.Review.GetUser().GetLogin() == input.PullRequest.GetUser().GetLogin() {
+			return
+		}
+		err = db.RecordReview(svc, audit.PullRequestKey(repository, input.PullRequest.GetNumber()), input.Review.GetUser().GetLogin(), orDefault(types.FormatTime(input.Review.SubmittedAt), now))
+	case "closed":
+		input := event.ClosedPullRequest()
+		err = db.RecordClosed(svc, audit.PullRequestKey(repository, input.Number), repository, input.Number, orDefault(types.FormatTime(input.PullRequest.ClosedAt), now), types.FormatTime(input.PullRequest.MergedAt))
+	}
+
+	if err != nil {
+		zapLog.Error("error record review metrics",
+			zap.Error(err),
+		)
+	}
+}
+
+func orDefault(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// per pull request lifecycle metrics as CSV (default) or ?format=json, filtered
+// with ?repo= and ?from= / ?to= (YYYY-MM-DD, on the opening date, to excluded)
+func AdminReviewMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format != "" && format != "csv" && format != "json" {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	var from, to time.Time
+	for _, bound := range []struct {
+		name  string
+		value *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if raw := query.Get(bound.name); raw != "" {
+			parsed, err := time.Parse(time.DateOnly, raw)
+			if err != nil {
+				http.Error(w, "Bad Request", http.StatusBadRequest)
+				return
+			}
+			*bound.value = parsed
+		}
+	}
+
+	records, err := db.ListReviewMetrics(db.DynamoDbConnection())
+	if err != nil {
+		zapLog.Error("error list review metrics",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	rows := reviewMetricsRows(records, query.Get("repo"), from, to)
+
+	if format == "json" {
+		j, err := json.Marshal(rows)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(j)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="review-metrics.csv"`)
+	w.WriteHeader(http.StatusOK)
+	if err := writeReviewMetricsCSV(w, rows); err != nil {
+		zapLog.Error("error write review metrics",
+			zap.Error(err),
+		)
+	}
+}
+
+// rows opened within [from, to), a zero bound is open, oldest first
+func reviewMetricsRows(records []types.TableReviewMetricsData, repository string, from time.Time, to time.Time) []reviewMetricsRow {
+	rows := []reviewMetricsRow{}
+	for _, record := range records {
+		if repository != "" && record.Repository != repository {
+			continue
+		}
+
+		openedAt, err := time.Parse(time.RFC3339, record.OpenedAt)
+		if err != nil {
+			// reviewed or closed before the opening was recorded
+			continue
+		}
+		if (!from.IsZero() && openedAt.Before(from)) || (!to.IsZero() && !openedAt.Before(to)) {
+			continue
+		}
+
+		reviewers := append([]string{}, record.Reviewers...)
+		sort.Strings(reviewers)
+
+		rows = append(rows, reviewMetricsRow{
+			Repository:             record.Repository,
+			Number:                 record.Number,
+			Author:                 record.Author,
+			OpenedAt:               record.OpenedAt,
+			FirstReviewAt:          record.FirstReviewAt,
+			MergedAt:               record.MergedAt,
+			ClosedAt:               record.ClosedAt,
+			State:                  db.ReviewMetricsState(record),
+			Reviewers:              reviewers,
+			TimeToFirstReviewHours: hoursSince(openedAt, record.FirstReviewAt),
+			TimeToMergeHours:       hoursSince(openedAt, record.MergedAt),
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].OpenedAt < rows[j].OpenedAt
+	})
+	return rows
+}
+
+// rounded to a tenth of an hour, nil when end is not set
+func hoursSince(start time.Time, end string) *float64 {
+	parsed, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return nil
+	}
+
+	hours := float64(parsed.Sub(start).Round(6*time.Minute)) / float64(time.Hour)
+	return &hours
+}
+
+func writeReviewMetricsCSV(w io.Writer, rows []reviewMetricsRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reviewMetricsHeader); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		err := writer.Write([]string{
+			row.Repository,
+			fmt.Sprintf("%d", row.Number),
+			row.Author,
+			row.OpenedAt,
+			row.FirstReviewAt,
+			row.MergedAt,
+			row.ClosedAt,
+			strings.Join(row.Reviewers, ";"),
+			formatHours(row.TimeToFirstReviewHours),
+			formatHours(row.TimeToMergeHours),
+			row.State,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatHours(hours *float64) string {
+	if hours == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", *hours)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewMetricsRows(t *testing.T) {
+	records := []types.TableReviewMetricsData{
+		{Repository: "api", Number: 2, Author: "alice", OpenedAt: "2024-03-02T10:00:00Z", Reviewers: []string{"dave", "bob"}, FirstReviewAt: "2024-03-02T13:30:00Z", MergedAt: "2024-03-03T10:00:00Z", ClosedAt: "2024-03-03T10:00:00Z"},
+		{Repository: "api", Number: 1, Author: "bob", OpenedAt: "2024-03-01T10:00:00Z"},
+		{Repository: "web", Number: 3, OpenedAt: "2024-03-01T11:00:00Z"},
+		{Repository: "api", Number: 4, OpenedAt: "2024-04-01T00:00:00Z"},
+		{Repository: "api", Number: 5, FirstReviewAt: "2024-03-02T13:30:00Z"},
+	}
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	rows := reviewMetricsRows(records, "api", from, to)
+
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, 1, rows[0].Number)
+		assert.Nil(t, rows[0].TimeToFirstReviewHours)
+		assert.Equal(t, []string{"bob", "dave"}, rows[1].Reviewers)
+		assert.Equal(t, 3.5, *rows[1].TimeToFirstReviewHours)
+		assert.Equal(t, 24.0, *rows[1].TimeToMergeHours)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeReviewMetricsCSV(&buf, rows))
+	expected := "repository,number,author,openedAt,firstReviewAt,mergedAt,closedAt,reviewers,timeToFirstReviewHours,timeToMergeHours,state\n" +
+		"api,1,bob,2024-03-01T10:00:00Z,,,,,,,open\n" +
+		"api,2,alice,2024-03-02T10:00:00Z,2024-03-02T13:30:00Z,2024-03-03T10:00:00Z,2024-03-03T10:00:00Z,bob;dave,3.5,24.0,merged\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestAdminReviewMetricsHandler(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	tests := []struct {
+		name   string
+		url    string
+		token  string
+		status int
+	}{
+		{"unauthorized", "/admin/review-metrics", "", http.StatusUnauthorized},
+		{"format", "/admin/review-metrics?format=xml", "secret", http.StatusBadRequest},
+		{"date", "/admin/review-metrics?from=03/01/2024", "secret", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(AdminReviewMetricsHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+}
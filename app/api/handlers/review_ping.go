@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"sort"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+var addReviewPings = db.AddReviewPings
+
+var removeReviewPings = db.RemoveReviewPings
+
+var deleteReviewPing = slack.SlackDeleteMessage
+
+// posts the "Please review" ping and keeps its timestamp per reviewer so it is
+// cleaned up once they review. Failing to keep it only leaves the ping
+func pingReviewers(svc *awsdynamodb.DynamoDB, out audit.Messenger, timeStamp string, id int, number int, reviewers []string, message string, zapLog *zap.Logger) error {
+	reply, err := out.Reply(timeStamp, message)
+	if err != nil || reply == "" {
+		return err
+	}
+
+	if err := addReviewPings(svc, id, number, reviewers, reply); err != nil {
+		zapLog.Warn("error add review pings",
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// a submitted review takes the reviewer out of their pings, pings still waiting
+// on other reviewers are edited to name only them and the others are deleted.
+// Slack errors are only logged, the review itself was already posted
+func clearReviewPings(svc *awsdynamodb.DynamoDB, out audit.Messenger, id int, number int, reviewer string, slackUsersMap map[string]interface{}, zapLog *zap.Logger) error {
+	removed, remaining, err := removeReviewPings(svc, id, number, reviewer)
+	if errors.Is(err, db.ErrNoDataFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	updates, deletes := reviewPingCleanup(removed, remaining)
+	// the edited ping keeps the out of office and email notes of the others,
+	// they were already emailed with the request
+	ooo := map[string]types.TableOutOfOfficeData{}
+	if len(updates) > 0 {
+		ooo = outOfOffice(svc, zapLog)
+	}
+	for timeStamp, logins := range updates {
+		now := time.Now()
+		emailed := emailRecipients(logins, slackUsersMap, ooo, now, zapLog)
+		if err := out.UpdateMessage(timeStamp, reviewRequestMessage(logins, nil, slackUsersMap, ooo, emailed, now)); err != nil {
+			zapLog.Warn("error update review ping",
+				zap.Error(err),
+			)
+		}
+	}
+	for _, timeStamp := range deletes {
+		if err := deleteReviewPing(timeStamp); err != nil {
+			zapLog.Warn("error delete review ping",
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// pings of the removed entries still waiting on other logins, by timestamp,
+// and those nobody waits on anymore
+func reviewPingCleanup(removed []string, remaining []string) (map[string][]string, []string) {
+	waiting := map[string][]string{}
+	for _, ping := range remaining {
+		login, timeStamp := db.ParseReviewPing(ping)
+		waiting[timeStamp] = append(waiting[timeStamp], login)
+	}
+
+	updates := map[string][]string{}
+	deletes := []string{}
+	for _, ping := range removed {
+		_, timeStamp := db.ParseReviewPing(ping)
+		if logins, ok := waiting[timeStamp]; ok {
+			sort.Strings(logins)
+			updates[timeStamp] = logins
+		} else {
+			deletes = append(deletes, timeStamp)
+		}
+	}
+	sort.Strings(deletes)
+	return updates, deletes
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"testing"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestReviewPingCleanup(t *testing.T) {
+	removed := []string{"bob=1.1", "bob=1.2"}
+	remaining := []string{"dave=1.1", "carol=1.1", "erin=1.3"}
+
+	updates, deletes := reviewPingCleanup(removed, remaining)
+	assert.Equal(t, map[string][]string{"1.1": {"carol", "dave"}}, updates)
+	assert.Equal(t, []string{"1.2"}, deletes)
+}
+
+func TestClearReviewPings(t *testing.T) {
+	deleted := []string{}
+	originalRemove, originalDelete := removeReviewPings, deleteReviewPing
+	deleteReviewPing = func(timeStamp string) error {
+		deleted = append(deleted, timeStamp)
+		return errors.New("message_not_found")
+	}
+	t.Cleanup(func() {
+		removeReviewPings, deleteReviewPing = originalRemove, originalDelete
+	})
+	out := audit.Messenger{Repository: "api", Log: zap.NewNop(), Trail: &audit.Trail{}}
+
+	t.Run("deleted", func(t *testing.T) {
+		deleted = []string{}
+		removeReviewPings = func(svc *awsdynamodb.DynamoDB, id int, pullRequestId int, login string) ([]string, []string, error) {
+			return []string{login + "=1.2"}, nil, nil
+		}
+
+		// failing to delete the ping doesn't fail the review
+		assert.NoError(t, clearReviewPings(nil, out, 1, 2, "bob", map[string]interface{}{}, zap.NewNop()))
+		assert.Equal(t, []string{"1.2"}, deleted)
+	})
+
+	t.Run("untracked", func(t *testing.T) {
+		deleted = []string{}
+		removeReviewPings = func(svc *awsdynamodb.DynamoDB, id int, pullRequestId int, login string) ([]string, []string, error) {
+			return nil, nil, db.ErrNoDataFound
+		}
+
+		assert.NoError(t, clearReviewPings(nil, out, 1, 2, "bob", map[string]interface{}{}, zap.NewNop()))
+		assert.Empty(t, deleted)
+	})
+}
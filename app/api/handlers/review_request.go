@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"fmt"
+	"slack-pr-lambda/api/mail"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/reviewers"
+	"slack-pr-lambda/types"
+	"strings"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+// number of alternates suggested when requested reviewers are out of office
+const alternateReviewers = 2
+
+// out of office records, a failed lookup only means nobody gets skipped
+func outOfOffice(svc *awsdynamodb.DynamoDB, zapLog *zap.Logger) map[string]types.TableOutOfOfficeData {
+	ooo, err := db.ListOutOfOffice(svc)
+	if err != nil {
+		zapLog.Warn("error list out of office",
+			zap.Error(err),
+		)
+		return map[string]types.TableOutOfOfficeData{}
+	}
+	return ooo
+}
+
+// reviewers other than the author, requested is true when the author asked
+// for their own review
+func withoutAuthor(logins []string, author string) (others []string, requested bool) {
+	others = []string{}
+	for _, login := range logins {
+		if login == author {
+			requested = true
+			continue
+		}
+		others = append(others, login)
+	}
+	return others, requested
+}
+
+// logins of the requested reviewers, teams are not part of them
+func requestedLogins(pr *gogithub.PullRequest) []string {
+	logins := []string{}
+	for _, reviewer := range pr.RequestedReviewers {
+		logins = append(logins, reviewer.GetLogin())
+	}
+	return logins
+}
+
+// thread notice for an author who requested themselves, they are not pinged
+// as a reviewer
+func selfReviewNotice(author string, onlyReviewer bool, slackUsersMap map[string]interface{}) string {
+	text := fmt.Sprintf("Heads up %s, authors can't review their own pull request so you were left out of the review request.", mail.Mention(author, slackUsersMap, nil))
+	if onlyReviewer {
+		text += " Please request another reviewer."
+	}
+	return text
+}
+
+// the available reviewers who opted into email or have no Slack mapping, with
+// their address
+func emailRecipients(logins []string, slackUsersMap map[string]interface{}, ooo map[string]types.TableOutOfOfficeData, now time.Time, zapLog *zap.Logger) map[string]string {
+	available, _ := reviewers.FilterOutOfOffice(logins, ooo, now)
+	return mail.Recipients(available, slackUsersMap, mail.Preferences(zapLog), zapLog)
+}
+
+// email the emailRecipients once per delivery. Returns the emailed logins with
+// their address
+func emailReviewRequest(out audit.Messenger, logins []string, entry mail.Entry, slackUsersMap map[string]interface{}, ooo map[string]types.TableOutOfOfficeData, now time.Time, zapLog *zap.Logger) map[string]string {
+	emailed := emailRecipients(logins, slackUsersMap, ooo, now, zapLog)
+
+	subject := fmt.Sprintf("Review requested: %s#%d", entry.Repository, entry.Number)
+	text := mail.Digest("*Your review is requested*", []mail.Entry{entry})
+	for login, address := range emailed {
+		// a failed email is only logged by mail.Send, it isn't sent again
+		out.Once("email:"+login, func() error {
+			mail.Send(address, subject, text, zapLog)
+			return nil
+		})
+	}
+	return emailed
+}
+
+// replaced in tests
+var listCollaborators = github.GetCollaborators
+
+var requestReviewers = github.RequestReviewers
+
+// reviewers of the repository config, otherwise its collaborators with write
+// access. A failed lookup suggests nobody
+func eligibleReviewers(repo config.RepoConfig, repository string, zapLog *zap.Logger) []string {
+	if len(repo.Reviewers) > 0 {
+		return repo.Reviewers
+	}
+
+	logins, err := listCollaborators(repository)
+	if err != nil {
+		zapLog.Warn("error list collaborators",
+			zap.String("repository", repository),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return logins
+}
+
+// eligible reviewers standing in for the out of office ones, rotating with the
+// pull request number. With assign they are requested on GitHub when the
+// repository assignAlternates, their review_requested event pings them so
+// none are returned to suggest
+func reviewAlternates(repository string, number int, logins []string, author string, ooo map[string]types.TableOutOfOfficeData, assign bool, now time.Time, zapLog *zap.Logger) []string {
+	if _, away := reviewers.FilterOutOfOffice(logins, ooo, now); len(away) == 0 {
+		return nil
+	}
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		conf, _ = config.ParseConfig("")
+	}
+	repo := conf.Repo(repository)
+
+	exclude := append([]string{author}, logins...)
+	alternates := reviewers.SuggestAlternates(eligibleReviewers(repo, repository, zapLog), exclude, ooo, now, alternateReviewers, number)
+	if !assign || !repo.AssignAlternates || len(alternates) == 0 {
+		return alternates
+	}
+
+	if err := requestReviewers(repository, number, alternates); err != nil {
+		zapLog.Warn("error request alternate reviewers",
+			zap.String("repository", repository),
+			zap.Int("number", number),
+			zap.Error(err),
+		)
+		return alternates
+	}
+	return nil
+}
+
+// "Please review" thread message, out of office reviewers are not pinged
+// and the alternates are suggested in their place. emailed reviewers are
+// named without a Slack mention
+func reviewRequestMessage(logins []string, alternates []string, slackUsersMap map[string]interface{}, ooo map[string]types.TableOutOfOfficeData, emailed map[string]string, now time.Time) string {
+	emoji := constants.Emoji()
+	available, away := reviewers.FilterOutOfOffice(logins, ooo, now)
+
+	lines := []string{}
+	if len(available) > 0 {
+		slackMention := "Please review: "
+		for _, user := range available {
+			slackMention += fmt.Sprintf("%s %s", mail.Mention(user, slackUsersMap, emailed), emoji.RequestReview)
+		}
+		lines = append(lines, slackMention)
+	}
+
+	for _, item := range away {
+		lines = append(lines, fmt.Sprintf("%s is out of office until %s.", item.GithubLogin, item.EndDate))
+	}
+
+	if len(away) > 0 && len(alternates) > 0 {
+		mentions := []string{}
+		for _, login := range alternates {
+			mentions = append(mentions, mail.Mention(login, slackUsersMap, nil))
+		}
+		lines = append(lines, fmt.Sprintf("Suggested alternates: %s", strings.Join(mentions, " ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"reflect"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestReviewRequestMessage(t *testing.T) {
+	now, _ := time.Parse("2006-01-02", "2024-03-11")
+	slackUsersMap := map[string]interface{}{
+		"alice": "UA",
+		"bob":   "UB",
+		"carol": "UC",
+		"dave":  "UD",
+	}
+
+	t.Run("all available", func(t *testing.T) {
+		result := reviewRequestMessage([]string{"alice", "bob"}, nil, slackUsersMap, map[string]types.TableOutOfOfficeData{}, map[string]string{}, now)
+		expected := "Please review: <@UA> :eyes:<@UB> :eyes:"
+		if result != expected {
+			t.Errorf("got %q want %q", result, expected)
+		}
+	})
+
+	t.Run("emailed reviewer", func(t *testing.T) {
+		result := reviewRequestMessage([]string{"alice", "erin"}, nil, slackUsersMap, map[string]types.TableOutOfOfficeData{}, map[string]string{"alice": "alice@acme.com"}, now)
+		expected := "Please review: @alice :eyes:@erin :eyes:"
+		if result != expected {
+			t.Errorf("got %q want %q", result, expected)
+		}
+	})
+
+	t.Run("out of office reviewer", func(t *testing.T) {
+		ooo := map[string]types.TableOutOfOfficeData{
+			"bob": {GithubLogin: "bob", StartDate: "2024-03-10", EndDate: "2024-03-15"},
+		}
+		result := reviewRequestMessage([]string{"alice", "bob"}, []string{"carol", "frank"}, slackUsersMap, ooo, map[string]string{}, now)
+		expected := "Please review: <@UA> :eyes:\nbob is out of office until 2024-03-15.\nSuggested alternates: <@UC> @frank"
+		if result != expected {
+			t.Errorf("got %q want %q", result, expected)
+		}
+	})
+}
+
+// eligible reviewers and review requests of every repository, returns the
+// requested logins
+func stubReviewers(t *testing.T, collaborators []string) *[]string {
+	requested := []string{}
+	originalList, originalRequest := listCollaborators, requestReviewers
+	listCollaborators = func(repo string) ([]string, error) {
+		return collaborators, nil
+	}
+	requestReviewers = func(repo string, prNumber int, logins []string) error {
+		requested = append(requested, logins...)
+		return nil
+	}
+	t.Cleanup(func() {
+		listCollaborators, requestReviewers = originalList, originalRequest
+	})
+	return &requested
+}
+
+func TestReviewAlternates(t *testing.T) {
+	now, _ := time.Parse("2006-01-02", "2024-03-11")
+	ooo := map[string]types.TableOutOfOfficeData{
+		"bob":  {GithubLogin: "bob", StartDate: "2024-03-10", EndDate: "2024-03-15"},
+		"erin": {GithubLogin: "erin", StartDate: "2024-03-10", EndDate: "2024-03-15"},
+	}
+	requested := stubReviewers(t, []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace"})
+
+	t.Run("nobody away", func(t *testing.T) {
+		if result := reviewAlternates("api", 7, []string{"alice"}, "dave", ooo, true, now, zap.NewNop()); len(result) != 0 {
+			t.Errorf("Expected no alternates, got %v", result)
+		}
+	})
+
+	t.Run("collaborators", func(t *testing.T) {
+		// author, requested and out of office reviewers are skipped
+		result := reviewAlternates("api", 0, []string{"alice", "bob"}, "dave", ooo, true, now, zap.NewNop())
+		if !reflect.DeepEqual(result, []string{"carol", "frank"}) {
+			t.Errorf("Unexpected alternates %v", result)
+		}
+		result = reviewAlternates("api", 2, []string{"alice", "bob"}, "dave", ooo, true, now, zap.NewNop())
+		if !reflect.DeepEqual(result, []string{"frank", "grace"}) {
+			t.Errorf("Expected the alternates to rotate, got %v", result)
+		}
+		if len(*requested) != 0 {
+			t.Errorf("Expected no review requests, got %v", *requested)
+		}
+	})
+
+	t.Run("assigned", func(t *testing.T) {
+		t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"reviewers": ["grace", "heidi"], "assignAlternates": true}}}`)
+
+		if result := reviewAlternates("api", 0, []string{"bob"}, "dave", ooo, false, now, zap.NewNop()); !reflect.DeepEqual(result, []string{"grace", "heidi"}) {
+			t.Errorf("Expected the configured reviewers suggested, got %v", result)
+		}
+		if result := reviewAlternates("api", 0, []string{"bob"}, "dave", ooo, true, now, zap.NewNop()); len(result) != 0 {
+			t.Errorf("Expected the requested alternates not to be suggested, got %v", result)
+		}
+		if !reflect.DeepEqual(*requested, []string{"grace", "heidi"}) {
+			t.Errorf("Expected grace and heidi to be requested, got %v", *requested)
+		}
+	})
+}
+
+func TestWithoutAuthor(t *testing.T) {
+	others, self := withoutAuthor([]string{"alice", "dave", "bob"}, "dave")
+	if !self || len(others) != 2 || others[0] != "alice" || others[1] != "bob" {
+		t.Errorf("Expected dave to be dropped, got %v %v", others, self)
+	}
+
+	others, self = withoutAuthor([]string{"alice"}, "dave")
+	if self || len(others) != 1 {
+		t.Errorf("Expected alice to be kept, got %v %v", others, self)
+	}
+}
+
+func TestSelfReviewNotice(t *testing.T) {
+	slackUsersMap := map[string]interface{}{"dave": "UD"}
+
+	result := selfReviewNotice("dave", false, slackUsersMap)
+	expected := "Heads up <@UD>, authors can't review their own pull request so you were left out of the review request."
+	if result != expected {
+		t.Errorf("got %q want %q", result, expected)
+	}
+
+	result = selfReviewNotice("erin", true, slackUsersMap)
+	if result != "Heads up @erin, authors can't review their own pull request so you were left out of the review request. Please request another reviewer." {
+		t.Errorf("Expected a request for another reviewer, got %q", result)
+	}
+}
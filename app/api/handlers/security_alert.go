@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"slices"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+var (
+	sendSecurity              = slack.SlackPostChannelMessage
+	getRefPullRequest         = github.GetRefPullRequest
+	getSecretAlertPullRequest = github.GetSecretAlertPullRequest
+)
+
+// security events handled apart from the pull request actions, by the kind
+// of alert they deliver
+var securityEvents = map[string]string{
+	"dependabot_alert":               "dependabot",
+	"repository_vulnerability_alert": "vulnerability",
+	"code_scanning_alert":            "code_scanning",
+	"secret_scanning_alert":          "secret_scanning",
+}
+
+// kinds of alert found on a branch, their pull request thread is warned too
+var branchAlertKinds = []string{"code_scanning", "secret_scanning"}
+
+// actions opening an alert, the repository_vulnerability_alert ones are verbs
+var securityOpenActions = []string{"created", "create", "reopened", "reopen", "reopened_by_user", "reintroduced", "auto_reopened"}
+
+// actions closing an alert and how the thread reads them
+var securityCloseActions = map[string]string{
+	"fixed":          "fixed",
+	"resolve":        "fixed",
+	"dismissed":      "dismissed",
+	"dismiss":        "dismissed",
+	"auto_dismissed": "dismissed",
+	"closed_by_user": "dismissed",
+	"resolved":       "resolved",
+}
+
+func securityEvent(event string) bool {
+	_, ok := securityEvents[event]
+	return ok
+}
+
+// SECURITY_CHANNEL, security alerts are ignored when unset
+func securityChannel() string {
+	return env.GetEnv("SECURITY_CHANNEL", "")
+}
+
+// answers a security delivery, failures are 500 so GitHub shows them
+func securityDelivery(w *failureWriter, out audit.Messenger, githubEvent string, event types.WebhookEvent, zapLog *zap.Logger) {
+	defer recoverPanic(w, githubEvent, event.Action, out.Trail, zapLog)
+
+	svc, err := db.Connection()
+	if err != nil {
+		zapLog.Error("error dynamodb connection",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	if err := securityAlert(svc, out, githubEvent, event, zapLog); err != nil {
+		zapLog.Error("error security alert",
+			zap.Error(err),
+		)
+		writeError(w, err)
+		return
+	}
+
+	writeWebhookResponse(w, "Webhook done.", githubEvent, event.Action, out.Trail)
+}
+
+// posts an opened alert to the security channel and keeps it for the SLA
+// reminders of the security job, closing it replies in its thread and stops
+// the reminders. Alerts found on the branch of an open pull request also warn
+// its thread
+func securityAlert(svc *awsdynamodb.DynamoDB, out audit.Messenger, githubEvent string, event types.WebhookEvent, zapLog *zap.Logger) error {
+	trail := out.Trail
+	channel := securityChannel()
+	if channel == "" {
+		trail.Skip("no security channel")
+		return nil
+	}
+	if event.Alert == nil {
+		trail.Skip("no alert")
+		return nil
+	}
+
+	kind := securityEvents[githubEvent]
+	item := securityAlertItem(kind, event, time.Now())
+
+	// an alert already posted, found on another branch
+	if event.Action == "appeared_in_branch" {
+		securityPullRequestWarning(svc, out, event, item, zapLog)
+		return nil
+	}
+
+	if slices.Contains(securityOpenActions, event.Action) {
+		timeStamp, err := sendSecurity(channel, "", messages.SecurityAlertMessage(item))
+		if err != nil {
+			return err
+		}
+		item.SlackTimeStamp = timeStamp
+		if err := db.InsertSecurityAlert(svc, item); err != nil {
+			return err
+		}
+
+		securityPullRequestWarning(svc, out, event, item, zapLog)
+		return nil
+	}
+
+	resolution, ok := securityCloseActions[event.Action]
+	if !ok {
+		trail.Skip("security action not notified")
+		return nil
+	}
+
+	record, err := db.GetSecurityAlert(svc, item.Alert)
+	if errors.Is(err, db.ErrNoDataFound) {
+		trail.Skip("security alert not tracked")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := sendSecurity(channel, record.SlackTimeStamp, securityClosedMessage(resolution, event.Sender.GetLogin())); err != nil {
+		return err
+	}
+	if err := db.DeleteSecurityAlert(svc, item.Alert); err != nil {
+		zapLog.Warn("error delete security alert",
+			zap.String("alert", item.Alert),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// warns the thread of the open pull request the alert was found on, failures
+// are only logged as the alert already reached the security channel
+func securityPullRequestWarning(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent, item *types.TableSecurityAlertData, zapLog *zap.Logger) {
+	if !slices.Contains(branchAlertKinds, item.Kind) {
+		return
+	}
+
+	pr, err := alertPullRequest(item.Kind, event)
+	if err != nil {
+		zapLog.Warn("error get alert pull request",
+			zap.String("alert", item.Alert),
+			zap.Error(err),
+		)
+		return
+	}
+	if pr == nil {
+		out.Trail.Skip("alert not on a pull request")
+		return
+	}
+
+	timeStamp, err := db.GetSlackTimeStamp(svc, int(pr.GetID()), pr.GetNumber())
+	if err != nil {
+		zapLog.Warn("error get slack timestamp",
+			zap.String("alert", item.Alert),
+			zap.Error(err),
+		)
+		return
+	}
+	if !tracked(out.Trail, timeStamp) {
+		return
+	}
+
+	out.Number = pr.GetNumber()
+	if err := out.SendMessageThread(timeStamp, messages.SecurityPullRequestMessage(item)); err != nil {
+		zapLog.Warn("error slack send alert warning",
+			zap.String("alert", item.Alert),
+			zap.Error(err),
+		)
+	}
+}
+
+// open pull request of the branch of a code scanning alert, or of the commits
+// a secret was leaked in
+func alertPullRequest(kind string, event types.WebhookEvent) (*gogithub.PullRequest, error) {
+	repository := event.Repository.GetName()
+
+	if kind == "secret_scanning" {
+		return getSecretAlertPullRequest(repository, event.Alert.Number)
+	}
+
+	ref := event.Ref
+	if ref == "" {
+		ref = event.Alert.InstanceRef()
+	}
+	return getRefPullRequest(repository, ref)
+}
+
+func securityAlertItem(kind string, event types.WebhookEvent, now time.Time) *types.TableSecurityAlertData {
+	alert := event.Alert
+	repository := event.Repository.GetName()
+
+	createdAt := alert.CreatedAt
+	if _, err := time.Parse(time.RFC3339, createdAt); err != nil {
+		createdAt = now.Format(time.RFC3339)
+	}
+
+	return &types.TableSecurityAlertData{
+		Alert:          db.SecurityAlertKey(repository, kind, alert.Key()),
+		Kind:           kind,
+		Repository:     repository,
+		Number:         alert.Key(),
+		Severity:       alert.GetSeverity(),
+		Summary:        alert.Summary(),
+		Package:        alert.PackageName(),
+		PatchedVersion: alert.PatchedVersion(),
+		HtmlUrl:        alert.Url(),
+		CreatedAt:      createdAt,
+	}
+}
+
+func securityClosedMessage(resolution string, login string) string {
+	if login == "" {
+		return fmt.Sprintf("Alert %s.", resolution)
+	}
+	return fmt.Sprintf("Alert %s by %s.", resolution, login)
+}
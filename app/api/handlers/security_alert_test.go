@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"strings"
+	"testing"
+	"time"
+
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+func stubSendSecurity(t *testing.T) *[]string {
+	sent := []string{}
+	original := sendSecurity
+	sendSecurity = func(channel string, threadTimeStamp string, message string) (string, error) {
+		sent = append(sent, channel+" "+threadTimeStamp+": "+message)
+		return "1.000001", nil
+	}
+	t.Cleanup(func() {
+		sendSecurity = original
+	})
+	return &sent
+}
+
+func TestSecurityAlert(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	event := types.WebhookEvent{
+		Action:     "created",
+		Repository: &gogithub.Repository{Name: gogithub.String("api")},
+		Alert: &types.SecurityAlert{
+			Number:           3,
+			HTMLURL:          "https://github.com/o/api/security/dependabot/3",
+			SecurityAdvisory: &types.SecurityAdvisory{Summary: "Prototype pollution in lodash", Severity: "critical"},
+		},
+	}
+
+	t.Run("no security channel", func(t *testing.T) {
+		sent := stubSendSecurity(t)
+		t.Setenv("SECURITY_CHANNEL", "")
+
+		trail := &audit.Trail{}
+		if err := securityAlert(nil, audit.Messenger{Log: zap.NewNop(), Trail: trail}, "dependabot_alert", event, zap.NewNop()); err != nil {
+			t.Fatal(err)
+		}
+		if skipped := trail.Skipped(); len(*sent) != 0 || len(skipped) != 1 || skipped[0] != "no security channel" {
+			t.Errorf("Expected the alert to be skipped, got %v %v", *sent, skipped)
+		}
+	})
+
+	t.Run("opened", func(t *testing.T) {
+		sent := stubSendSecurity(t)
+		t.Setenv("SECURITY_CHANNEL", "CSEC")
+
+		if err := securityAlert(nil, audit.Messenger{Log: zap.NewNop(), Trail: &audit.Trail{}}, "dependabot_alert", event, zap.NewNop()); err != nil {
+			t.Fatal(err)
+		}
+		if len(*sent) != 1 || !strings.HasPrefix((*sent)[0], "CSEC : :rotating_light: *Critical* Dependabot alert in `api`") {
+			t.Errorf("Expected the alert posted to CSEC, got %v", *sent)
+		}
+	})
+
+	t.Run("not notified", func(t *testing.T) {
+		sent := stubSendSecurity(t)
+		t.Setenv("SECURITY_CHANNEL", "CSEC")
+
+		trail := &audit.Trail{}
+		event := event
+		event.Action = "assigned"
+		if err := securityAlert(nil, audit.Messenger{Log: zap.NewNop(), Trail: trail}, "dependabot_alert", event, zap.NewNop()); err != nil {
+			t.Fatal(err)
+		}
+		if skipped := trail.Skipped(); len(*sent) != 0 || len(skipped) != 1 || skipped[0] != "security action not notified" {
+			t.Errorf("Expected the action to be skipped, got %v %v", *sent, skipped)
+		}
+	})
+}
+
+func TestSecurityAlertBranch(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+	t.Setenv("SECURITY_CHANNEL", "CSEC")
+
+	refs := []string{}
+	original := getRefPullRequest
+	getRefPullRequest = func(repo string, ref string) (*gogithub.PullRequest, error) {
+		refs = append(refs, ref)
+		return nil, nil
+	}
+	t.Cleanup(func() {
+		getRefPullRequest = original
+	})
+
+	event := types.WebhookEvent{
+		Action:     "created",
+		Ref:        "refs/heads/feature",
+		Repository: &gogithub.Repository{Name: gogithub.String("api")},
+		Alert: &types.SecurityAlert{
+			Number: 12,
+			Rule:   &types.AlertRule{ID: "js/sql-injection", SecuritySeverityLevel: "high"},
+		},
+	}
+
+	t.Run("created", func(t *testing.T) {
+		sent := stubSendSecurity(t)
+		trail := &audit.Trail{}
+
+		if err := securityAlert(nil, audit.Messenger{Log: zap.NewNop(), Trail: trail}, "code_scanning_alert", event, zap.NewNop()); err != nil {
+			t.Fatal(err)
+		}
+		if len(*sent) != 1 || !strings.HasPrefix((*sent)[0], "CSEC : :red_circle: *High* Code scanning alert in `api`") {
+			t.Errorf("Expected the alert posted to CSEC, got %v", *sent)
+		}
+		if skipped := trail.Skipped(); len(skipped) != 1 || skipped[0] != "alert not on a pull request" {
+			t.Errorf("Expected no pull request thread, got %v", skipped)
+		}
+	})
+
+	t.Run("appeared in branch", func(t *testing.T) {
+		sent := stubSendSecurity(t)
+		event := event
+		event.Action = "appeared_in_branch"
+
+		if err := securityAlert(nil, audit.Messenger{Log: zap.NewNop(), Trail: &audit.Trail{}}, "code_scanning_alert", event, zap.NewNop()); err != nil {
+			t.Fatal(err)
+		}
+		if len(*sent) != 0 {
+			t.Errorf("Expected the security channel to be left alone, got %v", *sent)
+		}
+	})
+
+	if len(refs) != 2 || refs[0] != "refs/heads/feature" {
+		t.Errorf("Expected the branch of both deliveries to be looked up, got %v", refs)
+	}
+}
+
+func TestAlertPullRequest(t *testing.T) {
+	lookups := []string{}
+	originalRef := getRefPullRequest
+	originalSecret := getSecretAlertPullRequest
+	getRefPullRequest = func(repo string, ref string) (*gogithub.PullRequest, error) {
+		lookups = append(lookups, repo+" "+ref)
+		return &gogithub.PullRequest{Number: gogithub.Int(7)}, nil
+	}
+	getSecretAlertPullRequest = func(repo string, number int) (*gogithub.PullRequest, error) {
+		lookups = append(lookups, fmt.Sprintf("%s secret %d", repo, number))
+		return nil, nil
+	}
+	t.Cleanup(func() {
+		getRefPullRequest = originalRef
+		getSecretAlertPullRequest = originalSecret
+	})
+
+	repository := &gogithub.Repository{Name: gogithub.String("api")}
+
+	// the ref of the delivery is missing on older code scanning deliveries
+	pr, err := alertPullRequest("code_scanning", types.WebhookEvent{Repository: repository, Alert: &types.SecurityAlert{MostRecentInstance: &types.AlertInstance{Ref: "refs/pull/7/merge"}}})
+	if err != nil || pr.GetNumber() != 7 {
+		t.Errorf("Expected pull request 7, got %v %v", pr, err)
+	}
+
+	pr, err = alertPullRequest("secret_scanning", types.WebhookEvent{Repository: repository, Alert: &types.SecurityAlert{Number: 2}})
+	if err != nil || pr != nil {
+		t.Errorf("Expected no pull request, got %v %v", pr, err)
+	}
+
+	if len(lookups) != 2 || lookups[0] != "api refs/pull/7/merge" || lookups[1] != "api secret 2" {
+		t.Errorf("Expected the ref and the secret locations to be looked up, got %v", lookups)
+	}
+}
+
+func TestSecurityAlertItem(t *testing.T) {
+	now := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+	event := types.WebhookEvent{
+		Repository: &gogithub.Repository{Name: gogithub.String("api")},
+		Alert:      &types.SecurityAlert{ID: 91, AffectedPackageName: "requests", Severity: "moderate", FixedIn: "2.20.0"},
+	}
+
+	item := securityAlertItem("vulnerability", event, now)
+	if item.Alert != "api#vulnerability-91" || item.Severity != "medium" || item.Package != "requests" || item.PatchedVersion != "2.20.0" {
+		t.Errorf("Expected the medium requests alert, got %+v", item)
+	}
+	if item.CreatedAt != "2024-03-08T12:00:00Z" {
+		t.Errorf("Expected the delivery time without a creation time, got %q", item.CreatedAt)
+	}
+}
+
+func TestPullRequestHandlerSecurityEvent(t *testing.T) {
+	t.Setenv("SECURITY_CHANNEL", "")
+	t.Setenv("UNHANDLED_ACTIONS", "reject")
+
+	req, err := http.NewRequest("POST", "/", strings.NewReader(`{"action": "fixed", "alert": {"number": 3}, "repository": {"name": "api"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-GitHub-Event", "dependabot_alert")
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(PullRequestHandler).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, `"recognized":true`) || !strings.Contains(body, "no security channel") {
+		t.Errorf("Expected a recognized delivery without security channel, got %s", body)
+	}
+}
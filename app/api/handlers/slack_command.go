@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"slack-pr-lambda/slack"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+type SlackCommandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+func SlackCommandHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Fatalf("error close req body. %v\n", err)
+		}
+	}()
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		zapLog.Error("error read request body",
+			zap.Error(err),
+		)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if err := slack.SlackVerifyRequest(r.Header, body); err != nil {
+		zapLog.Error("error verify slack request",
+			zap.Error(err),
+		)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	cmd, err := slack.SlackParseCommand(body)
+	if err != nil {
+		zapLog.Error("error parse slash command",
+			zap.Error(err),
+		)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	var text string
+	switch cmd.Command {
+	case "/pr-ooo":
+		text, err = outOfOfficeCommand(cmd.UserID, cmd.Text)
+	case "/pr-status":
+		text, err = statusCommand(cmd.Text, zapLog)
+	case "/pr-mute":
+		text, err = muteCommand(cmd.UserID, cmd.Text)
+	case "/pr-watch":
+		text, err = watchCommand(cmd.UserID, cmd.Text)
+	case "/pr-email":
+		text, err = emailCommand(cmd.UserID, cmd.Text)
+	case "/pr-load":
+		text, err = loadCommand(cmd.Text, zapLog)
+	default:
+		text = "Unknown command."
+	}
+	if err != nil {
+		zapLog.Error("error slash command",
+			zap.String("command", cmd.Command),
+			zap.Error(err),
+		)
+		text = "Something went wrong, please try again."
+	}
+
+	bodyBytes := SlackCommandResponse{
+		ResponseType: "ephemeral",
+		Text:         text,
+	}
+
+	j, err := json.Marshal(bodyBytes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(j)
+}
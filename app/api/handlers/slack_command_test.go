@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlackCommandHandler(t *testing.T) {
+	t.Run("unknown command", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/slack/commands", strings.NewReader("command=%2Fpr-unknown&user_id=U1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackCommandHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusOK)
+		}
+
+		expected := `{"response_type":"ephemeral","text":"Unknown command."}`
+		if !strings.Contains(rr.Body.String(), expected) {
+			t.Errorf("handler returned unexpected body: got %v want %v",
+				rr.Body.String(), expected)
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		t.Setenv("SLACK_SIGNING_SECRET", "secret")
+
+		req, err := http.NewRequest("POST", "/slack/commands", strings.NewReader("command=%2Fpr-ooo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Slack-Signature", "v0=abc")
+		req.Header.Set("X-Slack-Request-Timestamp", "1")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackCommandHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusUnauthorized {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusUnauthorized)
+		}
+	})
+}
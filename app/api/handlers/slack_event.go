@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"slack-pr-lambda/api/dashboard"
+	"slack-pr-lambda/api/home"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/apperrors"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/slack"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var publishHome = home.Publish
+var unfurl = slack.SlackUnfurl
+var unfurlPullRequest = adminPullRequest
+
+// Events API subscriptions, the url_verification challenge is echoed back,
+// opening the Home tab renders the queue of the user and pull request links
+// shared in a message are unfurled
+func SlackEventHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Fatalf("error close req body. %v\n", err)
+		}
+	}()
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		zapLog.Error("error read request body",
+			zap.Error(err),
+		)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if err := slack.SlackVerifyRequest(r.Header, body); err != nil {
+		zapLog.Error("error verify slack request",
+			zap.Error(err),
+		)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := slack.SlackParseEvent(body)
+	if err != nil {
+		zapLog.Error("error parse slack event",
+			zap.Error(err),
+		)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if event.Type == "url_verification" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"challenge": event.Challenge})
+		return
+	}
+
+	if event.InnerType == "app_home_opened" && event.Tab == "home" {
+		if err := publishHome(event.User, githubLogin(event.User), zapLog); err != nil {
+			zapLog.Error("error publish home tab",
+				zap.String("user", event.User),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if event.InnerType == "link_shared" {
+		unfurlLinks(event, zapLog)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// card of the tracked pull requests among the shared links, the other links
+// are left as they are. Links typed in the message composer have no message to
+// unfurl yet
+func unfurlLinks(event slack.SlackEvent, zapLog *zap.Logger) {
+	if event.Channel == "COMPOSER" {
+		return
+	}
+
+	cards := map[string]string{}
+	for _, link := range event.Links {
+		owner, repo, number, ok := findPullRequestUrl(link)
+		if !ok || !ownRepository(owner) {
+			continue
+		}
+
+		item, err := unfurlPullRequest(repo, number)
+		if errors.Is(err, db.ErrNoDataFound) || errors.Is(err, apperrors.ErrPRNotFound) {
+			continue
+		}
+		if err != nil {
+			zapLog.Warn("error get unfurled pull request",
+				zap.String("link", link),
+				zap.Error(err),
+			)
+			continue
+		}
+		cards[link] = messages.UnfurlCard(item, dashboard.ThreadUrl(*item), time.Now())
+	}
+	if len(cards) == 0 {
+		return
+	}
+
+	if err := unfurl(event.Channel, event.MessageTimeStamp, cards); err != nil {
+		zapLog.Error("error slack unfurl",
+			zap.String("channel", event.Channel),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSlackEventHandler(t *testing.T) {
+	t.Run("url verification", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/slack/events", strings.NewReader(`{"type":"url_verification","token":"t","challenge":"abc"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackEventHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		var response map[string]string
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil || response["challenge"] != "abc" {
+			t.Errorf("Expected the challenge, got %q", rr.Body.String())
+		}
+	})
+
+	t.Run("app home opened", func(t *testing.T) {
+		published := ""
+		original := publishHome
+		publishHome = func(slackUserId string, login string, zapLog *zap.Logger) error {
+			published = slackUserId
+			return nil
+		}
+		t.Cleanup(func() {
+			publishHome = original
+		})
+
+		body := `{"type":"event_callback","token":"t","event":{"type":"app_home_opened","user":"U1","channel":"D1","tab":"home"}}`
+		req, err := http.NewRequest("POST", "/slack/events", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackEventHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusOK)
+		}
+		if published != "U1" {
+			t.Errorf("Expected the home tab of U1 to be published, got %q", published)
+		}
+	})
+
+	t.Run("link shared", func(t *testing.T) {
+		t.Setenv("GITHUB_OWNER", "acme")
+
+		originalPullRequest := unfurlPullRequest
+		unfurlPullRequest = func(repository string, number int) (*types.TablePullRequestData, error) {
+			if number != 7 {
+				return nil, db.ErrNoDataFound
+			}
+			return &types.TablePullRequestData{Repository: repository, PullRequestId: number, Approvals: 1, RequiredApprovals: 2, Permalink: "https://acme.slack.com/archives/C1/p1"}, nil
+		}
+		var unfurled map[string]string
+		originalUnfurl := unfurl
+		unfurl = func(channel string, timeStamp string, cards map[string]string) error {
+			if channel != "C2" || timeStamp != "1.000001" {
+				t.Errorf("Expected the message of C2, got %s %s", channel, timeStamp)
+			}
+			unfurled = cards
+			return nil
+		}
+		t.Cleanup(func() {
+			unfurlPullRequest = originalPullRequest
+			unfurl = originalUnfurl
+		})
+
+		body := `{"type":"event_callback","token":"t","event":{"type":"link_shared","user":"U1","channel":"C2","message_ts":"1.000001","links":[` +
+			`{"domain":"github.com","url":"https://github.com/acme/api/pull/7"},` +
+			`{"domain":"github.com","url":"https://github.com/acme/api/pull/8"},` +
+			`{"domain":"github.com","url":"https://github.com/globex/api/pull/7"},` +
+			`{"domain":"github.com","url":"https://github.com/acme/api/issues/9"}]}}`
+		req, err := http.NewRequest("POST", "/slack/events", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackEventHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusOK)
+		}
+		if len(unfurled) != 1 {
+			t.Fatalf("Expected the tracked pull request only, got %v", unfurled)
+		}
+		card := unfurled["https://github.com/acme/api/pull/7"]
+		if !strings.Contains(card, "Approvals: 1/2") || !strings.Contains(card, "<https://acme.slack.com/archives/C1/p1|Slack thread>") {
+			t.Errorf("Expected the card of api#7, got %q", card)
+		}
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/slack/events", strings.NewReader("invalid"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackEventHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"slack-pr-lambda/api/home"
+	"slack-pr-lambda/slack"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Block Kit button clicks, replies are sent to the response url so the
+// interaction itself is acknowledged with an empty 200
+func SlackInteractionHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Fatalf("error close req body. %v\n", err)
+		}
+	}()
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		zapLog.Error("error read request body",
+			zap.Error(err),
+		)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if err := slack.SlackVerifyRequest(r.Header, body); err != nil {
+		zapLog.Error("error verify slack request",
+			zap.Error(err),
+		)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	callback, err := slack.SlackParseInteraction(body)
+	if err != nil {
+		zapLog.Error("error parse slack interaction",
+			zap.Error(err),
+		)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if callback.Type == "message_action" && callback.CallbackID == TrackCallbackId {
+		text, err := trackAction(callback.Message.Text, zapLog)
+		if err != nil {
+			zapLog.Error("error slack interaction",
+				zap.String("callbackId", callback.CallbackID),
+				zap.Error(err),
+			)
+			text = "Something went wrong, please try again."
+		}
+		if err := slack.SlackRespond(callback.ResponseURL, text); err != nil {
+			zapLog.Error("error slack respond",
+				zap.Error(err),
+			)
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// the Home tab has no response url, it is re-rendered instead
+	homeView := callback.View.Type == "home"
+	refresh := false
+
+	for _, action := range callback.ActionCallback.BlockActions {
+		var text string
+		switch {
+		case action.ActionID == home.RefreshActionId:
+			refresh = true
+			continue
+		case strings.HasPrefix(action.ActionID, "snooze_"):
+			text, err = snoozeAction(callback.User.ID, action.ActionID, action.Value, time.Now())
+		case action.ActionID == "pr_merge":
+			text, err = mergeAction(callback.User.ID, action.Value, zapLog)
+		case action.ActionID == "pr_status":
+			text, err = statusAction(action.Value, zapLog)
+		default:
+			continue
+		}
+		if err != nil {
+			zapLog.Error("error slack interaction",
+				zap.String("action", action.ActionID),
+				zap.Error(err),
+			)
+			text = "Something went wrong, please try again."
+		}
+
+		if homeView {
+			refresh = true
+			continue
+		}
+		if err := slack.SlackRespond(callback.ResponseURL, text); err != nil {
+			zapLog.Error("error slack respond",
+				zap.Error(err),
+			)
+		}
+	}
+
+	if homeView && refresh {
+		if err := publishHome(callback.User.ID, githubLogin(callback.User.ID), zapLog); err != nil {
+			zapLog.Error("error publish home tab",
+				zap.String("user", callback.User.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
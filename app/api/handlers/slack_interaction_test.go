@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSlackInteractionHandler(t *testing.T) {
+	t.Run("ignored action", func(t *testing.T) {
+		payload := `{"type":"block_actions","user":{"id":"U1"},"actions":[{"block_id":"b","action_id":"other","value":"x"}]}`
+		req, err := http.NewRequest("POST", "/slack/interactions", strings.NewReader("payload="+url.QueryEscape(payload)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackInteractionHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusOK)
+		}
+	})
+
+	t.Run("home refresh", func(t *testing.T) {
+		published := ""
+		original := publishHome
+		publishHome = func(slackUserId string, login string, zapLog *zap.Logger) error {
+			published = slackUserId
+			return nil
+		}
+		t.Cleanup(func() {
+			publishHome = original
+		})
+
+		payload := `{"type":"block_actions","user":{"id":"U1"},"view":{"type":"home"},"actions":[{"block_id":"b","action_id":"home_refresh"}]}`
+		req, err := http.NewRequest("POST", "/slack/interactions", strings.NewReader("payload="+url.QueryEscape(payload)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackInteractionHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusOK)
+		}
+		if published != "U1" {
+			t.Errorf("Expected the home tab of U1 to be published, got %q", published)
+		}
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/slack/interactions", strings.NewReader("payload=invalid"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SlackInteractionHandler)
+
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/reviewers"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"time"
+)
+
+// snooze button on a reminder message, the clicking user is not re-pinged for
+// that pull request until the snooze expires
+func snoozeAction(slackUserId string, actionId string, value string, now time.Time) (string, error) {
+	option, ok := reviewers.GetSnoozeOption(actionId)
+	if !ok {
+		return "Unknown snooze option.", nil
+	}
+
+	login := githubLogin(slackUserId)
+	if login == "" {
+		return "Your Slack account is not linked to a GitHub user.", nil
+	}
+
+	var input types.PullRequestActionValue
+	if err := json.Unmarshal([]byte(value), &input); err != nil {
+		return "", err
+	}
+
+	until := now.Add(option.Duration)
+	svc := db.DynamoDbConnection()
+	item := &types.TableSnoozeData{
+		ID:          input.ID,
+		GithubLogin: login,
+		SnoozeUntil: until.Unix(),
+	}
+	if err := db.InsertSnooze(svc, item); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Reminders for this pull request are snoozed until %s.", slack.SlackDate(until, slack.DateTimeTokens)), nil
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnoozeAction(t *testing.T) {
+	t.Run("unknown option", func(t *testing.T) {
+		text, err := snoozeAction("U06Q5GKADME", "snooze_forever", `{"id":"1","pullRequestId":1}`, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != "Unknown snooze option." {
+			t.Errorf("unexpected response %q", text)
+		}
+	})
+
+	t.Run("not linked", func(t *testing.T) {
+		text, err := snoozeAction("UNKNOWN", "snooze_4h", `{"id":"1","pullRequestId":1}`, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != "Your Slack account is not linked to a GitHub user." {
+			t.Errorf("unexpected response %q", text)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		if _, err := snoozeAction("U06Q5GKADME", "snooze_4h", "invalid", time.Now()); err == nil {
+			t.Errorf("Expected error for invalid button value")
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/types"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const statusUsage = "Usage: `/pr-status <repository> <number>` or `/pr-status <pull request url>`."
+
+// pull request url of the configured instance, github.com or GitHub Enterprise Server
+func pullRequestUrl() *regexp.Regexp {
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(github.BaseUrl()) + `/[^/]+/([^/]+)/pull/(\d+)`)
+}
+
+// parse "<repository> <number>", "<repository>#<number>" or a pull request url
+func parseStatusArgs(text string) (string, int, error) {
+	text = strings.TrimSpace(text)
+
+	if match := pullRequestUrl().FindStringSubmatch(text); match != nil {
+		number, _ := strconv.Atoi(match[2])
+		return match[1], number, nil
+	}
+
+	fields := strings.Fields(strings.Replace(text, "#", " ", 1))
+	if len(fields) != 2 {
+		return "", 0, errors.New(statusUsage)
+	}
+
+	number, err := strconv.Atoi(fields[1])
+	if err != nil || number <= 0 {
+		return "", 0, errors.New(statusUsage)
+	}
+
+	return fields[0], number, nil
+}
+
+func statusCommand(text string, zapLog *zap.Logger) (string, error) {
+	repo, number, err := parseStatusArgs(text)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	return pullRequestStatus(repo, number, zapLog)
+}
+
+// status button on a reminder message
+func statusAction(value string, zapLog *zap.Logger) (string, error) {
+	var input types.PullRequestActionValue
+	if err := json.Unmarshal([]byte(value), &input); err != nil {
+		return "", err
+	}
+
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return "", err
+	}
+
+	svc := db.DynamoDbConnection()
+	item, err := db.GetPullRequest(svc, id, input.PullRequestId)
+	if errors.Is(err, db.ErrNoDataFound) {
+		return "This pull request is no longer tracked.", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return pullRequestStatus(item.Repository, item.PullRequestId, zapLog)
+}
+
+// live state from GitHub, the required approvals tracked on the record win
+// over a fresh lookup
+func pullRequestStatus(repo string, number int, zapLog *zap.Logger) (string, error) {
+	status, err := github.GetPullRequestStatus(repo, number)
+	if err != nil {
+		return "", err
+	}
+
+	required := 0
+	svc := db.DynamoDbConnection()
+	item, err := db.GetPullRequest(svc, int(status.ID), number)
+	if err != nil && !errors.Is(err, db.ErrNoDataFound) {
+		zapLog.Warn("error get pull request",
+			zap.Error(err),
+		)
+	}
+	if item != nil {
+		required = item.RequiredApprovals
+	}
+	if required == 0 {
+		required = requiredApprovals(repo, status.BaseRef, zapLog)
+	}
+
+	return statusMessage(status, required), nil
+}
+
+func statusMessage(status *github.PullRequestStatus, required int) string {
+	emoji := constants.Emoji()
+
+	lines := []string{
+		fmt.Sprintf("<%s|#%d %s>", status.HtmlUrl, status.Number, status.Title),
+		messages.ApprovalsLine(status.Approvals, required),
+	}
+
+	if len(status.FailingChecks) > 0 {
+		lines = append(lines, fmt.Sprintf("Failing checks: %s %s", strings.Join(status.FailingChecks, ", "), emoji.CheckFailed))
+	} else {
+		lines = append(lines, fmt.Sprintf("Checks: no failures %s", emoji.CheckPassed))
+	}
+
+	if status.Conflicts {
+		lines = append(lines, fmt.Sprintf("Merge conflicts with the base branch %s", emoji.CheckFailed))
+	}
+
+	if status.UnresolvedThreads > 0 {
+		lines = append(lines, fmt.Sprintf("Unresolved review threads: %d", status.UnresolvedThreads))
+	}
+
+	ready := status.Approvals >= required && len(status.FailingChecks) == 0 && !status.Conflicts && status.UnresolvedThreads == 0
+	if ready {
+		lines = append(lines, "Ready to merge.")
+	} else {
+		lines = append(lines, "Not ready to merge yet.")
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"slack-pr-lambda/github"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestParseStatusArgs(t *testing.T) {
+	tests := []struct {
+		text   string
+		repo   string
+		number int
+		err    bool
+	}{
+		{text: "api 12", repo: "api", number: 12},
+		{text: "api#12", repo: "api", number: 12},
+		{text: "https://github.com/rodentskie/api/pull/12/files", repo: "api", number: 12},
+		{text: "api", err: true},
+		{text: "api twelve", err: true},
+		{text: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			repo, number, err := parseStatusArgs(tt.text)
+			if tt.err {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.text)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo != tt.repo || number != tt.number {
+				t.Errorf("got %s %d want %s %d", repo, number, tt.repo, tt.number)
+			}
+		})
+	}
+}
+
+func TestParseStatusArgsEnterprise(t *testing.T) {
+	t.Setenv("GITHUB_URL", "https://github.example.com")
+
+	repo, number, err := parseStatusArgs("https://github.example.com/rodentskie/api/pull/12")
+	if err != nil || repo != "api" || number != 12 {
+		t.Errorf("got %s %d %v", repo, number, err)
+	}
+}
+
+func TestStatusCommand(t *testing.T) {
+	text, err := statusCommand("api", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != statusUsage {
+		t.Errorf("unexpected response %q", text)
+	}
+}
+
+func TestStatusAction(t *testing.T) {
+	if _, err := statusAction("invalid", zap.NewNop()); err == nil {
+		t.Errorf("Expected error for invalid button value")
+	}
+}
+
+func TestStatusMessage(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		status := &github.PullRequestStatus{Number: 12, Title: "Fix", HtmlUrl: "url", Approvals: 2}
+		expected := "<url|#12 Fix>\nApprovals: 2/2 :approved:\nChecks: no failures :check-passed:\nReady to merge."
+		if result := statusMessage(status, 2); result != expected {
+			t.Errorf("got %q want %q", result, expected)
+		}
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		status := &github.PullRequestStatus{
+			Number:            12,
+			Title:             "Fix",
+			HtmlUrl:           "url",
+			Approvals:         1,
+			Conflicts:         true,
+			FailingChecks:     []string{"lint", "e2e"},
+			UnresolvedThreads: 3,
+		}
+		expected := "<url|#12 Fix>\nApprovals: 1/2\nFailing checks: lint, e2e :check-failed:\nMerge conflicts with the base branch :check-failed:\nUnresolved review threads: 3\nNot ready to merge yet."
+		if result := statusMessage(status, 2); result != expected {
+			t.Errorf("got %q want %q", result, expected)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"strconv"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+var getSlackTimeStamp = db.GetSlackTimeStamp
+
+// time a delivery may take before its steps are logged, GitHub gives up on a
+// delivery after 10 seconds
+func eventBudget() time.Duration {
+	ms, err := strconv.Atoi(env.GetEnv("EVENT_BUDGET_MS", "5000"))
+	if err != nil || ms <= 0 {
+		ms = 5000
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// runs a dynamodb call of the delivery, its outcome and duration are added to
+// the trail
+func step(trail *audit.Trail, name string, run func() error) error {
+	started := time.Now()
+	if err := run(); err != nil {
+		trail.Step(name, audit.StepFailed, time.Since(started))
+		return err
+	}
+	trail.Step(name, audit.StepDone, time.Since(started))
+	return nil
+}
+
+// parent message of the pull request, "" when it is not tracked
+func slackTimeStamp(svc *awsdynamodb.DynamoDB, trail *audit.Trail, id int, number int) (string, error) {
+	var timeStamp string
+	var err error
+	step(trail, "dynamo.read", func() error {
+		timeStamp, err = getSlackTimeStamp(svc, id, number)
+		// an untracked pull request is a completed read
+		if errors.Is(err, db.ErrNoDataFound) {
+			return nil
+		}
+		return err
+	})
+	return timeStamp, err
+}
+
+// a failed delivery logs which steps completed before the failure, GitHub
+// redelivering it skips the checkpointed messages. Deliveries over the budget
+// log their steps to find the slow one
+func reportSteps(w *failureWriter, trail *audit.Trail, githubEvent string, action string, started time.Time, zapLog *zap.Logger) {
+	took := time.Since(started)
+	steps := trail.Steps()
+
+	if w.status >= http.StatusInternalServerError {
+		zapLog.Warn("partial delivery",
+			zap.String("event", githubEvent),
+			zap.String("action", action),
+			zap.Any("steps", steps),
+		)
+		return
+	}
+
+	if took > eventBudget() {
+		zapLog.Warn("delivery over budget",
+			zap.String("event", githubEvent),
+			zap.String("action", action),
+			zap.Duration("took", took),
+			zap.Any("steps", steps),
+		)
+	}
+}
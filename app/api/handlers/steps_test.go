@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"testing"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func stubSlackTimeStamp(t *testing.T, timeStamp string, err error) {
+	original := getSlackTimeStamp
+	getSlackTimeStamp = func(svc *awsdynamodb.DynamoDB, id int, pullRequestId int) (string, error) {
+		return timeStamp, err
+	}
+	t.Cleanup(func() {
+		getSlackTimeStamp = original
+	})
+}
+
+func TestEventBudget(t *testing.T) {
+	t.Setenv("EVENT_BUDGET_MS", "")
+	assert.Equal(t, 5*time.Second, eventBudget())
+
+	t.Setenv("EVENT_BUDGET_MS", "2500")
+	assert.Equal(t, 2500*time.Millisecond, eventBudget())
+
+	t.Setenv("EVENT_BUDGET_MS", "-1")
+	assert.Equal(t, 5*time.Second, eventBudget())
+}
+
+func TestStep(t *testing.T) {
+	trail := &audit.Trail{}
+
+	assert.NoError(t, step(trail, "dynamo.write", func() error { return nil }))
+	assert.Error(t, step(trail, "dynamo.write", func() error { return errors.New("throttled") }))
+
+	steps := trail.Steps()
+	assert.Len(t, steps, 2)
+	assert.Equal(t, audit.StepDone, steps[0].Outcome)
+	assert.Equal(t, audit.StepFailed, steps[1].Outcome)
+	assert.True(t, trail.Partial())
+}
+
+func TestSlackTimeStamp(t *testing.T) {
+	t.Run("tracked", func(t *testing.T) {
+		stubSlackTimeStamp(t, "1.000001", nil)
+		trail := &audit.Trail{}
+
+		timeStamp, err := slackTimeStamp(nil, trail, 42, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, "1.000001", timeStamp)
+		assert.Equal(t, audit.StepDone, trail.Steps()[0].Outcome)
+	})
+
+	t.Run("not tracked", func(t *testing.T) {
+		stubSlackTimeStamp(t, "", db.ErrNoDataFound)
+		trail := &audit.Trail{}
+
+		_, err := slackTimeStamp(nil, trail, 42, 7)
+		assert.ErrorIs(t, err, db.ErrNoDataFound)
+		assert.Equal(t, audit.StepDone, trail.Steps()[0].Outcome)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		stubSlackTimeStamp(t, "", errors.New("throttled"))
+		trail := &audit.Trail{}
+
+		_, err := slackTimeStamp(nil, trail, 42, 7)
+		assert.Error(t, err)
+		assert.Equal(t, audit.StepFailed, trail.Steps()[0].Outcome)
+	})
+}
+
+func TestReportSteps(t *testing.T) {
+	trail := &audit.Trail{}
+	trail.Step("slack.parent", audit.StepDone, time.Second)
+	trail.Step("dynamo.write", audit.StepFailed, time.Second)
+
+	t.Run("failed", func(t *testing.T) {
+		core, logs := observer.New(zap.WarnLevel)
+		failures := &failureWriter{ResponseWriter: httptest.NewRecorder()}
+		failures.WriteHeader(500)
+
+		reportSteps(failures, trail, "pull_request", "opened", time.Now(), zap.New(core))
+		assert.Equal(t, 1, logs.FilterMessage("partial delivery").Len())
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		t.Setenv("EVENT_BUDGET_MS", "1000")
+		core, logs := observer.New(zap.WarnLevel)
+
+		reportSteps(&failureWriter{ResponseWriter: httptest.NewRecorder()}, trail, "pull_request", "opened", time.Now(), zap.New(core))
+		assert.Equal(t, 0, logs.Len())
+
+		reportSteps(&failureWriter{ResponseWriter: httptest.NewRecorder()}, trail, "pull_request", "opened", time.Now().Add(-2*time.Second), zap.New(core))
+		assert.Equal(t, 1, logs.FilterMessage("delivery over budget").Len())
+	})
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/mapstruct"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const TrackCallbackId = "track_pr"
+
+var getOpenPullRequest = github.GetOpenPullRequest
+
+var claimItem = db.ClaimItem
+
+// owner, repository and number of the first pull request url of the instance
+// anywhere in a message, Slack wraps links as <url|label>
+func findPullRequestUrl(text string) (string, string, int, bool) {
+	pattern := regexp.MustCompile(regexp.QuoteMeta(github.BaseUrl()) + `/([^/\s]+)/([^/\s|>]+)/pull/(\d+)`)
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return "", "", 0, false
+	}
+
+	number, _ := strconv.Atoi(match[3])
+	return match[1], match[2], number, true
+}
+
+// the repositories are looked up under GITHUB_OWNER, another owner's
+// repository of the same name is not one of them
+func ownRepository(owner string) bool {
+	return strings.EqualFold(owner, env.GetEnv("GITHUB_OWNER", "owner"))
+}
+
+// "Track this PR" message shortcut, backfills a pull request opened before the
+// webhook was set up or missed by it and posts its parent message
+func trackAction(text string, zapLog *zap.Logger) (string, error) {
+	owner, repo, number, ok := findPullRequestUrl(text)
+	if !ok {
+		return "No pull request link found in this message.", nil
+	}
+	if !ownRepository(owner) {
+		return fmt.Sprintf("Only pull requests of `%s` can be tracked.", env.GetEnv("GITHUB_OWNER", "owner")), nil
+	}
+	if !allowedEvent("pull_request", "opened", repo, zapLog) {
+		return fmt.Sprintf("Pull requests of `%s` are not tracked.", repo), nil
+	}
+
+	input, err := getOpenPullRequest(repo, number)
+	if err != nil {
+		return "", err
+	}
+	if input.PullRequest.GetState() != "open" {
+		return fmt.Sprintf("%s#%d is not open.", repo, number), nil
+	}
+
+	createdAt := input.PullRequest.GetCreatedAt()
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	slackUsersMap := mapstruct.StructToMap(*constants.SlackUsers())
+	item := openedItem(input, "opened", slackUsersMap, createdAt, zapLog)
+
+	// claimed before the parent message is posted so a double click posts one
+	svc := db.DynamoDbConnection()
+	err = claimItem(svc, item)
+	if errors.Is(err, db.ErrAlreadyTracked) {
+		return fmt.Sprintf("%s#%d is already tracked.", repo, number), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	out := audit.Messenger{
+		Source:     "track",
+		Repository: repo,
+		Number:     number,
+		Log:        zapLog,
+	}
+	parent := localizedParent(input, "opened", item, false, slackUsersMap)
+	timeStamp, entry, err := out.Localized(parent).SendParentMessage(input, messages.ParentMessage(item, time.Now()))
+	if err != nil {
+		// released so the pull request can be tracked again
+		if deleteErr := db.DeleteItem(svc, int(input.PullRequest.GetID()), number); deleteErr != nil {
+			zapLog.Warn("error release tracked pull request",
+				zap.Error(deleteErr),
+			)
+		}
+	}
+	if errors.Is(err, audit.ErrNotRouted) {
+		return fmt.Sprintf("%s#%d is not posted to the channel by the repository config.", repo, number), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	threadPosted(out, item, timeStamp, zapLog)
+	if err := storeParent(svc, out, item, entry); err != nil {
+		return "", err
+	}
+	if err := openedThread(svc, out, timeStamp, input, slackUsersMap, zapLog); err != nil {
+		return "", err
+	}
+
+	if err := db.RecordOpened(svc, audit.PullRequestKey(repo, number), repo, number, item.Author, item.CreatedAt); err != nil {
+		zapLog.Warn("error record review metrics",
+			zap.Error(err),
+		)
+	}
+	// reviews submitted before tracking count towards the quorum
+	err = retryConflict(func() error {
+		return updateApprovals(svc, out, int(input.PullRequest.GetID()), number)
+	})
+	if err != nil {
+		zapLog.Warn("error update approvals",
+			zap.Error(err),
+		)
+	}
+	if err := refreshDashboard(zapLog); err != nil {
+		zapLog.Error("error refresh dashboard",
+			zap.Error(err),
+		)
+	}
+
+	return fmt.Sprintf("Tracking %s#%d in <#%s>.", repo, number, env.GetEnv("SLACK_CHANNEL", "")), nil
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+func TestFindPullRequestUrl(t *testing.T) {
+	tests := []struct {
+		text   string
+		owner  string
+		repo   string
+		number int
+		found  bool
+	}{
+		{"can someone look at <https://github.com/rodentskie/api/pull/7|this>?", "rodentskie", "api", 7, true},
+		{"https://github.com/rodentskie/web/pull/12/files and https://github.com/rodentskie/api/pull/3", "rodentskie", "web", 12, true},
+		{"https://github.com/acme/api/pull/7", "acme", "api", 7, true},
+		{"https://github.com/rodentskie/api/issues/7", "", "", 0, false},
+		{"no link here", "", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		owner, repo, number, found := findPullRequestUrl(tt.text)
+		if owner != tt.owner || repo != tt.repo || number != tt.number || found != tt.found {
+			t.Errorf("%q: got %s/%s#%d %v", tt.text, owner, repo, number, found)
+		}
+	}
+}
+
+func TestTrackAction(t *testing.T) {
+	original := getOpenPullRequest
+	getOpenPullRequest = func(repo string, prNumber int) (types.OpenPullRequest, error) {
+		input := types.OpenPullRequest{
+			Number:      prNumber,
+			PullRequest: &gogithub.PullRequest{State: gogithub.String("closed")},
+		}
+		return input, nil
+	}
+	t.Cleanup(func() {
+		getOpenPullRequest = original
+	})
+
+	t.Setenv("GITHUB_OWNER", "rodentskie")
+
+	t.Run("no link", func(t *testing.T) {
+		text, err := trackAction("hello", zap.NewNop())
+		if err != nil || text != "No pull request link found in this message." {
+			t.Errorf("got %q %v", text, err)
+		}
+	})
+
+	t.Run("other owner", func(t *testing.T) {
+		text, err := trackAction("https://github.com/acme/api/pull/7", zap.NewNop())
+		if err != nil || text != "Only pull requests of `rodentskie` can be tracked." {
+			t.Errorf("got %q %v", text, err)
+		}
+	})
+
+	t.Run("repository not tracked", func(t *testing.T) {
+		t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"events": ["pull_request_review"]}}}`)
+
+		text, err := trackAction("https://github.com/rodentskie/api/pull/7", zap.NewNop())
+		if err != nil || text != "Pull requests of `api` are not tracked." {
+			t.Errorf("got %q %v", text, err)
+		}
+	})
+
+	t.Run("closed pull request", func(t *testing.T) {
+		text, err := trackAction("https://github.com/rodentskie/api/pull/7", zap.NewNop())
+		if err != nil || text != "api#7 is not open." {
+			t.Errorf("got %q %v", text, err)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/redact"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+var sendUnhandled = slack.SlackSendChannelMessage
+
+// actions PullRequestHandler acts on by X-GitHub-Event, anything else is
+// unhandled, e.g. the edited action of a review
+var handledActions = map[string][]string{
+	"pull_request":                {"opened", "reopened", "review_requested", "closed", "edited", "labeled", "unlabeled", "synchronize", "enqueued", "dequeued"},
+	"pull_request_review":         {"submitted", "dismissed"},
+	"pull_request_review_comment": {"created"},
+	"issue_comment":               {"created"},
+	"check_run":                   {"completed"},
+	"workflow_run":                {"completed"},
+	"merge_group":                 {"checks_requested"},
+}
+
+// raw payloads forwarded to the catch-all channel are cut to fit a message
+const unhandledPayloadLimit = 3000
+
+// UNHANDLED_ACTIONS, reject answers unhandled actions with 422 so they show
+// up as failed deliveries on GitHub, ignore (the default) acknowledges them
+func rejectUnhandled() bool {
+	return env.GetEnv("UNHANDLED_ACTIONS", "ignore") == "reject"
+}
+
+// an action no handler acts on is counted, logged and forwarded with its
+// redacted payload to UNHANDLED_CHANNEL when set, failing to forward it is
+// only logged
+func reportUnhandled(event string, action string, repository string, body []byte, zapLog *zap.Logger) {
+	unhandledEvents.Inc(action)
+	zapLog.Warn("unhandled action",
+		zap.String("event", event),
+		zap.String("action", action),
+		zap.String("repository", repository),
+		zap.Int("schemaVersion", types.WebhookSchemaVersion),
+	)
+
+	channel := env.GetEnv("UNHANDLED_CHANNEL", "")
+	if channel == "" {
+		return
+	}
+
+	if err := sendUnhandled(channel, unhandledMessage(event, action, repository, body)); err != nil {
+		zapLog.Error("error send unhandled action",
+			zap.Error(err),
+		)
+	}
+}
+
+func unhandledMessage(event string, action string, repository string, body []byte) string {
+	if event == "" {
+		event = "unknown"
+	}
+	if repository == "" {
+		repository = "unknown"
+	}
+
+	payload := string(redact.JSON(body))
+	if len(payload) > unhandledPayloadLimit {
+		payload = payload[:unhandledPayloadLimit] + "…"
+	}
+
+	return fmt.Sprintf("Unhandled `%s.%s` in `%s` (schema v%d):\n```%s```", event, action, repository, types.WebhookSchemaVersion, payload)
+}
+
+func handledAction(event string, action string) bool {
+	return slices.Contains(handledActions[event], action)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestPullRequestHandlerUnhandledAction(t *testing.T) {
+	t.Setenv("UNHANDLED_ACTIONS", "reject")
+
+	req, err := http.NewRequest("POST", "/", strings.NewReader(`{"action": "auto_merge_enabled", "repository": {"name": "api"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-GitHub-Event", "pull_request")
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(PullRequestHandler).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnprocessableEntity {
+		t.Errorf("handler returned wrong status code: got %v want %v",
+			status, http.StatusUnprocessableEntity)
+	}
+
+	if value := unhandledEvents.Value("auto_merge_enabled"); value != 1 {
+		t.Errorf("Expected 1 unhandled event, got %v", value)
+	}
+}
+
+func TestReportUnhandled(t *testing.T) {
+	sent := []string{}
+	original := sendUnhandled
+	sendUnhandled = func(channel string, message string) error {
+		sent = append(sent, channel+": "+message)
+		return errors.New("channel_not_found")
+	}
+	t.Cleanup(func() {
+		sendUnhandled = original
+	})
+
+	t.Setenv("UNHANDLED_CHANNEL", "")
+	reportUnhandled("pull_request", "locked", "api", []byte(`{"action": "locked"}`), zap.NewNop())
+	if len(sent) != 0 {
+		t.Errorf("Expected nothing forwarded without a channel, got %v", sent)
+	}
+
+	// the failure is only logged
+	t.Setenv("UNHANDLED_CHANNEL", "C9")
+	reportUnhandled("pull_request", "locked", "api", []byte(`{"action": "locked"}`), zap.NewNop())
+	if len(sent) != 1 || !strings.HasPrefix(sent[0], "C9: Unhandled `pull_request.locked` in `api` (schema v3)") {
+		t.Errorf("Expected the event forwarded to C9, got %v", sent)
+	}
+}
+
+func TestUnhandledMessage(t *testing.T) {
+	result := unhandledMessage("", "locked", "", []byte(`{"action": "locked"}`))
+	expected := "Unhandled `unknown.locked` in `unknown` (schema v3):\n```{\"action\":\"locked\"}```"
+	if result != expected {
+		t.Errorf("got %q want %q", result, expected)
+	}
+
+	result = unhandledMessage("pull_request", "edited", "api", []byte(`{"body": "`+strings.Repeat("a", 4000)+`"}`))
+	if !strings.HasSuffix(result, "…```") || len(result) > unhandledPayloadLimit+100 {
+		t.Errorf("Expected the payload to be cut, got %d characters", len(result))
+	}
+}
+
+func TestHandledAction(t *testing.T) {
+	for _, handled := range [][2]string{{"pull_request", "opened"}, {"issue_comment", "created"}, {"check_run", "completed"}, {"merge_group", "checks_requested"}} {
+		if !handledAction(handled[0], handled[1]) {
+			t.Errorf("Expected %s.%s to be handled", handled[0], handled[1])
+		}
+	}
+	for _, unhandled := range [][2]string{{"pull_request", "auto_merge_enabled"}, {"pull_request_review", "edited"}, {"issues", "opened"}, {"", "opened"}} {
+		if handledAction(unhandled[0], unhandled[1]) {
+			t.Errorf("Expected %s.%s to be unhandled", unhandled[0], unhandled[1])
+		}
+	}
+}
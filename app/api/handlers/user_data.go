@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"slack-pr-lambda/api/mail"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// replaces the mentions of a deleted user in the stored messages
+const deletedUserMention = "@deleted-user"
+
+// mentions of the user in a message: <@id>, and the @<id> or @<github login>
+// written instead by mutes and quiet hours. Names are not cut at a hyphen so
+// @bob doesn't match @bob-smith
+func redactMentions(text string, slackUserId string, login string) string {
+	text = strings.ReplaceAll(text, fmt.Sprintf("<@%s>", slackUserId), deletedUserMention)
+	for _, name := range plainNames(slackUserId, login) {
+		plain := regexp.MustCompile("@" + regexp.QuoteMeta(name) + "([^A-Za-z0-9-]|$)")
+		text = plain.ReplaceAllString(text, deletedUserMention+"${1}")
+	}
+	return text
+}
+
+// what stored messages are searched for, the <@id> mention contains @<id>
+func plainNames(slackUserId string, login string) []string {
+	if login == "" {
+		return []string{slackUserId}
+	}
+	return []string{slackUserId, login}
+}
+
+// data deletion request of a slack user: the out of office, mutes,
+// subscriptions and deferred mentions of the user and the snoozes, email
+// preference and review comment batches of the linked github login (?login=
+// once the user is no longer in constants.Users) are deleted. The mentions of
+// the user are edited out of the stored parent messages, which are updated in
+// Slack, and of the audit records
+func AdminDeleteUserHandler(w http.ResponseWriter, r *http.Request) {
+	zapLog, ok := requestLogger(w, r)
+	if !ok {
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	if !adminAuthorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	slackUserId := r.PathValue("slackUserId")
+	linked := githubLogin(slackUserId)
+	login := r.URL.Query().Get("login")
+	if login == "" {
+		login = linked
+	}
+
+	err := func() error {
+		svc := db.DynamoDbConnection()
+
+		if err := db.DeleteOutOfOffice(svc, slackUserId); err != nil {
+			return err
+		}
+
+		mutes, err := db.ListUserMutes(svc, slackUserId)
+		if err != nil {
+			return err
+		}
+		for _, pullRequest := range mutes {
+			if err := db.DeleteMute(svc, pullRequest, slackUserId); err != nil {
+				return err
+			}
+		}
+
+		subscriptions, err := db.ListUserSubscriptions(svc, slackUserId)
+		if err != nil {
+			return err
+		}
+		for _, pullRequest := range subscriptions {
+			if err := db.DeleteSubscription(svc, pullRequest, slackUserId); err != nil {
+				return err
+			}
+		}
+
+		deferred, err := db.ListUserDeferredMentions(svc, slackUserId)
+		if err != nil {
+			return err
+		}
+		for _, item := range deferred {
+			if len(item.SlackUserIds) > 1 {
+				err = db.RemoveDeferredMention(svc, item.PullRequest, item.ThreadTimeStamp, slackUserId)
+			} else {
+				err = db.DeleteDeferredMention(svc, item.PullRequest, item.ThreadTimeStamp)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		snoozes := []string{}
+		if login != "" {
+			snoozes, err = db.ListUserSnoozes(svc, login)
+			if err != nil {
+				return err
+			}
+			for _, id := range snoozes {
+				if err := db.DeleteSnooze(svc, id, login); err != nil {
+					return err
+				}
+			}
+
+			if err := db.DeleteEmailPreference(svc, login); err != nil {
+				return err
+			}
+			mail.Forget(login)
+
+			batches, err := db.ListUserCommentBatches(svc, login)
+			if err != nil {
+				return err
+			}
+			for _, id := range batches {
+				if err := db.DeleteCommentBatch(svc, id); err != nil {
+					return err
+				}
+			}
+		}
+
+		message := fmt.Sprintf("Deleted the out of office, email preference, comment batches, %d mutes, %d subscriptions, %d deferred mentions and %d snoozes of %s.", len(mutes), len(subscriptions), len(deferred), len(snoozes), slackUserId)
+
+		parents, err := redactParentMessages(svc, slackUserId, login, zapLog)
+		if err != nil {
+			return err
+		}
+
+		records, err := redactAudits(svc, slackUserId, login)
+		if err != nil {
+			return err
+		}
+		message += fmt.Sprintf(" Redacted %d parent messages and %d audit records.", parents, records)
+
+		if linked != "" {
+			message += fmt.Sprintf(" %s is still linked to %s in constants.Users, remove it there.", slackUserId, linked)
+		}
+
+		zapLog.Info("user data deleted",
+			zap.String("slackUserId", slackUserId),
+			zap.Int("mutes", len(mutes)),
+			zap.Int("subscriptions", len(subscriptions)),
+			zap.Int("deferredMentions", len(deferred)),
+			zap.Int("snoozes", len(snoozes)),
+			zap.Int("parentMessages", parents),
+			zap.Int("auditRecords", records),
+		)
+		writeResponse(w, message)
+		return nil
+	}()
+	if err != nil {
+		zapLog.Error("error delete user data",
+			zap.String("slackUserId", slackUserId),
+			zap.Error(err),
+		)
+		writeError(w, err)
+	}
+}
+
+// stored parent messages mentioning the user are redacted and rendered again
+// in Slack, the number of redacted messages is returned
+func redactParentMessages(svc *awsdynamodb.DynamoDB, slackUserId string, login string, zapLog *zap.Logger) (int, error) {
+	items := map[string]types.TablePullRequestData{}
+	for _, name := range plainNames(slackUserId, login) {
+		found, err := db.ListPullRequestsContaining(svc, "@"+name)
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range found {
+			items[item.ID] = item
+		}
+	}
+
+	redacted := 0
+	for _, item := range items {
+		text := redactMentions(item.ParentMessage, slackUserId, login)
+		if text == item.ParentMessage {
+			continue
+		}
+		item.ParentMessage = text
+
+		id, err := strconv.Atoi(item.ID)
+		if err != nil {
+			return redacted, err
+		}
+		if err := db.UpdateParentMessage(svc, id, item.PullRequestId, text); err != nil {
+			return redacted, err
+		}
+		redacted++
+
+		if item.SlackTimeStamp == "" {
+			continue
+		}
+		out := audit.Messenger{
+			Source:     "admin_delete_user",
+			Repository: item.Repository,
+			Number:     item.PullRequestId,
+			Log:        zapLog,
+		}
+		if err := out.UpdateMessage(item.SlackTimeStamp, messages.ParentMessage(&item, time.Now())); err != nil {
+			return redacted, err
+		}
+	}
+	return redacted, nil
+}
+
+// audit records mentioning the user are written again redacted, the number of
+// redacted records is returned
+func redactAudits(svc *awsdynamodb.DynamoDB, slackUserId string, login string) (int, error) {
+	records := map[string]types.TableAuditData{}
+	for _, name := range plainNames(slackUserId, login) {
+		found, err := db.ListAuditsContaining(svc, "@"+name)
+		if err != nil {
+			return 0, err
+		}
+		for _, record := range found {
+			records[record.PullRequest+"#"+record.SentAt] = record
+		}
+	}
+
+	redacted := 0
+	for _, record := range records {
+		text := redactMentions(record.Text, slackUserId, login)
+		if text == record.Text {
+			continue
+		}
+		record.Text = text
+		if err := db.InsertAudit(svc, &record); err != nil {
+			return redacted, err
+		}
+		redacted++
+	}
+	return redacted, nil
+}
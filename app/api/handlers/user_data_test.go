@@ -0,0 +1,22 @@
+package handlers
+
+import "testing"
+
+func TestRedactMentions(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"<@U1> review requested", "@deleted-user review requested"},
+		{"<@U1> and <@U12>, cc <@U1>", "@deleted-user and <@U12>, cc @deleted-user"},
+		{"muted @bob, @bob-smith and @bobby", "muted @deleted-user, @bob-smith and @bobby"},
+		{"quiet hours @U1", "quiet hours @deleted-user"},
+		{"no mention", "no mention"},
+	}
+
+	for _, tt := range tests {
+		if result := redactMentions(tt.text, "U1", "bob"); result != tt.expected {
+			t.Errorf("redactMentions(%q) returned %q, expected %q", tt.text, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"errors"
+	db "slack-pr-lambda/dynamodb"
+)
+
+// attempts of a read-modify-write update of a pull request record
+const versionAttempts = 3
+
+// runs the update again while another delivery changed the record between its
+// read and its write. The write comes before the Slack side effects, so a
+// conflicting attempt has posted nothing
+func retryConflict(update func() error) error {
+	var err error
+	for attempt := 0; attempt < versionAttempts; attempt++ {
+		if err = update(); !errors.Is(err, db.ErrVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	db "slack-pr-lambda/dynamodb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryConflict(t *testing.T) {
+	attempts := 0
+	err := retryConflict(func() error {
+		attempts++
+		if attempts < 2 {
+			return db.ErrVersionConflict
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	attempts = 0
+	err = retryConflict(func() error {
+		attempts++
+		return db.ErrVersionConflict
+	})
+	assert.ErrorIs(t, err, db.ErrVersionConflict)
+	assert.Equal(t, versionAttempts, attempts)
+
+	other := errors.New("other")
+	attempts = 0
+	err = retryConflict(func() error {
+		attempts++
+		return other
+	})
+	assert.ErrorIs(t, err, other)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strings"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+const watchUsage = "Usage: `/pr-watch <pull request url | repository#number | number> [off]`."
+
+// /pr-watch slash command, the user is sent a direct message on approvals,
+// failed checks and the merge of the pull request
+func watchCommand(slackUserId string, text string) (string, error) {
+	fields := strings.Fields(text)
+	off := len(fields) > 1 && strings.EqualFold(fields[len(fields)-1], "off")
+	if off {
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) == 0 {
+		return watchUsage, nil
+	}
+
+	svc := db.DynamoDbConnection()
+	repo, number, reply, err := resolvePullRequest(svc, strings.Join(fields, " "), watchUsage)
+	if err != nil || reply != "" {
+		return reply, err
+	}
+
+	key := audit.PullRequestKey(repo, number)
+	if off {
+		if err := db.DeleteSubscription(svc, key, slackUserId); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("You are no longer watching %s.", key), nil
+	}
+
+	item := &types.TableSubscriptionData{
+		PullRequest:  key,
+		SlackUserId:  slackUserId,
+		SubscribedAt: time.Now().Format(time.RFC3339),
+	}
+	if err := db.InsertSubscription(svc, item); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Watching %s, you will get a direct message on approvals, failed checks and the merge. `/pr-watch %s off` stops it.", key, key), nil
+}
+
+func watchApprovedMessage(repository string, number int, reviewer interface{}) string {
+	emoji := constants.Emoji()
+	return fmt.Sprintf("%s <@%s> approved <%s|%s#%d>.", emoji.Approved, reviewer, github.PullRequestUrl(repository, number), repository, number)
+}
+
+func watchChecksFailedMessage(repository string, number int) string {
+	emoji := constants.Emoji()
+	return fmt.Sprintf("%s Some checks were not successful on <%s|%s#%d>.", emoji.CheckFailed, github.PullRequestUrl(repository, number), repository, number)
+}
+
+func watchMergedMessage(repository string, number int, sender interface{}) string {
+	emoji := constants.Emoji()
+	return fmt.Sprintf("%s <@%s> merged <%s|%s#%d>.", emoji.Merged, sender, github.PullRequestUrl(repository, number), repository, number)
+}
+
+// direct message to the watchers of the pull request except the user behind the
+// event, failures are only logged as the thread was already notified
+func notifyWatchers(svc *awsdynamodb.DynamoDB, repository string, number int, actor interface{}, message string, zapLog *zap.Logger) {
+	watchers, err := db.ListSubscriptions(svc, audit.PullRequestKey(repository, number))
+	if err != nil {
+		zapLog.Warn("error list subscriptions",
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, slackUserId := range watchers {
+		if slackUserId == fmt.Sprintf("%v", actor) {
+			continue
+		}
+		if err := slack.SlackSendChannelMessage(slackUserId, message); err != nil {
+			zapLog.Warn("error slack send watcher message",
+				zap.String("user", slackUserId),
+				zap.Error(err),
+			)
+		}
+	}
+}
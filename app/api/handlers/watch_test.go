@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"testing"
+)
+
+func TestWatchCommandUsage(t *testing.T) {
+	for _, text := range []string{"", "off", "not a pull request"} {
+		reply, err := watchCommand("U1", text)
+		if err != nil || reply != watchUsage {
+			t.Errorf("%q: expected the usage, got %q %v", text, reply, err)
+		}
+	}
+}
+
+func TestWatchMessages(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "rodentskie")
+
+	tests := []struct {
+		result   string
+		expected string
+	}{
+		{watchApprovedMessage("api", 7, "UA"), ":approved: <@UA> approved <https://github.com/rodentskie/api/pull/7|api#7>."},
+		{watchChecksFailedMessage("api", 7), ":check-failed: Some checks were not successful on <https://github.com/rodentskie/api/pull/7|api#7>."},
+		{watchMergedMessage("api", 7, "UB"), ":merged: <@UB> merged <https://github.com/rodentskie/api/pull/7|api#7>."},
+	}
+
+	for _, tt := range tests {
+		if tt.result != tt.expected {
+			t.Errorf("got %q want %q", tt.result, tt.expected)
+		}
+	}
+}
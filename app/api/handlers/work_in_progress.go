@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"slack-pr-lambda/api/mail"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// title edits adding or removing the WIP prefix re-render the parent message,
+// removing it pings the requested reviewers that were held back
+func workInProgressEdit(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent, slackUsersMap map[string]interface{}, zapLog *zap.Logger) error {
+	title := event.Changes.GetTitle()
+	if title == nil || event.PullRequest == nil {
+		out.Trail.Skip("not a title edit")
+		return nil
+	}
+
+	workInProgress := types.WorkInProgress(event.PullRequest.GetTitle())
+	if workInProgress == types.WorkInProgress(title.GetFrom()) {
+		out.Trail.Skip("work in progress unchanged")
+		return nil
+	}
+
+	id := int(event.PullRequest.GetID())
+	number := event.PullRequest.GetNumber()
+	item, err := db.GetPullRequest(svc, id, number)
+	if errors.Is(err, db.ErrNoDataFound) {
+		out.Trail.Skip("pull request not tracked")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := db.UpdateWorkInProgress(svc, id, number, workInProgress, item.Version); err != nil {
+		return err
+	}
+	item.WorkInProgress = workInProgress
+
+	// tracked before the parent message was stored
+	if item.ParentMessage != "" {
+		if err := out.UpdateMessage(item.SlackTimeStamp, messages.ParentMessage(item, time.Now())); err != nil {
+			return err
+		}
+	}
+
+	if workInProgress {
+		return nil
+	}
+
+	// still held by an incomplete description, the author was nudged when
+	// the pull request was opened
+	if incomplete, _ := incompleteDescription(event.Repository.GetName(), event.PullRequest.GetBody(), zapLog); incomplete {
+		out.Trail.Skip("incomplete description")
+		return nil
+	}
+	return pingHeldReviewers(svc, out, event, item.SlackTimeStamp, slackUsersMap, zapLog)
+}
+
+// pings the requested reviewers held back by a WIP title or an incomplete
+// description
+func pingHeldReviewers(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent, timeStamp string, slackUsersMap map[string]interface{}, zapLog *zap.Logger) error {
+	author := event.PullRequest.GetUser().GetLogin()
+	reviewers, _ := withoutAuthor(requestedLogins(event.PullRequest), author)
+	if len(reviewers) == 0 {
+		out.Trail.Skip("no reviewers requested")
+		return nil
+	}
+
+	id := int(event.PullRequest.GetID())
+	number := event.PullRequest.GetNumber()
+	ooo := outOfOffice(svc, zapLog)
+	entry := mail.Entry{Repository: out.Repository, Number: number, CreatedAt: types.FormatTime(event.PullRequest.CreatedAt)}
+	emailed := emailReviewRequest(out, reviewers, entry, slackUsersMap, ooo, time.Now(), zapLog)
+	alternates := reviewAlternates(out.Repository, number, reviewers, author, ooo, true, time.Now(), zapLog)
+	return pingReviewers(svc, out, timeStamp, id, number, reviewers, reviewRequestMessage(reviewers, alternates, slackUsersMap, ooo, emailed, time.Now()), zapLog)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+func TestWorkInProgressEdit(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   types.WebhookEvent
+		skipped string
+	}{
+		{
+			name:    "body edit",
+			event:   types.WebhookEvent{PullRequest: &gogithub.PullRequest{}, Changes: &gogithub.EditChange{Body: &gogithub.EditBody{}}},
+			skipped: "not a title edit",
+		},
+		{
+			name: "still in progress",
+			event: types.WebhookEvent{
+				PullRequest: &gogithub.PullRequest{Title: gogithub.String("[WIP] reminders")},
+				Changes:     &gogithub.EditChange{Title: &gogithub.EditTitle{From: gogithub.String("WIP: reminders")}},
+			},
+			skipped: "work in progress unchanged",
+		},
+		{
+			name: "typo fixed",
+			event: types.WebhookEvent{
+				PullRequest: &gogithub.PullRequest{Title: gogithub.String("Add reminders")},
+				Changes:     &gogithub.EditChange{Title: &gogithub.EditTitle{From: gogithub.String("Add remindres")}},
+			},
+			skipped: "work in progress unchanged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trail := &audit.Trail{}
+			out := audit.Messenger{Repository: "api", Log: zap.NewNop(), Trail: trail}
+
+			if err := workInProgressEdit(nil, out, tt.event, map[string]interface{}{}, zap.NewNop()); err != nil {
+				t.Fatal(err)
+			}
+			if skipped := trail.Skipped(); len(skipped) != 1 || skipped[0] != tt.skipped {
+				t.Errorf("Expected %q to be skipped, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/types"
+	"strings"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+var getFailedJobs = github.GetFailedJobs
+
+// failed workflow runs post their failing jobs in the thread of the pull
+// request, the next passing run of the workflow posts a recovery note
+func workflowRunCompleted(svc *awsdynamodb.DynamoDB, out audit.Messenger, event types.WebhookEvent, zapLog *zap.Logger) error {
+	run := event.WorkflowRun
+	if run.GetStatus() != "completed" {
+		out.Trail.Skip("workflow run not completed")
+		return nil
+	}
+	// runs of the default branch and of forks carry no pull request
+	if len(run.PullRequests) == 0 {
+		out.Trail.Skip("workflow run without pull request")
+		return nil
+	}
+
+	pullRequestId := int(run.PullRequests[0].GetID())
+	pullRequestNumber := run.PullRequests[0].GetNumber()
+
+	switch run.GetConclusion() {
+	case "failure", "timed_out":
+		timeStamp, err := db.GetSlackTimeStamp(svc, pullRequestId, pullRequestNumber)
+		if err != nil {
+			return err
+		}
+		if !tracked(out.Trail, timeStamp) {
+			return nil
+		}
+
+		// the run link still points to the logs without the jobs
+		jobs, err := getFailedJobs(out.Repository, run.GetID())
+		if err != nil {
+			zapLog.Warn("error get failed jobs",
+				zap.Error(err),
+			)
+		}
+
+		if err := out.SendMessageThread(timeStamp, workflowFailedMessage(run, jobs)); err != nil {
+			return err
+		}
+		return db.AddFailedWorkflow(svc, pullRequestId, pullRequestNumber, run.GetName())
+	case "success":
+		recovered, err := db.RemoveFailedWorkflow(svc, pullRequestId, pullRequestNumber, run.GetName())
+		if err != nil {
+			return err
+		}
+		if !recovered {
+			out.Trail.Skip("workflow did not fail before")
+			return nil
+		}
+
+		timeStamp, err := db.GetSlackTimeStamp(svc, pullRequestId, pullRequestNumber)
+		if err != nil {
+			return err
+		}
+		if !tracked(out.Trail, timeStamp) {
+			return nil
+		}
+		return out.SendMessageThread(timeStamp, workflowRecoveredMessage(run))
+	}
+
+	out.Trail.Skip("workflow run " + run.GetConclusion())
+	return nil
+}
+
+// e.g. "Workflow <url|CI> failed :check-failed: <url|test>, <url|lint>"
+func workflowFailedMessage(run *gogithub.WorkflowRun, jobs []*gogithub.WorkflowJob) string {
+	emoji := constants.Emoji()
+
+	message := fmt.Sprintf("Workflow <%s|%s> failed %s", run.GetHTMLURL(), run.GetName(), emoji.CheckFailed)
+	if len(jobs) == 0 {
+		return message
+	}
+
+	links := []string{}
+	for _, job := range jobs {
+		links = append(links, fmt.Sprintf("<%s|%s>", job.GetHTMLURL(), job.GetName()))
+	}
+	return message + " " + strings.Join(links, ", ")
+}
+
+func workflowRecoveredMessage(run *gogithub.WorkflowRun) string {
+	emoji := constants.Emoji()
+
+	return fmt.Sprintf("%s Workflow <%s|%s> passed again.", emoji.Recovered, run.GetHTMLURL(), run.GetName())
+}
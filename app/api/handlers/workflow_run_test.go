@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/types"
+	"testing"
+
+	gogithub "github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+func TestWorkflowRunCompleted(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     *gogithub.WorkflowRun
+		skipped string
+	}{
+		{
+			name:    "in progress",
+			run:     &gogithub.WorkflowRun{Status: gogithub.String("in_progress")},
+			skipped: "workflow run not completed",
+		},
+		{
+			name:    "default branch",
+			run:     &gogithub.WorkflowRun{Status: gogithub.String("completed"), Conclusion: gogithub.String("failure")},
+			skipped: "workflow run without pull request",
+		},
+		{
+			name: "cancelled",
+			run: &gogithub.WorkflowRun{
+				Status:       gogithub.String("completed"),
+				Conclusion:   gogithub.String("cancelled"),
+				PullRequests: []*gogithub.PullRequest{{ID: gogithub.Int64(1), Number: gogithub.Int(2)}},
+			},
+			skipped: "workflow run cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trail := &audit.Trail{}
+			out := audit.Messenger{Repository: "api", Log: zap.NewNop(), Trail: trail}
+
+			if err := workflowRunCompleted(nil, out, types.WebhookEvent{WorkflowRun: tt.run}, zap.NewNop()); err != nil {
+				t.Fatal(err)
+			}
+			if skipped := trail.Skipped(); len(skipped) != 1 || skipped[0] != tt.skipped {
+				t.Errorf("Expected %q to be skipped, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+}
+
+func TestWorkflowFailedMessage(t *testing.T) {
+	run := &gogithub.WorkflowRun{Name: gogithub.String("CI"), HTMLURL: gogithub.String("https://github.com/o/api/actions/runs/5")}
+
+	if message := workflowFailedMessage(run, nil); message != "Workflow <https://github.com/o/api/actions/runs/5|CI> failed :check-failed:" {
+		t.Errorf("got %q", message)
+	}
+
+	jobs := []*gogithub.WorkflowJob{
+		{Name: gogithub.String("test"), HTMLURL: gogithub.String("https://github.com/o/api/actions/runs/5/job/7")},
+		{Name: gogithub.String("lint"), HTMLURL: gogithub.String("https://github.com/o/api/actions/runs/5/job/8")},
+	}
+	expected := "Workflow <https://github.com/o/api/actions/runs/5|CI> failed :check-failed: <https://github.com/o/api/actions/runs/5/job/7|test>, <https://github.com/o/api/actions/runs/5/job/8|lint>"
+	if message := workflowFailedMessage(run, jobs); message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
+
+func TestWorkflowRecoveredMessage(t *testing.T) {
+	run := &gogithub.WorkflowRun{Name: gogithub.String("CI"), HTMLURL: gogithub.String("https://github.com/o/api/actions/runs/6")}
+
+	if message := workflowRecoveredMessage(run); message != ":white_check_mark: Workflow <https://github.com/o/api/actions/runs/6|CI> passed again." {
+		t.Errorf("got %q", message)
+	}
+}
@@ -0,0 +1,191 @@
+package home
+
+import (
+	"encoding/json"
+	"fmt"
+	"slack-pr-lambda/api/dashboard"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/policy"
+	"slack-pr-lambda/reviewers"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"slices"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const RefreshActionId = "home_refresh"
+
+// render the "My queue" App Home tab of the slack user, login is the linked
+// github user and empty when the account is not linked
+func Publish(slackUserId string, login string, zapLog *zap.Logger) error {
+	if login == "" {
+		return slack.SlackPublishHome(slackUserId, []slack.HomeSection{
+			{Text: "Your Slack account is not linked to a GitHub user."},
+		})
+	}
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		conf, _ = config.ParseConfig("")
+	}
+
+	cal, err := calendar.Load()
+	if err != nil {
+		zapLog.Warn("error load holiday calendar",
+			zap.Error(err),
+		)
+	}
+
+	svc := db.DynamoDbConnection()
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return err
+	}
+
+	requested := dashboard.RequestedReviewers(items, "", zapLog)
+
+	// snoozes only matter on the pull requests waiting for the user
+	waiting := []string{}
+	for _, item := range items {
+		if slices.Contains(requested[item.ID], login) {
+			waiting = append(waiting, item.ID)
+		}
+	}
+	snoozes, err := db.BatchGetSnoozes(svc, waiting, login)
+	if err != nil {
+		return err
+	}
+
+	sections, err := Sections(login, items, requested, snoozes, conf, cal, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return slack.SlackPublishHome(slackUserId, sections)
+}
+
+// open pull requests authored by login and the ones still requesting its review,
+// oldest first. snoozes are the snooze expiries of login keyed by record id
+func Sections(login string, items []types.TablePullRequestData, requested map[string][]string, snoozes map[string]int64, conf *config.Config, cal calendar.Calendar, now time.Time) ([]slack.HomeSection, error) {
+	emoji := constants.Emoji()
+
+	authored := []types.TablePullRequestData{}
+	reviewing := []types.TablePullRequestData{}
+	for _, item := range items {
+		if item.Repository == "" {
+			continue
+		}
+		if item.Author == login {
+			authored = append(authored, item)
+		}
+		if slices.Contains(requested[item.ID], login) {
+			reviewing = append(reviewing, item)
+		}
+	}
+	for _, list := range [][]types.TablePullRequestData{authored, reviewing} {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].CreatedAt < list[j].CreatedAt
+		})
+	}
+
+	sections := []slack.HomeSection{
+		{
+			Text:    fmt.Sprintf("%s *My queue* · updated %s", emoji.PullRequest, slack.SlackDate(now, slack.DateTimeTokens)),
+			Buttons: []slack.SlackButton{{ActionId: RefreshActionId, Text: "Refresh"}},
+		},
+		{Text: fmt.Sprintf("*Your open pull requests* (%d)", len(authored))},
+	}
+	if len(authored) == 0 {
+		sections = append(sections, slack.HomeSection{Text: "No open pull requests."})
+	}
+	for _, item := range authored {
+		sections = append(sections, slack.HomeSection{Text: line(item, conf, cal, now)})
+	}
+
+	sections = append(sections, slack.HomeSection{Text: fmt.Sprintf("*Waiting for your review* (%d)", len(reviewing))})
+	if len(reviewing) == 0 {
+		sections = append(sections, slack.HomeSection{Text: "No review requests."})
+	}
+	for _, item := range reviewing {
+		text := line(item, conf, cal, now)
+		if until, ok := snoozes[item.ID]; ok && until > now.Unix() {
+			text += fmt.Sprintf("\n:zzz: Snoozed until %s", slack.SlackDate(time.Unix(until, 0), slack.DateTimeTokens))
+		}
+
+		buttons, err := snoozeButtons(item)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, slack.HomeSection{Text: text, Buttons: buttons})
+	}
+
+	return sections, nil
+}
+
+func line(item types.TablePullRequestData, conf *config.Config, cal calendar.Calendar, now time.Time) string {
+	text := fmt.Sprintf("<%s|%s#%d> %s · %s", github.PullRequestUrl(item.Repository, item.PullRequestId), item.Repository, item.PullRequestId, messages.AgeEmoji(messages.AgeBadge(item.CreatedAt, now)), messages.ApprovalsLine(item.Approvals, item.RequiredApprovals))
+
+	if sla := slaLine(item, conf, cal, now); sla != "" {
+		text += " · " + sla
+	}
+	if thread := dashboard.ThreadUrl(item); thread != "" {
+		text += fmt.Sprintf(" · <%s|thread>", thread)
+	}
+	return text
+}
+
+// first response SLA of the pull request, empty when no rule applies
+func slaLine(item types.TablePullRequestData, conf *config.Config, cal calendar.Calendar, now time.Time) string {
+	rule, ok := policy.Match(conf.Repo(item.Repository).SlaRules, item.Labels)
+	if !ok {
+		return ""
+	}
+
+	openedAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+	if err != nil {
+		return ""
+	}
+
+	status := policy.Evaluate(rule, cal, openedAt, item.FirstReviewAt, now)
+	elapsed := int(status.Elapsed.Hours())
+	switch {
+	case status.Responded && status.Breached:
+		return fmt.Sprintf("SLA missed: first review after %dh of %dh", elapsed, rule.FirstResponseHours)
+	case status.Responded:
+		return "SLA met"
+	case status.Breached:
+		return fmt.Sprintf(":rotating_light: SLA breached: waiting %dh of %dh", elapsed, rule.FirstResponseHours)
+	}
+	return fmt.Sprintf("SLA: waiting %dh of %dh", elapsed, rule.FirstResponseHours)
+}
+
+func snoozeButtons(item types.TablePullRequestData) ([]slack.SlackButton, error) {
+	value, err := json.Marshal(types.PullRequestActionValue{
+		ID:            item.ID,
+		PullRequestId: item.PullRequestId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buttons := []slack.SlackButton{}
+	for _, option := range reviewers.SnoozeOptions() {
+		buttons = append(buttons, slack.SlackButton{
+			ActionId: option.ActionId,
+			Text:     option.Text,
+			Value:    string(value),
+		})
+	}
+	return buttons, nil
+}
@@ -0,0 +1,86 @@
+package home
+
+import (
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestSections(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "rodentskie")
+	t.Setenv("SLACK_CHANNEL", "C1")
+	now := time.Date(2024, 3, 8, 13, 0, 0, 0, time.UTC)
+	conf, err := config.ParseConfig(`{"default": {"slaRules": [{"label": "hotfix", "firstResponseHours": 2}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []types.TablePullRequestData{
+		{ID: "1", PullRequestId: 7, Repository: "api", Author: "alice", CreatedAt: "2024-03-08T12:00:00Z", RequiredApprovals: 1, SlackTimeStamp: "1710000000.000100"},
+		{ID: "2", PullRequestId: 8, Repository: "web", Author: "bob", CreatedAt: "2024-03-08T10:00:00Z", RequiredApprovals: 2, Labels: []string{"hotfix"}},
+		{ID: "3", PullRequestId: 9, Repository: "web", Author: "bob", CreatedAt: "2024-03-08T09:00:00Z", RequiredApprovals: 1},
+	}
+	requested := map[string][]string{"2": {"alice"}, "3": {"carol"}}
+	snoozes := map[string]int64{"2": now.Add(time.Hour).Unix()}
+
+	sections, err := Sections("alice", items, requested, snoozes, conf, calendar.Calendar{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sections) != 5 {
+		t.Fatalf("Expected 5 sections, got %d", len(sections))
+	}
+	if sections[0].Buttons[0].ActionId != RefreshActionId {
+		t.Errorf("Expected a refresh button, got %+v", sections[0].Buttons)
+	}
+
+	expected := "<https://github.com/rodentskie/api/pull/7|api#7> :large_green_circle: · Approvals: 0/1 · <https://slack.com/archives/C1/p1710000000000100|thread>"
+	if sections[2].Text != expected {
+		t.Errorf("got %q want %q", sections[2].Text, expected)
+	}
+
+	expected = "<https://github.com/rodentskie/web/pull/8|web#8> :large_green_circle: · Approvals: 0/2 · :rotating_light: SLA breached: waiting 3h of 2h\n:zzz: Snoozed until <!date^1709906400^{date_short_pretty} {time}|2024-03-08 14:00 UTC>"
+	if sections[4].Text != expected {
+		t.Errorf("got %q want %q", sections[4].Text, expected)
+	}
+	if len(sections[4].Buttons) != 2 || sections[4].Buttons[0].Value != `{"id":"2","pullRequestId":8}` {
+		t.Errorf("Expected snooze buttons, got %+v", sections[4].Buttons)
+	}
+}
+
+func TestSectionsEmpty(t *testing.T) {
+	conf, _ := config.ParseConfig("")
+
+	sections, err := Sections("alice", nil, nil, nil, conf, calendar.Calendar{}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sections) != 5 || sections[2].Text != "No open pull requests." || sections[4].Text != "No review requests." {
+		t.Errorf("Expected empty queues, got %+v", sections)
+	}
+}
+
+func TestSlaLine(t *testing.T) {
+	now := time.Date(2024, 3, 8, 13, 0, 0, 0, time.UTC)
+	conf, _ := config.ParseConfig(`{"default": {"slaRules": [{"firstResponseHours": 4}]}}`)
+
+	tests := []struct {
+		item     types.TablePullRequestData
+		expected string
+	}{
+		{types.TablePullRequestData{Repository: "api", CreatedAt: "2024-03-08T12:00:00Z"}, "SLA: waiting 1h of 4h"},
+		{types.TablePullRequestData{Repository: "api", CreatedAt: "2024-03-08T12:00:00Z", FirstReviewAt: "2024-03-08T12:30:00Z"}, "SLA met"},
+		{types.TablePullRequestData{Repository: "api", CreatedAt: "2024-03-08T06:00:00Z", FirstReviewAt: "2024-03-08T12:00:00Z"}, "SLA missed: first review after 6h of 4h"},
+		{types.TablePullRequestData{Repository: "api", CreatedAt: "invalid"}, ""},
+	}
+
+	for _, tt := range tests {
+		if result := slaLine(tt.item, conf, calendar.Calendar{}, now); result != tt.expected {
+			t.Errorf("got %q want %q", result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package archive
+
+import (
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/s3"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// bucket of the closed pull request archives, archives older than
+// archiveRetentionDays are expired by S3 and kept forever when it is 0
+func Archive(ctx *pulumi.Context) error {
+	conf := config.New(ctx, "")
+	region := conf.Require("region")
+	env := conf.Require("env")
+	archiveBucket := conf.Get("archiveBucket")
+	archiveRetentionDays := conf.GetInt("archiveRetentionDays")
+
+	if archiveBucket == "" {
+		return nil
+	}
+
+	bucket, err := s3.NewBucketV2(ctx, "archive_bucket", &s3.BucketV2Args{
+		Bucket: pulumi.String(archiveBucket),
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"Bucket":      pulumi.String(archiveBucket),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s3.NewBucketPublicAccessBlock(ctx, "archive_bucket_public_access", &s3.BucketPublicAccessBlockArgs{
+		Bucket:                bucket.ID(),
+		BlockPublicAcls:       pulumi.Bool(true),
+		BlockPublicPolicy:     pulumi.Bool(true),
+		IgnorePublicAcls:      pulumi.Bool(true),
+		RestrictPublicBuckets: pulumi.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	if archiveRetentionDays <= 0 {
+		return nil
+	}
+
+	_, err = s3.NewBucketLifecycleConfigurationV2(ctx, "archive_bucket_retention", &s3.BucketLifecycleConfigurationV2Args{
+		Bucket: bucket.ID(),
+		Rules: s3.BucketLifecycleConfigurationV2RuleArray{
+			&s3.BucketLifecycleConfigurationV2RuleArgs{
+				Id:     pulumi.String("retention"),
+				Status: pulumi.String("Enabled"),
+				Filter: &s3.BucketLifecycleConfigurationV2RuleFilterArgs{
+					Prefix: pulumi.String("pull-requests/"),
+				},
+				Expiration: &s3.BucketLifecycleConfigurationV2RuleExpirationArgs{
+					Days: pulumi.Int(archiveRetentionDays),
+				},
+			},
+		},
+	})
+	return err
+}
@@ -0,0 +1,41 @@
+package archive
+
+import (
+	"slack-pr-lambda/pulumimock"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchive(t *testing.T) {
+	config := map[string]string{
+		"project:region":               "ap-southeast-2",
+		"project:env":                  "test",
+		"project:archiveBucket":        "testArchiveBucket",
+		"project:archiveRetentionDays": "365",
+	}
+
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		err := Archive(ctx)
+		assert.NoError(t, err)
+
+		return nil
+	}, pulumimock.WithMocksAndConfig("project", "stack", config, pulumimock.Mocks(0)))
+	assert.NoError(t, err)
+}
+
+func TestArchiveDisabled(t *testing.T) {
+	config := map[string]string{
+		"project:region": "ap-southeast-2",
+		"project:env":    "test",
+	}
+
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		err := Archive(ctx)
+		assert.NoError(t, err)
+
+		return nil
+	}, pulumimock.WithMocksAndConfig("project", "stack", config, pulumimock.Mocks(0)))
+	assert.NoError(t, err)
+}
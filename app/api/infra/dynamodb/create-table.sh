@@ -4,3 +4,20 @@ set -e
 
 sleep 3
 aws dynamodb create-table --cli-input-json file://table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://ooo-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://snooze-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://config-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://audit-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://dashboard-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://review-metrics-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://contributor-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://comment-batch-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://mute-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://subscription-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://email-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://deferred-mention-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://security-alert-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://event-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://checkpoint-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://recent-message-table.json --endpoint-url http://dynamodb-local:8000
+aws dynamodb create-table --cli-input-json file://pending-digest-table.json --endpoint-url http://dynamodb-local:8000
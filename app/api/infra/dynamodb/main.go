@@ -1,6 +1,8 @@
 package dynamodb
 
 import (
+	"slack-pr-lambda/api/infra/stage"
+
 	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/dynamodb"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
@@ -10,10 +12,32 @@ func DynamoDB(ctx *pulumi.Context) error {
 	conf := config.New(ctx, "")
 	region := conf.Require("region")
 	env := conf.Require("env")
-	tableName := conf.Require("tableName")
+	// "dev", "staging" or "prod" (default), the tables of another stage than
+	// prod are suffixed with it
+	environment := conf.Get("environment")
+	tableName := stage.TableName(conf.Require("tableName"), environment)
 	tableNameIndex := conf.Require("tableNameIndex")
+	oooTableName := stage.TableName(conf.Require("oooTableName"), environment)
+	snoozeTableName := stage.TableName(conf.Require("snoozeTableName"), environment)
+	configTableName := stage.TableName(conf.Require("configTableName"), environment)
+	auditTableName := stage.TableName(conf.Require("auditTableName"), environment)
+	eventTableName := stage.TableName(conf.Require("eventTableName"), environment)
+	dashboardTableName := stage.TableName(conf.Require("dashboardTableName"), environment)
+	reviewMetricsTableName := stage.TableName(conf.Require("reviewMetricsTableName"), environment)
+	contributorTableName := stage.TableName(conf.Require("contributorTableName"), environment)
+	commentBatchTableName := stage.TableName(conf.Require("commentBatchTableName"), environment)
+	muteTableName := stage.TableName(conf.Require("muteTableName"), environment)
+	subscriptionTableName := stage.TableName(conf.Require("subscriptionTableName"), environment)
+	emailTableName := stage.TableName(conf.Require("emailTableName"), environment)
+	deferredMentionTableName := stage.TableName(conf.Require("deferredMentionTableName"), environment)
+	securityAlertTableName := stage.TableName(conf.Require("securityAlertTableName"), environment)
+	checkpointTableName := stage.TableName(conf.Require("checkpointTableName"), environment)
+	recentMessageTableName := stage.TableName(conf.Require("recentMessageTableName"), environment)
+	pendingDigestTableName := stage.TableName(conf.Require("pendingDigestTableName"), environment)
+	// replica of the global tables, the lambda fails over to it
+	replicaRegion := conf.Get("replicaRegion")
 
-	_, err := dynamodb.NewTable(ctx, "pr_table", &dynamodb.TableArgs{
+	_, err := dynamodb.NewTable(ctx, "pr_table", replicated(&dynamodb.TableArgs{
 		Name:          pulumi.String(tableName),
 		BillingMode:   pulumi.String("PROVISIONED"),
 		ReadCapacity:  pulumi.Int(5),
@@ -44,10 +68,493 @@ func DynamoDB(ctx *pulumi.Context) error {
 			"Environment": pulumi.String(env),
 			"TableName":   pulumi.String(tableName),
 		},
-	})
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	_, err = dynamodb.NewTable(ctx, "ooo_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(oooTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("slackUserId"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("slackUserId"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(oooTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	_, err = dynamodb.NewTable(ctx, "snooze_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(snoozeTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("id"),
+		RangeKey:      pulumi.String("githubLogin"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("id"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("githubLogin"),
+				Type: pulumi.String("S"),
+			},
+		},
+		// expired snoozes are removed by dynamodb
+		Ttl: &dynamodb.TableTtlArgs{
+			AttributeName: pulumi.String("snoozeUntil"),
+			Enabled:       pulumi.Bool(true),
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(snoozeTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// every config document change is a new version, the latest one is served
+	_, err = dynamodb.NewTable(ctx, "config_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(configTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("id"),
+		RangeKey:      pulumi.String("version"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("id"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("version"),
+				Type: pulumi.String("N"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(configTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// sent Slack messages per "<repository>#<number>", ordered by send time
+	_, err = dynamodb.NewTable(ctx, "audit_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(auditTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("pullRequest"),
+		RangeKey:      pulumi.String("sentAt"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("pullRequest"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("sentAt"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(auditTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// processed webhooks per "<repository>#<number>", ordered by receive time
+	_, err = dynamodb.NewTable(ctx, "event_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(eventTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("pullRequest"),
+		RangeKey:      pulumi.String("receivedAt"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("pullRequest"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("receivedAt"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(eventTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// pinned open pull requests message per channel
+	_, err = dynamodb.NewTable(ctx, "dashboard_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(dashboardTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(1),
+		WriteCapacity: pulumi.Int(1),
+		HashKey:       pulumi.String("channel"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("channel"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(dashboardTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// lifecycle timestamps per "<repository>#<number>", kept after closing
+	_, err = dynamodb.NewTable(ctx, "review_metrics_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(reviewMetricsTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("pullRequest"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("pullRequest"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(reviewMetricsTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// authors per repository with their first pull request
+	_, err = dynamodb.NewTable(ctx, "contributor_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(contributorTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("repository"),
+		RangeKey:      pulumi.String("author"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("repository"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("author"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(contributorTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// review comment bursts per "<repository>#<number>#<login>"
+	_, err = dynamodb.NewTable(ctx, "comment_batch_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(commentBatchTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("id"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("id"),
+				Type: pulumi.String("S"),
+			},
+		},
+		// closed batches are removed by dynamodb
+		Ttl: &dynamodb.TableTtlArgs{
+			AttributeName: pulumi.String("expiresAt"),
+			Enabled:       pulumi.Bool(true),
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(commentBatchTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// muted thread notifications per "<repository>#<number>" and slack user
+	_, err = dynamodb.NewTable(ctx, "mute_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(muteTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("pullRequest"),
+		RangeKey:      pulumi.String("slackUserId"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("pullRequest"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("slackUserId"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(muteTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// /pr-watch subscribers per "<repository>#<number>" and slack user
+	_, err = dynamodb.NewTable(ctx, "subscription_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(subscriptionTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("pullRequest"),
+		RangeKey:      pulumi.String("slackUserId"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("pullRequest"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("slackUserId"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(subscriptionTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// /pr-email addresses per github login
+	_, err = dynamodb.NewTable(ctx, "email_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(emailTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("githubLogin"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("githubLogin"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(emailTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// mentions posted outside of the mention hours per "<repository>#<number>" and thread
+	_, err = dynamodb.NewTable(ctx, "deferred_mention_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(deferredMentionTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("pullRequest"),
+		RangeKey:      pulumi.String("threadTimeStamp"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("pullRequest"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("threadTimeStamp"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(deferredMentionTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// open security alerts per "<repository>#<kind>-<number>"
+	_, err = dynamodb.NewTable(ctx, "security_alert_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(securityAlertTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("alert"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("alert"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(securityAlertTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// completed steps of a delivery per event id, skipped when GitHub redelivers it
+	_, err = dynamodb.NewTable(ctx, "checkpoint_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(checkpointTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("eventId"),
+		RangeKey:      pulumi.String("step"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("eventId"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("step"),
+				Type: pulumi.String("S"),
+			},
+		},
+		// redeliveries are over long before checkpoints expire
+		Ttl: &dynamodb.TableTtlArgs{
+			AttributeName: pulumi.String("expiresAt"),
+			Enabled:       pulumi.Bool(true),
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(checkpointTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// hashes of the recently posted Slack messages, identical ones are not posted twice
+	_, err = dynamodb.NewTable(ctx, "recent_message_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(recentMessageTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("messageHash"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("messageHash"),
+				Type: pulumi.String("S"),
+			},
+		},
+		// the dedup window is over long before dynamodb removes them
+		Ttl: &dynamodb.TableTtlArgs{
+			AttributeName: pulumi.String("expiresAt"),
+			Enabled:       pulumi.Bool(true),
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(recentMessageTableName),
+		},
+	}, replicaRegion))
+	if err != nil {
+		return err
+	}
+
+	// digests and reports triggered outside of the digest window, delivered once it opens
+	_, err = dynamodb.NewTable(ctx, "pending_digest_table", replicated(&dynamodb.TableArgs{
+		Name:          pulumi.String(pendingDigestTableName),
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		HashKey:       pulumi.String("job"),
+		Attributes: dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("job"),
+				Type: pulumi.String("S"),
+			},
+		},
+		Tags: pulumi.StringMap{
+			"Region":      pulumi.String(region),
+			"Environment": pulumi.String(env),
+			"TableName":   pulumi.String(pendingDigestTableName),
+		},
+	}, replicaRegion))
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// global table replicated to replicaRegion, replicas need streams and on
+// demand capacity. Unchanged without a replica region
+func replicated(args *dynamodb.TableArgs, replicaRegion string) *dynamodb.TableArgs {
+	if replicaRegion == "" {
+		return args
+	}
+
+	args.BillingMode = pulumi.String("PAY_PER_REQUEST")
+	args.ReadCapacity = nil
+	args.WriteCapacity = nil
+	if indexes, ok := args.GlobalSecondaryIndexes.(dynamodb.TableGlobalSecondaryIndexArray); ok {
+		for _, index := range indexes {
+			if index, ok := index.(*dynamodb.TableGlobalSecondaryIndexArgs); ok {
+				index.ReadCapacity = nil
+				index.WriteCapacity = nil
+			}
+		}
+	}
+
+	args.StreamEnabled = pulumi.Bool(true)
+	args.StreamViewType = pulumi.String("NEW_AND_OLD_IMAGES")
+	args.Replicas = dynamodb.TableReplicaTypeArray{
+		&dynamodb.TableReplicaTypeArgs{
+			RegionName: pulumi.String(replicaRegion),
+		},
+	}
+	return args
+}
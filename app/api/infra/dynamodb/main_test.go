@@ -4,16 +4,34 @@ import (
 	"slack-pr-lambda/pulumimock"
 	"testing"
 
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/dynamodb"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestDynamoDB(t *testing.T) {
 	config := map[string]string{
-		"project:region":         "ap-southeast-2",
-		"project:env":            "test",
-		"project:tableName":      "testTable",
-		"project:tableNameIndex": "testTableIndex",
+		"project:region":                   "ap-southeast-2",
+		"project:env":                      "test",
+		"project:tableName":                "testTable",
+		"project:tableNameIndex":           "testTableIndex",
+		"project:oooTableName":             "testOooTable",
+		"project:snoozeTableName":          "testSnoozeTable",
+		"project:commentBatchTableName":    "testCommentBatchTable",
+		"project:configTableName":          "testConfigTable",
+		"project:auditTableName":           "testAuditTable",
+		"project:dashboardTableName":       "testDashboardTable",
+		"project:reviewMetricsTableName":   "testReviewMetricsTable",
+		"project:contributorTableName":     "testContributorTable",
+		"project:muteTableName":            "testMuteTable",
+		"project:subscriptionTableName":    "testSubscriptionTable",
+		"project:emailTableName":           "testEmailTable",
+		"project:deferredMentionTableName": "testDeferredMentionTable",
+		"project:eventTableName":           "testEventTable",
+		"project:securityAlertTableName":   "testSecurityAlertTable",
+		"project:checkpointTableName":      "testCheckpointTable",
+		"project:recentMessageTableName":   "testRecentMessageTable",
+		"project:pendingDigestTableName":   "testPendingDigestTable",
 	}
 
 	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
@@ -24,3 +42,32 @@ func TestDynamoDB(t *testing.T) {
 	}, pulumimock.WithMocksAndConfig("project", "stack", config, pulumimock.Mocks(0)))
 	assert.NoError(t, err)
 }
+
+func TestReplicated(t *testing.T) {
+	args := &dynamodb.TableArgs{
+		BillingMode:   pulumi.String("PROVISIONED"),
+		ReadCapacity:  pulumi.Int(5),
+		WriteCapacity: pulumi.Int(5),
+		GlobalSecondaryIndexes: dynamodb.TableGlobalSecondaryIndexArray{
+			&dynamodb.TableGlobalSecondaryIndexArgs{
+				Name:          pulumi.String("index"),
+				ReadCapacity:  pulumi.Int(5),
+				WriteCapacity: pulumi.Int(5),
+			},
+		},
+	}
+
+	assert.Same(t, args, replicated(args, ""))
+	assert.Nil(t, args.Replicas)
+
+	replicated(args, "us-west-2")
+	assert.Equal(t, pulumi.String("PAY_PER_REQUEST"), args.BillingMode)
+	assert.Nil(t, args.ReadCapacity)
+	assert.Nil(t, args.WriteCapacity)
+	index := args.GlobalSecondaryIndexes.(dynamodb.TableGlobalSecondaryIndexArray)[0].(*dynamodb.TableGlobalSecondaryIndexArgs)
+	assert.Nil(t, index.ReadCapacity)
+	assert.Nil(t, index.WriteCapacity)
+	assert.Equal(t, pulumi.Bool(true), args.StreamEnabled)
+	assert.Equal(t, pulumi.String("NEW_AND_OLD_IMAGES"), args.StreamViewType)
+	assert.Equal(t, dynamodb.TableReplicaTypeArray{&dynamodb.TableReplicaTypeArgs{RegionName: pulumi.String("us-west-2")}}, args.Replicas)
+}
@@ -2,6 +2,7 @@ package lambda
 
 import (
 	"encoding/base64"
+	"slack-pr-lambda/api/infra/stage"
 	"time"
 
 	"github.com/pulumi/pulumi-aws-apigateway/sdk/v2/go/apigateway"
@@ -11,16 +12,137 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 )
 
-func LambdaFunction(ctx *pulumi.Context, role *iam.Role) error {
+func LambdaFunction(ctx *pulumi.Context, role *iam.Role) (*lambda.Function, error) {
 	conf := config.New(ctx, "")
 	lambdaFunctionName := conf.Require("lambdaFunctionName")
 	slackToken := conf.Require("slackToken")
 	slackChannel := conf.Require("slackChannel")
 	env := conf.Require("env")
+	// "dev", "staging" or "prod" (default). Another stage than prod uses its own
+	// tables, prefixes the messages with it and posts them all to sandboxChannel
+	// (slackChannel when unset)
+	environment := conf.Get("environment")
+	sandboxChannel := conf.Get("sandboxChannel")
+	// failures injected into the Slack and DynamoDB calls outside of prod, e.g.
+	// "slack.chat.postMessage=429x2,dynamodb=throttle"
+	faults := conf.Get("faults")
 	dbEndpoint := conf.Require("dbEndpoint")
 	region := conf.Require("region")
 	githubOwner := conf.Require("githubOwner")
 	githubToken := conf.Require("githubToken")
+	// tokens by owner or instance host used before githubToken, e.g.
+	// "acme=ghp_1,github.example.com=ghp_2"
+	githubTokens := conf.Get("githubTokens")
+	tableName := stage.TableName(conf.Require("tableName"), environment)
+	oooTableName := stage.TableName(conf.Require("oooTableName"), environment)
+	snoozeTableName := stage.TableName(conf.Require("snoozeTableName"), environment)
+	configTableName := stage.TableName(conf.Require("configTableName"), environment)
+	auditTableName := stage.TableName(conf.Require("auditTableName"), environment)
+	dashboardTableName := stage.TableName(conf.Require("dashboardTableName"), environment)
+	reviewMetricsTableName := stage.TableName(conf.Require("reviewMetricsTableName"), environment)
+	contributorTableName := stage.TableName(conf.Require("contributorTableName"), environment)
+	commentBatchTableName := stage.TableName(conf.Require("commentBatchTableName"), environment)
+	muteTableName := stage.TableName(conf.Require("muteTableName"), environment)
+	subscriptionTableName := stage.TableName(conf.Require("subscriptionTableName"), environment)
+	emailTableName := stage.TableName(conf.Require("emailTableName"), environment)
+	deferredMentionTableName := stage.TableName(conf.Require("deferredMentionTableName"), environment)
+	eventTableName := stage.TableName(conf.Require("eventTableName"), environment)
+	// appends every processed webhook of a pull request to the events table
+	eventSourcing := conf.Get("eventSourcing")
+	securityAlertTableName := stage.TableName(conf.Require("securityAlertTableName"), environment)
+	checkpointTableName := stage.TableName(conf.Require("checkpointTableName"), environment)
+	recentMessageTableName := stage.TableName(conf.Require("recentMessageTableName"), environment)
+	pendingDigestTableName := stage.TableName(conf.Require("pendingDigestTableName"), environment)
+	// seconds an identical message isn't posted again to the same thread
+	dedupWindowSeconds := conf.Get("dedupWindowSeconds")
+	// milliseconds a webhook may take before its steps are logged as over budget
+	eventBudgetMs := conf.Get("eventBudgetMs")
+	// MB of the function, the lambda default (128) when unset. "true" logs the
+	// allocations of every invocation to tune it, and serves the runtime
+	// profiles on the admin API
+	memorySize := conf.GetInt("memorySize")
+	allocLogging := conf.Get("allocLogging")
+	pprofEnabled := conf.Get("pprofEnabled")
+	repoConfig := conf.Require("repoConfig")
+	dryRun := conf.Require("dryRun")
+	// ops channel for failure alerts, alerts are off when unset
+	alertChannel := conf.Get("alertChannel")
+	// unhandled webhook actions, "ignore" (default) or "reject", and the channel
+	// receiving their payload
+	unhandledActions := conf.Get("unhandledActions")
+	unhandledChannel := conf.Get("unhandledChannel")
+	// "true" logs payload drift of the webhook deliveries
+	strictDecoding := conf.Get("strictDecoding")
+	// answer of a panicking action, "ack" (default) or "retry"
+	panicResponse := conf.Get("panicResponse")
+	// verified SES sender of the reviewer emails, emails are off when unset
+	emailFrom := conf.Get("emailFrom")
+	// closed pull request threads are exported to this S3 bucket, archiving is off when unset
+	archiveBucket := conf.Get("archiveBucket")
+	// weekly abandoned pull requests report, SLACK_CHANNEL when unset
+	abandonedReportChannel := conf.Get("abandonedReportChannel")
+	// monthly reviewer leaderboard, disabled when unset
+	leaderboardChannel := conf.Get("leaderboardChannel")
+	// urls receiving a summary of every processed webhook, e.g.
+	// "https://a.example.com/hook,https://b.example.com/hook", and the HMAC
+	// secret signing it, set with `nx infra.secret api --key=forwardSecret --value=...`
+	forwardUrls := conf.Get("forwardUrls")
+	forwardSecret := conf.Get("forwardSecret")
+	// EventBridge bus receiving the processed webhooks, publishing is off when unset
+	eventBusName := conf.Get("eventBusName")
+	// Firehose delivery stream of the data warehouse, streaming is off when unset
+	firehoseStream := conf.Get("firehoseStream")
+	// working days of reminders, e.g. "true", "2024-12-25,2024-12-26" and an ICS feed of public holidays
+	pauseWeekends := conf.Get("pauseWeekends")
+	holidays := conf.Get("holidays")
+	holidayCalendarUrl := conf.Get("holidayCalendarUrl")
+	calendarTimezone := conf.Get("calendarTimezone")
+	// channels where mentions never notify, e.g. "C0123,C0456", and the hours
+	// mentions notify on working days, e.g. "09:00-18:00"
+	quietChannels := conf.Get("quietChannels")
+	mentionHours := conf.Get("mentionHours")
+	// e.g. "09:00-09:30" of the working days, digests and reports triggered
+	// outside of it are held until it opens. digestJobs overrides the held jobs
+	digestWindow := conf.Get("digestWindow")
+	digestJobs := conf.Get("digestJobs")
+	// language of the messages (en, fr or ja) and per channel, e.g. "C0123=ja"
+	language := conf.Get("language")
+	channelLanguages := conf.Get("channelLanguages")
+	// thread replies also shown in the channel by event, e.g.
+	// "C0123=approved|merged|checks_failed", none when unset
+	broadcastEvents := conf.Get("broadcastEvents")
+	// images of a description or comment shown in the thread, 3 when unset and
+	// 0 turns them off
+	threadImages := conf.Get("threadImages")
+	// channel of the security alerts, they are ignored when unset. Hours to fix
+	// an alert per severity, e.g. "critical=24,high=168", and between reminders
+	securityChannel := conf.Get("securityChannel")
+	securitySlaHours := conf.Get("securitySlaHours")
+	securityReminderHours := conf.Get("securityReminderHours")
+	// hours between reviewer reminders of the same pull request, 24 when unset
+	reminderIntervalHours := conf.Get("reminderIntervalHours")
+	// days closed pull request records are kept for a restore, 0 deletes them
+	softDeleteDays := conf.Get("softDeleteDays")
+	// e.g. https://acme.slack.com, Slack links of /prs go through slack.com when unset
+	slackWorkspaceUrl := conf.Get("slackWorkspaceUrl")
+	// GitHub Enterprise Server, e.g. https://github.example.com and https://github.example.com/api/v3/
+	githubUrl := conf.Get("githubUrl")
+	githubApiUrl := conf.Get("githubApiUrl")
+	webhookPath := conf.Get("webhookPath")
+	if webhookPath == "" {
+		webhookPath = "/pull-request"
+	}
+	// set with `nx infra.secret api --key=slackSigningSecret --value=...`
+	slackSigningSecret := conf.Get("slackSigningSecret")
+	// secret of the GitHub webhook, set with `nx infra.secret api --key=githubWebhookSecret --value=...`
+	githubWebhookSecret := conf.Get("githubWebhookSecret")
+	// X-Webhook-Token of forwarders stripping the signature, set with `nx infra.secret api --key=webhookToken --value=...`
+	webhookToken := conf.Get("webhookToken")
+	// bearer token of the admin API, set with `nx infra.secret api --key=adminToken --value=...`
+	adminToken := conf.Get("adminToken")
+	// replica of the global tables and its endpoint, e.g. https://dynamodb.us-west-2.amazonaws.com
+	replicaRegion := conf.Get("replicaRegion")
+	replicaDbEndpoint := conf.Get("replicaDbEndpoint")
 
 	// built zip file
 	fileName := "../bin/bootstrap.zip"
@@ -30,6 +152,11 @@ func LambdaFunction(ctx *pulumi.Context, role *iam.Role) error {
 	dateTimeBytes := []byte(dateTimeString)
 	base64EncodedHash := base64.StdEncoding.EncodeToString(dateTimeBytes)
 
+	var memory pulumi.IntPtrInput
+	if memorySize > 0 {
+		memory = pulumi.Int(memorySize)
+	}
+
 	lambdaFn, err := lambda.NewFunction(ctx, "test_lambda", &lambda.FunctionArgs{
 		Code:           pulumi.NewFileArchive(fileName),
 		Name:           pulumi.String(lambdaFunctionName),
@@ -37,15 +164,85 @@ func LambdaFunction(ctx *pulumi.Context, role *iam.Role) error {
 		Handler:        pulumi.String("bootstrap"),
 		SourceCodeHash: pulumi.String(base64EncodedHash),
 		Runtime:        pulumi.String("provided.al2023"),
+		MemorySize:     memory,
 		Environment: &lambda.FunctionEnvironmentArgs{
 			Variables: pulumi.StringMap{
-				"ENV":           pulumi.String(env),
-				"SLACK_TOKEN":   pulumi.String(slackToken),
-				"SLACK_CHANNEL": pulumi.String(slackChannel),
-				"DB_ENDPOINT":   pulumi.String(dbEndpoint),
-				"REGION":        pulumi.String(region),
-				"GITHUB_TOKEN":  pulumi.String(githubToken),
-				"GITHUB_OWNER":  pulumi.String(githubOwner),
+				"ENV":                         pulumi.String(env),
+				"ENVIRONMENT":                 pulumi.String(environment),
+				"SANDBOX_CHANNEL":             pulumi.String(sandboxChannel),
+				"FAULTS":                      pulumi.String(faults),
+				"TABLE_NAME":                  pulumi.String(tableName),
+				"SLACK_TOKEN":                 pulumi.String(slackToken),
+				"SLACK_CHANNEL":               pulumi.String(slackChannel),
+				"DB_ENDPOINT":                 pulumi.String(dbEndpoint),
+				"REGION":                      pulumi.String(region),
+				"FAILOVER_REGION":             pulumi.String(replicaRegion),
+				"FAILOVER_DB_ENDPOINT":        pulumi.String(replicaDbEndpoint),
+				"GITHUB_TOKEN":                pulumi.String(githubToken),
+				"GITHUB_TOKENS":               pulumi.String(githubTokens),
+				"GITHUB_OWNER":                pulumi.String(githubOwner),
+				"OOO_TABLE_NAME":              pulumi.String(oooTableName),
+				"SNOOZE_TABLE_NAME":           pulumi.String(snoozeTableName),
+				"SLACK_SIGNING_SECRET":        pulumi.String(slackSigningSecret),
+				"GITHUB_WEBHOOK_SECRET":       pulumi.String(githubWebhookSecret),
+				"WEBHOOK_TOKEN":               pulumi.String(webhookToken),
+				"CONFIG_TABLE_NAME":           pulumi.String(configTableName),
+				"AUDIT_TABLE_NAME":            pulumi.String(auditTableName),
+				"DASHBOARD_TABLE_NAME":        pulumi.String(dashboardTableName),
+				"REVIEW_METRICS_TABLE_NAME":   pulumi.String(reviewMetricsTableName),
+				"CONTRIBUTOR_TABLE_NAME":      pulumi.String(contributorTableName),
+				"COMMENT_BATCH_TABLE_NAME":    pulumi.String(commentBatchTableName),
+				"MUTE_TABLE_NAME":             pulumi.String(muteTableName),
+				"SUBSCRIPTION_TABLE_NAME":     pulumi.String(subscriptionTableName),
+				"EMAIL_TABLE_NAME":            pulumi.String(emailTableName),
+				"DEFERRED_MENTION_TABLE_NAME": pulumi.String(deferredMentionTableName),
+				"EVENT_TABLE_NAME":            pulumi.String(eventTableName),
+				"EVENT_SOURCING":              pulumi.String(eventSourcing),
+				"SECURITY_ALERT_TABLE_NAME":   pulumi.String(securityAlertTableName),
+				"CHECKPOINT_TABLE_NAME":       pulumi.String(checkpointTableName),
+				"RECENT_MESSAGE_TABLE_NAME":   pulumi.String(recentMessageTableName),
+				"PENDING_DIGEST_TABLE_NAME":   pulumi.String(pendingDigestTableName),
+				"DEDUP_WINDOW_SECONDS":        pulumi.String(dedupWindowSeconds),
+				"EVENT_BUDGET_MS":             pulumi.String(eventBudgetMs),
+				"ALLOC_LOGGING":               pulumi.String(allocLogging),
+				"PPROF_ENABLED":               pulumi.String(pprofEnabled),
+				"REPO_CONFIG":                 pulumi.String(repoConfig),
+				"DRY_RUN":                     pulumi.String(dryRun),
+				"ALERT_CHANNEL":               pulumi.String(alertChannel),
+				"UNHANDLED_ACTIONS":           pulumi.String(unhandledActions),
+				"UNHANDLED_CHANNEL":           pulumi.String(unhandledChannel),
+				"STRICT_DECODING":             pulumi.String(strictDecoding),
+				"PANIC_RESPONSE":              pulumi.String(panicResponse),
+				"ABANDONED_REPORT_CHANNEL":    pulumi.String(abandonedReportChannel),
+				"LEADERBOARD_CHANNEL":         pulumi.String(leaderboardChannel),
+				"FORWARD_URLS":                pulumi.String(forwardUrls),
+				"FORWARD_SECRET":              pulumi.String(forwardSecret),
+				"EVENT_BUS_NAME":              pulumi.String(eventBusName),
+				"FIREHOSE_STREAM":             pulumi.String(firehoseStream),
+				"ARCHIVE_BUCKET":              pulumi.String(archiveBucket),
+				"EMAIL_FROM":                  pulumi.String(emailFrom),
+				"PAUSE_WEEKENDS":              pulumi.String(pauseWeekends),
+				"HOLIDAYS":                    pulumi.String(holidays),
+				"HOLIDAY_CALENDAR_URL":        pulumi.String(holidayCalendarUrl),
+				"CALENDAR_TIMEZONE":           pulumi.String(calendarTimezone),
+				"QUIET_CHANNELS":              pulumi.String(quietChannels),
+				"MENTION_HOURS":               pulumi.String(mentionHours),
+				"DIGEST_WINDOW":               pulumi.String(digestWindow),
+				"DIGEST_JOBS":                 pulumi.String(digestJobs),
+				"LANGUAGE":                    pulumi.String(language),
+				"CHANNEL_LANGUAGES":           pulumi.String(channelLanguages),
+				"BROADCAST_EVENTS":            pulumi.String(broadcastEvents),
+				"THREAD_IMAGES":               pulumi.String(threadImages),
+				"SECURITY_CHANNEL":            pulumi.String(securityChannel),
+				"SECURITY_SLA_HOURS":          pulumi.String(securitySlaHours),
+				"SECURITY_REMINDER_HOURS":     pulumi.String(securityReminderHours),
+				"REMINDER_INTERVAL_HOURS":     pulumi.String(reminderIntervalHours),
+				"SOFT_DELETE_DAYS":            pulumi.String(softDeleteDays),
+				"ADMIN_TOKEN":                 pulumi.String(adminToken),
+				"SLACK_WORKSPACE_URL":         pulumi.String(slackWorkspaceUrl),
+				"GITHUB_URL":                  pulumi.String(githubUrl),
+				"GITHUB_API_URL":              pulumi.String(githubApiUrl),
+				"WEBHOOK_PATH":                pulumi.String(webhookPath),
 			},
 		},
 		Tags: pulumi.StringMap{
@@ -54,24 +251,79 @@ func LambdaFunction(ctx *pulumi.Context, role *iam.Role) error {
 	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	methodGet := apigateway.MethodGET
 	methodPost := apigateway.MethodPOST
+	methodDelete := apigateway.MethodDELETE
 	_, err = apigateway.NewRestAPI(ctx, "api_slack_pr", &apigateway.RestAPIArgs{
 		Routes: []apigateway.RouteArgs{
 			{
 				Path: "/", Method: &methodGet, EventHandler: lambdaFn,
 			},
 			{
-				Path: "/pull-request", Method: &methodPost, EventHandler: lambdaFn,
+				Path: webhookPath, Method: &methodPost, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/slack/commands", Method: &methodPost, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/slack/interactions", Method: &methodPost, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/slack/events", Method: &methodPost, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/healthz", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/metrics", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/prs", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/threads/{repository}/{number}", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/openapi.json", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/pull-requests/{repository}/{number}/resend", Method: &methodPost, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/pull-requests/{repository}/{number}/messages", Method: &methodDelete, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/pull-requests/{repository}/{number}/restore", Method: &methodPost, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/pull-requests/{repository}/{number}/events", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/review-metrics", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/archives/{repository}/{number}", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/users/{slackUserId}", Method: &methodDelete, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/features/{repository}", Method: &methodGet, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/contributors/{repository}/seed", Method: &methodPost, EventHandler: lambdaFn,
+			},
+			{
+				Path: "/admin/debug/pprof/{profile}", Method: &methodGet, EventHandler: lambdaFn,
 			},
 		},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return lambdaFn, nil
 }
@@ -11,15 +11,37 @@ import (
 
 func TestLambdaFunction(t *testing.T) {
 	config := map[string]string{
-		"project:lambdaRoleName":     "testRoleName",
-		"project:lambdaFunctionName": "testLambdaFunctionName",
-		"project:slackToken":         "testToken",
-		"project:slackChannel":       "testChannel",
-		"project:env":                "test",
-		"project:dbEndpoint":         "testEndpoint",
-		"project:region":             "ap-southeast-2",
-		"project:githubOwner":        "foo",
-		"project:githubToken":        "bar",
+		"project:lambdaRoleName":           "testRoleName",
+		"project:lambdaFunctionName":       "testLambdaFunctionName",
+		"project:slackToken":               "testToken",
+		"project:slackChannel":             "testChannel",
+		"project:env":                      "test",
+		"project:environment":              "staging",
+		"project:tableName":                "testTable",
+		"project:dbEndpoint":               "testEndpoint",
+		"project:region":                   "ap-southeast-2",
+		"project:githubOwner":              "foo",
+		"project:githubToken":              "bar",
+		"project:oooTableName":             "testOooTable",
+		"project:snoozeTableName":          "testSnoozeTable",
+		"project:commentBatchTableName":    "testCommentBatchTable",
+		"project:configTableName":          "testConfigTable",
+		"project:auditTableName":           "testAuditTable",
+		"project:dashboardTableName":       "testDashboardTable",
+		"project:reviewMetricsTableName":   "testReviewMetricsTable",
+		"project:contributorTableName":     "testContributorTable",
+		"project:muteTableName":            "testMuteTable",
+		"project:subscriptionTableName":    "testSubscriptionTable",
+		"project:emailTableName":           "testEmailTable",
+		"project:deferredMentionTableName": "testDeferredMentionTable",
+		"project:eventTableName":           "testEventTable",
+		"project:securityAlertTableName":   "testSecurityAlertTable",
+		"project:checkpointTableName":      "testCheckpointTable",
+		"project:recentMessageTableName":   "testRecentMessageTable",
+		"project:pendingDigestTableName":   "testPendingDigestTable",
+		"project:repoConfig":               "{}",
+		"project:dryRun":                   "false",
+		"project:memorySize":               "256",
 	}
 
 	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
@@ -27,8 +49,9 @@ func TestLambdaFunction(t *testing.T) {
 			Arn: pulumi.Sprintf("%s", "fakeArn"),
 		}
 
-		err := LambdaFunction(ctx, role)
+		lambdaFn, err := LambdaFunction(ctx, role)
 		assert.NoError(t, err)
+		assert.NotNil(t, lambdaFn)
 
 		return nil
 	}, pulumimock.WithMocksAndConfig("project", "stack", config, pulumimock.Mocks(0)))
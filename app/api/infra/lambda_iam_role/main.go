@@ -53,6 +53,48 @@ func LambdaIamRole(ctx *pulumi.Context) (*iam.Role, error) {
 				},
 				Effect: &allow,
 			},
+			{
+				// closed pull request archives
+				Actions: []string{
+					"s3:GetObject",
+					"s3:PutObject",
+					"s3:ListBucket",
+				},
+				Resources: []string{
+					"*",
+				},
+				Effect: &allow,
+			},
+			{
+				// reviewer emails
+				Actions: []string{
+					"ses:SendEmail",
+				},
+				Resources: []string{
+					"*",
+				},
+				Effect: &allow,
+			},
+			{
+				// pull request events published to EventBridge
+				Actions: []string{
+					"events:PutEvents",
+				},
+				Resources: []string{
+					"*",
+				},
+				Effect: &allow,
+			},
+			{
+				// pull request events streamed to the data warehouse
+				Actions: []string{
+					"firehose:PutRecordBatch",
+				},
+				Resources: []string{
+					"*",
+				},
+				Effect: &allow,
+			},
 		},
 	}, nil)
 	if err != nil {
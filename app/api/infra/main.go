@@ -1,9 +1,11 @@
 package main
 
 import (
+	"slack-pr-lambda/api/infra/archive"
 	"slack-pr-lambda/api/infra/dynamodb"
 	"slack-pr-lambda/api/infra/lambda"
 	lambdaiamrole "slack-pr-lambda/api/infra/lambda_iam_role"
+	"slack-pr-lambda/api/infra/scheduler"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
@@ -14,13 +16,22 @@ func main() {
 		if err != nil {
 			return err
 		}
-		if err := lambda.LambdaFunction(ctx, role); err != nil {
+		lambdaFn, err := lambda.LambdaFunction(ctx, role)
+		if err != nil {
+			return err
+		}
+
+		if err := scheduler.Scheduler(ctx, lambdaFn); err != nil {
 			return err
 		}
 
 		if err := dynamodb.DynamoDB(ctx); err != nil {
 			return err
 		}
+
+		if err := archive.Archive(ctx); err != nil {
+			return err
+		}
 		return nil
 	})
 }
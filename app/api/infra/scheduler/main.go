@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lambda"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// EventBridge rules invoking the lambda with {"job": "<name>"}, and with
+// {"warm": true} on warmSchedule when it is set. The digests job runs on
+// digestSchedule when it is set
+func Scheduler(ctx *pulumi.Context, lambdaFn *lambda.Function) error {
+	conf := config.New(ctx, "")
+	reminderSchedule := conf.Require("reminderSchedule")
+	ageSchedule := conf.Require("ageSchedule")
+	dashboardSchedule := conf.Require("dashboardSchedule")
+	rollupSchedule := conf.Require("rollupSchedule")
+	followUpSchedule := conf.Require("followUpSchedule")
+	abandonedSchedule := conf.Require("abandonedSchedule")
+	slaSchedule := conf.Require("slaSchedule")
+	escalationSchedule := conf.Require("escalationSchedule")
+	mentionSchedule := conf.Require("mentionSchedule")
+	securitySchedule := conf.Require("securitySchedule")
+	purgeSchedule := conf.Require("purgeSchedule")
+	leaderboardSchedule := conf.Require("leaderboardSchedule")
+	// e.g. "rate(5 minutes)", keeps an instance warm for low traffic
+	// repositories, off when unset
+	warmSchedule := conf.Get("warmSchedule")
+	// e.g. "rate(15 minutes)", delivers the digests held outside of the digest
+	// window, off when unset
+	digestSchedule := conf.Get("digestSchedule")
+
+	schedules := map[string]string{
+		"reminders":   reminderSchedule,
+		"age":         ageSchedule,
+		"dashboard":   dashboardSchedule,
+		"rollup":      rollupSchedule,
+		"followups":   followUpSchedule,
+		"abandoned":   abandonedSchedule,
+		"sla":         slaSchedule,
+		"escalations": escalationSchedule,
+		"mentions":    mentionSchedule,
+		"security":    securitySchedule,
+		"purge":       purgeSchedule,
+		"leaderboard": leaderboardSchedule,
+	}
+
+	for job, schedule := range schedules {
+		if err := invokeOn(ctx, lambdaFn, job, schedule, fmt.Sprintf(`{"job":"%s"}`, job)); err != nil {
+			return err
+		}
+	}
+
+	if digestSchedule != "" {
+		if err := invokeOn(ctx, lambdaFn, "digests", digestSchedule, `{"job":"digests"}`); err != nil {
+			return err
+		}
+	}
+
+	if warmSchedule != "" {
+		return invokeOn(ctx, lambdaFn, "warm", warmSchedule, `{"warm":true}`)
+	}
+	return nil
+}
+
+func invokeOn(ctx *pulumi.Context, lambdaFn *lambda.Function, name string, schedule string, input string) error {
+	rule, err := cloudwatch.NewEventRule(ctx, fmt.Sprintf("%s_schedule", name), &cloudwatch.EventRuleArgs{
+		ScheduleExpression: pulumi.String(schedule),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = cloudwatch.NewEventTarget(ctx, fmt.Sprintf("%s_target", name), &cloudwatch.EventTargetArgs{
+		Rule:  rule.Name,
+		Arn:   lambdaFn.Arn,
+		Input: pulumi.String(input),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = lambda.NewPermission(ctx, fmt.Sprintf("%s_permission", name), &lambda.PermissionArgs{
+		Action:    pulumi.String("lambda:InvokeFunction"),
+		Function:  lambdaFn.Name,
+		Principal: pulumi.String("events.amazonaws.com"),
+		SourceArn: rule.Arn,
+	})
+	return err
+}
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"slack-pr-lambda/pulumimock"
+	"testing"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lambda"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	config := map[string]string{
+		"project:reminderSchedule":    "rate(1 day)",
+		"project:ageSchedule":         "rate(1 hour)",
+		"project:dashboardSchedule":   "rate(1 hour)",
+		"project:rollupSchedule":      "rate(1 hour)",
+		"project:followUpSchedule":    "rate(1 hour)",
+		"project:abandonedSchedule":   "rate(7 days)",
+		"project:slaSchedule":         "rate(15 minutes)",
+		"project:escalationSchedule":  "rate(15 minutes)",
+		"project:mentionSchedule":     "rate(15 minutes)",
+		"project:securitySchedule":    "rate(1 hour)",
+		"project:purgeSchedule":       "rate(1 day)",
+		"project:leaderboardSchedule": "cron(0 9 1 * ? *)",
+		"project:warmSchedule":        "rate(5 minutes)",
+		"project:digestSchedule":      "rate(15 minutes)",
+	}
+
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		lambdaFn := &lambda.Function{
+			Arn:  pulumi.Sprintf("%s", "fakeArn"),
+			Name: pulumi.Sprintf("%s", "fakeName"),
+		}
+
+		err := Scheduler(ctx, lambdaFn)
+		assert.NoError(t, err)
+
+		return nil
+	}, pulumimock.WithMocksAndConfig("project", "stack", config, pulumimock.Mocks(0)))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,13 @@
+package stage
+
+import "fmt"
+
+// table name of the environment, prod keeps the configured names so the
+// existing tables stay in place, another stage gets its own tables, e.g.
+// "PullRequests-staging"
+func TableName(name string, environment string) string {
+	if environment == "" || environment == "prod" {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", name, environment)
+}
@@ -0,0 +1,19 @@
+package stage
+
+import "testing"
+
+func TestTableName(t *testing.T) {
+	for _, test := range []struct {
+		environment string
+		expected    string
+	}{
+		{"", "PullRequests"},
+		{"prod", "PullRequests"},
+		{"staging", "PullRequests-staging"},
+		{"dev", "PullRequests-dev"},
+	} {
+		if name := TableName("PullRequests", test.environment); name != test.expected {
+			t.Errorf("%s: got %s want %s", test.environment, name, test.expected)
+		}
+	}
+}
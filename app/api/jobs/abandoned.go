@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const abandonedPeriod = 7 * 24 * time.Hour
+
+// weekly report of the pull requests closed without merging, posted to
+// ABANDONED_REPORT_CHANNEL (SLACK_CHANNEL by default) so work doesn't silently
+// disappear. Nothing is posted for a week without abandoned pull requests
+func Abandoned() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	records, err := db.ListReviewMetrics(db.DynamoDbConnection())
+	if err != nil {
+		return err
+	}
+
+	abandoned := abandonedPullRequests(records, time.Now())
+	if len(abandoned) == 0 {
+		return nil
+	}
+
+	slackUsersMap := mapstruct.StructToMap(*constants.SlackUsers())
+	channel := env.GetEnv("ABANDONED_REPORT_CHANNEL", "")
+	if channel == "" {
+		channel = env.GetEnv("SLACK_CHANNEL", "")
+	}
+	if err := slack.SlackSendChannelMessage(channel, abandonedMessage(abandoned, slackUsersMap)); err != nil {
+		zapLog.Error("error slack send abandoned report",
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// closed without merging within the last week, most recently closed first
+func abandonedPullRequests(records []types.TableReviewMetricsData, now time.Time) []types.TableReviewMetricsData {
+	result := []types.TableReviewMetricsData{}
+	for _, record := range records {
+		if db.ReviewMetricsState(record) != db.StateAbandoned || record.Repository == "" {
+			continue
+		}
+
+		closedAt, err := time.Parse(time.RFC3339, record.ClosedAt)
+		if err != nil || now.Sub(closedAt) > abandonedPeriod {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ClosedAt > result[j].ClosedAt
+	})
+	return result
+}
+
+func abandonedMessage(records []types.TableReviewMetricsData, slackUsersMap map[string]interface{}) string {
+	emoji := constants.Emoji()
+
+	lines := []string{fmt.Sprintf("%s *Abandoned PRs* closed without merging in the last 7 days (%d)", emoji.Closed, len(records))}
+	for _, record := range records {
+		line := fmt.Sprintf("• <%s|%s#%d>", github.PullRequestUrl(record.Repository, record.Number), record.Repository, record.Number)
+		if user, ok := slackUsersMap[record.Author]; ok {
+			line += fmt.Sprintf(" by <@%s>", user)
+		} else if record.Author != "" {
+			line += fmt.Sprintf(" by %s", record.Author)
+		}
+		if closedAt, err := time.Parse(time.RFC3339, record.ClosedAt); err == nil {
+			line += fmt.Sprintf(" · closed %s", slack.SlackDate(closedAt, slack.DateTokens))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestAbandonedPullRequests(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	records := []types.TableReviewMetricsData{
+		{Repository: "api", Number: 1, State: "abandoned", ClosedAt: "2024-03-08T10:00:00Z"},
+		{Repository: "api", Number: 2, State: "merged", ClosedAt: "2024-03-08T10:00:00Z", MergedAt: "2024-03-08T10:00:00Z"},
+		{Repository: "api", Number: 3, State: "abandoned", ClosedAt: "2024-02-20T10:00:00Z"},
+		{Repository: "web", Number: 4, ClosedAt: "2024-03-09T10:00:00Z"},
+		{Repository: "web", Number: 5, State: "open"},
+	}
+
+	result := abandonedPullRequests(records, now)
+
+	if len(result) != 2 || result[0].Number != 4 || result[1].Number != 1 {
+		t.Errorf("Unexpected abandoned pull requests %v", result)
+	}
+}
+
+func TestAbandonedMessage(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "o")
+	emoji := constants.Emoji()
+
+	records := []types.TableReviewMetricsData{
+		{Repository: "api", Number: 1, Author: "alice", ClosedAt: "2024-03-08T10:00:00Z"},
+		{Repository: "web", Number: 4, Author: "carol", ClosedAt: "2024-03-09T10:00:00Z"},
+	}
+
+	message := abandonedMessage(records, map[string]interface{}{"alice": "UA"})
+	expected := emoji.Closed + " *Abandoned PRs* closed without merging in the last 7 days (2)\n" +
+		"• <https://github.com/o/api/pull/1|api#1> by <@UA> · closed <!date^1709892000^{date_short_pretty}|2024-03-08>\n" +
+		"• <https://github.com/o/web/pull/4|web#4> by carol · closed <!date^1709978400^{date_short_pretty}|2024-03-09>"
+	if message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
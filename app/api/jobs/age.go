@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/types"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// re-render the parent message of open pull requests whose age badge changed,
+// closed pull requests are no longer tracked
+func Age() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tasks := []func() error{}
+	for _, item := range agedPullRequests(items, now) {
+		tasks = append(tasks, func() error {
+			badge := messages.AgeBadge(item.CreatedAt, now)
+
+			out := audit.Messenger{
+				Source:     "age",
+				Repository: item.Repository,
+				Number:     item.PullRequestId,
+				Log:        zapLog,
+			}
+			if err := out.UpdateMessage(item.SlackTimeStamp, messages.ParentMessage(&item, now)); err != nil {
+				zapLog.Error("error slack update age",
+					zap.String("repository", item.Repository),
+					zap.Int("number", item.PullRequestId),
+					zap.Error(err),
+				)
+				return err
+			}
+
+			id, err := strconv.Atoi(item.ID)
+			if err != nil {
+				return err
+			}
+			return db.UpdateAgeBadge(svc, id, item.PullRequestId, badge)
+		})
+	}
+
+	return pool.Run(pool.Size(), tasks)
+}
+
+// pull requests with a rendered parent message whose badge is not current
+func agedPullRequests(items []types.TablePullRequestData, now time.Time) []types.TablePullRequestData {
+	result := []types.TablePullRequestData{}
+	for _, item := range items {
+		// tracked before the parent message was rendered from the record
+		if item.ParentMessage == "" || item.SlackTimeStamp == "" {
+			continue
+		}
+
+		badge := messages.AgeBadge(item.CreatedAt, now)
+		if badge == "" || badge == item.AgeBadge {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
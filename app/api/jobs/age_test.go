@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"slack-pr-lambda/api/messages"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestAgedPullRequests(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	items := []types.TablePullRequestData{
+		{ID: "1", ParentMessage: "opened", SlackTimeStamp: "1.1", CreatedAt: "2024-03-10T10:00:00Z", AgeBadge: messages.AgeFresh},
+		{ID: "2", ParentMessage: "opened", SlackTimeStamp: "1.2", CreatedAt: "2024-03-08T10:00:00Z", AgeBadge: messages.AgeFresh},
+		{ID: "3", ParentMessage: "opened", SlackTimeStamp: "1.3", CreatedAt: "2024-03-01T10:00:00Z"},
+		{ID: "4", SlackTimeStamp: "1.4", CreatedAt: "2024-03-01T10:00:00Z"},
+		{ID: "5", ParentMessage: "opened", SlackTimeStamp: "1.5"},
+	}
+
+	result := agedPullRequests(items, now)
+
+	if len(result) != 2 || result[0].ID != "2" || result[1].ID != "3" {
+		t.Errorf("Unexpected aged pull requests %v", result)
+	}
+}
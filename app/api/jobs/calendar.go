@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"slack-pr-lambda/calendar"
+
+	"go.uber.org/zap"
+)
+
+// without the holiday feed only the static holidays and weekends are paused
+func loadCalendar(zapLog *zap.Logger) calendar.Calendar {
+	cal, err := calendar.Load()
+	if err != nil {
+		zapLog.Warn("error load holiday calendar",
+			zap.Error(err),
+		)
+	}
+	return cal
+}
@@ -0,0 +1,17 @@
+package jobs
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestLoadCalendar(t *testing.T) {
+	t.Setenv("HOLIDAYS", "2024-12-25")
+	t.Setenv("HOLIDAY_CALENDAR_URL", "http://127.0.0.1:0/holidays.ics")
+
+	cal := loadCalendar(zap.NewNop())
+	if !cal.Holidays["2024-12-25"] {
+		t.Errorf("Expected the static holidays without the feed, got %v", cal.Holidays)
+	}
+}
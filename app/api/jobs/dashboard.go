@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"slack-pr-lambda/api/dashboard"
+	"slack-pr-lambda/logger"
+	"syscall"
+)
+
+// keeps the ages of the pinned open pull requests message current between events
+func Dashboard() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	return dashboard.Refresh(zapLog)
+}
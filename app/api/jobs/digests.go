@@ -0,0 +1,143 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"slack-pr-lambda/calendar"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/mentions"
+	"slack-pr-lambda/types"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DIGEST_WINDOW ("09:00-09:30") of the working days, in CALENDAR_TIMEZONE.
+// Digests and reports are delivered whenever they are triggered when unset
+func digestWindow() (mentions.Policy, error) {
+	window := mentions.Policy{}
+
+	hours := env.GetEnv("DIGEST_WINDOW", "")
+	if hours == "" {
+		return window, nil
+	}
+
+	start, end, err := mentions.ParseHours(hours)
+	if err != nil {
+		return window, err
+	}
+	window.Start, window.End = start, end
+
+	window.Calendar, err = calendar.Load()
+	return window, err
+}
+
+// DIGEST_JOBS ("reminders,abandoned,leaderboard"), the jobs held until the
+// digest window
+func digestJobs() map[string]bool {
+	jobs := map[string]bool{}
+	for _, name := range strings.Split(env.GetEnv("DIGEST_JOBS", "reminders,abandoned,leaderboard"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			jobs[name] = true
+		}
+	}
+	return jobs
+}
+
+// start of the digest window the job waits for, false when it runs now
+func heldUntil(window mentions.Policy, jobs map[string]bool, name string, now time.Time) (time.Time, bool) {
+	if !jobs[name] || window.InWindow(now) {
+		return time.Time{}, false
+	}
+	return window.NextWindow(now), true
+}
+
+// holds the job triggered outside of the digest window, the digests job
+// delivers it once the window opens
+func holdDigest(name string, now time.Time) (bool, error) {
+	window, err := digestWindow()
+	if err != nil {
+		return false, err
+	}
+
+	dueAt, held := heldUntil(window, digestJobs(), name, now)
+	if !held {
+		return false, nil
+	}
+
+	svc, err := db.Connection()
+	if err != nil {
+		return false, err
+	}
+	return true, db.DeferDigest(svc, &types.TablePendingDigestData{Job: name, DueAt: dueAt.Unix()})
+}
+
+// runs the held digests and reports once their window opened. A window the
+// schedule missed is caught up on the next run, late rather than a day later.
+// Failed jobs stay pending and are retried on the next run
+func Digests() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListPendingDigests(svc)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	jobs := registry()
+	errs := []error{}
+	for _, item := range dueDigests(items, now) {
+		job, ok := jobs[item.Job]
+		if !ok {
+			zapLog.Warn("unknown pending digest",
+				zap.String("job", item.Job),
+			)
+			errs = append(errs, db.DeletePendingDigest(svc, item.Job))
+			continue
+		}
+
+		zapLog.Info("deliver pending digest",
+			zap.String("job", item.Job),
+			zap.Duration("late", now.Sub(time.Unix(item.DueAt, 0)).Truncate(time.Second)),
+		)
+		if err := job(); err != nil {
+			zapLog.Error("error deliver pending digest",
+				zap.String("job", item.Job),
+				zap.Error(err),
+			)
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, db.DeletePendingDigest(svc, item.Job))
+	}
+
+	return errors.Join(errs...)
+}
+
+func dueDigests(items []types.TablePendingDigestData, now time.Time) []types.TablePendingDigestData {
+	result := []types.TablePendingDigestData{}
+	for _, item := range items {
+		if item.Job == "" || item.DueAt > now.Unix() {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
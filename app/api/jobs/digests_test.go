@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/mentions"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestDigestWindow(t *testing.T) {
+	t.Setenv("DIGEST_WINDOW", "")
+	window, err := digestWindow()
+	if err != nil || window.End != 0 {
+		t.Errorf("Expected no window, got %v %v", window, err)
+	}
+
+	t.Setenv("DIGEST_WINDOW", "09:00-09:30")
+	t.Setenv("CALENDAR_TIMEZONE", "Asia/Tokyo")
+	t.Setenv("PAUSE_WEEKENDS", "true")
+	window, err = digestWindow()
+	if err != nil || window.Start != 9*time.Hour || window.End != 9*time.Hour+30*time.Minute {
+		t.Errorf("Expected 09:00-09:30, got %v %v", window, err)
+	}
+	if window.Calendar.Location == nil || window.Calendar.Location.String() != "Asia/Tokyo" {
+		t.Errorf("Expected the calendar timezone, got %v", window.Calendar.Location)
+	}
+
+	t.Setenv("DIGEST_WINDOW", "09:30-09:00")
+	if _, err := digestWindow(); err == nil {
+		t.Errorf("Expected error for an invalid window")
+	}
+}
+
+func TestDigestJobs(t *testing.T) {
+	jobs := digestJobs()
+	if !jobs["reminders"] || !jobs["abandoned"] || !jobs["leaderboard"] || jobs["sla"] {
+		t.Errorf("Expected the default digest jobs, got %v", jobs)
+	}
+
+	t.Setenv("DIGEST_JOBS", " reminders , ")
+	jobs = digestJobs()
+	if len(jobs) != 1 || !jobs["reminders"] {
+		t.Errorf("Expected only reminders, got %v", jobs)
+	}
+}
+
+func TestHeldUntil(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	window := mentions.Policy{
+		Start:    9 * time.Hour,
+		End:      9*time.Hour + 30*time.Minute,
+		Calendar: calendar.Calendar{Location: tokyo, Weekends: true},
+	}
+	jobs := map[string]bool{"reminders": true}
+
+	// friday 23:00 UTC is saturday 08:00 in Tokyo
+	friday := time.Date(2024, 3, 8, 23, 0, 0, 0, time.UTC)
+	dueAt, held := heldUntil(window, jobs, "reminders", friday)
+	if expected := time.Date(2024, 3, 11, 9, 0, 0, 0, tokyo); !held || !dueAt.Equal(expected) {
+		t.Errorf("Expected reminders held until monday 09:00, got %v %v", dueAt, held)
+	}
+
+	if _, held := heldUntil(window, jobs, "sla", friday); held {
+		t.Errorf("Expected other jobs to run")
+	}
+
+	monday := time.Date(2024, 3, 11, 9, 10, 0, 0, tokyo)
+	if _, held := heldUntil(window, jobs, "reminders", monday); held {
+		t.Errorf("Expected reminders to run within the window")
+	}
+
+	if _, held := heldUntil(mentions.Policy{}, jobs, "reminders", friday); held {
+		t.Errorf("Expected reminders to run without a window")
+	}
+}
+
+func TestDueDigests(t *testing.T) {
+	now := time.Date(2024, 3, 11, 0, 10, 0, 0, time.UTC)
+	items := []types.TablePendingDigestData{
+		{Job: "reminders", DueAt: now.Add(-10 * time.Minute).Unix()},
+		// missed window, caught up
+		{Job: "abandoned", DueAt: now.Add(-24 * time.Hour).Unix()},
+		{Job: "leaderboard", DueAt: now.Add(time.Hour).Unix()},
+		{DueAt: now.Unix()},
+	}
+
+	due := dueDigests(items, now)
+	if len(due) != 2 || due[0].Job != "reminders" || due[1].Job != "abandoned" {
+		t.Errorf("Expected reminders and abandoned to be due, got %v", due)
+	}
+}
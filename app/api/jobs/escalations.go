@@ -0,0 +1,183 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/reviewers"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// walk the repository escalation chain of pull requests waiting for a first
+// review, one level per run. The level reached is kept on the record so the
+// chain continues where it stopped on the next run
+func Escalations() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		conf, _ = config.ParseConfig("")
+	}
+
+	cal := loadCalendar(zapLog)
+	now := time.Now()
+	if !cal.Working(now) {
+		return nil
+	}
+
+	slackUsersMap := mapstruct.StructToMap(*constants.SlackUsers())
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return err
+	}
+
+	ooo, err := db.ListOutOfOffice(svc)
+	if err != nil {
+		zapLog.Warn("error list out of office",
+			zap.Error(err),
+		)
+		ooo = map[string]types.TableOutOfOfficeData{}
+	}
+
+	tasks := []func() error{}
+	for _, item := range items {
+		levels := conf.Repo(item.Repository).Escalation
+		level, waited, ok := dueEscalation(item, levels, cal, now)
+		if !ok {
+			continue
+		}
+
+		tasks = append(tasks, func() error {
+			id, err := strconv.Atoi(item.ID)
+			if err != nil {
+				return err
+			}
+
+			// another run notified this level
+			err = db.ClaimEscalationLevel(svc, id, item.PullRequestId, item.EscalationLevel)
+			if errors.Is(err, db.ErrEscalationClaimed) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := escalate(item, level, len(levels), waited, ooo, now, slackUsersMap, zapLog); err != nil {
+				zapLog.Error("error slack send escalation",
+					zap.String("repository", item.Repository),
+					zap.Int("number", item.PullRequestId),
+					zap.Int("level", item.EscalationLevel+1),
+					zap.Error(err),
+				)
+				return err
+			}
+			return nil
+		})
+	}
+
+	return pool.Run(pool.Size(), tasks)
+}
+
+// the next level of the chain once the pull request waited its afterHours of
+// working time without a review. WIP pull requests are not escalated
+func dueEscalation(item types.TablePullRequestData, levels []config.EscalationLevel, cal calendar.Calendar, now time.Time) (config.EscalationLevel, time.Duration, bool) {
+	if item.WorkInProgress || item.Repository == "" || item.SlackTimeStamp == "" || item.FirstReviewAt != "" || item.EscalationLevel >= len(levels) {
+		return config.EscalationLevel{}, 0, false
+	}
+
+	openedAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+	if err != nil {
+		return config.EscalationLevel{}, 0, false
+	}
+
+	level := levels[item.EscalationLevel]
+	waited := cal.Elapsed(openedAt, now)
+	if waited < time.Duration(level.AfterHours)*time.Hour {
+		return config.EscalationLevel{}, 0, false
+	}
+	return level, waited, true
+}
+
+// out of office reviewers are left out of the mentions
+func escalate(item types.TablePullRequestData, level config.EscalationLevel, levels int, waited time.Duration, ooo map[string]types.TableOutOfOfficeData, now time.Time, slackUsersMap map[string]interface{}, zapLog *zap.Logger) error {
+	logins := append([]string{}, level.Users...)
+	if level.Reviewers {
+		requested, err := github.GetRequestedReviewers(item.Repository, item.PullRequestId)
+		if err != nil {
+			return err
+		}
+		available, _ := reviewers.FilterOutOfOffice(requested, ooo, now)
+		logins = append(available, logins...)
+	}
+
+	step := item.EscalationLevel + 1
+	if len(logins) > 0 {
+		out := audit.Messenger{
+			Source:     "escalations",
+			Repository: item.Repository,
+			Number:     item.PullRequestId,
+			Log:        zapLog,
+		}
+		if err := out.SendMessageThread(item.SlackTimeStamp, escalationMessage(logins, step, levels, waited, slackUsersMap)); err != nil {
+			return err
+		}
+	}
+
+	if level.Channel != "" {
+		return slack.SlackSendChannelMessage(level.Channel, escalationChannelMessage(item, step, levels, waited))
+	}
+	return nil
+}
+
+func escalationMessage(logins []string, step int, levels int, waited time.Duration, slackUsersMap map[string]interface{}) string {
+	emoji := constants.Emoji()
+
+	mentions := []string{}
+	for _, login := range logins {
+		if user, ok := slackUsersMap[login]; ok {
+			mentions = append(mentions, fmt.Sprintf("<@%s>", user))
+		}
+	}
+
+	return fmt.Sprintf("%s Escalation %d/%d: %s please take a look, this pull request has waited %dh for a review.", emoji.Reminder, step, levels, strings.Join(mentions, " "), int(waited.Hours()))
+}
+
+func escalationChannelMessage(item types.TablePullRequestData, step int, levels int, waited time.Duration) string {
+	emoji := constants.Emoji()
+
+	url := github.PullRequestUrl(item.Repository, item.PullRequestId)
+	return fmt.Sprintf("%s <%s|%s#%d> has waited %dh for a review (escalation %d/%d).", emoji.Reminder, url, item.Repository, item.PullRequestId, int(waited.Hours()), step, levels)
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestDueEscalation(t *testing.T) {
+	now := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+	levels := []config.EscalationLevel{
+		{AfterHours: 24, Reviewers: true},
+		{AfterHours: 48, Users: []string{"bob"}},
+		{AfterHours: 72, Channel: "CENG"},
+	}
+
+	tests := []struct {
+		item     types.TablePullRequestData
+		expected int
+	}{
+		{types.TablePullRequestData{ID: "opened today", Repository: "api", SlackTimeStamp: "1.1", CreatedAt: "2024-03-08T10:00:00Z"}, 0},
+		{types.TablePullRequestData{ID: "first level", Repository: "api", SlackTimeStamp: "1.2", CreatedAt: "2024-03-07T10:00:00Z"}, 24},
+		{types.TablePullRequestData{ID: "second level", Repository: "api", SlackTimeStamp: "1.3", CreatedAt: "2024-03-04T10:00:00Z", EscalationLevel: 1}, 48},
+		{types.TablePullRequestData{ID: "reviewed", Repository: "api", SlackTimeStamp: "1.4", CreatedAt: "2024-03-04T10:00:00Z", FirstReviewAt: "2024-03-05T10:00:00Z"}, 0},
+		{types.TablePullRequestData{ID: "done", Repository: "api", SlackTimeStamp: "1.5", CreatedAt: "2024-03-01T10:00:00Z", EscalationLevel: 3}, 0},
+		{types.TablePullRequestData{ID: "work in progress", Repository: "api", SlackTimeStamp: "1.6", CreatedAt: "2024-03-07T10:00:00Z", WorkInProgress: true}, 0},
+	}
+
+	for _, tt := range tests {
+		level, _, ok := dueEscalation(tt.item, levels, calendar.Calendar{}, now)
+		if ok != (tt.expected > 0) || level.AfterHours != tt.expected {
+			t.Errorf("%s: expected level after %dh, got %v %v", tt.item.ID, tt.expected, level, ok)
+		}
+	}
+}
+
+func TestEscalationMessage(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "o")
+	emoji := constants.Emoji()
+
+	message := escalationMessage([]string{"alice", "bob", "unknown"}, 2, 3, 49*time.Hour, map[string]interface{}{"alice": "UA", "bob": "UB"})
+	if expected := emoji.Reminder + " Escalation 2/3: <@UA> <@UB> please take a look, this pull request has waited 49h for a review."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+
+	message = escalationChannelMessage(types.TablePullRequestData{Repository: "api", PullRequestId: 7}, 3, 3, 72*time.Hour)
+	if expected := emoji.Reminder + " <https://github.com/o/api/pull/7|api#7> has waited 72h for a review (escalation 3/3)."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
@@ -0,0 +1,138 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DM the author of pull requests where changes were requested and nothing was
+// pushed within the repository followUpAfterHours (FOLLOW_UP_AFTER_HOURS by
+// default) of the calendar, once per changes requested review
+func FollowUps() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	followUpAfter, err := strconv.Atoi(env.GetEnv("FOLLOW_UP_AFTER_HOURS", "24"))
+	if err != nil {
+		return err
+	}
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		conf, _ = config.ParseConfig("")
+	}
+
+	cal := loadCalendar(zapLog)
+	now := time.Now()
+	if !cal.Working(now) {
+		return nil
+	}
+
+	slackUsersMap := mapstruct.StructToMap(*constants.SlackUsers())
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return err
+	}
+
+	tasks := []func() error{}
+	for _, item := range dueFollowUps(items, conf, cal, followUpAfter, now) {
+		user, ok := slackUsersMap[item.Author]
+		if !ok {
+			zapLog.Warn("error follow-up author not mapped",
+				zap.String("author", item.Author),
+			)
+			continue
+		}
+
+		tasks = append(tasks, func() error {
+			if err := slack.SlackSendChannelMessage(fmt.Sprintf("%s", user), followUpMessage(item, slackUsersMap, now)); err != nil {
+				zapLog.Error("error slack send follow-up",
+					zap.String("repository", item.Repository),
+					zap.Int("number", item.PullRequestId),
+					zap.Error(err),
+				)
+				return err
+			}
+
+			id, err := strconv.Atoi(item.ID)
+			if err != nil {
+				return err
+			}
+			return db.ClearChangesRequested(svc, id, item.PullRequestId)
+		})
+	}
+
+	return pool.Run(pool.Size(), tasks)
+}
+
+// pull requests with changes requested longer ago than the follow-up window,
+// the author of a WIP pull request is still on it
+func dueFollowUps(items []types.TablePullRequestData, conf *config.Config, cal calendar.Calendar, followUpAfter int, now time.Time) []types.TablePullRequestData {
+	result := []types.TablePullRequestData{}
+	for _, item := range items {
+		requestedAt, err := time.Parse(time.RFC3339, item.ChangesRequestedAt)
+		if err != nil || item.Author == "" || item.WorkInProgress {
+			continue
+		}
+
+		after := followUpAfter
+		if hours := conf.Repo(item.Repository).FollowUpAfterHours; hours > 0 {
+			after = hours
+		}
+		if cal.Elapsed(requestedAt, now) < time.Duration(after)*time.Hour {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func followUpMessage(item types.TablePullRequestData, slackUsersMap map[string]interface{}, now time.Time) string {
+	emoji := constants.Emoji()
+
+	hours := 0
+	if requestedAt, err := time.Parse(time.RFC3339, item.ChangesRequestedAt); err == nil {
+		hours = int(now.Sub(requestedAt).Hours())
+	}
+
+	reviewer := item.ChangesRequestedBy
+	if user, ok := slackUsersMap[reviewer]; ok {
+		reviewer = fmt.Sprintf("<@%s>", user)
+	}
+
+	url := github.PullRequestUrl(item.Repository, item.PullRequestId)
+	return fmt.Sprintf("%s Friendly nudge: %s requested <%s|changes> on <%s|%s#%d> %dh ago and no new commits were pushed since.", emoji.Reminder, reviewer, item.ChangesRequestedUrl, url, item.Repository, item.PullRequestId, hours)
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestDueFollowUps(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	conf, err := config.ParseConfig(`{"repositories": {"web": {"followUpAfterHours": 4}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []types.TablePullRequestData{
+		{ID: "1", Repository: "api", Author: "alice", ChangesRequestedAt: "2024-03-09T10:00:00Z"},
+		{ID: "2", Repository: "api", Author: "alice", ChangesRequestedAt: "2024-03-10T10:00:00Z"},
+		{ID: "3", Repository: "web", Author: "alice", ChangesRequestedAt: "2024-03-10T07:00:00Z"},
+		{ID: "4", Repository: "api", Author: "alice"},
+		{ID: "5", Repository: "api", ChangesRequestedAt: "2024-03-09T10:00:00Z"},
+		{ID: "6", Repository: "api", Author: "alice", ChangesRequestedAt: "2024-03-09T10:00:00Z", WorkInProgress: true},
+	}
+
+	result := dueFollowUps(items, conf, calendar.Calendar{}, 24, now)
+
+	if len(result) != 2 || result[0].ID != "1" || result[1].ID != "3" {
+		t.Errorf("Unexpected follow-ups %v", result)
+	}
+}
+
+func TestFollowUpMessage(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "o")
+	emoji := constants.Emoji()
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	item := types.TablePullRequestData{
+		Repository:          "api",
+		PullRequestId:       7,
+		ChangesRequestedAt:  "2024-03-09T10:00:00Z",
+		ChangesRequestedBy:  "bob",
+		ChangesRequestedUrl: "https://github.com/o/api/pull/7#pullrequestreview-1",
+	}
+
+	message := followUpMessage(item, map[string]interface{}{"bob": "UB"}, now)
+	expected := emoji.Reminder + " Friendly nudge: <@UB> requested <https://github.com/o/api/pull/7#pullrequestreview-1|changes> on <https://github.com/o/api/pull/7|api#7> 26h ago and no new commits were pushed since."
+	if message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
+
+func TestDueFollowUpsWeekend(t *testing.T) {
+	// Monday noon, changes requested Friday afternoon
+	now := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	conf, _ := config.ParseConfig("")
+	items := []types.TablePullRequestData{
+		{ID: "1", Repository: "api", Author: "alice", ChangesRequestedAt: "2024-03-08T16:00:00Z"},
+	}
+
+	if result := dueFollowUps(items, conf, calendar.Calendar{Weekends: true}, 24, now); len(result) != 0 {
+		t.Errorf("Expected the weekend to be paused, got %v", result)
+	}
+	if result := dueFollowUps(items, conf, calendar.Calendar{}, 24, now); len(result) != 1 {
+		t.Errorf("Expected a follow-up, got %v", result)
+	}
+}
@@ -0,0 +1,209 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reviewers named on the leaderboard
+const leaderboardSize = 5
+
+// events after which the pull request waits for a review, the turnaround of a
+// review runs from the last of them
+var reviewRequestActions = map[string]bool{
+	"opened":           true,
+	"reopened":         true,
+	"ready_for_review": true,
+	"review_requested": true,
+}
+
+type leaderboardEntry struct {
+	Login   string
+	Reviews int
+	// median from the review request to the first review of each pull
+	// request, 0 without a request in the events
+	Median time.Duration
+}
+
+// monthly top reviewers of the previous month, posted to LEADERBOARD_CHANNEL
+// (opt-in, nothing is posted when unset). Computed from the events table so
+// EVENT_SOURCING must be on
+func Leaderboard() error {
+	channel := env.GetEnv("LEADERBOARD_CHANNEL", "")
+	if channel == "" {
+		return nil
+	}
+
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	from, to := previousMonth(time.Now())
+	svc := db.DynamoDbConnection()
+
+	events, err := db.ListEventsBetween(svc, from, to)
+	if err != nil {
+		return err
+	}
+
+	// the review request of a pull request may be older than the month
+	timelines := map[string][]types.TableEventData{}
+	for _, event := range events {
+		if !reviewEvent(event) || timelines[event.PullRequest] != nil {
+			continue
+		}
+		timeline, err := db.ListEvents(svc, event.PullRequest)
+		if err != nil {
+			return err
+		}
+		timelines[event.PullRequest] = timeline
+	}
+
+	entries := leaderboard(timelines, from, to)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	slackUsersMap := mapstruct.StructToMap(*constants.SlackUsers())
+	if err := slack.SlackSendChannelMessage(channel, leaderboardMessage(entries, from, slackUsersMap)); err != nil {
+		zapLog.Error("error slack send leaderboard",
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// first and last instant (excluded) of the month before now, in UTC
+func previousMonth(now time.Time) (time.Time, time.Time) {
+	to := time.Date(now.UTC().Year(), now.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	return to.AddDate(0, -1, 0), to
+}
+
+func reviewEvent(event types.TableEventData) bool {
+	return event.Event == "pull_request_review" && event.Action == "submitted"
+}
+
+// reviewers by reviews submitted from (included) to (excluded), then by the
+// fastest median turnaround. Only the first review of a reviewer on a pull
+// request counts, the author answering on their own pull request doesn't
+func leaderboard(timelines map[string][]types.TableEventData, from time.Time, to time.Time) []leaderboardEntry {
+	reviews := map[string]int{}
+	turnarounds := map[string][]time.Duration{}
+
+	for _, timeline := range timelines {
+		author := ""
+		var requestedAt time.Time
+		reviewed := map[string]bool{}
+
+		for _, event := range timeline {
+			receivedAt, err := time.Parse(time.RFC3339Nano, event.ReceivedAt)
+			if err != nil {
+				continue
+			}
+			if event.Event == "pull_request" && reviewRequestActions[event.Action] {
+				if event.Action == "opened" {
+					author = event.Actor
+				}
+				requestedAt = receivedAt
+				continue
+			}
+
+			if !reviewEvent(event) || event.Actor == "" || event.Actor == author || reviewed[event.Actor] {
+				continue
+			}
+			reviewed[event.Actor] = true
+			if receivedAt.Before(from) || !receivedAt.Before(to) {
+				continue
+			}
+
+			reviews[event.Actor]++
+			if !requestedAt.IsZero() {
+				turnarounds[event.Actor] = append(turnarounds[event.Actor], receivedAt.Sub(requestedAt))
+			}
+		}
+	}
+
+	entries := []leaderboardEntry{}
+	for login, count := range reviews {
+		entries = append(entries, leaderboardEntry{Login: login, Reviews: count, Median: median(turnarounds[login])})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Reviews != entries[j].Reviews {
+			return entries[i].Reviews > entries[j].Reviews
+		}
+		if entries[i].Median != entries[j].Median {
+			// reviewers without a turnaround last
+			return entries[j].Median == 0 || (entries[i].Median != 0 && entries[i].Median < entries[j].Median)
+		}
+		return entries[i].Login < entries[j].Login
+	})
+
+	if len(entries) > leaderboardSize {
+		entries = entries[:leaderboardSize]
+	}
+	return entries
+}
+
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func leaderboardMessage(entries []leaderboardEntry, month time.Time, slackUsersMap map[string]interface{}) string {
+	medals := []string{":first_place_medal:", ":second_place_medal:", ":third_place_medal:"}
+
+	lines := []string{fmt.Sprintf(":trophy: *Top reviewers of %s*, thanks for the timely reviews!", month.Format("January 2006"))}
+	for i, entry := range entries {
+		rank := fmt.Sprintf("%d.", i+1)
+		if i < len(medals) {
+			rank = medals[i]
+		}
+
+		reviewer := entry.Login
+		if user, ok := slackUsersMap[entry.Login]; ok {
+			reviewer = fmt.Sprintf("<@%s>", user)
+		}
+
+		line := fmt.Sprintf("%s %s · %d reviews", rank, reviewer, entry.Reviews)
+		if entry.Reviews == 1 {
+			line = fmt.Sprintf("%s %s · 1 review", rank, reviewer)
+		}
+		if entry.Median > 0 {
+			line += fmt.Sprintf(" · median turnaround %.1fh", entry.Median.Hours())
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"reflect"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestPreviousMonth(t *testing.T) {
+	from, to := previousMonth(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+
+	if !from.Equal(time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected month %v - %v", from, to)
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	from, to := previousMonth(time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC))
+	event := func(event string, action string, actor string, receivedAt string) types.TableEventData {
+		return types.TableEventData{Event: event, Action: action, Actor: actor, ReceivedAt: receivedAt}
+	}
+
+	timelines := map[string][]types.TableEventData{
+		"api#1": {
+			// opened the month before
+			event("pull_request", "opened", "alice", "2024-02-29T10:00:00Z"),
+			event("pull_request_review", "submitted", "bob", "2024-03-01T10:00:00Z"),
+			event("pull_request_review", "submitted", "alice", "2024-03-01T11:00:00Z"),
+			event("pull_request_review", "submitted", "carol", "2024-03-01T12:00:00Z"),
+			// second review of bob
+			event("pull_request_review", "submitted", "bob", "2024-03-02T10:00:00Z"),
+		},
+		"api#2": {
+			event("pull_request", "opened", "carol", "2024-03-10T10:00:00.5Z"),
+			event("pull_request", "review_requested", "carol", "2024-03-11T10:00:00Z"),
+			event("pull_request_review", "submitted", "bob", "2024-03-11T12:00:00Z"),
+			// the month after
+			event("pull_request_review", "submitted", "dave", "2024-04-01T00:00:00Z"),
+		},
+		"web#3": {
+			// recorded before the opened event
+			event("pull_request_review", "submitted", "dave", "2024-03-05T10:00:00Z"),
+		},
+	}
+
+	expected := []leaderboardEntry{
+		{Login: "bob", Reviews: 2, Median: 13 * time.Hour},
+		{Login: "carol", Reviews: 1, Median: 26 * time.Hour},
+		{Login: "dave", Reviews: 1},
+	}
+	if entries := leaderboard(timelines, from, to); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("got %+v want %+v", entries, expected)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if m := median([]time.Duration{3 * time.Hour, time.Hour}); m != 2*time.Hour {
+		t.Errorf("median %v", m)
+	}
+	if m := median([]time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour}); m != 2*time.Hour {
+		t.Errorf("median %v", m)
+	}
+	if m := median(nil); m != 0 {
+		t.Errorf("median %v", m)
+	}
+}
+
+func TestLeaderboardMessage(t *testing.T) {
+	entries := []leaderboardEntry{
+		{Login: "bob", Reviews: 2, Median: 13 * time.Hour},
+		{Login: "dave", Reviews: 1},
+	}
+
+	message := leaderboardMessage(entries, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), map[string]interface{}{"bob": "UB"})
+	expected := ":trophy: *Top reviewers of March 2024*, thanks for the timely reviews!\n" +
+		":first_place_medal: <@UB> · 2 reviews · median turnaround 13.0h\n" +
+		":second_place_medal: dave · 1 review"
+	if message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+)
+
+// scheduled jobs, triggered by an EventBridge rule with input {"job": "<name>"}
+func registry() map[string]func() error {
+	return map[string]func() error{
+		"reminders":   Reminders,
+		"age":         Age,
+		"dashboard":   Dashboard,
+		"rollup":      Rollup,
+		"followups":   FollowUps,
+		"abandoned":   Abandoned,
+		"sla":         Sla,
+		"escalations": Escalations,
+		"mentions":    Mentions,
+		"security":    Security,
+		"purge":       Purge,
+		"leaderboard": Leaderboard,
+		"digests":     Digests,
+	}
+}
+
+// digests and reports triggered outside of the digest window are held for the
+// digests job
+func Run(name string) error {
+	job, ok := registry()[name]
+	if !ok {
+		return fmt.Errorf("unknown job %s", name)
+	}
+
+	held, err := holdDigest(name, time.Now())
+	if err != nil || held {
+		return err
+	}
+	return job()
+}
@@ -0,0 +1,15 @@
+package jobs
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	if err := Run("unknown"); err == nil {
+		t.Errorf("Expected error for unknown job")
+	}
+
+	for _, name := range []string{"reminders", "age", "dashboard", "rollup", "followups", "abandoned", "sla", "escalations", "mentions", "security", "purge", "leaderboard", "digests"} {
+		if _, ok := registry()[name]; !ok {
+			t.Errorf("Expected %s job to be registered", name)
+		}
+	}
+}
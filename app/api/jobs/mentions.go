@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slack-pr-lambda/audit"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/mentions"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/types"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// posts the mentions deferred outside of the mention hours in their thread
+// once the window opened, so they notify when people are around
+func Mentions() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	policy, err := mentions.Load()
+	if err != nil {
+		zapLog.Warn("error load mention policy",
+			zap.Error(err),
+		)
+	}
+
+	// posted now they would be deferred again
+	now := time.Now()
+	if !policy.InWindow(now) {
+		return nil
+	}
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListDeferredMentions(svc)
+	if err != nil {
+		return err
+	}
+
+	tasks := []func() error{}
+	for _, item := range dueMentions(items, now) {
+		tasks = append(tasks, func() error {
+			out := audit.Messenger{
+				Source:     "mentions",
+				Repository: item.Repository,
+				Number:     item.Number,
+				Log:        zapLog,
+			}
+			if err := out.SendMessageThread(item.ThreadTimeStamp, deferredMentionMessage(item.SlackUserIds)); err != nil {
+				zapLog.Error("error slack send deferred mentions",
+					zap.String("repository", item.Repository),
+					zap.Int("number", item.Number),
+					zap.Error(err),
+				)
+				return err
+			}
+			return db.DeleteDeferredMention(svc, item.PullRequest, item.ThreadTimeStamp)
+		})
+	}
+
+	return pool.Run(pool.Size(), tasks)
+}
+
+func dueMentions(items []types.TableDeferredMentionData, now time.Time) []types.TableDeferredMentionData {
+	result := []types.TableDeferredMentionData{}
+	for _, item := range items {
+		if item.ThreadTimeStamp == "" || len(item.SlackUserIds) == 0 || item.DueAt > now.Unix() {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func deferredMentionMessage(ids []string) string {
+	users := []string{}
+	for _, id := range ids {
+		users = append(users, fmt.Sprintf("<@%s>", id))
+	}
+	return fmt.Sprintf("%s you were mentioned in this thread outside of working hours.", strings.Join(users, " "))
+}
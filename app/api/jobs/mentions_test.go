@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestDueMentions(t *testing.T) {
+	now := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	items := []types.TableDeferredMentionData{
+		{PullRequest: "api#1", ThreadTimeStamp: "1.1", SlackUserIds: []string{"U1"}, DueAt: now.Unix()},
+		{PullRequest: "api#2", ThreadTimeStamp: "1.2", SlackUserIds: []string{"U1"}, DueAt: now.Add(time.Hour).Unix()},
+		{PullRequest: "api#3", ThreadTimeStamp: "1.3", DueAt: now.Unix()},
+		{PullRequest: "api#4", SlackUserIds: []string{"U1"}, DueAt: now.Unix()},
+	}
+
+	due := dueMentions(items, now)
+	if len(due) != 1 || due[0].PullRequest != "api#1" {
+		t.Errorf("Expected only api#1 to be due, got %v", due)
+	}
+}
+
+func TestDeferredMentionMessage(t *testing.T) {
+	message := deferredMentionMessage([]string{"U1", "U2"})
+	if expected := "<@U1> <@U2> you were mentioned in this thread outside of working hours."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
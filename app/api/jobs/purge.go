@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/logger"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// removes the pull request records soft-deleted more than SOFT_DELETE_DAYS
+// ago, until then a deleted record can be restored with the admin API
+func Purge() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	// records are deleted right away
+	if db.SoftDeleteDays() == 0 {
+		return nil
+	}
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+
+	purged, err := db.PurgeDeleted(svc, time.Now())
+	zapLog.Info("purged deleted pull requests",
+		zap.Int("purged", purged),
+	)
+	return err
+}
@@ -0,0 +1,310 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slack-pr-lambda/api/mail"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/policy"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/reviewers"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// re-ping requested reviewers of pull requests waiting longer than the repository
+// reminderAfterHours (REMINDER_AFTER_HOURS by default), then again every
+// REMINDER_INTERVAL_HOURS, skipping reviewers that are out of office or snoozed
+// the reminder. Pull requests holding their review pings, with a WIP title or an
+// incomplete description, are not reminded. Weekends and holidays of the
+// calendar don't count and nobody is pinged on them. Reviewers notified by email
+// get one digest of all their pending pull requests
+func Reminders() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	remindAfter, err := strconv.Atoi(env.GetEnv("REMINDER_AFTER_HOURS", "24"))
+	if err != nil {
+		return err
+	}
+	interval := defaultReminderInterval()
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		conf, _ = config.ParseConfig("")
+	}
+
+	cal := loadCalendar(zapLog)
+	now := time.Now()
+	if !cal.Working(now) {
+		return nil
+	}
+
+	slackUsersMap := mapstruct.StructToMap(*constants.SlackUsers())
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return err
+	}
+
+	ooo, err := db.ListOutOfOffice(svc)
+	if err != nil {
+		zapLog.Warn("error list out of office",
+			zap.Error(err),
+		)
+		ooo = map[string]types.TableOutOfOfficeData{}
+	}
+
+	emails := &reminderEmails{
+		preferences:   mail.Preferences(zapLog),
+		slackUsersMap: slackUsersMap,
+		addresses:     map[string]string{},
+		entries:       map[string][]mail.Entry{},
+	}
+
+	tasks := []func() error{}
+	for _, item := range items {
+		// records created before reminders existed have no repository
+		if item.Repository == "" {
+			continue
+		}
+
+		repo := conf.Repo(item.Repository)
+		after := time.Duration(reminderAfterHours(repo, item, remindAfter)) * time.Hour
+		if !dueReminder(item, after, reminderInterval(repo, interval), cal, now) {
+			continue
+		}
+
+		tasks = append(tasks, func() error {
+			return remind(svc, repo, item, ooo, emails, now, zapLog)
+		})
+	}
+
+	err = pool.Run(pool.Size(), tasks)
+	emails.send(zapLog)
+	return err
+}
+
+// pending pull requests of the reviewers notified by email, filled concurrently
+// by remind
+type reminderEmails struct {
+	preferences   map[string]string
+	slackUsersMap map[string]interface{}
+
+	mu        sync.Mutex
+	addresses map[string]string
+	entries   map[string][]mail.Entry
+}
+
+// reviewers of pending to email, the pull request is added to their digest
+func (e *reminderEmails) add(pending []string, item types.TablePullRequestData, zapLog *zap.Logger) map[string]string {
+	emailed := mail.Recipients(pending, e.slackUsersMap, e.preferences, zapLog)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for login, address := range emailed {
+		e.addresses[login] = address
+		e.entries[login] = append(e.entries[login], mail.Entry{
+			Repository: item.Repository,
+			Number:     item.PullRequestId,
+			CreatedAt:  item.CreatedAt,
+		})
+	}
+	return emailed
+}
+
+func (e *reminderEmails) send(zapLog *zap.Logger) {
+	for login, entries := range e.entries {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].CreatedAt < entries[j].CreatedAt
+		})
+		subject := fmt.Sprintf("Pull requests waiting for your review (%d)", len(entries))
+		mail.Send(e.addresses[login], subject, mail.Digest("*"+subject+"*", entries), zapLog)
+	}
+}
+
+// the first response time of the matching SLA rule, otherwise the repository
+// reminderAfterHours or remindAfter
+func reminderAfterHours(repo config.RepoConfig, item types.TablePullRequestData, remindAfter int) int {
+	if rule, ok := policy.Match(repo.SlaRules, item.Labels); ok {
+		return rule.FirstResponseHours
+	}
+	if repo.ReminderAfterHours > 0 {
+		return repo.ReminderAfterHours
+	}
+	return remindAfter
+}
+
+// REMINDER_INTERVAL_HOURS
+func defaultReminderInterval() time.Duration {
+	hours, err := strconv.Atoi(env.GetEnv("REMINDER_INTERVAL_HOURS", "24"))
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// the repository reminderIntervalHours, otherwise interval
+func reminderInterval(repo config.RepoConfig, interval time.Duration) time.Duration {
+	if repo.ReminderIntervalHours > 0 {
+		return time.Duration(repo.ReminderIntervalHours) * time.Hour
+	}
+	return interval
+}
+
+// whether the pull request waited after and its last reminder is older than the
+// interval, both in working time. WIP pull requests hold their review pings
+func dueReminder(item types.TablePullRequestData, after time.Duration, interval time.Duration, cal calendar.Calendar, now time.Time) bool {
+	if item.WorkInProgress {
+		return false
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+	if err != nil || cal.Elapsed(createdAt, now) < after {
+		return false
+	}
+
+	remindedAt, err := time.Parse(time.RFC3339, item.RemindedAt)
+	return err != nil || cal.Elapsed(remindedAt, now) >= interval
+}
+
+// the body is only read for repositories requiring a description
+func heldByDescription(repo config.RepoConfig, item types.TablePullRequestData) (bool, error) {
+	if !repo.RequireDescription && len(repo.RequiredSections) == 0 {
+		return false, nil
+	}
+
+	input, err := github.GetOpenPullRequest(item.Repository, item.PullRequestId)
+	if err != nil {
+		return false, err
+	}
+	incomplete, _ := repo.IncompleteDescription(input.PullRequest.GetBody())
+	return incomplete, nil
+}
+
+func remind(svc *awsdynamodb.DynamoDB, repo config.RepoConfig, item types.TablePullRequestData, ooo map[string]types.TableOutOfOfficeData, emails *reminderEmails, now time.Time, zapLog *zap.Logger) error {
+	held, err := heldByDescription(repo, item)
+	if err != nil || held {
+		return err
+	}
+
+	requested, err := github.GetRequestedReviewers(item.Repository, item.PullRequestId)
+	if err != nil {
+		return err
+	}
+
+	snoozes, err := db.ListSnoozes(svc, item.ID)
+	if err != nil {
+		return err
+	}
+
+	pending := pendingReviewers(requested, ooo, snoozes, now)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	buttons, err := reminderButtons(item)
+	if err != nil {
+		return err
+	}
+
+	emailed := emails.add(pending, item, zapLog)
+	message := reminderMessage(pending, emails.slackUsersMap, emailed)
+	out := audit.Messenger{
+		Source:     "reminders",
+		Repository: item.Repository,
+		Number:     item.PullRequestId,
+		Log:        zapLog,
+	}
+	if err := out.SendMessageThreadWithButtons(item.SlackTimeStamp, message, buttons); err != nil {
+		zapLog.Error("error slack send reminder",
+			zap.String("repository", item.Repository),
+			zap.Int("number", item.PullRequestId),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	id, err := strconv.Atoi(item.ID)
+	if err != nil {
+		return err
+	}
+	return db.UpdateReminded(svc, id, item.PullRequestId, now.Format(time.RFC3339))
+}
+
+func pendingReviewers(requested []string, ooo map[string]types.TableOutOfOfficeData, snoozes map[string]int64, now time.Time) []string {
+	available, _ := reviewers.FilterOutOfOffice(requested, ooo, now)
+	return reviewers.FilterSnoozed(available, snoozes, now)
+}
+
+func reminderMessage(pending []string, slackUsersMap map[string]interface{}, emailed map[string]string) string {
+	emoji := constants.Emoji()
+
+	mentions := []string{}
+	for _, login := range pending {
+		mentions = append(mentions, mail.Mention(login, slackUsersMap, emailed))
+	}
+
+	return fmt.Sprintf("%s Reminder: this pull request is still waiting for a review from %s.", emoji.Reminder, strings.Join(mentions, " "))
+}
+
+// snooze options plus a merge readiness status button
+func reminderButtons(item types.TablePullRequestData) ([]slack.SlackButton, error) {
+	value, err := json.Marshal(types.PullRequestActionValue{
+		ID:            item.ID,
+		PullRequestId: item.PullRequestId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buttons := []slack.SlackButton{}
+	for _, option := range reviewers.SnoozeOptions() {
+		buttons = append(buttons, slack.SlackButton{
+			ActionId: option.ActionId,
+			Text:     option.Text,
+			Value:    string(value),
+		})
+	}
+	buttons = append(buttons, slack.SlackButton{
+		ActionId: "pr_status",
+		Text:     "Status",
+		Value:    string(value),
+	})
+
+	return buttons, nil
+}
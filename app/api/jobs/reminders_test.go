@@ -0,0 +1,137 @@
+package jobs
+
+import (
+	"encoding/json"
+	"reflect"
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestPendingReviewers(t *testing.T) {
+	now, _ := time.Parse("2006-01-02", "2024-03-11")
+	ooo := map[string]types.TableOutOfOfficeData{
+		"bob": {GithubLogin: "bob", StartDate: "2024-03-10", EndDate: "2024-03-12"},
+	}
+	snoozes := map[string]int64{
+		"carol": now.Add(time.Hour).Unix(),
+		"dave":  now.Add(-time.Hour).Unix(),
+	}
+
+	result := pendingReviewers([]string{"alice", "bob", "carol", "dave"}, ooo, snoozes, now)
+
+	if !reflect.DeepEqual(result, []string{"alice", "dave"}) {
+		t.Errorf("Unexpected pending reviewers %v", result)
+	}
+}
+
+func TestReminderMessage(t *testing.T) {
+	slackUsersMap := map[string]interface{}{
+		"alice": "UA",
+		"dave":  "UD",
+	}
+
+	result := reminderMessage([]string{"alice", "dave"}, slackUsersMap, map[string]string{})
+	expected := ":bell: Reminder: this pull request is still waiting for a review from <@UA> <@UD>."
+
+	if result != expected {
+		t.Errorf("got %q want %q", result, expected)
+	}
+
+	result = reminderMessage([]string{"alice", "dave", "erin"}, slackUsersMap, map[string]string{"dave": "dave@acme.com"})
+	expected = ":bell: Reminder: this pull request is still waiting for a review from <@UA> @dave @erin."
+
+	if result != expected {
+		t.Errorf("got %q want %q", result, expected)
+	}
+}
+
+func TestReminderButtons(t *testing.T) {
+	buttons, err := reminderButtons(types.TablePullRequestData{ID: "123", PullRequestId: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buttons) != 3 {
+		t.Fatalf("Expected 3 buttons, got %d", len(buttons))
+	}
+	if buttons[2].ActionId != "pr_status" {
+		t.Errorf("Expected status button last, got %s", buttons[2].ActionId)
+	}
+
+	var value types.PullRequestActionValue
+	if err := json.Unmarshal([]byte(buttons[0].Value), &value); err != nil {
+		t.Fatal(err)
+	}
+	if value.ID != "123" || value.PullRequestId != 7 {
+		t.Errorf("Unexpected button value %+v", value)
+	}
+}
+
+func TestReminderAfterHours(t *testing.T) {
+	repo := config.RepoConfig{
+		ReminderAfterHours: 12,
+		SlaRules:           []config.SlaRule{{Label: "hotfix", FirstResponseHours: 2}},
+	}
+
+	if hours := reminderAfterHours(repo, types.TablePullRequestData{Labels: []string{"hotfix"}}, 24); hours != 2 {
+		t.Errorf("Expected the SLA rule, got %d", hours)
+	}
+	if hours := reminderAfterHours(repo, types.TablePullRequestData{}, 24); hours != 12 {
+		t.Errorf("Expected the repository setting, got %d", hours)
+	}
+	if hours := reminderAfterHours(config.RepoConfig{}, types.TablePullRequestData{}, 24); hours != 24 {
+		t.Errorf("Expected the default, got %d", hours)
+	}
+}
+
+func TestReminderInterval(t *testing.T) {
+	t.Setenv("REMINDER_INTERVAL_HOURS", "8")
+	if interval := defaultReminderInterval(); interval != 8*time.Hour {
+		t.Errorf("Expected 8h, got %v", interval)
+	}
+
+	t.Setenv("REMINDER_INTERVAL_HOURS", "0")
+	if interval := defaultReminderInterval(); interval != 24*time.Hour {
+		t.Errorf("Expected the 24h default, got %v", interval)
+	}
+
+	if interval := reminderInterval(config.RepoConfig{ReminderIntervalHours: 4}, 24*time.Hour); interval != 4*time.Hour {
+		t.Errorf("Expected the repository setting, got %v", interval)
+	}
+	if interval := reminderInterval(config.RepoConfig{}, 24*time.Hour); interval != 24*time.Hour {
+		t.Errorf("Expected the default, got %v", interval)
+	}
+}
+
+func TestDueReminder(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2024-03-08T12:00:00Z")
+	tests := []struct {
+		name string
+		item types.TablePullRequestData
+		due  bool
+	}{
+		{"too recent", types.TablePullRequestData{CreatedAt: "2024-03-08T00:00:00Z"}, false},
+		{"never reminded", types.TablePullRequestData{CreatedAt: "2024-03-07T00:00:00Z"}, true},
+		{"reminded within the interval", types.TablePullRequestData{CreatedAt: "2024-03-06T00:00:00Z", RemindedAt: "2024-03-08T06:00:00Z"}, false},
+		{"reminded before the interval", types.TablePullRequestData{CreatedAt: "2024-03-06T00:00:00Z", RemindedAt: "2024-03-07T06:00:00Z"}, true},
+		{"work in progress", types.TablePullRequestData{CreatedAt: "2024-03-07T00:00:00Z", WorkInProgress: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if due := dueReminder(tt.item, 24*time.Hour, 24*time.Hour, calendar.Calendar{Weekends: true}, now); due != tt.due {
+				t.Errorf("got %v want %v", due, tt.due)
+			}
+		})
+	}
+}
+
+func TestHeldByDescription(t *testing.T) {
+	held, err := heldByDescription(config.RepoConfig{}, types.TablePullRequestData{})
+	if err != nil || held {
+		t.Errorf("Expected no description required, got %v %v", held, err)
+	}
+}
@@ -0,0 +1,103 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/types"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// one thread reply for the comments held back on very active pull requests
+// since the last rollup, e.g. "12 new comments in the last hour"
+func Rollup() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tasks := []func() error{}
+	for _, item := range pendingRollups(items) {
+		tasks = append(tasks, func() error {
+			out := audit.Messenger{
+				Source:     "rollup",
+				Repository: item.Repository,
+				Number:     item.PullRequestId,
+				Log:        zapLog,
+			}
+			if err := out.SendMessageThread(item.SlackTimeStamp, rollupMessage(item, now)); err != nil {
+				zapLog.Error("error slack send rollup",
+					zap.String("repository", item.Repository),
+					zap.Int("number", item.PullRequestId),
+					zap.Error(err),
+				)
+				return err
+			}
+
+			id, err := strconv.Atoi(item.ID)
+			if err != nil {
+				return err
+			}
+			return db.ClearPendingComments(svc, id, item.PullRequestId, item.PendingComments)
+		})
+	}
+
+	return pool.Run(pool.Size(), tasks)
+}
+
+// pull requests with a thread and comments waiting for the rollup
+func pendingRollups(items []types.TablePullRequestData) []types.TablePullRequestData {
+	result := []types.TablePullRequestData{}
+	for _, item := range items {
+		if item.PendingComments <= 0 || item.SlackTimeStamp == "" {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// the period starts at the oldest pending comment, rounded up to hours
+func rollupMessage(item types.TablePullRequestData, now time.Time) string {
+	emoji := constants.Emoji()
+
+	comments := "comment"
+	if item.PendingComments != 1 {
+		comments = "comments"
+	}
+
+	period := "hour"
+	if since, err := time.Parse(time.RFC3339, item.PendingSince); err == nil {
+		if hours := int(math.Ceil(now.Sub(since).Hours())); hours > 1 {
+			period = fmt.Sprintf("%d hours", hours)
+		}
+	}
+
+	return fmt.Sprintf("%s %d new %s in the last %s.", emoji.Comment, item.PendingComments, comments, period)
+}
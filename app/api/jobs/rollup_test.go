@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestPendingRollups(t *testing.T) {
+	items := []types.TablePullRequestData{
+		{ID: "1", SlackTimeStamp: "1.1", PendingComments: 3},
+		{ID: "2", SlackTimeStamp: "1.2"},
+		{ID: "3", PendingComments: 2},
+	}
+
+	result := pendingRollups(items)
+
+	if len(result) != 1 || result[0].ID != "1" {
+		t.Errorf("Unexpected pending rollups %v", result)
+	}
+}
+
+func TestRollupMessage(t *testing.T) {
+	emoji := constants.Emoji()
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]types.TablePullRequestData{
+		emoji.Comment + " 12 new comments in the last hour.":   {PendingComments: 12, PendingSince: "2024-03-10T11:10:00Z"},
+		emoji.Comment + " 1 new comment in the last hour.":     {PendingComments: 1},
+		emoji.Comment + " 5 new comments in the last 3 hours.": {PendingComments: 5, PendingSince: "2024-03-10T09:30:00Z"},
+	}
+
+	for expected, item := range tests {
+		if message := rollupMessage(item, now); message != expected {
+			t.Errorf("Expected %q, got %q", expected, message)
+		}
+	}
+}
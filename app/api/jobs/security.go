@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"slack-pr-lambda/api/messages"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reminds the security channel of the open alerts past the SLA of their
+// severity, in the thread of each alert and again every
+// SECURITY_REMINDER_HOURS (default 24) until it is fixed or dismissed
+func Security() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	channel := env.GetEnv("SECURITY_CHANNEL", "")
+	if channel == "" {
+		return nil
+	}
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListSecurityAlerts(svc)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tasks := []func() error{}
+	for _, item := range items {
+		open, ok := dueSecurityReminder(item, securityReminderInterval(), now)
+		if !ok {
+			continue
+		}
+
+		tasks = append(tasks, func() error {
+			if _, err := slack.SlackPostChannelMessage(channel, item.SlackTimeStamp, messages.SecurityReminderMessage(&item, open)); err != nil {
+				zapLog.Error("error slack send security reminder",
+					zap.String("alert", item.Alert),
+					zap.Error(err),
+				)
+				return err
+			}
+			return db.UpdateSecurityReminder(svc, item.Alert, now.Format(time.RFC3339))
+		})
+	}
+
+	return pool.Run(pool.Size(), tasks)
+}
+
+func securityReminderInterval() time.Duration {
+	hours, err := strconv.Atoi(env.GetEnv("SECURITY_REMINDER_HOURS", "24"))
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// how long the alert has been open once it is past its SLA and was not
+// reminded within the interval
+func dueSecurityReminder(item types.TableSecurityAlertData, interval time.Duration, now time.Time) (time.Duration, bool) {
+	sla := messages.SecuritySla(item.Repository, item.Severity)
+	if item.SlackTimeStamp == "" || sla == 0 {
+		return 0, false
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+	if err != nil {
+		return 0, false
+	}
+	open := now.Sub(createdAt)
+	if open < sla {
+		return 0, false
+	}
+
+	if remindedAt, err := time.Parse(time.RFC3339, item.RemindedAt); err == nil && now.Sub(remindedAt) < interval {
+		return 0, false
+	}
+	return open, true
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestDueSecurityReminder(t *testing.T) {
+	now := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		item     types.TableSecurityAlertData
+		expected time.Duration
+	}{
+		{types.TableSecurityAlertData{Alert: "within the SLA", Severity: "critical", SlackTimeStamp: "1.1", CreatedAt: "2024-03-08T00:00:00Z"}, 0},
+		{types.TableSecurityAlertData{Alert: "past the SLA", Severity: "critical", SlackTimeStamp: "1.2", CreatedAt: "2024-03-07T00:00:00Z"}, 36 * time.Hour},
+		{types.TableSecurityAlertData{Alert: "reminded recently", Severity: "critical", SlackTimeStamp: "1.3", CreatedAt: "2024-03-06T00:00:00Z", RemindedAt: "2024-03-08T00:00:00Z"}, 0},
+		{types.TableSecurityAlertData{Alert: "reminded yesterday", Severity: "critical", SlackTimeStamp: "1.4", CreatedAt: "2024-03-06T12:00:00Z", RemindedAt: "2024-03-07T12:00:00Z"}, 48 * time.Hour},
+		{types.TableSecurityAlertData{Alert: "no severity", SlackTimeStamp: "1.5", CreatedAt: "2023-03-06T12:00:00Z"}, 0},
+		{types.TableSecurityAlertData{Alert: "not posted", Severity: "critical", CreatedAt: "2024-03-06T12:00:00Z"}, 0},
+	}
+
+	for _, tt := range tests {
+		open, ok := dueSecurityReminder(tt.item, 24*time.Hour, now)
+		if ok != (tt.expected > 0) || open != tt.expected {
+			t.Errorf("%s: expected %v, got %v %v", tt.item.Alert, tt.expected, open, ok)
+		}
+	}
+}
+
+func TestSecurityReminderInterval(t *testing.T) {
+	t.Setenv("SECURITY_REMINDER_HOURS", "12")
+	if interval := securityReminderInterval(); interval != 12*time.Hour {
+		t.Errorf("Expected 12h, got %v", interval)
+	}
+
+	t.Setenv("SECURITY_REMINDER_HOURS", "never")
+	if interval := securityReminderInterval(); interval != 24*time.Hour {
+		t.Errorf("Expected the 24h default, got %v", interval)
+	}
+}
@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slack-pr-lambda/audit"
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/logger"
+	"slack-pr-lambda/metrics"
+	"slack-pr-lambda/policy"
+	"slack-pr-lambda/pool"
+	"slack-pr-lambda/types"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var slaBreaches = metrics.NewCounter("sla_breaches_total", "Pull requests without a first review within the SLA by repository and rule.", "repository", "rule")
+
+// evaluate the first response SLA rules of the open pull requests, a breach is
+// counted and announced in the thread once per pull request
+func Sla() error {
+	zapLog, err := logger.Base()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		zapLog.Warn("error load repository config",
+			zap.Error(err),
+		)
+		conf, _ = config.ParseConfig("")
+	}
+
+	svc, err := db.Connection()
+	if err != nil {
+		return err
+	}
+	items, err := db.ListPullRequests(svc)
+	if err != nil {
+		return err
+	}
+
+	cal := loadCalendar(zapLog)
+	now := time.Now()
+	tasks := []func() error{}
+	for _, item := range items {
+		status, ok := slaBreach(item, conf, cal, now)
+		if !ok {
+			continue
+		}
+
+		tasks = append(tasks, func() error {
+			out := audit.Messenger{
+				Source:     "sla",
+				Repository: item.Repository,
+				Number:     item.PullRequestId,
+				Log:        zapLog,
+			}
+			if err := out.SendMessageThread(item.SlackTimeStamp, slaMessage(status)); err != nil {
+				zapLog.Error("error slack send sla breach",
+					zap.String("repository", item.Repository),
+					zap.Int("number", item.PullRequestId),
+					zap.Error(err),
+				)
+				return err
+			}
+
+			id, err := strconv.Atoi(item.ID)
+			if err != nil {
+				return err
+			}
+			if err := db.UpdateSlaBreached(svc, id, item.PullRequestId, now.Format(time.RFC3339)); err != nil {
+				return err
+			}
+
+			slaBreaches.Inc(item.Repository, policy.Name(status.Rule))
+			return nil
+		})
+	}
+
+	return pool.Run(pool.Size(), tasks)
+}
+
+// newly breached SLA of a pull request still waiting for its first review, a WIP
+// pull request is not waiting for one
+func slaBreach(item types.TablePullRequestData, conf *config.Config, cal calendar.Calendar, now time.Time) (policy.Status, bool) {
+	if item.WorkInProgress || item.Repository == "" || item.SlackTimeStamp == "" || item.SlaBreachedAt != "" || item.FirstReviewAt != "" {
+		return policy.Status{}, false
+	}
+
+	rule, ok := policy.Match(conf.Repo(item.Repository).SlaRules, item.Labels)
+	if !ok {
+		return policy.Status{}, false
+	}
+
+	openedAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+	if err != nil {
+		return policy.Status{}, false
+	}
+
+	status := policy.Evaluate(rule, cal, openedAt, item.FirstReviewAt, now)
+	return status, status.Breached
+}
+
+func slaMessage(status policy.Status) string {
+	emoji := constants.Emoji()
+
+	rule := "default"
+	if status.Rule.Label != "" {
+		rule = fmt.Sprintf("`%s`", status.Rule.Label)
+	}
+	return fmt.Sprintf("%s First response SLA breached: no review within %dh (%s rule).", emoji.Reminder, status.Rule.FirstResponseHours, rule)
+}
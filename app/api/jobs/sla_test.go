@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/policy"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestSlaBreach(t *testing.T) {
+	now := time.Date(2024, 3, 8, 13, 0, 0, 0, time.UTC)
+	conf, err := config.ParseConfig(`{"default": {"slaRules": [{"label": "hotfix", "firstResponseHours": 2}, {"firstResponseHours": 24}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		item     types.TablePullRequestData
+		breached bool
+	}{
+		{types.TablePullRequestData{Repository: "api", SlackTimeStamp: "1.1", CreatedAt: "2024-03-08T10:00:00Z", Labels: []string{"hotfix"}}, true},
+		{types.TablePullRequestData{Repository: "api", SlackTimeStamp: "1.2", CreatedAt: "2024-03-08T10:00:00Z"}, false},
+		{types.TablePullRequestData{Repository: "api", SlackTimeStamp: "1.3", CreatedAt: "2024-03-08T10:00:00Z", Labels: []string{"hotfix"}, FirstReviewAt: "2024-03-08T11:00:00Z"}, false},
+		{types.TablePullRequestData{Repository: "api", SlackTimeStamp: "1.4", CreatedAt: "2024-03-08T10:00:00Z", Labels: []string{"hotfix"}, SlaBreachedAt: "2024-03-08T12:00:00Z"}, false},
+		{types.TablePullRequestData{Repository: "api", SlackTimeStamp: "1.5", CreatedAt: "2024-03-06T10:00:00Z"}, true},
+		{types.TablePullRequestData{Repository: "api", SlackTimeStamp: "1.6", CreatedAt: "2024-03-06T10:00:00Z", WorkInProgress: true}, false},
+	}
+
+	for _, tt := range tests {
+		if _, breached := slaBreach(tt.item, conf, calendar.Calendar{}, now); breached != tt.breached {
+			t.Errorf("%s: expected breached %v", tt.item.SlackTimeStamp, tt.breached)
+		}
+	}
+}
+
+func TestSlaMessage(t *testing.T) {
+	emoji := constants.Emoji()
+
+	message := slaMessage(policy.Status{Rule: config.SlaRule{Label: "hotfix", FirstResponseHours: 2}})
+	if expected := emoji.Reminder + " First response SLA breached: no review within 2h (`hotfix` rule)."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+
+	message = slaMessage(policy.Status{Rule: config.SlaRule{FirstResponseHours: 24}})
+	if expected := emoji.Reminder + " First response SLA breached: no review within 24h (default rule)."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
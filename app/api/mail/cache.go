@@ -0,0 +1,143 @@
+package mail
+
+import (
+	"container/list"
+	"slack-pr-lambda/env"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// USER_CACHE_TTL_SECONDS, how long a warm lambda keeps the user lookups
+func cacheTTL() time.Duration {
+	seconds, err := strconv.Atoi(env.GetEnv("USER_CACHE_TTL_SECONDS", "300"))
+	if err != nil || seconds < 0 {
+		seconds = 300
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// USER_CACHE_SIZE, logins kept before the least recently used is evicted
+func cacheSize() int {
+	size, err := strconv.Atoi(env.GetEnv("USER_CACHE_SIZE", "500"))
+	if err != nil || size <= 0 {
+		size = 500
+	}
+	return size
+}
+
+// least recently used addresses by github login, an entry older than the TTL
+// is looked up again. An empty address is cached too, most reviewers have no
+// public email
+type lru struct {
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	login    string
+	address  string
+	storedAt time.Time
+}
+
+func newLRU() *lru {
+	return &lru{order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (c *lru) get(login string, now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[login]
+	if !ok {
+		return "", false
+	}
+	entry := element.Value.(*lruEntry)
+	if now.Sub(entry.storedAt) >= cacheTTL() {
+		c.order.Remove(element)
+		delete(c.entries, login)
+		return "", false
+	}
+	c.order.MoveToFront(element)
+	return entry.address, true
+}
+
+func (c *lru) put(login string, address string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[login]; ok {
+		element.Value = &lruEntry{login: login, address: address, storedAt: now}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[login] = c.order.PushFront(&lruEntry{login: login, address: address, storedAt: now})
+	for c.order.Len() > cacheSize() {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).login)
+	}
+}
+
+func (c *lru) remove(login string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[login]; ok {
+		c.order.Remove(element)
+		delete(c.entries, login)
+	}
+}
+
+// public emails of the GitHub profiles
+var profileEmails = newLRU()
+
+// the email preferences table, read once per TTL instead of on every review
+// request
+var preferencesCache = struct {
+	sync.Mutex
+	preferences map[string]string
+	loadedAt    time.Time
+}{}
+
+func cachedPreferences(now time.Time) (map[string]string, error) {
+	preferencesCache.Lock()
+	defer preferencesCache.Unlock()
+
+	if preferencesCache.preferences != nil && now.Sub(preferencesCache.loadedAt) < cacheTTL() {
+		return preferencesCache.preferences, nil
+	}
+
+	preferences, err := listPreferences()
+	if err != nil {
+		return nil, err
+	}
+	preferencesCache.preferences = preferences
+	preferencesCache.loadedAt = now
+	return preferences, nil
+}
+
+func cachedUserEmail(login string, now time.Time) (string, error) {
+	if address, ok := profileEmails.get(login, now); ok {
+		return address, nil
+	}
+
+	address, err := userEmail(login)
+	if err != nil {
+		return "", err
+	}
+	profileEmails.put(login, address, now)
+	return address, nil
+}
+
+// drops the cached lookups of a login whose email preference changed or was
+// deleted, the preferences are read again on the next lookup
+func Forget(login string) {
+	profileEmails.remove(login)
+
+	preferencesCache.Lock()
+	defer preferencesCache.Unlock()
+	preferencesCache.preferences = nil
+}
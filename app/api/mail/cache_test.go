@@ -0,0 +1,78 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	t.Setenv("USER_CACHE_SIZE", "2")
+	t.Setenv("USER_CACHE_TTL_SECONDS", "60")
+	now := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+
+	cache := newLRU()
+	cache.put("alice", "alice@acme.com", now)
+	cache.put("bob", "", now)
+	if _, ok := cache.get("alice", now); !ok {
+		t.Errorf("Expected alice to be cached")
+	}
+
+	// bob is the least recently used
+	cache.put("carol", "carol@acme.com", now)
+	if _, ok := cache.get("bob", now); ok {
+		t.Errorf("Expected bob to be evicted")
+	}
+	if address, ok := cache.get("carol", now); !ok || address != "carol@acme.com" {
+		t.Errorf("Expected carol to be cached, got %q %v", address, ok)
+	}
+
+	if _, ok := cache.get("alice", now.Add(time.Minute)); ok {
+		t.Errorf("Expected alice to expire")
+	}
+
+	cache.remove("carol")
+	if _, ok := cache.get("carol", now); ok {
+		t.Errorf("Expected carol to be removed")
+	}
+}
+
+func TestCachedLookups(t *testing.T) {
+	resetCache(t)
+	now := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+
+	scans, lookups := 0, 0
+	originalPreferences := listPreferences
+	originalUserEmail := userEmail
+	listPreferences = func() (map[string]string, error) {
+		scans++
+		return map[string]string{"alice": "alice@acme.com"}, nil
+	}
+	userEmail = func(login string) (string, error) {
+		lookups++
+		return "", nil
+	}
+	t.Cleanup(func() {
+		listPreferences = originalPreferences
+		userEmail = originalUserEmail
+	})
+
+	cachedPreferences(now)
+	cachedPreferences(now.Add(time.Minute))
+	cachedUserEmail("dave", now)
+	cachedUserEmail("dave", now.Add(time.Minute))
+	if scans != 1 || lookups != 1 {
+		t.Errorf("Expected one scan and one lookup, got %v and %v", scans, lookups)
+	}
+
+	cachedPreferences(now.Add(10 * time.Minute))
+	if scans != 2 {
+		t.Errorf("Expected the preferences to be read again after the TTL, got %v scans", scans)
+	}
+
+	Forget("dave")
+	cachedPreferences(now.Add(10 * time.Minute))
+	cachedUserEmail("dave", now.Add(10*time.Minute))
+	if scans != 3 || lookups != 2 {
+		t.Errorf("Expected forgotten lookups to be read again, got %v and %v", scans, lookups)
+	}
+}
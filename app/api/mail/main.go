@@ -0,0 +1,123 @@
+package mail
+
+import (
+	"fmt"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/notifier"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// replaced in tests
+var listPreferences = func() (map[string]string, error) {
+	return db.ListEmailPreferences(db.DynamoDbConnection())
+}
+
+var userEmail = github.GetUserEmail
+
+var send = func(address string, subject string, text string) error {
+	return notifier.Email{To: address, Subject: subject}.Notify(text)
+}
+
+// pull request listed in an email
+type Entry struct {
+	Repository string
+	Number     int
+	CreatedAt  string
+}
+
+// addresses of the users who opted in with /pr-email keyed by github login,
+// empty when emails are off or the lookup failed. Cached, see cachedPreferences
+func Preferences(zapLog *zap.Logger) map[string]string {
+	if !notifier.EmailEnabled() {
+		return map[string]string{}
+	}
+
+	preferences, err := cachedPreferences(time.Now())
+	if err != nil {
+		zapLog.Warn("error list email preferences",
+			zap.Error(err),
+		)
+		return map[string]string{}
+	}
+	return preferences
+}
+
+// reviewers notified by email with their address: the ones who opted in and,
+// when they have no Slack mapping, the public email of their GitHub profile
+func Recipients(logins []string, slackUsersMap map[string]interface{}, preferences map[string]string, zapLog *zap.Logger) map[string]string {
+	result := map[string]string{}
+	if !notifier.EmailEnabled() {
+		return result
+	}
+
+	for _, login := range logins {
+		if address, ok := preferences[login]; ok {
+			result[login] = address
+			continue
+		}
+		if _, ok := slackUsersMap[login]; ok {
+			continue
+		}
+
+		address, err := cachedUserEmail(login, time.Now())
+		if err != nil {
+			zapLog.Warn("error get github user email",
+				zap.String("login", login),
+				zap.Error(err),
+			)
+			continue
+		}
+		if address == "" {
+			zapLog.Warn("reviewer has no slack mapping nor public email",
+				zap.String("login", login),
+			)
+			continue
+		}
+		result[login] = address
+	}
+	return result
+}
+
+// Slack mention of the user, users notified by email or without Slack mapping
+// are written as @login
+func Mention(login string, slackUsersMap map[string]interface{}, emailed map[string]string) string {
+	user, ok := slackUsersMap[login]
+	if _, email := emailed[login]; email || !ok {
+		return "@" + login
+	}
+	return fmt.Sprintf("<@%s>", user)
+}
+
+// heading and one line per pull request, in Slack mrkdwn like every other
+// notification
+func Digest(heading string, entries []Entry) string {
+	lines := []string{heading, ""}
+	for _, entry := range entries {
+		line := fmt.Sprintf("• <%s|%s#%d>", github.PullRequestUrl(entry.Repository, entry.Number), entry.Repository, entry.Number)
+		if created, err := time.Parse(time.RFC3339, entry.CreatedAt); err == nil {
+			line += fmt.Sprintf(" opened %s", created.UTC().Format(time.DateOnly))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", "You get this email because you opted into email with /pr-email or have no Slack account linked.")
+	return strings.Join(lines, "\n")
+}
+
+// a failed email is only logged, the Slack message is already sent
+func Send(address string, subject string, text string, zapLog *zap.Logger) {
+	if err := send(address, subject, text); err != nil {
+		zapLog.Error("error send email",
+			zap.String("subject", subject),
+			zap.Error(err),
+		)
+	}
+}
+
+// emails are sent when EMAIL_FROM is set
+func Enabled() bool {
+	return notifier.EmailEnabled()
+}
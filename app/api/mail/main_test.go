@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func stubLookups(t *testing.T, preferences map[string]string, emails map[string]string) {
+	originalPreferences := listPreferences
+	originalUserEmail := userEmail
+	listPreferences = func() (map[string]string, error) {
+		return preferences, nil
+	}
+	userEmail = func(login string) (string, error) {
+		if login == "broken" {
+			return "", errors.New("rate limited")
+		}
+		return emails[login], nil
+	}
+	t.Cleanup(func() {
+		listPreferences = originalPreferences
+		userEmail = originalUserEmail
+	})
+	resetCache(t)
+}
+
+// lookups of the test are not served from the cache of the previous one
+func resetCache(t *testing.T) {
+	profileEmails = newLRU()
+	Forget("")
+	t.Cleanup(func() {
+		profileEmails = newLRU()
+		Forget("")
+	})
+}
+
+func TestRecipients(t *testing.T) {
+	stubLookups(t, map[string]string{"alice": "alice@acme.com"}, map[string]string{"carol": "carol@acme.com"})
+	slackUsersMap := map[string]interface{}{"alice": "U1", "bob": "U2"}
+	logins := []string{"alice", "bob", "carol", "dave", "broken"}
+
+	t.Setenv("EMAIL_FROM", "")
+	if result := Recipients(logins, slackUsersMap, Preferences(zap.NewNop()), zap.NewNop()); len(result) != 0 {
+		t.Errorf("Expected no recipients when emails are off, got %v", result)
+	}
+
+	t.Setenv("EMAIL_FROM", "pulls@acme.com")
+	expected := map[string]string{"alice": "alice@acme.com", "carol": "carol@acme.com"}
+	if result := Recipients(logins, slackUsersMap, Preferences(zap.NewNop()), zap.NewNop()); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestMention(t *testing.T) {
+	slackUsersMap := map[string]interface{}{"alice": "U1", "bob": "U2"}
+	emailed := map[string]string{"alice": "alice@acme.com"}
+
+	data := map[string]string{
+		"alice": "@alice",
+		"bob":   "<@U2>",
+		"carol": "@carol",
+	}
+	for login, expected := range data {
+		if result := Mention(login, slackUsersMap, emailed); result != expected {
+			t.Errorf("%s: Expected %s, got %s", login, expected, result)
+		}
+	}
+}
+
+func TestDigest(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "acme")
+	t.Setenv("GITHUB_URL", "")
+	os.Unsetenv("GITHUB_URL")
+
+	result := Digest("*Pull requests waiting for your review* (2)", []Entry{
+		{Repository: "api", Number: 7, CreatedAt: "2024-05-01T10:00:00Z"},
+		{Repository: "web", Number: 12},
+	})
+
+	lines := strings.Split(result, "\n")
+	if lines[0] != "*Pull requests waiting for your review* (2)" {
+		t.Errorf("Expected the heading first, got %q", lines[0])
+	}
+	if lines[2] != "• <https://github.com/acme/api/pull/7|api#7> opened 2024-05-01" || lines[3] != "• <https://github.com/acme/web/pull/12|web#12>" {
+		t.Errorf("Expected one line per pull request, got %q", lines[2:4])
+	}
+}
+
+func TestSend(t *testing.T) {
+	original := send
+	sent := []string{}
+	send = func(address string, subject string, text string) error {
+		sent = append(sent, address+": "+subject)
+		return errors.New("address not verified")
+	}
+	t.Cleanup(func() {
+		send = original
+	})
+
+	// the failure is only logged
+	Send("bob@acme.com", "Review requested: api#7", "text", zap.NewNop())
+	if len(sent) != 1 || sent[0] != "bob@acme.com: Review requested: api#7" {
+		t.Errorf("Expected the email to be sent, got %v", sent)
+	}
+}
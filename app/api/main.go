@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"slack-pr-lambda/api/handlers"
+	"slack-pr-lambda/api/jobs"
 	"slack-pr-lambda/api/routes"
 	"slack-pr-lambda/constants"
+	db "slack-pr-lambda/dynamodb"
 	"slack-pr-lambda/env"
 	"slack-pr-lambda/logger"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -18,15 +25,84 @@ import (
 	"go.uber.org/zap"
 )
 
-var httpLambda *httpadapter.HandlerAdapter
+// HTTP stack built on the first API Gateway request or warm ping, scheduled
+// jobs don't need the routes
+var httpStack struct {
+	once    sync.Once
+	adapter *httpadapter.HandlerAdapter
+}
+
+// set by the first invocation of the instance
+var invoked atomic.Bool
+
+// {"job": "<name>"} runs a scheduled job, {"warm": true} initializes the HTTP
+// stack and DynamoDB client of an idle instance
+type ScheduledEvent struct {
+	Job  string `json:"job"`
+	Warm bool   `json:"warm"`
+}
+
+func httpAdapter() *httpadapter.HandlerAdapter {
+	httpStack.once.Do(func() {
+		mux := http.NewServeMux()
+		routes.MainRoutes(mux)
+		httpStack.adapter = httpadapter.New(mux)
+
+		// once per cold start, GET /healthz runs it on demand. It doesn't hold
+		// the first request, a frozen instance finishes it on its next invocation
+		if zapLog, err := logger.Base(); err == nil {
+			go handlers.SlackScopeCheck(zapLog)
+		}
+	})
+	return httpStack.adapter
+}
+
+// API Gateway requests, EventBridge scheduled jobs and warm pings share the
+// same lambda
+func Handler(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	cold := !invoked.Swap(true)
+
+	var scheduled ScheduledEvent
+	if err := json.Unmarshal(raw, &scheduled); err == nil && scheduled.Job != "" {
+		defer handlers.TrackMemory(ctx, "job:"+scheduled.Job)()
+		return nil, jobs.Run(scheduled.Job)
+	}
+	if scheduled.Warm {
+		return nil, warm(ctx, cold)
+	}
+
+	var req events.APIGatewayProxyRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, err
+	}
 
-func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	return httpLambda.ProxyWithContext(ctx, req)
+	return httpAdapter().ProxyWithContext(ctx, req)
+}
+
+// builds what the next webhook needs so it doesn't pay for it
+func warm(ctx context.Context, cold bool) error {
+	started := time.Now()
+	httpAdapter()
+	if _, err := db.Connection(); err != nil {
+		return err
+	}
+
+	zapLog, err := logger.New(ctx)
+	if err != nil {
+		return err
+	}
+	zapLog.Info("warm ping",
+		zap.Bool("coldStart", cold),
+		zap.Duration("took", time.Since(started)),
+	)
+	return nil
 }
 
 func main() {
-	l := logger.LoggerConfig()
-	zapLog, _ := l.Build()
+	zapLog, err := logger.Base()
+	if err != nil {
+		log.Fatalf("error building the logger. %v\n", err)
+	}
 	ports := constants.Port()
 
 	defer func() {
@@ -41,10 +117,11 @@ func main() {
 	host := env.GetEnv("HOST", "localhost")
 	env := env.GetEnv("ENV", "local")
 
-	mux := http.NewServeMux()
-	routes.MainRoutes(mux)
-
 	if env == "local" {
+		mux := http.NewServeMux()
+		routes.MainRoutes(mux)
+		handlers.SlackScopeCheck(zapLog)
+
 		sandboxLink := fmt.Sprintf("http://%s%s", host, port)
 		zapLog.Info("running at 🚀⚙️",
 			zap.String("link", sandboxLink),
@@ -58,6 +135,5 @@ func main() {
 		}
 	}
 
-	httpLambda = httpadapter.New(mux)
 	lambda.Start(Handler)
 }
@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
 
 func TestMain(t *testing.T) {
 	t.Logf("Granule code are tested, no need to test main function.")
@@ -8,3 +12,23 @@ func TestMain(t *testing.T) {
 		t.Errorf("This should not fail")
 	}
 }
+
+func TestHandlerScheduledJob(t *testing.T) {
+	_, err := Handler(context.Background(), json.RawMessage(`{"job":"unknown"}`))
+	if err == nil {
+		t.Errorf("Expected error for unknown job")
+	}
+}
+
+func TestHandlerWarm(t *testing.T) {
+	_, err := Handler(context.Background(), json.RawMessage(`{"warm":true}`))
+	if err != nil {
+		t.Errorf("Expected warm ping to succeed: %v", err)
+	}
+	if httpStack.adapter == nil {
+		t.Errorf("Expected the HTTP stack to be built")
+	}
+	if !invoked.Load() {
+		t.Errorf("Expected the instance to be marked as invoked")
+	}
+}
@@ -0,0 +1,26 @@
+package messages
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func BenchmarkParentMessage(b *testing.B) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	item := &types.TablePullRequestData{
+		ParentMessage:      "<@U1> :github: opened new <https://github.com/o/api/pull/42|pull request> in `api`.",
+		Approvals:          1,
+		RequiredApprovals:  2,
+		Dependencies:       []string{"api#40", "web#7"},
+		MergedDependencies: []string{"api#40"},
+		ChangedDirectories: map[string]int{"handlers": 4, "messages": 2, "/": 1, "infra/lambda": 2},
+		DiffClass:          "M",
+		CreatedAt:          "2024-03-09T10:00:00Z",
+	}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = ParentMessage(item, now)
+	}
+}
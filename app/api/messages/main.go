@@ -0,0 +1,246 @@
+package messages
+
+import (
+	"fmt"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/i18n"
+	"slack-pr-lambda/types"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// text of the message key in the language of SLACK_CHANNEL, where the pull
+// request messages are posted
+func T(key string, args ...any) string {
+	return In(Language(), key, args...)
+}
+
+// text of the message key in a language, e.g. of a destination copy
+func In(language string, key string, args ...any) string {
+	return i18n.T(language, key, args...)
+}
+
+// language of SLACK_CHANNEL
+func Language() string {
+	return i18n.Language(env.GetEnv("SLACK_CHANNEL", ""))
+}
+
+// age badges of the parent message, the rendered tier is stored on the record
+// so the age job only edits messages whose tier changed
+const (
+	AgeFresh = "fresh"
+	AgeAging = "aging"
+	AgeStale = "stale"
+)
+
+// parent Slack message of a pull request, the stored notification text plus
+// status lines rendered from the record
+func ParentMessage(item *types.TablePullRequestData, now time.Time) string {
+	return ParentMessageIn(Language(), item, now)
+}
+
+// parent message with the status lines in a language, e.g. of a destination
+// copy whose item holds the notification text in that language
+func ParentMessageIn(language string, item *types.TablePullRequestData, now time.Time) string {
+	message := item.ParentMessage
+	if item.WorkInProgress {
+		message += "\n" + workInProgressLine(language)
+	}
+	message += "\n" + approvalsLine(language, item.Approvals, item.RequiredApprovals)
+	if len(item.Dependencies) > 0 {
+		message += "\n" + DependenciesLine(language, item.Dependencies, item.MergedDependencies)
+	}
+	if len(item.ChangedDirectories) > 0 {
+		message += "\n" + ChangedFilesLine(language, item.ChangedDirectories)
+	}
+	if item.DiffClass != "" {
+		message += fmt.Sprintf("\n%s %s", constants.Emoji().DiffClass, item.DiffClass)
+	}
+	if item.ChecklistTotal > 0 {
+		message += "\n" + checklistLine(language, item.ChecklistDone, item.ChecklistTotal)
+	}
+
+	if line := ageLine(language, AgeBadge(item.CreatedAt, now)); line != "" {
+		message += "\n" + line
+	}
+	return message
+}
+
+// compact card unfurling a pull request link pasted in Slack: the link, its
+// approvals, status lines and age, and a link to its thread
+func UnfurlCard(item *types.TablePullRequestData, threadUrl string, now time.Time) string {
+	lines := []string{
+		fmt.Sprintf("*<%s|%s#%d>*", github.PullRequestUrl(item.Repository, item.PullRequestId), item.Repository, item.PullRequestId),
+		ApprovalsLine(item.Approvals, item.RequiredApprovals),
+	}
+	if item.WorkInProgress {
+		lines = append(lines, WorkInProgressLine())
+	}
+	if item.DiffClass != "" {
+		lines = append(lines, fmt.Sprintf("%s %s", constants.Emoji().DiffClass, item.DiffClass))
+	}
+	if line := ageLine(Language(), AgeBadge(item.CreatedAt, now)); line != "" {
+		lines = append(lines, line)
+	}
+	if threadUrl != "" {
+		lines = append(lines, fmt.Sprintf("<%s|%s>", threadUrl, T("unfurl.thread")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// "Approvals: 1/2", marked approved once the quorum is met
+func ApprovalsLine(approvals int, required int) string {
+	return approvalsLine(Language(), approvals, required)
+}
+
+func approvalsLine(language string, approvals int, required int) string {
+	emoji := constants.Emoji()
+
+	if required <= 0 {
+		return In(language, "approvals", approvals)
+	}
+
+	line := In(language, "approvals.required", approvals, required)
+	if approvals >= required {
+		line += " " + emoji.Approved
+	}
+	return line
+}
+
+// "Depends on <url|acme/api#12> :merged:, <url|acme/web#7>", merged
+// dependencies are marked
+func DependenciesLine(language string, dependencies []string, merged []string) string {
+	links := []string{}
+	for _, dependency := range dependencies {
+		link := DependencyLink(dependency)
+		if slices.Contains(merged, dependency) {
+			link += " " + constants.Emoji().Merged
+		}
+		links = append(links, link)
+	}
+	return In(language, "dependencies", strings.Join(links, ", "))
+}
+
+// thread note of a merged dependency, unblocked once every dependency merged
+func DependencyMergedMessage(language string, dependency string, dependencies []string, merged []string) string {
+	message := In(language, "dependency.merged", constants.Emoji().Merged, DependencyLink(dependency))
+
+	waiting := []string{}
+	for _, other := range dependencies {
+		if other != dependency && !slices.Contains(merged, other) {
+			waiting = append(waiting, DependencyLink(other))
+		}
+	}
+	if len(waiting) > 0 {
+		return In(language, "dependency.waiting", message, strings.Join(waiting, ", "))
+	}
+	return In(language, "dependency.unblocked", message)
+}
+
+func DependencyLink(dependency string) string {
+	return fmt.Sprintf("<%s|%s>", github.ReferenceUrl(dependency), dependency)
+}
+
+// "Checklist: 3/5" of the body checkboxes, marked once every box is checked
+func ChecklistLine(done int, total int) string {
+	return checklistLine(Language(), done, total)
+}
+
+func checklistLine(language string, done int, total int) string {
+	line := In(language, "checklist", done, total)
+	if done == total {
+		line += " " + constants.Emoji().CheckPassed
+	}
+	return line
+}
+
+// top-level directories listed in the changed files line, the others are counted
+const changedDirectoriesShown = 4
+
+// "Files: 16 (12 in `web/`, 3 in `api/`, 1 at the root)", largest directories
+// first
+func ChangedFilesLine(language string, directories map[string]int) string {
+	names := []string{}
+	total := 0
+	for name, count := range directories {
+		names = append(names, name)
+		total += count
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if directories[names[i]] != directories[names[j]] {
+			return directories[names[i]] > directories[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	groups := []string{}
+	for i, name := range names {
+		if i == changedDirectoriesShown {
+			groups = append(groups, In(language, "files.more", len(names)-i))
+			break
+		}
+		if name == types.RootDirectory {
+			groups = append(groups, In(language, "files.root", directories[name]))
+			continue
+		}
+		groups = append(groups, In(language, "files.directory", directories[name], name))
+	}
+	return In(language, "files", total, strings.Join(groups, ", "))
+}
+
+// badge of a WIP title, reviewers are held back until the prefix is removed
+func WorkInProgressLine() string {
+	return workInProgressLine(Language())
+}
+
+func workInProgressLine(language string) string {
+	return In(language, "work_in_progress", constants.Emoji().WorkInProgress)
+}
+
+// fresh under a day, aging up to 3 days and stale after, empty for records
+// without a valid creation time
+func AgeBadge(createdAt string, now time.Time) string {
+	created, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return ""
+	}
+
+	age := now.Sub(created)
+	switch {
+	case age < 24*time.Hour:
+		return AgeFresh
+	case age <= 72*time.Hour:
+		return AgeAging
+	}
+	return AgeStale
+}
+
+func ageLine(language string, badge string) string {
+	switch badge {
+	case AgeFresh:
+		return In(language, "age.fresh", AgeEmoji(badge))
+	case AgeAging:
+		return In(language, "age.aging", AgeEmoji(badge))
+	case AgeStale:
+		return In(language, "age.stale", AgeEmoji(badge))
+	}
+	return ""
+}
+
+func AgeEmoji(badge string) string {
+	emoji := constants.Emoji()
+
+	switch badge {
+	case AgeFresh:
+		return emoji.AgeFresh
+	case AgeAging:
+		return emoji.AgeAging
+	case AgeStale:
+		return emoji.AgeStale
+	}
+	return ""
+}
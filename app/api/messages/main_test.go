@@ -0,0 +1,150 @@
+package messages
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestParentMessage(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		item     types.TablePullRequestData
+		expected string
+	}{
+		{
+			name:     "waiting for approvals",
+			item:     types.TablePullRequestData{ParentMessage: "opened", Approvals: 1, RequiredApprovals: 2},
+			expected: "opened\nApprovals: 1/2",
+		},
+		{
+			name:     "quorum met",
+			item:     types.TablePullRequestData{ParentMessage: "opened", Approvals: 2, RequiredApprovals: 2},
+			expected: "opened\nApprovals: 2/2 :approved:",
+		},
+		{
+			name:     "no approvals required",
+			item:     types.TablePullRequestData{ParentMessage: "opened", Approvals: 1},
+			expected: "opened\nApprovals: 1",
+		},
+		{
+			name:     "with age",
+			item:     types.TablePullRequestData{ParentMessage: "opened", RequiredApprovals: 1, CreatedAt: "2024-03-08T12:00:00Z"},
+			expected: "opened\nApprovals: 0/1\nAge: 1-3d :large_yellow_circle:",
+		},
+		{
+			name:     "work in progress",
+			item:     types.TablePullRequestData{ParentMessage: "opened", RequiredApprovals: 1, WorkInProgress: true},
+			expected: "opened\n:construction: Work in progress, reviewers are pinged once the WIP prefix is removed.\nApprovals: 0/1",
+		},
+		{
+			name:     "generated files",
+			item:     types.TablePullRequestData{ParentMessage: "opened", RequiredApprovals: 1, ChangedDirectories: map[string]int{"api": 16}, DiffClass: "Mostly generated files (14/16)"},
+			expected: "opened\nApprovals: 0/1\nFiles: 16 (16 in `api/`)\n:package: Mostly generated files (14/16)",
+		},
+		{
+			name:     "checklist",
+			item:     types.TablePullRequestData{ParentMessage: "opened", RequiredApprovals: 1, ChecklistDone: 1, ChecklistTotal: 3},
+			expected: "opened\nApprovals: 0/1\nChecklist: 1/3",
+		},
+		{
+			name:     "checklist complete",
+			item:     types.TablePullRequestData{ParentMessage: "opened", RequiredApprovals: 1, ChecklistDone: 3, ChecklistTotal: 3},
+			expected: "opened\nApprovals: 0/1\nChecklist: 3/3 :check-passed:",
+		},
+		{
+			name:     "dependencies",
+			item:     types.TablePullRequestData{ParentMessage: "opened", RequiredApprovals: 1, Dependencies: []string{"acme/api#12", "acme/web#7"}, MergedDependencies: []string{"acme/api#12"}},
+			expected: "opened\nApprovals: 0/1\nDepends on <https://github.com/acme/api/pull/12|acme/api#12> :merged:, <https://github.com/acme/web/pull/7|acme/web#7>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ParentMessage(&tt.item, now); result != tt.expected {
+				t.Errorf("got %q want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnfurlCard(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "acme")
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	item := types.TablePullRequestData{Repository: "api", PullRequestId: 7, Approvals: 1, RequiredApprovals: 2, CreatedAt: "2024-03-08T12:00:00Z"}
+	expected := "*<https://github.com/acme/api/pull/7|api#7>*\nApprovals: 1/2\nAge: 1-3d :large_yellow_circle:\n<https://acme.slack.com/archives/C1/p1|Slack thread>"
+	if card := UnfurlCard(&item, "https://acme.slack.com/archives/C1/p1", now); card != expected {
+		t.Errorf("got %q want %q", card, expected)
+	}
+
+	item = types.TablePullRequestData{Repository: "api", PullRequestId: 7, WorkInProgress: true}
+	expected = "*<https://github.com/acme/api/pull/7|api#7>*\nApprovals: 0\n:construction: Work in progress, reviewers are pinged once the WIP prefix is removed."
+	if card := UnfurlCard(&item, "", now); card != expected {
+		t.Errorf("got %q want %q", card, expected)
+	}
+}
+
+func TestChangedFilesLine(t *testing.T) {
+	line := ChangedFilesLine("en", map[string]int{"web": 12, "api": 3, "/": 1})
+	if expected := "Files: 16 (12 in `web/`, 3 in `api/`, 1 at the root)"; line != expected {
+		t.Errorf("got %q want %q", line, expected)
+	}
+
+	line = ChangedFilesLine("en", map[string]int{"a": 1, "b": 1, "c": 1, "d": 1, "e": 1, "f": 1})
+	if expected := "Files: 6 (1 in `a/`, 1 in `b/`, 1 in `c/`, 1 in `d/`, 2 more directories)"; line != expected {
+		t.Errorf("got %q want %q", line, expected)
+	}
+
+	line = ChangedFilesLine("fr", map[string]int{"web": 2})
+	if expected := "Fichiers : 2 (2 dans `web/`)"; line != expected {
+		t.Errorf("got %q want %q", line, expected)
+	}
+}
+
+func TestDependencyMergedMessage(t *testing.T) {
+	dependencies := []string{"acme/api#12", "acme/web#7"}
+
+	message := DependencyMergedMessage("en", "acme/api#12", dependencies, nil)
+	if expected := ":merged: <https://github.com/acme/api/pull/12|acme/api#12> merged, still waiting on <https://github.com/acme/web/pull/7|acme/web#7>."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+
+	message = DependencyMergedMessage("en", "acme/web#7", dependencies, []string{"acme/api#12"})
+	if expected := ":merged: <https://github.com/acme/web/pull/7|acme/web#7> merged — this PR is unblocked."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
+
+func TestAgeBadge(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]string{
+		"2024-03-10T01:00:00Z": AgeFresh,
+		"2024-03-09T12:00:00Z": AgeAging,
+		"2024-03-07T12:00:00Z": AgeAging,
+		"2024-03-07T11:00:00Z": AgeStale,
+		"":                     "",
+		"yesterday":            "",
+	}
+
+	for createdAt, expected := range tests {
+		if result := AgeBadge(createdAt, now); result != expected {
+			t.Errorf("AgeBadge(%q) = %q, want %q", createdAt, result, expected)
+		}
+	}
+}
+
+func TestParentMessageLanguage(t *testing.T) {
+	t.Setenv("SLACK_CHANNEL", "C123")
+	t.Setenv("CHANNEL_LANGUAGES", "C123=ja")
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	item := types.TablePullRequestData{ParentMessage: "opened", Approvals: 1, RequiredApprovals: 2, CreatedAt: "2024-03-08T12:00:00Z", ChangedDirectories: map[string]int{"web": 2}}
+	expected := "opened\n承認: 1/2\nファイル: 2 (`web/` に 2)\n経過: 1-3日 :large_yellow_circle:"
+	if message := ParentMessage(&item, now); message != expected {
+		t.Errorf("ParentMessage() = %q, expected %q", message, expected)
+	}
+}
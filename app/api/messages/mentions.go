@@ -0,0 +1,41 @@
+package messages
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// "@login" not preceded by a word character, so emails are not matched
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@/])@([A-Za-z0-9][A-Za-z0-9-]*)`)
+
+// GitHub text quoted in a code block, mentions don't render inside it so the
+// mapped users mentioned in the body are called out below
+func Quote(body string, slackUsersMap map[string]interface{}) string {
+	message := fmt.Sprintf("```%s```\n", body)
+	if mentions := Mentions(body, slackUsersMap); len(mentions) > 0 {
+		message += fmt.Sprintf("cc %s\n", strings.Join(mentions, " "))
+	}
+	return message
+}
+
+// Slack mentions of the mapped users @mentioned in a GitHub text, in order of
+// appearance. Team mentions (@org/team) and unmapped logins are skipped
+func Mentions(body string, slackUsersMap map[string]interface{}) []string {
+	mentions := []string{}
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(body, -1) {
+		if match[3] < len(body) && body[match[3]] == '/' {
+			continue
+		}
+
+		login := body[match[2]:match[3]]
+		user, ok := slackUsersMap[login]
+		if !ok || seen[login] {
+			continue
+		}
+		seen[login] = true
+		mentions = append(mentions, fmt.Sprintf("<@%s>", user))
+	}
+	return mentions
+}
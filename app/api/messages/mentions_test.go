@@ -0,0 +1,38 @@
+package messages
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMentions(t *testing.T) {
+	slackUsersMap := map[string]interface{}{
+		"alice":   "U1",
+		"bob-dev": "U2",
+	}
+
+	tests := map[string][]string{
+		"@alice can you check? cc @bob-dev and @alice": {"<@U1>", "<@U2>"},
+		"(@bob-dev) thanks":                            {"<@U2>"},
+		"mail alice@example.com or ping @carol":        {},
+		"@org/alice please review":                     {},
+		"no mentions":                                  {},
+	}
+
+	for body, expected := range tests {
+		if mentions := Mentions(body, slackUsersMap); !reflect.DeepEqual(mentions, expected) {
+			t.Errorf("%q: expected %v, got %v", body, expected, mentions)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	slackUsersMap := map[string]interface{}{"alice": "U1"}
+
+	if message := Quote("@alice nit", slackUsersMap); message != "```@alice nit```\ncc <@U1>\n" {
+		t.Errorf("Unexpected quote %q", message)
+	}
+	if message := Quote("nit", slackUsersMap); message != "```nit```\n" {
+		t.Errorf("Unexpected quote %q", message)
+	}
+}
@@ -0,0 +1,133 @@
+package messages
+
+import (
+	"fmt"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hours to fix an alert per severity, overridden by SECURITY_SLA_HOURS, e.g.
+// "critical=24,high=168", then by the securitySlaHours of the repository
+var securitySlaHours = map[string]int{
+	"critical": 24,
+	"high":     7 * 24,
+	"medium":   30 * 24,
+	"low":      90 * 24,
+}
+
+var alertKinds = map[string]string{
+	"dependabot":      "Dependabot alert",
+	"vulnerability":   "Vulnerability alert",
+	"code_scanning":   "Code scanning alert",
+	"secret_scanning": "Secret scanning alert",
+}
+
+// time to fix an alert of the severity in the repository, 0 for severities
+// without an SLA
+func SecuritySla(repository string, severity string) time.Duration {
+	hours := map[string]int{}
+	for name, value := range securitySlaHours {
+		hours[name] = value
+	}
+
+	for _, pair := range strings.Split(env.GetEnv("SECURITY_SLA_HOURS", ""), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && parsed > 0 {
+			hours[strings.ToLower(strings.TrimSpace(name))] = parsed
+		}
+	}
+
+	conf, err := config.LoadConfig()
+	if err != nil {
+		conf, _ = config.ParseConfig("")
+	}
+	for name, value := range conf.Repo(repository).SecuritySlaHours {
+		if value > 0 {
+			hours[strings.ToLower(name)] = value
+		}
+	}
+
+	return time.Duration(hours[severity]) * time.Hour
+}
+
+func SeverityEmoji(severity string) string {
+	emoji := constants.Emoji()
+
+	switch severity {
+	case "critical":
+		return emoji.SeverityCritical
+	case "high":
+		return emoji.SeverityHigh
+	case "medium":
+		return emoji.SeverityMedium
+	}
+	return emoji.SeverityLow
+}
+
+// parent message of an alert in the security channel, e.g.
+// ":red_circle: *High* Dependabot alert in `api`: <url|summary> · `lodash`, patched in 4.17.21"
+func SecurityAlertMessage(item *types.TableSecurityAlertData) string {
+	severity := orUnknown(item.Severity)
+
+	message := fmt.Sprintf("%s *%s* %s in `%s`: %s", SeverityEmoji(item.Severity), strings.ToUpper(severity[:1])+severity[1:], alertKind(item.Kind), item.Repository, alertLink(item))
+	if item.Package != "" {
+		message += fmt.Sprintf(" · `%s`", item.Package)
+	}
+	if item.PatchedVersion != "" {
+		message += fmt.Sprintf(", patched in %s", item.PatchedVersion)
+	}
+
+	if sla := SecuritySla(item.Repository, item.Severity); sla > 0 {
+		message += fmt.Sprintf("\nTo be fixed within %s.", slaText(sla))
+	}
+	return message
+}
+
+// warning in the thread of the pull request the alert was found on
+func SecurityPullRequestMessage(item *types.TableSecurityAlertData) string {
+	return fmt.Sprintf("%s %s on this branch: %s (%s). Please fix it before merging.", SeverityEmoji(item.Severity), alertKind(item.Kind), alertLink(item), orUnknown(item.Severity))
+}
+
+func alertKind(kind string) string {
+	if name, ok := alertKinds[kind]; ok {
+		return name
+	}
+	return "Security alert"
+}
+
+func alertLink(item *types.TableSecurityAlertData) string {
+	if item.HtmlUrl == "" {
+		return orUnknown(item.Summary)
+	}
+	return fmt.Sprintf("<%s|%s>", item.HtmlUrl, orUnknown(item.Summary))
+}
+
+// thread reply of an alert past its SLA
+func SecurityReminderMessage(item *types.TableSecurityAlertData, open time.Duration) string {
+	emoji := constants.Emoji()
+
+	return fmt.Sprintf("%s This %s alert has been open for %s, past its SLA of %s.", emoji.Reminder, orUnknown(item.Severity), slaText(open), slaText(SecuritySla(item.Repository, item.Severity)))
+}
+
+// "36h" under 2 days, "9 days" after
+func slaText(duration time.Duration) string {
+	if duration < 48*time.Hour {
+		return fmt.Sprintf("%dh", int(duration.Hours()))
+	}
+	return fmt.Sprintf("%d days", int(duration.Hours()/24))
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,79 @@
+package messages
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestSecuritySla(t *testing.T) {
+	if sla := SecuritySla("api", "critical"); sla != 24*time.Hour {
+		t.Errorf("Expected 24h for critical alerts, got %v", sla)
+	}
+	if sla := SecuritySla("api", "unknown"); sla != 0 {
+		t.Errorf("Expected no SLA, got %v", sla)
+	}
+
+	t.Setenv("SECURITY_SLA_HOURS", "critical=4, High=48,low=never")
+	if sla := SecuritySla("api", "critical"); sla != 4*time.Hour {
+		t.Errorf("Expected 4h, got %v", sla)
+	}
+	if sla := SecuritySla("api", "high"); sla != 48*time.Hour {
+		t.Errorf("Expected 48h, got %v", sla)
+	}
+	if sla := SecuritySla("api", "low"); sla != 90*24*time.Hour {
+		t.Errorf("Expected the default of low alerts, got %v", sla)
+	}
+
+	t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"securitySlaHours": {"critical": 2}}}}`)
+	if sla := SecuritySla("api", "critical"); sla != 2*time.Hour {
+		t.Errorf("Expected the repository SLA, got %v", sla)
+	}
+	if sla := SecuritySla("web", "critical"); sla != 4*time.Hour {
+		t.Errorf("Expected SECURITY_SLA_HOURS, got %v", sla)
+	}
+}
+
+func TestSecurityAlertMessage(t *testing.T) {
+	item := &types.TableSecurityAlertData{
+		Kind:           "dependabot",
+		Repository:     "api",
+		Severity:       "high",
+		Summary:        "Prototype pollution in lodash",
+		Package:        "lodash",
+		PatchedVersion: "4.17.21",
+		HtmlUrl:        "https://github.com/o/api/security/dependabot/3",
+	}
+
+	message := SecurityAlertMessage(item)
+	expected := ":red_circle: *High* Dependabot alert in `api`: <https://github.com/o/api/security/dependabot/3|Prototype pollution in lodash> · `lodash`, patched in 4.17.21\nTo be fixed within 7 days."
+	if message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+
+	message = SecurityAlertMessage(&types.TableSecurityAlertData{Kind: "vulnerability", Repository: "web"})
+	if expected := ":large_yellow_circle: *Unknown* Vulnerability alert in `web`: unknown"; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
+
+func TestSecurityPullRequestMessage(t *testing.T) {
+	item := &types.TableSecurityAlertData{
+		Kind:     "code_scanning",
+		Severity: "high",
+		Summary:  "Database query built from user-controlled sources",
+		HtmlUrl:  "https://github.com/o/api/security/code-scanning/12",
+	}
+
+	message := SecurityPullRequestMessage(item)
+	if expected := ":red_circle: Code scanning alert on this branch: <https://github.com/o/api/security/code-scanning/12|Database query built from user-controlled sources> (high). Please fix it before merging."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
+
+func TestSecurityReminderMessage(t *testing.T) {
+	message := SecurityReminderMessage(&types.TableSecurityAlertData{Severity: "critical"}, 36*time.Hour)
+	if expected := ":bell: This critical alert has been open for 36h, past its SLA of 24h."; message != expected {
+		t.Errorf("got %q want %q", message, expected)
+	}
+}
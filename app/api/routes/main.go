@@ -3,9 +3,107 @@ package routes
 import (
 	"net/http"
 	"slack-pr-lambda/api/handlers"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/metrics"
 )
 
+// parameter of a route, see OpenAPI parameter objects. In is path, query or
+// header, Type string or integer and Format date for YYYY-MM-DD
+type Param struct {
+	Name        string
+	In          string
+	Type        string
+	Format      string
+	Enum        []string
+	Required    bool
+	Description string
+}
+
+// route of the HTTP surface, the OpenAPI document is generated from the routes
+// and requests are validated against their params before the handler runs.
+// Admin routes require the ADMIN_TOKEN bearer token
+type Route struct {
+	Method      string
+	Path        string
+	Summary     string
+	Admin       bool
+	Params      []Param
+	ContentType string
+	Handler     http.HandlerFunc
+}
+
+func (route Route) Pattern() string {
+	if route.Method == "" {
+		return route.Path
+	}
+	return route.Method + " " + route.Path
+}
+
+var (
+	repositoryParam = Param{Name: "repository", In: "path", Type: "string", Required: true, Description: "repository name, without the owner"}
+	numberParam     = Param{Name: "number", In: "path", Type: "integer", Required: true, Description: "pull request number"}
+)
+
+func Routes() []Route {
+	pullRequest := []Param{repositoryParam, numberParam}
+
+	return []Route{
+		{Path: "/", Summary: "Service status", Handler: handlers.IndexRequestHandler},
+		{Method: "GET", Path: "/healthz", Summary: "Slack token check, 503 when the token is rejected or misses scopes", Handler: handlers.HealthHandler},
+		// GitHub Enterprise Server hooks can be pointed to another path
+		{Method: "POST", Path: env.GetEnv("WEBHOOK_PATH", "/pull-request"), Summary: "GitHub webhook", ContentType: "application/json", Handler: handlers.PullRequestHandler, Params: []Param{
+			{Name: "X-GitHub-Event", In: "header", Type: "string", Description: "GitHub event of the delivery"},
+			{Name: "X-GitHub-Delivery", In: "header", Type: "string", Description: "id of the delivery"},
+			{Name: "X-Hub-Signature-256", In: "header", Type: "string", Description: "HMAC of the body with the webhook secret"},
+		}},
+		{Method: "POST", Path: "/slack/commands", Summary: "Slack slash commands", ContentType: "application/x-www-form-urlencoded", Handler: handlers.SlackCommandHandler},
+		{Method: "POST", Path: "/slack/interactions", Summary: "Slack block actions and shortcuts", ContentType: "application/x-www-form-urlencoded", Handler: handlers.SlackInteractionHandler},
+		{Method: "POST", Path: "/slack/events", Summary: "Slack Events API", ContentType: "application/json", Handler: handlers.SlackEventHandler},
+		{Method: "GET", Path: "/prs", Summary: "Tracked pull requests, oldest first", Admin: true, Handler: handlers.PullRequestsHandler, Params: []Param{
+			{Name: "repo", In: "query", Type: "string", Description: "narrows to a repository"},
+		}},
+		{Method: "GET", Path: "/threads/{repository}/{number}", Summary: "Redirect to the Slack thread of a pull request", Params: pullRequest, Handler: handlers.ThreadHandler},
+		{Method: "POST", Path: "/jobs/{name}", Summary: "Run a scheduled job, only with ENV=local", Handler: handlers.JobHandler, Params: []Param{
+			{Name: "name", In: "path", Type: "string", Required: true, Description: "job name"},
+		}},
+		{Method: "POST", Path: "/admin/pull-requests/{repository}/{number}/resend", Summary: "Re-post the parent message of a tracked pull request", Admin: true, Params: pullRequest, Handler: handlers.AdminResendHandler},
+		{Method: "DELETE", Path: "/admin/pull-requests/{repository}/{number}/messages", Summary: "Delete the bot messages of a pull request", Admin: true, Handler: handlers.AdminDeleteMessagesHandler, Params: append(pullRequest,
+			Param{Name: "mode", In: "query", Type: "string", Enum: []string{"redact"}, Description: "replace the text of the messages instead"},
+		)},
+		{Method: "POST", Path: "/admin/pull-requests/{repository}/{number}/restore", Summary: "Restore the soft-deleted record of a closed pull request", Admin: true, Params: pullRequest, Handler: handlers.AdminRestoreHandler},
+		{Method: "GET", Path: "/admin/pull-requests/{repository}/{number}/events", Summary: "Processed webhooks of a pull request, oldest first", Admin: true, Params: pullRequest, Handler: handlers.AdminEventsHandler},
+		{Method: "GET", Path: "/admin/review-metrics", Summary: "Review metrics export", Admin: true, Handler: handlers.AdminReviewMetricsHandler, Params: []Param{
+			{Name: "format", In: "query", Type: "string", Enum: []string{"csv", "json"}, Description: "csv when empty"},
+			{Name: "repo", In: "query", Type: "string", Description: "narrows to a repository"},
+			{Name: "from", In: "query", Type: "string", Format: "date", Description: "first opening date, included"},
+			{Name: "to", In: "query", Type: "string", Format: "date", Description: "last opening date, excluded"},
+		}},
+		{Method: "GET", Path: "/admin/archives/{repository}/{number}", Summary: "Archived thread of a closed pull request", Admin: true, Params: pullRequest, Handler: handlers.AdminArchiveHandler},
+		{Method: "DELETE", Path: "/admin/users/{slackUserId}", Summary: "Data deletion request of a Slack user", Admin: true, Handler: handlers.AdminDeleteUserHandler, Params: []Param{
+			{Name: "slackUserId", In: "path", Type: "string", Required: true, Description: "Slack user id"},
+			{Name: "login", In: "query", Type: "string", Description: "GitHub login of the user once it is no longer in constants.Users"},
+		}},
+		{Method: "GET", Path: "/admin/features/{repository}", Summary: "Feature flags of the config document and whether they are on for a repository", Admin: true, Params: []Param{repositoryParam}, Handler: handlers.AdminFeaturesHandler},
+		{Method: "POST", Path: "/admin/contributors/{repository}/seed", Summary: "Record the authors of every pull request of a repository on GitHub", Admin: true, Params: []Param{repositoryParam}, Handler: handlers.AdminSeedContributorsHandler},
+		{Method: "GET", Path: "/admin/debug/pprof/{profile}", Summary: "Runtime profile of the lambda instance, only with PPROF_ENABLED", Admin: true, Handler: handlers.AdminProfileHandler, Params: []Param{
+			{Name: "profile", In: "path", Type: "string", Required: true, Enum: handlers.Profiles, Description: "profile name"},
+			{Name: "seconds", In: "query", Type: "integer", Description: "duration of the profile and trace, 30 when empty"},
+			{Name: "debug", In: "query", Type: "integer", Description: "text format when 1"},
+		}},
+		{Method: "GET", Path: "/openapi.json", Summary: "OpenAPI document of the routes", Handler: OpenAPIHandler},
+	}
+}
+
 func MainRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/", handlers.IndexRequestHandler)
-	mux.HandleFunc("POST /pull-request", handlers.PullRequestHandler)
+	for _, route := range Routes() {
+		handle(mux, route)
+	}
+	mux.HandleFunc("GET /metrics", handlers.MetricsHandler)
+}
+
+// every route is counted and timed under its pattern, invalid requests are
+// answered before the handler. Requests are correlated, allocations are logged
+// with ALLOC_LOGGING
+func handle(mux *http.ServeMux, route Route) {
+	mux.HandleFunc(route.Pattern(), metrics.Instrument(route.Pattern(), handlers.Correlated(handlers.MemoryTracked(route.Pattern(), validated(route)))))
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -44,4 +45,91 @@ func TestMainRoutes(t *testing.T) {
 		t.Errorf("POST /pull-request returned %v, expected %v", rr.Code, http.StatusOK)
 	}
 
+	// POST /slack/commands
+	req, err = http.NewRequest("POST", "/slack/commands", strings.NewReader("command=%2Fpr-unknown"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("POST /slack/commands returned %v, expected %v", rr.Code, http.StatusOK)
+	}
+
+	// POST /slack/interactions
+	req, err = http.NewRequest("POST", "/slack/interactions", strings.NewReader("payload=%7B%7D"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("POST /slack/interactions returned %v, expected %v", rr.Code, http.StatusOK)
+	}
+
+	// POST /slack/events
+	req, err = http.NewRequest("POST", "/slack/events", strings.NewReader(`{"type":"url_verification","challenge":"abc"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("POST /slack/events returned %v, expected %v", rr.Code, http.StatusOK)
+	}
+
+	// POST /admin/pull-requests/{repository}/{number}/resend
+	req, err = http.NewRequest("POST", "/admin/pull-requests/api/1/resend", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("POST /admin/pull-requests/api/1/resend returned %v, expected %v", rr.Code, http.StatusUnauthorized)
+	}
+
+	// GET /metrics
+	req, err = http.NewRequest("GET", "/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("GET /metrics returned %v, expected %v", rr.Code, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), `http_requests_total{route="POST /pull-request",status="200"}`) {
+		t.Errorf("GET /metrics missing the pull request route, got %v", rr.Body.String())
+	}
+
+}
+
+func TestWebhookPath(t *testing.T) {
+	t.Setenv("WEBHOOK_PATH", "/github/webhook")
+
+	mux := http.NewServeMux()
+	MainRoutes(mux)
+
+	req, err := http.NewRequest("POST", "/github/webhook", strings.NewReader(`{"action": "test"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("POST /github/webhook returned %v, expected %v", rr.Code, http.StatusOK)
+	}
 }
@@ -0,0 +1,191 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type schema struct {
+	Type   string   `json:"type"`
+	Format string   `json:"format,omitempty"`
+	Enum   []string `json:"enum,omitempty"`
+}
+
+type parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description,omitempty"`
+	Schema      schema `json:"schema"`
+}
+
+type mediaType struct {
+	Schema schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string `json:"description"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary"`
+	OperationId string                `json:"operationId"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type securityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       map[string]string               `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components struct {
+		SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+	} `json:"components"`
+}
+
+// OpenAPI 3.0 document of the routes, routes without a method (the index
+// catch-all) are documented as GET
+func OpenAPI(routes []Route) Document {
+	document := Document{
+		OpenAPI: "3.0.3",
+		Info:    map[string]string{"title": "slack-pr-lambda", "version": "1.0.0"},
+		Paths:   map[string]map[string]operation{},
+	}
+	document.Components.SecuritySchemes = map[string]securityScheme{
+		"adminToken": {Type: "http", Scheme: "bearer"},
+	}
+
+	for _, route := range routes {
+		method := strings.ToLower(route.Method)
+		if method == "" {
+			method = "get"
+		}
+
+		op := operation{
+			Summary:     route.Summary,
+			OperationId: operationId(method, route.Path),
+			Responses:   map[string]response{"200": {Description: "OK"}},
+		}
+		for _, param := range route.Params {
+			op.Parameters = append(op.Parameters, parameter{
+				Name:        param.Name,
+				In:          param.In,
+				Required:    param.Required,
+				Description: param.Description,
+				Schema:      schema{Type: param.Type, Format: param.Format, Enum: param.Enum},
+			})
+			if param.In != "header" {
+				op.Responses["400"] = response{Description: "Bad Request"}
+			}
+		}
+		if route.ContentType != "" {
+			op.RequestBody = &requestBody{
+				Required: true,
+				Content:  map[string]mediaType{route.ContentType: {Schema: schema{Type: "object"}}},
+			}
+		}
+		if route.Admin {
+			op.Security = []map[string][]string{{"adminToken": {}}}
+			op.Responses["401"] = response{Description: "Unauthorized"}
+		}
+
+		if document.Paths[route.Path] == nil {
+			document.Paths[route.Path] = map[string]operation{}
+		}
+		document.Paths[route.Path][method] = op
+	}
+	return document
+}
+
+// e.g. post_admin_pull_requests_repository_number_resend
+func operationId(method string, path string) string {
+	words := strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '{' || r == '}' || r == '.'
+	})
+	if len(words) == 0 {
+		words = []string{"index"}
+	}
+	return strings.Join(append([]string{method}, words...), "_")
+}
+
+// the request against the params of its route: 401 when an admin route has no
+// bearer token, 400 when a param is missing, not an integer, not a date or
+// not one of its values. The handler still checks the token itself
+func validate(route Route, r *http.Request) (int, string) {
+	if route.Admin && !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		return http.StatusUnauthorized, "Unauthorized"
+	}
+
+	for _, param := range route.Params {
+		var value string
+		switch param.In {
+		case "path":
+			value = r.PathValue(param.Name)
+		case "query":
+			value = r.URL.Query().Get(param.Name)
+		case "header":
+			value = r.Header.Get(param.Name)
+		}
+
+		if value == "" {
+			if param.Required {
+				return http.StatusBadRequest, fmt.Sprintf("Bad Request: %s is required", param.Name)
+			}
+			continue
+		}
+		if param.Type == "integer" {
+			if _, err := strconv.Atoi(value); err != nil {
+				return http.StatusBadRequest, fmt.Sprintf("Bad Request: %s must be an integer", param.Name)
+			}
+		}
+		if param.Format == "date" {
+			if _, err := time.Parse(time.DateOnly, value); err != nil {
+				return http.StatusBadRequest, fmt.Sprintf("Bad Request: %s must be a YYYY-MM-DD date", param.Name)
+			}
+		}
+		if len(param.Enum) > 0 && !slices.Contains(param.Enum, value) {
+			return http.StatusBadRequest, fmt.Sprintf("Bad Request: %s must be one of %s", param.Name, strings.Join(param.Enum, ", "))
+		}
+	}
+	return http.StatusOK, ""
+}
+
+func validated(route Route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if status, message := validate(route, r); status != http.StatusOK {
+			http.Error(w, message, status)
+			return
+		}
+		route.Handler(w, r)
+	}
+}
+
+// the OpenAPI document, e.g. imported by the API gateway tooling
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	j, err := json.Marshal(OpenAPI(Routes()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(j)
+}
@@ -0,0 +1,104 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+// every {param} of a path is declared, the document names each route once
+func TestOpenAPI(t *testing.T) {
+	routes := Routes()
+	document := OpenAPI(routes)
+
+	operations := 0
+	for _, methods := range document.Paths {
+		operations += len(methods)
+	}
+	if operations != len(routes) {
+		t.Errorf("OpenAPI documents %v operations, expected %v", operations, len(routes))
+	}
+
+	pathParam := regexp.MustCompile(`{(\w+)}`)
+	for _, route := range routes {
+		declared := map[string]bool{}
+		for _, param := range route.Params {
+			if param.In == "path" {
+				declared[param.Name] = true
+			}
+		}
+		for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+			if !declared[match[1]] {
+				t.Errorf("%v does not declare the path param %v", route.Pattern(), match[1])
+			}
+		}
+	}
+
+	resend := document.Paths["/admin/pull-requests/{repository}/{number}/resend"]["post"]
+	if len(resend.Security) != 1 || resend.Responses["401"].Description == "" {
+		t.Errorf("admin route missing the bearer token, got %+v", resend)
+	}
+	if resend.OperationId != "post_admin_pull_requests_repository_number_resend" {
+		t.Errorf("operationId %v", resend.OperationId)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	mux := http.NewServeMux()
+	MainRoutes(mux)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		token  string
+		status int
+	}{
+		{"no token", "GET", "/admin/review-metrics?format=xml", "", http.StatusUnauthorized},
+		{"enum", "GET", "/admin/review-metrics?format=xml", "secret", http.StatusBadRequest},
+		{"date", "GET", "/admin/review-metrics?from=03/01/2024", "secret", http.StatusBadRequest},
+		{"integer", "POST", "/admin/pull-requests/api/seven/resend", "secret", http.StatusBadRequest},
+		{"query route", "GET", "/threads/api/seven", "", http.StatusBadRequest},
+		{"wrong token", "POST", "/admin/pull-requests/api/7/restore", "other", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("%v %v returned %v, expected %v", tt.method, tt.url, rr.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	MainRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/openapi.json", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json returned %v, expected %v", rr.Code, http.StatusOK)
+	}
+
+	var document Document
+	if err := json.Unmarshal(rr.Body.Bytes(), &document); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := document.Paths["/prs"]["get"]; !ok {
+		t.Errorf("GET /openapi.json missing /prs, got %v", rr.Body.String())
+	}
+}
@@ -0,0 +1,41 @@
+package alert
+
+import (
+	"fmt"
+	"slack-pr-lambda/env"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// last alert per channel the bot has to be invited to
+var invites = struct {
+	sync.Mutex
+	alerted map[string]time.Time
+}{alerted: map[string]time.Time{}}
+
+// ops alert naming a channel the bot is not in and could not join, posted to
+// ALERT_CHANNEL at most once per ALERT_WINDOW_SECONDS and channel
+func NotInChannel(channel string) error {
+	alertChannel := env.GetEnv("ALERT_CHANNEL", "")
+	// an alert channel the bot is not in can't be told about it
+	if alertChannel == "" || alertChannel == channel {
+		return nil
+	}
+
+	seconds, err := strconv.Atoi(env.GetEnv("ALERT_WINDOW_SECONDS", "300"))
+	if err != nil {
+		return err
+	}
+
+	invites.Lock()
+	now := time.Now()
+	if last, ok := invites.alerted[channel]; ok && now.Sub(last) < time.Duration(seconds)*time.Second {
+		invites.Unlock()
+		return nil
+	}
+	invites.alerted[channel] = now
+	invites.Unlock()
+
+	return send(alertChannel, fmt.Sprintf(":warning: The bot is not in <#%s> and can't join it, messages to the channel fail until the bot is invited with `/invite`.", channel))
+}
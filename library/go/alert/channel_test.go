@@ -0,0 +1,54 @@
+package alert
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotInChannel(t *testing.T) {
+	messages := stubSend(t)
+	t.Setenv("ALERT_CHANNEL", "C-OPS")
+	t.Cleanup(func() {
+		invites.alerted = map[string]time.Time{}
+	})
+
+	if err := NotInChannel("C-PRS"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*messages) != 1 || (*messages)[0].channel != "C-OPS" {
+		t.Fatalf("Expected 1 alert in the ops channel, got %v", *messages)
+	}
+	if expected := ":warning: The bot is not in <#C-PRS> and can't join it, messages to the channel fail until the bot is invited with `/invite`."; (*messages)[0].message != expected {
+		t.Errorf("got %q want %q", (*messages)[0].message, expected)
+	}
+
+	// once per window and channel
+	NotInChannel("C-PRS")
+	NotInChannel("C-SEC")
+	if len(*messages) != 2 {
+		t.Errorf("Expected one more alert for the other channel, got %v", *messages)
+	}
+
+	invites.alerted["C-PRS"] = time.Now().Add(-time.Hour)
+	NotInChannel("C-PRS")
+	if len(*messages) != 3 {
+		t.Errorf("Expected a new alert after the window, got %v", *messages)
+	}
+}
+
+func TestNotInChannelAlertChannel(t *testing.T) {
+	messages := stubSend(t)
+	t.Cleanup(func() {
+		invites.alerted = map[string]time.Time{}
+	})
+
+	t.Setenv("ALERT_CHANNEL", "")
+	NotInChannel("C-PRS")
+
+	t.Setenv("ALERT_CHANNEL", "C-OPS")
+	NotInChannel("C-OPS")
+
+	if len(*messages) != 0 {
+		t.Errorf("Expected no alert, got %v", *messages)
+	}
+}
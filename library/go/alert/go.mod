@@ -0,0 +1,3 @@
+module slack-pr-lambda/alert
+
+go 1.22
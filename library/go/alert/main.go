@@ -0,0 +1,99 @@
+package alert
+
+import (
+	"fmt"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/slack"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type failure struct {
+	Repository string
+	Action     string
+	At         time.Time
+}
+
+var window = struct {
+	sync.Mutex
+	failures []failure
+}{}
+
+// replaced in tests
+var send = slack.SlackSendChannelMessage
+
+// record a failed webhook, once ALERT_THRESHOLD failures happened within
+// ALERT_WINDOW_SECONDS the breakdown is posted to ALERT_CHANNEL and the window
+// starts over, without ALERT_CHANNEL nothing is recorded
+func Failure(repository string, action string) error {
+	channel := env.GetEnv("ALERT_CHANNEL", "")
+	if channel == "" {
+		return nil
+	}
+
+	threshold, err := strconv.Atoi(env.GetEnv("ALERT_THRESHOLD", "5"))
+	if err != nil {
+		return err
+	}
+	seconds, err := strconv.Atoi(env.GetEnv("ALERT_WINDOW_SECONDS", "300"))
+	if err != nil {
+		return err
+	}
+	duration := time.Duration(seconds) * time.Second
+
+	window.Lock()
+	defer window.Unlock()
+
+	now := time.Now()
+	recent := []failure{}
+	for _, f := range window.failures {
+		if now.Sub(f.At) < duration {
+			recent = append(recent, f)
+		}
+	}
+	recent = append(recent, failure{Repository: repository, Action: action, At: now})
+	window.failures = recent
+
+	if len(recent) < threshold {
+		return nil
+	}
+
+	window.failures = nil
+	return send(channel, alertMessage(recent, duration))
+}
+
+// ":rotating_light: 5 webhook failures in the last 5m0s" followed by the count
+// per repository and action, most failing first
+func alertMessage(failures []failure, duration time.Duration) string {
+	counts := map[string]int{}
+	for _, f := range failures {
+		repository := f.Repository
+		if repository == "" {
+			repository = "unknown"
+		}
+		counts[repository+" "+f.Action]++
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	lines := []string{
+		fmt.Sprintf(":rotating_light: %d webhook failures in the last %s", len(failures), duration),
+	}
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("• %s: %d", key, counts[key]))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,95 @@
+package alert
+
+import (
+	"testing"
+	"time"
+)
+
+type sent struct {
+	channel string
+	message string
+}
+
+func stubSend(t *testing.T) *[]sent {
+	messages := []sent{}
+	original := send
+	send = func(channel string, message string) error {
+		messages = append(messages, sent{channel: channel, message: message})
+		return nil
+	}
+	t.Cleanup(func() {
+		send = original
+		window.failures = nil
+	})
+	return &messages
+}
+
+func TestFailure(t *testing.T) {
+	messages := stubSend(t)
+	t.Setenv("ALERT_CHANNEL", "C-OPS")
+	t.Setenv("ALERT_THRESHOLD", "3")
+
+	Failure("api", "opened")
+	Failure("api", "opened")
+	if len(*messages) != 0 {
+		t.Fatalf("Expected no alert below the threshold, got %v", *messages)
+	}
+
+	if err := Failure("web", "closed"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(*messages) != 1 {
+		t.Fatalf("Expected 1 alert, got %v", *messages)
+	}
+	if (*messages)[0].channel != "C-OPS" {
+		t.Errorf("Expected the ops channel, got %s", (*messages)[0].channel)
+	}
+
+	// the window starts over after an alert
+	Failure("api", "opened")
+	if len(*messages) != 1 {
+		t.Errorf("Expected no new alert, got %v", *messages)
+	}
+}
+
+func TestFailureWindow(t *testing.T) {
+	messages := stubSend(t)
+	t.Setenv("ALERT_CHANNEL", "C-OPS")
+	t.Setenv("ALERT_THRESHOLD", "2")
+
+	window.failures = []failure{{Repository: "api", Action: "opened", At: time.Now().Add(-time.Hour)}}
+
+	Failure("api", "opened")
+	if len(*messages) != 0 {
+		t.Errorf("Expected failures outside the window to be dropped, got %v", *messages)
+	}
+}
+
+func TestFailureWithoutChannel(t *testing.T) {
+	messages := stubSend(t)
+	t.Setenv("ALERT_CHANNEL", "")
+	t.Setenv("ALERT_THRESHOLD", "1")
+
+	Failure("api", "opened")
+	if len(*messages) != 0 || len(window.failures) != 0 {
+		t.Errorf("Expected nothing recorded, got %v", *messages)
+	}
+}
+
+func TestAlertMessage(t *testing.T) {
+	now := time.Now()
+	result := alertMessage([]failure{
+		{Repository: "web", Action: "closed", At: now},
+		{Repository: "api", Action: "opened", At: now},
+		{Repository: "api", Action: "opened", At: now},
+		{Action: "created", At: now},
+	}, 5*time.Minute)
+
+	expected := ":rotating_light: 4 webhook failures in the last 5m0s\n" +
+		"• api opened: 2\n" +
+		"• unknown created: 1\n" +
+		"• web closed: 1"
+	if result != expected {
+		t.Errorf("got\n%s\nwant\n%s", result, expected)
+	}
+}
@@ -0,0 +1,3 @@
+module slack-pr-lambda/apperrors
+
+go 1.22
@@ -0,0 +1,85 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// kinds of failures shared by the packages, matched with errors.Is through
+// the wrapping layers
+var (
+	ErrSlackRateLimited = errors.New("slack rate limited")
+	ErrPRNotFound       = errors.New("pull request not found")
+	ErrBadPayload       = errors.New("bad payload")
+	ErrDynamoThrottle   = errors.New("dynamodb throttled")
+)
+
+// failure of an operation, e.g. slack.chat.postMessage, errors.Is matches both
+// its kind and its cause
+type Error struct {
+	Kind error
+	Op   string
+	Err  error
+	// wait asked by the remote service, 0 when it didn't say
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v: %v", e.Op, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// err as a failure of kind, nil stays nil
+func Wrap(kind error, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Op: op, Err: err}
+}
+
+// kinds by HTTP status and metric label, the first match wins
+var kinds = []struct {
+	err    error
+	status int
+	label  string
+}{
+	{ErrBadPayload, http.StatusBadRequest, "bad_payload"},
+	{ErrPRNotFound, http.StatusNotFound, "pr_not_found"},
+	{ErrSlackRateLimited, http.StatusServiceUnavailable, "slack_rate_limited"},
+	{ErrDynamoThrottle, http.StatusServiceUnavailable, "dynamo_throttle"},
+}
+
+// HTTP status answering err, 503 for the failures a later retry gets through
+// and 500 for the unknown ones
+func Status(err error) int {
+	for _, kind := range kinds {
+		if errors.Is(err, kind.err) {
+			return kind.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// metric label of err, internal for the unknown ones
+func Kind(err error) string {
+	for _, kind := range kinds {
+		if errors.Is(err, kind.err) {
+			return kind.label
+		}
+	}
+	return "internal"
+}
+
+// wait asked by the remote service of err, 0 when none did
+func RetryAfter(err error) time.Duration {
+	var failure *Error
+	if errors.As(err, &failure) {
+		return failure.RetryAfter
+	}
+	return 0
+}
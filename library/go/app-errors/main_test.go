@@ -0,0 +1,64 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWrap(t *testing.T) {
+	if Wrap(ErrBadPayload, "webhook.decode", nil) != nil {
+		t.Errorf("Expected nil to stay nil")
+	}
+
+	cause := errors.New("unexpected end of JSON input")
+	err := fmt.Errorf("opened: %w", Wrap(ErrBadPayload, "webhook.decode", cause))
+
+	if !errors.Is(err, ErrBadPayload) || !errors.Is(err, cause) {
+		t.Errorf("Expected the kind and the cause to match")
+	}
+	if errors.Is(err, ErrPRNotFound) {
+		t.Errorf("Expected another kind not to match")
+	}
+	if expected := "opened: webhook.decode: bad payload: unexpected end of JSON input"; err.Error() != expected {
+		t.Errorf("got %q want %q", err.Error(), expected)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	cause := errors.New("x")
+	for _, test := range []struct {
+		err    error
+		status int
+		kind   string
+	}{
+		{Wrap(ErrBadPayload, "webhook.decode", cause), http.StatusBadRequest, "bad_payload"},
+		{Wrap(ErrPRNotFound, "github.pull_request", cause), http.StatusNotFound, "pr_not_found"},
+		{Wrap(ErrSlackRateLimited, "slack.chat.postMessage", cause), http.StatusServiceUnavailable, "slack_rate_limited"},
+		{Wrap(ErrDynamoThrottle, "dynamodb.GetItem", cause), http.StatusServiceUnavailable, "dynamo_throttle"},
+		// errors of the worker pool are joined
+		{errors.Join(cause, Wrap(ErrDynamoThrottle, "dynamodb.PutItem", cause)), http.StatusServiceUnavailable, "dynamo_throttle"},
+		{cause, http.StatusInternalServerError, "internal"},
+		{nil, http.StatusInternalServerError, "internal"},
+	} {
+		if status := Status(test.err); status != test.status {
+			t.Errorf("%v: got status %d want %d", test.err, status, test.status)
+		}
+		if kind := Kind(test.err); kind != test.kind {
+			t.Errorf("%v: got kind %s want %s", test.err, kind, test.kind)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if after := RetryAfter(errors.New("x")); after != 0 {
+		t.Errorf("got %s want 0", after)
+	}
+
+	err := fmt.Errorf("send: %w", &Error{Kind: ErrSlackRateLimited, Op: "slack.chat.postMessage", Err: errors.New("x"), RetryAfter: 30 * time.Second})
+	if after := RetryAfter(err); after != 30*time.Second {
+		t.Errorf("got %s want 30s", after)
+	}
+}
@@ -0,0 +1,135 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"go.uber.org/zap"
+)
+
+var ErrNotFound = errors.New("archive not found")
+
+// Slack thread and lifecycle of a closed pull request, kept in ARCHIVE_BUCKET
+// for retention once the tracking record is deleted
+type Document struct {
+	PullRequest string                       `json:"pullRequest"`
+	Repository  string                       `json:"repository"`
+	Number      int                          `json:"number"`
+	ArchivedAt  string                       `json:"archivedAt"`
+	Record      types.TablePullRequestData   `json:"record"`
+	Lifecycle   types.TableReviewMetricsData `json:"lifecycle"`
+	Messages    []slack.ThreadMessage        `json:"messages"`
+}
+
+// archiving is off without a bucket
+func Enabled() bool {
+	return env.GetEnv("ARCHIVE_BUCKET", "") != ""
+}
+
+// object key of a pull request closed at closedAt, a reopened and closed again
+// pull request gets one archive per close
+func Key(repository string, number int, closedAt string) string {
+	return fmt.Sprintf("%s%s.json", prefix(repository, number), closedAt)
+}
+
+// keys of the archives of a pull request, the close times sort chronologically
+func prefix(repository string, number int) string {
+	return fmt.Sprintf("pull-requests/%s/%d/", repository, number)
+}
+
+// IAM actions of Put and Get on the bucket objects, and List to find the
+// latest archive of a pull request
+func Actions() []string {
+	return []string{"s3:GetObject", "s3:PutObject", "s3:ListBucket"}
+}
+
+// built on first use, the archive is only written by closed pull requests
+var client = sync.OnceValue(func() *s3.S3 {
+	sess := session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	}))
+
+	region := env.GetEnv("REGION", "us-east-1")
+	config := &aws.Config{
+		Region: &region,
+	}
+	// e.g. a local S3 compatible server
+	if endpoint := env.GetEnv("S3_ENDPOINT", ""); endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+		config.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	return s3.New(sess, config)
+})
+
+func Put(document Document) error {
+	bucket := env.GetEnv("ARCHIVE_BUCKET", "")
+	key := Key(document.Repository, document.Number, document.Lifecycle.ClosedAt)
+
+	if dryrun.Enabled() {
+		dryrun.Log("s3.put_object", zap.String("bucket", bucket), zap.String("key", key), zap.Int("messages", len(document.Messages)))
+		return nil
+	}
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	_, err = client().PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	return err
+}
+
+// archived JSON document of the last close of a pull request
+func Get(repository string, number int) ([]byte, error) {
+	bucket := env.GetEnv("ARCHIVE_BUCKET", "")
+
+	key := ""
+	err := client().ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix(repository, number)),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			key = max(key, aws.StringValue(object.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, ErrNotFound
+	}
+
+	output, err := client().GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return io.ReadAll(output.Body)
+}
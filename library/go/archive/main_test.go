@@ -0,0 +1,49 @@
+package archive
+
+import (
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("ARCHIVE_BUCKET", "")
+	if Enabled() {
+		t.Errorf("Expected archiving to be off without a bucket")
+	}
+
+	t.Setenv("ARCHIVE_BUCKET", "archives")
+	if !Enabled() {
+		t.Errorf("Expected archiving to be on")
+	}
+}
+
+func TestKey(t *testing.T) {
+	if key := Key("api", 7, "2024-05-02T09:00:00Z"); key != "pull-requests/api/7/2024-05-02T09:00:00Z.json" {
+		t.Errorf("Expected the key of api#7, got %s", key)
+	}
+	if Key("api", 7, "2024-05-02T09:00:00Z") >= Key("api", 7, "2024-06-01T08:00:00Z") {
+		t.Errorf("Expected the keys of api#7 to sort by close time")
+	}
+}
+
+func TestPut(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to s3")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to s3")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	if err := Put(Document{Repository: "api", Number: 7}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
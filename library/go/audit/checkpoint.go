@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/types"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var loadCheckpoint = func(eventId string, step string) (*types.TableCheckpointData, error) {
+	return db.GetCheckpoint(db.DynamoDbConnection(), eventId, step)
+}
+
+var saveCheckpoint = func(item *types.TableCheckpointData) error {
+	return db.InsertCheckpoint(db.DynamoDbConnection(), item)
+}
+
+// checkpoint of a thread message, replies of one delivery differ by their text
+func threadStep(threadTimeStamp string, message string) string {
+	sum := sha256.Sum256([]byte(message))
+	return "thread:" + threadTimeStamp + ":" + hex.EncodeToString(sum[:8])
+}
+
+// sends a message once per event: a redelivery gets the timestamp the first
+// delivery posted under instead of a duplicate message. Messages without an
+// event id are always sent, unless the identical message was just posted to
+// the thread. The outcome and duration are added to the trail
+func (m Messenger) once(name string, step string, threadKey string, message string, send func() (string, error)) (string, bool, error) {
+	if timeStamp, ok := m.replayed(step); ok {
+		m.Trail.Step(name, StepReplayed, 0)
+		return timeStamp, true, nil
+	}
+
+	hash := messageHash(threadKey, message)
+	posted, ok := m.claim(hash)
+	if !ok {
+		m.Trail.Step(name, StepDuplicate, 0)
+		m.Trail.Skip("duplicate message")
+		return posted, true, nil
+	}
+
+	started := time.Now()
+	timeStamp, err := send()
+	m.claimed(hash, timeStamp, err)
+	if err != nil {
+		m.Trail.Step(name, StepFailed, time.Since(started))
+		return "", false, err
+	}
+	m.Trail.Step(name, StepDone, time.Since(started))
+
+	m.checkpoint(step, timeStamp)
+	return timeStamp, false, nil
+}
+
+// runs send once per event like the messages, for the deliveries that are not
+// Slack messages, e.g. the emails of a review request. A redelivery skips the
+// completed step
+func (m Messenger) Once(step string, send func() error) error {
+	if _, ok := m.replayed(step); ok {
+		m.Trail.Step(step, StepReplayed, 0)
+		return nil
+	}
+
+	started := time.Now()
+	if err := send(); err != nil {
+		m.Trail.Step(step, StepFailed, time.Since(started))
+		return err
+	}
+	m.Trail.Step(step, StepDone, time.Since(started))
+
+	m.checkpoint(step, "")
+	return nil
+}
+
+// a failed lookup sends the message again rather than losing it
+func (m Messenger) replayed(step string) (string, bool) {
+	if m.EventId == "" {
+		return "", false
+	}
+
+	checkpoint, err := loadCheckpoint(m.EventId, step)
+	if errors.Is(err, db.ErrNoDataFound) {
+		return "", false
+	}
+	if err != nil {
+		if m.Log != nil {
+			m.Log.Warn("error get checkpoint",
+				zap.String("eventId", m.EventId),
+				zap.String("step", step),
+				zap.Error(err),
+			)
+		}
+		return "", false
+	}
+	return checkpoint.SlackTimeStamp, true
+}
+
+// the message is already sent, a failed write only risks a duplicate on
+// redelivery
+func (m Messenger) checkpoint(step string, timeStamp string) {
+	if m.EventId == "" {
+		return
+	}
+
+	err := saveCheckpoint(&types.TableCheckpointData{
+		EventId:        m.EventId,
+		Step:           step,
+		SlackTimeStamp: timeStamp,
+		CompletedAt:    time.Now().Format(time.RFC3339),
+	})
+	if err != nil && m.Log != nil {
+		m.Log.Warn("error insert checkpoint",
+			zap.String("eventId", m.EventId),
+			zap.String("step", step),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errParentInFlight = errors.New("identical parent message being sent")
+
+var claimMessage = func(item *types.TableRecentMessageData, now int64) (*types.TableRecentMessageData, error) {
+	return db.ClaimMessage(db.DynamoDbConnection(), item, now)
+}
+
+var sentMessage = func(messageHash string, timeStamp string) error {
+	return db.UpdateMessageTimeStamp(db.DynamoDbConnection(), messageHash, timeStamp)
+}
+
+var releaseMessage = func(messageHash string) error {
+	return db.DeleteMessageClaim(db.DynamoDbConnection(), messageHash)
+}
+
+// DEDUP_WINDOW_SECONDS an identical message isn't posted again to the same
+// thread, 0 turns the check off
+func dedupWindow() int64 {
+	seconds, err := strconv.Atoi(env.GetEnv("DEDUP_WINDOW_SECONDS", "120"))
+	if err != nil || seconds < 0 {
+		seconds = 120
+	}
+	return int64(seconds)
+}
+
+// hash of the composed message and where it goes, threadKey is the thread
+// timestamp of a reply or the pull request of a parent message
+func messageHash(threadKey string, message string) string {
+	sum := sha256.Sum256([]byte(env.GetEnv("SLACK_CHANNEL", "") + "\n" + threadKey + "\n" + message))
+	return hex.EncodeToString(sum[:])
+}
+
+// whether the message may be sent, otherwise the timestamp of the identical
+// one, empty while it is still being sent. A failed claim sends the message
+// rather than losing it
+func (m Messenger) claim(messageHash string) (string, bool) {
+	window := dedupWindow()
+	if window == 0 {
+		return "", true
+	}
+
+	now := time.Now()
+	posted, err := claimMessage(&types.TableRecentMessageData{
+		MessageHash: messageHash,
+		PostedAt:    now.Format(time.RFC3339),
+		ExpiresAt:   now.Unix() + window,
+	}, now.Unix())
+	if errors.Is(err, db.ErrMessagePosted) {
+		return posted.SlackTimeStamp, false
+	}
+	if err != nil && m.Log != nil {
+		m.Log.Warn("error claim message",
+			zap.String("pullRequest", PullRequestKey(m.Repository, m.Number)),
+			zap.Error(err),
+		)
+	}
+	return "", true
+}
+
+// timestamp of the claimed message, or the claim released when it failed to
+// send so the next attempt isn't taken for a duplicate
+func (m Messenger) claimed(messageHash string, timeStamp string, sendErr error) {
+	if dedupWindow() == 0 {
+		return
+	}
+
+	var err error
+	if sendErr != nil {
+		err = releaseMessage(messageHash)
+	} else {
+		err = sentMessage(messageHash, timeStamp)
+	}
+	if err != nil && m.Log != nil {
+		m.Log.Warn("error update message claim",
+			zap.String("pullRequest", PullRequestKey(m.Repository, m.Number)),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,601 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/alert"
+	"slack-pr-lambda/config"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/github"
+	"slack-pr-lambda/i18n"
+	"slack-pr-lambda/mentions"
+	"slack-pr-lambda/notifier"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// longest text kept in an audit record
+const maxText = 300
+
+// parent message of a pull request changing none of the paths of the
+// repository config, it is only copied to the matching destinations
+var ErrNotRouted = errors.New("pull request not routed to SLACK_CHANNEL by its paths")
+
+// parent timestamp of a pull request tracked without a SLACK_CHANNEL parent
+// message, its thread messages are only copied to the routed destinations
+const Unrouted = "unrouted"
+
+// replaced in tests
+var insert = func(item *types.TableAuditData) error {
+	return db.InsertAudit(db.DynamoDbConnection(), item)
+}
+
+var listMutes = func(pullRequest string) (map[string]bool, error) {
+	return db.ListMutes(db.DynamoDbConnection(), pullRequest)
+}
+
+var deferMentions = func(item *types.TableDeferredMentionData) error {
+	return db.DeferMentions(db.DynamoDbConnection(), item)
+}
+
+var loadPolicy = mentions.Load
+
+var repoConfig = func(repository string) (config.RepoConfig, error) {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		return config.RepoConfig{}, err
+	}
+	return conf.Repo(repository), nil
+}
+
+var pullRequestFiles = github.GetPullRequestFiles
+
+var sendThread = slack.SlackSendMessageThread
+
+var sendThreadWithButtons = slack.SlackSendMessageThreadWithButtons
+
+var broadcastThread = slack.SlackBroadcastMessageThread
+
+var sendThreadWithImages = slack.SlackSendMessageThreadWithImages
+
+var alertNotInChannel = alert.NotInChannel
+
+var notify = func(destination config.Destination, text string) error {
+	n, err := notifier.New(destination)
+	if err != nil {
+		return err
+	}
+	return n.Notify(text)
+}
+
+// sends the Slack messages of one pull request, every sent message is written
+// to AUDIT_TABLE_NAME so "why did / didn't the bot post X" can be answered
+// from the records of the pull request. New messages are also posted to the
+// destinations of the repository config
+type Messenger struct {
+	// X-GitHub-Delivery of the webhook or the Slack trigger id, when there is one
+	EventId string
+	// webhook action, job or Slack interaction that sent the message
+	Source     string
+	Repository string
+	Number     int
+	Log        *zap.Logger
+	// collects the sent and skipped messages when set
+	Trail *Trail
+	// changed files routing the messages by paths, listed once for the messages
+	// sharing it, e.g. those of one event. Every message lists them when nil
+	Files *ChangedFiles
+	// re-posts the parent message deleted in Slack, returns the timestamp the
+	// reply is retried under
+	Resend func(timeStamp string) (string, error)
+	// renders the message in the language of each destination copy, the copies
+	// get the sent text when nil. See Localized
+	Localize func(language string) string
+}
+
+// messenger whose next messages are rendered by localize for the destinations,
+// e.g. out.Localized(render).SendMessageThread(timeStamp, render(language))
+func (m Messenger) Localized(localize func(language string) string) Messenger {
+	m.Localize = localize
+	return m
+}
+
+func PullRequestKey(repository string, number int) string {
+	return fmt.Sprintf("%s#%d", repository, number)
+}
+
+func (m Messenger) SendMessage(input types.OpenPullRequest, message string) (string, error) {
+	message, deferred := m.quiet(message)
+
+	timeStamp, err := slack.SlackSendMessage(input, message)
+	if err != nil {
+		m.notInChannel(err)
+		return "", err
+	}
+
+	m.record("parent", timeStamp, "", message)
+	m.deferMentions(timeStamp, deferred)
+	m.forward(message, false)
+	return timeStamp, nil
+}
+
+// parent message of a new pull request record, the audit entry is returned
+// instead of written so it is stored with the record, see
+// db.InsertItemAudited
+func (m Messenger) SendParentMessage(input types.OpenPullRequest, message string) (string, *types.TableAuditData, error) {
+	if !m.routed() {
+		m.Trail.Skip("paths not routed to SLACK_CHANNEL")
+		m.forward(message, false)
+		return "", nil, ErrNotRouted
+	}
+
+	message, deferred := m.quiet(message)
+
+	timeStamp, replayed, err := m.once("slack.parent", "parent", "parent:"+PullRequestKey(m.Repository, m.Number), message, func() (string, error) {
+		return slack.SlackSendMessage(input, message)
+	})
+	if err != nil {
+		m.notInChannel(err)
+		return "", nil, err
+	}
+	if replayed && timeStamp == "" {
+		return "", nil, errParentInFlight
+	}
+	if replayed {
+		return timeStamp, m.entry("parent", timeStamp, "", message), nil
+	}
+
+	m.Trail.post("parent", timeStamp, "")
+	m.deferMentions(timeStamp, deferred)
+	m.forward(message, false)
+	return timeStamp, m.entry("parent", timeStamp, "", message), nil
+}
+
+func (m Messenger) SendMessageThread(timeStamp string, message string) error {
+	_, err := m.Reply(timeStamp, message)
+	return err
+}
+
+// thread message whose timestamp is kept to edit it later, empty when the pull
+// request is muted. A redelivered event gets the reply of the first delivery
+func (m Messenger) Reply(timeStamp string, message string) (string, error) {
+	return m.reply(timeStamp, message, sendThread)
+}
+
+// thread message of an important event, e.g. merged, also shown in the channel
+// when the broadcastEvents of the repository, or BROADCAST_EVENTS for
+// SLACK_CHANNEL without them, list the event
+func (m Messenger) SendEventThread(event string, timeStamp string, message string) error {
+	send := sendThread
+	if m.broadcasts(event) {
+		send = broadcastThread
+	}
+	_, err := m.reply(timeStamp, message, send)
+	return err
+}
+
+func (m Messenger) broadcasts(event string) bool {
+	if m.Repository != "" {
+		repo, err := repoConfig(m.Repository)
+		if err == nil && repo.BroadcastEvents != nil {
+			return slices.Contains(repo.BroadcastEvents, event)
+		}
+	}
+	return slack.Broadcasts(env.GetEnv("SLACK_CHANNEL", ""), event)
+}
+
+// thread message with the images below its text, e.g. the screenshots of a
+// description
+func (m Messenger) SendImagesThread(timeStamp string, message string, images []types.Image) error {
+	_, err := m.reply(timeStamp, message, func(timeStamp string, message string) (string, error) {
+		return sendThreadWithImages(timeStamp, message, images)
+	})
+	return err
+}
+
+func (m Messenger) reply(timeStamp string, message string, send func(timeStamp string, message string) (string, error)) (string, error) {
+	if m.unrouted(timeStamp, message) {
+		return "", nil
+	}
+
+	step := threadStep(timeStamp, message)
+	message, ok := m.unmuted(timeStamp, message)
+	if !ok {
+		return "", nil
+	}
+	message, deferred := m.quiet(message)
+
+	reply, replayed, err := m.once("slack.thread", step, timeStamp, message, func() (string, error) {
+		reply, err := send(timeStamp, message)
+		if resent, ok := m.resend(timeStamp, err); ok {
+			timeStamp = resent
+			reply, err = send(timeStamp, message)
+		}
+		return reply, err
+	})
+	if err != nil {
+		m.notInChannel(err)
+		return "", err
+	}
+	if replayed {
+		return reply, nil
+	}
+
+	m.record("thread", reply, timeStamp, message)
+	m.deferMentions(timeStamp, deferred)
+	m.forward(message, true)
+	return reply, nil
+}
+
+func (m Messenger) SendMessageThreadWithButtons(timeStamp string, message string, buttons []slack.SlackButton) error {
+	if m.unrouted(timeStamp, message) {
+		return nil
+	}
+
+	step := threadStep(timeStamp, message)
+	message, ok := m.unmuted(timeStamp, message)
+	if !ok {
+		return nil
+	}
+	message, deferred := m.quiet(message)
+
+	reply, replayed, err := m.once("slack.thread", step, timeStamp, message, func() (string, error) {
+		reply, err := sendThreadWithButtons(timeStamp, message, buttons)
+		if resent, ok := m.resend(timeStamp, err); ok {
+			timeStamp = resent
+			reply, err = sendThreadWithButtons(timeStamp, message, buttons)
+		}
+		return reply, err
+	})
+	if err != nil {
+		m.notInChannel(err)
+		return err
+	}
+	if replayed {
+		return nil
+	}
+
+	m.record("buttons", reply, timeStamp, message)
+	m.deferMentions(timeStamp, deferred)
+	m.forward(message, true)
+	return nil
+}
+
+func (m Messenger) UpdateMessage(timeStamp string, message string) error {
+	if timeStamp == Unrouted {
+		m.Trail.Skip("paths not routed to SLACK_CHANNEL")
+		return nil
+	}
+
+	if err := slack.SlackUpdateMessage(timeStamp, message); err != nil {
+		return err
+	}
+
+	m.record("update", timeStamp, "", message)
+	return nil
+}
+
+// thread message of an Unrouted pull request, only copied to the destinations
+// once per event
+func (m Messenger) unrouted(timeStamp string, message string) bool {
+	if timeStamp != Unrouted {
+		return false
+	}
+
+	m.Trail.Skip("paths not routed to SLACK_CHANNEL")
+	m.Once(threadStep(timeStamp, message), func() error {
+		m.forward(message, true)
+		return nil
+	})
+	return true
+}
+
+// a reply failing on a deleted parent message is retried once under the
+// re-posted parent, the original error is kept when it can't be re-posted
+func (m Messenger) resend(timeStamp string, err error) (string, bool) {
+	if m.Resend == nil || !slack.ParentDeleted(err) {
+		return "", false
+	}
+
+	resent, err := m.Resend(timeStamp)
+	if err != nil {
+		if m.Log != nil {
+			m.Log.Warn("error resend parent message",
+				zap.String("pullRequest", PullRequestKey(m.Repository, m.Number)),
+				zap.String("timeStamp", timeStamp),
+				zap.Error(err),
+			)
+		}
+		return "", false
+	}
+	return resent, true
+}
+
+// ops alert for a channel the bot has to be invited to, the send error is
+// returned either way
+func (m Messenger) notInChannel(err error) {
+	var notInChannel *slack.NotInChannelError
+	if !errors.As(err, &notInChannel) {
+		return
+	}
+
+	if err := alertNotInChannel(notInChannel.Channel); err != nil && m.Log != nil {
+		m.Log.Warn("error send not in channel alert",
+			zap.String("channel", notInChannel.Channel),
+			zap.Error(err),
+		)
+	}
+}
+
+// thread messages of a pull request muted for the channel are dropped, muted
+// users are written as @<github login> so they are not notified. A failed
+// lookup lets the message through
+func (m Messenger) unmuted(timeStamp string, message string) (string, bool) {
+	if m.Repository == "" {
+		return message, true
+	}
+
+	mutes, err := listMutes(PullRequestKey(m.Repository, m.Number))
+	if err != nil {
+		if m.Log != nil {
+			m.Log.Warn("error list mutes",
+				zap.String("pullRequest", PullRequestKey(m.Repository, m.Number)),
+				zap.Error(err),
+			)
+		}
+		return message, true
+	}
+
+	if mutes[db.MuteChannel] {
+		m.record("muted", "", timeStamp, message)
+		m.Trail.Skip("thread muted")
+		return "", false
+	}
+
+	muted := []string{}
+	for slackUserId := range mutes {
+		muted = append(muted, slackUserId)
+	}
+	return mentions.Plain(message, muted), true
+}
+
+// mention policy of the message, a policy failing to load keeps what it could
+// read
+func (m Messenger) policy() mentions.Policy {
+	policy, err := loadPolicy()
+	if err != nil && m.Log != nil {
+		m.Log.Warn("error load mention policy",
+			zap.Error(err),
+		)
+	}
+	return policy
+}
+
+// mentions of a quiet channel or outside of the mention hours are posted as
+// plain names. Outside of the hours the mentions are returned to be made in
+// the thread once the next window starts, quiet channels never get them
+func (m Messenger) quiet(message string) (string, *types.TableDeferredMentionData) {
+	ids := mentions.Ids(message)
+	if len(ids) == 0 {
+		return message, nil
+	}
+
+	policy := m.policy()
+	channel := env.GetEnv("SLACK_CHANNEL", "")
+	now := time.Now()
+	if !policy.Quiet(channel, now) {
+		return message, nil
+	}
+
+	message = mentions.Plain(message, ids)
+	if policy.Channels[channel] || m.Repository == "" {
+		return message, nil
+	}
+	return message, &types.TableDeferredMentionData{
+		SlackUserIds: ids,
+		DueAt:        policy.NextWindow(now).Unix(),
+	}
+}
+
+// the message is already sent, a failed write only loses the later mention
+func (m Messenger) deferMentions(threadTimeStamp string, deferred *types.TableDeferredMentionData) {
+	if deferred == nil {
+		return
+	}
+
+	deferred.PullRequest = PullRequestKey(m.Repository, m.Number)
+	deferred.ThreadTimeStamp = threadTimeStamp
+	deferred.Repository = m.Repository
+	deferred.Number = m.Number
+	if err := deferMentions(deferred); err != nil && m.Log != nil {
+		m.Log.Warn("error defer mentions",
+			zap.String("pullRequest", deferred.PullRequest),
+			zap.Error(err),
+		)
+	}
+}
+
+// copy of a new message to the other destinations, replies are prefixed with
+// the pull request since they are not threaded there. Destinations with paths
+// only get pull requests changing a matching file. A failing destination is
+// only logged so it doesn't keep the message from the others
+func (m Messenger) forward(message string, reply bool) {
+	if m.Repository == "" {
+		return
+	}
+
+	repo, err := repoConfig(m.Repository)
+	if err != nil {
+		if m.Log != nil {
+			m.Log.Warn("error load destinations",
+				zap.String("repository", m.Repository),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+
+	files, routed := m.changedFiles(repo.Destinations)
+
+	var policy *mentions.Policy
+	for _, destination := range repo.Destinations {
+		if routed && !destination.Matches(files) {
+			continue
+		}
+
+		text := m.localized(message, destination)
+		if reply {
+			text = fmt.Sprintf("*%s* %s", PullRequestKey(m.Repository, m.Number), text)
+		}
+		if destination.Type == "slack" && len(mentions.Ids(text)) > 0 {
+			if policy == nil {
+				loaded := m.policy()
+				policy = &loaded
+			}
+			if policy.Quiet(destination.Channel, time.Now()) {
+				text = mentions.Plain(text, mentions.Ids(text))
+			}
+		}
+
+		if err := notify(destination, text); err != nil && m.Log != nil {
+			m.Log.Warn("error notify destination",
+				zap.String("pullRequest", PullRequestKey(m.Repository, m.Number)),
+				zap.String("destination", destination.Type),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// message in the language of the destination, the mentions written as plain
+// names in the sent message, e.g. of muted users, are plain in the copy too
+func (m Messenger) localized(message string, destination config.Destination) string {
+	if m.Localize == nil {
+		return message
+	}
+
+	language := destination.Language
+	if language == "" {
+		language = i18n.Language(destination.Channel)
+	}
+	text := m.Localize(language)
+
+	plain := []string{}
+	for _, id := range mentions.Ids(text) {
+		if !slices.Contains(mentions.Ids(message), id) {
+			plain = append(plain, id)
+		}
+	}
+	return mentions.Plain(text, plain)
+}
+
+// files of the pull request when a destination is routed by paths, a failed
+// lookup sends the message to every destination
+func (m Messenger) changedFiles(destinations []config.Destination) ([]string, bool) {
+	routed := false
+	for _, destination := range destinations {
+		routed = routed || len(destination.Paths) > 0
+	}
+	if !routed || m.Number == 0 {
+		return nil, false
+	}
+
+	files, err := m.Files.list(m.Repository, m.Number)
+	if err != nil {
+		if m.Log != nil {
+			m.Log.Warn("error list pull request files",
+				zap.String("pullRequest", PullRequestKey(m.Repository, m.Number)),
+				zap.Error(err),
+			)
+		}
+		return nil, false
+	}
+	return files, true
+}
+
+// whether the pull request changes a file of the repository paths, every pull
+// request when there are none or the files can't be listed. None when the
+// repository disabled SLACK_CHANNEL
+func (m Messenger) routed() bool {
+	if m.Repository == "" {
+		return true
+	}
+
+	repo, err := repoConfig(m.Repository)
+	if err != nil {
+		return true
+	}
+	if repo.DisableSlackChannel {
+		return false
+	}
+	if len(repo.Paths) == 0 {
+		return true
+	}
+
+	files, ok := m.changedFiles([]config.Destination{{Paths: repo.Paths}})
+	return !ok || repo.Matches(files)
+}
+
+// changed files of a pull request, listed on the first call
+type ChangedFiles struct {
+	once  sync.Once
+	files []string
+	err   error
+}
+
+func (c *ChangedFiles) list(repository string, number int) ([]string, error) {
+	if c == nil {
+		return pullRequestFiles(repository, number)
+	}
+
+	c.once.Do(func() {
+		c.files, c.err = pullRequestFiles(repository, number)
+	})
+	return c.files, c.err
+}
+
+func (m Messenger) record(messageType string, timeStamp string, threadTimeStamp string, message string) {
+	if messageType != "muted" {
+		m.Trail.post(messageType, timeStamp, threadTimeStamp)
+	}
+	m.Write(m.entry(messageType, timeStamp, threadTimeStamp, message))
+}
+
+func (m Messenger) entry(messageType string, timeStamp string, threadTimeStamp string, message string) *types.TableAuditData {
+	return &types.TableAuditData{
+		PullRequest:     PullRequestKey(m.Repository, m.Number),
+		SentAt:          time.Now().UTC().Format(types.SortableTime),
+		EventId:         m.EventId,
+		Source:          m.Source,
+		Type:            messageType,
+		Channel:         slack.Channel(),
+		TimeStamp:       timeStamp,
+		ThreadTimeStamp: threadTimeStamp,
+		Text:            truncate(message, maxText),
+	}
+}
+
+// the message is already sent, a failed audit write is only logged
+func (m Messenger) Write(item *types.TableAuditData) {
+	if err := insert(item); err != nil && m.Log != nil {
+		m.Log.Warn("error insert audit record",
+			zap.String("pullRequest", item.PullRequest),
+			zap.String("timeStamp", item.TimeStamp),
+			zap.Error(err),
+		)
+	}
+}
+
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "…"
+}
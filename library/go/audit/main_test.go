@@ -0,0 +1,934 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/config"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/mentions"
+	"slack-pr-lambda/slack"
+	"slack-pr-lambda/types"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func stubInsert(t *testing.T, err error) *[]types.TableAuditData {
+	records := []types.TableAuditData{}
+	original := insert
+	insert = func(item *types.TableAuditData) error {
+		records = append(records, *item)
+		return err
+	}
+	t.Cleanup(func() {
+		insert = original
+	})
+	return &records
+}
+
+func stubMutes(t *testing.T, mutes map[string]bool) {
+	original := listMutes
+	listMutes = func(pullRequest string) (map[string]bool, error) {
+		return mutes, nil
+	}
+	t.Cleanup(func() {
+		listMutes = original
+	})
+}
+
+// checkpoints kept in memory by "<event id> <step>"
+func stubCheckpoints(t *testing.T, err error) map[string]string {
+	saved := map[string]string{}
+	originalLoad := loadCheckpoint
+	originalSave := saveCheckpoint
+	loadCheckpoint = func(eventId string, step string) (*types.TableCheckpointData, error) {
+		if err != nil {
+			return nil, err
+		}
+		timeStamp, ok := saved[eventId+" "+step]
+		if !ok {
+			return nil, db.ErrNoDataFound
+		}
+		return &types.TableCheckpointData{EventId: eventId, Step: step, SlackTimeStamp: timeStamp}, nil
+	}
+	saveCheckpoint = func(item *types.TableCheckpointData) error {
+		saved[item.EventId+" "+item.Step] = item.SlackTimeStamp
+		return nil
+	}
+	t.Cleanup(func() {
+		loadCheckpoint = originalLoad
+		saveCheckpoint = originalSave
+	})
+	return saved
+}
+
+// recently posted messages kept in memory by hash, returns them
+func stubMessages(t *testing.T) map[string]string {
+	posted := map[string]string{}
+	originalClaim, originalSent, originalRelease := claimMessage, sentMessage, releaseMessage
+	claimMessage = func(item *types.TableRecentMessageData, now int64) (*types.TableRecentMessageData, error) {
+		if timeStamp, ok := posted[item.MessageHash]; ok {
+			return &types.TableRecentMessageData{MessageHash: item.MessageHash, SlackTimeStamp: timeStamp}, db.ErrMessagePosted
+		}
+		posted[item.MessageHash] = ""
+		return nil, nil
+	}
+	sentMessage = func(messageHash string, timeStamp string) error {
+		posted[messageHash] = timeStamp
+		return nil
+	}
+	releaseMessage = func(messageHash string) error {
+		delete(posted, messageHash)
+		return nil
+	}
+	t.Cleanup(func() {
+		claimMessage, sentMessage, releaseMessage = originalClaim, originalSent, originalRelease
+	})
+	return posted
+}
+
+// destinations of every repository, returns the notified "<type>: <text>"
+func stubDestinations(t *testing.T, destinations []config.Destination, err error) *[]string {
+	return stubRepo(t, config.RepoConfig{Destinations: destinations}, err)
+}
+
+// config of every repository, returns the notified "<type>: <text>"
+func stubRepo(t *testing.T, repo config.RepoConfig, err error) *[]string {
+	sent := []string{}
+	originalConfig := repoConfig
+	originalNotify := notify
+	repoConfig = func(repository string) (config.RepoConfig, error) {
+		return repo, nil
+	}
+	notify = func(destination config.Destination, text string) error {
+		sent = append(sent, destination.Type+": "+text)
+		return err
+	}
+	t.Cleanup(func() {
+		repoConfig = originalConfig
+		notify = originalNotify
+	})
+	return &sent
+}
+
+// changed files of every pull request, returns the number of lookups
+func stubFiles(t *testing.T, files []string, err error) *int {
+	lookups := 0
+	original := pullRequestFiles
+	pullRequestFiles = func(repo string, prNumber int) ([]string, error) {
+		lookups++
+		return files, err
+	}
+	t.Cleanup(func() {
+		pullRequestFiles = original
+	})
+	return &lookups
+}
+
+func TestMessenger(t *testing.T) {
+	records := stubInsert(t, nil)
+	stubMutes(t, nil)
+	stubCheckpoints(t, nil)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+	t.Setenv("SLACK_CHANNEL", "C1")
+
+	m := Messenger{EventId: "delivery-1", Source: "opened", Repository: "api", Number: 7}
+
+	timeStamp, err := m.SendMessage(types.OpenPullRequest{}, "opened new pull request")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := m.SendMessageThread(timeStamp, "pushed a change"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := m.UpdateMessage(timeStamp, "opened new pull request\nApprovals: 1/1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(*records) != 3 {
+		t.Fatalf("Expected 3 records, got %v", *records)
+	}
+
+	first := (*records)[0]
+	if first.PullRequest != "api#7" || first.EventId != "delivery-1" || first.Source != "opened" || first.Type != "parent" || first.Channel != "C1" {
+		t.Errorf("Unexpected record %+v", first)
+	}
+	if (*records)[1].Type != "thread" || (*records)[1].ThreadTimeStamp != timeStamp {
+		t.Errorf("Expected a thread record, got %+v", (*records)[1])
+	}
+	if (*records)[2].Type != "update" {
+		t.Errorf("Expected an update record, got %+v", (*records)[2])
+	}
+}
+
+func TestMessengerSendParentMessage(t *testing.T) {
+	records := stubInsert(t, nil)
+	stubCheckpoints(t, nil)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+	t.Setenv("SLACK_CHANNEL", "C1")
+
+	trail := &Trail{}
+	m := Messenger{EventId: "delivery-1", Source: "opened", Repository: "api", Number: 7, Trail: trail}
+
+	timeStamp, entry, err := m.SendParentMessage(types.OpenPullRequest{}, "opened new pull request")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// stored with the pull request record
+	if len(*records) != 0 {
+		t.Errorf("Expected no written record, got %+v", *records)
+	}
+	if entry.PullRequest != "api#7" || entry.Type != "parent" || entry.TimeStamp != timeStamp || entry.Channel != "C1" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if posted := trail.Posted(); len(posted) != 1 || posted[0].Type != "parent" {
+		t.Errorf("Expected the parent message on the trail, got %+v", trail.Posted())
+	}
+
+	m.Write(entry)
+	if len(*records) != 1 {
+		t.Errorf("Expected the written entry, got %+v", *records)
+	}
+
+	t.Run("sandbox", func(t *testing.T) {
+		t.Setenv("ENVIRONMENT", "staging")
+		t.Setenv("SANDBOX_CHANNEL", "CSANDBOX")
+
+		m := Messenger{EventId: "delivery-2", Source: "opened", Repository: "api", Number: 8}
+		if _, entry, err := m.SendParentMessage(types.OpenPullRequest{}, "opened new pull request"); err != nil || entry.Channel != "CSANDBOX" {
+			t.Errorf("Expected the sandbox channel recorded, got %+v %v", entry, err)
+		}
+	})
+}
+
+func TestMessengerInsertError(t *testing.T) {
+	stubInsert(t, errors.New("table not found"))
+	stubMutes(t, nil)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	m := Messenger{Source: "opened", Repository: "api", Number: 7, Log: zap.NewNop()}
+
+	// the message was sent, the audit failure does not surface
+	if err := m.SendMessageThread("1.000001", "hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestMessengerReply(t *testing.T) {
+	records := stubInsert(t, nil)
+	stubMutes(t, nil)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	m := Messenger{Source: "created", Repository: "api", Number: 7}
+
+	reply, err := m.Reply("1.000001", "left a review comment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "dry-run" {
+		t.Errorf("Expected the reply timestamp, got %s", reply)
+	}
+	if len(*records) != 1 || (*records)[0].ThreadTimeStamp != "1.000001" {
+		t.Errorf("Expected a thread record, got %+v", *records)
+	}
+}
+
+func TestMessengerSendEventThread(t *testing.T) {
+	stubInsert(t, nil)
+	stubMutes(t, nil)
+	stubDestinations(t, nil, nil)
+	t.Setenv("SLACK_CHANNEL", "C1")
+	t.Setenv("BROADCAST_EVENTS", "C1=merged")
+
+	sent := []string{}
+	originalThread, originalBroadcast := sendThread, broadcastThread
+	sendThread = func(timeStamp string, message string) (string, error) {
+		sent = append(sent, "thread: "+message)
+		return "2.000001", nil
+	}
+	broadcastThread = func(timeStamp string, message string) (string, error) {
+		sent = append(sent, "broadcast: "+message)
+		return "2.000002", nil
+	}
+	t.Cleanup(func() {
+		sendThread, broadcastThread = originalThread, originalBroadcast
+	})
+
+	m := Messenger{Source: "closed", Repository: "api", Number: 7}
+	if err := m.SendEventThread("merged", "1.000001", "merged the pull request"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SendEventThread("approved", "1.000001", "approved the pull request"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 2 || sent[0] != "broadcast: merged the pull request" || sent[1] != "thread: approved the pull request" {
+		t.Errorf("Expected only the merge to be broadcast, got %v", sent)
+	}
+
+	// the repository events replace BROADCAST_EVENTS, [] broadcasts none
+	sent = sent[:0]
+	stubRepo(t, config.RepoConfig{BroadcastEvents: []string{"approved"}}, nil)
+	m.SendEventThread("merged", "1.000001", "merged the pull request")
+	m.SendEventThread("approved", "1.000001", "approved the pull request")
+	stubRepo(t, config.RepoConfig{BroadcastEvents: []string{}}, nil)
+	m.SendEventThread("merged", "1.000001", "merged the pull request")
+
+	expected := "thread: merged the pull request, broadcast: approved the pull request, thread: merged the pull request"
+	if strings.Join(sent, ", ") != expected {
+		t.Errorf("Expected the repository broadcast events, got %v", sent)
+	}
+}
+
+func TestMessengerSendImagesThread(t *testing.T) {
+	stubInsert(t, nil)
+	stubMutes(t, nil)
+	stubDestinations(t, nil, nil)
+
+	var sent []types.Image
+	original := sendThreadWithImages
+	sendThreadWithImages = func(timeStamp string, message string, images []types.Image) (string, error) {
+		sent = images
+		return "2.000001", nil
+	}
+	t.Cleanup(func() {
+		sendThreadWithImages = original
+	})
+
+	m := Messenger{Source: "opened", Repository: "api", Number: 7}
+	images := []types.Image{{Url: "https://github.com/user-attachments/assets/1", Alt: "before"}}
+	if err := m.SendImagesThread("1.000001", "Images of the description", images); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0] != images[0] {
+		t.Errorf("Expected the images to be sent, got %v", sent)
+	}
+}
+
+func TestMessengerMuted(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	m := Messenger{Source: "created", Repository: "api", Number: 7}
+
+	t.Run("channel", func(t *testing.T) {
+		records := stubInsert(t, nil)
+		stubMutes(t, map[string]bool{"channel": true})
+
+		reply, err := m.Reply("1.000001", "left a review comment")
+		if err != nil || reply != "" {
+			t.Errorf("Expected the reply to be dropped, got %q %v", reply, err)
+		}
+		if len(*records) != 1 || (*records)[0].Type != "muted" || (*records)[0].TimeStamp != "" {
+			t.Errorf("Expected a muted record, got %+v", *records)
+		}
+	})
+
+	t.Run("user", func(t *testing.T) {
+		records := stubInsert(t, nil)
+		stubMutes(t, map[string]bool{"U1": true})
+
+		if err := m.SendMessageThread("1.000001", "<@U1> <@U2> please review"); err != nil {
+			t.Fatal(err)
+		}
+		if len(*records) != 1 || (*records)[0].Text != "@U1 <@U2> please review" {
+			t.Errorf("Expected the mention of U1 to be removed, got %+v", *records)
+		}
+	})
+}
+
+func TestMessengerTrail(t *testing.T) {
+	stubInsert(t, nil)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	m := Messenger{Source: "created", Repository: "api", Number: 7, Trail: &Trail{}}
+
+	stubMutes(t, nil)
+	if err := m.SendMessageThread("1.000001", "pushed a change"); err != nil {
+		t.Fatal(err)
+	}
+	stubMutes(t, map[string]bool{"channel": true})
+	if err := m.SendMessageThread("1.000001", "pushed a change"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Posted{{Type: "thread", TimeStamp: "dry-run", ThreadTimeStamp: "1.000001"}}
+	if posted := m.Trail.Posted(); len(posted) != 1 || posted[0] != expected[0] {
+		t.Errorf("Expected %+v, got %+v", expected, posted)
+	}
+	if skipped := m.Trail.Skipped(); len(skipped) != 1 || skipped[0] != "thread muted" {
+		t.Errorf("Expected the muted reply to be skipped, got %v", skipped)
+	}
+}
+
+func TestMessengerCheckpoint(t *testing.T) {
+	records := stubInsert(t, nil)
+	stubMutes(t, nil)
+	saved := stubCheckpoints(t, nil)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	first := Messenger{EventId: "delivery-1", Source: "opened", Repository: "api", Number: 7, Trail: &Trail{}}
+	if _, _, err := first.SendParentMessage(types.OpenPullRequest{}, "opened new pull request"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.Reply("1.000001", "please review"); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 2 || saved["delivery-1 parent"] != "dry-run" {
+		t.Errorf("Expected the parent and the reply checkpointed, got %v", saved)
+	}
+
+	// GitHub redelivers the event, nothing is posted twice
+	saved["delivery-1 parent"] = "1.000001"
+	redelivery := Messenger{EventId: "delivery-1", Source: "opened", Repository: "api", Number: 7, Trail: &Trail{}}
+	timeStamp, entry, err := redelivery.SendParentMessage(types.OpenPullRequest{}, "opened new pull request")
+	if err != nil || timeStamp != "1.000001" || entry.TimeStamp != "1.000001" {
+		t.Errorf("Expected the first parent message, got %s %+v %v", timeStamp, entry, err)
+	}
+	if _, err := redelivery.Reply("1.000001", "please review"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := redelivery.Reply("1.000001", "pushed a change"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*records) != 2 {
+		t.Errorf("Expected the first reply and the new one recorded, got %+v", *records)
+	}
+	if posted := redelivery.Trail.Posted(); len(posted) != 1 {
+		t.Errorf("Expected only the new reply posted, got %+v", posted)
+	}
+	expected := []string{"slack.parent replayed", "slack.thread replayed", "slack.thread done"}
+	steps := redelivery.Trail.Steps()
+	for i, step := range steps {
+		if i >= len(expected) || step.Name+" "+step.Outcome != expected[i] {
+			t.Errorf("Expected steps %v, got %+v", expected, steps)
+			break
+		}
+	}
+	if redelivery.Trail.Partial() {
+		t.Errorf("Expected no failed step")
+	}
+}
+
+func TestMessengerCheckpointError(t *testing.T) {
+	stubInsert(t, nil)
+	stubMutes(t, nil)
+	stubCheckpoints(t, errors.New("throttled"))
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	m := Messenger{EventId: "delivery-1", Source: "created", Repository: "api", Number: 7, Log: zap.NewNop(), Trail: &Trail{}}
+
+	// sent rather than lost
+	reply, err := m.Reply("1.000001", "left a review comment")
+	if err != nil || reply != "dry-run" {
+		t.Errorf("Expected the reply to be sent, got %q %v", reply, err)
+	}
+}
+
+func TestMessengerOnce(t *testing.T) {
+	stubCheckpoints(t, nil)
+
+	m := Messenger{EventId: "delivery-1", Trail: &Trail{}}
+	sent := 0
+	send := func() error {
+		sent++
+		return nil
+	}
+
+	for delivery := 0; delivery < 2; delivery++ {
+		if err := m.Once("email:alice", send); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if sent != 1 {
+		t.Errorf("Expected the redelivery to skip the step, sent %d times", sent)
+	}
+
+	if err := m.Once("email:bob", func() error { return errors.New("ses down") }); err == nil {
+		t.Errorf("Expected the error")
+	}
+	if err := m.Once("email:bob", send); err != nil || sent != 2 {
+		t.Errorf("Expected the failed step to run again, sent %d times %v", sent, err)
+	}
+}
+
+func TestThreadStep(t *testing.T) {
+	if threadStep("1.000001", "a") == threadStep("1.000001", "b") {
+		t.Errorf("Expected replies with different texts to differ")
+	}
+	if threadStep("1.000001", "a") == threadStep("2.000001", "a") {
+		t.Errorf("Expected replies of different threads to differ")
+	}
+	if !strings.HasPrefix(threadStep("1.000001", "a"), "thread:1.000001:") {
+		t.Errorf("Unexpected step %s", threadStep("1.000001", "a"))
+	}
+}
+
+func TestMessengerDuplicate(t *testing.T) {
+	records := stubInsert(t, nil)
+	stubMutes(t, nil)
+	stubCheckpoints(t, nil)
+	posted := stubMessages(t)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	// two deliveries of the same change, e.g. a queue retry
+	first := Messenger{EventId: "delivery-1", Source: "synchronize", Repository: "api", Number: 7, Trail: &Trail{}}
+	second := Messenger{EventId: "delivery-2", Source: "synchronize", Repository: "api", Number: 7, Trail: &Trail{}}
+
+	if _, err := first.Reply("1.000001", "pushed a change"); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := second.Reply("1.000001", "pushed a change")
+	if err != nil || reply != "dry-run" {
+		t.Errorf("Expected the first reply, got %q %v", reply, err)
+	}
+	if len(*records) != 1 || len(posted) != 1 {
+		t.Errorf("Expected a single reply, got %+v", *records)
+	}
+	if skipped := second.Trail.Skipped(); len(skipped) != 1 || skipped[0] != "duplicate message" {
+		t.Errorf("Expected the duplicate to be skipped, got %v", skipped)
+	}
+	if steps := second.Trail.Steps(); len(steps) != 1 || steps[0].Outcome != StepDuplicate {
+		t.Errorf("Expected a duplicate step, got %+v", steps)
+	}
+
+	// another thread or text is posted
+	if _, err := second.Reply("2.000001", "pushed a change"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Reply("1.000001", "pushed another change"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*records) != 3 {
+		t.Errorf("Expected 3 replies, got %+v", *records)
+	}
+
+	t.Run("parent in flight", func(t *testing.T) {
+		posted[messageHash("parent:api#8", "opened new pull request")] = ""
+		m := Messenger{EventId: "delivery-3", Source: "opened", Repository: "api", Number: 8}
+		if _, _, err := m.SendParentMessage(types.OpenPullRequest{}, "opened new pull request"); !errors.Is(err, errParentInFlight) {
+			t.Errorf("Expected errParentInFlight, got %v", err)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("DEDUP_WINDOW_SECONDS", "0")
+		if _, err := second.Reply("1.000001", "pushed a change"); err != nil {
+			t.Fatal(err)
+		}
+		if len(*records) != 4 {
+			t.Errorf("Expected the reply to be posted again, got %+v", *records)
+		}
+	})
+}
+
+func TestDedupWindow(t *testing.T) {
+	t.Setenv("DEDUP_WINDOW_SECONDS", "")
+	if window := dedupWindow(); window != 120 {
+		t.Errorf("Expected 120, got %d", window)
+	}
+	t.Setenv("DEDUP_WINDOW_SECONDS", "abc")
+	if window := dedupWindow(); window != 120 {
+		t.Errorf("Expected 120, got %d", window)
+	}
+	t.Setenv("DEDUP_WINDOW_SECONDS", "0")
+	if window := dedupWindow(); window != 0 {
+		t.Errorf("Expected 0, got %d", window)
+	}
+}
+
+func TestMessengerForward(t *testing.T) {
+	stubInsert(t, nil)
+	stubMutes(t, nil)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	m := Messenger{Source: "opened", Repository: "api", Number: 7, Log: zap.NewNop()}
+
+	t.Run("destinations", func(t *testing.T) {
+		sent := stubDestinations(t, []config.Destination{{Type: "teams"}, {Type: "discord"}}, nil)
+
+		timeStamp, err := m.SendMessage(types.OpenPullRequest{}, "opened new pull request")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.SendMessageThread(timeStamp, "pushed a change"); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.UpdateMessage(timeStamp, "opened new pull request\nApprovals: 1/1"); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{
+			"teams: opened new pull request",
+			"discord: opened new pull request",
+			"teams: *api#7* pushed a change",
+			"discord: *api#7* pushed a change",
+		}
+		if strings.Join(*sent, "|") != strings.Join(expected, "|") {
+			t.Errorf("Expected %v, got %v", expected, *sent)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		sent := stubDestinations(t, []config.Destination{{Type: "teams"}}, errors.New("webhook returned 400"))
+
+		// Slack has the message, the failed copy does not surface
+		if err := m.SendMessageThread("1.000001", "pushed a change"); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if len(*sent) != 1 {
+			t.Errorf("Expected 1 notification, got %v", *sent)
+		}
+	})
+
+	t.Run("localized", func(t *testing.T) {
+		t.Setenv("CHANNEL_LANGUAGES", "CFR=fr")
+		stubMutes(t, map[string]bool{"U2": true})
+		sent := stubDestinations(t, []config.Destination{{Type: "slack", Channel: "CFR"}, {Type: "teams", Language: "ja"}, {Type: "discord"}}, nil)
+
+		render := func(language string) string {
+			return fmt.Sprintf("%s: <@U1> <@U2>", language)
+		}
+		if err := m.Localized(render).SendMessageThread("1.000001", render("en")); err != nil {
+			t.Fatal(err)
+		}
+
+		// the muted user is plain in every copy
+		expected := []string{
+			"slack: *api#7* fr: <@U1> @U2",
+			"teams: *api#7* ja: <@U1> @U2",
+			"discord: *api#7* en: <@U1> @U2",
+		}
+		if strings.Join(*sent, "|") != strings.Join(expected, "|") {
+			t.Errorf("Expected %v, got %v", expected, *sent)
+		}
+	})
+
+	t.Run("paths", func(t *testing.T) {
+		sent := stubDestinations(t, []config.Destination{{Type: "slack", Channel: "CFRONT", Paths: []string{"web"}}, {Type: "slack", Channel: "CBACK", Paths: []string{"api"}}, {Type: "teams"}}, nil)
+		lookups := stubFiles(t, []string{"web/src/app.ts", "README.md"}, nil)
+
+		if err := m.SendMessageThread("1.000001", "pushed a change"); err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"slack: *api#7* pushed a change", "teams: *api#7* pushed a change"}
+		if strings.Join(*sent, "|") != strings.Join(expected, "|") || *lookups != 1 {
+			t.Errorf("Expected the frontend channel and teams after 1 lookup, got %v after %d", *sent, *lookups)
+		}
+	})
+
+	t.Run("paths lookup failure", func(t *testing.T) {
+		sent := stubDestinations(t, []config.Destination{{Type: "slack", Channel: "CFRONT", Paths: []string{"web"}}, {Type: "slack", Channel: "CBACK", Paths: []string{"api"}}}, nil)
+		stubFiles(t, nil, errors.New("rate limited"))
+
+		if err := m.SendMessageThread("1.000001", "pushed a change"); err != nil {
+			t.Fatal(err)
+		}
+		if len(*sent) != 2 {
+			t.Errorf("Expected every destination without the files, got %v", *sent)
+		}
+	})
+
+	t.Run("paths listed once", func(t *testing.T) {
+		stubDestinations(t, []config.Destination{{Type: "slack", Channel: "CFRONT", Paths: []string{"web"}}}, nil)
+		lookups := stubFiles(t, []string{"web/src/app.ts"}, nil)
+
+		shared := m
+		shared.Files = &ChangedFiles{}
+		for _, message := range []string{"pushed a change", "approved"} {
+			if err := shared.SendMessageThread("1.000001", message); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if *lookups != 1 {
+			t.Errorf("Expected 1 lookup for the event, got %d", *lookups)
+		}
+	})
+
+	t.Run("primary paths", func(t *testing.T) {
+		stubMessages(t)
+		stubCheckpoints(t, nil)
+		sent := stubRepo(t, config.RepoConfig{Paths: []string{"api"}, Destinations: []config.Destination{{Type: "slack", Channel: "CFRONT", Paths: []string{"web"}}}}, nil)
+		stubFiles(t, []string{"web/src/app.ts"}, nil)
+
+		trail := &Trail{}
+		routed := m
+		routed.Trail = trail
+		timeStamp, entry, err := routed.SendParentMessage(types.OpenPullRequest{}, "opened new pull request")
+		if !errors.Is(err, ErrNotRouted) || timeStamp != "" || entry != nil {
+			t.Errorf("Expected ErrNotRouted, got %q %v", timeStamp, err)
+		}
+		if strings.Join(*sent, "|") != "slack: opened new pull request" {
+			t.Errorf("Expected the frontend channel only, got %v", *sent)
+		}
+		if skipped := trail.Skipped(); len(skipped) != 1 || skipped[0] != "paths not routed to SLACK_CHANNEL" {
+			t.Errorf("Expected the parent to be skipped, got %v", skipped)
+		}
+
+		stubFiles(t, []string{"api/main.go"}, nil)
+		if _, _, err := routed.SendParentMessage(types.OpenPullRequest{}, "opened new pull request"); err != nil {
+			t.Errorf("Expected the parent to be posted, got %v", err)
+		}
+	})
+
+	t.Run("slack channel disabled", func(t *testing.T) {
+		stubMessages(t)
+		stubCheckpoints(t, nil)
+		sent := stubRepo(t, config.RepoConfig{DisableSlackChannel: true, Destinations: []config.Destination{{Type: "teams"}}}, nil)
+
+		timeStamp, _, err := m.SendParentMessage(types.OpenPullRequest{}, "opened new pull request")
+		if !errors.Is(err, ErrNotRouted) || timeStamp != "" {
+			t.Errorf("Expected ErrNotRouted, got %q %v", timeStamp, err)
+		}
+		if strings.Join(*sent, "|") != "teams: opened new pull request" {
+			t.Errorf("Expected the destination only, got %v", *sent)
+		}
+	})
+
+	t.Run("unrouted", func(t *testing.T) {
+		stubCheckpoints(t, nil)
+		sent := stubDestinations(t, []config.Destination{{Type: "teams"}}, nil)
+
+		trail := &Trail{}
+		unrouted := m
+		unrouted.EventId = "delivery-1"
+		unrouted.Trail = trail
+		// a redelivery doesn't copy the message again
+		for delivery := 0; delivery < 2; delivery++ {
+			if err := unrouted.SendMessageThread(Unrouted, "approved"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := unrouted.UpdateMessage(Unrouted, "opened new pull request\nApprovals: 1/1"); err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Join(*sent, "|") != "teams: *api#7* approved" {
+			t.Errorf("Expected the destination copy only, got %v", *sent)
+		}
+		if posted := trail.Posted(); len(posted) != 0 {
+			t.Errorf("Expected nothing posted to SLACK_CHANNEL, got %v", posted)
+		}
+	})
+
+	t.Run("muted", func(t *testing.T) {
+		sent := stubDestinations(t, []config.Destination{{Type: "teams"}}, nil)
+		stubMutes(t, map[string]bool{"channel": true})
+
+		if err := m.SendMessageThread("1.000001", "pushed a change"); err != nil {
+			t.Fatal(err)
+		}
+		if len(*sent) != 0 {
+			t.Errorf("Expected muted messages not to be forwarded, got %v", *sent)
+		}
+	})
+}
+
+// mention policy of every message, returns the deferred mentions
+func stubPolicy(t *testing.T, policy mentions.Policy) *[]types.TableDeferredMentionData {
+	deferred := []types.TableDeferredMentionData{}
+	originalPolicy := loadPolicy
+	originalDefer := deferMentions
+	loadPolicy = func() (mentions.Policy, error) {
+		return policy, nil
+	}
+	deferMentions = func(item *types.TableDeferredMentionData) error {
+		deferred = append(deferred, *item)
+		return nil
+	}
+	t.Cleanup(func() {
+		loadPolicy = originalPolicy
+		deferMentions = originalDefer
+	})
+	return &deferred
+}
+
+func TestMessengerQuiet(t *testing.T) {
+	stubMutes(t, nil)
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+	t.Setenv("SLACK_CHANNEL", "C1")
+
+	m := Messenger{Source: "review_requested", Repository: "api", Number: 7, Log: zap.NewNop()}
+
+	t.Run("within the hours", func(t *testing.T) {
+		records := stubInsert(t, nil)
+		deferred := stubPolicy(t, mentions.Policy{})
+
+		if err := m.SendMessageThread("1.000001", "Please review: <@U1> :eyes:"); err != nil {
+			t.Fatal(err)
+		}
+		if (*records)[0].Text != "Please review: <@U1> :eyes:" || len(*deferred) != 0 {
+			t.Errorf("Expected the mention to notify, got %v %v", *records, *deferred)
+		}
+	})
+
+	t.Run("after hours", func(t *testing.T) {
+		records := stubInsert(t, nil)
+		// a window nobody is ever in
+		deferred := stubPolicy(t, mentions.Policy{Start: time.Minute, End: 2 * time.Minute})
+
+		if err := m.SendMessageThread("1.000001", "Please review: <@U1> :eyes:"); err != nil {
+			t.Fatal(err)
+		}
+		if (*records)[0].Text != "Please review: @U1 :eyes:" {
+			t.Errorf("Expected a plain name, got %q", (*records)[0].Text)
+		}
+		if len(*deferred) != 1 || (*deferred)[0].PullRequest != "api#7" || (*deferred)[0].ThreadTimeStamp != "1.000001" || (*deferred)[0].SlackUserIds[0] != "U1" {
+			t.Errorf("Expected the mention to be deferred, got %v", *deferred)
+		}
+	})
+
+	t.Run("quiet channel", func(t *testing.T) {
+		records := stubInsert(t, nil)
+		deferred := stubPolicy(t, mentions.Policy{Channels: map[string]bool{"C1": true}})
+
+		if err := m.SendMessageThread("1.000001", "Please review: <@U1> :eyes:"); err != nil {
+			t.Fatal(err)
+		}
+		if (*records)[0].Text != "Please review: @U1 :eyes:" || len(*deferred) != 0 {
+			t.Errorf("Expected a plain name that is never mentioned, got %v %v", *records, *deferred)
+		}
+	})
+
+	t.Run("quiet destination", func(t *testing.T) {
+		stubInsert(t, nil)
+		stubPolicy(t, mentions.Policy{Channels: map[string]bool{"C2": true}})
+		sent := stubDestinations(t, []config.Destination{{Type: "slack", Channel: "C2"}, {Type: "slack", Channel: "C3"}}, nil)
+
+		if _, err := m.SendMessage(types.OpenPullRequest{}, "<@U1> opened new pull request"); err != nil {
+			t.Fatal(err)
+		}
+		if len(*sent) != 2 || (*sent)[0] != "slack: @U1 opened new pull request" || (*sent)[1] != "slack: <@U1> opened new pull request" {
+			t.Errorf("Expected the mention to be plain in C2 only, got %v", *sent)
+		}
+	})
+}
+
+// replies under the deleted parent "1.000001" fail, returns the thread of
+// every reply
+func stubDeletedParent(t *testing.T) *[]string {
+	threads := []string{}
+	original := sendThread
+	sendThread = func(timeStamp string, message string) (string, error) {
+		threads = append(threads, timeStamp)
+		if timeStamp == "1.000001" {
+			return "", errors.New("thread_not_found")
+		}
+		return "2.000001", nil
+	}
+	t.Cleanup(func() {
+		sendThread = original
+	})
+	return &threads
+}
+
+func TestMessengerResend(t *testing.T) {
+	stubMutes(t, nil)
+	stubDestinations(t, nil, nil)
+
+	t.Run("resent", func(t *testing.T) {
+		records := stubInsert(t, nil)
+		threads := stubDeletedParent(t)
+
+		m := Messenger{Source: "created", Repository: "api", Number: 7, Resend: func(timeStamp string) (string, error) {
+			return "1.000009", nil
+		}}
+		reply, err := m.Reply("1.000001", "left a review comment")
+		if err != nil || reply != "2.000001" {
+			t.Fatalf("Expected the reply to be retried, got %q %v", reply, err)
+		}
+		if len(*threads) != 2 || (*threads)[1] != "1.000009" {
+			t.Errorf("Expected the retry under the new parent, got %v", *threads)
+		}
+		if len(*records) != 1 || (*records)[0].ThreadTimeStamp != "1.000009" {
+			t.Errorf("Expected the reply recorded under the new parent, got %+v", *records)
+		}
+	})
+
+	t.Run("without hook", func(t *testing.T) {
+		stubInsert(t, nil)
+		threads := stubDeletedParent(t)
+
+		m := Messenger{Source: "created", Repository: "api", Number: 7}
+		if _, err := m.Reply("1.000001", "left a review comment"); err == nil || err.Error() != "thread_not_found" {
+			t.Errorf("Expected the Slack error, got %v", err)
+		}
+		if len(*threads) != 1 {
+			t.Errorf("Expected no retry, got %v", *threads)
+		}
+	})
+
+	t.Run("resend failed", func(t *testing.T) {
+		stubInsert(t, nil)
+		threads := stubDeletedParent(t)
+
+		m := Messenger{Source: "created", Repository: "api", Number: 7, Log: zap.NewNop(), Resend: func(timeStamp string) (string, error) {
+			return "", errors.New("no parent message")
+		}}
+		if _, err := m.Reply("1.000001", "left a review comment"); err == nil || err.Error() != "thread_not_found" {
+			t.Errorf("Expected the Slack error, got %v", err)
+		}
+		if len(*threads) != 1 {
+			t.Errorf("Expected no retry, got %v", *threads)
+		}
+	})
+}
+
+func TestMessengerNotInChannel(t *testing.T) {
+	stubMutes(t, nil)
+	stubInsert(t, nil)
+
+	alerted := []string{}
+	originalAlert := alertNotInChannel
+	originalSend := sendThread
+	alertNotInChannel = func(channel string) error {
+		alerted = append(alerted, channel)
+		return nil
+	}
+	sendThread = func(timeStamp string, message string) (string, error) {
+		if timeStamp == "1.000001" {
+			return "", &slack.NotInChannelError{Channel: "C-PRS", Err: errors.New("method_not_supported_for_channel_type")}
+		}
+		return "", errors.New("rate_limited")
+	}
+	t.Cleanup(func() {
+		alertNotInChannel = originalAlert
+		sendThread = originalSend
+	})
+
+	m := Messenger{Source: "created", Repository: "api", Number: 7}
+	if _, err := m.Reply("1.000001", "left a review comment"); err == nil {
+		t.Errorf("Expected the send error")
+	}
+	if _, err := m.Reply("1.000002", "left a review comment"); err == nil {
+		t.Errorf("Expected the send error")
+	}
+	if len(alerted) != 1 || alerted[0] != "C-PRS" {
+		t.Errorf("Expected one alert naming the channel, got %v", alerted)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if result := truncate("hello", 10); result != "hello" {
+		t.Errorf("Expected the text unchanged, got %s", result)
+	}
+
+	result := truncate(strings.Repeat("é", 20), 10)
+	if result != strings.Repeat("é", 10)+"…" {
+		t.Errorf("Expected 10 runes, got %s", result)
+	}
+}
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"sync"
+	"time"
+)
+
+// Slack message sent while handling one event
+type Posted struct {
+	Type            string `json:"type"`
+	TimeStamp       string `json:"ts"`
+	ThreadTimeStamp string `json:"threadTs,omitempty"`
+}
+
+// outcomes of a step
+const (
+	StepDone = "done"
+	// completed by an earlier delivery of the event, not done again
+	StepReplayed = "replayed"
+	// identical to a message just posted to the thread, not sent again
+	StepDuplicate = "duplicate"
+	StepFailed    = "failed"
+)
+
+// Slack send or dynamodb call made while handling one event, e.g.
+// "slack.parent" or "dynamo.read"
+type Step struct {
+	Name       string `json:"name"`
+	Outcome    string `json:"outcome"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// messages sent, steps run and steps skipped while handling one event, safe
+// for concurrent use since thread messages may be sent in parallel
+type Trail struct {
+	mu      sync.Mutex
+	posted  []Posted
+	steps   []Step
+	skipped []string
+}
+
+func (t *Trail) post(messageType string, timeStamp string, threadTimeStamp string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.posted = append(t.posted, Posted{Type: messageType, TimeStamp: timeStamp, ThreadTimeStamp: threadTimeStamp})
+}
+
+// outcome of a step that took the duration
+func (t *Trail) Step(name string, outcome string, took time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.steps = append(t.steps, Step{Name: name, Outcome: outcome, DurationMs: took.Milliseconds()})
+}
+
+// step not performed and why, e.g. "not tracked"
+func (t *Trail) Skip(reason string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.skipped = append(t.skipped, reason)
+}
+
+func (t *Trail) Posted() []Posted {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Posted{}, t.posted...)
+}
+
+func (t *Trail) Skipped() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string{}, t.skipped...)
+}
+
+func (t *Trail) Steps() []Step {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Step{}, t.steps...)
+}
+
+// whether a step failed, the earlier ones completed and are checkpointed
+func (t *Trail) Partial() bool {
+	for _, step := range t.Steps() {
+		if step.Outcome == StepFailed {
+			return true
+		}
+	}
+	return false
+}
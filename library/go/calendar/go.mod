@@ -0,0 +1,3 @@
+module slack-pr-lambda/calendar
+
+go 1.22
@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func fetchICS(url string) ([]string, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("holiday calendar returned %d", resp.StatusCode)
+	}
+	return parseICS(resp.Body)
+}
+
+// "YYYY-MM-DD" days of the VEVENTs, all day events span DTSTART until the
+// excluded DTEND
+func parseICS(r io.Reader) ([]string, error) {
+	days := []string{}
+	var start, end time.Time
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// DTSTART;VALUE=DATE:20241225 or DTSTART:20241225T000000Z
+		name, _, _ = strings.Cut(name, ";")
+
+		switch name {
+		case "BEGIN":
+			if value == "VEVENT" {
+				start, end = time.Time{}, time.Time{}
+			}
+		case "DTSTART":
+			start = parseICSDate(value)
+		case "DTEND":
+			end = parseICSDate(value)
+		case "END":
+			if value != "VEVENT" || start.IsZero() {
+				continue
+			}
+			days = append(days, start.Format(time.DateOnly))
+			for day := start.AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+				days = append(days, day.Format(time.DateOnly))
+			}
+		}
+	}
+
+	return days, scanner.Err()
+}
+
+func parseICSDate(value string) time.Time {
+	if len(value) < 8 {
+		return time.Time{}
+	}
+	day, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}
+	}
+	return day
+}
@@ -0,0 +1,60 @@
+package calendar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const holidays = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+DTSTART;VALUE=DATE:20241225
+DTEND;VALUE=DATE:20241227
+SUMMARY:Christmas
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20250101T000000Z
+SUMMARY:New Year
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestParseICS(t *testing.T) {
+	days, err := parseICS(strings.NewReader(holidays))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"2024-12-25", "2024-12-26", "2025-01-01"}
+	if !reflect.DeepEqual(days, expected) {
+		t.Errorf("Expected %v, got %v", expected, days)
+	}
+}
+
+func TestLoadICS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/holidays.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(holidays))
+	}))
+	defer server.Close()
+
+	t.Setenv("HOLIDAY_CALENDAR_URL", server.URL+"/holidays.ics")
+	cal, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cal.Holidays["2024-12-26"] || !cal.Holidays["2025-01-01"] {
+		t.Errorf("Unexpected holidays %v", cal.Holidays)
+	}
+
+	t.Setenv("HOLIDAY_CALENDAR_URL", server.URL+"/missing.ics")
+	if _, err := Load(); err == nil {
+		t.Errorf("Expected error for a missing calendar")
+	}
+}
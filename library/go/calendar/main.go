@@ -0,0 +1,92 @@
+package calendar
+
+import (
+	"slack-pr-lambda/env"
+	"strings"
+	"time"
+)
+
+// working days of the team, reminders and SLA timers only run on those
+type Calendar struct {
+	// Saturdays and Sundays are not working days
+	Weekends bool
+	// "YYYY-MM-DD" public holidays
+	Holidays map[string]bool
+	Location *time.Location
+}
+
+// PAUSE_WEEKENDS, the static HOLIDAYS ("2024-12-25,2024-12-26") and the
+// HOLIDAY_CALENDAR_URL ICS feed, in CALENDAR_TIMEZONE (default UTC). The
+// returned calendar is usable without the feed when it fails to load
+func Load() (Calendar, error) {
+	cal := Calendar{
+		Weekends: env.GetEnv("PAUSE_WEEKENDS", "false") == "true",
+		Holidays: map[string]bool{},
+		Location: time.UTC,
+	}
+
+	if name := env.GetEnv("CALENDAR_TIMEZONE", ""); name != "" {
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return cal, err
+		}
+		cal.Location = location
+	}
+
+	for _, day := range strings.Split(env.GetEnv("HOLIDAYS", ""), ",") {
+		if day = strings.TrimSpace(day); day != "" {
+			cal.Holidays[day] = true
+		}
+	}
+
+	if url := env.GetEnv("HOLIDAY_CALENDAR_URL", ""); url != "" {
+		days, err := fetchICS(url)
+		if err != nil {
+			return cal, err
+		}
+		for _, day := range days {
+			cal.Holidays[day] = true
+		}
+	}
+
+	return cal, nil
+}
+
+// whether t falls on a working day of the calendar
+func (c Calendar) Working(t time.Time) bool {
+	local := t.In(c.location())
+	if c.Weekends && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+		return false
+	}
+	return !c.Holidays[local.Format(time.DateOnly)]
+}
+
+// time between start and end spent on working days, e.g. a pull request opened
+// Friday noon is 24h old on Monday noon with weekends paused
+func (c Calendar) Elapsed(start time.Time, end time.Time) time.Duration {
+	if !end.After(start) {
+		return 0
+	}
+
+	var elapsed time.Duration
+	location := c.location()
+	for day := start.In(location); day.Before(end); {
+		year, month, date := day.Date()
+		next := time.Date(year, month, date+1, 0, 0, 0, 0, location)
+		if next.After(end) {
+			next = end
+		}
+		if c.Working(day) {
+			elapsed += next.Sub(day)
+		}
+		day = next
+	}
+	return elapsed
+}
+
+func (c Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
@@ -0,0 +1,68 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("PAUSE_WEEKENDS", "true")
+	t.Setenv("HOLIDAYS", "2024-12-25, 2024-12-26")
+
+	cal, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cal.Weekends || !cal.Holidays["2024-12-25"] || !cal.Holidays["2024-12-26"] {
+		t.Errorf("Unexpected calendar %v", cal)
+	}
+
+	t.Setenv("CALENDAR_TIMEZONE", "Nowhere/Unknown")
+	if _, err := Load(); err == nil {
+		t.Errorf("Expected error for an unknown timezone")
+	}
+}
+
+func TestWorking(t *testing.T) {
+	cal := Calendar{Weekends: true, Holidays: map[string]bool{"2024-12-25": true}}
+
+	tests := map[time.Time]bool{
+		time.Date(2024, 12, 24, 10, 0, 0, 0, time.UTC): true,
+		time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC): false,
+		time.Date(2024, 12, 28, 10, 0, 0, 0, time.UTC): false,
+	}
+
+	for day, expected := range tests {
+		if working := cal.Working(day); working != expected {
+			t.Errorf("%v: expected %v, got %v", day, expected, working)
+		}
+	}
+
+	if !(Calendar{}).Working(time.Date(2024, 12, 28, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected weekends to be working days by default")
+	}
+}
+
+func TestElapsed(t *testing.T) {
+	cal := Calendar{Weekends: true, Holidays: map[string]bool{"2024-03-12": true}}
+
+	// Friday noon to Monday noon
+	friday := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	if elapsed := cal.Elapsed(friday, monday); elapsed != 24*time.Hour {
+		t.Errorf("Expected 24h, got %v", elapsed)
+	}
+
+	// Monday noon to Wednesday noon over the holiday
+	wednesday := time.Date(2024, 3, 13, 12, 0, 0, 0, time.UTC)
+	if elapsed := cal.Elapsed(monday, wednesday); elapsed != 24*time.Hour {
+		t.Errorf("Expected 24h, got %v", elapsed)
+	}
+
+	if elapsed := (Calendar{}).Elapsed(friday, monday); elapsed != 72*time.Hour {
+		t.Errorf("Expected 72h, got %v", elapsed)
+	}
+	if elapsed := cal.Elapsed(monday, friday); elapsed != 0 {
+		t.Errorf("Expected 0, got %v", elapsed)
+	}
+}
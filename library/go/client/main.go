@@ -0,0 +1,258 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slack-pr-lambda/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized, check the admin token")
+	ErrNotFound     = errors.New("not found")
+)
+
+// non-2xx answer of the API, errors.Is matches ErrUnauthorized and
+// ErrNotFound on their status
+type ResponseError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("slack-pr-lambda: %d %s", e.StatusCode, e.Body)
+}
+
+func (e *ResponseError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// tracked pull request as served by GET /prs
+type PullRequest struct {
+	Repository        string   `json:"repository"`
+	Number            int      `json:"number"`
+	State             string   `json:"state"`
+	Approvals         int      `json:"approvals"`
+	RequiredApprovals int      `json:"requiredApprovals"`
+	Reviewers         []string `json:"reviewers"`
+	CreatedAt         string   `json:"createdAt"`
+	AgeHours          int      `json:"ageHours"`
+	AgeBadge          string   `json:"ageBadge"`
+	Url               string   `json:"url"`
+	SlackUrl          string   `json:"slackUrl"`
+}
+
+// lifecycle of a pull request as served by GET /admin/review-metrics
+type ReviewMetrics struct {
+	Repository             string   `json:"repository"`
+	Number                 int      `json:"number"`
+	Author                 string   `json:"author"`
+	OpenedAt               string   `json:"openedAt"`
+	FirstReviewAt          string   `json:"firstReviewAt"`
+	MergedAt               string   `json:"mergedAt"`
+	ClosedAt               string   `json:"closedAt"`
+	State                  string   `json:"state"`
+	Reviewers              []string `json:"reviewers"`
+	TimeToFirstReviewHours *float64 `json:"timeToFirstReviewHours"`
+	TimeToMergeHours       *float64 `json:"timeToMergeHours"`
+}
+
+// client of the service API. BaseUrl is the API gateway (or local server)
+// url, Token the ADMIN_TOKEN of the deployment, required by the admin methods
+type Client struct {
+	BaseUrl    string
+	Token      string
+	HttpClient *http.Client
+}
+
+func New(baseUrl string, token string) *Client {
+	return &Client{
+		BaseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		Token:      token,
+		HttpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// tracked pull requests, oldest first. repository narrows the list, every
+// repository when empty
+func (c *Client) PullRequests(repository string) ([]PullRequest, error) {
+	query := url.Values{}
+	if repository != "" {
+		query.Set("repo", repository)
+	}
+
+	result := []PullRequest{}
+	err := c.do(http.MethodGet, "/prs", query, &result)
+	return result, err
+}
+
+// re-posts the parent message of a tracked pull request
+func (c *Client) Resend(repository string, number int) (string, error) {
+	return c.message(http.MethodPost, pullRequestPath(repository, number, "resend"), nil)
+}
+
+// deletes the bot messages of a pull request, only replaces their text when
+// redact is set. Returns the summary of the API
+func (c *Client) DeleteMessages(repository string, number int, redact bool) (string, error) {
+	query := url.Values{}
+	if redact {
+		query.Set("mode", "redact")
+	}
+	return c.message(http.MethodDelete, pullRequestPath(repository, number, "messages"), query)
+}
+
+// restores the soft-deleted record of a closed pull request
+func (c *Client) Restore(repository string, number int) (string, error) {
+	return c.message(http.MethodPost, pullRequestPath(repository, number, "restore"), nil)
+}
+
+// processed webhooks of a pull request, oldest first. Empty unless the
+// deployment runs with EVENT_SOURCING
+func (c *Client) Events(repository string, number int) ([]types.TableEventData, error) {
+	result := []types.TableEventData{}
+	err := c.do(http.MethodGet, pullRequestPath(repository, number, "events"), nil, &result)
+	return result, err
+}
+
+// review metrics of the pull requests opened from (included) to (excluded),
+// zero times leave the range open. repository narrows the rows
+func (c *Client) ReviewMetrics(repository string, from time.Time, to time.Time) ([]ReviewMetrics, error) {
+	query := url.Values{"format": {"json"}}
+	if repository != "" {
+		query.Set("repo", repository)
+	}
+	if !from.IsZero() {
+		query.Set("from", from.Format(time.DateOnly))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.Format(time.DateOnly))
+	}
+
+	result := []ReviewMetrics{}
+	err := c.do(http.MethodGet, "/admin/review-metrics", query, &result)
+	return result, err
+}
+
+// archived thread and lifecycle of a closed pull request, ErrNotFound when
+// archiving is disabled or the pull request was not archived
+func (c *Client) Archive(repository string, number int) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := c.do(http.MethodGet, "/admin/archives/"+url.PathEscape(repository)+"/"+strconv.Itoa(number), nil, &result)
+	return result, err
+}
+
+// deletes the data of a Slack user and redacts its mentions. login is the
+// GitHub login of the user once it is no longer in constants.Users
+func (c *Client) DeleteUser(slackUserId string, login string) (string, error) {
+	query := url.Values{}
+	if login != "" {
+		query.Set("login", login)
+	}
+	return c.message(http.MethodDelete, "/admin/users/"+url.PathEscape(slackUserId), query)
+}
+
+// feature flags of the config document and whether they are on for the
+// repository
+func (c *Client) Features(repository string) (map[string]bool, error) {
+	result := map[string]bool{}
+	err := c.do(http.MethodGet, "/admin/features/"+url.PathEscape(repository), nil, &result)
+	return result, err
+}
+
+// records the authors of every pull request of a repository on GitHub, run
+// before enabling the first_contributor flag. Returns the number of authors
+func (c *Client) SeedContributors(repository string) (int, error) {
+	result := struct {
+		Contributors int `json:"contributors"`
+	}{}
+	err := c.do(http.MethodPost, "/admin/contributors/"+url.PathEscape(repository)+"/seed", nil, &result)
+	return result.Contributors, err
+}
+
+// runtime profile of the lambda instance serving the request, e.g. heap or
+// allocs, read with go tool pprof. seconds is the duration of the profile
+// and trace profiles, ErrNotFound when PPROF_ENABLED is off
+func (c *Client) Profile(name string, seconds int) ([]byte, error) {
+	query := url.Values{}
+	if seconds > 0 {
+		query.Set("seconds", strconv.Itoa(seconds))
+	}
+
+	resp, err := c.send(http.MethodGet, "/admin/debug/pprof/"+url.PathEscape(name), query, "application/octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+func pullRequestPath(repository string, number int, action string) string {
+	return fmt.Sprintf("/admin/pull-requests/%s/%d/%s", url.PathEscape(repository), number, action)
+}
+
+// message of the {"message": ...} answers
+func (c *Client) message(method string, path string, query url.Values) (string, error) {
+	var result struct {
+		Message string `json:"message"`
+	}
+	err := c.do(method, path, query, &result)
+	return result.Message, err
+}
+
+// sends the request and decodes the JSON answer into result
+func (c *Client) do(method string, path string, query url.Values, result interface{}) error {
+	resp, err := c.send(method, path, query, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// response of a 2xx answer, a ResponseError otherwise
+func (c *Client) send(method string, path string, query url.Values, accept string) (*http.Response, error) {
+	target := c.BaseUrl + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	req.Header.Set("Accept", accept)
+
+	httpClient := c.HttpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &ResponseError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	return resp, nil
+}
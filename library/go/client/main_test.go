@@ -0,0 +1,146 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// answers body to every request and records the method, url and token
+func testServer(t *testing.T, status int, body string) (*Client, *[]string) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.String()+" "+r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return New(server.URL+"/", "secret"), &requests
+}
+
+func TestPullRequests(t *testing.T) {
+	c, requests := testServer(t, http.StatusOK, `[{"repository": "api", "number": 7, "state": "approved", "reviewers": ["octocat"]}]`)
+
+	pullRequests, err := c.PullRequests("api")
+	assert.NoError(t, err)
+	if assert.Len(t, pullRequests, 1) {
+		assert.Equal(t, 7, pullRequests[0].Number)
+		assert.Equal(t, []string{"octocat"}, pullRequests[0].Reviewers)
+	}
+
+	_, err = c.PullRequests("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET /prs?repo=api Bearer secret", "GET /prs Bearer secret"}, *requests)
+}
+
+func TestAdminMessages(t *testing.T) {
+	c, requests := testServer(t, http.StatusOK, `{"message": "done"}`)
+
+	tests := []struct {
+		name string
+		call func() (string, error)
+	}{
+		{"resend", func() (string, error) { return c.Resend("api", 7) }},
+		{"delete messages", func() (string, error) { return c.DeleteMessages("api", 7, true) }},
+		{"restore", func() (string, error) { return c.Restore("api", 7) }},
+		{"delete user", func() (string, error) { return c.DeleteUser("U123", "octocat") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := tt.call()
+			assert.NoError(t, err)
+			assert.Equal(t, "done", message)
+		})
+	}
+
+	assert.Equal(t, []string{
+		"POST /admin/pull-requests/api/7/resend Bearer secret",
+		"DELETE /admin/pull-requests/api/7/messages?mode=redact Bearer secret",
+		"POST /admin/pull-requests/api/7/restore Bearer secret",
+		"DELETE /admin/users/U123?login=octocat Bearer secret",
+	}, *requests)
+}
+
+func TestEvents(t *testing.T) {
+	c, requests := testServer(t, http.StatusOK, `[{"pullRequest": "api#7", "receivedAt": "2024-03-01T10:00:00.5Z", "action": "opened", "actor": "octocat"}]`)
+
+	events, err := c.Events("api", 7)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "opened", events[0].Action)
+		assert.Equal(t, "octocat", events[0].Actor)
+	}
+	assert.Equal(t, []string{"GET /admin/pull-requests/api/7/events Bearer secret"}, *requests)
+}
+
+func TestReviewMetrics(t *testing.T) {
+	c, requests := testServer(t, http.StatusOK, `[{"repository": "api", "number": 7, "timeToMergeHours": 24}]`)
+
+	rows, err := c.ReviewMetrics("api", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 1) {
+		assert.Equal(t, 24.0, *rows[0].TimeToMergeHours)
+		assert.Nil(t, rows[0].TimeToFirstReviewHours)
+	}
+	assert.Equal(t, []string{"GET /admin/review-metrics?format=json&from=2024-03-01&repo=api Bearer secret"}, *requests)
+}
+
+func TestArchive(t *testing.T) {
+	c, _ := testServer(t, http.StatusOK, `{"repository": "api"}`)
+
+	document, err := c.Archive("api", 7)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"repository": "api"}`, string(document))
+}
+
+func TestFeatures(t *testing.T) {
+	c, requests := testServer(t, http.StatusOK, `{"two_way_sync": true, "ci_integration": false}`)
+
+	features, err := c.Features("api")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"two_way_sync": true, "ci_integration": false}, features)
+	assert.Equal(t, []string{"GET /admin/features/api Bearer secret"}, *requests)
+}
+
+func TestSeedContributors(t *testing.T) {
+	c, requests := testServer(t, http.StatusOK, `{"repository": "api", "contributors": 12}`)
+
+	contributors, err := c.SeedContributors("api")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, contributors)
+	assert.Equal(t, []string{"POST /admin/contributors/api/seed Bearer secret"}, *requests)
+}
+
+func TestProfile(t *testing.T) {
+	c, requests := testServer(t, http.StatusOK, "profile")
+
+	profile, err := c.Profile("profile", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "profile", string(profile))
+	assert.Equal(t, []string{"GET /admin/debug/pprof/profile?seconds=5 Bearer secret"}, *requests)
+
+	c, _ = testServer(t, http.StatusNotFound, "Not Found\n")
+
+	_, err = c.Profile("heap", 0)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestResponseError(t *testing.T) {
+	c, _ := testServer(t, http.StatusUnauthorized, "Unauthorized\n")
+
+	_, err := c.Restore("api", 7)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.EqualError(t, err, "slack-pr-lambda: 401 Unauthorized")
+
+	c, _ = testServer(t, http.StatusNotFound, "Not Found\n")
+
+	_, err = c.Events("api", 7)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrUnauthorized)
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"hash/fnv"
+	"slices"
+)
+
+// rollout of a feature flag, e.g. {"percent": 25, "repositories": ["api"]},
+// new event processors check their flag so they are turned on per repository
+// by a config change instead of a deployment
+type Feature struct {
+	// on for every repository, e.g. once rolled out
+	Enabled bool `json:"enabled,omitempty"`
+	// repositories always on
+	Repositories []string `json:"repositories,omitempty"`
+	// repositories always off, over the rest of the rollout
+	Excluded []string `json:"excluded,omitempty"`
+	// share of the other repositories on, 0 to 100
+	Percent int `json:"percent,omitempty"`
+}
+
+// whether the feature is on for the repository. A repository is in the
+// percentage by the hash of the feature and its name, it stays in as the
+// percentage grows. Unknown features are off
+func (c *Config) Enabled(feature string, repository string) bool {
+	flag, ok := c.Features[feature]
+	if !ok || slices.Contains(flag.Excluded, repository) {
+		return false
+	}
+	if flag.Enabled || slices.Contains(flag.Repositories, repository) {
+		return true
+	}
+	return bucket(feature, repository) < flag.Percent
+}
+
+// 0 to 99, another feature buckets the repositories differently so the same
+// repositories don't get every new feature first
+func bucket(feature string, repository string) int {
+	h := fnv.New32a()
+	h.Write([]byte(feature + "/" + repository))
+	return int(h.Sum32() % 100)
+}
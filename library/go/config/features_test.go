@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	config, err := ParseConfig(`{"features": {
+		"ci": {"enabled": true, "excluded": ["legacy"]},
+		"sync": {"repositories": ["api"], "percent": 50},
+		"off": {}
+	}}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, test := range []struct {
+		feature    string
+		repository string
+		expected   bool
+	}{
+		{"ci", "web", true},
+		{"ci", "legacy", false},
+		{"sync", "api", true},
+		{"off", "api", false},
+		{"unknown", "api", false},
+	} {
+		if enabled := config.Enabled(test.feature, test.repository); enabled != test.expected {
+			t.Errorf("%s %s: got %v want %v", test.feature, test.repository, enabled, test.expected)
+		}
+	}
+}
+
+func TestEnabledPercent(t *testing.T) {
+	config := &Config{Features: map[string]Feature{"sync": {Percent: 30}}}
+
+	enabled := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		repository := fmt.Sprintf("repo-%d", i)
+		if config.Enabled("sync", repository) {
+			enabled[repository] = true
+		}
+	}
+	if len(enabled) < 250 || len(enabled) > 350 {
+		t.Errorf("Expected about 30%% of the repositories, got %d of 1000", len(enabled))
+	}
+
+	// repositories in the rollout stay in as it grows
+	config.Features["sync"] = Feature{Percent: 60}
+	for repository := range enabled {
+		if !config.Enabled("sync", repository) {
+			t.Errorf("Expected %s to stay enabled", repository)
+		}
+	}
+
+	config.Features["sync"] = Feature{Percent: 100}
+	if !config.Enabled("sync", "repo-1") {
+		t.Errorf("Expected every repository at 100%%")
+	}
+}
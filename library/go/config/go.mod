@@ -0,0 +1,3 @@
+module slack-pr-lambda/config
+
+go 1.22
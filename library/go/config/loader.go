@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	db "slack-pr-lambda/dynamodb"
+	"slack-pr-lambda/env"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// key of the config document in CONFIG_TABLE_NAME
+const configId = "config"
+
+var cache = struct {
+	sync.Mutex
+	config   *Config
+	loadedAt time.Time
+}{}
+
+// replaced in tests
+var loadTable = loadFromTable
+
+// the latest config document of CONFIG_TABLE_NAME, cached for CONFIG_TTL_SECONDS
+// so changes are picked up without a redeploy, REPO_CONFIG when no table is set
+func LoadConfig() (*Config, error) {
+	if env.GetEnv("CONFIG_TABLE_NAME", "") == "" {
+		return ParseConfig(env.GetEnv("REPO_CONFIG", ""))
+	}
+
+	ttl, err := strconv.Atoi(env.GetEnv("CONFIG_TTL_SECONDS", "60"))
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Lock()
+	defer cache.Unlock()
+
+	now := time.Now()
+	if cache.config != nil && now.Sub(cache.loadedAt) < time.Duration(ttl)*time.Second {
+		return cache.config, nil
+	}
+
+	config, err := loadTable()
+	if err != nil {
+		// keep serving the last good document until the next reload
+		if cache.config != nil {
+			cache.loadedAt = now
+			return cache.config, nil
+		}
+		return nil, err
+	}
+
+	cache.config = config
+	cache.loadedAt = now
+
+	return config, nil
+}
+
+// REPO_CONFIG until the first document is stored
+func loadFromTable() (*Config, error) {
+	svc := db.DynamoDbConnection()
+
+	item, err := db.GetLatestConfig(svc, configId)
+	if errors.Is(err, db.ErrNoDataFound) {
+		return ParseConfig(env.GetEnv("REPO_CONFIG", ""))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := ParseConfig(item.Document)
+	if err != nil {
+		return nil, err
+	}
+	config.Version = item.Version
+
+	return config, nil
+}
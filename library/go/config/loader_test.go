@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func resetCache(t *testing.T, load func() (*Config, error)) {
+	cache.config = nil
+	cache.loadedAt = time.Time{}
+	loadTable = load
+
+	t.Cleanup(func() {
+		cache.config = nil
+		cache.loadedAt = time.Time{}
+		loadTable = loadFromTable
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("CONFIG_TABLE_NAME", "")
+		t.Setenv("REPO_CONFIG", `{"default": {"requiredApprovals": 2}}`)
+
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.Repo("api").RequiredApprovals != 2 {
+			t.Errorf("Expected 2 required approvals, got %d", config.Repo("api").RequiredApprovals)
+		}
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		t.Setenv("CONFIG_TABLE_NAME", "Config")
+		t.Setenv("CONFIG_TTL_SECONDS", "60")
+
+		loads := 0
+		resetCache(t, func() (*Config, error) {
+			loads++
+			return &Config{Version: loads}, nil
+		})
+
+		for i := 0; i < 3; i++ {
+			config, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if config.Version != 1 {
+				t.Errorf("Expected cached version 1, got %d", config.Version)
+			}
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		t.Setenv("CONFIG_TABLE_NAME", "Config")
+		t.Setenv("CONFIG_TTL_SECONDS", "0")
+
+		loads := 0
+		resetCache(t, func() (*Config, error) {
+			loads++
+			return &Config{Version: loads}, nil
+		})
+
+		LoadConfig()
+		config, _ := LoadConfig()
+		if config.Version != 2 {
+			t.Errorf("Expected reloaded version 2, got %d", config.Version)
+		}
+	})
+
+	t.Run("stale on error", func(t *testing.T) {
+		t.Setenv("CONFIG_TABLE_NAME", "Config")
+		t.Setenv("CONFIG_TTL_SECONDS", "0")
+
+		fail := false
+		resetCache(t, func() (*Config, error) {
+			if fail {
+				return nil, errors.New("unreachable")
+			}
+			return &Config{Version: 1}, nil
+		})
+
+		LoadConfig()
+		fail = true
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("Expected the last good config, got %v", err)
+		}
+		if config.Version != 1 {
+			t.Errorf("Expected version 1, got %d", config.Version)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Setenv("CONFIG_TABLE_NAME", "Config")
+
+		resetCache(t, func() (*Config, error) {
+			return nil, errors.New("unreachable")
+		})
+
+		if _, err := LoadConfig(); err == nil {
+			t.Errorf("Expected error without a cached config")
+		}
+	})
+}
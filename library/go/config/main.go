@@ -0,0 +1,331 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"regexp"
+	"slack-pr-lambda/env"
+	"strings"
+)
+
+// settings of a repository, zero values fall back to the default settings.
+// An organization or repository replaces every field it sets, also with an
+// empty value, and null resets a field of the levels below
+type RepoConfig struct {
+	// approvals needed before merging, 0 uses the branch protection rule
+	RequiredApprovals int `json:"requiredApprovals,omitempty"`
+	// check runs gating the ready to merge ping, empty uses the branch protection rule
+	RequiredChecks []string `json:"requiredChecks,omitempty"`
+	// comment the Slack thread link on the pull request once it is posted
+	ThreadComment bool `json:"threadComment,omitempty"`
+	// merge, squash or rebase strategy of the Slack merge button
+	MergeMethod string `json:"mergeMethod,omitempty"`
+	// hours before requested reviewers are reminded, 0 uses REMINDER_AFTER_HOURS
+	ReminderAfterHours int `json:"reminderAfterHours,omitempty"`
+	// hours between two reminders of a pull request, 0 uses REMINDER_INTERVAL_HOURS
+	ReminderIntervalHours int `json:"reminderIntervalHours,omitempty"`
+	// hours after changes were requested without a push before the author is nudged, 0 uses FOLLOW_UP_AFTER_HOURS
+	FollowUpAfterHours int `json:"followUpAfterHours,omitempty"`
+	// allowed webhook events as "<event>" or "<event>.<action>", empty allows everything
+	Events []string `json:"events,omitempty"`
+	// comment notifications in a thread before they are rolled up, 0 uses COMMENT_ROLLUP_AFTER
+	CommentRollupAfter int `json:"commentRollupAfter,omitempty"`
+	// notify every comment, even on very active pull requests
+	DisableCommentRollup bool `json:"disableCommentRollup,omitempty"`
+	// review SLAs, the first rule matching a label of the pull request applies
+	SlaRules []SlaRule `json:"slaRules,omitempty"`
+	// levels notified in order once a pull request waited their afterHours for a first review
+	Escalation []EscalationLevel `json:"escalation,omitempty"`
+	// only pull requests changing a matching file are posted to SLACK_CHANNEL,
+	// like the destination paths. The others only go to their destinations
+	Paths []string `json:"paths,omitempty"`
+	// nothing is posted to SLACK_CHANNEL, the destinations are the only
+	// notifiers of the repository, e.g. a team on Teams
+	DisableSlackChannel bool `json:"disableSlackChannel,omitempty"`
+	// events whose thread reply is also shown in SLACK_CHANNEL, e.g. ["merged"],
+	// nil uses the BROADCAST_EVENTS entry of the channel and [] none
+	BroadcastEvents []string `json:"broadcastEvents,omitempty"`
+	// images of a description or comment shown in the thread, nil uses
+	// THREAD_IMAGES and 0 turns them off
+	ThreadImages *int `json:"threadImages,omitempty"`
+	// hours to fix a security alert by severity, e.g. {"critical": 4}, over
+	// SECURITY_SLA_HOURS
+	SecuritySlaHours map[string]int `json:"securitySlaHours,omitempty"`
+	// other destinations receiving a copy of the pull request messages
+	Destinations []Destination `json:"destinations,omitempty"`
+	// classes annotating the parent message when they cover most of the diff,
+	// empty uses DefaultFileClasses
+	FileClasses []FileClass `json:"fileClasses,omitempty"`
+	// "/slack <command>" pull request comments allowed, empty uses
+	// DefaultCommentCommands
+	CommentCommands []string `json:"commentCommands,omitempty"`
+	// logins suggested in place of out of office reviewers, empty uses the
+	// collaborators with write access
+	Reviewers []string `json:"reviewers,omitempty"`
+	// request the alternates on GitHub instead of only suggesting them
+	AssignAlternates bool `json:"assignAlternates,omitempty"`
+	// nudge the author of an empty body and hold the review pings until it
+	// is filled in
+	RequireDescription bool `json:"requireDescription,omitempty"`
+	// regexes the body must match, e.g. "(?m)^## Testing", also holding the
+	// review pings until they all match
+	RequiredSections []string `json:"requiredSections,omitempty"`
+}
+
+// e.g. {"name": "generated files", "paths": ["*.pb.go", "gen"]}, the name is
+// the plural shown on the parent message
+type FileClass struct {
+	Name  string   `json:"name"`
+	Paths []string `json:"paths"`
+}
+
+var DefaultFileClasses = []FileClass{
+	{Name: "lockfiles", Paths: []string{"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "go.sum", "Cargo.lock", "poetry.lock", "Gemfile.lock", "composer.lock"}},
+	{Name: "generated files", Paths: []string{"*.pb.go", "*_generated.go", "*.gen.go", "*.min.js", "*.snap", "dist", "vendor"}},
+	{Name: "assets", Paths: []string{"*.png", "*.jpg", "*.jpeg", "*.gif", "*.svg", "*.ico", "*.webp", "*.woff", "*.woff2", "*.ttf", "*.pdf"}},
+}
+
+// e.g. {"label": "hotfix", "firstResponseHours": 2}, a rule without label
+// matches every pull request
+type SlaRule struct {
+	Label              string `json:"label,omitempty"`
+	FirstResponseHours int    `json:"firstResponseHours"`
+}
+
+// e.g. {"afterHours": 48, "users": ["bob"]}, reviewers mentions the requested
+// reviewers and channel posts the pull request to another channel
+type EscalationLevel struct {
+	AfterHours int      `json:"afterHours"`
+	Reviewers  bool     `json:"reviewers,omitempty"`
+	Users      []string `json:"users,omitempty"`
+	Channel    string   `json:"channel,omitempty"`
+}
+
+// e.g. {"type": "teams", "url": "https://..."}, type is slack (channel), teams
+// or discord (url of an incoming webhook), email (to) or a type registered with
+// notifier.Register
+type Destination struct {
+	Type    string `json:"type"`
+	Url     string `json:"url,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	To      string `json:"to,omitempty"`
+	// language of the copies, e.g. "fr". The language of the channel in
+	// CHANNEL_LANGUAGES, then LANGUAGE, when unset
+	Language string `json:"language,omitempty"`
+	// only pull requests changing a matching file, e.g. ["web/", "services/*/api"]
+	Paths []string `json:"paths,omitempty"`
+}
+
+// whether a pull request changing the files is sent to the destination, every
+// pull request without paths
+func (d Destination) Matches(files []string) bool {
+	if len(d.Paths) == 0 {
+		return true
+	}
+
+	for _, file := range files {
+		for _, pattern := range d.Paths {
+			if matchPath(pattern, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// whether a pull request changing the files is posted to SLACK_CHANNEL, every
+// pull request without paths
+func (r RepoConfig) Matches(files []string) bool {
+	return Destination{Paths: r.Paths}.Matches(files)
+}
+
+// like .gitignore, a pattern matches the file or one of its directories.
+// Patterns with a slash are relative to the root ("services/*/ui"), the others
+// match at any level ("*.png", "web" matches web/src/app.ts)
+func matchPath(pattern string, file string) bool {
+	pattern = strings.Trim(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+
+	for name := file; name != "." && name != "/"; name = path.Dir(name) {
+		candidate := name
+		if !anchored {
+			candidate = path.Base(name)
+		}
+		if matched, _ := path.Match(pattern, candidate); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (r RepoConfig) GetFileClasses() []FileClass {
+	if len(r.FileClasses) == 0 {
+		return DefaultFileClasses
+	}
+	return r.FileClasses
+}
+
+// name of the first class matching the file, empty when none does
+func FileClassOf(classes []FileClass, file string) string {
+	for _, class := range classes {
+		for _, pattern := range class.Paths {
+			if matchPath(pattern, file) {
+				return class.Name
+			}
+		}
+	}
+	return ""
+}
+
+// every comment command
+var DefaultCommentCommands = []string{"remind", "mute", "route"}
+
+// whether "/slack <command>" comments run the command, an allowlist without
+// any known command (e.g. ["none"]) turns them off
+func (r RepoConfig) AllowsCommentCommand(command string) bool {
+	allowed := r.CommentCommands
+	if len(allowed) == 0 {
+		allowed = DefaultCommentCommands
+	}
+	for _, name := range allowed {
+		if name == command {
+			return true
+		}
+	}
+	return false
+}
+
+// HTML comments of a pull request template, a body left with only them is empty
+var templateComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// whether the body misses the required description, with the required
+// sections it doesn't match. Invalid patterns are ignored
+func (r RepoConfig) IncompleteDescription(body string) (bool, []string) {
+	if !r.RequireDescription && len(r.RequiredSections) == 0 {
+		return false, nil
+	}
+
+	empty := strings.TrimSpace(templateComment.ReplaceAllString(body, "")) == ""
+	missing := []string{}
+	for _, section := range r.RequiredSections {
+		pattern, err := regexp.Compile(section)
+		if err != nil {
+			continue
+		}
+		if !pattern.MatchString(body) {
+			missing = append(missing, section)
+		}
+	}
+	return empty || len(missing) > 0, missing
+}
+
+const DefaultMergeMethod = "squash"
+
+// merge strategy, falls back to squash when unset or unknown
+func (r RepoConfig) GetMergeMethod() string {
+	switch r.MergeMethod {
+	case "merge", "squash", "rebase":
+		return r.MergeMethod
+	}
+	return DefaultMergeMethod
+}
+
+// whether the webhook event and action should be notified, the event is the
+// X-GitHub-Event header and may be empty, then only "<event>.<action>" entries
+// are matched against the action
+func (r RepoConfig) Allows(event string, action string) bool {
+	if len(r.Events) == 0 {
+		return true
+	}
+
+	for _, allowed := range r.Events {
+		name, allowedAction, hasAction := strings.Cut(allowed, ".")
+		if event == "" {
+			if hasAction && allowedAction == action {
+				return true
+			}
+			continue
+		}
+		if name == event && (!hasAction || allowedAction == action) {
+			return true
+		}
+	}
+	return false
+}
+
+type Config struct {
+	// version of the stored document, 0 when read from REPO_CONFIG
+	Version       int                   `json:"version,omitempty"`
+	Default       RepoConfig            `json:"default"`
+	Organizations map[string]RepoConfig `json:"organizations"`
+	Repositories  map[string]RepoConfig `json:"repositories"`
+	// feature flags by name
+	Features map[string]Feature `json:"features,omitempty"`
+
+	// fields set by each level, merged by Repo
+	fields configFields
+}
+
+type configFields struct {
+	Default       map[string]json.RawMessage            `json:"default"`
+	Organizations map[string]map[string]json.RawMessage `json:"organizations"`
+	Repositories  map[string]map[string]json.RawMessage `json:"repositories"`
+}
+
+// parse a config document, e.g.
+// {"default": {"requiredApprovals": 1}, "repositories": {"api": {"requiredApprovals": 2}}}
+func ParseConfig(raw string) (*Config, error) {
+	config := &Config{
+		Organizations: map[string]RepoConfig{},
+		Repositories:  map[string]RepoConfig{},
+	}
+	if raw == "" {
+		return config, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), config); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(raw), &config.fields); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// repository settings merged over the organization (GITHUB_OWNER) settings
+// and the defaults
+func (c *Config) Repo(name string) RepoConfig {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	fields := map[string]json.RawMessage{}
+	overlay(fields, c.fields.Default)
+	overlay(fields, c.fields.Organizations[owner])
+	overlay(fields, c.fields.Repositories[name])
+
+	result := RepoConfig{}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return result
+	}
+	// the levels were already parsed into RepoConfig by ParseConfig
+	_ = json.Unmarshal(b, &result)
+
+	return result
+}
+
+// a field of the override replaces the base one, lists as a whole and the
+// zero values too, e.g. [] clears the requiredChecks of the defaults. null
+// removes the field so the setting falls back to its default. Names are
+// matched case-insensitively, like encoding/json does
+func overlay(fields map[string]json.RawMessage, override map[string]json.RawMessage) {
+	for name, value := range override {
+		name = strings.ToLower(name)
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			delete(fields, name)
+			continue
+		}
+		fields[name] = value
+	}
+}
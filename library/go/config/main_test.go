@@ -0,0 +1,273 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		config, err := ParseConfig("")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(config.Repo("api"), RepoConfig{}) {
+			t.Errorf("Expected empty repo config, got %+v", config.Repo("api"))
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := ParseConfig("{"); err == nil {
+			t.Errorf("Expected error for invalid config")
+		}
+	})
+}
+
+func TestRepo(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"default": {"requiredApprovals": 1}, "repositories": {"api": {"requiredApprovals": 2}, "web": {}}}`)
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := map[string]int{
+		"api":   2,
+		"web":   1,
+		"other": 1,
+	}
+
+	for repo, expected := range data {
+		if result := config.Repo(repo).RequiredApprovals; result != expected {
+			t.Errorf("%s: Expected %d required approvals, got %d", repo, expected, result)
+		}
+	}
+}
+
+func TestGetMergeMethod(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"default": {"mergeMethod": "merge"}, "repositories": {"api": {"mergeMethod": "rebase"}, "web": {"mergeMethod": "fast-forward"}}}`)
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := map[string]string{
+		"api":   "rebase",
+		"web":   DefaultMergeMethod,
+		"other": "merge",
+	}
+
+	for repo, expected := range data {
+		if result := config.Repo(repo).GetMergeMethod(); result != expected {
+			t.Errorf("%s: Expected %s merge method, got %s", repo, expected, result)
+		}
+	}
+}
+
+func TestAllowsCommentCommand(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"commentCommands": ["remind"]}, "web": {"commentCommands": ["none"]}}}`)
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := map[string][]string{
+		"api":   {"remind"},
+		"web":   {},
+		"other": {"remind", "mute", "route"},
+	}
+
+	for repo, expected := range data {
+		allowed := []string{}
+		for _, command := range DefaultCommentCommands {
+			if config.Repo(repo).AllowsCommentCommand(command) {
+				allowed = append(allowed, command)
+			}
+		}
+		if !reflect.DeepEqual(allowed, expected) {
+			t.Errorf("%s: Expected %v, got %v", repo, expected, allowed)
+		}
+	}
+}
+
+func TestAllows(t *testing.T) {
+	t.Setenv("REPO_CONFIG", `{"repositories": {"api": {"events": ["pull_request.opened", "pull_request.closed", "check_run"]}}}`)
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := []struct {
+		repo     string
+		event    string
+		action   string
+		expected bool
+	}{
+		{"api", "pull_request", "opened", true},
+		{"api", "pull_request", "synchronize", false},
+		{"api", "check_run", "completed", true},
+		{"api", "pull_request_review", "submitted", false},
+		{"api", "", "closed", true},
+		{"api", "", "submitted", false},
+		{"web", "pull_request", "synchronize", true},
+	}
+
+	for _, d := range data {
+		if result := config.Repo(d.repo).Allows(d.event, d.action); result != d.expected {
+			t.Errorf("%s %s.%s: Expected %v, got %v", d.repo, d.event, d.action, d.expected, result)
+		}
+	}
+}
+
+func TestRepoOrganization(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "rodentskie")
+
+	config, err := ParseConfig(`{"default": {"requiredApprovals": 1, "mergeMethod": "merge"}, "organizations": {"rodentskie": {"requiredApprovals": 2}}, "repositories": {"api": {"mergeMethod": "rebase"}}}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := RepoConfig{RequiredApprovals: 2, MergeMethod: "rebase"}
+	if result := config.Repo("api"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	expected = RepoConfig{RequiredApprovals: 2, MergeMethod: "merge"}
+	if result := config.Repo("web"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestRepoOverrides(t *testing.T) {
+	t.Setenv("GITHUB_OWNER", "rodentskie")
+
+	config, err := ParseConfig(`{"default": {"requiredChecks": ["build"], "threadComment": true, "threadImages": 3, "reminderAfterHours": 12},
+		"organizations": {"rodentskie": {"requiredApprovals": 2}},
+		"repositories": {"api": {"requiredChecks": [], "threadComment": false, "threadImages": 0, "requiredApprovals": null}, "web": {"reminderAfterHours": null, "RequiredChecks": ["lint"]}}}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	none := 0
+	expected := RepoConfig{RequiredChecks: []string{}, ThreadImages: &none, ReminderAfterHours: 12}
+	if result := config.Repo("api"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected the empty values to clear the defaults, got %+v", result)
+	}
+
+	three := 3
+	expected = RepoConfig{RequiredApprovals: 2, RequiredChecks: []string{"lint"}, ThreadComment: true, ThreadImages: &three}
+	if result := config.Repo("web"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected null to reset the setting, got %+v", result)
+	}
+}
+
+func TestRepoDestinations(t *testing.T) {
+	config, err := ParseConfig(`{"default": {"destinations": [{"type": "slack", "channel": "C2"}]}, "repositories": {"api": {"destinations": [{"type": "teams", "url": "https://example.webhook.office.com/1"}]}}}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []Destination{{Type: "teams", Url: "https://example.webhook.office.com/1"}}
+	if result := config.Repo("api").Destinations; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	expected = []Destination{{Type: "slack", Channel: "C2"}}
+	if result := config.Repo("web").Destinations; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestDestinationMatches(t *testing.T) {
+	frontend := Destination{Type: "slack", Channel: "CFRONT", Paths: []string{"web/", "services/*/ui"}}
+
+	tests := []struct {
+		files   []string
+		matches bool
+	}{
+		{[]string{"web/src/app.ts"}, true},
+		{[]string{"api/main.go", "services/billing/ui/index.ts"}, true},
+		{[]string{"api/main.go", "README.md"}, false},
+		{[]string{"webhooks/main.go"}, false},
+		{[]string{}, false},
+	}
+
+	for _, tt := range tests {
+		if result := frontend.Matches(tt.files); result != tt.matches {
+			t.Errorf("%v: got %v want %v", tt.files, result, tt.matches)
+		}
+	}
+
+	if !(Destination{Type: "teams"}).Matches(nil) {
+		t.Errorf("Expected a destination without paths to match every pull request")
+	}
+}
+
+func TestRepoConfigMatches(t *testing.T) {
+	repo := RepoConfig{Paths: []string{"services"}}
+
+	if !repo.Matches([]string{"services/api/main.go"}) || repo.Matches([]string{"web/src/app.ts"}) {
+		t.Errorf("Expected only the services files to match")
+	}
+	if !(RepoConfig{}).Matches(nil) {
+		t.Errorf("Expected a repository without paths to match every pull request")
+	}
+}
+
+func TestFileClassOf(t *testing.T) {
+	tests := map[string]string{
+		"package-lock.json":           "lockfiles",
+		"web/yarn.lock":               "lockfiles",
+		"api/proto/user.pb.go":        "generated files",
+		"web/dist/app.js":             "generated files",
+		"web/public/logo.svg":         "assets",
+		"api/main.go":                 "",
+		"docs/lockfiles-explained.md": "",
+	}
+
+	for file, expected := range tests {
+		if result := FileClassOf(DefaultFileClasses, file); result != expected {
+			t.Errorf("%s: got %q want %q", file, result, expected)
+		}
+	}
+
+	classes := RepoConfig{FileClasses: []FileClass{{Name: "migrations", Paths: []string{"db/migrations"}}}}.GetFileClasses()
+	if result := FileClassOf(classes, "db/migrations/001_init.sql"); result != "migrations" {
+		t.Errorf("Expected the configured class, got %q", result)
+	}
+	if result := FileClassOf(classes, "go.sum"); result != "" {
+		t.Errorf("Expected configured classes to replace the defaults, got %q", result)
+	}
+}
+
+func TestIncompleteDescription(t *testing.T) {
+	required := RepoConfig{RequireDescription: true}
+	sections := RepoConfig{RequiredSections: []string{"(?m)^## Types of changes", "(?mi)^## testing", "("}}
+
+	tests := []struct {
+		name       string
+		config     RepoConfig
+		body       string
+		incomplete bool
+		missing    []string
+	}{
+		{"not required", RepoConfig{}, "", false, nil},
+		{"empty", required, " \n", true, []string{}},
+		{"template comments only", required, "<!-- What does this change? -->\n", true, []string{}},
+		{"filled in", required, "Adds the client.", false, []string{}},
+		{"missing section", sections, "## Types of changes\n- [x] Bug fix", true, []string{"(?mi)^## testing"}},
+		{"every section", sections, "## Types of changes\n- [x] Bug fix\n## Testing\nUnit tests", false, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incomplete, missing := tt.config.IncompleteDescription(tt.body)
+			if incomplete != tt.incomplete || !reflect.DeepEqual(missing, tt.missing) {
+				t.Errorf("got %v %v want %v %v", incomplete, missing, tt.incomplete, tt.missing)
+			}
+		})
+	}
+}
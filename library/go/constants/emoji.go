@@ -15,6 +15,23 @@ type Emojis struct {
 	Reviewed         string
 	RequestReview    string
 	Comment          string
+	Reminder         string
+	AgeFresh         string
+	AgeAging         string
+	AgeStale         string
+	FirstContributor string
+	WorkInProgress   string
+	Recovered        string
+	ReadyToMerge     string
+	DiffClass        string
+	// merge queue entries
+	MergeQueued   string
+	MergeDequeued string
+	// security alerts by severity
+	SeverityCritical string
+	SeverityHigh     string
+	SeverityMedium   string
+	SeverityLow      string
 }
 
 func Emoji() *Emojis {
@@ -32,5 +49,20 @@ func Emoji() *Emojis {
 		Reviewed:         ":reviewed:",
 		RequestReview:    ":eyes:",
 		Comment:          ":writing_hand:",
+		Reminder:         ":bell:",
+		AgeFresh:         ":large_green_circle:",
+		AgeAging:         ":large_yellow_circle:",
+		AgeStale:         ":red_circle:",
+		FirstContributor: ":tada:",
+		WorkInProgress:   ":construction:",
+		Recovered:        ":white_check_mark:",
+		ReadyToMerge:     ":rocket:",
+		DiffClass:        ":package:",
+		MergeQueued:      ":train:",
+		MergeDequeued:    ":no_entry:",
+		SeverityCritical: ":rotating_light:",
+		SeverityHigh:     ":red_circle:",
+		SeverityMedium:   ":large_orange_circle:",
+		SeverityLow:      ":large_yellow_circle:",
 	}
 }
@@ -20,6 +20,21 @@ func TestEmoji(t *testing.T) {
 		Reviewed:         ":reviewed:",
 		RequestReview:    ":eyes:",
 		Comment:          ":writing_hand:",
+		Reminder:         ":bell:",
+		AgeFresh:         ":large_green_circle:",
+		AgeAging:         ":large_yellow_circle:",
+		AgeStale:         ":red_circle:",
+		FirstContributor: ":tada:",
+		WorkInProgress:   ":construction:",
+		Recovered:        ":white_check_mark:",
+		ReadyToMerge:     ":rocket:",
+		DiffClass:        ":package:",
+		MergeQueued:      ":train:",
+		MergeDequeued:    ":no_entry:",
+		SeverityCritical: ":rotating_light:",
+		SeverityHigh:     ":red_circle:",
+		SeverityMedium:   ":large_orange_circle:",
+		SeverityLow:      ":large_yellow_circle:",
 	}
 
 	result := Emoji()
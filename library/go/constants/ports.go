@@ -1,11 +1,13 @@
 package constants
 
 type Ports struct {
-	MainApi int
+	MainApi   int
+	SlackStub int
 }
 
 func Port() *Ports {
 	return &Ports{
-		MainApi: 8080,
+		MainApi:   8080,
+		SlackStub: 8081,
 	}
 }
@@ -7,7 +7,8 @@ import (
 
 func TestPort(t *testing.T) {
 	expected := &Ports{
-		MainApi: 8080,
+		MainApi:   8080,
+		SlackStub: 8081,
 	}
 
 	result := Port()
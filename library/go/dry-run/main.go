@@ -0,0 +1,36 @@
+package dryrun
+
+import (
+	"errors"
+	"log"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/logger"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// DRY_RUN=true runs the full pipeline but side effects (Slack calls, DynamoDB
+// and GitHub writes) are only logged
+func Enabled() bool {
+	return env.GetEnv("DRY_RUN", "false") == "true"
+}
+
+// log the side effect that was skipped
+func Log(operation string, fields ...zap.Field) {
+	zapLog, err := logger.Base()
+	if err != nil {
+		log.Printf("error building the logger. %v\n", err)
+		return
+	}
+
+	defer func() {
+		if err := zapLog.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
+			log.Fatalf("error closing the logger. %v\n", err)
+		}
+	}()
+
+	zapLog.Info("dry run",
+		append([]zap.Field{zap.String("operation", operation)}, fields...)...,
+	)
+}
@@ -0,0 +1,30 @@
+package dryrun
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestEnabled(t *testing.T) {
+	data := map[string]bool{
+		"true":  true,
+		"false": false,
+		"":      false,
+		"1":     false,
+	}
+
+	for value, expected := range data {
+		t.Setenv("DRY_RUN", value)
+
+		if result := Enabled(); result != expected {
+			t.Errorf("DRY_RUN=%q: Expected %v, got %v", value, expected, result)
+		}
+	}
+}
+
+func TestLog(t *testing.T) {
+	t.Setenv("ENV", "test")
+
+	Log("slack.send_message", zap.String("message", "hello"))
+}
@@ -0,0 +1,68 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+func InsertAudit(svc *dynamodb.DynamoDB, item *types.TableAuditData) error {
+	tableName := env.GetEnv("AUDIT_TABLE_NAME", "Audit")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// every message sent for "<repository>#<number>", oldest first
+func ListAudits(svc *dynamodb.DynamoDB, pullRequest string) ([]types.TableAuditData, error) {
+	tableName := env.GetEnv("AUDIT_TABLE_NAME", "Audit")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("pullRequest = :pullRequest"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pullRequest": {
+				S: aws.String(pullRequest),
+			},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.QueryPages(input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableAuditData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,62 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	envVars := map[string]string{
+		"AUDIT_TABLE_NAME": "Audit",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	pullRequest := fmt.Sprintf("api#%d", time.Now().UnixMilli())
+	first := &types.TableAuditData{
+		PullRequest: pullRequest,
+		SentAt:      time.Now().Format(time.RFC3339Nano),
+		EventId:     "delivery-1",
+		Source:      "opened",
+		Type:        "parent",
+		Channel:     "C1",
+		TimeStamp:   "1.000001",
+		Text:        "opened new pull request",
+	}
+	second := &types.TableAuditData{
+		PullRequest:     pullRequest,
+		SentAt:          time.Now().Add(time.Second).Format(time.RFC3339Nano),
+		EventId:         "delivery-2",
+		Source:          "synchronize",
+		Type:            "thread",
+		Channel:         "C1",
+		TimeStamp:       "1.000002",
+		ThreadTimeStamp: "1.000001",
+		Text:            "pushed a change",
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		assert.NoError(t, InsertAudit(svc, first))
+		assert.NoError(t, InsertAudit(svc, second))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListAudits(svc, pullRequest)
+		assert.NoError(t, err)
+		assert.Equal(t, []types.TableAuditData{*first, *second}, result)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := ListAudits(svc, "unknown#0")
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
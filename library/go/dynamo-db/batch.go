@@ -0,0 +1,99 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// keys per BatchGetItem request, the DynamoDB limit
+const batchGetSize = 100
+
+// requests of the keys left unprocessed by a throttled table
+const batchGetAttempts = 5
+
+var ErrUnprocessedKeys = errors.New("keys left unprocessed by the batch get")
+
+// wait before the unprocessed keys are requested again, doubled on every
+// attempt. Replaced in tests
+var batchRetryDelay = 50 * time.Millisecond
+
+// items of the keys found in the table, in no particular order. Keys are
+// requested 100 at a time and the unprocessed ones again with a backoff
+func batchGet(svc *dynamodb.DynamoDB, tableName string, keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, error) {
+	var items []map[string]*dynamodb.AttributeValue
+	for i := 0; i < len(keys); i += batchGetSize {
+		pending := keys[i:min(i+batchGetSize, len(keys))]
+
+		delay := batchRetryDelay
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt == batchGetAttempts {
+				return nil, ErrUnprocessedKeys
+			}
+			if attempt > 0 {
+				time.Sleep(delay)
+				delay *= 2
+			}
+
+			output, err := svc.BatchGetItem(&dynamodb.BatchGetItemInput{
+				RequestItems: map[string]*dynamodb.KeysAndAttributes{
+					tableName: {Keys: pending},
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, output.Responses[tableName]...)
+			pending = nil
+			if unprocessed, ok := output.UnprocessedKeys[tableName]; ok {
+				pending = unprocessed.Keys
+			}
+		}
+	}
+	return items, nil
+}
+
+// snooze-until unix timestamps of githubLogin keyed by the pull request record
+// id, for the ids snoozed by the login
+func BatchGetSnoozes(svc *dynamodb.DynamoDB, ids []string, githubLogin string) (map[string]int64, error) {
+	tableName := env.GetEnv("SNOOZE_TABLE_NAME", "Snoozes")
+
+	seen := map[string]bool{}
+	requested := []map[string]*dynamodb.AttributeValue{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		requested = append(requested, map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(id),
+			},
+			"githubLogin": {
+				S: aws.String(githubLogin),
+			},
+		})
+	}
+
+	items, err := batchGet(svc, tableName, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableSnoozeData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64)
+	for _, record := range records {
+		result[record.ID] = record.SnoozeUntil
+	}
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+)
+
+type batchGetRequest struct {
+	RequestItems map[string]struct {
+		Keys           []map[string]map[string]string
+		ConsistentRead bool
+	}
+}
+
+// BatchGetItem of one table, the first request leaves its last `throttled`
+// keys unprocessed. Returns the key counts of the requests
+func batchGetServer(t *testing.T, tableName string, throttled int) (*httptest.Server, *[]int) {
+	requests := []int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input batchGetRequest
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Fatal(err)
+		}
+		keys := input.RequestItems[tableName].Keys
+		requests = append(requests, len(keys))
+
+		unprocessed := []map[string]map[string]string{}
+		if len(requests) == 1 && throttled > 0 {
+			unprocessed = keys[len(keys)-throttled:]
+			keys = keys[:len(keys)-throttled]
+		}
+
+		output := map[string]interface{}{
+			"Responses": map[string]interface{}{tableName: keys},
+		}
+		if len(unprocessed) > 0 {
+			output["UnprocessedKeys"] = map[string]interface{}{tableName: map[string]interface{}{"Keys": unprocessed}}
+		}
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func stubBatchRetryDelay(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	original := batchRetryDelay
+	batchRetryDelay = time.Millisecond
+	t.Cleanup(func() {
+		batchRetryDelay = original
+	})
+}
+
+func TestBatchGetPages(t *testing.T) {
+	stubBatchRetryDelay(t)
+	t.Setenv("SNOOZE_TABLE_NAME", "Snoozes")
+
+	server, requests := batchGetServer(t, "Snoozes", 10)
+	svc := newClient(session.Must(session.NewSession()), server.URL, "us-east-1")
+
+	ids := []string{}
+	for number := 1; number <= 150; number++ {
+		ids = append(ids, strconv.Itoa(number))
+	}
+	// requested once
+	ids = append(ids, "1")
+
+	snoozes, err := BatchGetSnoozes(svc, ids, "octocat")
+	assert.NoError(t, err)
+	assert.Len(t, snoozes, 150)
+	assert.Equal(t, []int{100, 10, 50}, *requests, "the unprocessed keys are requested again")
+}
+
+func TestBatchGetUnprocessed(t *testing.T) {
+	stubBatchRetryDelay(t)
+
+	// every request leaves its keys unprocessed
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input batchGetRequest
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"UnprocessedKeys": map[string]interface{}{"Snoozes": map[string]interface{}{"Keys": input.RequestItems["Snoozes"].Keys}},
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("SNOOZE_TABLE_NAME", "Snoozes")
+	svc := newClient(session.Must(session.NewSession()), server.URL, "us-east-1")
+
+	_, err := BatchGetSnoozes(svc, []string{"1"}, "octocat")
+	assert.ErrorIs(t, err, ErrUnprocessedKeys)
+}
+
+func TestBatchGetSnoozes(t *testing.T) {
+	stubBatchRetryDelay(t)
+	t.Setenv("SNOOZE_TABLE_NAME", "Snoozes")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"Responses": {"Snoozes": [{"id": {"S": "2"}, "githubLogin": {"S": "octocat"}, "snoozeUntil": {"N": "1700000000"}}]}}`))
+	}))
+	defer server.Close()
+
+	svc := newClient(session.Must(session.NewSession()), server.URL, "us-east-1")
+
+	snoozes, err := BatchGetSnoozes(svc, []string{"1", "2"}, "octocat")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"2": 1700000000}, snoozes)
+
+	// no request without ids
+	snoozes, err = BatchGetSnoozes(svc, nil, "octocat")
+	assert.NoError(t, err)
+	assert.Empty(t, snoozes)
+}
@@ -0,0 +1,46 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// changed files per top-level directory and class of the diff after a push
+func UpdateChangedFiles(svc *dynamodb.DynamoDB, id int, pullRequestId int, directories map[string]int, diffClass string, version int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Any("changedDirectories", directories), zap.String("diffClass", diffClass), zap.Int("version", version))
+		return nil
+	}
+
+	value, err := dynamodbattribute.Marshal(directories)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		UpdateExpression: aws.String("SET changedDirectories = :changedDirectories, diffClass = :diffClass"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":changedDirectories": value,
+			":diffClass":          {S: aws.String(diffClass)},
+		},
+	}
+
+	return versionConflict(svc.UpdateItem(versioned(input, version)))
+}
@@ -0,0 +1,43 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateChangedFiles(t *testing.T) {
+	t.Setenv("TABLE_NAME", "PullRequests")
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:                 fmt.Sprintf("%d", id),
+		PullRequestId:      id,
+		SlackTimeStamp:     fmt.Sprintf("%d", id),
+		ChangedDirectories: map[string]int{"web": 2},
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		assert.NoError(t, UpdateChangedFiles(svc, id, id, map[string]int{"web": 3}, "", 0))
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("update", func(t *testing.T) {
+		assert.NoError(t, UpdateChangedFiles(svc, id, id, map[string]int{"web": 3, "/": 1}, "Mostly lockfiles (3/4)", 0))
+
+		result, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"web": 3, "/": 1}, result.ChangedDirectories)
+		assert.Equal(t, "Mostly lockfiles (3/4)", result.DiffClass)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// checked and total checkboxes after an edit of the pull request body
+func UpdateChecklist(svc *dynamodb.DynamoDB, id int, pullRequestId int, done int, total int, version int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Int("checklistDone", done), zap.Int("checklistTotal", total), zap.Int("version", version))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		UpdateExpression: aws.String("SET checklistDone = :checklistDone, checklistTotal = :checklistTotal"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":checklistDone":  {N: aws.String(strconv.Itoa(done))},
+			":checklistTotal": {N: aws.String(strconv.Itoa(total))},
+		},
+	}
+
+	return versionConflict(svc.UpdateItem(versioned(input, version)))
+}
@@ -0,0 +1,39 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateChecklist(t *testing.T) {
+	t.Setenv("TABLE_NAME", "PullRequests")
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+		ChecklistTotal: 3,
+	}
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("update", func(t *testing.T) {
+		assert.NoError(t, UpdateChecklist(svc, id, id, 2, 4, 0))
+
+		result, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.ChecklistDone)
+		assert.Equal(t, 4, result.ChecklistTotal)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
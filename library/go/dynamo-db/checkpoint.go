@@ -0,0 +1,74 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// checkpoints are kept three days, GitHub only redelivers recent deliveries
+const checkpointRetention = 3 * 24 * 60 * 60
+
+// step of the delivery eventId, ErrNoDataFound when it didn't complete yet
+func GetCheckpoint(svc *dynamodb.DynamoDB, eventId string, step string) (*types.TableCheckpointData, error) {
+	tableName := env.GetEnv("CHECKPOINT_TABLE_NAME", "Checkpoints")
+
+	result, err := getItem(svc, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"eventId": {
+				S: aws.String(eventId),
+			},
+			"step": {
+				S: aws.String(step),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TableCheckpointData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// record a completed step, expired by dynamodb after checkpointRetention
+func InsertCheckpoint(svc *dynamodb.DynamoDB, item *types.TableCheckpointData) error {
+	tableName := env.GetEnv("CHECKPOINT_TABLE_NAME", "Checkpoints")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	item.ExpiresAt = time.Now().Unix() + checkpointRetention
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpoint(t *testing.T) {
+	t.Setenv("CHECKPOINT_TABLE_NAME", "Checkpoints")
+
+	svc := DynamoDbConnection()
+
+	eventId := fmt.Sprintf("delivery-%d", time.Now().UnixMilli())
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := GetCheckpoint(svc, eventId, "parent")
+		assert.ErrorIs(t, err, ErrNoDataFound)
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		err := InsertCheckpoint(svc, &types.TableCheckpointData{
+			EventId:        eventId,
+			Step:           "parent",
+			SlackTimeStamp: "1.000001",
+			CompletedAt:    "2024-03-08T10:00:00Z",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		result, err := GetCheckpoint(svc, eventId, "parent")
+		assert.NoError(t, err)
+		assert.Equal(t, "1.000001", result.SlackTimeStamp)
+		assert.Greater(t, result.ExpiresAt, time.Now().Unix())
+
+		_, err = GetCheckpoint(svc, eventId, "thread")
+		assert.ErrorIs(t, err, ErrNoDataFound)
+	})
+}
@@ -0,0 +1,159 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// batches are kept a day, long after their window closed
+const commentBatchRetention = 24 * 60 * 60
+
+var ErrCommentBatchClosed = errors.New("comment batch closed")
+
+// another comment of the burst claimed the batch first
+var ErrCommentBatchOpen = errors.New("comment batch open")
+
+// count a review comment on path in the batch id started at or after windowStart,
+// ErrCommentBatchClosed when there is none and a new batch has to be started
+func AddToCommentBatch(svc *dynamodb.DynamoDB, id string, path string, windowStart int64) (*types.TableCommentBatchData, error) {
+	tableName := env.GetEnv("COMMENT_BATCH_TABLE_NAME", "CommentBatches")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.String("id", id))
+		return nil, ErrCommentBatchClosed
+	}
+
+	result, err := svc.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(id),
+			},
+		},
+		UpdateExpression:    aws.String("ADD comments :one, files :files"),
+		ConditionExpression: aws.String("startedAt >= :windowStart"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":one":         {N: aws.String("1")},
+			":files":       {SS: []*string{aws.String(path)}},
+			":windowStart": {N: aws.String(fmt.Sprintf("%d", windowStart))},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllNew),
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil, ErrCommentBatchClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	item := &types.TableCommentBatchData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// claim a new batch, replacing the closed one of the same reviewer, before its
+// reply is posted. ErrCommentBatchOpen when a batch started at or after
+// windowStart exists, the comment is then counted in it
+func ClaimCommentBatch(svc *dynamodb.DynamoDB, item *types.TableCommentBatchData, windowStart int64) error {
+	tableName := env.GetEnv("COMMENT_BATCH_TABLE_NAME", "CommentBatches")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	item.ExpiresAt = item.StartedAt + commentBatchRetention
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(tableName),
+		ConditionExpression: aws.String("attribute_not_exists(id) OR startedAt < :windowStart"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":windowStart": {N: aws.String(fmt.Sprintf("%d", windowStart))},
+		},
+	}
+
+	_, err = svc.PutItem(insert)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return ErrCommentBatchOpen
+	}
+	return err
+}
+
+// store the reply of the claimed batch, returns the batch with the comments
+// counted while the reply was posted
+func UpdateCommentBatchTimeStamp(svc *dynamodb.DynamoDB, id string, slackTimeStamp string) (*types.TableCommentBatchData, error) {
+	tableName := env.GetEnv("COMMENT_BATCH_TABLE_NAME", "CommentBatches")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.String("id", id), zap.String("slackTimeStamp", slackTimeStamp))
+		return &types.TableCommentBatchData{ID: id, SlackTimeStamp: slackTimeStamp}, nil
+	}
+
+	result, err := svc.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(id),
+			},
+		},
+		UpdateExpression: aws.String("SET slackTimeStamp = :slackTimeStamp"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":slackTimeStamp": {S: aws.String(slackTimeStamp)},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllNew),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item := &types.TableCommentBatchData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// releases the claim of a batch whose reply could not be posted
+func DeleteCommentBatch(svc *dynamodb.DynamoDB, id string) error {
+	tableName := env.GetEnv("COMMENT_BATCH_TABLE_NAME", "CommentBatches")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("id", id))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(id),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
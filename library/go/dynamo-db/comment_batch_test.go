@@ -0,0 +1,71 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentBatch(t *testing.T) {
+	envVars := map[string]string{
+		"COMMENT_BATCH_TABLE_NAME": "CommentBatches",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	id := fmt.Sprintf("api#%d#alice", time.Now().UnixMilli())
+	now := time.Now().Unix()
+
+	t.Run("closed", func(t *testing.T) {
+		_, err := AddToCommentBatch(svc, id, "main.go", now-10)
+		assert.ErrorIs(t, err, ErrCommentBatchClosed)
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		err := ClaimCommentBatch(svc, &types.TableCommentBatchData{
+			ID:             id,
+			SlackTimeStamp: "1.000001",
+			StartedAt:      now,
+			Comments:       1,
+			Files:          []string{"main.go"},
+		}, now-10)
+		assert.NoError(t, err)
+	})
+
+	t.Run("claimed", func(t *testing.T) {
+		err := ClaimCommentBatch(svc, &types.TableCommentBatchData{
+			ID:        id,
+			StartedAt: now,
+			Comments:  1,
+			Files:     []string{"main.go"},
+		}, now-10)
+		assert.ErrorIs(t, err, ErrCommentBatchOpen)
+	})
+
+	t.Run("add", func(t *testing.T) {
+		item, err := AddToCommentBatch(svc, id, "main_test.go", now-10)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, item.Comments)
+		assert.ElementsMatch(t, []string{"main.go", "main_test.go"}, item.Files)
+		assert.Equal(t, "1.000001", item.SlackTimeStamp)
+	})
+
+	t.Run("time stamp", func(t *testing.T) {
+		item, err := UpdateCommentBatchTimeStamp(svc, id, "1.000002")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, item.Comments)
+		assert.Equal(t, "1.000002", item.SlackTimeStamp)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := AddToCommentBatch(svc, id, "main.go", now+10)
+		assert.ErrorIs(t, err, ErrCommentBatchClosed)
+	})
+}
@@ -0,0 +1,119 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// counts a comment notification sent in the thread, returns the new count
+func AddCommentNotification(svc *dynamodb.DynamoDB, id int, pullRequestId int) (int, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("add", "commentNotifications"))
+		return 1, nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// untracked pull requests are not created by the update
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("ADD commentNotifications :one"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":one": {
+				N: aws.String("1"),
+			},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueUpdatedNew),
+	}
+
+	result, err := svc.UpdateItem(input)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(aws.StringValue(result.Attributes["commentNotifications"].N))
+}
+
+// keeps a comment for the next rollup, pendingSince is set by the first one
+func AddPendingComment(svc *dynamodb.DynamoDB, id int, pullRequestId int, now string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("add", "pendingComments"))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("ADD pendingComments :one SET pendingSince = if_not_exists(pendingSince, :now)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":one": {
+				N: aws.String("1"),
+			},
+			":now": {
+				S: aws.String(now),
+			},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+// subtracts the rolled up comments, the ones received meanwhile stay pending
+func ClearPendingComments(svc *dynamodb.DynamoDB, id int, pullRequestId int, count int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Int("pendingComments", -count))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		UpdateExpression: aws.String("ADD pendingComments :count REMOVE pendingSince"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":count": {
+				N: aws.String(strconv.Itoa(-count)),
+			},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentRollup(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		_, err := AddCommentNotification(svc, id, id)
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("notifications", func(t *testing.T) {
+		count, err := AddCommentNotification(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = AddCommentNotification(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("pending", func(t *testing.T) {
+		assert.NoError(t, AddPendingComment(svc, id, id, "2024-01-01T10:00:00Z"))
+		assert.NoError(t, AddPendingComment(svc, id, id, "2024-01-01T10:30:00Z"))
+
+		pullRequest, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, pullRequest.PendingComments)
+		assert.Equal(t, "2024-01-01T10:00:00Z", pullRequest.PendingSince)
+
+		assert.NoError(t, ClearPendingComments(svc, id, id, 2))
+
+		pullRequest, err = GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, pullRequest.PendingComments)
+		assert.Equal(t, "", pullRequest.PendingSince)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
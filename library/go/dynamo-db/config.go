@@ -0,0 +1,69 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// versions are never overwritten, a new document needs a higher version
+func InsertConfig(svc *dynamodb.DynamoDB, item *types.TableConfigData) error {
+	tableName := env.GetEnv("CONFIG_TABLE_NAME", "Config")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(tableName),
+		ConditionExpression: aws.String("attribute_not_exists(version)"),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// highest version of the config document
+func GetLatestConfig(svc *dynamodb.DynamoDB, id string) (*types.TableConfigData, error) {
+	tableName := env.GetEnv("CONFIG_TABLE_NAME", "Config")
+
+	result, err := svc.Query(&dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("id = :id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":id": {
+				S: aws.String(id),
+			},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int64(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TableConfigData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Items[0], item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
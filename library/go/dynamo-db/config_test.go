@@ -0,0 +1,51 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	envVars := map[string]string{
+		"CONFIG_TABLE_NAME": "Config",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+	id := fmt.Sprintf("%d", time.Now().UnixMilli())
+
+	t.Run("insert", func(t *testing.T) {
+		for version := 1; version <= 2; version++ {
+			err := InsertConfig(svc, &types.TableConfigData{
+				ID:       id,
+				Version:  version,
+				Document: fmt.Sprintf(`{"version": %d}`, version),
+			})
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("existing version", func(t *testing.T) {
+		err := InsertConfig(svc, &types.TableConfigData{ID: id, Version: 1})
+		assert.Error(t, err)
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		result, err := GetLatestConfig(svc, id)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Version)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := GetLatestConfig(svc, "unknown")
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrNoDataFound)
+	})
+}
@@ -0,0 +1,85 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// records the author of a repository, the first recorded pull request is kept
+// so the contribution stays the first when its opened event is redelivered
+func RecordContributor(svc *dynamodb.DynamoDB, item *types.TableContributorData) error {
+	tableName := env.GetEnv("CONTRIBUTOR_TABLE_NAME", "Contributors")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.PutItem(&dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(tableName),
+		ConditionExpression: aws.String("attribute_not_exists(author)"),
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil
+	}
+	return err
+}
+
+func GetContributor(svc *dynamodb.DynamoDB, repository string, author string) (*types.TableContributorData, error) {
+	tableName := env.GetEnv("CONTRIBUTOR_TABLE_NAME", "Contributors")
+
+	result, err := svc.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"repository": {
+				S: aws.String(repository),
+			},
+			"author": {
+				S: aws.String(author),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TableContributorData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// whether the author is a contributor of the repository with another first
+// pull request than pullRequest. The history starts with the seeded authors,
+// see RecordContributor
+func HasAuthored(svc *dynamodb.DynamoDB, repository string, author string, pullRequest string) (bool, error) {
+	item, err := GetContributor(svc, repository, author)
+	if errors.Is(err, ErrNoDataFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return item.PullRequest != pullRequest, nil
+}
@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContributors(t *testing.T) {
+	t.Setenv("CONTRIBUTOR_TABLE_NAME", "Contributors")
+
+	svc := DynamoDbConnection()
+
+	number := int(time.Now().UnixMilli() % 1000000)
+	author := fmt.Sprintf("newcomer-%d", number)
+	first := fmt.Sprintf("api#%d", number)
+
+	t.Run("record", func(t *testing.T) {
+		assert.NoError(t, RecordContributor(svc, &types.TableContributorData{Repository: "api", Author: author, PullRequest: first, RecordedAt: "2024-03-04T10:00:00Z"}))
+
+		// the first pull request is kept
+		assert.NoError(t, RecordContributor(svc, &types.TableContributorData{Repository: "api", Author: author, PullRequest: fmt.Sprintf("api#%d", number+1), RecordedAt: "2024-03-05T10:00:00Z"}))
+
+		item, err := GetContributor(svc, "api", author)
+		assert.NoError(t, err)
+		assert.Equal(t, first, item.PullRequest)
+
+		_, err = GetContributor(svc, "web", author)
+		assert.ErrorIs(t, err, ErrNoDataFound)
+	})
+
+	t.Run("authored", func(t *testing.T) {
+		authored, err := HasAuthored(svc, "api", author, first)
+		assert.NoError(t, err)
+		assert.False(t, authored)
+
+		authored, err = HasAuthored(svc, "api", author, fmt.Sprintf("api#%d", number+3))
+		assert.NoError(t, err)
+		assert.True(t, authored)
+
+		authored, err = HasAuthored(svc, "web", author, "web#1")
+		assert.NoError(t, err)
+		assert.False(t, authored)
+	})
+}
@@ -0,0 +1,86 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// the first dashboard of a channel wins, a concurrent insert fails with
+// ConditionalCheckFailedException
+func InsertDashboard(svc *dynamodb.DynamoDB, item *types.TableDashboardData) error {
+	tableName := env.GetEnv("DASHBOARD_TABLE_NAME", "Dashboards")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(tableName),
+		ConditionExpression: aws.String("attribute_not_exists(channel)"),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetDashboard(svc *dynamodb.DynamoDB, channel string) (*types.TableDashboardData, error) {
+	tableName := env.GetEnv("DASHBOARD_TABLE_NAME", "Dashboards")
+
+	result, err := getItem(svc, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"channel": {
+				S: aws.String(channel),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TableDashboardData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// the pinned message was deleted in Slack, the next refresh posts a new one
+func DeleteDashboard(svc *dynamodb.DynamoDB, channel string) error {
+	tableName := env.GetEnv("DASHBOARD_TABLE_NAME", "Dashboards")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("channel", channel))
+		return nil
+	}
+
+	_, err := svc.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"channel": {
+				S: aws.String(channel),
+			},
+		},
+	})
+	return err
+}
@@ -0,0 +1,47 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboard(t *testing.T) {
+	envVars := map[string]string{
+		"DASHBOARD_TABLE_NAME": "Dashboards",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	item := &types.TableDashboardData{
+		Channel:        fmt.Sprintf("C%d", time.Now().UnixMilli()),
+		SlackTimeStamp: "1.000001",
+		UpdatedAt:      time.Now().Format(time.RFC3339),
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		assert.NoError(t, InsertDashboard(svc, item))
+		assert.Error(t, InsertDashboard(svc, item))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		result, err := GetDashboard(svc, item.Channel)
+		assert.NoError(t, err)
+		assert.Equal(t, item, result)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, DeleteDashboard(svc, item.Channel))
+
+		_, err := GetDashboard(svc, item.Channel)
+		assert.True(t, errors.Is(err, ErrNoDataFound))
+	})
+}
@@ -0,0 +1,131 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// adds the slack user ids to the mentions deferred in the thread, the first
+// due time is kept
+func DeferMentions(svc *dynamodb.DynamoDB, item *types.TableDeferredMentionData) error {
+	tableName := env.GetEnv("DEFERRED_MENTION_TABLE_NAME", "DeferredMentions")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"pullRequest": {
+				S: aws.String(item.PullRequest),
+			},
+			"threadTimeStamp": {
+				S: aws.String(item.ThreadTimeStamp),
+			},
+		},
+		UpdateExpression: aws.String("SET repository = :repository, #number = :number, dueAt = if_not_exists(dueAt, :dueAt) ADD slackUserIds :slackUserIds"),
+		ExpressionAttributeNames: map[string]*string{
+			"#number": aws.String("number"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":repository":   {S: aws.String(item.Repository)},
+			":number":       {N: aws.String(strconv.Itoa(item.Number))},
+			":dueAt":        {N: aws.String(strconv.FormatInt(item.DueAt, 10))},
+			":slackUserIds": {SS: aws.StringSlice(item.SlackUserIds)},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ListDeferredMentions(svc *dynamodb.DynamoDB) ([]types.TableDeferredMentionData, error) {
+	tableName := env.GetEnv("DEFERRED_MENTION_TABLE_NAME", "DeferredMentions")
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableDeferredMentionData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func DeleteDeferredMention(svc *dynamodb.DynamoDB, pullRequest string, threadTimeStamp string) error {
+	tableName := env.GetEnv("DEFERRED_MENTION_TABLE_NAME", "DeferredMentions")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("pullRequest", pullRequest), zap.String("threadTimeStamp", threadTimeStamp))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"pullRequest": {
+				S: aws.String(pullRequest),
+			},
+			"threadTimeStamp": {
+				S: aws.String(threadTimeStamp),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+// the slack user is no longer mentioned in the thread, the other deferred
+// mentions stay due
+func RemoveDeferredMention(svc *dynamodb.DynamoDB, pullRequest string, threadTimeStamp string, slackUserId string) error {
+	tableName := env.GetEnv("DEFERRED_MENTION_TABLE_NAME", "DeferredMentions")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.String("pullRequest", pullRequest), zap.String("threadTimeStamp", threadTimeStamp), zap.String("slackUserId", slackUserId))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"pullRequest": {
+				S: aws.String(pullRequest),
+			},
+			"threadTimeStamp": {
+				S: aws.String(threadTimeStamp),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(pullRequest)"),
+		UpdateExpression:    aws.String("DELETE slackUserIds :slackUserIds"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":slackUserIds": {SS: aws.StringSlice([]string{slackUserId})},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
@@ -0,0 +1,59 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeferredMentions(t *testing.T) {
+	t.Setenv("DEFERRED_MENTION_TABLE_NAME", "DeferredMentions")
+
+	svc := DynamoDbConnection()
+
+	pullRequest := fmt.Sprintf("api#%d", time.Now().UnixMilli())
+	item := &types.TableDeferredMentionData{
+		PullRequest:     pullRequest,
+		ThreadTimeStamp: "1.000001",
+		Repository:      "api",
+		Number:          7,
+		SlackUserIds:    []string{"U1"},
+		DueAt:           100,
+	}
+
+	t.Run("defer", func(t *testing.T) {
+		assert.NoError(t, DeferMentions(svc, item))
+		assert.NoError(t, DeferMentions(svc, &types.TableDeferredMentionData{
+			PullRequest:     pullRequest,
+			ThreadTimeStamp: "1.000001",
+			Repository:      "api",
+			Number:          7,
+			SlackUserIds:    []string{"U1", "U2"},
+			DueAt:           200,
+		}))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListDeferredMentions(svc)
+		assert.NoError(t, err)
+
+		var record *types.TableDeferredMentionData
+		for i := range result {
+			if result[i].PullRequest == pullRequest {
+				record = &result[i]
+			}
+		}
+		if assert.NotNil(t, record) {
+			assert.ElementsMatch(t, []string{"U1", "U2"}, record.SlackUserIds)
+			assert.Equal(t, int64(100), record.DueAt)
+			assert.Equal(t, 7, record.Number)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, DeleteDeferredMention(svc, pullRequest, "1.000001"))
+	})
+}
@@ -0,0 +1,79 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// dependencies declared by an edit of the pull request body
+func UpdateDependencies(svc *dynamodb.DynamoDB, id int, pullRequestId int, dependencies []string, version int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Strings("dependencies", dependencies), zap.Int("version", version))
+		return nil
+	}
+
+	values := []*dynamodb.AttributeValue{}
+	for _, dependency := range dependencies {
+		values = append(values, &dynamodb.AttributeValue{S: aws.String(dependency)})
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		UpdateExpression: aws.String("SET dependencies = :dependencies"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":dependencies": {L: values},
+		},
+	}
+
+	return versionConflict(svc.UpdateItem(versioned(input, version)))
+}
+
+// true for the first caller only, the unblocked note is posted once per
+// merged dependency
+func AddMergedDependency(svc *dynamodb.DynamoDB, id int, pullRequestId int, dependency string) (bool, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("mergedDependency", dependency))
+		return true, nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(id) AND NOT contains(mergedDependencies, :name)"),
+		UpdateExpression:    aws.String("ADD mergedDependencies :dependency"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":name":       {S: aws.String(dependency)},
+			":dependency": {SS: aws.StringSlice([]string{dependency})},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		// already merged, or the pull request is untracked
+		return false, ignoreUntracked(nil, err)
+	}
+	return true, nil
+}
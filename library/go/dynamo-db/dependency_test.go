@@ -0,0 +1,54 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencies(t *testing.T) {
+	t.Setenv("TABLE_NAME", "PullRequests")
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+		Dependencies:   []string{"acme/api#1"},
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		merged, err := AddMergedDependency(svc, id, id, "acme/api#1")
+		assert.NoError(t, err)
+		assert.False(t, merged)
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("update", func(t *testing.T) {
+		assert.NoError(t, UpdateDependencies(svc, id, id, []string{"acme/api#1", "acme/web#2"}, 0))
+
+		result, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"acme/api#1", "acme/web#2"}, result.Dependencies)
+	})
+
+	t.Run("merged once", func(t *testing.T) {
+		merged, err := AddMergedDependency(svc, id, id, "acme/api#1")
+		assert.NoError(t, err)
+		assert.True(t, merged)
+
+		merged, err = AddMergedDependency(svc, id, id, "acme/api#1")
+		assert.NoError(t, err)
+		assert.False(t, merged)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
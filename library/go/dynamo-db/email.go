@@ -0,0 +1,116 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+func InsertEmailPreference(svc *dynamodb.DynamoDB, item *types.TableEmailPreferenceData) error {
+	tableName := env.GetEnv("EMAIL_TABLE_NAME", "EmailPreferences")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetEmailPreference(svc *dynamodb.DynamoDB, githubLogin string) (*types.TableEmailPreferenceData, error) {
+	tableName := env.GetEnv("EMAIL_TABLE_NAME", "EmailPreferences")
+
+	result, err := svc.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"githubLogin": {
+				S: aws.String(githubLogin),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TableEmailPreferenceData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// email addresses of the users who opted into email, keyed by github login
+func ListEmailPreferences(svc *dynamodb.DynamoDB) (map[string]string, error) {
+	tableName := env.GetEnv("EMAIL_TABLE_NAME", "EmailPreferences")
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableEmailPreferenceData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for _, record := range records {
+		result[record.GithubLogin] = record.Email
+	}
+
+	return result, nil
+}
+
+func DeleteEmailPreference(svc *dynamodb.DynamoDB, githubLogin string) error {
+	tableName := env.GetEnv("EMAIL_TABLE_NAME", "EmailPreferences")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("githubLogin", githubLogin))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"githubLogin": {
+				S: aws.String(githubLogin),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailPreference(t *testing.T) {
+	envVars := map[string]string{
+		"EMAIL_TABLE_NAME": "EmailPreferences",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	item := &types.TableEmailPreferenceData{
+		GithubLogin: fmt.Sprintf("login%d", time.Now().UnixMilli()),
+		Email:       "bob@acme.com",
+		OptedInAt:   time.Now().Format(time.RFC3339),
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		assert.NoError(t, InsertEmailPreference(svc, item))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		result, err := GetEmailPreference(svc, item.GithubLogin)
+		assert.NoError(t, err)
+		assert.Equal(t, item, result)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListEmailPreferences(svc)
+		assert.NoError(t, err)
+		assert.Equal(t, "bob@acme.com", result[item.GithubLogin])
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, DeleteEmailPreference(svc, item.GithubLogin))
+
+		_, err := GetEmailPreference(svc, item.GithubLogin)
+		assert.ErrorIs(t, err, ErrNoDataFound)
+	})
+}
@@ -0,0 +1,60 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+var ErrEscalationClaimed = errors.New("escalation level already claimed")
+
+// moves the pull request from level current to current+1 before the level is
+// notified, so concurrent or retried runs notify every level once
+func ClaimEscalationLevel(svc *dynamodb.DynamoDB, id int, pullRequestId int, current int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Int("escalationLevel", current+1))
+		return nil
+	}
+
+	condition := "escalationLevel = :current"
+	if current == 0 {
+		condition = "attribute_exists(id) AND (attribute_not_exists(escalationLevel) OR escalationLevel = :current)"
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String(condition),
+		UpdateExpression:    aws.String("SET escalationLevel = :next"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":current": {
+				N: aws.String(strconv.Itoa(current)),
+			},
+			":next": {
+				N: aws.String(strconv.Itoa(current + 1)),
+			},
+		},
+	}
+
+	_, err := svc.UpdateItem(input)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return ErrEscalationClaimed
+	}
+	return err
+}
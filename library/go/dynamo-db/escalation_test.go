@@ -0,0 +1,49 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimEscalationLevel(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		assert.ErrorIs(t, ClaimEscalationLevel(svc, id, id, 0), ErrEscalationClaimed)
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("claim", func(t *testing.T) {
+		assert.NoError(t, ClaimEscalationLevel(svc, id, id, 0))
+		assert.ErrorIs(t, ClaimEscalationLevel(svc, id, id, 0), ErrEscalationClaimed)
+		assert.NoError(t, ClaimEscalationLevel(svc, id, id, 1))
+
+		pullRequest, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, pullRequest.EscalationLevel)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
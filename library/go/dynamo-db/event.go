@@ -0,0 +1,140 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+var ErrEventRecorded = errors.New("event already recorded")
+
+// appends a webhook to the events of its pull request. The delivery id is
+// checkpointed with the event, a redelivery of it is ErrEventRecorded
+func AppendEvent(svc *dynamodb.DynamoDB, item *types.TableEventData) error {
+	tableName := env.GetEnv("EVENT_TABLE_NAME", "Events")
+	checkpointTableName := env.GetEnv("CHECKPOINT_TABLE_NAME", "Checkpoints")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.transact_write_items", zap.String("table", tableName), zap.Any("item", item), zap.String("checkpointTable", checkpointTableName))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+	event := &dynamodb.Put{
+		Item:      av,
+		TableName: aws.String(tableName),
+		// events are never overwritten
+		ConditionExpression: aws.String("attribute_not_exists(receivedAt)"),
+	}
+
+	if item.EventId == "" {
+		_, err := svc.PutItem(&dynamodb.PutItemInput{
+			Item:                event.Item,
+			TableName:           event.TableName,
+			ConditionExpression: event.ConditionExpression,
+		})
+		return err
+	}
+
+	checkpointAv, err := dynamodbattribute.MarshalMap(&types.TableCheckpointData{
+		EventId:     item.EventId,
+		Step:        "event",
+		CompletedAt: item.ReceivedAt,
+		ExpiresAt:   time.Now().Unix() + checkpointRetention,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{Put: event},
+			{Put: &dynamodb.Put{
+				Item:                checkpointAv,
+				TableName:           aws.String(checkpointTableName),
+				ConditionExpression: aws.String("attribute_not_exists(eventId)"),
+			}},
+		},
+	})
+	var canceled *dynamodb.TransactionCanceledException
+	if errors.As(err, &canceled) && len(canceled.CancellationReasons) == 2 && aws.StringValue(canceled.CancellationReasons[1].Code) == "ConditionalCheckFailed" {
+		return ErrEventRecorded
+	}
+	return err
+}
+
+// events of "<repository>#<number>", oldest first
+func ListEvents(svc *dynamodb.DynamoDB, pullRequest string) ([]types.TableEventData, error) {
+	tableName := env.GetEnv("EVENT_TABLE_NAME", "Events")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("pullRequest = :pullRequest"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pullRequest": {
+				S: aws.String(pullRequest),
+			},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.QueryPages(input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableEventData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// events of every pull request received from (included) to (excluded)
+func ListEventsBetween(svc *dynamodb.DynamoDB, from time.Time, to time.Time) ([]types.TableEventData, error) {
+	tableName := env.GetEnv("EVENT_TABLE_NAME", "Events")
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+		// receivedAt is types.SortableTime, the bounds compare to the nanosecond
+		FilterExpression: aws.String("receivedAt >= :from AND receivedAt < :to"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":from": {
+				S: aws.String(from.UTC().Format(types.SortableTime)),
+			},
+			":to": {
+				S: aws.String(to.UTC().Format(types.SortableTime)),
+			},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableEventData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,71 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents(t *testing.T) {
+	t.Setenv("EVENT_TABLE_NAME", "Events")
+	t.Setenv("CHECKPOINT_TABLE_NAME", "Checkpoints")
+
+	svc := DynamoDbConnection()
+
+	pullRequest := fmt.Sprintf("api#%d", time.Now().UnixMilli())
+	opened := &types.TableEventData{
+		PullRequest: pullRequest,
+		ReceivedAt:  time.Now().UTC().Format(types.SortableTime),
+		EventId:     fmt.Sprintf("delivery-%d", time.Now().UnixNano()),
+		Event:       "pull_request",
+		Action:      "opened",
+		Actor:       "octocat",
+	}
+	approved := &types.TableEventData{
+		PullRequest: pullRequest,
+		ReceivedAt:  time.Now().UTC().Add(time.Second).Format(types.SortableTime),
+		EventId:     fmt.Sprintf("delivery-%d", time.Now().UnixNano()+1),
+		Event:       "pull_request_review",
+		Action:      "submitted",
+		Actor:       "hubot",
+	}
+
+	t.Run("append", func(t *testing.T) {
+		assert.NoError(t, AppendEvent(svc, approved))
+		assert.NoError(t, AppendEvent(svc, opened))
+
+		// never overwritten
+		assert.Error(t, AppendEvent(svc, &types.TableEventData{PullRequest: pullRequest, ReceivedAt: opened.ReceivedAt}))
+
+		// redelivered later
+		redelivered := *opened
+		redelivered.ReceivedAt = time.Now().UTC().Add(time.Minute).Format(types.SortableTime)
+		assert.ErrorIs(t, AppendEvent(svc, &redelivered), ErrEventRecorded)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		events, err := ListEvents(svc, pullRequest)
+		assert.NoError(t, err)
+		assert.Equal(t, []types.TableEventData{*opened, *approved}, events)
+	})
+	t.Run("between", func(t *testing.T) {
+		receivedAt, err := time.Parse(time.RFC3339Nano, opened.ReceivedAt)
+		assert.NoError(t, err)
+
+		// the bound is compared to the nanosecond
+		events, err := ListEventsBetween(svc, receivedAt, receivedAt.Add(time.Nanosecond))
+		assert.NoError(t, err)
+		assert.Contains(t, events, *opened)
+
+		events, err = ListEventsBetween(svc, receivedAt.Add(-time.Minute), receivedAt.Add(time.Minute))
+		assert.NoError(t, err)
+		assert.Contains(t, events, *opened)
+
+		events, err = ListEventsBetween(svc, receivedAt.Add(time.Minute), receivedAt.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.NotContains(t, events, *opened)
+	})
+}
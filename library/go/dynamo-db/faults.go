@@ -0,0 +1,51 @@
+package dynamodb
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slack-pr-lambda/faults"
+	"strconv"
+	"strings"
+)
+
+// answers the DynamoDB calls failed by FAULTS like DynamoDB would, "throttle"
+// as a ProvisionedThroughputExceededException and a status, e.g. "500", as an
+// InternalServerError. The SDK retries, the throttle classification and the
+// failover see them as real failures
+type faultTransport struct {
+	next http.RoundTripper
+}
+
+func (t faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// "DynamoDB_20120810.PutItem"
+	_, operation, _ := strings.Cut(req.Header.Get("X-Amz-Target"), ".")
+
+	status, code := 0, ""
+	switch fault := faults.Next("dynamodb", operation); fault {
+	case "":
+		return t.next.RoundTrip(req)
+	case "throttle":
+		status, code = http.StatusBadRequest, "ProvisionedThroughputExceededException"
+	default:
+		var err error
+		if status, err = strconv.Atoi(fault); err != nil {
+			return t.next.RoundTrip(req)
+		}
+		code = "InternalServerError"
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"__type":"com.amazonaws.dynamodb.v20120810#%s","message":"injected fault"}`, code))),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package dynamodb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/apperrors"
+	"slack-pr-lambda/faults"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaults(t *testing.T) {
+	resetFailover(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("ENVIRONMENT", "staging")
+	t.Cleanup(faults.Reset)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String("PullRequests"),
+		Item:      map[string]*dynamodb.AttributeValue{"id": {S: aws.String("1")}},
+	}
+	svc := newClient(session.Must(session.NewSession()), server.URL, "us-east-1")
+
+	t.Run("retried", func(t *testing.T) {
+		t.Setenv("FAULTS", "dynamodb.PutItem=throttlex1")
+		faults.Reset()
+
+		_, err := svc.PutItem(input)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls, "the throttled attempt is retried")
+	})
+
+	// without retries the failure reaches the caller
+	svc.Retryer = client.DefaultRetryer{NumMaxRetries: 0}
+
+	t.Run("throttle", func(t *testing.T) {
+		t.Setenv("FAULTS", "dynamodb=throttle")
+		faults.Reset()
+
+		_, err := svc.PutItem(input)
+		assert.ErrorIs(t, err, apperrors.ErrDynamoThrottle)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		t.Setenv("FAULTS", "dynamodb.PutItem=500")
+		faults.Reset()
+
+		_, err := svc.PutItem(input)
+		assert.True(t, regionalError(err))
+		_, err = svc.GetItem(&dynamodb.GetItemInput{TableName: aws.String("PullRequests"), Key: input.Item})
+		assert.NoError(t, err, "other operations go through")
+	})
+}
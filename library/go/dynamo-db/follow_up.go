@@ -0,0 +1,88 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// starts the follow-up of a changes requested review, a later review restarts it
+func UpdateChangesRequested(svc *dynamodb.DynamoDB, id int, pullRequestId int, author string, reviewer string, url string, at string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("changesRequestedBy", reviewer))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// untracked pull requests are not created by the update
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("SET changesRequestedAt = :at, changesRequestedBy = :reviewer, changesRequestedUrl = :url, author = :author"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":at": {
+				S: aws.String(at),
+			},
+			":reviewer": {
+				S: aws.String(reviewer),
+			},
+			":url": {
+				S: aws.String(url),
+			},
+			":author": {
+				S: aws.String(author),
+			},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+// stops the follow-up after a push or the nudge, a no-op without one
+func ClearChangesRequested(svc *dynamodb.DynamoDB, id int, pullRequestId int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("remove", "changesRequestedAt"))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(changesRequestedAt)"),
+		UpdateExpression:    aws.String("REMOVE changesRequestedAt, changesRequestedBy, changesRequestedUrl"),
+	}
+
+	_, err := svc.UpdateItem(input)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil
+	}
+	return err
+}
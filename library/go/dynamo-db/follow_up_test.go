@@ -0,0 +1,60 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangesRequested(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		assert.Error(t, UpdateChangesRequested(svc, id, id, "alice", "bob", "https://github.com/o/api/pull/7#r1", "2024-01-01T10:00:00Z"))
+		assert.NoError(t, ClearChangesRequested(svc, id, id))
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("update", func(t *testing.T) {
+		assert.NoError(t, UpdateChangesRequested(svc, id, id, "alice", "bob", "https://github.com/o/api/pull/7#r1", "2024-01-01T10:00:00Z"))
+
+		pullRequest, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-01T10:00:00Z", pullRequest.ChangesRequestedAt)
+		assert.Equal(t, "bob", pullRequest.ChangesRequestedBy)
+		assert.Equal(t, "alice", pullRequest.Author)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		assert.NoError(t, ClearChangesRequested(svc, id, id))
+		assert.NoError(t, ClearChangesRequested(svc, id, id))
+
+		pullRequest, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "", pullRequest.ChangesRequestedAt)
+		assert.Equal(t, "alice", pullRequest.Author)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
@@ -5,6 +5,7 @@ go 1.22
 require (
 	github.com/aws/aws-sdk-go v1.51.0
 	github.com/stretchr/testify v1.8.4
+	go.uber.org/zap v1.27.0
 )
 
 require (
@@ -13,6 +14,7 @@ require (
 	github.com/kr/pretty v0.3.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.12.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
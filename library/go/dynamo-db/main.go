@@ -2,39 +2,89 @@ package dynamodb
 
 import (
 	"errors"
+	"slack-pr-lambda/dryrun"
 	"slack-pr-lambda/env"
 	"slack-pr-lambda/types"
 
 	"strconv"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
 )
 
+var ErrNoDataFound = errors.New("no data found")
+
+var ErrAlreadyTracked = errors.New("pull request already tracked")
+
+var ErrParentReposted = errors.New("parent message re-posted concurrently")
+
+var connection struct {
+	once sync.Once
+	sess *session.Session
+	svc  *dynamodb.DynamoDB
+	err  error
+}
+
+// client created on first use and shared by every caller afterwards, the
+// session error is returned to every caller as well. The client of
+// FAILOVER_REGION while the primary region is failed over
+func Connection() (*dynamodb.DynamoDB, error) {
+	connection.once.Do(func() {
+		connection.sess, connection.svc, connection.err = newConnection()
+	})
+	if connection.err != nil {
+		return nil, connection.err
+	}
+
+	if svc := failoverConnection(connection.sess); svc != nil {
+		return svc, nil
+	}
+	return connection.svc, nil
+}
+
+// Connection for callers without an error path, panics when the session can't
+// be created
 func DynamoDbConnection() *dynamodb.DynamoDB {
+	svc, err := Connection()
+	if err != nil {
+		panic(err)
+	}
+	return svc
+}
+
+func newConnection() (*session.Session, *dynamodb.DynamoDB, error) {
 	// Initialize a session that the SDK will use to load
 	// credentials from the shared credentials file ~/.aws/credentials
 	// and region from the shared configuration file ~/.aws/config.
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
+	sess, err := session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
-	}))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 
 	db := env.GetEnv("DB_ENDPOINT", "http://localhost:8000")
 	region := env.GetEnv("REGION", "us-east-1")
 
-	svc := dynamodb.New(sess, &aws.Config{
-		Endpoint: &db,
-		Region:   &region,
-	})
+	svc := newClient(sess, db, region)
+	svc.Handlers.Complete.PushBack(observeRegion)
 
-	return svc
+	return sess, svc, nil
 }
 
 func InsertItem(svc *dynamodb.DynamoDB, item *types.TablePullRequestData) error {
 	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
 
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
 	av, err := dynamodbattribute.MarshalMap(item)
 	if err != nil {
 		return err
@@ -53,10 +103,73 @@ func InsertItem(svc *dynamodb.DynamoDB, item *types.TablePullRequestData) error
 	return nil
 }
 
+// claim the record of a pull request before its parent message is posted, so
+// two concurrent requests don't both post one. A soft-deleted record is
+// replaced, ErrAlreadyTracked when the pull request has a record
+func ClaimItem(svc *dynamodb.DynamoDB, item *types.TablePullRequestData) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(tableName),
+		ConditionExpression: aws.String("attribute_not_exists(id) OR attribute_exists(deletedAt)"),
+	}
+
+	_, err = svc.PutItem(insert)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return ErrAlreadyTracked
+	}
+	return err
+}
+
+// the record of a new parent message and the audit entry of the message, both
+// or neither are stored
+func InsertItemAudited(svc *dynamodb.DynamoDB, item *types.TablePullRequestData, audit *types.TableAuditData) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+	auditTableName := env.GetEnv("AUDIT_TABLE_NAME", "Audit")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.transact_write_items", zap.String("table", tableName), zap.Any("item", item), zap.String("auditTable", auditTableName), zap.Any("audit", audit))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+	auditAv, err := dynamodbattribute.MarshalMap(audit)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{Put: &dynamodb.Put{Item: av, TableName: aws.String(tableName)}},
+			{Put: &dynamodb.Put{Item: auditAv, TableName: aws.String(auditTableName)}},
+		},
+	}
+
+	if _, err := svc.TransactWriteItems(input); err != nil {
+		return err
+	}
+	return nil
+}
+
 func GetSlackTimeStamp(svc *dynamodb.DynamoDB, id int, pullRequestId int) (string, error) {
 	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
 
-	result, err := svc.GetItem(&dynamodb.GetItemInput{
+	result, err := getItem(svc, &dynamodb.GetItemInput{
 		TableName: aws.String(tableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"id": {
@@ -71,7 +184,7 @@ func GetSlackTimeStamp(svc *dynamodb.DynamoDB, id int, pullRequestId int) (strin
 		return "", err
 	}
 	if result.Item == nil {
-		return "", errors.New("no data found")
+		return "", ErrNoDataFound
 	}
 
 	item := types.TablePullRequestData{}
@@ -80,13 +193,26 @@ func GetSlackTimeStamp(svc *dynamodb.DynamoDB, id int, pullRequestId int) (strin
 	if err != nil {
 		return "", err
 	}
+	if item.DeletedAt != "" {
+		return "", ErrNoDataFound
+	}
 
 	return item.SlackTimeStamp, nil
 }
 
+// soft-deletes the record, or deletes it when SOFT_DELETE_DAYS is 0
 func DeleteItem(svc *dynamodb.DynamoDB, id int, pullRequestId int) error {
 	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
 
+	if SoftDeleteDays() > 0 {
+		return softDelete(svc, tableName, id, pullRequestId)
+	}
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId))
+		return nil
+	}
+
 	input := &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"id": {
@@ -108,6 +234,11 @@ func DeleteItem(svc *dynamodb.DynamoDB, id int, pullRequestId int) error {
 func DeleteAllItem(svc *dynamodb.DynamoDB) error {
 	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
 
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_all_items", zap.String("table", tableName))
+		return nil
+	}
+
 	input := &dynamodb.ScanInput{
 		TableName: aws.String(tableName),
 	}
@@ -159,3 +290,229 @@ func DeleteAllItem(svc *dynamodb.DynamoDB) error {
 
 	return nil
 }
+
+func ListPullRequests(svc *dynamodb.DynamoDB) ([]types.TablePullRequestData, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []types.TablePullRequestData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &result); err != nil {
+		return nil, err
+	}
+
+	return live(result), nil
+}
+
+func GetPullRequest(svc *dynamodb.DynamoDB, id int, pullRequestId int) (*types.TablePullRequestData, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	result, err := getItem(svc, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TablePullRequestData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+		return nil, err
+	}
+	if item.DeletedAt != "" {
+		return nil, ErrNoDataFound
+	}
+
+	return item, nil
+}
+
+func UpdateApprovals(svc *dynamodb.DynamoDB, id int, pullRequestId int, approvals int, requiredApprovals int, version int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Int("approvals", approvals), zap.Int("requiredApprovals", requiredApprovals), zap.Int("version", version))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		UpdateExpression: aws.String("SET approvals = :approvals, requiredApprovals = :requiredApprovals"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":approvals": {
+				N: aws.String(strconv.Itoa(approvals)),
+			},
+			":requiredApprovals": {
+				N: aws.String(strconv.Itoa(requiredApprovals)),
+			},
+		},
+	}
+
+	return versionConflict(svc.UpdateItem(versioned(input, version)))
+}
+
+// parent message re-posted, the thread continues under the new timestamp. It
+// replaces oldSlackTimeStamp only, ErrParentReposted when another delivery
+// re-posted the parent message first
+func UpdateSlackTimeStamp(svc *dynamodb.DynamoDB, id int, pullRequestId int, oldSlackTimeStamp string, slackTimeStamp string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("slackTimeStamp", slackTimeStamp))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("slackTimeStamp = :oldSlackTimeStamp"),
+		UpdateExpression:    aws.String("SET slackTimeStamp = :slackTimeStamp"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":oldSlackTimeStamp": {
+				S: aws.String(oldSlackTimeStamp),
+			},
+			":slackTimeStamp": {
+				S: aws.String(slackTimeStamp),
+			},
+		},
+	}
+
+	_, err := svc.UpdateItem(bumpVersion(input))
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return ErrParentReposted
+	}
+	return err
+}
+
+// permalink of a new parent message
+func UpdatePermalink(svc *dynamodb.DynamoDB, id int, pullRequestId int, permalink string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("permalink", permalink))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// untracked pull requests are not created by the update
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("SET permalink = :permalink"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":permalink": {
+				S: aws.String(permalink),
+			},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
+
+// parent message text of the record, e.g. with the mentions of a deleted user
+// edited out
+func UpdateParentMessage(svc *dynamodb.DynamoDB, id int, pullRequestId int, parentMessage string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("parentMessage", parentMessage))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("SET parentMessage = :parentMessage"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":parentMessage": {
+				S: aws.String(parentMessage),
+			},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
+
+// age tier last rendered on the parent message
+func UpdateAgeBadge(svc *dynamodb.DynamoDB, id int, pullRequestId int, ageBadge string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("ageBadge", ageBadge))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		UpdateExpression: aws.String("SET ageBadge = :ageBadge"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":ageBadge": {
+				S: aws.String(ageBadge),
+			},
+		},
+	}
+
+	if _, err := svc.UpdateItem(bumpVersion(input)); err != nil {
+		return err
+	}
+	return nil
+}
@@ -28,6 +28,16 @@ func TestDynamoDbConnection(t *testing.T) {
 	assert.Equal(t, envVars["REGION"], aws.StringValue(svc.Config.Region))
 }
 
+func TestConnection(t *testing.T) {
+	first, err := Connection()
+	assert.NoError(t, err)
+
+	second, err := Connection()
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Same(t, first, DynamoDbConnection())
+}
+
 func TestInsertItem(t *testing.T) {
 	envVars := map[string]string{
 		"TABLE_NAME": "PullRequests",
@@ -57,6 +67,50 @@ func TestInsertItem(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("claim", func(t *testing.T) {
+		item := &types.TablePullRequestData{
+			ID:            fmt.Sprintf("%d", time.Now().UnixMilli()),
+			PullRequestId: int(time.Now().UnixMilli()),
+		}
+
+		assert.NoError(t, ClaimItem(svc, item))
+		assert.ErrorIs(t, ClaimItem(svc, item), ErrAlreadyTracked)
+	})
+
+	t.Run("audited", func(t *testing.T) {
+		item := &types.TablePullRequestData{
+			ID:             fmt.Sprintf("%d", time.Now().UnixMilli()),
+			PullRequestId:  int(time.Now().UnixMilli()),
+			SlackTimeStamp: fmt.Sprintf("%d", time.Now().UnixMilli()),
+		}
+		audit := &types.TableAuditData{
+			PullRequest: fmt.Sprintf("api#%d", item.PullRequestId),
+			SentAt:      time.Now().Format(time.RFC3339Nano),
+			Type:        "parent",
+			TimeStamp:   item.SlackTimeStamp,
+		}
+
+		assert.NoError(t, InsertItemAudited(svc, item, audit))
+
+		records, err := ListAudits(svc, audit.PullRequest)
+		assert.NoError(t, err)
+		assert.Equal(t, []types.TableAuditData{*audit}, records)
+	})
+
+	t.Run("audited error", func(t *testing.T) {
+		// the invalid record cancels the audit entry too
+		audit := &types.TableAuditData{
+			PullRequest: fmt.Sprintf("api#%d", time.Now().UnixMilli()),
+			SentAt:      time.Now().Format(time.RFC3339Nano),
+		}
+
+		assert.Error(t, InsertItemAudited(svc, &types.TablePullRequestData{}, audit))
+
+		records, err := ListAudits(svc, audit.PullRequest)
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
 	if err := DeleteAllItem(svc); err != nil {
 		t.Errorf("error delete all item %v", err)
 	}
@@ -175,3 +229,306 @@ func TestDeleteAllItem(t *testing.T) {
 	})
 
 }
+
+func TestListPullRequests(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	t.Run("successful", func(t *testing.T) {
+		item := &types.TablePullRequestData{
+			ID:             fmt.Sprintf("%d", time.Now().UnixMilli()),
+			PullRequestId:  int(time.Now().UnixMilli()),
+			SlackTimeStamp: fmt.Sprintf("%d", time.Now().UnixMilli()),
+			Repository:     "repo",
+			CreatedAt:      time.Now().Format(time.RFC3339),
+		}
+
+		err := InsertItem(svc, item)
+		assert.NoError(t, err)
+
+		items, err := ListPullRequests(svc)
+		assert.NoError(t, err)
+		assert.Contains(t, items, *item)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
+
+func TestGetPullRequest(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	t.Run("successful", func(t *testing.T) {
+		item := &types.TablePullRequestData{
+			ID:                fmt.Sprintf("%d", time.Now().UnixMilli()),
+			PullRequestId:     int(time.Now().UnixMilli()),
+			SlackTimeStamp:    fmt.Sprintf("%d", time.Now().UnixMilli()),
+			ParentMessage:     "message",
+			RequiredApprovals: 2,
+		}
+
+		err := InsertItem(svc, item)
+		assert.NoError(t, err)
+
+		id, err := strconv.Atoi(item.ID)
+		assert.NoError(t, err)
+
+		result, err := GetPullRequest(svc, id, item.PullRequestId)
+		assert.NoError(t, err)
+		assert.Equal(t, item, result)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := GetPullRequest(svc, int(time.Now().UnixMilli()), int(time.Now().UnixMilli()))
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrNoDataFound)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
+
+func TestUpdateApprovals(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	t.Run("successful", func(t *testing.T) {
+		item := &types.TablePullRequestData{
+			ID:             fmt.Sprintf("%d", time.Now().UnixMilli()),
+			PullRequestId:  int(time.Now().UnixMilli()),
+			SlackTimeStamp: fmt.Sprintf("%d", time.Now().UnixMilli()),
+		}
+
+		err := InsertItem(svc, item)
+		assert.NoError(t, err)
+
+		id, err := strconv.Atoi(item.ID)
+		assert.NoError(t, err)
+
+		err = UpdateApprovals(svc, id, item.PullRequestId, 1, 2, 0)
+		assert.NoError(t, err)
+
+		result, err := GetPullRequest(svc, id, item.PullRequestId)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Approvals)
+		assert.Equal(t, 2, result.RequiredApprovals)
+		assert.Equal(t, 1, result.Version)
+
+		// based on the read before the first update
+		err = UpdateApprovals(svc, id, item.PullRequestId, 2, 2, 0)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+
+		err = UpdateApprovals(svc, id, item.PullRequestId, 2, 2, result.Version)
+		assert.NoError(t, err)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
+
+func TestUpdateSlackTimeStamp(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	t.Run("successful", func(t *testing.T) {
+		item := &types.TablePullRequestData{
+			ID:             fmt.Sprintf("%d", time.Now().UnixMilli()),
+			PullRequestId:  int(time.Now().UnixMilli()),
+			SlackTimeStamp: "1.000001",
+		}
+
+		err := InsertItem(svc, item)
+		assert.NoError(t, err)
+
+		id, err := strconv.Atoi(item.ID)
+		assert.NoError(t, err)
+
+		err = UpdateSlackTimeStamp(svc, id, item.PullRequestId, "1.000001", "2.000002")
+		assert.NoError(t, err)
+
+		result, err := GetSlackTimeStamp(svc, id, item.PullRequestId)
+		assert.NoError(t, err)
+		assert.Equal(t, "2.000002", result)
+
+		// a concurrent resend of the old parent message lost
+		err = UpdateSlackTimeStamp(svc, id, item.PullRequestId, "1.000001", "3.000003")
+		assert.ErrorIs(t, err, ErrParentReposted)
+	})
+
+	t.Run("permalink", func(t *testing.T) {
+		id := int(time.Now().UnixMilli())
+		item := &types.TablePullRequestData{
+			ID:             fmt.Sprintf("%d", id),
+			PullRequestId:  id,
+			SlackTimeStamp: "1.000001",
+		}
+		assert.NoError(t, InsertItem(svc, item))
+
+		assert.NoError(t, UpdatePermalink(svc, id, id, "https://acme.slack.com/archives/C1/p1000001"))
+
+		result, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://acme.slack.com/archives/C1/p1000001", result.Permalink)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
+
+func TestUpdateAgeBadge(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	t.Run("successful", func(t *testing.T) {
+		item := &types.TablePullRequestData{
+			ID:             fmt.Sprintf("%d", time.Now().UnixMilli()),
+			PullRequestId:  int(time.Now().UnixMilli()),
+			SlackTimeStamp: fmt.Sprintf("%d", time.Now().UnixMilli()),
+			AgeBadge:       "fresh",
+		}
+
+		err := InsertItem(svc, item)
+		assert.NoError(t, err)
+
+		id, err := strconv.Atoi(item.ID)
+		assert.NoError(t, err)
+
+		err = UpdateAgeBadge(svc, id, item.PullRequestId, "aging")
+		assert.NoError(t, err)
+
+		result, err := GetPullRequest(svc, id, item.PullRequestId)
+		assert.NoError(t, err)
+		assert.Equal(t, "aging", result.AgeBadge)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	svc := DynamoDbConnection()
+	item := &types.TablePullRequestData{}
+
+	// writes are only logged so invalid items don't fail
+	assert.NoError(t, InsertItem(svc, item))
+	assert.NoError(t, ClaimItem(svc, item))
+	assert.NoError(t, InsertItemAudited(svc, item, &types.TableAuditData{}))
+	assert.NoError(t, UpdateApprovals(svc, 0, 0, 1, 1, 0))
+	assert.NoError(t, UpdateSlackTimeStamp(svc, 0, 0, "", ""))
+	assert.NoError(t, UpdatePermalink(svc, 0, 0, ""))
+	assert.NoError(t, UpdateParentMessage(svc, 0, 0, ""))
+	assert.NoError(t, UpdateAgeBadge(svc, 0, 0, ""))
+	assert.NoError(t, UpdateWorkInProgress(svc, 0, 0, false, 0))
+	assert.NoError(t, AddFailedWorkflow(svc, 0, 0, ""))
+	recovered, err := RemoveFailedWorkflow(svc, 0, 0, "")
+	assert.NoError(t, err)
+	assert.False(t, recovered)
+	_, err = UpdatePassedCheck(svc, 0, 0, "", "", true)
+	assert.NoError(t, err)
+	assert.NoError(t, ClearPassedChecks(svc, 0, 0, ""))
+	ready, err := MarkReadyToMerge(svc, 0, 0, "")
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.NoError(t, UpdateDependencies(svc, 0, 0, nil, 0))
+	assert.NoError(t, UpdateChangedFiles(svc, 0, 0, nil, "", 0))
+	assert.NoError(t, UpdateChecklist(svc, 0, 0, 0, 0, 0))
+	merged, err := AddMergedDependency(svc, 0, 0, "")
+	assert.NoError(t, err)
+	assert.True(t, merged)
+	_, err = AddCommentNotification(svc, 0, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, AddPendingComment(svc, 0, 0, ""))
+	assert.NoError(t, ClearPendingComments(svc, 0, 0, 1))
+	assert.NoError(t, UpdateChangesRequested(svc, 0, 0, "", "", "", ""))
+	assert.NoError(t, ClearChangesRequested(svc, 0, 0))
+	assert.NoError(t, UpdateLabels(svc, 0, 0, nil))
+	assert.NoError(t, RecordFirstReview(svc, 0, 0, ""))
+	assert.NoError(t, UpdateSlaBreached(svc, 0, 0, ""))
+	assert.NoError(t, ClaimEscalationLevel(svc, 0, 0, 0))
+	assert.NoError(t, UpdateReminded(svc, 0, 0, ""))
+	assert.NoError(t, DeleteItem(svc, 0, 0))
+	t.Setenv("SOFT_DELETE_DAYS", "0")
+	assert.NoError(t, DeleteItem(svc, 0, 0))
+	assert.NoError(t, RestoreItem(svc, 0, 0))
+	assert.NoError(t, InsertOutOfOffice(svc, &types.TableOutOfOfficeData{}))
+	assert.NoError(t, DeleteOutOfOffice(svc, ""))
+	assert.NoError(t, InsertSnooze(svc, &types.TableSnoozeData{}))
+	assert.NoError(t, DeleteSnooze(svc, "", ""))
+	assert.NoError(t, InsertMute(svc, &types.TableMuteData{}))
+	assert.NoError(t, DeleteMute(svc, "", ""))
+	assert.NoError(t, InsertSubscription(svc, &types.TableSubscriptionData{}))
+	assert.NoError(t, DeleteSubscription(svc, "", ""))
+	assert.NoError(t, InsertEmailPreference(svc, &types.TableEmailPreferenceData{}))
+	assert.NoError(t, DeleteEmailPreference(svc, ""))
+	assert.NoError(t, DeferMentions(svc, &types.TableDeferredMentionData{}))
+	assert.NoError(t, DeleteDeferredMention(svc, "", ""))
+	assert.NoError(t, RemoveDeferredMention(svc, "", "", ""))
+	assert.NoError(t, DeferDigest(svc, &types.TablePendingDigestData{}))
+	assert.NoError(t, DeletePendingDigest(svc, ""))
+	assert.NoError(t, InsertSecurityAlert(svc, &types.TableSecurityAlertData{}))
+	assert.NoError(t, UpdateSecurityReminder(svc, "", ""))
+	assert.NoError(t, DeleteSecurityAlert(svc, ""))
+	assert.NoError(t, InsertCheckpoint(svc, &types.TableCheckpointData{}))
+	_, err = ClaimMessage(svc, &types.TableRecentMessageData{}, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, UpdateMessageTimeStamp(svc, "", ""))
+	assert.NoError(t, DeleteMessageClaim(svc, ""))
+	assert.NoError(t, InsertConfig(svc, &types.TableConfigData{}))
+	assert.NoError(t, InsertAudit(svc, &types.TableAuditData{}))
+	assert.NoError(t, AppendEvent(svc, &types.TableEventData{}))
+	assert.NoError(t, InsertDashboard(svc, &types.TableDashboardData{}))
+	assert.NoError(t, DeleteDashboard(svc, ""))
+	assert.NoError(t, RecordOpened(svc, "", "", 0, "", ""))
+	assert.NoError(t, RecordReview(svc, "", "", ""))
+	assert.NoError(t, RecordClosed(svc, "", "", 0, "", ""))
+	assert.NoError(t, ClaimCommentBatch(svc, &types.TableCommentBatchData{}, 0))
+	_, err = UpdateCommentBatchTimeStamp(svc, "", "")
+	assert.NoError(t, err)
+	assert.NoError(t, DeleteCommentBatch(svc, ""))
+	_, err = AddToCommentBatch(svc, "", "", 0)
+	assert.ErrorIs(t, err, ErrCommentBatchClosed)
+}
@@ -0,0 +1,31 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/apperrors"
+	"slack-pr-lambda/metrics"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+var (
+	requestDuration = metrics.NewHistogram("dynamodb_request_duration_seconds", "DynamoDB request latency by operation.", metrics.DefaultBuckets, "operation")
+	requestErrors   = metrics.NewCounter("dynamodb_errors_total", "Failed DynamoDB requests by operation.", "operation")
+)
+
+// runs once the request is done, retries included
+func observeRequest(r *request.Request) {
+	requestDuration.Observe(time.Since(r.Time).Seconds(), r.Operation.Name)
+	if r.Error != nil {
+		requestErrors.Inc(r.Operation.Name)
+	}
+}
+
+// a request still throttled once its retries are spent, as
+// apperrors.ErrDynamoThrottle. Other errors keep their awserr type for the
+// callers checking their code
+func classifyThrottle(r *request.Request) {
+	if r.Error != nil && request.IsErrorThrottle(r.Error) {
+		r.Error = apperrors.Wrap(apperrors.ErrDynamoThrottle, "dynamodb."+r.Operation.Name, r.Error)
+	}
+}
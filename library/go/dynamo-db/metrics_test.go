@@ -0,0 +1,40 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/apperrors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveRequest(t *testing.T) {
+	r := &request.Request{
+		Operation: &request.Operation{Name: "PutItem"},
+		Time:      time.Now(),
+		Error:     errors.New("ConditionalCheckFailedException"),
+	}
+
+	observeRequest(r)
+
+	assert.Equal(t, uint64(1), requestDuration.Count("PutItem"))
+	assert.Equal(t, float64(1), requestErrors.Value("PutItem"))
+}
+
+func TestClassifyThrottle(t *testing.T) {
+	r := &request.Request{
+		Operation: &request.Operation{Name: "GetItem"},
+		Error:     awserr.New("ProvisionedThroughputExceededException", "rate exceeded", nil),
+	}
+	classifyThrottle(r)
+	assert.ErrorIs(t, r.Error, apperrors.ErrDynamoThrottle)
+	assert.Contains(t, r.Error.Error(), "dynamodb.GetItem")
+
+	conditional := awserr.New("ConditionalCheckFailedException", "failed", nil)
+	r = &request.Request{Operation: &request.Operation{Name: "PutItem"}, Error: conditional}
+	classifyThrottle(r)
+	assert.Equal(t, conditional, r.Error)
+}
@@ -0,0 +1,103 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// slackUserId of a mute of the whole channel
+const MuteChannel = "channel"
+
+func InsertMute(svc *dynamodb.DynamoDB, item *types.TableMuteData) error {
+	tableName := env.GetEnv("MUTE_TABLE_NAME", "Mutes")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteMute(svc *dynamodb.DynamoDB, pullRequest string, slackUserId string) error {
+	tableName := env.GetEnv("MUTE_TABLE_NAME", "Mutes")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("pullRequest", pullRequest), zap.String("slackUserId", slackUserId))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"pullRequest": {
+				S: aws.String(pullRequest),
+			},
+			"slackUserId": {
+				S: aws.String(slackUserId),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+// muted slack user ids of "<repository>#<number>", MuteChannel when the whole
+// channel is muted
+func ListMutes(svc *dynamodb.DynamoDB, pullRequest string) (map[string]bool, error) {
+	tableName := env.GetEnv("MUTE_TABLE_NAME", "Mutes")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("pullRequest = :pullRequest"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pullRequest": {
+				S: aws.String(pullRequest),
+			},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.QueryPages(input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableMuteData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool)
+	for _, record := range records {
+		result[record.SlackUserId] = true
+	}
+
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMute(t *testing.T) {
+	envVars := map[string]string{
+		"MUTE_TABLE_NAME": "Mutes",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	pullRequest := fmt.Sprintf("api#%d", time.Now().UnixMilli())
+
+	t.Run("insert", func(t *testing.T) {
+		assert.NoError(t, InsertMute(svc, &types.TableMuteData{PullRequest: pullRequest, SlackUserId: "U1", MutedAt: time.Now().Format(time.RFC3339)}))
+		assert.NoError(t, InsertMute(svc, &types.TableMuteData{PullRequest: pullRequest, SlackUserId: MuteChannel, MutedAt: time.Now().Format(time.RFC3339)}))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListMutes(svc, pullRequest)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]bool{"U1": true, MuteChannel: true}, result)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, DeleteMute(svc, pullRequest, MuteChannel))
+
+		result, err := ListMutes(svc, pullRequest)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]bool{"U1": true}, result)
+	})
+}
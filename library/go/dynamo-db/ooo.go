@@ -0,0 +1,116 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+func InsertOutOfOffice(svc *dynamodb.DynamoDB, item *types.TableOutOfOfficeData) error {
+	tableName := env.GetEnv("OOO_TABLE_NAME", "OutOfOffice")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetOutOfOffice(svc *dynamodb.DynamoDB, slackUserId string) (*types.TableOutOfOfficeData, error) {
+	tableName := env.GetEnv("OOO_TABLE_NAME", "OutOfOffice")
+
+	result, err := svc.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"slackUserId": {
+				S: aws.String(slackUserId),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TableOutOfOfficeData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// all out of office records keyed by github login
+func ListOutOfOffice(svc *dynamodb.DynamoDB) (map[string]types.TableOutOfOfficeData, error) {
+	tableName := env.GetEnv("OOO_TABLE_NAME", "OutOfOffice")
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableOutOfOfficeData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]types.TableOutOfOfficeData)
+	for _, record := range records {
+		result[record.GithubLogin] = record
+	}
+
+	return result, nil
+}
+
+func DeleteOutOfOffice(svc *dynamodb.DynamoDB, slackUserId string) error {
+	tableName := env.GetEnv("OOO_TABLE_NAME", "OutOfOffice")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("slackUserId", slackUserId))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"slackUserId": {
+				S: aws.String(slackUserId),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutOfOffice(t *testing.T) {
+	envVars := map[string]string{
+		"OOO_TABLE_NAME": "OutOfOffice",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	item := &types.TableOutOfOfficeData{
+		SlackUserId: fmt.Sprintf("U%d", time.Now().UnixMilli()),
+		GithubLogin: fmt.Sprintf("login%d", time.Now().UnixMilli()),
+		StartDate:   "2024-01-01",
+		EndDate:     "2024-01-05",
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		err := InsertOutOfOffice(svc, item)
+		assert.NoError(t, err)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		result, err := GetOutOfOffice(svc, item.SlackUserId)
+		assert.NoError(t, err)
+		assert.Equal(t, item, result)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListOutOfOffice(svc)
+		assert.NoError(t, err)
+		assert.Equal(t, *item, result[item.GithubLogin])
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := DeleteOutOfOffice(svc, item.SlackUserId)
+		assert.NoError(t, err)
+
+		_, err = GetOutOfOffice(svc, item.SlackUserId)
+		assert.Error(t, err)
+	})
+}
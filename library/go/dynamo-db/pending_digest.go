@@ -0,0 +1,89 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// holds the job until the digest window, the first due time is kept so a job
+// triggered again before its delivery is posted once
+func DeferDigest(svc *dynamodb.DynamoDB, item *types.TablePendingDigestData) error {
+	tableName := env.GetEnv("PENDING_DIGEST_TABLE_NAME", "PendingDigests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"job": {
+				S: aws.String(item.Job),
+			},
+		},
+		UpdateExpression: aws.String("SET dueAt = if_not_exists(dueAt, :dueAt)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":dueAt": {N: aws.String(strconv.FormatInt(item.DueAt, 10))},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ListPendingDigests(svc *dynamodb.DynamoDB) ([]types.TablePendingDigestData, error) {
+	tableName := env.GetEnv("PENDING_DIGEST_TABLE_NAME", "PendingDigests")
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TablePendingDigestData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func DeletePendingDigest(svc *dynamodb.DynamoDB, job string) error {
+	tableName := env.GetEnv("PENDING_DIGEST_TABLE_NAME", "PendingDigests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("job", job))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"job": {
+				S: aws.String(job),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
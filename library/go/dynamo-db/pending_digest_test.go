@@ -0,0 +1,42 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingDigests(t *testing.T) {
+	t.Setenv("PENDING_DIGEST_TABLE_NAME", "PendingDigests")
+
+	svc := DynamoDbConnection()
+
+	job := fmt.Sprintf("reminders-%d", time.Now().UnixMilli())
+
+	t.Run("defer", func(t *testing.T) {
+		assert.NoError(t, DeferDigest(svc, &types.TablePendingDigestData{Job: job, DueAt: 100}))
+		assert.NoError(t, DeferDigest(svc, &types.TablePendingDigestData{Job: job, DueAt: 200}))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListPendingDigests(svc)
+		assert.NoError(t, err)
+
+		var record *types.TablePendingDigestData
+		for i := range result {
+			if result[i].Job == job {
+				record = &result[i]
+			}
+		}
+		if assert.NotNil(t, record) {
+			assert.Equal(t, int64(100), record.DueAt)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, DeletePendingDigest(svc, job))
+	})
+}
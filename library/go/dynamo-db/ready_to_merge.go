@@ -0,0 +1,127 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// required check that passed or no longer passes on the head of the pull
+// request, returns the updated record. nil for a run of an older head or an
+// untracked pull request
+func UpdatePassedCheck(svc *dynamodb.DynamoDB, id int, pullRequestId int, check string, headSha string, passed bool) (*types.TablePullRequestData, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("check", check), zap.String("headSha", headSha), zap.Bool("passed", passed))
+		return nil, nil
+	}
+
+	// the set is changed atomically, concurrent check runs don't overwrite each other
+	expression := "ADD passedChecks :check"
+	if !passed {
+		expression = "DELETE passedChecks :check"
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// untracked pull requests are not created by the update, records
+		// without a head take every run
+		ConditionExpression: aws.String("attribute_exists(id) AND (attribute_not_exists(headSha) OR attribute_type(headSha, :null) OR headSha = :headSha)"),
+		UpdateExpression:    aws.String(expression),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":check":   {SS: aws.StringSlice([]string{check})},
+			":headSha": {S: aws.String(headSha)},
+			":null":    {S: aws.String("NULL")},
+		},
+		// the check runs finishing last see the set of each other
+		ReturnValues: aws.String(dynamodb.ReturnValueAllNew),
+	}
+
+	result, err := svc.UpdateItem(input)
+	if err != nil {
+		return nil, ignoreUntracked(nil, err)
+	}
+
+	item := &types.TablePullRequestData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// checks of a new head run again
+func ClearPassedChecks(svc *dynamodb.DynamoDB, id int, pullRequestId int, headSha string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("headSha", headSha), zap.String("remove", "passedChecks"))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("SET headSha = :headSha REMOVE passedChecks"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":headSha": {S: aws.String(headSha)},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
+
+// true for the first caller only, the ready to merge ping is posted once
+func MarkReadyToMerge(svc *dynamodb.DynamoDB, id int, pullRequestId int, readyAt string) (bool, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("readyToMergeAt", readyAt))
+		return true, nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(id) AND attribute_not_exists(readyToMergeAt)"),
+		UpdateExpression:    aws.String("SET readyToMergeAt = :readyAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":readyAt": {S: aws.String(readyAt)},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		// already posted, or the pull request is untracked
+		return false, ignoreUntracked(nil, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,72 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPassedChecks(t *testing.T) {
+	t.Setenv("TABLE_NAME", "PullRequests")
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+		HeadSha:        "abc",
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		result, err := UpdatePassedCheck(svc, id, id, "build", "abc", true)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("passed and failed", func(t *testing.T) {
+		_, err := UpdatePassedCheck(svc, id, id, "build", "abc", true)
+		assert.NoError(t, err)
+		result, err := UpdatePassedCheck(svc, id, id, "test", "abc", true)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"build", "test"}, result.PassedChecks)
+		result, err = UpdatePassedCheck(svc, id, id, "test", "abc", false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"build"}, result.PassedChecks)
+	})
+
+	t.Run("older head", func(t *testing.T) {
+		result, err := UpdatePassedCheck(svc, id, id, "test", "old", true)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("cleared", func(t *testing.T) {
+		assert.NoError(t, ClearPassedChecks(svc, id, id, "def"))
+
+		result, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Empty(t, result.PassedChecks)
+		assert.Equal(t, "def", result.HeadSha)
+	})
+
+	t.Run("ready once", func(t *testing.T) {
+		ready, err := MarkReadyToMerge(svc, id, id, "2024-03-08T12:00:00Z")
+		assert.NoError(t, err)
+		assert.True(t, ready)
+
+		ready, err = MarkReadyToMerge(svc, id, id, "2024-03-08T13:00:00Z")
+		assert.NoError(t, err)
+		assert.False(t, ready)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+var ErrMessagePosted = errors.New("message posted recently")
+
+// claims the message before it is sent, ErrMessagePosted and the earlier
+// message when an identical one was claimed and has not expired yet. Expired
+// items may linger until dynamodb removes them, they are claimed over
+func ClaimMessage(svc *dynamodb.DynamoDB, item *types.TableRecentMessageData, now int64) (*types.TableRecentMessageData, error) {
+	tableName := env.GetEnv("RECENT_MESSAGE_TABLE_NAME", "RecentMessages")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil, nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.PutItem(&dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(tableName),
+		ConditionExpression: aws.String("attribute_not_exists(messageHash) OR expiresAt <= :now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now": {N: aws.String(strconv.FormatInt(now, 10))},
+		},
+	})
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || awsErr.Code() != dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil, err
+	}
+
+	result, err := getItem(svc, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"messageHash": {
+				S: aws.String(item.MessageHash),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	posted := &types.TableRecentMessageData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, posted); err != nil {
+		return nil, err
+	}
+	return posted, ErrMessagePosted
+}
+
+// timestamp of the claimed message once it is sent
+func UpdateMessageTimeStamp(svc *dynamodb.DynamoDB, messageHash string, timeStamp string) error {
+	tableName := env.GetEnv("RECENT_MESSAGE_TABLE_NAME", "RecentMessages")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.String("messageHash", messageHash), zap.String("slackTimeStamp", timeStamp))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"messageHash": {
+				S: aws.String(messageHash),
+			},
+		},
+		UpdateExpression: aws.String("SET slackTimeStamp = :timeStamp"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":timeStamp": {S: aws.String(timeStamp)},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+// releases the claim of a message that failed to send, so a retry can post it
+func DeleteMessageClaim(svc *dynamodb.DynamoDB, messageHash string) error {
+	tableName := env.GetEnv("RECENT_MESSAGE_TABLE_NAME", "RecentMessages")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("messageHash", messageHash))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"messageHash": {
+				S: aws.String(messageHash),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecentMessage(t *testing.T) {
+	t.Setenv("RECENT_MESSAGE_TABLE_NAME", "RecentMessages")
+
+	svc := DynamoDbConnection()
+
+	hash := fmt.Sprintf("hash-%d", time.Now().UnixMilli())
+	now := time.Now().Unix()
+	claim := func(now int64) (*types.TableRecentMessageData, error) {
+		return ClaimMessage(svc, &types.TableRecentMessageData{MessageHash: hash, ExpiresAt: now + 60}, now)
+	}
+
+	t.Run("claim", func(t *testing.T) {
+		posted, err := claim(now)
+		assert.NoError(t, err)
+		assert.Nil(t, posted)
+	})
+
+	t.Run("sent", func(t *testing.T) {
+		assert.NoError(t, UpdateMessageTimeStamp(svc, hash, "1.000001"))
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		posted, err := claim(now + 30)
+		assert.ErrorIs(t, err, ErrMessagePosted)
+		assert.Equal(t, "1.000001", posted.SlackTimeStamp)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := claim(now + 60)
+		assert.NoError(t, err)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		assert.NoError(t, DeleteMessageClaim(svc, hash))
+		_, err := claim(now + 60)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,132 @@
+package dynamodb
+
+import (
+	"net/http"
+	"slack-pr-lambda/env"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// wait before a missing item is read again in the failover region, global
+// tables usually replicate within a second. Replaced in tests
+var replicationLag = 2 * time.Second
+
+// with FAILOVER_REGION (a replica of the global tables), FAILOVER_AFTER_ERRORS
+// consecutive regional errors of the primary region send the requests to the
+// replica for FAILOVER_SECONDS, then the primary region is tried again
+var failover struct {
+	sync.Mutex
+	svc    *dynamodb.DynamoDB
+	errors int
+	until  time.Time
+}
+
+func newClient(sess *session.Session, endpoint string, region string) *dynamodb.DynamoDB {
+	config := &aws.Config{
+		Region:     aws.String(region),
+		HTTPClient: &http.Client{Transport: faultTransport{next: http.DefaultTransport}},
+	}
+	// the default endpoint of the region when empty
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+	}
+
+	svc := dynamodb.New(sess, config)
+	svc.Handlers.Complete.PushBack(observeRequest)
+	// Send returns the error before the Complete handlers run
+	svc.Handlers.AfterRetry.PushBack(classifyThrottle)
+	return svc
+}
+
+// replica client while the primary region is failed over, nil otherwise
+func failoverConnection(sess *session.Session) *dynamodb.DynamoDB {
+	region := env.GetEnv("FAILOVER_REGION", "")
+	if region == "" {
+		return nil
+	}
+
+	failover.Lock()
+	defer failover.Unlock()
+
+	if time.Now().After(failover.until) {
+		return nil
+	}
+	if failover.svc == nil {
+		failover.svc = newClient(sess, env.GetEnv("FAILOVER_DB_ENDPOINT", ""), region)
+	}
+	return failover.svc
+}
+
+// counts the consecutive regional errors of the primary region, any other
+// outcome resets the count
+func observeRegion(r *request.Request) {
+	if env.GetEnv("FAILOVER_REGION", "") == "" {
+		return
+	}
+
+	failover.Lock()
+	defer failover.Unlock()
+
+	if !regionalError(r.Error) {
+		failover.errors = 0
+		return
+	}
+
+	failover.errors++
+	threshold, err := strconv.Atoi(env.GetEnv("FAILOVER_AFTER_ERRORS", "3"))
+	if err != nil || failover.errors < threshold {
+		return
+	}
+
+	seconds, err := strconv.Atoi(env.GetEnv("FAILOVER_SECONDS", "300"))
+	if err != nil {
+		seconds = 300
+	}
+	failover.errors = 0
+	failover.until = time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// the region can't be reached or fails on its side, client errors such as a
+// failed condition are not
+func regionalError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if failure, ok := err.(awserr.RequestFailure); ok {
+		return failure.StatusCode() >= http.StatusInternalServerError
+	}
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code() == request.ErrCodeRequestError || aerr.Code() == request.ErrCodeResponseTimeout
+	}
+	return false
+}
+
+// the replica client serves the requests
+func failedOver(svc *dynamodb.DynamoDB) bool {
+	failover.Lock()
+	defer failover.Unlock()
+	return failover.svc != nil && svc == failover.svc
+}
+
+// strongly consistent read of an item, so a record written by the previous
+// webhook of the pull request is found. Consistency does not cross regions, in
+// the failover region a missing item is read once more after the replication
+// lag before the pull request is treated as untracked
+func getItem(svc *dynamodb.DynamoDB, input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	input.ConsistentRead = aws.Bool(true)
+
+	result, err := svc.GetItem(input)
+	if err != nil || result.Item != nil || !failedOver(svc) {
+		return result, err
+	}
+
+	time.Sleep(replicationLag)
+	return svc.GetItem(input)
+}
@@ -0,0 +1,128 @@
+package dynamodb
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetFailover(t *testing.T) {
+	t.Cleanup(func() {
+		failover.svc = nil
+		failover.errors = 0
+		failover.until = time.Time{}
+	})
+}
+
+func TestRegionalError(t *testing.T) {
+	assert.False(t, regionalError(nil))
+	assert.True(t, regionalError(awserr.New(request.ErrCodeRequestError, "send request failed", errors.New("connection refused"))))
+	assert.True(t, regionalError(awserr.NewRequestFailure(awserr.New("InternalServerError", "internal error", nil), http.StatusInternalServerError, "1")))
+	assert.False(t, regionalError(awserr.NewRequestFailure(awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil), http.StatusBadRequest, "2")))
+	assert.False(t, regionalError(errors.New("other")))
+}
+
+func TestFailover(t *testing.T) {
+	resetFailover(t)
+	t.Setenv("FAILOVER_REGION", "us-west-2")
+	t.Setenv("FAILOVER_DB_ENDPOINT", "http://localhost:8001")
+	t.Setenv("FAILOVER_AFTER_ERRORS", "2")
+
+	primary, err := Connection()
+	assert.NoError(t, err)
+
+	down := &request.Request{Error: awserr.New(request.ErrCodeRequestError, "send request failed", errors.New("connection refused"))}
+	observeRegion(down)
+	observeRegion(&request.Request{})
+	observeRegion(down)
+
+	svc, err := Connection()
+	assert.NoError(t, err)
+	assert.Same(t, primary, svc, "a success resets the consecutive errors")
+
+	observeRegion(down)
+	svc, err = Connection()
+	assert.NoError(t, err)
+	assert.NotSame(t, primary, svc)
+	assert.Equal(t, "us-west-2", aws.StringValue(svc.Config.Region))
+	assert.Equal(t, "http://localhost:8001", svc.Endpoint)
+	assert.True(t, failedOver(svc))
+
+	// the primary region is tried again after FAILOVER_SECONDS
+	failover.until = time.Now().Add(-time.Second)
+	svc, err = Connection()
+	assert.NoError(t, err)
+	assert.Same(t, primary, svc)
+}
+
+func TestFailoverDisabled(t *testing.T) {
+	resetFailover(t)
+	t.Setenv("FAILOVER_REGION", "")
+	t.Setenv("FAILOVER_AFTER_ERRORS", "1")
+
+	observeRegion(&request.Request{Error: awserr.New(request.ErrCodeRequestError, "send request failed", errors.New("connection refused"))})
+
+	svc, err := Connection()
+	assert.NoError(t, err)
+	assert.False(t, failedOver(svc))
+}
+
+func TestGetItemReplicationLag(t *testing.T) {
+	resetFailover(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	original := replicationLag
+	replicationLag = time.Millisecond
+	t.Cleanup(func() {
+		replicationLag = original
+	})
+
+	reads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reads++
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		// replicated by the second read of the failover region
+		if reads <= 2 {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.Write([]byte(`{"Item": {"id": {"S": "1"}, "pullRequestId": {"N": "7"}, "slackTimeStamp": {"S": "1.000001"}}}`))
+	}))
+	defer server.Close()
+
+	input := func() *dynamodb.GetItemInput {
+		return &dynamodb.GetItemInput{
+			TableName: aws.String("PullRequests"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id":            {S: aws.String("1")},
+				"pullRequestId": {N: aws.String("7")},
+			},
+		}
+	}
+
+	sess := session.Must(session.NewSession())
+	primary := newClient(sess, server.URL, "us-east-1")
+
+	result, err := getItem(primary, input())
+	assert.NoError(t, err)
+	assert.Nil(t, result.Item, "the primary region is read once")
+	assert.Equal(t, 1, reads)
+
+	failover.svc = newClient(sess, server.URL, "us-west-2")
+	read := input()
+	result, err = getItem(failover.svc, read)
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Item)
+	assert.Equal(t, 3, reads)
+	assert.True(t, aws.BoolValue(read.ConsistentRead))
+}
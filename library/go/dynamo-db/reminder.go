@@ -0,0 +1,40 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// time of the last reviewer reminder, the next one follows REMINDER_INTERVAL_HOURS later
+func UpdateReminded(svc *dynamodb.DynamoDB, id int, pullRequestId int, remindedAt string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("remindedAt", remindedAt))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("SET remindedAt = :remindedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":remindedAt": {S: aws.String(remindedAt)},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReminded(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		assert.NoError(t, UpdateReminded(svc, id, id, "2024-03-08T10:00:00Z"))
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("update", func(t *testing.T) {
+		assert.NoError(t, UpdateReminded(svc, id, id, "2024-03-08T10:00:00Z"))
+		assert.NoError(t, UpdateReminded(svc, id, id, "2024-03-09T10:00:00Z"))
+
+		pullRequest, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-03-09T10:00:00Z", pullRequest.RemindedAt)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// lifecycle state of a review metrics record
+const (
+	StateOpen      = "open"
+	StateMerged    = "merged"
+	StateAbandoned = "abandoned"
+)
+
+// first opening of "<repository>#<number>", reopening keeps the original time
+func RecordOpened(svc *dynamodb.DynamoDB, pullRequest string, repository string, number int, author string, openedAt string) error {
+	return updateReviewMetrics(svc, pullRequest,
+		"SET repository = :repository, #number = :number, author = :author, openedAt = if_not_exists(openedAt, :openedAt), #state = :state REMOVE closedAt, mergedAt",
+		map[string]*dynamodb.AttributeValue{
+			":state":      {S: aws.String(StateOpen)},
+			":repository": {S: aws.String(repository)},
+			":number":     {N: aws.String(fmt.Sprintf("%d", number))},
+			":author":     {S: aws.String(author)},
+			":openedAt":   {S: aws.String(openedAt)},
+		},
+	)
+}
+
+// adds the reviewer, the first review time is kept
+func RecordReview(svc *dynamodb.DynamoDB, pullRequest string, reviewer string, reviewedAt string) error {
+	return updateReviewMetrics(svc, pullRequest,
+		"SET firstReviewAt = if_not_exists(firstReviewAt, :reviewedAt) ADD reviewers :reviewer",
+		map[string]*dynamodb.AttributeValue{
+			":reviewedAt": {S: aws.String(reviewedAt)},
+			":reviewer":   {SS: []*string{aws.String(reviewer)}},
+		},
+	)
+}
+
+// mergedAt is empty for pull requests closed without merging, those are abandoned
+func RecordClosed(svc *dynamodb.DynamoDB, pullRequest string, repository string, number int, closedAt string, mergedAt string) error {
+	expression := "SET repository = :repository, #number = :number, closedAt = :closedAt, #state = :state"
+	values := map[string]*dynamodb.AttributeValue{
+		":repository": {S: aws.String(repository)},
+		":number":     {N: aws.String(fmt.Sprintf("%d", number))},
+		":closedAt":   {S: aws.String(closedAt)},
+		":state":      {S: aws.String(StateAbandoned)},
+	}
+	if mergedAt != "" {
+		expression += ", mergedAt = :mergedAt"
+		values[":mergedAt"] = &dynamodb.AttributeValue{S: aws.String(mergedAt)}
+		values[":state"] = &dynamodb.AttributeValue{S: aws.String(StateMerged)}
+	}
+
+	return updateReviewMetrics(svc, pullRequest, expression, values)
+}
+
+func updateReviewMetrics(svc *dynamodb.DynamoDB, pullRequest string, expression string, values map[string]*dynamodb.AttributeValue) error {
+	tableName := env.GetEnv("REVIEW_METRICS_TABLE_NAME", "ReviewMetrics")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.String("pullRequest", pullRequest), zap.String("expression", expression))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"pullRequest": {
+				S: aws.String(pullRequest),
+			},
+		},
+		UpdateExpression:          aws.String(expression),
+		ExpressionAttributeValues: values,
+	}
+	// "number" and "state" are reserved words
+	for _, name := range []string{"number", "state"} {
+		if strings.Contains(expression, "#"+name) {
+			if input.ExpressionAttributeNames == nil {
+				input.ExpressionAttributeNames = map[string]*string{}
+			}
+			input.ExpressionAttributeNames["#"+name] = aws.String(name)
+		}
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// records closed before the state was recorded are derived from the timestamps
+func ReviewMetricsState(record types.TableReviewMetricsData) string {
+	switch {
+	case record.State != "":
+		return record.State
+	case record.MergedAt != "":
+		return StateMerged
+	case record.ClosedAt != "":
+		return StateAbandoned
+	}
+	return StateOpen
+}
+
+func ListReviewMetrics(svc *dynamodb.DynamoDB) ([]types.TableReviewMetricsData, error) {
+	tableName := env.GetEnv("REVIEW_METRICS_TABLE_NAME", "ReviewMetrics")
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableReviewMetricsData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func GetReviewMetrics(svc *dynamodb.DynamoDB, pullRequest string) (*types.TableReviewMetricsData, error) {
+	tableName := env.GetEnv("REVIEW_METRICS_TABLE_NAME", "ReviewMetrics")
+
+	result, err := svc.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"pullRequest": {
+				S: aws.String(pullRequest),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TableReviewMetricsData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
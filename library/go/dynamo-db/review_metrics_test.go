@@ -0,0 +1,85 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewMetrics(t *testing.T) {
+	envVars := map[string]string{
+		"REVIEW_METRICS_TABLE_NAME": "ReviewMetrics",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	number := int(time.Now().UnixMilli() % 1000000)
+	pullRequest := fmt.Sprintf("api#%d", number)
+
+	t.Run("record", func(t *testing.T) {
+		assert.NoError(t, RecordOpened(svc, pullRequest, "api", number, "alice", "2024-03-01T10:00:00Z"))
+		assert.NoError(t, RecordReview(svc, pullRequest, "bob", "2024-03-01T12:00:00Z"))
+		assert.NoError(t, RecordReview(svc, pullRequest, "carol", "2024-03-02T09:00:00Z"))
+		assert.NoError(t, RecordClosed(svc, pullRequest, "api", number, "2024-03-03T08:00:00Z", "2024-03-03T08:00:00Z"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListReviewMetrics(svc)
+		assert.NoError(t, err)
+
+		var record *types.TableReviewMetricsData
+		for i := range result {
+			if result[i].PullRequest == pullRequest {
+				record = &result[i]
+			}
+		}
+		if assert.NotNil(t, record) {
+			assert.Equal(t, "2024-03-01T10:00:00Z", record.OpenedAt)
+			assert.Equal(t, "2024-03-01T12:00:00Z", record.FirstReviewAt)
+			assert.Equal(t, "2024-03-03T08:00:00Z", record.MergedAt)
+			assert.Equal(t, StateMerged, record.State)
+			assert.ElementsMatch(t, []string{"bob", "carol"}, record.Reviewers)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		record, err := GetReviewMetrics(svc, pullRequest)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", record.Author)
+
+		_, err = GetReviewMetrics(svc, "unknown#0")
+		assert.ErrorIs(t, err, ErrNoDataFound)
+	})
+
+	t.Run("reopened", func(t *testing.T) {
+		assert.NoError(t, RecordOpened(svc, pullRequest, "api", number, "alice", "2024-03-04T10:00:00Z"))
+	})
+
+	t.Run("abandoned", func(t *testing.T) {
+		closed := fmt.Sprintf("api#%d", number+1)
+		assert.NoError(t, RecordClosed(svc, closed, "api", number+1, "2024-03-05T08:00:00Z", ""))
+
+		result, err := ListReviewMetrics(svc)
+		assert.NoError(t, err)
+		for _, record := range result {
+			if record.PullRequest == closed {
+				assert.Equal(t, StateAbandoned, record.State)
+				assert.Equal(t, number+1, record.Number)
+			}
+		}
+	})
+}
+
+func TestReviewMetricsState(t *testing.T) {
+	assert.Equal(t, StateAbandoned, ReviewMetricsState(types.TableReviewMetricsData{State: StateAbandoned, ClosedAt: "2024-03-03T08:00:00Z"}))
+	assert.Equal(t, StateMerged, ReviewMetricsState(types.TableReviewMetricsData{MergedAt: "2024-03-03T08:00:00Z", ClosedAt: "2024-03-03T08:00:00Z"}))
+	assert.Equal(t, StateAbandoned, ReviewMetricsState(types.TableReviewMetricsData{ClosedAt: "2024-03-03T08:00:00Z"}))
+	assert.Equal(t, StateOpen, ReviewMetricsState(types.TableReviewMetricsData{}))
+}
@@ -0,0 +1,121 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// "<login>=<thread ts>" entry of a review ping
+func ReviewPing(login string, timeStamp string) string {
+	return login + "=" + timeStamp
+}
+
+// login and thread ts of a review ping entry
+func ParseReviewPing(ping string) (string, string) {
+	login, timeStamp, _ := strings.Cut(ping, "=")
+	return login, timeStamp
+}
+
+// thread message pinging the logins for a review, kept until they review
+func AddReviewPings(svc *dynamodb.DynamoDB, id int, pullRequestId int, logins []string, timeStamp string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+	if len(logins) == 0 || timeStamp == "" {
+		return nil
+	}
+
+	pings := []string{}
+	for _, login := range logins {
+		pings = append(pings, ReviewPing(login, timeStamp))
+	}
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Strings("reviewPings", pings))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// untracked pull requests are not created by the update
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("ADD reviewPings :pings"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pings": {SS: aws.StringSlice(pings)},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
+
+// removes the pings of a reviewer who reviewed, returns the removed pings and
+// those still waiting. Logins compare case-insensitively like on GitHub
+func RemoveReviewPings(svc *dynamodb.DynamoDB, id int, pullRequestId int, login string) ([]string, []string, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	item, err := GetPullRequest(svc, id, pullRequestId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	removed := []string{}
+	remaining := []string{}
+	for _, ping := range item.ReviewPings {
+		if pinged, _ := ParseReviewPing(ping); strings.EqualFold(pinged, login) {
+			removed = append(removed, ping)
+		} else {
+			remaining = append(remaining, ping)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, remaining, nil
+	}
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Strings("removedReviewPings", removed))
+		return removed, remaining, nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		UpdateExpression: aws.String("DELETE reviewPings :pings"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pings": {SS: aws.StringSlice(removed)},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllNew),
+	}
+
+	out, err := svc.UpdateItem(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// pings added by a concurrent delivery since the read
+	updated := types.TablePullRequestData{}
+	if err := dynamodbattribute.UnmarshalMap(out.Attributes, &updated); err != nil {
+		return nil, nil, err
+	}
+	return removed, updated.ReviewPings, nil
+}
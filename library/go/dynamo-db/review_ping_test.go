@@ -0,0 +1,59 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewPings(t *testing.T) {
+	t.Setenv("TABLE_NAME", "PullRequests")
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		assert.NoError(t, AddReviewPings(svc, id, id, []string{"bob"}, "1.1"))
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("pinged", func(t *testing.T) {
+		assert.NoError(t, AddReviewPings(svc, id, id, []string{"bob", "carol"}, "1.1"))
+		assert.NoError(t, AddReviewPings(svc, id, id, []string{"bob"}, "1.2"))
+
+		result, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"bob=1.1", "carol=1.1", "bob=1.2"}, result.ReviewPings)
+	})
+
+	t.Run("reviewed", func(t *testing.T) {
+		removed, remaining, err := RemoveReviewPings(svc, id, id, "Bob")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"bob=1.1", "bob=1.2"}, removed)
+		assert.Equal(t, []string{"carol=1.1"}, remaining)
+
+		removed, _, err = RemoveReviewPings(svc, id, id, "bob")
+		assert.NoError(t, err)
+		assert.Empty(t, removed)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
+
+func TestParseReviewPing(t *testing.T) {
+	login, timeStamp := ParseReviewPing(ReviewPing("bob", "1712345678.000100"))
+	assert.Equal(t, "bob", login)
+	assert.Equal(t, "1712345678.000100", timeStamp)
+}
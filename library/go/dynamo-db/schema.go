@@ -0,0 +1,197 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/env"
+	"sort"
+)
+
+// key attribute of a table or index, Type is "S" or "N"
+type KeyAttribute struct {
+	Name string
+	Type string
+}
+
+type Index struct {
+	Name     string
+	HashKey  KeyAttribute
+	RangeKey *KeyAttribute
+}
+
+// table used by this library, named by the EnvName variable or DefaultName.
+// Capacity is the provisioned read and write units of the table and its indexes,
+// TtlAttribute the unix timestamp after which dynamodb removes the item, empty
+// when items don't expire
+type Table struct {
+	EnvName      string
+	DefaultName  string
+	HashKey      KeyAttribute
+	RangeKey     *KeyAttribute
+	Indexes      []Index
+	Capacity     int
+	TtlAttribute string
+}
+
+func (t Table) Name() string {
+	return env.GetEnv(t.EnvName, t.DefaultName)
+}
+
+// every table read or written by this library, kept in line with the table
+// names and keys of the functions by TestTables
+func Tables() []Table {
+	return []Table{
+		{
+			EnvName:     "TABLE_NAME",
+			DefaultName: "PullRequests",
+			HashKey:     KeyAttribute{Name: "id", Type: "S"},
+			RangeKey:    &KeyAttribute{Name: "pullRequestId", Type: "N"},
+			Indexes: []Index{
+				{Name: "PullRequestIdIndex", HashKey: KeyAttribute{Name: "pullRequestId", Type: "N"}},
+			},
+			Capacity: 5,
+		},
+		{
+			EnvName:     "OOO_TABLE_NAME",
+			DefaultName: "OutOfOffice",
+			HashKey:     KeyAttribute{Name: "slackUserId", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:      "SNOOZE_TABLE_NAME",
+			DefaultName:  "Snoozes",
+			HashKey:      KeyAttribute{Name: "id", Type: "S"},
+			RangeKey:     &KeyAttribute{Name: "githubLogin", Type: "S"},
+			TtlAttribute: "snoozeUntil",
+			Capacity:     5,
+		},
+		{
+			EnvName:     "CONFIG_TABLE_NAME",
+			DefaultName: "Config",
+			HashKey:     KeyAttribute{Name: "id", Type: "S"},
+			RangeKey:    &KeyAttribute{Name: "version", Type: "N"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "AUDIT_TABLE_NAME",
+			DefaultName: "Audit",
+			HashKey:     KeyAttribute{Name: "pullRequest", Type: "S"},
+			RangeKey:    &KeyAttribute{Name: "sentAt", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "DASHBOARD_TABLE_NAME",
+			DefaultName: "Dashboards",
+			HashKey:     KeyAttribute{Name: "channel", Type: "S"},
+			Capacity:    1,
+		},
+		{
+			EnvName:     "REVIEW_METRICS_TABLE_NAME",
+			DefaultName: "ReviewMetrics",
+			HashKey:     KeyAttribute{Name: "pullRequest", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "CONTRIBUTOR_TABLE_NAME",
+			DefaultName: "Contributors",
+			HashKey:     KeyAttribute{Name: "repository", Type: "S"},
+			RangeKey:    &KeyAttribute{Name: "author", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:      "COMMENT_BATCH_TABLE_NAME",
+			DefaultName:  "CommentBatches",
+			HashKey:      KeyAttribute{Name: "id", Type: "S"},
+			TtlAttribute: "expiresAt",
+			Capacity:     5,
+		},
+		{
+			EnvName:     "MUTE_TABLE_NAME",
+			DefaultName: "Mutes",
+			HashKey:     KeyAttribute{Name: "pullRequest", Type: "S"},
+			RangeKey:    &KeyAttribute{Name: "slackUserId", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "SUBSCRIPTION_TABLE_NAME",
+			DefaultName: "Subscriptions",
+			HashKey:     KeyAttribute{Name: "pullRequest", Type: "S"},
+			RangeKey:    &KeyAttribute{Name: "slackUserId", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "EMAIL_TABLE_NAME",
+			DefaultName: "EmailPreferences",
+			HashKey:     KeyAttribute{Name: "githubLogin", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "DEFERRED_MENTION_TABLE_NAME",
+			DefaultName: "DeferredMentions",
+			HashKey:     KeyAttribute{Name: "pullRequest", Type: "S"},
+			RangeKey:    &KeyAttribute{Name: "threadTimeStamp", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "PENDING_DIGEST_TABLE_NAME",
+			DefaultName: "PendingDigests",
+			HashKey:     KeyAttribute{Name: "job", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "EVENT_TABLE_NAME",
+			DefaultName: "Events",
+			HashKey:     KeyAttribute{Name: "pullRequest", Type: "S"},
+			RangeKey:    &KeyAttribute{Name: "receivedAt", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:     "SECURITY_ALERT_TABLE_NAME",
+			DefaultName: "SecurityAlerts",
+			HashKey:     KeyAttribute{Name: "alert", Type: "S"},
+			Capacity:    5,
+		},
+		{
+			EnvName:      "CHECKPOINT_TABLE_NAME",
+			DefaultName:  "Checkpoints",
+			HashKey:      KeyAttribute{Name: "eventId", Type: "S"},
+			RangeKey:     &KeyAttribute{Name: "step", Type: "S"},
+			TtlAttribute: "expiresAt",
+			Capacity:     5,
+		},
+		{
+			EnvName:      "RECENT_MESSAGE_TABLE_NAME",
+			DefaultName:  "RecentMessages",
+			HashKey:      KeyAttribute{Name: "messageHash", Type: "S"},
+			TtlAttribute: "expiresAt",
+			Capacity:     5,
+		},
+	}
+}
+
+// operations called by this library, the paginated ones need the plain action
+var actions = map[string]string{
+	"BatchGetItem":   "dynamodb:BatchGetItem",
+	"BatchWriteItem": "dynamodb:BatchWriteItem",
+	"DeleteItem":     "dynamodb:DeleteItem",
+	"GetItem":        "dynamodb:GetItem",
+	"PutItem":        "dynamodb:PutItem",
+	"Query":          "dynamodb:Query",
+	"QueryPages":     "dynamodb:Query",
+	"ScanPages":      "dynamodb:Scan",
+	// transactions are authorized by the actions of their items
+	"TransactWriteItems": "dynamodb:PutItem",
+	"UpdateItem":         "dynamodb:UpdateItem",
+}
+
+// IAM actions needed on the tables, sorted
+func Actions() []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, action := range actions {
+		if !seen[action] {
+			seen[action] = true
+			result = append(result, action)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,81 @@
+package dynamodb
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// table names and dynamodb calls of the library sources
+func sourceUsage(t *testing.T) (map[string]string, map[string]bool) {
+	files, err := filepath.Glob("*.go")
+	assert.NoError(t, err)
+
+	tableName := regexp.MustCompile(`env\.GetEnv\("([A-Z_]*TABLE_NAME)", "([^"]*)"\)`)
+	call := regexp.MustCompile(`svc\.([A-Za-z]+)\(`)
+
+	tables := map[string]string{}
+	calls := map[string]bool{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		source, err := os.ReadFile(file)
+		assert.NoError(t, err)
+
+		for _, match := range tableName.FindAllStringSubmatch(string(source), -1) {
+			tables[match[1]] = match[2]
+		}
+		for _, match := range call.FindAllStringSubmatch(string(source), -1) {
+			calls[match[1]] = true
+		}
+	}
+	return tables, calls
+}
+
+func TestTables(t *testing.T) {
+	tables, calls := sourceUsage(t)
+
+	catalog := map[string]string{}
+	for _, table := range Tables() {
+		catalog[table.EnvName] = table.DefaultName
+	}
+	assert.Equal(t, tables, catalog, "every table of the library must be described by Tables()")
+
+	for call := range calls {
+		_, ok := actions[call]
+		assert.True(t, ok, "svc.%s has no IAM action", call)
+	}
+	for call := range actions {
+		assert.True(t, calls[call], "svc.%s is no longer called", call)
+	}
+}
+
+func TestActions(t *testing.T) {
+	assert.Equal(t, []string{
+		"dynamodb:BatchGetItem",
+		"dynamodb:BatchWriteItem",
+		"dynamodb:DeleteItem",
+		"dynamodb:GetItem",
+		"dynamodb:PutItem",
+		"dynamodb:Query",
+		"dynamodb:Scan",
+		"dynamodb:UpdateItem",
+	}, Actions())
+}
+
+func TestTableName(t *testing.T) {
+	table := Table{EnvName: "MUTE_TABLE_NAME", DefaultName: "Mutes"}
+
+	// restored by t.Setenv once the test ends
+	t.Setenv("MUTE_TABLE_NAME", "")
+	os.Unsetenv("MUTE_TABLE_NAME")
+	assert.Equal(t, "Mutes", table.Name())
+
+	t.Setenv("MUTE_TABLE_NAME", "dev-mutes")
+	assert.Equal(t, "dev-mutes", table.Name())
+}
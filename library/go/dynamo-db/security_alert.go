@@ -0,0 +1,145 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// key of an alert of the repository, kind is e.g. "dependabot"
+func SecurityAlertKey(repository string, kind string, number int64) string {
+	return fmt.Sprintf("%s#%s-%d", repository, kind, number)
+}
+
+func InsertSecurityAlert(svc *dynamodb.DynamoDB, item *types.TableSecurityAlertData) error {
+	tableName := env.GetEnv("SECURITY_ALERT_TABLE_NAME", "SecurityAlerts")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetSecurityAlert(svc *dynamodb.DynamoDB, alert string) (*types.TableSecurityAlertData, error) {
+	tableName := env.GetEnv("SECURITY_ALERT_TABLE_NAME", "SecurityAlerts")
+
+	result, err := getItem(svc, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"alert": {
+				S: aws.String(alert),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, ErrNoDataFound
+	}
+
+	item := &types.TableSecurityAlertData{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func ListSecurityAlerts(svc *dynamodb.DynamoDB) ([]types.TableSecurityAlertData, error) {
+	tableName := env.GetEnv("SECURITY_ALERT_TABLE_NAME", "SecurityAlerts")
+
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableSecurityAlertData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// time of the last SLA reminder, the next one follows SECURITY_REMINDER_HOURS later
+func UpdateSecurityReminder(svc *dynamodb.DynamoDB, alert string, remindedAt string) error {
+	tableName := env.GetEnv("SECURITY_ALERT_TABLE_NAME", "SecurityAlerts")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.String("alert", alert), zap.String("remindedAt", remindedAt))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"alert": {
+				S: aws.String(alert),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(alert)"),
+		UpdateExpression:    aws.String("SET remindedAt = :remindedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":remindedAt": {S: aws.String(remindedAt)},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+func DeleteSecurityAlert(svc *dynamodb.DynamoDB, alert string) error {
+	tableName := env.GetEnv("SECURITY_ALERT_TABLE_NAME", "SecurityAlerts")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("alert", alert))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"alert": {
+				S: aws.String(alert),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityAlertKey(t *testing.T) {
+	assert.Equal(t, "api#dependabot-3", SecurityAlertKey("api", "dependabot", 3))
+}
+
+func TestSecurityAlerts(t *testing.T) {
+	t.Setenv("SECURITY_ALERT_TABLE_NAME", "SecurityAlerts")
+
+	svc := DynamoDbConnection()
+
+	alert := SecurityAlertKey("api", "dependabot", time.Now().UnixMilli())
+	item := &types.TableSecurityAlertData{
+		Alert:          alert,
+		Kind:           "dependabot",
+		Repository:     "api",
+		Number:         3,
+		Severity:       "high",
+		Summary:        "Prototype pollution in lodash",
+		Package:        "lodash",
+		CreatedAt:      "2024-03-08T10:00:00Z",
+		SlackTimeStamp: "1.000001",
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		assert.NoError(t, InsertSecurityAlert(svc, item))
+	})
+
+	t.Run("remind", func(t *testing.T) {
+		assert.NoError(t, UpdateSecurityReminder(svc, alert, "2024-03-09T10:00:00Z"))
+		assert.Error(t, UpdateSecurityReminder(svc, fmt.Sprintf("%s-missing", alert), "2024-03-09T10:00:00Z"))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		result, err := GetSecurityAlert(svc, alert)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-03-09T10:00:00Z", result.RemindedAt)
+		assert.Equal(t, "1.000001", result.SlackTimeStamp)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListSecurityAlerts(svc)
+		assert.NoError(t, err)
+
+		found := false
+		for _, record := range result {
+			found = found || record.Alert == alert
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, DeleteSecurityAlert(svc, alert))
+
+		_, err := GetSecurityAlert(svc, alert)
+		assert.ErrorIs(t, err, ErrNoDataFound)
+	})
+}
@@ -0,0 +1,98 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// labels after a labeled / unlabeled event, matched against the SLA rules
+func UpdateLabels(svc *dynamodb.DynamoDB, id int, pullRequestId int, labels []string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Strings("labels", labels))
+		return nil
+	}
+
+	value, err := dynamodbattribute.Marshal(labels)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// untracked pull requests are not created by the update
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("SET labels = :labels"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":labels": value,
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(bumpVersion(input)))
+}
+
+// the first review ends the first response SLA, later reviews keep the time
+func RecordFirstReview(svc *dynamodb.DynamoDB, id int, pullRequestId int, reviewedAt string) error {
+	return updateOnce(svc, id, pullRequestId, "firstReviewAt", reviewedAt)
+}
+
+// a breach is reported once per pull request
+func UpdateSlaBreached(svc *dynamodb.DynamoDB, id int, pullRequestId int, breachedAt string) error {
+	return updateOnce(svc, id, pullRequestId, "slaBreachedAt", breachedAt)
+}
+
+func updateOnce(svc *dynamodb.DynamoDB, id int, pullRequestId int, attribute string, value string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String(attribute, value))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("SET " + attribute + " = if_not_exists(" + attribute + ", :value)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":value": {
+				S: aws.String(value),
+			},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
+
+// pull requests opened before they were tracked have no record to update
+func ignoreUntracked(_ *dynamodb.UpdateItemOutput, err error) error {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSla(t *testing.T) {
+	envVars := map[string]string{
+		"TABLE_NAME": "PullRequests",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		assert.NoError(t, UpdateLabels(svc, id, id, []string{"hotfix"}))
+		assert.NoError(t, RecordFirstReview(svc, id, id, "2024-03-08T11:00:00Z"))
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("update", func(t *testing.T) {
+		assert.NoError(t, UpdateLabels(svc, id, id, []string{"hotfix", "bug"}))
+		assert.NoError(t, RecordFirstReview(svc, id, id, "2024-03-08T11:00:00Z"))
+		assert.NoError(t, RecordFirstReview(svc, id, id, "2024-03-08T12:00:00Z"))
+		assert.NoError(t, UpdateSlaBreached(svc, id, id, "2024-03-08T10:30:00Z"))
+
+		pullRequest, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"hotfix", "bug"}, pullRequest.Labels)
+		assert.Equal(t, "2024-03-08T11:00:00Z", pullRequest.FirstReviewAt)
+		assert.Equal(t, "2024-03-08T10:30:00Z", pullRequest.SlaBreachedAt)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
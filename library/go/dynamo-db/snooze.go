@@ -0,0 +1,73 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+func InsertSnooze(svc *dynamodb.DynamoDB, item *types.TableSnoozeData) error {
+	tableName := env.GetEnv("SNOOZE_TABLE_NAME", "Snoozes")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// snooze-until unix timestamps of a pull request keyed by github login
+func ListSnoozes(svc *dynamodb.DynamoDB, id string) (map[string]int64, error) {
+	tableName := env.GetEnv("SNOOZE_TABLE_NAME", "Snoozes")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("id = :id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":id": {
+				S: aws.String(id),
+			},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.QueryPages(input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableSnoozeData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64)
+	for _, record := range records {
+		result[record.GithubLogin] = record.SnoozeUntil
+	}
+
+	return result, nil
+}
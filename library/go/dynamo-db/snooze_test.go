@@ -0,0 +1,45 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnooze(t *testing.T) {
+	envVars := map[string]string{
+		"SNOOZE_TABLE_NAME": "Snoozes",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	item := &types.TableSnoozeData{
+		ID:          fmt.Sprintf("%d", time.Now().UnixMilli()),
+		GithubLogin: "rodentskie",
+		SnoozeUntil: time.Now().Add(4 * time.Hour).Unix(),
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		err := InsertSnooze(svc, item)
+		assert.NoError(t, err)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListSnoozes(svc, item.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, item.SnoozeUntil, result[item.GithubLogin])
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := ListSnoozes(svc, "unknown")
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
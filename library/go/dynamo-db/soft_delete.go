@@ -0,0 +1,170 @@
+package dynamodb
+
+import (
+	"errors"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// days a deleted pull request record is kept before the purge job removes it,
+// SOFT_DELETE_DAYS (default 30). 0 deletes the records right away
+func SoftDeleteDays() int {
+	days, err := strconv.Atoi(env.GetEnv("SOFT_DELETE_DAYS", "30"))
+	if err != nil || days < 0 {
+		return 30
+	}
+	return days
+}
+
+// the record is kept with deletedAt, reads ignore it until it is restored or
+// purged
+func softDelete(svc *dynamodb.DynamoDB, tableName string, id int, pullRequestId int) error {
+	deletedAt := time.Now().UTC().Format(time.RFC3339)
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("deletedAt", deletedAt))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// untracked pull requests are not created by the update
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("SET deletedAt = :deletedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":deletedAt": {
+				S: aws.String(deletedAt),
+			},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
+
+// soft-deleted record, found again by the reads. ErrNoDataFound when the
+// record is not deleted or already purged
+func RestoreItem(svc *dynamodb.DynamoDB, id int, pullRequestId int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("deletedAt", ""))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(deletedAt)"),
+		UpdateExpression:    aws.String("REMOVE deletedAt"),
+	}
+
+	_, err := svc.UpdateItem(input)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return ErrNoDataFound
+	}
+	return err
+}
+
+// removes the records deleted more than SoftDeleteDays before now, returns the
+// number of purged records
+func PurgeDeleted(svc *dynamodb.DynamoDB, now time.Time) (int, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+	before := now.UTC().AddDate(0, 0, -SoftDeleteDays()).Format(time.RFC3339)
+
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(tableName),
+		FilterExpression: aws.String("deletedAt < :before"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":before": {
+				S: aws.String(before),
+			},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	records := []types.TablePullRequestData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, record := range records {
+		if dryrun.Enabled() {
+			dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("id", record.ID), zap.Int("pullRequestId", record.PullRequestId))
+			purged++
+			continue
+		}
+
+		_, err := svc.DeleteItem(&dynamodb.DeleteItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {
+					S: aws.String(record.ID),
+				},
+				"pullRequestId": {
+					N: aws.String(strconv.Itoa(record.PullRequestId)),
+				},
+			},
+			// restored, or stored again by a reopen, since the scan
+			ConditionExpression: aws.String("deletedAt = :deletedAt"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":deletedAt": {
+					S: aws.String(record.DeletedAt),
+				},
+			},
+		})
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// pull request records that are not soft-deleted
+func live(items []types.TablePullRequestData) []types.TablePullRequestData {
+	result := []types.TablePullRequestData{}
+	for _, item := range items {
+		if item.DeletedAt == "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
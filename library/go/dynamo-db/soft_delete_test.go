@@ -0,0 +1,84 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftDeleteDays(t *testing.T) {
+	t.Setenv("SOFT_DELETE_DAYS", "")
+	assert.Equal(t, 30, SoftDeleteDays())
+
+	t.Setenv("SOFT_DELETE_DAYS", "0")
+	assert.Equal(t, 0, SoftDeleteDays())
+
+	t.Setenv("SOFT_DELETE_DAYS", "-1")
+	assert.Equal(t, 30, SoftDeleteDays())
+}
+
+func TestLive(t *testing.T) {
+	items := []types.TablePullRequestData{
+		{ID: "1"},
+		{ID: "2", DeletedAt: "2024-03-10T12:00:00Z"},
+	}
+	assert.Equal(t, []types.TablePullRequestData{{ID: "1"}}, live(items))
+}
+
+func TestSoftDelete(t *testing.T) {
+	t.Setenv("TABLE_NAME", "PullRequests")
+	t.Setenv("SOFT_DELETE_DAYS", "30")
+
+	svc := DynamoDbConnection()
+
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", time.Now().UnixMilli()),
+		PullRequestId:  int(time.Now().UnixMilli()),
+		SlackTimeStamp: fmt.Sprintf("%d", time.Now().UnixMilli()),
+	}
+	assert.NoError(t, InsertItem(svc, item))
+
+	id, err := strconv.Atoi(item.ID)
+	assert.NoError(t, err)
+
+	t.Run("deleted", func(t *testing.T) {
+		assert.NoError(t, DeleteItem(svc, id, item.PullRequestId))
+
+		_, err := GetPullRequest(svc, id, item.PullRequestId)
+		assert.ErrorIs(t, err, ErrNoDataFound)
+		_, err = GetSlackTimeStamp(svc, id, item.PullRequestId)
+		assert.ErrorIs(t, err, ErrNoDataFound)
+
+		// kept until SOFT_DELETE_DAYS passed
+		purged, err := PurgeDeleted(svc, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, purged)
+	})
+
+	t.Run("restored", func(t *testing.T) {
+		assert.NoError(t, RestoreItem(svc, id, item.PullRequestId))
+
+		result, err := GetPullRequest(svc, id, item.PullRequestId)
+		assert.NoError(t, err)
+		assert.Equal(t, item.SlackTimeStamp, result.SlackTimeStamp)
+
+		assert.ErrorIs(t, RestoreItem(svc, id, item.PullRequestId), ErrNoDataFound)
+	})
+
+	t.Run("purged", func(t *testing.T) {
+		assert.NoError(t, DeleteItem(svc, id, item.PullRequestId))
+
+		purged, err := PurgeDeleted(svc, time.Now().AddDate(0, 0, 31))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, purged, 1)
+		assert.ErrorIs(t, RestoreItem(svc, id, item.PullRequestId), ErrNoDataFound)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
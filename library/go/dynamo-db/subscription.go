@@ -0,0 +1,99 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+func InsertSubscription(svc *dynamodb.DynamoDB, item *types.TableSubscriptionData) error {
+	tableName := env.GetEnv("SUBSCRIPTION_TABLE_NAME", "Subscriptions")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.put_item", zap.String("table", tableName), zap.Any("item", item))
+		return nil
+	}
+
+	av, err := dynamodbattribute.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	insert := &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.PutItem(insert); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteSubscription(svc *dynamodb.DynamoDB, pullRequest string, slackUserId string) error {
+	tableName := env.GetEnv("SUBSCRIPTION_TABLE_NAME", "Subscriptions")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("pullRequest", pullRequest), zap.String("slackUserId", slackUserId))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"pullRequest": {
+				S: aws.String(pullRequest),
+			},
+			"slackUserId": {
+				S: aws.String(slackUserId),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+// subscribed slack user ids of "<repository>#<number>"
+func ListSubscriptions(svc *dynamodb.DynamoDB, pullRequest string) ([]string, error) {
+	tableName := env.GetEnv("SUBSCRIPTION_TABLE_NAME", "Subscriptions")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("pullRequest = :pullRequest"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pullRequest": {
+				S: aws.String(pullRequest),
+			},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.QueryPages(input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableSubscriptionData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, record := range records {
+		result = append(result, record.SlackUserId)
+	}
+
+	return result, nil
+}
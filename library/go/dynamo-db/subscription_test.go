@@ -0,0 +1,42 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscription(t *testing.T) {
+	envVars := map[string]string{
+		"SUBSCRIPTION_TABLE_NAME": "Subscriptions",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	pullRequest := fmt.Sprintf("api#%d", time.Now().UnixMilli())
+
+	t.Run("insert", func(t *testing.T) {
+		assert.NoError(t, InsertSubscription(svc, &types.TableSubscriptionData{PullRequest: pullRequest, SlackUserId: "U1", SubscribedAt: time.Now().Format(time.RFC3339)}))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := ListSubscriptions(svc, pullRequest)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"U1"}, result)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, DeleteSubscription(svc, pullRequest, "U1"))
+
+		result, err := ListSubscriptions(svc, pullRequest)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
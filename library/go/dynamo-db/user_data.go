@@ -0,0 +1,208 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"go.uber.org/zap"
+)
+
+// the tables are keyed by pull request, rows of a user are found with a scan
+func scanWhere(svc *dynamodb.DynamoDB, tableName string, filter string, attribute string, value string) ([]map[string]*dynamodb.AttributeValue, error) {
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(tableName),
+		FilterExpression: aws.String(filter),
+		ExpressionAttributeNames: map[string]*string{
+			"#attribute": aws.String(attribute),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":value": {
+				S: aws.String(value),
+			},
+		},
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err := svc.ScanPages(input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		items = append(items, output.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// "<repository>#<number>" of the pull requests muted by the slack user
+func ListUserMutes(svc *dynamodb.DynamoDB, slackUserId string) ([]string, error) {
+	tableName := env.GetEnv("MUTE_TABLE_NAME", "Mutes")
+
+	items, err := scanWhere(svc, tableName, "#attribute = :value", "slackUserId", slackUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableMuteData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, record := range records {
+		result = append(result, record.PullRequest)
+	}
+
+	return result, nil
+}
+
+// "<repository>#<number>" of the pull requests watched by the slack user
+func ListUserSubscriptions(svc *dynamodb.DynamoDB, slackUserId string) ([]string, error) {
+	tableName := env.GetEnv("SUBSCRIPTION_TABLE_NAME", "Subscriptions")
+
+	items, err := scanWhere(svc, tableName, "#attribute = :value", "slackUserId", slackUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableSubscriptionData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, record := range records {
+		result = append(result, record.PullRequest)
+	}
+
+	return result, nil
+}
+
+// record ids of the pull requests snoozed by the github user
+func ListUserSnoozes(svc *dynamodb.DynamoDB, githubLogin string) ([]string, error) {
+	tableName := env.GetEnv("SNOOZE_TABLE_NAME", "Snoozes")
+
+	items, err := scanWhere(svc, tableName, "#attribute = :value", "githubLogin", githubLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableSnoozeData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, record := range records {
+		result = append(result, record.ID)
+	}
+
+	return result, nil
+}
+
+func DeleteSnooze(svc *dynamodb.DynamoDB, id string, githubLogin string) error {
+	tableName := env.GetEnv("SNOOZE_TABLE_NAME", "Snoozes")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.delete_item", zap.String("table", tableName), zap.String("id", id), zap.String("githubLogin", githubLogin))
+		return nil
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(id),
+			},
+			"githubLogin": {
+				S: aws.String(githubLogin),
+			},
+		},
+		TableName: aws.String(tableName),
+	}
+
+	if _, err := svc.DeleteItem(input); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deferred mentions of threads waiting to mention the slack user
+func ListUserDeferredMentions(svc *dynamodb.DynamoDB, slackUserId string) ([]types.TableDeferredMentionData, error) {
+	tableName := env.GetEnv("DEFERRED_MENTION_TABLE_NAME", "DeferredMentions")
+
+	items, err := scanWhere(svc, tableName, "contains(#attribute, :value)", "slackUserIds", slackUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableDeferredMentionData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// ids of the review comment batches of the github user, "<repository>#<number>#<login>"
+func ListUserCommentBatches(svc *dynamodb.DynamoDB, githubLogin string) ([]string, error) {
+	tableName := env.GetEnv("COMMENT_BATCH_TABLE_NAME", "CommentBatches")
+
+	items, err := scanWhere(svc, tableName, "contains(#attribute, :value)", "id", "#"+githubLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableCommentBatchData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, record := range records {
+		// contains also matches a login the github login is a prefix of
+		if strings.HasSuffix(record.ID, "#"+githubLogin) {
+			result = append(result, record.ID)
+		}
+	}
+
+	return result, nil
+}
+
+// tracked pull requests whose parent message contains value, e.g. a "<@U123>" mention
+func ListPullRequestsContaining(svc *dynamodb.DynamoDB, value string) ([]types.TablePullRequestData, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	items, err := scanWhere(svc, tableName, "contains(#attribute, :value)", "parentMessage", value)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TablePullRequestData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// audit records of every pull request whose text contains value, e.g. a "<@U123>" mention
+func ListAuditsContaining(svc *dynamodb.DynamoDB, value string) ([]types.TableAuditData, error) {
+	tableName := env.GetEnv("AUDIT_TABLE_NAME", "Audit")
+
+	items, err := scanWhere(svc, tableName, "contains(#attribute, :value)", "text", value)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []types.TableAuditData{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,88 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserData(t *testing.T) {
+	envVars := map[string]string{
+		"MUTE_TABLE_NAME":             "Mutes",
+		"SUBSCRIPTION_TABLE_NAME":     "Subscriptions",
+		"SNOOZE_TABLE_NAME":           "Snoozes",
+		"AUDIT_TABLE_NAME":            "Audit",
+		"DEFERRED_MENTION_TABLE_NAME": "DeferredMentions",
+		"COMMENT_BATCH_TABLE_NAME":    "CommentBatches",
+	}
+
+	for key, value := range envVars {
+		t.Setenv(key, value)
+	}
+
+	svc := DynamoDbConnection()
+
+	suffix := time.Now().UnixMilli()
+	pullRequest := fmt.Sprintf("api#%d", suffix)
+	slackUserId := fmt.Sprintf("U%d", suffix)
+	githubLogin := fmt.Sprintf("login-%d", suffix)
+
+	t.Run("insert", func(t *testing.T) {
+		now := time.Now().Format(time.RFC3339)
+		assert.NoError(t, InsertMute(svc, &types.TableMuteData{PullRequest: pullRequest, SlackUserId: slackUserId, MutedAt: now}))
+		assert.NoError(t, InsertSubscription(svc, &types.TableSubscriptionData{PullRequest: pullRequest, SlackUserId: slackUserId, SubscribedAt: now}))
+		assert.NoError(t, InsertSnooze(svc, &types.TableSnoozeData{ID: pullRequest, GithubLogin: githubLogin, SnoozeUntil: time.Now().Unix()}))
+		assert.NoError(t, InsertAudit(svc, &types.TableAuditData{PullRequest: pullRequest, SentAt: now, Text: fmt.Sprintf("review requested <@%s>", slackUserId)}))
+		assert.NoError(t, DeferMentions(svc, &types.TableDeferredMentionData{PullRequest: pullRequest, ThreadTimeStamp: "1000.0001", SlackUserIds: []string{slackUserId, "U0"}, DueAt: time.Now().Unix()}))
+		assert.NoError(t, ClaimCommentBatch(svc, &types.TableCommentBatchData{ID: pullRequest + "#" + githubLogin, Comments: 1, StartedAt: time.Now().Unix()}, 0))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mutes, err := ListUserMutes(svc, slackUserId)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{pullRequest}, mutes)
+
+		subscriptions, err := ListUserSubscriptions(svc, slackUserId)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{pullRequest}, subscriptions)
+
+		snoozes, err := ListUserSnoozes(svc, githubLogin)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{pullRequest}, snoozes)
+
+		audits, err := ListAuditsContaining(svc, fmt.Sprintf("<@%s>", slackUserId))
+		assert.NoError(t, err)
+		assert.Len(t, audits, 1)
+
+		deferred, err := ListUserDeferredMentions(svc, slackUserId)
+		assert.NoError(t, err)
+		assert.Len(t, deferred, 1)
+
+		batches, err := ListUserCommentBatches(svc, githubLogin)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{pullRequest + "#" + githubLogin}, batches)
+
+		batches, err = ListUserCommentBatches(svc, "login")
+		assert.NoError(t, err)
+		assert.Empty(t, batches)
+	})
+
+	t.Run("remove deferred mention", func(t *testing.T) {
+		assert.NoError(t, RemoveDeferredMention(svc, pullRequest, "1000.0001", slackUserId))
+
+		deferred, err := ListUserDeferredMentions(svc, slackUserId)
+		assert.NoError(t, err)
+		assert.Empty(t, deferred)
+	})
+
+	t.Run("delete snooze", func(t *testing.T) {
+		assert.NoError(t, DeleteSnooze(svc, pullRequest, githubLogin))
+
+		snoozes, err := ListUserSnoozes(svc, githubLogin)
+		assert.NoError(t, err)
+		assert.Empty(t, snoozes)
+	})
+}
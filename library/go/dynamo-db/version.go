@@ -0,0 +1,60 @@
+package dynamodb
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+var ErrVersionConflict = errors.New("pull request record changed concurrently")
+
+// the update bumps the version of the pull request record, so read-modify-write
+// updates based on an older read fail. Atomic counters and sets don't need it
+func bumpVersion(input *dynamodb.UpdateItemInput) *dynamodb.UpdateItemInput {
+	expression := aws.StringValue(input.UpdateExpression)
+	if strings.Contains(expression, "ADD ") {
+		expression = strings.Replace(expression, "ADD ", "ADD version :one, ", 1)
+	} else {
+		expression += " ADD version :one"
+	}
+	input.UpdateExpression = aws.String(expression)
+
+	if input.ExpressionAttributeValues == nil {
+		input.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{}
+	}
+	input.ExpressionAttributeValues[":one"] = &dynamodb.AttributeValue{N: aws.String("1")}
+	return input
+}
+
+// optimistic lock of a read-modify-write update, it applies while the record
+// still has the version it was read with. Records stored before versions
+// have none and match version 0
+func versioned(input *dynamodb.UpdateItemInput, version int) *dynamodb.UpdateItemInput {
+	condition := "attribute_exists(id) AND version = :version"
+	if version == 0 {
+		condition = "attribute_exists(id) AND (attribute_not_exists(version) OR version = :version)"
+	}
+	input.ConditionExpression = aws.String(condition)
+	// tells a changed record from an untracked one
+	input.ReturnValuesOnConditionCheckFailure = aws.String(dynamodb.ReturnValuesOnConditionCheckFailureAllOld)
+
+	bumpVersion(input)
+	input.ExpressionAttributeValues[":version"] = &dynamodb.AttributeValue{N: aws.String(strconv.Itoa(version))}
+	return input
+}
+
+// ErrVersionConflict when the record changed since it was read, untracked
+// pull requests are ignored
+func versionConflict(_ *dynamodb.UpdateItemOutput, err error) error {
+	var failed *dynamodb.ConditionalCheckFailedException
+	if errors.As(err, &failed) {
+		if failed.Item != nil {
+			return ErrVersionConflict
+		}
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersioned(t *testing.T) {
+	input := versioned(&dynamodb.UpdateItemInput{
+		UpdateExpression: aws.String("SET approvals = :approvals"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":approvals": {N: aws.String("1")},
+		},
+	}, 3)
+
+	assert.Equal(t, "SET approvals = :approvals ADD version :one", aws.StringValue(input.UpdateExpression))
+	assert.Equal(t, "attribute_exists(id) AND version = :version", aws.StringValue(input.ConditionExpression))
+	assert.Equal(t, "3", aws.StringValue(input.ExpressionAttributeValues[":version"].N))
+	assert.Equal(t, "1", aws.StringValue(input.ExpressionAttributeValues[":one"].N))
+
+	// records stored before versions
+	input = versioned(&dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET labels = :labels")}, 0)
+	assert.Equal(t, "attribute_exists(id) AND (attribute_not_exists(version) OR version = :version)", aws.StringValue(input.ConditionExpression))
+}
+
+func TestBumpVersion(t *testing.T) {
+	input := bumpVersion(&dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET ageBadge = :ageBadge ADD reminders :one")})
+	assert.Equal(t, "SET ageBadge = :ageBadge ADD version :one, reminders :one", aws.StringValue(input.UpdateExpression))
+}
+
+func TestVersionConflict(t *testing.T) {
+	assert.NoError(t, versionConflict(nil, nil))
+
+	// untracked pull request
+	assert.NoError(t, versionConflict(nil, &dynamodb.ConditionalCheckFailedException{}))
+
+	changed := &dynamodb.ConditionalCheckFailedException{
+		Item: map[string]*dynamodb.AttributeValue{"version": {N: aws.String("4")}},
+	}
+	assert.ErrorIs(t, versionConflict(nil, changed), ErrVersionConflict)
+
+	other := errors.New("other")
+	assert.ErrorIs(t, versionConflict(nil, other), other)
+}
@@ -0,0 +1,41 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// WIP prefix of the title added or removed by an edit
+func UpdateWorkInProgress(svc *dynamodb.DynamoDB, id int, pullRequestId int, workInProgress bool, version int) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.Bool("workInProgress", workInProgress), zap.Int("version", version))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		UpdateExpression: aws.String("SET workInProgress = :workInProgress"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":workInProgress": {
+				BOOL: aws.Bool(workInProgress),
+			},
+		},
+	}
+
+	return versionConflict(svc.UpdateItem(versioned(input, version)))
+}
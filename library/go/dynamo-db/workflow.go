@@ -0,0 +1,76 @@
+package dynamodb
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// workflow whose run failed on the pull request, kept until a run passes
+func AddFailedWorkflow(svc *dynamodb.DynamoDB, id int, pullRequestId int, workflow string) error {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("failedWorkflow", workflow))
+		return nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// untracked pull requests are not created by the update
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		UpdateExpression:    aws.String("ADD failedWorkflows :workflow"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":workflow": {SS: aws.StringSlice([]string{workflow})},
+		},
+	}
+
+	return ignoreUntracked(svc.UpdateItem(input))
+}
+
+// removes a workflow that passed again, true when its previous run failed
+func RemoveFailedWorkflow(svc *dynamodb.DynamoDB, id int, pullRequestId int, workflow string) (bool, error) {
+	tableName := env.GetEnv("TABLE_NAME", "PullRequests")
+
+	if dryrun.Enabled() {
+		dryrun.Log("dynamodb.update_item", zap.String("table", tableName), zap.Int("id", id), zap.Int("pullRequestId", pullRequestId), zap.String("recoveredWorkflow", workflow))
+		return false, nil
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(strconv.Itoa(id)),
+			},
+			"pullRequestId": {
+				N: aws.String(strconv.Itoa(pullRequestId)),
+			},
+		},
+		// only failed workflows, so a passing run is announced once
+		ConditionExpression: aws.String("contains(failedWorkflows, :name)"),
+		UpdateExpression:    aws.String("DELETE failedWorkflows :workflow"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":name":     {S: aws.String(workflow)},
+			":workflow": {SS: aws.StringSlice([]string{workflow})},
+		},
+	}
+
+	if _, err := svc.UpdateItem(input); err != nil {
+		// the workflow did not fail before, or the pull request is untracked
+		return false, ignoreUntracked(nil, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,52 @@
+package dynamodb
+
+import (
+	"fmt"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailedWorkflows(t *testing.T) {
+	t.Setenv("TABLE_NAME", "PullRequests")
+
+	svc := DynamoDbConnection()
+
+	id := int(time.Now().UnixMilli())
+	item := &types.TablePullRequestData{
+		ID:             fmt.Sprintf("%d", id),
+		PullRequestId:  id,
+		SlackTimeStamp: fmt.Sprintf("%d", id),
+	}
+
+	t.Run("untracked", func(t *testing.T) {
+		assert.NoError(t, AddFailedWorkflow(svc, id, id, "CI"))
+	})
+
+	assert.NoError(t, InsertItem(svc, item))
+
+	t.Run("failed", func(t *testing.T) {
+		assert.NoError(t, AddFailedWorkflow(svc, id, id, "CI"))
+		assert.NoError(t, AddFailedWorkflow(svc, id, id, "Lint"))
+
+		result, err := GetPullRequest(svc, id, id)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"CI", "Lint"}, result.FailedWorkflows)
+	})
+
+	t.Run("recovered once", func(t *testing.T) {
+		recovered, err := RemoveFailedWorkflow(svc, id, id, "CI")
+		assert.NoError(t, err)
+		assert.True(t, recovered)
+
+		recovered, err = RemoveFailedWorkflow(svc, id, id, "CI")
+		assert.NoError(t, err)
+		assert.False(t, recovered)
+	})
+
+	if err := DeleteAllItem(svc); err != nil {
+		t.Errorf("error delete all item %v", err)
+	}
+}
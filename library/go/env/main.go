@@ -9,3 +9,11 @@ func GetEnv(key, fallback string) string {
 	}
 	return value
 }
+
+// ENVIRONMENT of the deployment, "dev", "staging" or "prod" (default)
+func Stage() string {
+	if stage := GetEnv("ENVIRONMENT", ""); stage != "" {
+		return stage
+	}
+	return "prod"
+}
@@ -31,3 +31,15 @@ func TestEnv(t *testing.T) {
 	}
 
 }
+
+func TestStage(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "")
+	if stage := Stage(); stage != "prod" {
+		t.Errorf("Expected prod by default, got %s", stage)
+	}
+
+	t.Setenv("ENVIRONMENT", "staging")
+	if stage := Stage(); stage != "staging" {
+		t.Errorf("Expected staging, got %s", stage)
+	}
+}
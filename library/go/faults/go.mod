@@ -0,0 +1,3 @@
+module slack-pr-lambda/faults
+
+go 1.22
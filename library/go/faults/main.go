@@ -0,0 +1,89 @@
+package faults
+
+import (
+	"slack-pr-lambda/env"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// injected failure of the calls of a target ("slack" or "dynamodb"), every
+// operation of it when operation is empty. remaining is the calls still
+// failing, -1 for all of them
+type rule struct {
+	target    string
+	operation string
+	fault     string
+	remaining int
+}
+
+var state struct {
+	sync.Mutex
+	spec  string
+	rules []*rule
+}
+
+// fault of the next call of the operation of target, empty when the call goes
+// through. FAULTS ("slack.chat.postMessage=429x2,dynamodb=throttle") lists
+// "<target>[.<operation>]=<fault>[x<count>]", the first matching entry with
+// calls left applies. Never outside of ENVIRONMENT prod
+func Next(target string, operation string) string {
+	if env.Stage() == "prod" {
+		return ""
+	}
+	spec := env.GetEnv("FAULTS", "")
+	if spec == "" {
+		return ""
+	}
+
+	state.Lock()
+	defer state.Unlock()
+
+	if spec != state.spec {
+		state.spec = spec
+		state.rules = parse(spec)
+	}
+
+	for _, r := range state.rules {
+		if r.target != target || (r.operation != "" && r.operation != operation) || r.remaining == 0 {
+			continue
+		}
+		if r.remaining > 0 {
+			r.remaining--
+		}
+		return r.fault
+	}
+	return ""
+}
+
+// counts start over, e.g. between the cases of an integration test
+func Reset() {
+	state.Lock()
+	defer state.Unlock()
+	state.spec = ""
+	state.rules = nil
+}
+
+// invalid entries are skipped
+func parse(spec string) []*rule {
+	rules := []*rule{}
+	for _, entry := range strings.Split(spec, ",") {
+		call, fault, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || call == "" || fault == "" {
+			continue
+		}
+
+		r := &rule{remaining: -1}
+		r.target, r.operation, _ = strings.Cut(call, ".")
+		r.fault = fault
+		if name, count, ok := strings.Cut(fault, "x"); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n <= 0 {
+				continue
+			}
+			r.fault, r.remaining = name, n
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
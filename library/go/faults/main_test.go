@@ -0,0 +1,64 @@
+package faults
+
+import "testing"
+
+func TestNext(t *testing.T) {
+	t.Setenv("FAULTS", "slack.chat.postMessage=429x2,slack=500x1,dynamodb=throttle,bad,dynamodb=500xz")
+
+	t.Run("prod", func(t *testing.T) {
+		Reset()
+		t.Setenv("ENVIRONMENT", "")
+		if fault := Next("slack", "chat.postMessage"); fault != "" {
+			t.Errorf("Expected no fault in prod, got %s", fault)
+		}
+	})
+
+	t.Setenv("ENVIRONMENT", "staging")
+
+	t.Run("counts", func(t *testing.T) {
+		Reset()
+		for i, expected := range []string{"429", "429", "500", ""} {
+			if fault := Next("slack", "chat.postMessage"); fault != expected {
+				t.Errorf("call %d: got %q want %q", i, fault, expected)
+			}
+		}
+	})
+
+	t.Run("operations", func(t *testing.T) {
+		Reset()
+		if fault := Next("slack", "chat.update"); fault != "500" {
+			t.Errorf("Expected the target wide fault, got %q", fault)
+		}
+		if fault := Next("slack", "chat.update"); fault != "" {
+			t.Errorf("Expected the fault spent, got %q", fault)
+		}
+		for i := 0; i < 3; i++ {
+			if fault := Next("dynamodb", "PutItem"); fault != "throttle" {
+				t.Errorf("Expected every dynamodb call throttled, got %q", fault)
+			}
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		Reset()
+		Next("slack", "chat.postMessage")
+		Next("slack", "chat.postMessage")
+		Reset()
+		if fault := Next("slack", "chat.postMessage"); fault != "429" {
+			t.Errorf("Expected the counts to start over, got %q", fault)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	rules := parse("slack.chat.postMessage=429x2, dynamodb=throttle,=500,slack=,slack=500x0")
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	if r := rules[0]; r.target != "slack" || r.operation != "chat.postMessage" || r.fault != "429" || r.remaining != 2 {
+		t.Errorf("got %+v", *r)
+	}
+	if r := rules[1]; r.target != "dynamodb" || r.operation != "" || r.fault != "throttle" || r.remaining != -1 {
+		t.Errorf("got %+v", *r)
+	}
+}
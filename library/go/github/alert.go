@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slack-pr-lambda/env"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// open pull request of a code scanning ref, refs/pull/<number>/merge or the
+// head branch refs/heads/<branch>. Nil when there is none
+func GetRefPullRequest(repo string, ref string) (*github.PullRequest, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	if number, ok := refPullRequestNumber(ref); ok {
+		pr, _, err := client.PullRequests.Get(ctx, owner, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		if pr.GetState() != "open" {
+			return nil, nil
+		}
+		return pr, nil
+	}
+
+	branch, ok := strings.CutPrefix(ref, "refs/heads/")
+	if !ok || branch == "" {
+		return nil, nil
+	}
+
+	prs, _, err := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+		State: "open",
+		Head:  fmt.Sprintf("%s:%s", owner, branch),
+	})
+	if err != nil || len(prs) == 0 {
+		return nil, err
+	}
+	return prs[0], nil
+}
+
+// number of refs/pull/<number>/merge and refs/pull/<number>/head
+func refPullRequestNumber(ref string) (int, bool) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "refs" || parts[1] != "pull" {
+		return 0, false
+	}
+
+	number, err := strconv.Atoi(parts[2])
+	if err != nil || number <= 0 {
+		return 0, false
+	}
+	return number, true
+}
+
+// location of a leaked secret, commits carry the sha it was pushed in
+type secretLocation struct {
+	Type    string `json:"type"`
+	Details struct {
+		CommitSha string `json:"commit_sha"`
+	} `json:"details"`
+}
+
+// open pull request a leaked secret was pushed to, found through the commits
+// of its locations. Nil when there is none
+func GetSecretAlertPullRequest(repo string, number int) (*github.PullRequest, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	// not part of go-github v39
+	req, err := client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/secret-scanning/alerts/%d/locations", owner, repo, number), nil)
+	if err != nil {
+		return nil, err
+	}
+	locations := []secretLocation{}
+	if _, err := client.Do(ctx, req, &locations); err != nil {
+		return nil, err
+	}
+
+	for _, location := range locations {
+		if location.Type != "commit" || location.Details.CommitSha == "" {
+			continue
+		}
+
+		prs, _, err := client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repo, location.Details.CommitSha, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			if pr.GetState() == "open" {
+				return pr, nil
+			}
+		}
+	}
+	return nil, nil
+}
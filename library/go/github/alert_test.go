@@ -0,0 +1,38 @@
+package github
+
+import "testing"
+
+func TestGetRefPullRequest(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestGetSecretAlertPullRequest(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestRefPullRequestNumber(t *testing.T) {
+	tests := []struct {
+		ref    string
+		number int
+		ok     bool
+	}{
+		{"refs/pull/7/merge", 7, true},
+		{"refs/pull/12/head", 12, true},
+		{"refs/heads/feature", 0, false},
+		{"refs/pull/x/merge", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		number, ok := refPullRequestNumber(tt.ref)
+		if number != tt.number || ok != tt.ok {
+			t.Errorf("%q: got %d %v", tt.ref, number, ok)
+		}
+	}
+}
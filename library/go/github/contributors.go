@@ -0,0 +1,51 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/env"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// number of the first pull request of every author of the repository, open or
+// closed, bots left out. Seeds the contributors before the first-time
+// contributor celebration is enabled
+func GetPullRequestAuthors(repo string) (map[string]int, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	authors := map[string]int{}
+	opts := &github.PullRequestListOptions{
+		State:       "all",
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := client.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		firstPullRequests(page, authors)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return authors, nil
+}
+
+func firstPullRequests(pulls []*github.PullRequest, authors map[string]int) {
+	for _, pull := range pulls {
+		user := pull.GetUser()
+		if user.GetLogin() == "" || user.GetType() == "Bot" {
+			continue
+		}
+		if number, ok := authors[user.GetLogin()]; !ok || pull.GetNumber() < number {
+			authors[user.GetLogin()] = pull.GetNumber()
+		}
+	}
+}
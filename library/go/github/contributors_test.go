@@ -0,0 +1,28 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+)
+
+func TestFirstPullRequests(t *testing.T) {
+	pull := func(number int, login string, userType string) *github.PullRequest {
+		return &github.PullRequest{Number: github.Int(number), User: &github.User{Login: github.String(login), Type: github.String(userType)}}
+	}
+
+	authors := map[string]int{"alice": 12}
+	firstPullRequests([]*github.PullRequest{
+		pull(3, "alice", "User"),
+		pull(5, "bob", "User"),
+		pull(4, "bob", "User"),
+		pull(6, "dependabot[bot]", "Bot"),
+		{Number: github.Int(7)},
+	}, authors)
+
+	expected := map[string]int{"alice": 3, "bob": 4}
+	if !reflect.DeepEqual(authors, expected) {
+		t.Errorf("Expected %v, got %v", expected, authors)
+	}
+}
@@ -0,0 +1,34 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/env"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// paths of the files changed by the pull request, GitHub lists up to 3000
+func GetPullRequestFiles(repo string, prNumber int) ([]string, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	files := []string{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.PullRequests.ListFiles(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range page {
+			files = append(files, file.GetFilename())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return files, nil
+}
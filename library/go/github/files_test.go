@@ -0,0 +1,10 @@
+package github
+
+import "testing"
+
+func TestGetPullRequestFiles(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
@@ -4,6 +4,7 @@ go 1.22.1
 
 require (
 	github.com/google/go-github/v39 v39.2.0
+	go.uber.org/zap v1.27.0
 	golang.org/x/oauth2 v0.18.0
 )
 
@@ -11,6 +12,7 @@ require (
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/crypto v0.21.0 // indirect
 	google.golang.org/appengine v1.6.8 // indirect
 	google.golang.org/protobuf v1.33.0 // indirect
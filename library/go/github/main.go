@@ -2,28 +2,204 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slack-pr-lambda/apperrors"
 	"slack-pr-lambda/env"
+	"strings"
 
 	"github.com/google/go-github/v39/github"
 	"golang.org/x/oauth2"
 )
 
-func GetPullRequestId(repo string, prNumber int) (int64, error) {
-	token := env.GetEnv("GITHUB_TOKEN", "token")
-	owner := env.GetEnv("GITHUB_OWNER", "owner")
-
-	ctx := context.Background()
+// client of the instance authenticated with the token of the owner
+func githubClient(ctx context.Context, owner string) *github.Client {
 	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
+		&oauth2.Token{AccessToken: token(owner)},
 	)
 	tc := oauth2.NewClient(ctx, ts)
 
-	client := github.NewClient(tc)
+	// GitHub Enterprise Server, e.g. https://github.example.com/api/v3/
+	if apiUrl := env.GetEnv("GITHUB_API_URL", ""); apiUrl != "" {
+		uploadUrl := env.GetEnv("GITHUB_UPLOAD_URL", apiUrl)
+		if client, err := github.NewEnterpriseClient(apiUrl, uploadUrl, tc); err == nil {
+			return client
+		}
+	}
+
+	return github.NewClient(tc)
+}
+
+// token of the owner or else of the instance host in GITHUB_TOKENS, e.g.
+// "acme=ghp_1,github.example.com=ghp_2", GITHUB_TOKEN for the others
+func token(owner string) string {
+	tokens := map[string]string{}
+	for _, entry := range strings.Split(env.GetEnv("GITHUB_TOKENS", ""), ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if ok && key != "" && value != "" {
+			tokens[key] = value
+		}
+	}
+
+	if token, ok := tokens[owner]; ok {
+		return token
+	}
+	if instance, err := url.Parse(BaseUrl()); err == nil {
+		if token, ok := tokens[instance.Host]; ok {
+			return token
+		}
+	}
+	return env.GetEnv("GITHUB_TOKEN", "token")
+}
+
+// web url of the instance, GITHUB_URL for GitHub Enterprise Server, github.com when unset or empty
+func BaseUrl() string {
+	if baseUrl := env.GetEnv("GITHUB_URL", ""); baseUrl != "" {
+		return strings.TrimSuffix(baseUrl, "/")
+	}
+	return "https://github.com"
+}
+
+func PullRequestUrl(repo string, prNumber int) string {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+	return fmt.Sprintf("%s/%s/%s/pull/%d", BaseUrl(), owner, repo, prNumber)
+}
+
+// web url of an "org/repo#123" pull request reference
+func ReferenceUrl(reference string) string {
+	return fmt.Sprintf("%s/%s", BaseUrl(), strings.Replace(reference, "#", "/pull/", 1))
+}
+
+func GetPullRequestId(repo string, prNumber int) (int64, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
 
 	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
 	if err != nil {
-		return 0, err
+		return 0, notFound("github.pull_request", err)
 	}
 
 	return pr.GetID(), nil
 }
+
+// a 404 answer as apperrors.ErrPRNotFound, other errors are returned as is
+func notFound(op string, err error) error {
+	var response *github.ErrorResponse
+	if errors.As(err, &response) && response.Response != nil && response.Response.StatusCode == http.StatusNotFound {
+		return apperrors.Wrap(apperrors.ErrPRNotFound, op, err)
+	}
+	return err
+}
+
+// github logins of the reviewers that have not submitted a review yet
+func GetRequestedReviewers(repo string, prNumber int) ([]string, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	reviewers, _, err := client.PullRequests.ListReviewers(ctx, owner, repo, prNumber, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	logins := []string{}
+	for _, user := range reviewers.Users {
+		logins = append(logins, user.GetLogin())
+	}
+
+	return logins, nil
+}
+
+// approving review count required by the branch protection rule, 0 when the branch is not protected
+func GetRequiredApprovals(repo string, branch string) (int, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	protection, resp, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if protection.RequiredPullRequestReviews == nil {
+		return 0, nil
+	}
+
+	return protection.RequiredPullRequestReviews.RequiredApprovingReviewCount, nil
+}
+
+// status checks required by the branch protection rule, none when the branch is not protected
+func GetRequiredChecks(repo string, branch string) ([]string, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	protection, resp, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if protection.RequiredStatusChecks == nil {
+		return nil, nil
+	}
+
+	return protection.RequiredStatusChecks.Contexts, nil
+}
+
+// number of reviewers whose latest review approves the pull request
+func GetApprovals(repo string, prNumber int) (int, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	reviews := []*github.PullRequestReview{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.PullRequests.ListReviews(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return 0, err
+		}
+		reviews = append(reviews, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return CountApprovals(reviews), nil
+}
+
+// reviews are in chronological order, comments don't change an earlier approval
+func CountApprovals(reviews []*github.PullRequestReview) int {
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		state := review.GetState()
+		if state == "COMMENTED" || state == "PENDING" {
+			continue
+		}
+		latest[review.GetUser().GetLogin()] = state
+	}
+
+	count := 0
+	for _, state := range latest {
+		if state == "APPROVED" {
+			count++
+		}
+	}
+
+	return count
+}
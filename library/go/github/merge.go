@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+
+	"github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+// whether the user can push to the repository, required to merge from Slack
+func HasWriteAccess(repo string, login string) (bool, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	level, _, err := client.Repositories.GetPermissionLevel(ctx, owner, repo, login)
+	if err != nil {
+		return false, err
+	}
+
+	return CanWrite(level.GetPermission()), nil
+}
+
+// maintain is reported as write by the permission level api
+func CanWrite(permission string) bool {
+	return permission == "admin" || permission == "write"
+}
+
+// merge with the given strategy (merge, squash or rebase), returns the merge commit sha
+func MergePullRequest(repo string, prNumber int, method string) (string, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	if dryrun.Enabled() {
+		dryrun.Log("github.merge", zap.String("owner", owner), zap.String("repository", repo), zap.Int("number", prNumber), zap.String("method", method))
+		return "", nil
+	}
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	result, _, err := client.PullRequests.Merge(ctx, owner, repo, prNumber, "", &github.PullRequestOptions{
+		MergeMethod: method,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result.GetSHA(), nil
+}
+
+// comment on the pull request, e.g. the link of its Slack thread
+func CreateComment(repo string, prNumber int, body string) error {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	if dryrun.Enabled() {
+		dryrun.Log("github.create_comment", zap.String("owner", owner), zap.String("repository", repo), zap.Int("number", prNumber), zap.String("body", body))
+		return nil
+	}
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	_, _, err := client.Issues.CreateComment(ctx, owner, repo, prNumber, &github.IssueComment{Body: github.String(body)})
+	return err
+}
+
+// reaction (+1, -1, confused, ...) on an issue comment of the pull request,
+// e.g. to acknowledge a comment command
+func CreateCommentReaction(repo string, commentId int64, content string) error {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	if dryrun.Enabled() {
+		dryrun.Log("github.create_comment_reaction", zap.String("owner", owner), zap.String("repository", repo), zap.Int64("commentId", commentId), zap.String("content", content))
+		return nil
+	}
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	_, _, err := client.Reactions.CreateIssueCommentReaction(ctx, owner, repo, commentId, content)
+	return err
+}
@@ -0,0 +1,50 @@
+package github
+
+import "testing"
+
+func TestHasWriteAccess(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestCanWrite(t *testing.T) {
+	data := map[string]bool{
+		"admin": true,
+		"write": true,
+		"read":  false,
+		"none":  false,
+	}
+
+	for permission, expected := range data {
+		if result := CanWrite(permission); result != expected {
+			t.Errorf("%s: Expected %v, got %v", permission, expected, result)
+		}
+	}
+}
+
+func TestMergePullRequest(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestMergePullRequestDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	if _, err := MergePullRequest("repo", 1, "squash"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestCreateCommentDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	if err := CreateComment("repo", 1, "hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
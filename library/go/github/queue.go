@@ -0,0 +1,60 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/env"
+)
+
+const mergeQueueEntryQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      mergeQueueEntry {
+        position
+      }
+    }
+  }
+}`
+
+type mergeQueueEntryResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequest struct {
+				MergeQueueEntry *struct {
+					Position int `json:"position"`
+				} `json:"mergeQueueEntry"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+}
+
+// position of the pull request in the merge queue of its base branch, the
+// merge queue is only exposed by the GraphQL API. 0 when it is not queued
+func GetMergeQueuePosition(repo string, prNumber int) (int, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	req, err := client.NewRequest("POST", "graphql", map[string]interface{}{
+		"query": mergeQueueEntryQuery,
+		"variables": map[string]interface{}{
+			"owner":  owner,
+			"repo":   repo,
+			"number": prNumber,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var result mergeQueueEntryResponse
+	if _, err := client.Do(ctx, req, &result); err != nil {
+		return 0, err
+	}
+
+	entry := result.Data.Repository.PullRequest.MergeQueueEntry
+	if entry == nil {
+		return 0, nil
+	}
+	return entry.Position, nil
+}
@@ -0,0 +1,42 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetMergeQueuePosition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if r.URL.Path != "/api/v3/graphql" || json.NewDecoder(r.Body).Decode(&body) != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		if body.Variables["number"] == float64(7) {
+			w.Write([]byte(`{"data": {"repository": {"pullRequest": {"mergeQueueEntry": {"position": 2}}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"repository": {"pullRequest": {"mergeQueueEntry": null}}}}`))
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL+"/api/v3/")
+
+	data := map[int]int{
+		7: 2,
+		8: 0,
+	}
+	for number, expected := range data {
+		position, err := GetMergeQueuePosition("api", number)
+		if err != nil {
+			t.Fatalf("#%d: Expected no error, got %v", number, err)
+		}
+		if position != expected {
+			t.Errorf("#%d: Expected %d, got %d", number, expected, position)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+
+	"github.com/google/go-github/v39/github"
+	"go.uber.org/zap"
+)
+
+// logins of the collaborators with write access, those who can review the
+// pull requests of the repository
+func GetCollaborators(repo string) ([]string, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	logins := []string{}
+	opts := &github.ListCollaboratorsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := client.Repositories.ListCollaborators(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range page {
+			if user.GetPermissions()["push"] {
+				logins = append(logins, user.GetLogin())
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return logins, nil
+}
+
+// requests the review of the logins, GitHub delivers a review_requested
+// event for each of them
+func RequestReviewers(repo string, prNumber int, logins []string) error {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	if dryrun.Enabled() {
+		dryrun.Log("github.request_reviewers", zap.String("owner", owner), zap.String("repository", repo), zap.Int("number", prNumber), zap.Strings("reviewers", logins))
+		return nil
+	}
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	_, _, err := client.PullRequests.RequestReviewers(ctx, owner, repo, prNumber, github.ReviewersRequest{Reviewers: logins})
+	return err
+}
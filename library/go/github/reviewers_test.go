@@ -0,0 +1,19 @@
+package github
+
+import "testing"
+
+func TestGetCollaborators(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestRequestReviewersDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	if err := RequestReviewers("api", 7, []string{"alice"}); err != nil {
+		t.Errorf("Expected no github call, got %v", err)
+	}
+}
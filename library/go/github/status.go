@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/env"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// live merge readiness of a pull request
+type PullRequestStatus struct {
+	ID                int64
+	Number            int
+	Title             string
+	HtmlUrl           string
+	BaseRef           string
+	Approvals         int
+	Conflicts         bool
+	FailingChecks     []string
+	UnresolvedThreads int
+}
+
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) {
+        nodes {
+          isResolved
+        }
+      }
+    }
+  }
+}`
+
+type reviewThreadsResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						IsResolved bool `json:"isResolved"`
+					} `json:"nodes"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+}
+
+func GetPullRequestStatus(repo string, prNumber int) (*PullRequestStatus, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals, err := GetApprovals(repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []*github.CheckRun{}
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := client.Checks.ListCheckRunsForRef(ctx, owner, repo, pr.GetHead().GetSHA(), opts)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, page.CheckRuns...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	unresolved, err := unresolvedThreads(ctx, client, owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PullRequestStatus{
+		ID:                pr.GetID(),
+		Number:            pr.GetNumber(),
+		Title:             pr.GetTitle(),
+		HtmlUrl:           pr.GetHTMLURL(),
+		BaseRef:           pr.GetBase().GetRef(),
+		Approvals:         approvals,
+		Conflicts:         pr.GetMergeableState() == "dirty",
+		FailingChecks:     FailingChecks(runs),
+		UnresolvedThreads: unresolved,
+	}, nil
+}
+
+// review thread resolution is only exposed by the GraphQL API
+func unresolvedThreads(ctx context.Context, client *github.Client, owner string, repo string, prNumber int) (int, error) {
+	req, err := client.NewRequest("POST", "graphql", map[string]interface{}{
+		"query": reviewThreadsQuery,
+		"variables": map[string]interface{}{
+			"owner":  owner,
+			"repo":   repo,
+			"number": prNumber,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var result reviewThreadsResponse
+	if _, err := client.Do(ctx, req, &result); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, thread := range result.Data.Repository.PullRequest.ReviewThreads.Nodes {
+		if !thread.IsResolved {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// names of the completed check runs that did not pass
+func FailingChecks(runs []*github.CheckRun) []string {
+	failing := []string{}
+	for _, run := range runs {
+		if run.GetStatus() != "completed" {
+			continue
+		}
+		switch run.GetConclusion() {
+		case "failure", "cancelled", "timed_out", "action_required":
+			failing = append(failing, run.GetName())
+		}
+	}
+	return failing
+}
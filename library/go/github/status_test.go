@@ -0,0 +1,38 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+)
+
+func TestGetPullRequestStatus(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestFailingChecks(t *testing.T) {
+	run := func(name string, status string, conclusion string) *github.CheckRun {
+		return &github.CheckRun{
+			Name:       github.String(name),
+			Status:     github.String(status),
+			Conclusion: github.String(conclusion),
+		}
+	}
+
+	runs := []*github.CheckRun{
+		run("build", "completed", "success"),
+		run("lint", "completed", "failure"),
+		run("e2e", "completed", "cancelled"),
+		run("deploy", "in_progress", ""),
+		run("docs", "completed", "skipped"),
+	}
+
+	expected := []string{"lint", "e2e"}
+	if result := FailingChecks(runs); !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %v want %v", result, expected)
+	}
+}
@@ -0,0 +1,37 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// pull request in the shape of an "opened" webhook delivery, to track pull
+// requests opened before the webhook was set up
+func GetOpenPullRequest(repo string, prNumber int) (types.OpenPullRequest, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return types.OpenPullRequest{}, err
+	}
+
+	return openPullRequest(repo, pr), nil
+}
+
+// the REST and webhook pull request objects are the same, the author stands
+// in for the sender
+func openPullRequest(repo string, pr *github.PullRequest) types.OpenPullRequest {
+	return types.OpenPullRequest{
+		Action:      "opened",
+		Number:      pr.GetNumber(),
+		PullRequest: pr,
+		Repository:  &github.Repository{Name: github.String(repo)},
+		Sender:      pr.GetUser(),
+	}
+}
@@ -0,0 +1,47 @@
+package github
+
+import (
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+)
+
+func TestGetOpenPullRequest(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestOpenPullRequest(t *testing.T) {
+	pr := &github.PullRequest{
+		ID:        github.Int64(42),
+		Number:    github.Int(7),
+		State:     github.String("open"),
+		HTMLURL:   github.String("https://github.com/o/api/pull/7"),
+		User:      &github.User{Login: github.String("alice"), ID: github.Int64(1)},
+		CreatedAt: &time.Time{},
+		Base:      &github.PullRequestBranch{Ref: github.String("main")},
+		Labels:    []*github.Label{{Name: github.String("hotfix")}},
+		RequestedReviewers: []*github.User{
+			{Login: github.String("bob")},
+		},
+	}
+
+	input := openPullRequest("api", pr)
+
+	if input.Action != "opened" || input.Number != 7 || input.Repository.GetName() != "api" || input.Sender.GetLogin() != "alice" {
+		t.Errorf("Expected an opened delivery of api#7 by alice, got %+v", input)
+	}
+	if input.PullRequest.GetID() != 42 || input.PullRequest.GetBase().GetRef() != "main" || input.PullRequest.GetHTMLURL() != "https://github.com/o/api/pull/7" {
+		t.Errorf("Expected the pull request fields, got %+v", input.PullRequest)
+	}
+	if len(input.PullRequest.RequestedReviewers) != 1 || input.PullRequest.RequestedReviewers[0].GetLogin() != "bob" {
+		t.Errorf("Expected bob to be requested, got %+v", input.PullRequest.RequestedReviewers)
+	}
+	if labels := types.LabelNames(input.PullRequest); len(labels) != 1 || labels[0] != "hotfix" {
+		t.Errorf("Expected the hotfix label, got %v", labels)
+	}
+}
@@ -0,0 +1,21 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/env"
+)
+
+// public email of the GitHub profile, empty when the user keeps it private
+func GetUserEmail(login string) (string, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	user, _, err := client.Users.Get(ctx, login)
+	if err != nil {
+		return "", err
+	}
+
+	return user.GetEmail(), nil
+}
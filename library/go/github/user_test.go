@@ -0,0 +1,40 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetUserEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/users/bob":
+			w.Write([]byte(`{"login": "bob", "email": "bob@acme.com"}`))
+		case "/api/v3/users/carol":
+			w.Write([]byte(`{"login": "carol", "email": null}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL+"/api/v3/")
+
+	data := map[string]string{
+		"bob":   "bob@acme.com",
+		"carol": "",
+	}
+	for login, expected := range data {
+		email, err := GetUserEmail(login)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", login, err)
+		}
+		if email != expected {
+			t.Errorf("%s: Expected %q, got %q", login, expected, email)
+		}
+	}
+
+	if _, err := GetUserEmail("dave"); err == nil {
+		t.Errorf("Expected an error for an unknown user")
+	}
+}
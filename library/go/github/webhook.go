@@ -0,0 +1,49 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slack-pr-lambda/env"
+	"strings"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidToken     = errors.New("invalid webhook token")
+	ErrUnauthenticated  = errors.New("webhook is not authenticated")
+)
+
+// authenticate a delivery with the X-Hub-Signature-256 HMAC of GITHUB_WEBHOOK_SECRET,
+// or the X-Webhook-Token shared secret of WEBHOOK_TOKEN for forwarders and
+// proxies stripping the signature. Deliveries are accepted when neither is set
+func VerifyWebhook(header http.Header, body []byte) error {
+	secret := env.GetEnv("GITHUB_WEBHOOK_SECRET", "")
+	token := env.GetEnv("WEBHOOK_TOKEN", "")
+	if secret == "" && token == "" {
+		return nil
+	}
+
+	if signature := header.Get("X-Hub-Signature-256"); secret != "" && signature != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+		if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+
+	if value := header.Get("X-Webhook-Token"); token != "" && value != "" {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(token)) != 1 {
+			return ErrInvalidToken
+		}
+		return nil
+	}
+
+	return ErrUnauthenticated
+}
@@ -0,0 +1,45 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+	// echo -n '{"action":"opened"}' | openssl dgst -sha256 -hmac secret
+	signature := "sha256=d42142b53efbc7cf5cd20b6e074eb33707e0de3b368f698e6d6f6c824ffb8d37"
+
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		headers map[string]string
+		err     error
+	}{
+		{name: "not configured"},
+		{name: "signature", secret: "secret", headers: map[string]string{"X-Hub-Signature-256": signature}},
+		{name: "wrong signature", secret: "secret", headers: map[string]string{"X-Hub-Signature-256": "sha256=00"}, err: ErrInvalidSignature},
+		{name: "token", token: "shared", headers: map[string]string{"X-Webhook-Token": "shared"}},
+		{name: "wrong token", token: "shared", headers: map[string]string{"X-Webhook-Token": "other"}, err: ErrInvalidToken},
+		{name: "token without signature", secret: "secret", token: "shared", headers: map[string]string{"X-Webhook-Token": "shared"}},
+		{name: "missing", secret: "secret", token: "shared", err: ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_WEBHOOK_SECRET", tt.secret)
+			t.Setenv("WEBHOOK_TOKEN", tt.token)
+
+			header := http.Header{}
+			for key, value := range tt.headers {
+				header.Set(key, value)
+			}
+
+			if err := VerifyWebhook(header, body); !errors.Is(err, tt.err) {
+				t.Errorf("got %v want %v", err, tt.err)
+			}
+		})
+	}
+}
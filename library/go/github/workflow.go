@@ -0,0 +1,37 @@
+package github
+
+import (
+	"context"
+	"slack-pr-lambda/env"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// jobs of a workflow run that failed, in the order of the run
+func GetFailedJobs(repo string, runID int64) ([]*github.WorkflowJob, error) {
+	owner := env.GetEnv("GITHUB_OWNER", "owner")
+
+	ctx := context.Background()
+	client := githubClient(ctx, owner)
+
+	jobs, _, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{
+		Filter:      "latest",
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FailedJobs(jobs.Jobs), nil
+}
+
+// completed jobs that failed or timed out
+func FailedJobs(jobs []*github.WorkflowJob) []*github.WorkflowJob {
+	failed := []*github.WorkflowJob{}
+	for _, job := range jobs {
+		switch job.GetConclusion() {
+		case "failure", "timed_out":
+			failed = append(failed, job)
+		}
+	}
+	return failed
+}
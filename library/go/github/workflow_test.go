@@ -0,0 +1,35 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+)
+
+func TestGetFailedJobs(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to github api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestFailedJobs(t *testing.T) {
+	job := func(name string, conclusion string) *github.WorkflowJob {
+		return &github.WorkflowJob{
+			Name:       github.String(name),
+			Conclusion: github.String(conclusion),
+		}
+	}
+
+	jobs := []*github.WorkflowJob{
+		job("build", "success"),
+		job("test", "failure"),
+		job("e2e", "timed_out"),
+		job("deploy", "skipped"),
+	}
+
+	failed := FailedJobs(jobs)
+	if len(failed) != 2 || failed[0].GetName() != "test" || failed[1].GetName() != "e2e" {
+		t.Errorf("Expected test and e2e, got %v", failed)
+	}
+}
@@ -0,0 +1,171 @@
+package i18n
+
+// message formats by language and key, the arguments of a key are the same in
+// every language. Explicit indexes (%[2]s) reorder them
+var catalogs = map[string]map[string]string{
+	"en": {
+		// parent message status lines
+		"approvals":            "Approvals: %d",
+		"approvals.required":   "Approvals: %d/%d",
+		"dependencies":         "Depends on %s",
+		"dependency.merged":    "%s %s merged",
+		"dependency.waiting":   "%s, still waiting on %s.",
+		"dependency.unblocked": "%s — this PR is unblocked.",
+		"files":                "Files: %d (%s)",
+		"files.more":           "%d more directories",
+		"files.root":           "%d at the root",
+		"files.directory":      "%d in `%s/`",
+		"checklist":            "Checklist: %d/%d",
+		"work_in_progress":     "%s Work in progress, reviewers are pinged once the WIP prefix is removed.",
+		"age.fresh":            "Age: < 1d %s",
+		"age.aging":            "Age: 1-3d %s",
+		"age.stale":            "Age: > 3d %s",
+		"unfurl.thread":        "Slack thread",
+
+		// pull request notifications
+		"opened":                   "<@%s> %s opened new <%s|pull request> in `%s`.",
+		"reopened":                 "<@%s> %s Reopened <%s|pull request> in `%s`.",
+		"closed":                   "<@%s> closed the pull request without merging %s. ",
+		"merged":                   "<@%s> merged the pull request %s. ",
+		"comment.issue":            "<@%s> %s submitted an issue <%s|comment>. \n",
+		"review.commented":         "<@%s> submitted a review <%s|comment> %s. \n ",
+		"review.approved":          "<@%s> approved the pull <%s|request> %s. \n",
+		"review.changes_requested": "<@%s> requested a change <%s|comment> %s. \n ",
+		"pushed":                   "<@%s> %s pushed a <%s|change>.",
+		"check_run":                "Check run <%s|%s> %s.",
+		"checks.passed":            "All checks have passed. %s",
+		"checks.failed":            "Some checks were not successful. %s",
+		"checks.canceled":          "Some checks were cancelled. %s",
+		"first_contribution":       "%s first PR from %s!",
+		"comment.review":           "<@%s> %s left a review <%s|comment> on `%s`. \n",
+		"comment.review.batch":     "<@%s> %s left %d review <%s/files|comments> on %d files.",
+		"comment.review.batch_one": "<@%s> %s left %d review <%s/files|comments> on %d file.",
+		"merge.slack":              "<@%s> merged the pull request from Slack (%s) %s.",
+		"merge.failed":             "<@%s> tried to merge the pull request but it failed %s: %s",
+
+		// merge queue
+		"merge_queue.enqueued":                    "%s <@%s> added the pull request to the merge queue.",
+		"merge_queue.position":                    "%s <@%s> added the pull request to the merge queue, position %d.",
+		"merge_queue.dequeued":                    "%s Removed from the merge queue: %s.",
+		"merge_queue.checks":                      "%s Merge queue checks started.",
+		"merge_queue.reason.already_merged":       "already merged",
+		"merge_queue.reason.branch_protections":   "branch protections not met",
+		"merge_queue.reason.ci_failure":           "CI failed",
+		"merge_queue.reason.ci_timeout":           "CI timed out",
+		"merge_queue.reason.git_tree_invalid":     "invalid git tree",
+		"merge_queue.reason.invalid_merge_commit": "invalid merge commit",
+		"merge_queue.reason.manual":               "removed manually",
+		"merge_queue.reason.merge_conflict":       "merge conflict",
+		"merge_queue.reason.queue_cleared":        "queue cleared",
+		"merge_queue.reason.roll_back":            "rolled back",
+		"merge_queue.reason.unknown":              "unknown reason",
+	},
+	"fr": {
+		"approvals":            "Approbations : %d",
+		"approvals.required":   "Approbations : %d/%d",
+		"dependencies":         "Dépend de %s",
+		"dependency.merged":    "%s %s fusionnée",
+		"dependency.waiting":   "%s, en attente de %s.",
+		"dependency.unblocked": "%s — cette PR est débloquée.",
+		"files":                "Fichiers : %d (%s)",
+		"files.more":           "%d autres dossiers",
+		"files.root":           "%d à la racine",
+		"files.directory":      "%d dans `%s/`",
+		"checklist":            "Liste de contrôle : %d/%d",
+		"work_in_progress":     "%s Travail en cours, les relecteurs seront notifiés une fois le préfixe WIP retiré.",
+		"age.fresh":            "Âge : < 1j %s",
+		"age.aging":            "Âge : 1-3j %s",
+		"age.stale":            "Âge : > 3j %s",
+		"unfurl.thread":        "Fil Slack",
+
+		"opened":                   "<@%s> %s a ouvert une nouvelle <%s|pull request> dans `%s`.",
+		"reopened":                 "<@%s> %s a rouvert la <%s|pull request> dans `%s`.",
+		"closed":                   "<@%s> a fermé la pull request sans la fusionner %s. ",
+		"merged":                   "<@%s> a fusionné la pull request %s. ",
+		"comment.issue":            "<@%s> %s a publié un <%s|commentaire>. \n",
+		"review.commented":         "<@%s> a publié une <%s|revue> %s. \n ",
+		"review.approved":          "<@%s> a approuvé la pull <%s|request> %s. \n",
+		"review.changes_requested": "<@%s> a demandé des <%s|modifications> %s. \n ",
+		"pushed":                   "<@%s> %s a poussé une <%s|modification>.",
+		"check_run":                "Vérification <%s|%s> %s.",
+		"checks.passed":            "Toutes les vérifications sont passées. %s",
+		"checks.failed":            "Certaines vérifications ont échoué. %s",
+		"checks.canceled":          "Certaines vérifications ont été annulées. %s",
+		"first_contribution":       "%s première PR de %s !",
+		"comment.review":           "<@%s> %s a laissé un <%s|commentaire de revue> sur `%s`. \n",
+		"comment.review.batch":     "<@%s> %s a laissé %d <%s/files|commentaires de revue> sur %d fichiers.",
+		"comment.review.batch_one": "<@%s> %s a laissé %d <%s/files|commentaires de revue> sur %d fichier.",
+		"merge.slack":              "<@%s> a fusionné la pull request depuis Slack (%s) %s.",
+		"merge.failed":             "<@%s> a tenté de fusionner la pull request mais cela a échoué %s : %s",
+
+		"merge_queue.enqueued":                    "%s <@%s> a ajouté la pull request à la file de fusion.",
+		"merge_queue.position":                    "%s <@%s> a ajouté la pull request à la file de fusion, position %d.",
+		"merge_queue.dequeued":                    "%s Retirée de la file de fusion : %s.",
+		"merge_queue.checks":                      "%s Vérifications de la file de fusion lancées.",
+		"merge_queue.reason.already_merged":       "déjà fusionnée",
+		"merge_queue.reason.branch_protections":   "protections de branche non respectées",
+		"merge_queue.reason.ci_failure":           "échec de la CI",
+		"merge_queue.reason.ci_timeout":           "délai de la CI dépassé",
+		"merge_queue.reason.git_tree_invalid":     "arbre git invalide",
+		"merge_queue.reason.invalid_merge_commit": "commit de fusion invalide",
+		"merge_queue.reason.manual":               "retirée manuellement",
+		"merge_queue.reason.merge_conflict":       "conflit de fusion",
+		"merge_queue.reason.queue_cleared":        "file vidée",
+		"merge_queue.reason.roll_back":            "annulée",
+		"merge_queue.reason.unknown":              "raison inconnue",
+	},
+	"ja": {
+		"approvals":            "承認: %d",
+		"approvals.required":   "承認: %d/%d",
+		"dependencies":         "依存: %s",
+		"dependency.merged":    "%s %s がマージされました",
+		"dependency.waiting":   "%s。%s を待っています。",
+		"dependency.unblocked": "%s — この PR のブロックが解除されました。",
+		"files":                "ファイル: %d (%s)",
+		"files.more":           "他 %d ディレクトリ",
+		"files.root":           "ルートに %d",
+		"files.directory":      "`%[2]s/` に %[1]d",
+		"checklist":            "チェックリスト: %d/%d",
+		"work_in_progress":     "%s 作業中です。WIP が外れるとレビュアーに通知されます。",
+		"age.fresh":            "経過: 1日未満 %s",
+		"age.aging":            "経過: 1-3日 %s",
+		"age.stale":            "経過: 3日以上 %s",
+		"unfurl.thread":        "Slack スレッド",
+
+		"opened":                   "<@%[1]s> %[2]s が `%[4]s` に新しい<%[3]s|プルリクエスト>を作成しました。",
+		"reopened":                 "<@%[1]s> %[2]s が `%[4]s` の<%[3]s|プルリクエスト>を再オープンしました。",
+		"closed":                   "<@%s> がプルリクエストをマージせずにクローズしました %s。",
+		"merged":                   "<@%s> がプルリクエストをマージしました %s。",
+		"comment.issue":            "<@%s> %s が<%s|コメント>しました。\n",
+		"review.commented":         "<@%s> が<%s|レビューコメント>しました %s。\n ",
+		"review.approved":          "<@%s> がプル<%s|リクエスト>を承認しました %s。\n",
+		"review.changes_requested": "<@%s> が<%s|変更をリクエスト>しました %s。\n ",
+		"pushed":                   "<@%s> %s が<%s|変更>をプッシュしました。",
+		"check_run":                "チェック <%s|%s> %s。",
+		"checks.passed":            "すべてのチェックが成功しました。%s",
+		"checks.failed":            "一部のチェックが失敗しました。%s",
+		"checks.canceled":          "一部のチェックがキャンセルされました。%s",
+		"first_contribution":       "%s %s の初めての PR です！",
+		"comment.review":           "<@%[1]s> %[2]s が `%[4]s` に<%[3]s|レビューコメント>しました。\n",
+		"comment.review.batch":     "<@%[1]s> %[2]s が %[5]d ファイルに %[3]d 件の<%[4]s/files|レビューコメント>をしました。",
+		"comment.review.batch_one": "<@%[1]s> %[2]s が %[5]d ファイルに %[3]d 件の<%[4]s/files|レビューコメント>をしました。",
+		"merge.slack":              "<@%s> が Slack からプルリクエストをマージしました (%s) %s。",
+		"merge.failed":             "<@%s> がプルリクエストのマージを試みましたが失敗しました %s: %s",
+
+		"merge_queue.enqueued":                    "%s <@%s> がプルリクエストをマージキューに追加しました。",
+		"merge_queue.position":                    "%s <@%s> がプルリクエストをマージキューに追加しました（%d 番目）。",
+		"merge_queue.dequeued":                    "%s マージキューから外れました: %s。",
+		"merge_queue.checks":                      "%s マージキューのチェックを開始しました。",
+		"merge_queue.reason.already_merged":       "マージ済み",
+		"merge_queue.reason.branch_protections":   "ブランチ保護を満たしていない",
+		"merge_queue.reason.ci_failure":           "CI 失敗",
+		"merge_queue.reason.ci_timeout":           "CI タイムアウト",
+		"merge_queue.reason.git_tree_invalid":     "無効な git ツリー",
+		"merge_queue.reason.invalid_merge_commit": "無効なマージコミット",
+		"merge_queue.reason.manual":               "手動で削除",
+		"merge_queue.reason.merge_conflict":       "マージコンフリクト",
+		"merge_queue.reason.queue_cleared":        "キューのクリア",
+		"merge_queue.reason.roll_back":            "ロールバック",
+		"merge_queue.reason.unknown":              "不明な理由",
+	},
+}
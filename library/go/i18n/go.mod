@@ -0,0 +1,3 @@
+module slack-pr-lambda/i18n
+
+go 1.22
@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"fmt"
+	"slack-pr-lambda/env"
+	"sort"
+	"strings"
+)
+
+// catalogs are complete in English, the other languages fall back to it
+const fallbackLanguage = "en"
+
+// LANGUAGE of the channels without their own language in CHANNEL_LANGUAGES
+// ("C0123=ja,C0456=fr"), English when unset
+func Language(channel string) string {
+	for _, entry := range strings.Split(env.GetEnv("CHANNEL_LANGUAGES", ""), ",") {
+		id, language, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if ok && id == channel && language != "" {
+			return language
+		}
+	}
+	return env.GetEnv("LANGUAGE", fallbackLanguage)
+}
+
+// languages tried for a message, e.g. fr-CA, fr, LANGUAGE then English
+func chain(language string) []string {
+	languages := []string{}
+	add := func(language string) {
+		language = strings.ToLower(strings.ReplaceAll(language, "_", "-"))
+		for _, added := range languages {
+			if added == language {
+				return
+			}
+		}
+		if language != "" {
+			languages = append(languages, language)
+		}
+	}
+
+	add(language)
+	if base, _, found := strings.Cut(language, "-"); found {
+		add(base)
+	}
+	add(env.GetEnv("LANGUAGE", fallbackLanguage))
+	add(fallbackLanguage)
+	return languages
+}
+
+// text of the message key in the language, formatted with args like
+// fmt.Sprintf. The key itself when no catalog has it
+func T(language string, key string, args ...any) string {
+	for _, candidate := range chain(language) {
+		if format, ok := catalogs[candidate][key]; ok {
+			return fmt.Sprintf(format, args...)
+		}
+	}
+	return key
+}
+
+// text of the message key in the language of the channel
+func Channel(channel string, key string, args ...any) string {
+	return T(Language(channel), key, args...)
+}
+
+// languages with a catalog, sorted
+func Languages() []string {
+	languages := []string{}
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLanguage(t *testing.T) {
+	t.Setenv("CHANNEL_LANGUAGES", "C1=ja, C2=fr")
+
+	if language := Language("C1"); language != "ja" {
+		t.Errorf("Language(C1) = %v, expected ja", language)
+	}
+	if language := Language("C3"); language != "en" {
+		t.Errorf("Language(C3) = %v, expected en", language)
+	}
+
+	t.Setenv("LANGUAGE", "fr")
+	if language := Language("C3"); language != "fr" {
+		t.Errorf("Language(C3) = %v, expected fr", language)
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		language string
+		key      string
+		args     []any
+		expected string
+	}{
+		{"en", "approvals.required", []any{1, 2}, "Approvals: 1/2"},
+		{"ja", "approvals.required", []any{1, 2}, "承認: 1/2"},
+		{"ja", "files.directory", []any{3, "web"}, "`web/` に 3"},
+		{"fr-CA", "approvals", []any{1}, "Approbations : 1"},
+		{"de", "approvals", []any{1}, "Approvals: 1"},
+		{"ja", "unknown", nil, "unknown"},
+	}
+
+	for _, tt := range tests {
+		if text := T(tt.language, tt.key, tt.args...); text != tt.expected {
+			t.Errorf("T(%v, %v) = %q, expected %q", tt.language, tt.key, text, tt.expected)
+		}
+	}
+}
+
+func TestFallbackLanguage(t *testing.T) {
+	t.Setenv("LANGUAGE", "ja")
+
+	if text := T("de", "approvals", 1); text != "承認: 1" {
+		t.Errorf("T(de) = %q, expected the LANGUAGE catalog", text)
+	}
+}
+
+// every translation has the keys of the English catalog and formats their
+// arguments, so a missing or extra verb shows up here instead of in Slack
+func TestCatalogs(t *testing.T) {
+	verb := regexp.MustCompile(`%(\[\d+\])?[sd]`)
+
+	for _, language := range Languages() {
+		catalog := catalogs[language]
+		if len(catalog) != len(catalogs[fallbackLanguage]) {
+			t.Errorf("%v has %v keys, expected %v", language, len(catalog), len(catalogs[fallbackLanguage]))
+		}
+
+		for key, format := range catalogs[fallbackLanguage] {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("%v misses %v", language, key)
+				continue
+			}
+
+			args := []any{}
+			for _, match := range verb.FindAllStringSubmatch(format, -1) {
+				if match[0][len(match[0])-1] == 'd' {
+					args = append(args, 1)
+				} else {
+					args = append(args, "x")
+				}
+			}
+			if text := fmt.Sprintf(translated, args...); strings.Contains(text, "%!") {
+				t.Errorf("%v %v formats to %q", language, key, text)
+			}
+			if verbs := len(verb.FindAllString(translated, -1)); verbs != len(args) {
+				t.Errorf("%v %v has %v verbs, expected %v", language, key, verbs, len(args))
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+type correlationKey struct{}
+
+// ctx carrying the correlation id of a request, logged by every logger.New
+// of ctx
+func WithCorrelationId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// correlation id of ctx, empty when the request was not correlated
+func CorrelationId(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// random version 4 UUID, for the requests without a GitHub delivery id
+func NewCorrelationId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCorrelationId(t *testing.T) {
+	if id := CorrelationId(context.Background()); id != "" {
+		t.Errorf("FAIL: Expected no correlation id, Got: %s", id)
+	}
+
+	ctx := WithCorrelationId(context.Background(), "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	if id := CorrelationId(ctx); id != "72d3162e-cc78-11e3-81ab-4c9367dc0958" {
+		t.Errorf("FAIL: Expected the delivery id, Got: %s", id)
+	}
+
+	core, logs := observer.New(zap.InfoLevel)
+	zap.New(core, Request(ctx)).Info("hello")
+	if logs.All()[0].ContextMap()["correlationId"] != "72d3162e-cc78-11e3-81ab-4c9367dc0958" {
+		t.Errorf("FAIL: Expected the correlation id, Got: %v", logs.All()[0].ContextMap())
+	}
+}
+
+func TestNewCorrelationId(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, second := NewCorrelationId(), NewCorrelationId()
+	if !uuid.MatchString(first) {
+		t.Errorf("FAIL: Expected a version 4 UUID, Got: %s", first)
+	}
+	if first == second {
+		t.Errorf("FAIL: Expected different ids")
+	}
+}
@@ -2,7 +2,10 @@ module slack-pr-lambda/logger
 
 go 1.22
 
-require go.uber.org/zap v1.27.0
+require (
+	github.com/aws/aws-lambda-go v1.46.0
+	go.uber.org/zap v1.27.0
+)
 
 require (
 	github.com/stretchr/testify v1.8.4 // indirect
@@ -1,21 +1,81 @@
 package logger
 
 import (
+	"context"
 	"slack-pr-lambda/env"
+	"sync"
 
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// LOG_LEVEL (debug, info, warn, error, default info), LOG_FORMAT (json or
+// console, default json) and LOG_SAMPLING (default true outside of local)
 func LoggerConfig() zap.Config {
 	GOENV := env.GetEnv("ENV", "dev")
 
 	config := zap.NewProductionConfig()
+	config.Encoding = redactedEncoding
 
-	if GOENV == "test" {
+	if env.GetEnv("LOG_FORMAT", "json") == "console" {
+		config.Encoding = redactedConsoleEncoding
+		config.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	// the first 100 entries with the same message per second, then every 100th
+	if GOENV == "local" || env.GetEnv("LOG_SAMPLING", "true") == "false" {
+		config.Sampling = nil
+	}
+
+	if level, err := zapcore.ParseLevel(env.GetEnv("LOG_LEVEL", "info")); err == nil {
+		config.Level = zap.NewAtomicLevelAt(level)
+	}
+
+	if GOENV == "test" && env.GetEnv("LOG_LEVEL", "") == "" {
 		// disable logs during test environment
 		config.Level = zap.NewAtomicLevelAt(zapcore.FatalLevel)
 	}
 
 	return config
 }
+
+var base struct {
+	once   sync.Once
+	logger *zap.Logger
+	err    error
+}
+
+// logger built from LoggerConfig on first use and shared by every caller
+// afterwards, a failed build is returned to every caller as well
+func Base() (*zap.Logger, error) {
+	base.once.Do(func() {
+		base.logger, base.err = LoggerConfig().Build()
+	})
+	return base.logger, base.err
+}
+
+// shared logger with the lambda request id of ctx
+//
+//	zapLog, err := logger.New(r.Context())
+func New(ctx context.Context) (*zap.Logger, error) {
+	zapLog, err := Base()
+	if err != nil {
+		return nil, err
+	}
+	return zapLog.WithOptions(Request(ctx)), nil
+}
+
+// build option adding the lambda request id and the correlation id of ctx to
+// every entry, none of them when the request was not served by lambda nor
+// correlated
+func Request(ctx context.Context) zap.Option {
+	fields := []zap.Field{}
+	if lc, ok := lambdacontext.FromContext(ctx); ok && lc.AwsRequestID != "" {
+		fields = append(fields, zap.String("requestId", lc.AwsRequestID))
+	}
+	if id := CorrelationId(ctx); id != "" {
+		fields = append(fields, zap.String("correlationId", id))
+	}
+	return zap.Fields(fields...)
+}
@@ -1,11 +1,14 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"reflect"
 	"testing"
 
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type TestEnvData struct {
@@ -30,6 +33,10 @@ func TestLoggerConfig(t *testing.T) {
 			t.Errorf("FAIL: Unexpected config type. Expected: %s, Got: %s", expectedType, reflect.TypeOf(config))
 		}
 
+		if config.Encoding != "redacted-json" {
+			t.Errorf("FAIL: Unexpected config encoding. Expected: %s, Got: %s", "redacted-json", config.Encoding)
+		}
+
 		if os.Getenv("ENV") == "dev" && config.Level.Level().CapitalString() != "INFO" {
 			t.Errorf("FAIL: Unexpected config level. Expected: %s, Got: %s", "info", config.Level)
 		}
@@ -40,3 +47,76 @@ func TestLoggerConfig(t *testing.T) {
 	}
 
 }
+
+func TestLoggerConfigEnv(t *testing.T) {
+	t.Setenv("ENV", "stage")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_FORMAT", "console")
+	t.Setenv("LOG_SAMPLING", "false")
+
+	config := LoggerConfig()
+
+	if config.Level.Level() != zap.DebugLevel {
+		t.Errorf("FAIL: Unexpected config level. Expected: %s, Got: %s", "debug", config.Level)
+	}
+	if config.Encoding != "redacted-console" {
+		t.Errorf("FAIL: Unexpected config encoding. Expected: %s, Got: %s", "redacted-console", config.Encoding)
+	}
+	if config.Sampling != nil {
+		t.Errorf("FAIL: Expected sampling to be disabled")
+	}
+	if _, err := config.Build(); err != nil {
+		t.Errorf("FAIL: Unexpected build error %v", err)
+	}
+}
+
+func TestLoggerConfigDefaults(t *testing.T) {
+	t.Setenv("ENV", "stage")
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	config := LoggerConfig()
+
+	if config.Level.Level() != zap.InfoLevel {
+		t.Errorf("FAIL: Expected an invalid level to keep info, Got: %s", config.Level)
+	}
+	if config.Sampling == nil {
+		t.Errorf("FAIL: Expected sampling outside of local")
+	}
+
+	t.Setenv("ENV", "local")
+	if LoggerConfig().Sampling != nil {
+		t.Errorf("FAIL: Expected no sampling locally")
+	}
+}
+
+func TestRequest(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "c6af9ac6-7b61-11e6-9a41-93e812345678"})
+	zap.New(core, Request(ctx)).Info("hello")
+	zap.New(core, Request(context.Background())).Info("local")
+
+	entries := logs.All()
+	if entries[0].ContextMap()["requestId"] != "c6af9ac6-7b61-11e6-9a41-93e812345678" {
+		t.Errorf("FAIL: Expected the request id, Got: %v", entries[0].ContextMap())
+	}
+	if _, ok := entries[1].ContextMap()["requestId"]; ok {
+		t.Errorf("FAIL: Expected no request id outside of lambda")
+	}
+}
+
+func TestBase(t *testing.T) {
+	first, err := Base()
+	if err != nil {
+		t.Fatalf("FAIL: Unexpected build error %v", err)
+	}
+	second, _ := Base()
+	if first != second {
+		t.Errorf("FAIL: Expected the logger to be built once")
+	}
+
+	zapLog, err := New(context.Background())
+	if err != nil || zapLog == nil {
+		t.Errorf("FAIL: Expected a request logger, Got: %v", err)
+	}
+}
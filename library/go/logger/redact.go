@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"slack-pr-lambda/redact"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// encoders that mask tokens, signatures and emails in the whole entry, message,
+// fields and errors included, so payload fragments never reach the logs
+const (
+	redactedEncoding        = "redacted-json"
+	redactedConsoleEncoding = "redacted-console"
+)
+
+func init() {
+	if err := zap.RegisterEncoder(redactedEncoding, func(config zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return redactingEncoder{zapcore.NewJSONEncoder(config)}, nil
+	}); err != nil {
+		panic(err)
+	}
+	if err := zap.RegisterEncoder(redactedConsoleEncoding, func(config zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return redactingEncoder{zapcore.NewConsoleEncoder(config)}, nil
+	}); err != nil {
+		panic(err)
+	}
+}
+
+type redactingEncoder struct {
+	zapcore.Encoder
+}
+
+func (e redactingEncoder) Clone() zapcore.Encoder {
+	return redactingEncoder{e.Encoder.Clone()}
+}
+
+func (e redactingEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	line := redact.String(buf.String())
+	buf.Reset()
+	buf.AppendString(line)
+
+	return buf, nil
+}
@@ -0,0 +1,3 @@
+module slack-pr-lambda/mentions
+
+go 1.22
@@ -0,0 +1,145 @@
+package mentions
+
+import (
+	"fmt"
+	"regexp"
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/mapstruct"
+	"strings"
+	"time"
+)
+
+// when Slack mentions notify. Mentions in quiet channels never do, outside of
+// the mention hours of the working days they are posted as plain names and
+// the real mention waits for the next window
+type Policy struct {
+	Channels map[string]bool
+	// window as offsets from midnight, mentions notify at any time when End is 0
+	Start    time.Duration
+	End      time.Duration
+	Calendar calendar.Calendar
+}
+
+// QUIET_CHANNELS ("C0123,C0456") and MENTION_HOURS ("09:00-18:00") of the
+// working days of the calendar, in CALENDAR_TIMEZONE. The returned policy is
+// usable when the hours or the calendar fail to load
+func Load() (Policy, error) {
+	policy := Policy{Channels: map[string]bool{}}
+	for _, channel := range strings.Split(env.GetEnv("QUIET_CHANNELS", ""), ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			policy.Channels[channel] = true
+		}
+	}
+
+	hours := env.GetEnv("MENTION_HOURS", "")
+	if hours == "" {
+		return policy, nil
+	}
+
+	start, end, err := ParseHours(hours)
+	if err != nil {
+		return policy, err
+	}
+	policy.Start, policy.End = start, end
+
+	policy.Calendar, err = calendar.Load()
+	return policy, err
+}
+
+// "09:00-18:00" as offsets from midnight
+func ParseHours(hours string) (time.Duration, time.Duration, error) {
+	from, to, ok := strings.Cut(hours, "-")
+	start, startErr := parseClock(from)
+	end, endErr := parseClock(to)
+	if !ok || startErr != nil || endErr != nil || end <= start {
+		return 0, 0, fmt.Errorf("invalid hours %q, expected HH:MM-HH:MM", hours)
+	}
+	return start, end, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// whether mentions posted to the channel at now must not notify
+func (p Policy) Quiet(channel string, now time.Time) bool {
+	return p.Channels[channel] || !p.InWindow(now)
+}
+
+// whether now is within the mention hours of a working day
+func (p Policy) InWindow(now time.Time) bool {
+	if p.End == 0 {
+		return true
+	}
+	if !p.Calendar.Working(now) {
+		return false
+	}
+
+	local := now.In(p.location())
+	year, month, day := local.Date()
+	offset := local.Sub(time.Date(year, month, day, 0, 0, 0, 0, p.location()))
+	return offset >= p.Start && offset < p.End
+}
+
+// start of the next mention window, now when it is within one
+func (p Policy) NextWindow(now time.Time) time.Time {
+	if p.InWindow(now) {
+		return now
+	}
+
+	year, month, day := now.In(p.location()).Date()
+	// a year of holidays at most
+	for i := 0; i <= 366; i++ {
+		start := time.Date(year, month, day+i, 0, 0, 0, 0, p.location()).Add(p.Start)
+		if start.After(now) && p.Calendar.Working(start) {
+			return start
+		}
+	}
+	return now
+}
+
+func (p Policy) location() *time.Location {
+	if p.Calendar.Location == nil {
+		return time.UTC
+	}
+	return p.Calendar.Location
+}
+
+var slackMention = regexp.MustCompile(`<@([A-Z0-9]+)>`)
+
+// Slack user ids mentioned in the text, in order and without duplicates
+func Ids(text string) []string {
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, match := range slackMention.FindAllStringSubmatch(text, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			ids = append(ids, match[1])
+		}
+	}
+	return ids
+}
+
+// the mentions of ids written as @<github login>, or @<id> when the user is
+// not linked, so they don't notify
+func Plain(text string, ids []string) string {
+	logins := map[string]string{}
+	for login, id := range mapstruct.StructToMap(*constants.SlackUsers()) {
+		logins[fmt.Sprintf("%s", id)] = login
+	}
+
+	for _, id := range ids {
+		name, ok := logins[id]
+		if !ok {
+			name = id
+		}
+		text = strings.ReplaceAll(text, fmt.Sprintf("<@%s>", id), "@"+name)
+	}
+	return text
+}
@@ -0,0 +1,98 @@
+package mentions
+
+import (
+	"slack-pr-lambda/calendar"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("QUIET_CHANNELS", "C1, C2")
+	t.Setenv("MENTION_HOURS", "09:00-18:30")
+
+	policy, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !policy.Channels["C1"] || !policy.Channels["C2"] || len(policy.Channels) != 2 {
+		t.Errorf("Unexpected channels %v", policy.Channels)
+	}
+	if policy.Start != 9*time.Hour || policy.End != 18*time.Hour+30*time.Minute {
+		t.Errorf("Unexpected window %v-%v", policy.Start, policy.End)
+	}
+
+	for _, hours := range []string{"9-18", "18:00-09:00", "09:00"} {
+		t.Setenv("MENTION_HOURS", hours)
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected an error for %q", hours)
+		}
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	policy := Policy{
+		Channels: map[string]bool{"C2": true},
+		Start:    9 * time.Hour,
+		End:      18 * time.Hour,
+		Calendar: calendar.Calendar{Weekends: true},
+	}
+
+	tests := []struct {
+		channel string
+		now     time.Time
+		quiet   bool
+	}{
+		{"C1", time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC), false},
+		{"C2", time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC), true},
+		{"C1", time.Date(2024, 3, 11, 18, 0, 0, 0, time.UTC), true},
+		{"C1", time.Date(2024, 3, 11, 8, 59, 0, 0, time.UTC), true},
+		// Saturday
+		{"C1", time.Date(2024, 3, 9, 10, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		if result := policy.Quiet(tt.channel, tt.now); result != tt.quiet {
+			t.Errorf("%s at %s: got %v want %v", tt.channel, tt.now, result, tt.quiet)
+		}
+	}
+
+	if (Policy{}).Quiet("C1", time.Date(2024, 3, 9, 3, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected mentions at any time without mention hours")
+	}
+}
+
+func TestNextWindow(t *testing.T) {
+	policy := Policy{
+		Start:    9 * time.Hour,
+		End:      18 * time.Hour,
+		Calendar: calendar.Calendar{Weekends: true},
+	}
+
+	tests := map[time.Time]time.Time{
+		// within the window
+		time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC): time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC),
+		// early morning
+		time.Date(2024, 3, 11, 7, 0, 0, 0, time.UTC): time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC),
+		// Friday evening
+		time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC): time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC),
+	}
+
+	for now, expected := range tests {
+		if result := policy.NextWindow(now); !result.Equal(expected) {
+			t.Errorf("%s: got %s want %s", now, result, expected)
+		}
+	}
+}
+
+func TestPlain(t *testing.T) {
+	text := "Please review: <@U1> :eyes:<@U2> :eyes:<@U1>"
+
+	ids := Ids(text)
+	if len(ids) != 2 || ids[0] != "U1" || ids[1] != "U2" {
+		t.Errorf("Unexpected ids %v", ids)
+	}
+
+	if result := Plain(text, []string{"U1"}); result != "Please review: @U1 :eyes:<@U2> :eyes:@U1" {
+		t.Errorf("got %q", result)
+	}
+}
@@ -0,0 +1,3 @@
+module slack-pr-lambda/metrics
+
+go 1.22
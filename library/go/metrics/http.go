@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	httpRequests = NewCounter("http_requests_total", "HTTP requests by route and status code.", "route", "status")
+	httpDuration = NewHistogram("http_request_duration_seconds", "HTTP request latency by route.", DefaultBuckets, "route")
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// count the requests and latency of a route, the route pattern is used as
+// label so path parameters don't create new series
+func Instrument(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next(recorder, r)
+
+		httpRequests.Inc(route, strconv.Itoa(recorder.status))
+		httpDuration.Observe(time.Since(start).Seconds(), route)
+	}
+}
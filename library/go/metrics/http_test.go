@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstrument(t *testing.T) {
+	handler := Instrument("POST /test", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+	})
+
+	req := httptest.NewRequest("POST", "/test", nil)
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected the handler status, got %d", rr.Code)
+	}
+	if value := httpRequests.Value("POST /test", "400"); value != 1 {
+		t.Errorf("Expected 1 request, got %v", value)
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	counter := NewCounter("test_events_total", "Test events.", "action")
+	counter.Inc("opened")
+	counter.Inc("opened")
+	counter.Add(3, "closed")
+
+	if value := counter.Value("opened"); value != 2 {
+		t.Errorf("Expected 2, got %v", value)
+	}
+
+	var buf bytes.Buffer
+	counter.write(&buf)
+
+	expected := `# HELP test_events_total Test events.
+# TYPE test_events_total counter
+test_events_total{action="closed"} 3
+test_events_total{action="opened"} 2
+`
+	if buf.String() != expected {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogram("test_duration_seconds", "Test latency.", []float64{0.1, 1}, "method")
+	histogram.Observe(0.05, "post")
+	histogram.Observe(0.5, "post")
+	histogram.Observe(2, "post")
+
+	if count := histogram.Count("post"); count != 3 {
+		t.Errorf("Expected 3, got %v", count)
+	}
+
+	var buf bytes.Buffer
+	histogram.write(&buf)
+
+	expected := `# HELP test_duration_seconds Test latency.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{method="post",le="0.1"} 1
+test_duration_seconds_bucket{method="post",le="1"} 2
+test_duration_seconds_bucket{method="post",le="+Inf"} 3
+test_duration_seconds_sum{method="post"} 2.55
+test_duration_seconds_count{method="post"} 3
+`
+	if buf.String() != expected {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), expected)
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	counter := NewCounter("test_escaped_total", "Test escaping.", "repo")
+	counter.Inc(`a"b`)
+	counter.Inc("web\tapp\nv2")
+	counter.Inc("café")
+
+	var buf bytes.Buffer
+	counter.write(&buf)
+
+	for _, expected := range []string{`test_escaped_total{repo="a\"b"} 1`, "test_escaped_total{repo=\"web\tapp\\nv2\"} 1", `test_escaped_total{repo="café"} 1`} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q, got %s", expected, buf.String())
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	NewCounter("test_registered_total", "Test registry.").Inc()
+
+	var buf bytes.Buffer
+	Write(&buf)
+
+	if !strings.Contains(buf.String(), "test_registered_total 1\n") {
+		t.Errorf("Expected registered counter, got %s", buf.String())
+	}
+}
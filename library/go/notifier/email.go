@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"go.uber.org/zap"
+)
+
+// SES email to one address, sent from EMAIL_FROM
+type Email struct {
+	To      string
+	Subject string
+}
+
+// replaced in tests
+var sendEmail = func(input *ses.SendEmailInput) error {
+	region := env.GetEnv("REGION", "us-east-1")
+	_, err := ses.New(awsSession(), &aws.Config{Region: &region}).SendEmail(input)
+	return err
+}
+
+// emails are off without a verified EMAIL_FROM sender
+func EmailEnabled() bool {
+	return env.GetEnv("EMAIL_FROM", "") != ""
+}
+
+// IAM actions of the email notifier
+func EmailActions() []string {
+	return []string{"ses:SendEmail"}
+}
+
+func (e Email) Notify(text string) error {
+	from := env.GetEnv("EMAIL_FROM", "")
+	body := Plain(text)
+
+	if dryrun.Enabled() {
+		dryrun.Log("ses.send_email", zap.String("to", e.To), zap.String("subject", e.Subject), zap.String("body", body))
+		return nil
+	}
+
+	return sendEmail(&ses.SendEmailInput{
+		Source: aws.String(from),
+		Destination: &ses.Destination{
+			ToAddresses: []*string{aws.String(e.To)},
+		},
+		Message: &ses.Message{
+			Subject: &ses.Content{Charset: aws.String("UTF-8"), Data: aws.String(e.Subject)},
+			Body: &ses.Body{
+				Text: &ses.Content{Charset: aws.String("UTF-8"), Data: aws.String(body)},
+			},
+		},
+	})
+}
+
+// Slack mrkdwn as plain text, links are written as "text (url)"
+func Plain(text string) string {
+	text = slackMention.ReplaceAllStringFunc(text, mentionLogin)
+	text = slackLink.ReplaceAllString(text, "$2 ($1)")
+	text = slackUrl.ReplaceAllString(text, "$1")
+	text = slackBold.ReplaceAllString(text, "$1$2")
+	return strings.TrimSpace(text)
+}
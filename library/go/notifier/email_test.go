@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/ses"
+)
+
+func stubSendEmail(t *testing.T) *[]*ses.SendEmailInput {
+	sent := []*ses.SendEmailInput{}
+	original := sendEmail
+	sendEmail = func(input *ses.SendEmailInput) error {
+		sent = append(sent, input)
+		return nil
+	}
+	t.Cleanup(func() {
+		sendEmail = original
+	})
+	return &sent
+}
+
+func TestEmailEnabled(t *testing.T) {
+	t.Setenv("EMAIL_FROM", "")
+	if EmailEnabled() {
+		t.Errorf("Expected emails to be off without a sender")
+	}
+
+	t.Setenv("EMAIL_FROM", "pulls@acme.com")
+	if !EmailEnabled() {
+		t.Errorf("Expected emails to be on")
+	}
+}
+
+func TestEmail(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	t.Setenv("EMAIL_FROM", "pulls@acme.com")
+	sent := stubSendEmail(t)
+
+	if err := (Email{To: "bob@acme.com", Subject: "Review requested"}).Notify("*Please review* <https://github.com/o/api/pull/7|api#7>"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(*sent) != 1 {
+		t.Fatalf("Expected 1 email, got %v", len(*sent))
+	}
+	input := (*sent)[0]
+	if *input.Source != "pulls@acme.com" || *input.Destination.ToAddresses[0] != "bob@acme.com" || *input.Message.Subject.Data != "Review requested" {
+		t.Errorf("Unexpected email %+v", input)
+	}
+	if body := *input.Message.Body.Text.Data; body != "Please review api#7 (https://github.com/o/api/pull/7)" {
+		t.Errorf("Expected a plain text body, got %q", body)
+	}
+}
+
+func TestEmailDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+	sent := stubSendEmail(t)
+
+	if err := (Email{To: "bob@acme.com"}).Notify("hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Errorf("Expected nothing sent on dry run, got %v", len(*sent))
+	}
+}
+
+func TestPlain(t *testing.T) {
+	data := map[string]string{
+		"<https://github.com/o/api/pull/7|api#7> opened by <@U06Q5GKADME>": "api#7 (https://github.com/o/api/pull/7) opened by @rodentskie",
+		"*Approvals:* 1/2\n": "Approvals: 1/2",
+	}
+
+	for text, expected := range data {
+		if result := Plain(text); result != expected {
+			t.Errorf("Plain(%q): Expected %q, got %q", text, expected, result)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"go.uber.org/zap"
+)
+
+// source of the published events, rules match it with
+// {"source": ["slack-pr-lambda"]}
+const EventSource = "slack-pr-lambda"
+
+// replaced in tests
+var putEvents = func(input *eventbridge.PutEventsInput) (*eventbridge.PutEventsOutput, error) {
+	region := env.GetEnv("REGION", "us-east-1")
+	return eventbridge.New(awsSession(), &aws.Config{Region: &region}).PutEvents(input)
+}
+
+// EVENT_BUS_NAME (name or ARN) receiving the processed webhooks, publishing is
+// off when unset
+func EventBusName() string {
+	return env.GetEnv("EVENT_BUS_NAME", "")
+}
+
+// IAM actions of the event bus publisher
+func EventBusActions() []string {
+	return []string{"events:PutEvents"}
+}
+
+// detail-type of the event, "<github event>.<action>" e.g.
+// "pull_request_review.submitted"
+func DetailType(event types.ForwardedEvent) string {
+	if event.Action == "" {
+		return event.Event
+	}
+	return event.Event + "." + event.Action
+}
+
+// publishes the event to EVENT_BUS_NAME, the detail is the forwarded summary
+func Publish(event types.ForwardedEvent) error {
+	detail, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	entry := &eventbridge.PutEventsRequestEntry{
+		EventBusName: aws.String(EventBusName()),
+		Source:       aws.String(EventSource),
+		DetailType:   aws.String(DetailType(event)),
+		Detail:       aws.String(string(detail)),
+	}
+
+	if dryrun.Enabled() {
+		dryrun.Log("events.put_events", zap.String("detailType", *entry.DetailType), zap.String("detail", *entry.Detail))
+		return nil
+	}
+
+	out, err := putEvents(&eventbridge.PutEventsInput{Entries: []*eventbridge.PutEventsRequestEntry{entry}})
+	if err != nil {
+		return err
+	}
+
+	// PutEvents answers 200 with the entries it rejected
+	if aws.Int64Value(out.FailedEntryCount) > 0 && len(out.Entries) > 0 {
+		return fmt.Errorf("events.put_events failed: %s %s", aws.StringValue(out.Entries[0].ErrorCode), aws.StringValue(out.Entries[0].ErrorMessage))
+	}
+	return nil
+}
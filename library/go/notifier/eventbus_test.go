@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"encoding/json"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+)
+
+func stubPutEvents(t *testing.T, out *eventbridge.PutEventsOutput) *[]*eventbridge.PutEventsInput {
+	put := []*eventbridge.PutEventsInput{}
+	original := putEvents
+	putEvents = func(input *eventbridge.PutEventsInput) (*eventbridge.PutEventsOutput, error) {
+		put = append(put, input)
+		return out, nil
+	}
+	t.Cleanup(func() {
+		putEvents = original
+	})
+	return &put
+}
+
+func TestDetailType(t *testing.T) {
+	if detailType := DetailType(types.ForwardedEvent{Event: "pull_request_review", Action: "submitted"}); detailType != "pull_request_review.submitted" {
+		t.Errorf("Unexpected detail type %v", detailType)
+	}
+	if detailType := DetailType(types.ForwardedEvent{Event: "push"}); detailType != "push" {
+		t.Errorf("Unexpected detail type %v", detailType)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	t.Setenv("EVENT_BUS_NAME", "pulls")
+	put := stubPutEvents(t, &eventbridge.PutEventsOutput{FailedEntryCount: aws.Int64(0)})
+
+	event := types.ForwardedEvent{DeliveryId: "delivery", Event: "pull_request", Action: "closed", Repository: "api", Number: 7, State: "merged"}
+	if err := Publish(event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(*put) != 1 || len((*put)[0].Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %v", *put)
+	}
+	entry := (*put)[0].Entries[0]
+	if *entry.EventBusName != "pulls" || *entry.Source != EventSource || *entry.DetailType != "pull_request.closed" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+
+	var detail types.ForwardedEvent
+	if err := json.Unmarshal([]byte(*entry.Detail), &detail); err != nil || detail != event {
+		t.Errorf("Unexpected detail %v", *entry.Detail)
+	}
+}
+
+func TestPublishFailedEntry(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	stubPutEvents(t, &eventbridge.PutEventsOutput{
+		FailedEntryCount: aws.Int64(1),
+		Entries:          []*eventbridge.PutEventsResultEntry{{ErrorCode: aws.String("ThrottlingException"), ErrorMessage: aws.String("Rate exceeded")}},
+	})
+
+	if err := Publish(types.ForwardedEvent{Event: "pull_request"}); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestPublishDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	put := stubPutEvents(t, nil)
+
+	if err := Publish(types.ForwardedEvent{Event: "pull_request"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(*put) != 0 {
+		t.Errorf("Expected nothing published in dry run, got %v", *put)
+	}
+}
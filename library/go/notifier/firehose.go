@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"go.uber.org/zap"
+)
+
+// PutRecordBatch accepts at most 500 records
+const firehoseMaxBatch = 500
+
+// sends of a batch, records Firehose rejects (throttling, internal errors) are
+// sent again with a backoff
+const firehoseAttempts = 3
+
+// wait before the rejected records are sent again, doubled on every attempt.
+// Replaced in tests
+var firehoseRetryDelay = 200 * time.Millisecond
+
+// replaced in tests
+var putRecordBatch = func(input *firehose.PutRecordBatchInput) (*firehose.PutRecordBatchOutput, error) {
+	region := env.GetEnv("REGION", "us-east-1")
+	return firehose.New(awsSession(), &aws.Config{Region: &region}).PutRecordBatch(input)
+}
+
+// FIREHOSE_STREAM delivery stream receiving the processed webhooks, streaming
+// is off when unset
+func FirehoseStream() string {
+	return env.GetEnv("FIREHOSE_STREAM", "")
+}
+
+// IAM actions of the Firehose sink
+func FirehoseActions() []string {
+	return []string{"firehose:PutRecordBatch"}
+}
+
+// sends the events of the delivery as JSON lines with PutRecordBatch before the
+// invocation ends, a frozen or recycled lambda keeps no records. Rejected
+// records (throttling, internal errors) are retried, those still rejected are
+// reported in the error
+func Stream(events ...types.ForwardedEvent) error {
+	records := [][]byte{}
+	for _, event := range events {
+		record, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		records = append(records, append(record, '\n'))
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	if dryrun.Enabled() {
+		dryrun.Log("firehose.put_record_batch", zap.String("stream", FirehoseStream()), zap.Int("records", len(records)))
+		return nil
+	}
+
+	var err error
+	for start := 0; start < len(records); start += firehoseMaxBatch {
+		end := min(start+firehoseMaxBatch, len(records))
+		if _, batchErr := putFirehoseBatch(records[start:end]); batchErr != nil {
+			err = batchErr
+		}
+	}
+	return err
+}
+
+// records still rejected after the attempts
+func putFirehoseBatch(records [][]byte) ([][]byte, error) {
+	delay := firehoseRetryDelay
+	for attempt := 1; ; attempt++ {
+		input := &firehose.PutRecordBatchInput{DeliveryStreamName: aws.String(FirehoseStream())}
+		for _, record := range records {
+			input.Records = append(input.Records, &firehose.Record{Data: record})
+		}
+
+		out, err := putRecordBatch(input)
+		if err == nil {
+			if aws.Int64Value(out.FailedPutCount) == 0 {
+				return nil, nil
+			}
+
+			// results are in the order of the records, failed ones have an error code
+			rejected := [][]byte{}
+			code := ""
+			for i, result := range out.RequestResponses {
+				if result.ErrorCode != nil && i < len(records) {
+					rejected = append(rejected, records[i])
+					code = aws.StringValue(result.ErrorCode)
+				}
+			}
+			records = rejected
+			err = fmt.Errorf("firehose.put_record_batch rejected %d records: %s", len(rejected), code)
+		}
+
+		if attempt == firehoseAttempts {
+			return records, err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"errors"
+	"slack-pr-lambda/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/firehose"
+)
+
+// every answer rejects the records at the given indexes
+func stubPutRecordBatch(t *testing.T, rejected map[int]bool, err error) *[]*firehose.PutRecordBatchInput {
+	put := []*firehose.PutRecordBatchInput{}
+	original, originalDelay := putRecordBatch, firehoseRetryDelay
+	putRecordBatch = func(input *firehose.PutRecordBatchInput) (*firehose.PutRecordBatchOutput, error) {
+		put = append(put, input)
+		if err != nil {
+			return nil, err
+		}
+
+		out := &firehose.PutRecordBatchOutput{FailedPutCount: aws.Int64(0)}
+		for i := range input.Records {
+			result := &firehose.PutRecordBatchResponseEntry{RecordId: aws.String("id")}
+			if rejected[i] {
+				result = &firehose.PutRecordBatchResponseEntry{ErrorCode: aws.String("ServiceUnavailableException")}
+				*out.FailedPutCount++
+			}
+			out.RequestResponses = append(out.RequestResponses, result)
+		}
+		return out, nil
+	}
+	firehoseRetryDelay = time.Millisecond
+	t.Cleanup(func() {
+		putRecordBatch, firehoseRetryDelay = original, originalDelay
+	})
+	return &put
+}
+
+func TestStreamSendsRightAway(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	t.Setenv("FIREHOSE_STREAM", "pulls")
+	put := stubPutRecordBatch(t, nil, nil)
+
+	err := Stream(
+		types.ForwardedEvent{Event: "pull_request", Repository: "api", Number: 1},
+		types.ForwardedEvent{Event: "pull_request", Repository: "api", Number: 2},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// nothing is kept for a later invocation
+	if len(*put) != 1 || len((*put)[0].Records) != 2 || *(*put)[0].DeliveryStreamName != "pulls" {
+		t.Fatalf("Expected 1 batch of 2 records, got %v", *put)
+	}
+	if record := string((*put)[0].Records[1].Data); !strings.HasSuffix(record, "\n") || !strings.Contains(record, `"number":2`) {
+		t.Errorf("Unexpected record %q", record)
+	}
+}
+
+func TestStreamRetriesRejectedRecords(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	put := stubPutRecordBatch(t, map[int]bool{0: true}, nil)
+
+	err := Stream(types.ForwardedEvent{Number: 1}, types.ForwardedEvent{Number: 2})
+	if err == nil || !strings.Contains(err.Error(), "ServiceUnavailableException") {
+		t.Fatalf("Expected the rejection, got %v", err)
+	}
+
+	// the batch, then the rejected record alone
+	if len(*put) != firehoseAttempts || len((*put)[1].Records) != 1 || !strings.Contains(string((*put)[1].Records[0].Data), `"number":1`) {
+		t.Fatalf("Unexpected batches %v", *put)
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	put := stubPutRecordBatch(t, nil, errors.New("throttled"))
+
+	if err := Stream(types.ForwardedEvent{Number: 1}); err == nil || err.Error() != "throttled" {
+		t.Fatalf("Expected the error, got %v", err)
+	}
+	if len(*put) != firehoseAttempts {
+		t.Errorf("Expected %d attempts, got %d", firehoseAttempts, len(*put))
+	}
+}
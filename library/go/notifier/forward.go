@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// posts of an event to a downstream url, failed ones are retried on network
+// errors, 429 and 5xx answers
+const forwardAttempts = 3
+
+// wait before a failed post is retried, doubled on every attempt. Replaced in
+// tests
+var forwardRetryDelay = 500 * time.Millisecond
+
+// FORWARD_URLS ("https://a.example.com/hook,https://b.example.com/hook")
+// receiving the processed webhooks, none when unset
+func ForwardUrls() []string {
+	urls := []string{}
+	for _, url := range strings.Split(env.GetEnv("FORWARD_URLS", ""), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// "sha256=<hex>" HMAC of the body with FORWARD_SECRET, verified by receivers
+// like GitHub's X-Hub-Signature-256
+func ForwardSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// posts the event to url, signed with FORWARD_SECRET when it is set
+func Forward(url string, event types.ForwardedEvent) error {
+	if dryrun.Enabled() {
+		dryrun.Log("forward.post", zap.String("url", url), zap.Any("event", event))
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	delay := forwardRetryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := forward(url, event, body)
+		if err == nil || !retry || attempt == forwardAttempts {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// whether a failed post is worth retrying
+func forward(url string, event types.ForwardedEvent, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-Event", event.Event)
+	req.Header.Set("X-Forwarded-Delivery", event.DeliveryId)
+	if secret := env.GetEnv("FORWARD_SECRET", ""); secret != "" {
+		req.Header.Set("X-Forwarded-Signature-256", ForwardSignature(secret, body))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("forward.post returned %d", resp.StatusCode)
+	}
+	return false, nil
+}
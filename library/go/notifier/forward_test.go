@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func stubForwardRetryDelay(t *testing.T) {
+	original := forwardRetryDelay
+	forwardRetryDelay = time.Millisecond
+	t.Cleanup(func() {
+		forwardRetryDelay = original
+	})
+}
+
+func TestForwardUrls(t *testing.T) {
+	t.Setenv("FORWARD_URLS", "https://a.example.com/hook, ,https://b.example.com/hook")
+
+	urls := ForwardUrls()
+	if len(urls) != 2 || urls[1] != "https://b.example.com/hook" {
+		t.Errorf("Unexpected urls %v", urls)
+	}
+}
+
+func TestForward(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	t.Setenv("FORWARD_SECRET", "secret")
+	stubForwardRetryDelay(t)
+
+	// fails once, then accepts
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		var event types.ForwardedEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Expected a JSON body, got %v", err)
+		}
+		body, _ := json.Marshal(event)
+		if signature := r.Header.Get("X-Forwarded-Signature-256"); signature != ForwardSignature("secret", body) {
+			t.Errorf("Unexpected signature %v", signature)
+		}
+		if r.Header.Get("X-Forwarded-Event") != "pull_request" || r.Header.Get("X-Forwarded-Delivery") != "delivery" {
+			t.Errorf("Unexpected headers %v", r.Header)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event := types.ForwardedEvent{DeliveryId: "delivery", Event: "pull_request", Action: "opened", Repository: "api", Number: 7}
+	if err := Forward(server.URL, event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected a retry, got %d attempts", attempts)
+	}
+}
+
+func TestForwardRejected(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	stubForwardRetryDelay(t)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if err := Forward(server.URL, types.ForwardedEvent{}); err == nil {
+		t.Error("Expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected no retry of a client error, got %d attempts", attempts)
+	}
+}
+
+func TestForwardSignature(t *testing.T) {
+	// echo -n '{}' | openssl dgst -sha256 -hmac secret
+	expected := "sha256=77325902caca812dc259733aacd046b73817372c777b8d95b402647474516e13"
+	if signature := ForwardSignature("secret", []byte("{}")); signature != expected {
+		t.Errorf("Unexpected signature %v", signature)
+	}
+}
@@ -0,0 +1,13 @@
+module slack-pr-lambda/notifier
+
+go 1.22
+
+require (
+	github.com/aws/aws-sdk-go v1.51.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+)
@@ -0,0 +1,107 @@
+package notifier
+
+import (
+	"fmt"
+	"regexp"
+	"slack-pr-lambda/config"
+	"slack-pr-lambda/constants"
+	"slack-pr-lambda/mapstruct"
+	"slack-pr-lambda/slack"
+	"sync"
+)
+
+// posts a message to one destination, the text is Slack mrkdwn
+type Notifier interface {
+	Notify(text string) error
+}
+
+// copy of the messages in another Slack channel
+type Slack struct {
+	Channel string
+}
+
+func (s Slack) Notify(text string) error {
+	return slack.SlackSendChannelMessage(s.Channel, text)
+}
+
+// notifier of a destination, errors on a destination missing its settings
+type Factory func(destination config.Destination) (Notifier, error)
+
+// notifiers by destination type, see Register
+var factories = struct {
+	sync.RWMutex
+	types map[string]Factory
+}{types: map[string]Factory{
+	"slack": func(destination config.Destination) (Notifier, error) {
+		if destination.Channel == "" {
+			return nil, fmt.Errorf("slack destination without channel")
+		}
+		return Slack{Channel: destination.Channel}, nil
+	},
+	"teams": func(destination config.Destination) (Notifier, error) {
+		if destination.Url == "" {
+			return nil, fmt.Errorf("teams destination without url")
+		}
+		return Teams{Url: destination.Url}, nil
+	},
+	"discord": func(destination config.Destination) (Notifier, error) {
+		if destination.Url == "" {
+			return nil, fmt.Errorf("discord destination without url")
+		}
+		return Discord{Url: destination.Url}, nil
+	},
+	"email": func(destination config.Destination) (Notifier, error) {
+		if destination.To == "" {
+			return nil, fmt.Errorf("email destination without to")
+		}
+		return Email{To: destination.To, Subject: "Pull request notification"}, nil
+	},
+}}
+
+// adds or replaces the notifier of a destination type, e.g. a chat the
+// repository config can then name as {"type": "mattermost", "url": "..."}
+func Register(destinationType string, factory Factory) {
+	factories.Lock()
+	defer factories.Unlock()
+
+	factories.types[destinationType] = factory
+}
+
+// notifier of a destination of the repository config
+func New(destination config.Destination) (Notifier, error) {
+	factories.RLock()
+	factory, ok := factories.types[destination.Type]
+	factories.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown destination type %q", destination.Type)
+	}
+	return factory(destination)
+}
+
+var (
+	slackLink    = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)\|([^>]+)>`)
+	slackUrl     = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)>`)
+	slackMention = regexp.MustCompile(`<@([A-Z0-9]+)>`)
+	slackBold    = regexp.MustCompile(`(^|[\s(])\*([^*\s]|[^*\s][^*\n]*[^*\s])\*`)
+)
+
+// "<@U123>" as "@<github login>", or "@U123" when the user is not linked
+func mentionLogin(mention string) string {
+	id := slackMention.FindStringSubmatch(mention)[1]
+	for login, slackUserId := range mapstruct.StructToMap(*constants.SlackUsers()) {
+		if fmt.Sprintf("%s", slackUserId) == id {
+			return "@" + login
+		}
+	}
+	return "@" + id
+}
+
+// Slack mrkdwn as the markdown of Teams and Discord, mentions are written as
+// @<github login> since the Slack ids mean nothing there
+func Markdown(text string) string {
+	text = slackMention.ReplaceAllStringFunc(text, mentionLogin)
+	text = slackLink.ReplaceAllString(text, "[$2]($1)")
+	text = slackUrl.ReplaceAllString(text, "$1")
+	return slackBold.ReplaceAllString(text, "$1**$2**")
+}
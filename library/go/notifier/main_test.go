@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"reflect"
+	"slack-pr-lambda/config"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	data := []struct {
+		destination config.Destination
+		expected    Notifier
+	}{
+		{config.Destination{Type: "slack", Channel: "C2"}, Slack{Channel: "C2"}},
+		{config.Destination{Type: "teams", Url: "https://teams/1"}, Teams{Url: "https://teams/1"}},
+		{config.Destination{Type: "discord", Url: "https://discord/1"}, Discord{Url: "https://discord/1"}},
+		{config.Destination{Type: "email", To: "team@acme.com"}, Email{To: "team@acme.com", Subject: "Pull request notification"}},
+	}
+
+	for _, d := range data {
+		result, err := New(d.destination)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", d.destination.Type, err)
+		}
+		if !reflect.DeepEqual(result, d.expected) {
+			t.Errorf("%s: Expected %+v, got %+v", d.destination.Type, d.expected, result)
+		}
+	}
+
+	for _, destination := range []config.Destination{
+		{Type: "slack"},
+		{Type: "teams"},
+		{Type: "discord", Channel: "C2"},
+		{Type: "email", Url: "https://mail"},
+		{Type: "sms", To: "+61400000000"},
+	} {
+		if _, err := New(destination); err == nil {
+			t.Errorf("%+v: Expected an error", destination)
+		}
+	}
+}
+
+type recorder struct {
+	Url  string
+	sent *[]string
+}
+
+func (r recorder) Notify(text string) error {
+	*r.sent = append(*r.sent, r.Url+": "+text)
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	sent := []string{}
+	Register("mattermost", func(destination config.Destination) (Notifier, error) {
+		return recorder{Url: destination.Url, sent: &sent}, nil
+	})
+	t.Cleanup(func() {
+		factories.Lock()
+		defer factories.Unlock()
+		delete(factories.types, "mattermost")
+	})
+
+	n, err := New(config.Destination{Type: "mattermost", Url: "https://chat/hooks/1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	n.Notify("api#7 opened")
+	if !reflect.DeepEqual(sent, []string{"https://chat/hooks/1: api#7 opened"}) {
+		t.Errorf("Expected the registered notifier, got %v", sent)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	data := map[string]string{
+		"<https://github.com/o/api/pull/7|api#7> opened by <@U06Q5GKADME>": "[api#7](https://github.com/o/api/pull/7) opened by @rodentskie",
+		"*Approvals:* 1/2, cc <@U999>":                                     "**Approvals:** 1/2, cc @U999",
+		"see <https://example.com>":                                        "see https://example.com",
+		"2 * 3 * 4":                                                        "2 * 3 * 4",
+	}
+
+	for text, expected := range data {
+		if result := Markdown(text); result != expected {
+			t.Errorf("Markdown(%q): Expected %q, got %q", text, expected, result)
+		}
+	}
+}
+
+func TestSlackDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	if err := (Slack{Channel: "C2"}).Notify("hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
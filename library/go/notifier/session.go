@@ -0,0 +1,15 @@
+package notifier
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// session of the SES, EventBridge and Firehose clients, the shared config is
+// loaded on first use instead of on every send
+var awsSession = sync.OnceValue(func() *session.Session {
+	return session.Must(session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	}))
+})
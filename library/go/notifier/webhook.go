@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slack-pr-lambda/dryrun"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// longest content of a Discord message
+const maxDiscordContent = 2000
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Microsoft Teams incoming webhook (Workflows or Office 365 connector)
+type Teams struct {
+	Url string
+}
+
+// Discord channel webhook
+type Discord struct {
+	Url string
+}
+
+type teamsTextBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	Wrap bool   `json:"wrap"`
+}
+
+type teamsCard struct {
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []teamsTextBlock `json:"body"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type discordAllowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+type discordMessage struct {
+	Content         string                 `json:"content"`
+	AllowedMentions discordAllowedMentions `json:"allowed_mentions"`
+}
+
+// adaptive card with the text, accepted by both kinds of webhooks
+func (t Teams) Notify(text string) error {
+	return postJSON("teams.post", t.Url, teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: teamsCard{
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body:    []teamsTextBlock{{Type: "TextBlock", Text: Markdown(text), Wrap: true}},
+				},
+			},
+		},
+	})
+}
+
+// @everyone / @here in the text don't ping anyone
+func (d Discord) Notify(text string) error {
+	content := []rune(Markdown(text))
+	if len(content) > maxDiscordContent {
+		content = append(content[:maxDiscordContent-1], '…')
+	}
+
+	return postJSON("discord.post", d.Url, discordMessage{
+		Content:         string(content),
+		AllowedMentions: discordAllowedMentions{Parse: []string{}},
+	})
+}
+
+func postJSON(operation string, url string, payload any) error {
+	if dryrun.Enabled() {
+		dryrun.Log(operation, zap.Any("payload", payload))
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %d", operation, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// webhook recording the posted payloads
+func webhook(t *testing.T, status int) (*httptest.Server, *[]map[string]any) {
+	payloads := []map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected a JSON body, got %s", r.Header.Get("Content-Type"))
+		}
+		payload := map[string]any{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Expected a JSON body, got %v", err)
+		}
+		payloads = append(payloads, payload)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &payloads
+}
+
+func TestTeams(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	server, payloads := webhook(t, http.StatusAccepted)
+
+	if err := (Teams{Url: server.URL}).Notify("*api#7* approved"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(*payloads) != 1 {
+		t.Fatalf("Expected 1 payload, got %v", *payloads)
+	}
+	attachment := (*payloads)[0]["attachments"].([]any)[0].(map[string]any)
+	if attachment["contentType"] != "application/vnd.microsoft.card.adaptive" {
+		t.Errorf("Expected an adaptive card, got %v", attachment["contentType"])
+	}
+	block := attachment["content"].(map[string]any)["body"].([]any)[0].(map[string]any)
+	if block["text"] != "**api#7** approved" {
+		t.Errorf("Expected the markdown text, got %v", block["text"])
+	}
+}
+
+func TestDiscord(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	server, payloads := webhook(t, http.StatusNoContent)
+
+	if err := (Discord{Url: server.URL}).Notify(strings.Repeat("a", 2500)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	content := (*payloads)[0]["content"].(string)
+	if len([]rune(content)) != maxDiscordContent || !strings.HasSuffix(content, "…") {
+		t.Errorf("Expected content truncated to %d characters, got %d", maxDiscordContent, len([]rune(content)))
+	}
+	if mentions := (*payloads)[0]["allowed_mentions"].(map[string]any)["parse"].([]any); len(mentions) != 0 {
+		t.Errorf("Expected no allowed mentions, got %v", mentions)
+	}
+}
+
+func TestWebhookError(t *testing.T) {
+	t.Setenv("DRY_RUN", "false")
+	server, _ := webhook(t, http.StatusBadRequest)
+
+	if err := (Discord{Url: server.URL}).Notify("hello"); err == nil {
+		t.Errorf("Expected an error for a rejected message")
+	}
+}
+
+func TestWebhookDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+	server, payloads := webhook(t, http.StatusOK)
+
+	if err := (Teams{Url: server.URL}).Notify("hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(*payloads) != 0 {
+		t.Errorf("Expected nothing posted on dry run, got %v", *payloads)
+	}
+}
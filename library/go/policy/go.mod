@@ -0,0 +1,3 @@
+module slack-pr-lambda/policy
+
+go 1.22
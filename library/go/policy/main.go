@@ -0,0 +1,59 @@
+package policy
+
+import (
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"time"
+)
+
+// rule name of the metrics and messages, the label or "default"
+func Name(rule config.SlaRule) string {
+	if rule.Label == "" {
+		return "default"
+	}
+	return rule.Label
+}
+
+// first rule with a label of the pull request or without label, in the order
+// of the config. False when no rule applies
+func Match(rules []config.SlaRule, labels []string) (config.SlaRule, bool) {
+	for _, rule := range rules {
+		if rule.FirstResponseHours <= 0 {
+			continue
+		}
+		if rule.Label == "" {
+			return rule, true
+		}
+		for _, label := range labels {
+			if label == rule.Label {
+				return rule, true
+			}
+		}
+	}
+	return config.SlaRule{}, false
+}
+
+// first response SLA of a pull request
+type Status struct {
+	Rule config.SlaRule
+	// working time waited for the first review, until now while waiting
+	Elapsed   time.Duration
+	Responded bool
+	Breached  bool
+}
+
+// only working days of the calendar count towards the SLA, firstReviewAt is
+// empty while waiting for the first review
+func Evaluate(rule config.SlaRule, cal calendar.Calendar, openedAt time.Time, firstReviewAt string, now time.Time) Status {
+	status := Status{Rule: rule}
+
+	end := now
+	if reviewedAt, err := time.Parse(time.RFC3339, firstReviewAt); err == nil {
+		status.Responded = true
+		end = reviewedAt
+	}
+
+	status.Elapsed = cal.Elapsed(openedAt, end)
+	status.Breached = status.Elapsed >= time.Duration(rule.FirstResponseHours)*time.Hour
+	return status
+}
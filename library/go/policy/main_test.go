@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"slack-pr-lambda/calendar"
+	"slack-pr-lambda/config"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	rules := []config.SlaRule{
+		{Label: "hotfix", FirstResponseHours: 2},
+		{Label: "broken"},
+		{FirstResponseHours: 24},
+	}
+
+	tests := []struct {
+		labels   []string
+		expected int
+	}{
+		{[]string{"bug", "hotfix"}, 2},
+		{[]string{"broken"}, 24},
+		{nil, 24},
+	}
+
+	for _, tt := range tests {
+		rule, ok := Match(rules, tt.labels)
+		if !ok || rule.FirstResponseHours != tt.expected {
+			t.Errorf("%v: expected %dh, got %v %v", tt.labels, tt.expected, rule, ok)
+		}
+	}
+
+	if _, ok := Match(rules[:1], []string{"bug"}); ok {
+		t.Errorf("Expected no rule without a default")
+	}
+}
+
+func TestName(t *testing.T) {
+	if name := Name(config.SlaRule{Label: "hotfix"}); name != "hotfix" {
+		t.Errorf("Expected hotfix, got %s", name)
+	}
+	if name := Name(config.SlaRule{}); name != "default" {
+		t.Errorf("Expected default, got %s", name)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rule := config.SlaRule{Label: "hotfix", FirstResponseHours: 2}
+	openedAt := time.Date(2024, 3, 8, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 3, 8, 13, 0, 0, 0, time.UTC)
+
+	status := Evaluate(rule, calendar.Calendar{}, openedAt, "", now)
+	if status.Responded || !status.Breached || status.Elapsed != 3*time.Hour {
+		t.Errorf("Expected a breach while waiting, got %+v", status)
+	}
+
+	status = Evaluate(rule, calendar.Calendar{}, openedAt, "2024-03-08T11:00:00Z", now)
+	if !status.Responded || status.Breached || status.Elapsed != time.Hour {
+		t.Errorf("Expected a response within the SLA, got %+v", status)
+	}
+
+	// Friday evening to Monday morning with weekends paused
+	openedAt = time.Date(2024, 3, 8, 23, 0, 0, 0, time.UTC)
+	now = time.Date(2024, 3, 11, 0, 30, 0, 0, time.UTC)
+	status = Evaluate(rule, calendar.Calendar{Weekends: true}, openedAt, "", now)
+	if status.Breached {
+		t.Errorf("Expected the weekend to be paused, got %+v", status)
+	}
+}
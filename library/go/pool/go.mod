@@ -0,0 +1,3 @@
+module slack-pr-lambda/pool
+
+go 1.22
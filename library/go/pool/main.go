@@ -0,0 +1,59 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/env"
+	"strconv"
+	"sync"
+)
+
+// WORKER_POOL_SIZE, kept low so bursts stay under the Slack rate limits
+func Size() int {
+	size, err := strconv.Atoi(env.GetEnv("WORKER_POOL_SIZE", "4"))
+	if err != nil || size <= 0 {
+		return 4
+	}
+	return size
+}
+
+// run the tasks with at most size of them at once, every task runs even when
+// others fail or panic and their errors are joined
+func Run(size int, tasks []func() error) error {
+	if size <= 0 {
+		size = 1
+	}
+
+	errs := make([]error, len(tasks))
+	queue := make(chan int)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(size, len(tasks)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				errs[i] = run(tasks[i])
+			}
+		}()
+	}
+
+	for i := range tasks {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// a panic of the task is returned as its error instead of crashing the
+// invocation with the other tasks still running
+func run(task func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panic: %v", r)
+		}
+	}()
+	return task()
+}
@@ -0,0 +1,88 @@
+package pool
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var running, peak, done int32
+	tasks := []func() error{}
+	for i := 0; i < 10; i++ {
+		tasks = append(tasks, func() error {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				previous := atomic.LoadInt32(&peak)
+				if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+			return nil
+		})
+	}
+
+	if err := Run(3, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if done != 10 {
+		t.Errorf("Expected every task to run, got %d", done)
+	}
+	if peak > 3 {
+		t.Errorf("Expected at most 3 tasks at once, got %d", peak)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+
+	err := Run(2, []func() error{
+		func() error { return first },
+		func() error { return nil },
+		func() error { return second },
+	})
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("Expected both errors, got %v", err)
+	}
+
+	if err := Run(2, nil); err != nil {
+		t.Errorf("Expected no error without tasks, got %v", err)
+	}
+}
+
+func TestRunPanic(t *testing.T) {
+	var done int32
+	err := Run(2, []func() error{
+		func() error { panic("boom") },
+		func() error { atomic.AddInt32(&done, 1); return nil },
+		func() error { atomic.AddInt32(&done, 1); return nil },
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected the panic as an error, got %v", err)
+	}
+	if done != 2 {
+		t.Errorf("Expected the other tasks to run, got %d", done)
+	}
+}
+
+func TestSize(t *testing.T) {
+	if size := Size(); size != 4 {
+		t.Errorf("Expected the default size, got %d", size)
+	}
+
+	t.Setenv("WORKER_POOL_SIZE", "8")
+	if size := Size(); size != 8 {
+		t.Errorf("Expected 8, got %d", size)
+	}
+
+	t.Setenv("WORKER_POOL_SIZE", "none")
+	if size := Size(); size != 4 {
+		t.Errorf("Expected the default size, got %d", size)
+	}
+}
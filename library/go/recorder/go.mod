@@ -0,0 +1,3 @@
+module slack-pr-lambda/recorder
+
+go 1.22
@@ -0,0 +1,130 @@
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slack-pr-lambda/env"
+	"sort"
+	"strings"
+	"time"
+)
+
+// headers replayed with the body, the handlers only rely on these
+var recordedHeaders = []string{
+	"Content-Type",
+	"X-GitHub-Event",
+	"X-GitHub-Delivery",
+	"X-Hub-Signature-256",
+	"X-GitHub-Enterprise-Host",
+}
+
+type RecordedEvent struct {
+	ReceivedAt string            `json:"receivedAt"`
+	Headers    map[string]string `json:"headers"`
+	// kept verbatim so replayed bodies are byte for byte the received ones
+	Body string `json:"body"`
+}
+
+// RECORD_DIR is set, every incoming webhook is written there
+func Enabled() bool {
+	return env.GetEnv("RECORD_DIR", "") != ""
+}
+
+// persist the webhook as <RECORD_DIR>/<unix nano>-<event>.json, returns the file path
+func Record(header http.Header, body []byte) (string, error) {
+	dir := env.GetEnv("RECORD_DIR", "")
+
+	event := RecordedEvent{
+		ReceivedAt: time.Now().UTC().Format(time.RFC3339Nano),
+		Headers:    map[string]string{},
+		Body:       string(body),
+	}
+	for _, key := range recordedHeaders {
+		if value := header.Get(key); value != "" {
+			event.Headers[key] = value
+		}
+	}
+
+	data, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	name := header.Get("X-GitHub-Event")
+	if name == "" {
+		name = "event"
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d-%s.json", time.Now().UnixNano(), name))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// recorded events of a file, or of every .json file of a directory in the
+// order they were received
+func Load(path string) ([]RecordedEvent, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files = []string{}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(files)
+	}
+
+	events := []RecordedEvent{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var event RecordedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// re-send a recorded event with its original headers
+func Replay(url string, event RecordedEvent) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(event.Body))
+	if err != nil {
+		return 0, err
+	}
+	for key, value := range event.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
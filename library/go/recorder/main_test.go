@@ -0,0 +1,81 @@
+package recorder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("RECORD_DIR", "")
+	if Enabled() {
+		t.Errorf("Expected recording disabled without RECORD_DIR")
+	}
+
+	t.Setenv("RECORD_DIR", t.TempDir())
+	if !Enabled() {
+		t.Errorf("Expected recording enabled with RECORD_DIR")
+	}
+}
+
+func TestRecordAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("RECORD_DIR", dir)
+
+	header := http.Header{}
+	header.Set("X-GitHub-Event", "pull_request")
+	header.Set("X-GitHub-Delivery", "abc")
+	header.Set("Authorization", "secret")
+
+	for _, body := range []string{`{"action":"opened"}`, `{"action":"closed"}`} {
+		if _, err := Record(header, []byte(body)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	events, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Body != `{"action":"opened"}` || events[1].Body != `{"action":"closed"}` {
+		t.Errorf("Unexpected event order %s %s", events[0].Body, events[1].Body)
+	}
+	if events[0].Headers["X-GitHub-Event"] != "pull_request" {
+		t.Errorf("Expected event header, got %v", events[0].Headers)
+	}
+	if _, ok := events[0].Headers["Authorization"]; ok {
+		t.Errorf("Expected only webhook headers to be recorded, got %v", events[0].Headers)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := Load("unknown.json"); err == nil {
+		t.Errorf("Expected error for a missing file")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	var event, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event = r.Header.Get("X-GitHub-Event")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	status, err := Replay(server.URL, RecordedEvent{
+		Headers: map[string]string{"X-GitHub-Event": "pull_request"},
+		Body:    `{"action":"opened"}`,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status != http.StatusOK || event != "pull_request" || body != `{"action":"opened"}` {
+		t.Errorf("Unexpected replay %d %s %s", status, event, body)
+	}
+}
@@ -0,0 +1,20 @@
+package redact
+
+import (
+	"os"
+	"testing"
+)
+
+// the delivery logged for every webhook, shared with the types benchmarks
+func BenchmarkJSON(b *testing.B) {
+	body, err := os.ReadFile("../types/testdata/pull_request_opened.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = JSON(body)
+	}
+}
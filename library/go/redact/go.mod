@@ -0,0 +1,3 @@
+module slack-pr-lambda/redact
+
+go 1.22
@@ -0,0 +1,79 @@
+package redact
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+const Mask = "[REDACTED]"
+
+// values of these keys are masked whatever they look like
+var sensitiveKey = regexp.MustCompile(`(?i)(token|secret|password|passwd|signature|authorization|api_?key|private_?key|credential|email)`)
+
+type pattern struct {
+	expression  *regexp.Regexp
+	replacement string
+}
+
+// ordered, credentials in urls go before emails so user:pass@host is masked whole.
+// The escaped form is JSON quoted in a string, e.g. an error logged as JSON
+var patterns = []pattern{
+	{regexp.MustCompile(`\\"([A-Za-z_]*(?i:token|secret|password|passwd|signature|authorization|api_?key|private_?key|credential|email)[A-Za-z_]*)\\":\s*\\"(?:[^"\\]|\\[^"])+\\"`), `\"$1\":\"` + Mask + `\"`},
+	{regexp.MustCompile(`"([A-Za-z_]*(?i:token|secret|password|passwd|signature|authorization|api_?key|private_?key|credential|email)[A-Za-z_]*)":\s*"(?:[^"\\]|\\.)+"`), `"$1":"` + Mask + `"`},
+	{regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{20,}`), Mask},
+	{regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{20,}`), Mask},
+	{regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]+`), Mask},
+	{regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`), Mask},
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), Mask},
+	// only after the header so prose like "token expired" is kept
+	{regexp.MustCompile(`(?i)\b(authorization:\s*(?:bearer|token|basic))\s+[A-Za-z0-9._~+/=-]{8,}`), "$1 " + Mask},
+	{regexp.MustCompile(`\b(sha1|sha256)=[0-9a-fA-F]{40,64}\b`), "$1=" + Mask},
+	{regexp.MustCompile(`://[^/\s:@"]+:[^/\s@"]+@`), "://" + Mask + "@"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), Mask},
+}
+
+// mask tokens, signatures, credentials and emails found in free text
+func String(text string) string {
+	for _, p := range patterns {
+		text = p.expression.ReplaceAllString(text, p.replacement)
+	}
+	return text
+}
+
+// mask a JSON payload before it is logged, values of sensitive keys are
+// masked and every other string goes through String, invalid JSON is masked
+// as text
+func JSON(body []byte) []byte {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return []byte(String(string(body)))
+	}
+
+	result, err := json.Marshal(value(payload))
+	if err != nil {
+		return []byte(String(string(body)))
+	}
+	return result
+}
+
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if text, ok := item.(string); ok && text != "" && sensitiveKey.MatchString(key) {
+				v[key] = Mask
+				continue
+			}
+			v[key] = value(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = value(item)
+		}
+		return v
+	case string:
+		return String(v)
+	}
+	return v
+}
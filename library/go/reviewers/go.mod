@@ -0,0 +1,3 @@
+module slack-pr-lambda/reviewers
+
+go 1.22
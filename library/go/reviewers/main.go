@@ -0,0 +1,77 @@
+package reviewers
+
+import (
+	"slack-pr-lambda/types"
+	"sort"
+	"time"
+)
+
+// date format for out of office ranges, e.g. 2024-03-25
+const DateLayout = "2006-01-02"
+
+// the range is inclusive on both ends, dates are compared in UTC
+func IsOutOfOffice(item types.TableOutOfOfficeData, now time.Time) bool {
+	start, err := time.Parse(DateLayout, item.StartDate)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(DateLayout, item.EndDate)
+	if err != nil {
+		return false
+	}
+
+	today, _ := time.Parse(DateLayout, now.UTC().Format(DateLayout))
+
+	return !today.Before(start) && !today.After(end)
+}
+
+// split reviewers (github logins) into available and out of office
+func FilterOutOfOffice(logins []string, ooo map[string]types.TableOutOfOfficeData, now time.Time) ([]string, []types.TableOutOfOfficeData) {
+	available := []string{}
+	away := []types.TableOutOfOfficeData{}
+
+	for _, login := range logins {
+		item, ok := ooo[login]
+		if ok && IsOutOfOffice(item, now) {
+			away = append(away, item)
+			continue
+		}
+		available = append(available, login)
+	}
+
+	return available, away
+}
+
+// pick up to limit reviewers from the eligible pool that are not excluded and
+// not out of office. The sorted pool is walked from offset, e.g. the pull request
+// number, so suggestions rotate instead of always naming the same reviewers
+func SuggestAlternates(pool []string, exclude []string, ooo map[string]types.TableOutOfOfficeData, now time.Time, limit int, offset int) []string {
+	skip := make(map[string]bool)
+	for _, login := range exclude {
+		skip[login] = true
+	}
+
+	candidates := []string{}
+	for _, login := range pool {
+		if !skip[login] {
+			skip[login] = true
+			candidates = append(candidates, login)
+		}
+	}
+	sort.Strings(candidates)
+
+	alternates := []string{}
+	for i := range candidates {
+		if len(alternates) >= limit {
+			break
+		}
+		start := (offset%len(candidates) + len(candidates)) % len(candidates)
+		login := candidates[(start+i)%len(candidates)]
+		if item, ok := ooo[login]; ok && IsOutOfOffice(item, now) {
+			continue
+		}
+		alternates = append(alternates, login)
+	}
+
+	return alternates
+}
@@ -0,0 +1,78 @@
+package reviewers
+
+import (
+	"reflect"
+	"slack-pr-lambda/types"
+	"testing"
+	"time"
+)
+
+func TestIsOutOfOffice(t *testing.T) {
+	item := types.TableOutOfOfficeData{
+		StartDate: "2024-03-10",
+		EndDate:   "2024-03-12",
+	}
+
+	data := []struct {
+		now      string
+		expected bool
+	}{
+		{"2024-03-09T23:59:00Z", false},
+		{"2024-03-10T00:00:00Z", true},
+		{"2024-03-12T23:00:00Z", true},
+		{"2024-03-13T00:00:00Z", false},
+	}
+
+	for _, e := range data {
+		now, _ := time.Parse(time.RFC3339, e.now)
+		if result := IsOutOfOffice(item, now); result != e.expected {
+			t.Errorf("Expected %v at %s, got %v", e.expected, e.now, result)
+		}
+	}
+
+	if IsOutOfOffice(types.TableOutOfOfficeData{StartDate: "bad", EndDate: "2024-03-12"}, time.Now()) {
+		t.Errorf("Expected invalid dates to be treated as available")
+	}
+}
+
+func TestFilterOutOfOffice(t *testing.T) {
+	now, _ := time.Parse(DateLayout, "2024-03-11")
+	ooo := map[string]types.TableOutOfOfficeData{
+		"bob":   {GithubLogin: "bob", StartDate: "2024-03-10", EndDate: "2024-03-12"},
+		"carol": {GithubLogin: "carol", StartDate: "2024-01-01", EndDate: "2024-01-02"},
+	}
+
+	available, away := FilterOutOfOffice([]string{"alice", "bob", "carol"}, ooo, now)
+
+	if !reflect.DeepEqual(available, []string{"alice", "carol"}) {
+		t.Errorf("Unexpected available reviewers %v", available)
+	}
+	if len(away) != 1 || away[0].GithubLogin != "bob" {
+		t.Errorf("Unexpected away reviewers %v", away)
+	}
+}
+
+func TestSuggestAlternates(t *testing.T) {
+	now, _ := time.Parse(DateLayout, "2024-03-11")
+	ooo := map[string]types.TableOutOfOfficeData{
+		"dave": {GithubLogin: "dave", StartDate: "2024-03-10", EndDate: "2024-03-12"},
+	}
+	pool := []string{"erin", "dave", "bob", "alice", "carol"}
+
+	result := SuggestAlternates(pool, []string{"alice", "bob"}, ooo, now, 2, 0)
+
+	if !reflect.DeepEqual(result, []string{"carol", "erin"}) {
+		t.Errorf("Unexpected alternates %v", result)
+	}
+
+	// the next pull request starts further in the pool
+	result = SuggestAlternates(pool, []string{"alice", "bob"}, ooo, now, 2, 1)
+
+	if !reflect.DeepEqual(result, []string{"erin", "carol"}) {
+		t.Errorf("Unexpected rotated alternates %v", result)
+	}
+
+	if result := SuggestAlternates(nil, nil, ooo, now, 2, 7); len(result) != 0 {
+		t.Errorf("Expected no alternates of an empty pool, got %v", result)
+	}
+}
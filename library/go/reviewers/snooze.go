@@ -0,0 +1,38 @@
+package reviewers
+
+import "time"
+
+type SnoozeOption struct {
+	ActionId string
+	Text     string
+	Duration time.Duration
+}
+
+// snooze buttons attached to reminder messages
+func SnoozeOptions() []SnoozeOption {
+	return []SnoozeOption{
+		{ActionId: "snooze_4h", Text: "Snooze 4h", Duration: 4 * time.Hour},
+		{ActionId: "snooze_1d", Text: "Snooze 1d", Duration: 24 * time.Hour},
+	}
+}
+
+func GetSnoozeOption(actionId string) (SnoozeOption, bool) {
+	for _, option := range SnoozeOptions() {
+		if option.ActionId == actionId {
+			return option, true
+		}
+	}
+	return SnoozeOption{}, false
+}
+
+// drop reviewers whose snooze-until (unix seconds) is still in the future
+func FilterSnoozed(logins []string, snoozes map[string]int64, now time.Time) []string {
+	result := []string{}
+	for _, login := range logins {
+		if until, ok := snoozes[login]; ok && now.Unix() < until {
+			continue
+		}
+		result = append(result, login)
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package reviewers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetSnoozeOption(t *testing.T) {
+	option, ok := GetSnoozeOption("snooze_4h")
+	if !ok || option.Duration != 4*time.Hour {
+		t.Errorf("Unexpected snooze option %+v", option)
+	}
+
+	if _, ok := GetSnoozeOption("unknown"); ok {
+		t.Errorf("Expected unknown action to have no snooze option")
+	}
+}
+
+func TestFilterSnoozed(t *testing.T) {
+	now := time.Unix(1000, 0)
+	snoozes := map[string]int64{
+		"alice": 2000,
+		"bob":   500,
+	}
+
+	result := FilterSnoozed([]string{"alice", "bob", "carol"}, snoozes, now)
+
+	if !reflect.DeepEqual(result, []string{"bob", "carol"}) {
+		t.Errorf("Unexpected reviewers %v", result)
+	}
+}
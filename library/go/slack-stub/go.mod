@@ -0,0 +1,3 @@
+module slack-pr-lambda/slackstub
+
+go 1.22
@@ -0,0 +1,191 @@
+package slackstub
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recorded Slack Web API call
+type Call struct {
+	Method   string    `json:"method"`
+	Channel  string    `json:"channel,omitempty"`
+	Ts       string    `json:"ts,omitempty"`
+	ThreadTs string    `json:"threadTs,omitempty"`
+	Text     string    `json:"text,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Blocks   string    `json:"blocks,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// fake Slack API, point SLACK_API_URL to <url>/api/
+type Server struct {
+	mu    sync.Mutex
+	calls []Call
+	seq   int
+}
+
+func NewServer() *Server {
+	return &Server{}
+}
+
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call{}, s.calls...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/"):
+		s.api(w, r, strings.TrimPrefix(r.URL.Path, "/api/"))
+	case r.URL.Path == "/respond":
+		s.respond(w, r)
+	case r.URL.Path == "/calls":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s.Calls())
+	case r.URL.Path == "/":
+		s.index(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) api(w http.ResponseWriter, r *http.Request, method string) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	call := Call{
+		Method:   method,
+		Channel:  r.Form.Get("channel"),
+		Ts:       r.Form.Get("ts"),
+		ThreadTs: r.Form.Get("thread_ts"),
+		Text:     r.Form.Get("text"),
+		Name:     r.Form.Get("name"),
+		Blocks:   r.Form.Get("blocks"),
+		At:       time.Now(),
+	}
+	if method == "reactions.add" || method == "pins.add" {
+		call.Ts = r.Form.Get("timestamp")
+	}
+
+	s.mu.Lock()
+	if method == "chat.postMessage" {
+		s.seq++
+		call.Ts = fmt.Sprintf("%d.%06d", call.At.Unix(), s.seq)
+	}
+	s.calls = append(s.calls, call)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":      true,
+		"channel": call.Channel,
+		"ts":      call.Ts,
+		"text":    call.Text,
+	})
+}
+
+// response_url of slash commands and interactions
+func (s *Server) respond(w http.ResponseWriter, r *http.Request) {
+	var message struct {
+		Text string `json:"text"`
+	}
+	body, _ := io.ReadAll(r.Body)
+	json.Unmarshal(body, &message)
+
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Method: "respond", Text: message.Text, At: time.Now()})
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+type thread struct {
+	Parent    Call
+	Reactions []string
+	Replies   []Call
+}
+
+// parent messages with their latest text, reactions and thread replies
+func (s *Server) threads() ([]*thread, []Call) {
+	threads := []*thread{}
+	byTs := map[string]*thread{}
+	responses := []Call{}
+
+	for _, call := range s.Calls() {
+		switch call.Method {
+		case "chat.postMessage":
+			if parent, ok := byTs[call.ThreadTs]; ok {
+				parent.Replies = append(parent.Replies, call)
+				continue
+			}
+			t := &thread{Parent: call}
+			byTs[call.Ts] = t
+			threads = append(threads, t)
+		case "chat.update":
+			if t, ok := byTs[call.Ts]; ok {
+				t.Parent.Text = call.Text
+			}
+		case "reactions.add":
+			if t, ok := byTs[call.Ts]; ok {
+				t.Reactions = append(t.Reactions, call.Name)
+			}
+		case "respond":
+			responses = append(responses, call)
+		}
+	}
+
+	return threads, responses
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>Slack stub</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.thread { border: 1px solid #ddd; border-radius: 4px; margin-bottom: 1em; padding: 0.5em 1em; }
+.reply { border-left: 3px solid #ddd; margin: 0.5em 0 0.5em 1em; padding-left: 0.5em; }
+.meta { color: #888; font-size: 0.8em; }
+pre { white-space: pre-wrap; margin: 0.2em 0; }
+</style>
+</head>
+<body>
+<h1>Sent messages</h1>
+{{range .Threads}}
+<div class="thread">
+  <div class="meta">{{.Parent.Channel}} · {{.Parent.Ts}} {{range .Reactions}}:{{.}}: {{end}}</div>
+  <pre>{{.Parent.Text}}</pre>
+  {{range .Replies}}<div class="reply"><div class="meta">{{.Ts}}</div><pre>{{.Text}}</pre></div>{{end}}
+</div>
+{{else}}
+<p>No messages yet.</p>
+{{end}}
+{{if .Responses}}
+<h2>Ephemeral responses</h2>
+{{range .Responses}}<div class="reply"><pre>{{.Text}}</pre></div>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+func (s *Server) index(w http.ResponseWriter) {
+	threads, responses := s.threads()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	indexTemplate.Execute(w, map[string]interface{}{
+		"Threads":   threads,
+		"Responses": responses,
+	})
+}
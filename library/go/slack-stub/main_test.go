@@ -0,0 +1,75 @@
+package slackstub
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func post(t *testing.T, server *httptest.Server, method string, form url.Values) map[string]interface{} {
+	resp, err := http.PostForm(server.URL+"/api/"+method, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestServer(t *testing.T) {
+	stub := NewServer()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	result := post(t, server, "chat.postMessage", url.Values{"channel": {"C1"}, "text": {"opened"}})
+	if result["ok"] != true {
+		t.Fatalf("Expected ok response, got %v", result)
+	}
+	ts := result["ts"].(string)
+
+	post(t, server, "chat.postMessage", url.Values{"channel": {"C1"}, "text": {"please review"}, "thread_ts": {ts}})
+	post(t, server, "chat.update", url.Values{"channel": {"C1"}, "ts": {ts}, "text": {"opened\nApprovals: 0/1"}})
+	post(t, server, "reactions.add", url.Values{"channel": {"C1"}, "timestamp": {ts}, "name": {"opened"}})
+
+	resp, err := http.Post(server.URL+"/respond", "application/json", strings.NewReader(`{"text":"snoozed"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if calls := stub.Calls(); len(calls) != 5 {
+		t.Fatalf("Expected 5 recorded calls, got %d", len(calls))
+	}
+
+	threads, responses := stub.threads()
+	if len(threads) != 1 {
+		t.Fatalf("Expected 1 thread, got %d", len(threads))
+	}
+	if threads[0].Parent.Text != "opened\nApprovals: 0/1" {
+		t.Errorf("Expected updated parent text, got %q", threads[0].Parent.Text)
+	}
+	if len(threads[0].Replies) != 1 || len(threads[0].Reactions) != 1 {
+		t.Errorf("Unexpected thread %+v", threads[0])
+	}
+	if len(responses) != 1 || responses[0].Text != "snoozed" {
+		t.Errorf("Unexpected responses %+v", responses)
+	}
+
+	index, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Body.Close()
+	body, _ := io.ReadAll(index.Body)
+	if !strings.Contains(string(body), "please review") {
+		t.Errorf("Expected thread reply in the web UI")
+	}
+}
@@ -0,0 +1,47 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func benchButtons() []SlackButton {
+	return []SlackButton{
+		{ActionId: "snooze", Text: "Snooze 1d", Value: "api#42"},
+		{ActionId: "mute", Text: "Mute", Value: "api#42"},
+		{ActionId: "approve", Text: "Approve", Value: "api#42"},
+	}
+}
+
+// blocks of a reminder reply, marshalled like they are sent
+func BenchmarkButtonBlocks(b *testing.B) {
+	buttons := benchButtons()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		blocks := ButtonBlocks("<@U1> *api#42* waits on your review since yesterday.", buttons)
+		if _, err := json.Marshal(blocks); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// a full home tab, cut at the 100 blocks Slack accepts
+func BenchmarkHomeBlocks(b *testing.B) {
+	sections := []HomeSection{}
+	for i := 0; i < 60; i++ {
+		sections = append(sections, HomeSection{
+			Text:    fmt.Sprintf("*<https://github.com/o/api/pull/%d|api#%d>* Add review reminders · Approvals: 0/1", i, i),
+			Buttons: benchButtons(),
+		})
+	}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		blocks := HomeBlocks(sections)
+		if _, err := json.Marshal(blocks); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package slack
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// events whose thread reply can be broadcast
+var BroadcastEvents = []string{"approved", "changes_requested", "merged", "closed", "checks_passed", "checks_failed"}
+
+// whether the thread replies of the event are also shown in the channel,
+// BROADCAST_EVENTS ("C0123=approved|merged|checks_failed,C0456=merged") lists
+// the events per channel, none when unset
+func Broadcasts(channel string, event string) bool {
+	for _, entry := range strings.Split(env.GetEnv("BROADCAST_EVENTS", ""), ",") {
+		id, events, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || id != channel {
+			continue
+		}
+		for _, name := range strings.Split(events, "|") {
+			if strings.TrimSpace(name) == event {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reply in the thread of timeStamp also shown in the channel (reply_broadcast),
+// returns the timestamp of the reply
+func SlackBroadcastMessageThread(timeStamp string, message string) (string, error) {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel := Channel()
+	message = label(message)
+	if dryrun.Enabled() {
+		dryrun.Log("slack.broadcast_message_thread", zap.String("channel", channel), zap.String("timeStamp", timeStamp), zap.String("message", message))
+		return "dry-run", nil
+	}
+
+	api := slackClient(token)
+
+	_, reply, err := postMessage(
+		api,
+		channel,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionTS(timeStamp),
+		slack.MsgOptionBroadcast(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return reply, nil
+}
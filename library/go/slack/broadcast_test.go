@@ -0,0 +1,51 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBroadcasts(t *testing.T) {
+	t.Setenv("BROADCAST_EVENTS", "")
+	if Broadcasts("C1", "merged") {
+		t.Errorf("Expected no broadcast when unset")
+	}
+
+	t.Setenv("BROADCAST_EVENTS", "C1=approved|merged, C2=checks_failed,invalid")
+	for _, test := range []struct {
+		channel  string
+		event    string
+		expected bool
+	}{
+		{"C1", "approved", true},
+		{"C1", "merged", true},
+		{"C1", "checks_failed", false},
+		{"C2", "checks_failed", true},
+		{"C3", "merged", false},
+	} {
+		if got := Broadcasts(test.channel, test.event); got != test.expected {
+			t.Errorf("%s %s: Expected %v, got %v", test.channel, test.event, test.expected, got)
+		}
+	}
+}
+
+func TestSlackBroadcastMessageThread(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("reply_broadcast") != "true" || r.Form.Get("thread_ts") != "1.000000" {
+			t.Errorf("Expected a broadcast reply, got %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true, "channel": "C1", "ts": "1.000001"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_CHANNEL", "C1")
+
+	reply, err := SlackBroadcastMessageThread("1.000000", "merged")
+	if err != nil || reply != "1.000001" {
+		t.Errorf("Expected the reply timestamp, got %q %v", reply, err)
+	}
+}
@@ -0,0 +1,53 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"slack-pr-lambda/apperrors"
+
+	"github.com/slack-go/slack"
+)
+
+// a 429 answer of method as apperrors.ErrSlackRateLimited, with the wait Slack
+// asked for. Other errors are returned as is
+func classify(method string, err error) error {
+	var limited *slack.RateLimitedError
+	if errors.As(err, &limited) {
+		return &apperrors.Error{Kind: apperrors.ErrSlackRateLimited, Op: "slack." + method, Err: err, RetryAfter: limited.RetryAfter}
+	}
+	return err
+}
+
+// channel the bot is not in and could not join, e.g. a private channel it was
+// never invited to
+type NotInChannelError struct {
+	Channel string
+	// error of conversations.join
+	Err error
+}
+
+func (e *NotInChannelError) Error() string {
+	return fmt.Sprintf("not_in_channel %s: %v", e.Channel, e.Err)
+}
+
+func (e *NotInChannelError) Unwrap() error {
+	return e.Err
+}
+
+// a bot removed from a public channel joins it again and the message is
+// retried once, a *NotInChannelError names the channel when it can't join.
+// Links are not unfurled, pull request links pasted by people get the card of
+// SlackUnfurl instead
+func postMessage(api *slack.Client, channel string, options ...slack.MsgOption) (string, string, error) {
+	options = append(options, slack.MsgOptionDisableLinkUnfurl())
+	respChannel, timestamp, err := api.PostMessage(channel, options...)
+	if err == nil || err.Error() != "not_in_channel" {
+		return respChannel, timestamp, classify("chat.postMessage", err)
+	}
+
+	if _, _, _, err := api.JoinConversation(channel); err != nil {
+		return "", "", &NotInChannelError{Channel: channel, Err: err}
+	}
+	respChannel, timestamp, err = api.PostMessage(channel, options...)
+	return respChannel, timestamp, classify("chat.postMessage", err)
+}
@@ -0,0 +1,93 @@
+package slack
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/apperrors"
+	"testing"
+	"time"
+)
+
+// posts fail with not_in_channel until the bot joined, returns the called
+// methods
+func notInChannelServer(t *testing.T, joinError string) *[]string {
+	methods := []string{}
+	joined := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/conversations.join" && joinError != "":
+			w.Write([]byte(`{"ok": false, "error": "` + joinError + `"}`))
+		case r.URL.Path == "/api/conversations.join":
+			joined = true
+			w.Write([]byte(`{"ok": true, "channel": {"id": "C1"}}`))
+		case !joined:
+			w.Write([]byte(`{"ok": false, "error": "not_in_channel"}`))
+		default:
+			w.Write([]byte(`{"ok": true, "channel": "C1", "ts": "1.000001"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_CHANNEL", "C1")
+	return &methods
+}
+
+func TestPostMessageJoin(t *testing.T) {
+	methods := notInChannelServer(t, "")
+
+	timeStamp, err := SlackSendMessageThread("1.000000", "hello")
+	if err != nil || timeStamp != "1.000001" {
+		t.Fatalf("Expected the message posted after joining, got %q %v", timeStamp, err)
+	}
+
+	expected := []string{"/api/chat.postMessage", "/api/conversations.join", "/api/chat.postMessage"}
+	if len(*methods) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, *methods)
+	}
+	for i := range expected {
+		if (*methods)[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, *methods)
+		}
+	}
+}
+
+func TestPostMessageJoinFailed(t *testing.T) {
+	notInChannelServer(t, "method_not_supported_for_channel_type")
+
+	_, err := SlackSendMessageThread("1.000000", "hello")
+
+	var notInChannel *NotInChannelError
+	if !errors.As(err, &notInChannel) || notInChannel.Channel != "C1" {
+		t.Fatalf("Expected the channel to be named, got %v", err)
+	}
+	if err.Error() != "not_in_channel C1: method_not_supported_for_channel_type" {
+		t.Errorf("Unexpected error %q", err.Error())
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_CHANNEL", "C1")
+
+	_, err := SlackSendMessageThread("1.000000", "hello")
+	if !errors.Is(err, apperrors.ErrSlackRateLimited) {
+		t.Fatalf("Expected a rate limited error, got %v", err)
+	}
+	if after := apperrors.RetryAfter(err); after != 30*time.Second {
+		t.Errorf("Expected to retry after 30s, got %s", after)
+	}
+
+	if err := SlackUpdateMessage("1.000000", "hello"); !errors.Is(err, apperrors.ErrSlackRateLimited) {
+		t.Errorf("Expected a rate limited update, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package slack
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slack-pr-lambda/types"
+	"strings"
+	"testing"
+)
+
+// go test -run TestContract -update rewrites the golden files, review their
+// diff like the code change
+var update = flag.Bool("update", false, "rewrite the golden files of the contract tests")
+
+// form fields Slack decodes as JSON, kept as JSON in the golden files so a
+// change of a block shows as such
+var jsonFields = map[string]bool{"blocks": true, "attachments": true, "unfurls": true}
+
+// every request sent to the stub Slack API, as method and body
+func contractServer(t *testing.T) *[]map[string]any {
+	requests := []map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, map[string]any{
+			"method": strings.TrimPrefix(r.URL.Path, "/api/"),
+			"body":   requestBody(t, r.Header.Get("Content-Type"), body),
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true, "channel": "C1", "ts": "1.000001"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_CHANNEL", "C1")
+	return &requests
+}
+
+func requestBody(t *testing.T, contentType string, body []byte) map[string]any {
+	fields := map[string]any{}
+	if strings.HasPrefix(contentType, "application/json") {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			t.Fatal(err)
+		}
+		return fields
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, values := range form {
+		// sent in the Authorization header by newer clients
+		if name == "token" {
+			continue
+		}
+		var value any = values[0]
+		if jsonFields[name] {
+			if err := json.Unmarshal([]byte(values[0]), &value); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+		fields[name] = value
+	}
+	return fields
+}
+
+// the requests against testdata/contract/<name>.json
+func assertContract(t *testing.T, name string, requests []map[string]any) {
+	got, err := json.MarshalIndent(requests, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", "contract", name+".json")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run go test -run TestContract -update", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s changed, run go test -run TestContract -update and review the diff\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestContract(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		env  map[string]string
+		call func() error
+	}{
+		{"parent_message", nil, func() error {
+			_, err := SlackSendMessage(types.OpenPullRequest{}, ":pr: <https://github.com/owner/api/pull/7|api#7> Add login")
+			return err
+		}},
+		{"thread_reply", nil, func() error {
+			_, err := SlackSendMessageThread("1.000000", ":white_check_mark: <@U1> approved the pull request.")
+			return err
+		}},
+		{"broadcast_reply", nil, func() error {
+			_, err := SlackBroadcastMessageThread("1.000000", ":merged: Merged.")
+			return err
+		}},
+		{"buttons", nil, func() error {
+			_, err := SlackSendMessageThreadWithButtons("1.000000", "Ready to merge.", []SlackButton{
+				{ActionId: "merge", Text: "Merge", Value: `{"id":"api","pullRequestId":7}`},
+				{ActionId: "snooze", Text: "Snooze", Value: "7"},
+			})
+			return err
+		}},
+		{"images", nil, func() error {
+			_, err := SlackSendMessageThreadWithImages("1.000000", "Images of the description:", []types.Image{
+				{Url: "https://example.com/screenshot.png", Alt: "screenshot"},
+			})
+			return err
+		}},
+		{"update", nil, func() error {
+			return SlackUpdateMessage("1.000000", ":pr: <https://github.com/owner/api/pull/7|api#7> Add login\n:white_check_mark: approved")
+		}},
+		{"channel_message", nil, func() error {
+			_, err := SlackPostChannelMessage("C2", "1.000000", "Copy of the thread reply.")
+			return err
+		}},
+		{"unfurl", nil, func() error {
+			return SlackUnfurl("C1", "1.000000", map[string]string{"https://github.com/owner/api/pull/7": "*api#7* Add login"})
+		}},
+		{"staging", map[string]string{"ENVIRONMENT": "staging", "SANDBOX_CHANNEL": "CSANDBOX"}, func() error {
+			return SlackSendChannelMessage("C2", "Failure alert.")
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			requests := contractServer(t)
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+			if err := test.call(); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			assertContract(t, test.name, *requests)
+		})
+	}
+}
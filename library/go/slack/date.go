@@ -0,0 +1,24 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Slack date formatting tokens, clients render them in the timezone of the
+// viewer
+const (
+	DateTokens     = "{date_short_pretty}"
+	DateTimeTokens = "{date_short_pretty} {time}"
+)
+
+// <!date^...> of t, the UTC fallback is shown where Slack can't format the
+// date, e.g. in notifications of older clients
+func SlackDate(t time.Time, tokens string) string {
+	fallback := t.UTC().Format(time.DateOnly)
+	if strings.Contains(tokens, "{time") {
+		fallback = t.UTC().Format("2006-01-02 15:04 UTC")
+	}
+	return fmt.Sprintf("<!date^%d^%s|%s>", t.Unix(), tokens, fallback)
+}
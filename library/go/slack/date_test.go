@@ -0,0 +1,15 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackDate(t *testing.T) {
+	at := time.Date(2024, 3, 8, 14, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	assert.Equal(t, "<!date^1709874000^{date_short_pretty} {time}|2024-03-08 05:00 UTC>", SlackDate(at, DateTimeTokens))
+	assert.Equal(t, "<!date^1709874000^{date_short_pretty}|2024-03-08>", SlackDate(at, DateTokens))
+}
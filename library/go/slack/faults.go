@@ -0,0 +1,44 @@
+package slack
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slack-pr-lambda/faults"
+	"strconv"
+	"strings"
+)
+
+// answers the Slack API calls failed by FAULTS with the status of the fault,
+// e.g. "429" (retried after a second) or "500", so the callers go through
+// their real error handling
+type faultTransport struct {
+	next http.RoundTripper
+}
+
+func (t faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault := faults.Next("slack", path.Base(req.URL.Path))
+	status, err := strconv.Atoi(fault)
+	if fault == "" || err != nil {
+		return t.next.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	resp := &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"ok":false,"error":"injected_fault"}`)),
+		Request:    req,
+	}
+	if status == http.StatusTooManyRequests {
+		resp.Header.Set("Retry-After", "1")
+	}
+	return resp, nil
+}
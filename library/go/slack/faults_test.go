@@ -0,0 +1,48 @@
+package slack
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/apperrors"
+	"slack-pr-lambda/faults"
+	"testing"
+	"time"
+)
+
+func TestFaults(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true, "channel": "C1", "ts": "1.000001"}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_CHANNEL", "C1")
+	t.Setenv("FAULTS", "slack.chat.postMessage=429x1,slack.chat.postMessage=500x1")
+	t.Cleanup(faults.Reset)
+
+	t.Run("prod", func(t *testing.T) {
+		faults.Reset()
+		if _, err := SlackSendMessageThread("1.000000", "hello"); err != nil || calls != 1 {
+			t.Errorf("Expected no fault in prod, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Setenv("ENVIRONMENT", "staging")
+	faults.Reset()
+
+	_, err := SlackSendMessageThread("1.000000", "hello")
+	if !errors.Is(err, apperrors.ErrSlackRateLimited) || apperrors.RetryAfter(err) != time.Second {
+		t.Errorf("Expected a rate limited error retried after 1s, got %v", err)
+	}
+
+	if _, err := SlackSendMessageThread("1.000000", "hello"); err == nil {
+		t.Errorf("Expected a server error")
+	}
+
+	if _, err := SlackSendMessageThread("1.000000", "hello"); err != nil || calls != 2 {
+		t.Errorf("Expected the call through once the faults are spent, got %v after %d calls", err, calls)
+	}
+}
@@ -2,10 +2,14 @@ module slack-pr-lambda/slack
 
 go 1.22
 
-require github.com/slack-go/slack v0.12.5
+require (
+	github.com/slack-go/slack v0.12.5
+	go.uber.org/zap v1.27.0
+)
 
 require (
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/stretchr/testify v1.8.4 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
 )
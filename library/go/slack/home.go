@@ -0,0 +1,98 @@
+package slack
+
+import (
+	"encoding/json"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+// a home tab view is limited to 100 blocks
+const maxHomeBlocks = 100
+
+// markdown text of the App Home tab with an optional row of buttons below it
+type HomeSection struct {
+	Text    string
+	Buttons []SlackButton
+}
+
+// Events API request, Challenge is set for url_verification, User and Tab for
+// an app_home_opened inner event, Channel, MessageTimeStamp and Links for a
+// link_shared one
+type SlackEvent struct {
+	Type             string
+	Challenge        string
+	InnerType        string
+	User             string
+	Tab              string
+	Channel          string
+	MessageTimeStamp string
+	Links            []string
+}
+
+// the request signature is verified with SlackVerifyRequest, the deprecated
+// verification token is not checked
+func SlackParseEvent(body []byte) (SlackEvent, error) {
+	parsed, err := slackevents.ParseEvent(json.RawMessage(body), slackevents.OptionNoVerifyToken())
+	if err != nil {
+		return SlackEvent{}, err
+	}
+
+	event := SlackEvent{Type: parsed.Type, InnerType: parsed.InnerEvent.Type}
+	switch data := parsed.Data.(type) {
+	case *slackevents.EventsAPIURLVerificationEvent:
+		event.Challenge = data.Challenge
+	}
+	switch data := parsed.InnerEvent.Data.(type) {
+	case *slackevents.AppHomeOpenedEvent:
+		event.User = data.User
+		event.Tab = data.Tab
+	case *slackevents.LinkSharedEvent:
+		event.User = data.User
+		event.Channel = data.Channel
+		event.MessageTimeStamp = data.MessageTimeStamp
+		for _, link := range data.Links {
+			event.Links = append(event.Links, link.URL)
+		}
+	}
+
+	return event, nil
+}
+
+// replace the App Home tab of the user, sections past the block limit are dropped
+func SlackPublishHome(userId string, sections []HomeSection) error {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	if dryrun.Enabled() {
+		dryrun.Log("slack.publish_home", zap.String("user", userId), zap.Any("sections", sections))
+		return nil
+	}
+
+	api := slackClient(token)
+
+	view := slack.HomeTabViewRequest{
+		Type:   slack.VTHomeTab,
+		Blocks: slack.Blocks{BlockSet: HomeBlocks(sections)},
+	}
+	if _, err := api.PublishView(userId, view, ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+func HomeBlocks(sections []HomeSection) []slack.Block {
+	blocks := []slack.Block{}
+	for _, section := range sections {
+		next := []slack.Block{slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, section.Text, false, false), nil, nil)}
+		if len(section.Buttons) > 0 {
+			next = ButtonBlocks(section.Text, section.Buttons)
+		}
+		if len(blocks)+len(next) > maxHomeBlocks {
+			break
+		}
+		blocks = append(blocks, next...)
+	}
+	return blocks
+}
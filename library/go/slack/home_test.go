@@ -0,0 +1,72 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlackParseEvent(t *testing.T) {
+	t.Run("url verification", func(t *testing.T) {
+		event, err := SlackParseEvent([]byte(`{"type":"url_verification","token":"t","challenge":"abc"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != "url_verification" || event.Challenge != "abc" {
+			t.Errorf("Expected the challenge, got %+v", event)
+		}
+	})
+
+	t.Run("app home opened", func(t *testing.T) {
+		body := `{"type":"event_callback","token":"t","event":{"type":"app_home_opened","user":"U1","channel":"D1","tab":"home"}}`
+		event, err := SlackParseEvent([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.InnerType != "app_home_opened" || event.User != "U1" || event.Tab != "home" {
+			t.Errorf("Expected the home tab of U1, got %+v", event)
+		}
+	})
+
+	t.Run("link shared", func(t *testing.T) {
+		body := `{"type":"event_callback","token":"t","event":{"type":"link_shared","user":"U1","channel":"C1","message_ts":"1.000001","links":[{"domain":"github.com","url":"https://github.com/acme/api/pull/7"}]}}`
+		event, err := SlackParseEvent([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.InnerType != "link_shared" || event.Channel != "C1" || event.MessageTimeStamp != "1.000001" {
+			t.Errorf("Expected the message of C1, got %+v", event)
+		}
+		if len(event.Links) != 1 || event.Links[0] != "https://github.com/acme/api/pull/7" {
+			t.Errorf("Expected the shared link, got %v", event.Links)
+		}
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		if _, err := SlackParseEvent([]byte("invalid")); err == nil {
+			t.Errorf("Expected error for an invalid body")
+		}
+	})
+}
+
+func TestHomeBlocks(t *testing.T) {
+	blocks := HomeBlocks([]HomeSection{
+		{Text: "title"},
+		{Text: "pull request", Buttons: []SlackButton{{ActionId: "one", Text: "One", Value: "1"}}},
+	})
+
+	if len(blocks) != 3 {
+		t.Fatalf("Expected 3 blocks, got %d", len(blocks))
+	}
+	if _, ok := blocks[2].(*slack.ActionBlock); !ok {
+		t.Errorf("Expected an action block, got %T", blocks[2])
+	}
+
+	sections := []HomeSection{}
+	for i := 0; i < 60; i++ {
+		sections = append(sections, HomeSection{Text: "pull request", Buttons: []SlackButton{{ActionId: "one", Text: "One", Value: "1"}}})
+	}
+	if blocks := HomeBlocks(sections); len(blocks) != maxHomeBlocks {
+		t.Errorf("Expected %d blocks, got %d", maxHomeBlocks, len(blocks))
+	}
+}
@@ -0,0 +1,52 @@
+package slack
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slack-pr-lambda/types"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// thread message with an image block per image below the text, returns the
+// timestamp of the reply. Slack fetches the images itself, it rejects the
+// message when one can't be fetched
+func SlackSendMessageThreadWithImages(timeStamp string, message string, images []types.Image) (string, error) {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel := Channel()
+	message = label(message)
+	if dryrun.Enabled() {
+		dryrun.Log("slack.send_message_thread", zap.String("channel", channel), zap.String("timeStamp", timeStamp), zap.String("message", message), zap.Any("images", images))
+		return "dry-run", nil
+	}
+
+	api := slackClient(token)
+
+	_, reply, err := postMessage(
+		api,
+		channel,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(ImageBlocks(message, images)...),
+		slack.MsgOptionTS(timeStamp),
+	)
+	if err != nil {
+		return "", err
+	}
+	return reply, nil
+}
+
+// text section then the images, the alt text of an image block is required
+func ImageBlocks(message string, images []types.Image) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, message, false, false), nil, nil),
+	}
+	for _, image := range images {
+		alt := image.Alt
+		if alt == "" {
+			alt = "image"
+		}
+		blocks = append(blocks, slack.NewImageBlock(image.Url, alt, "", nil))
+	}
+	return blocks
+}
@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"slack-pr-lambda/types"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestImageBlocks(t *testing.T) {
+	blocks := ImageBlocks("Images of the description", []types.Image{
+		{Url: "https://github.com/user-attachments/assets/1", Alt: "before"},
+		{Url: "https://github.com/user-attachments/assets/2"},
+	})
+
+	if len(blocks) != 3 {
+		t.Fatalf("Expected 3 blocks, got %d", len(blocks))
+	}
+	image, ok := blocks[2].(*slack.ImageBlock)
+	if !ok {
+		t.Fatalf("Expected an image block, got %T", blocks[2])
+	}
+	if image.ImageURL != "https://github.com/user-attachments/assets/2" || image.AltText != "image" {
+		t.Errorf("Expected the second image with a default alt text, got %+v", image)
+	}
+}
+
+func TestSlackSendMessageThreadWithImages(t *testing.T) {
+	forms := formServer(t)
+
+	reply, err := SlackSendMessageThreadWithImages("1.000000", "Images", []types.Image{{Url: "https://github.com/user-attachments/assets/1", Alt: "before"}})
+	if err != nil || reply != "1.000001" {
+		t.Fatalf("Expected the reply, got %q %v", reply, err)
+	}
+
+	form := forms["chat.postMessage"]
+	if form.Get("thread_ts") != "1.000000" || !strings.Contains(form.Get("blocks"), "https://github.com/user-attachments/assets/1") {
+		t.Errorf("Expected the image in the thread, got %v", form)
+	}
+}
@@ -1,18 +1,47 @@
 package slack
 
 import (
+	"slack-pr-lambda/dryrun"
 	"slack-pr-lambda/env"
 	"slack-pr-lambda/types"
+	"sync"
 
 	"github.com/slack-go/slack"
+	"go.uber.org/zap"
 )
 
+// clients by token and api url, built once and shared between calls
+var clients sync.Map
+
+// SLACK_API_URL points the client to another Slack API, e.g. the devserver stub
+func slackClient(token string) *slack.Client {
+	apiUrl := env.GetEnv("SLACK_API_URL", "")
+	key := token + " " + apiUrl
+	if api, ok := clients.Load(key); ok {
+		return api.(*slack.Client)
+	}
+
+	options := []slack.Option{slack.OptionHTTPClient(httpClient)}
+	if apiUrl != "" {
+		options = append(options, slack.OptionAPIURL(apiUrl))
+	}
+	api, _ := clients.LoadOrStore(key, slack.New(token, options...))
+	return api.(*slack.Client)
+}
+
 func SlackSendMessage(input types.OpenPullRequest, msg string) (string, error) {
 	token := env.GetEnv("SLACK_TOKEN", "")
-	channel := env.GetEnv("SLACK_CHANNEL", "")
-	api := slack.New(token)
+	channel := Channel()
+	msg = label(msg)
+	if dryrun.Enabled() {
+		dryrun.Log("slack.send_message", zap.String("channel", channel), zap.String("message", msg))
+		return "dry-run", nil
+	}
 
-	_, timestamp, err := api.PostMessage(
+	api := slackClient(token)
+
+	_, timestamp, err := postMessage(
+		api,
 		channel,
 		slack.MsgOptionText(msg, false),
 		slack.MsgOptionAsUser(false),
@@ -25,31 +54,267 @@ func SlackSendMessage(input types.OpenPullRequest, msg string) (string, error) {
 	return timestamp, nil
 }
 
-func SlackSendMessageThread(timeStamp string, message string) error {
+// reply in the thread of timeStamp, returns the timestamp of the reply
+func SlackSendMessageThread(timeStamp string, message string) (string, error) {
 	token := env.GetEnv("SLACK_TOKEN", "")
-	channel := env.GetEnv("SLACK_CHANNEL", "")
-	api := slack.New(token)
+	channel := Channel()
+	message = label(message)
+	if dryrun.Enabled() {
+		dryrun.Log("slack.send_message_thread", zap.String("channel", channel), zap.String("timeStamp", timeStamp), zap.String("message", message))
+		return "dry-run", nil
+	}
 
-	_, _, err := api.PostMessage(
+	api := slackClient(token)
+
+	_, reply, err := postMessage(
+		api,
 		channel,
 		slack.MsgOptionText(message, false),
 		slack.MsgOptionTS(timeStamp),
 	)
 	if err != nil {
-		return err
+		return "", err
 	}
-	return nil
+	return reply, nil
+}
+
+// error of a reply whose parent message was deleted in Slack
+func ParentDeleted(err error) bool {
+	return err != nil && (err.Error() == "thread_not_found" || err.Error() == "message_not_found")
 }
 
 func SlackAddReaction(timeStamp string, emoji string) error {
 	token := env.GetEnv("SLACK_TOKEN", "")
-	channel := env.GetEnv("SLACK_CHANNEL", "")
-	api := slack.New(token)
+	channel := Channel()
+	if dryrun.Enabled() {
+		dryrun.Log("slack.add_reaction", zap.String("channel", channel), zap.String("timeStamp", timeStamp), zap.String("emoji", emoji))
+		return nil
+	}
+
+	api := slackClient(token)
 
 	err := api.AddReaction(emoji, slack.NewRefToMessage(channel, timeStamp))
 
+	if err != nil {
+		return classify("reactions.add", err)
+	}
+	return nil
+}
+
+// replace the text of a message posted earlier, e.g. the parent pull request message
+func SlackUpdateMessage(timeStamp string, message string) error {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel := Channel()
+	message = label(message)
+	if dryrun.Enabled() {
+		dryrun.Log("slack.update_message", zap.String("channel", channel), zap.String("timeStamp", timeStamp), zap.String("message", message))
+		return nil
+	}
+
+	api := slackClient(token)
+
+	_, _, _, err := api.UpdateMessage(
+		channel,
+		timeStamp,
+		slack.MsgOptionText(message, false),
+	)
+	if err != nil {
+		return classify("chat.update", err)
+	}
+	return nil
+}
+
+func SlackDeleteMessage(timeStamp string) error {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel := Channel()
+	if dryrun.Enabled() {
+		dryrun.Log("slack.delete_message", zap.String("channel", channel), zap.String("timeStamp", timeStamp))
+		return nil
+	}
+
+	api := slackClient(token)
+
+	_, _, err := api.DeleteMessage(channel, timeStamp)
+	if err != nil {
+		return classify("chat.delete", err)
+	}
+	return nil
+}
+
+func SlackPinMessage(timeStamp string) error {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel := Channel()
+	if dryrun.Enabled() {
+		dryrun.Log("slack.pin_message", zap.String("channel", channel), zap.String("timeStamp", timeStamp))
+		return nil
+	}
+
+	api := slackClient(token)
+
+	if err := api.AddPin(channel, slack.NewRefToMessage(channel, timeStamp)); err != nil {
+		return classify("pins.add", err)
+	}
+	return nil
+}
+
+// permalink of a message of SLACK_CHANNEL, empty in dry-run
+func SlackGetPermalink(timeStamp string) (string, error) {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel := Channel()
+	if dryrun.Enabled() {
+		dryrun.Log("slack.get_permalink", zap.String("channel", channel), zap.String("timeStamp", timeStamp))
+		return "", nil
+	}
+
+	api := slackClient(token)
+
+	return api.GetPermalink(&slack.PermalinkParameters{Channel: channel, Ts: timeStamp})
+}
+
+// message to a channel other than SLACK_CHANNEL, e.g. the ops alert channel
+func SlackSendChannelMessage(channel string, message string) error {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel = route(channel)
+	message = label(message)
+	if dryrun.Enabled() {
+		dryrun.Log("slack.send_channel_message", zap.String("channel", channel), zap.String("message", message))
+		return nil
+	}
+
+	api := slackClient(token)
+
+	_, _, err := postMessage(
+		api,
+		channel,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionAsUser(false),
+	)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// message to a channel other than SLACK_CHANNEL, in the thread of
+// threadTimeStamp unless it is empty. Returns the timestamp of the message
+func SlackPostChannelMessage(channel string, threadTimeStamp string, message string) (string, error) {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel = route(channel)
+	message = label(message)
+	if dryrun.Enabled() {
+		dryrun.Log("slack.post_channel_message", zap.String("channel", channel), zap.String("timeStamp", threadTimeStamp), zap.String("message", message))
+		return "dry-run", nil
+	}
+
+	api := slackClient(token)
+
+	options := []slack.MsgOption{
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionAsUser(false),
+	}
+	if threadTimeStamp != "" {
+		options = append(options, slack.MsgOptionTS(threadTimeStamp))
+	}
+
+	_, timestamp, err := postMessage(api, channel, options...)
+	if err != nil {
+		return "", err
+	}
+	return timestamp, nil
+}
+
+type SlackButton struct {
+	ActionId string
+	Text     string
+	Value    string
+}
+
+// thread message with a row of Block Kit buttons below the text, returns the
+// timestamp of the reply
+func SlackSendMessageThreadWithButtons(timeStamp string, message string, buttons []SlackButton) (string, error) {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel := Channel()
+	message = label(message)
+	if dryrun.Enabled() {
+		dryrun.Log("slack.send_message_thread", zap.String("channel", channel), zap.String("timeStamp", timeStamp), zap.String("message", message), zap.Any("buttons", buttons))
+		return "dry-run", nil
+	}
+
+	api := slackClient(token)
+
+	_, reply, err := postMessage(
+		api,
+		channel,
+		slack.MsgOptionText(message, false),
+		slack.MsgOptionBlocks(ButtonBlocks(message, buttons)...),
+		slack.MsgOptionTS(timeStamp),
+	)
+	if err != nil {
+		return "", err
+	}
+	return reply, nil
+}
+
+func ButtonBlocks(message string, buttons []SlackButton) []slack.Block {
+	elements := []slack.BlockElement{}
+	for _, button := range buttons {
+		elements = append(elements, slack.NewButtonBlockElement(
+			button.ActionId,
+			button.Value,
+			slack.NewTextBlockObject(slack.PlainTextType, button.Text, false, false),
+		))
+	}
+
+	return []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, message, false, false), nil, nil),
+		slack.NewActionBlock("", elements...),
+	}
+}
+
+// one message of a thread, the parent message first
+type ThreadMessage struct {
+	User      string `json:"user"`
+	BotId     string `json:"botId,omitempty"`
+	Text      string `json:"text"`
+	TimeStamp string `json:"timeStamp"`
+}
+
+// every message of the thread of timeStamp in SLACK_CHANNEL
+func SlackThreadReplies(timeStamp string) ([]ThreadMessage, error) {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	channel := Channel()
+	if dryrun.Enabled() {
+		dryrun.Log("slack.conversations_replies", zap.String("channel", channel), zap.String("timeStamp", timeStamp))
+		return []ThreadMessage{}, nil
+	}
+
+	api := slackClient(token)
+
+	result := []ThreadMessage{}
+	cursor := ""
+	for {
+		replies, hasMore, next, err := api.GetConversationReplies(&slack.GetConversationRepliesParameters{
+			ChannelID: channel,
+			Timestamp: timeStamp,
+			Cursor:    cursor,
+			Limit:     200,
+		})
+		if err != nil {
+			return nil, classify("conversations.replies", err)
+		}
+
+		for _, reply := range replies {
+			result = append(result, ThreadMessage{
+				User:      reply.User,
+				BotId:     reply.BotID,
+				Text:      reply.Text,
+				TimeStamp: reply.Timestamp,
+			})
+		}
+
+		if !hasMore || next == "" {
+			return result, nil
+		}
+		cursor = next
+	}
+}
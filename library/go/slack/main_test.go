@@ -1,6 +1,46 @@
 package slack
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"slack-pr-lambda/types"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestSlackClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat.postMessage" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true, "channel": "C1", "ts": "1.000001"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_CHANNEL", "C1")
+
+	timeStamp, err := SlackSendMessage(types.OpenPullRequest{}, "hello")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if timeStamp != "1.000001" {
+		t.Errorf("Expected stub timestamp, got %s", timeStamp)
+	}
+}
+
+func TestSlackClientShared(t *testing.T) {
+	t.Setenv("SLACK_API_URL", "http://localhost:8081/api/")
+
+	if slackClient("token") != slackClient("token") {
+		t.Errorf("Expected the client to be reused")
+	}
+	if slackClient("token") == slackClient("other") {
+		t.Errorf("Expected a client per token")
+	}
+}
 
 func TestSlackSendMessage(t *testing.T) {
 	t.Logf("can't test this one, will have to connect to slack api")
@@ -16,9 +56,146 @@ func TestSlackSendMessageThread(t *testing.T) {
 	}
 }
 
+func TestParentDeleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": false, "error": "thread_not_found"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_CHANNEL", "C1")
+
+	_, err := SlackSendMessageThread("1.000001", "hello")
+	if !ParentDeleted(err) {
+		t.Errorf("Expected a deleted parent message, got %v", err)
+	}
+	if ParentDeleted(nil) || ParentDeleted(slack.SlackErrorResponse{Err: "channel_not_found"}) {
+		t.Errorf("Expected other errors to be kept")
+	}
+}
+
 func TestSlackAddReaction(t *testing.T) {
 	t.Logf("can't test this one, will have to connect to slack api")
 	if false {
 		t.Errorf("This should not fail")
 	}
 }
+
+func TestSlackUpdateMessage(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestSlackDeleteMessage(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestSlackPinMessage(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestSlackGetPermalink(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestSlackSendChannelMessage(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestSlackSendMessageThreadWithButtons(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestSlackThreadReplies(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
+
+func TestButtonBlocks(t *testing.T) {
+	blocks := ButtonBlocks("hello", []SlackButton{
+		{ActionId: "one", Text: "One", Value: "1"},
+		{ActionId: "two", Text: "Two", Value: "2"},
+	})
+
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 blocks, got %d", len(blocks))
+	}
+
+	actions, ok := blocks[1].(*slack.ActionBlock)
+	if !ok {
+		t.Fatalf("Expected an action block, got %T", blocks[1])
+	}
+	if len(actions.Elements.ElementSet) != 2 {
+		t.Errorf("Expected 2 buttons, got %d", len(actions.Elements.ElementSet))
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+	t.Setenv("ENV", "test")
+
+	timeStamp, err := SlackSendMessage(types.OpenPullRequest{}, "hello")
+	if err != nil || timeStamp != "dry-run" {
+		t.Errorf("Expected dry-run timestamp, got %q %v", timeStamp, err)
+	}
+
+	if _, err := SlackSendMessageThread(timeStamp, "hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := SlackAddReaction(timeStamp, "eyes"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := SlackUpdateMessage(timeStamp, "hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := SlackDeleteMessage(timeStamp); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := SlackPinMessage(timeStamp); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if scopes, err := SlackTokenScopes(); err != nil || len(MissingScopes(scopes)) != 0 {
+		t.Errorf("Expected every scope, got %v %v", scopes, err)
+	}
+	if err := SlackSendChannelMessage("C2", "hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if reply, err := SlackPostChannelMessage("C2", timeStamp, "hello"); err != nil || reply != "dry-run" {
+		t.Errorf("Expected dry-run timestamp, got %q %v", reply, err)
+	}
+	if _, err := SlackSendMessageThreadWithButtons(timeStamp, "hello", []SlackButton{}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := SlackRespond("http://localhost:0", "hello"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := SlackPublishHome("U1", []HomeSection{{Text: "hello"}}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if replies, err := SlackThreadReplies(timeStamp); err != nil || len(replies) != 0 {
+		t.Errorf("Expected no replies, got %v %v", replies, err)
+	}
+	if permalink, err := SlackGetPermalink(timeStamp); err != nil || permalink != "" {
+		t.Errorf("Expected no permalink, got %q %v", permalink, err)
+	}
+}
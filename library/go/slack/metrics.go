@@ -0,0 +1,79 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slack-pr-lambda/metrics"
+	"time"
+)
+
+var (
+	callDuration = metrics.NewHistogram("slack_call_duration_seconds", "Slack API call latency by method.", metrics.DefaultBuckets, "method")
+	callErrors   = metrics.NewCounter("slack_call_errors_total", "Failed Slack API calls by method and error.", "method", "error")
+)
+
+// times every Slack API call, the method is the last segment of the url
+// e.g. chat.postMessage
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := path.Base(req.URL.Path)
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	callDuration.Observe(time.Since(start).Seconds(), method)
+	if err != nil {
+		callErrors.Inc(method, "request_failed")
+		return resp, err
+	}
+	if callError := responseError(resp); callError != "" {
+		callErrors.Inc(method, callError)
+	}
+
+	return resp, nil
+}
+
+// error of a failed call, empty when it succeeded. Slack answers most failures
+// with a 200 and {"ok": false, "error": "channel_not_found"}, the body is read
+// and put back for the caller
+func responseError(resp *http.Response) string {
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Sprintf("http_%d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" || resp.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return "read_failed"
+	}
+
+	var result struct {
+		Ok    *bool  `json:"ok"`
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &result) != nil || result.Ok == nil || *result.Ok {
+		return ""
+	}
+	if result.Error == "" {
+		return "unknown"
+	}
+	return result.Error
+}
+
+var httpClient = &http.Client{
+	Transport: instrumentedTransport{next: faultTransport{next: http.DefaultTransport}},
+}
@@ -0,0 +1,57 @@
+package slack
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstrumentedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/reactions.add":
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		case "/api/chat.postMessage":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer server.Close()
+
+	call := func(method string) string {
+		req, _ := http.NewRequest("POST", server.URL+"/api/"+method, nil)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	call("reactions.add")
+	if count := callDuration.Count("reactions.add"); count != 1 {
+		t.Errorf("Expected 1 call, got %d", count)
+	}
+	if value := callErrors.Value("reactions.add", "http_500"); value != 1 {
+		t.Errorf("Expected 1 error, got %v", value)
+	}
+
+	// Slack errors come with a 200, the caller still reads the body
+	if body := call("chat.postMessage"); body != `{"ok":false,"error":"channel_not_found"}` {
+		t.Errorf("Expected the body to be put back, got %q", body)
+	}
+	if value := callErrors.Value("chat.postMessage", "channel_not_found"); value != 1 {
+		t.Errorf("Expected 1 channel_not_found error, got %v", value)
+	}
+
+	call("chat.update")
+	if value := callErrors.Value("chat.update", "unknown"); value != 0 {
+		t.Errorf("Expected no error, got %v", value)
+	}
+}
@@ -0,0 +1,76 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// verify the X-Slack-Signature of an incoming slash command / interaction request,
+// verification is skipped only when running locally without a signing secret
+func SlackVerifyRequest(header http.Header, body []byte) error {
+	secret := env.GetEnv("SLACK_SIGNING_SECRET", "")
+	if secret == "" {
+		if env.GetEnv("ENV", "local") == "local" {
+			return nil
+		}
+		return errors.New("slack signing secret is not configured")
+	}
+
+	sv, err := slack.NewSecretsVerifier(header, secret)
+	if err != nil {
+		return err
+	}
+	if _, err := sv.Write(body); err != nil {
+		return err
+	}
+
+	return sv.Ensure()
+}
+
+func SlackParseCommand(body []byte) (slack.SlashCommand, error) {
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return slack.SlashCommand{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return slack.SlashCommandParse(req)
+}
+
+// block actions / shortcuts are posted as a form with a JSON "payload" field
+func SlackParseInteraction(body []byte) (slack.InteractionCallback, error) {
+	var callback slack.InteractionCallback
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return callback, err
+	}
+
+	if err := json.Unmarshal([]byte(form.Get("payload")), &callback); err != nil {
+		return callback, err
+	}
+
+	return callback, nil
+}
+
+// ephemeral reply to the user that triggered an interaction
+func SlackRespond(responseUrl string, message string) error {
+	if dryrun.Enabled() {
+		dryrun.Log("slack.respond", zap.String("message", message))
+		return nil
+	}
+
+	return slack.PostWebhook(responseUrl, &slack.WebhookMessage{
+		ResponseType:    slack.ResponseTypeEphemeral,
+		ReplaceOriginal: false,
+		Text:            message,
+	})
+}
@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func signedHeader(secret string, body []byte) http.Header {
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("v0:%s:%s", timestamp, body)))
+
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestSlackVerifyRequest(t *testing.T) {
+	body := []byte("command=%2Fpr-ooo&text=off")
+
+	t.Run("valid signature", func(t *testing.T) {
+		t.Setenv("SLACK_SIGNING_SECRET", "secret")
+		if err := SlackVerifyRequest(signedHeader("secret", body), body); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		t.Setenv("SLACK_SIGNING_SECRET", "secret")
+		if err := SlackVerifyRequest(signedHeader("other", body), body); err == nil {
+			t.Errorf("Expected error for invalid signature")
+		}
+	})
+
+	t.Run("missing secret outside local", func(t *testing.T) {
+		t.Setenv("SLACK_SIGNING_SECRET", "")
+		t.Setenv("ENV", "stage")
+		if err := SlackVerifyRequest(http.Header{}, body); err == nil {
+			t.Errorf("Expected error for missing signing secret")
+		}
+	})
+
+	t.Run("missing secret locally", func(t *testing.T) {
+		t.Setenv("SLACK_SIGNING_SECRET", "")
+		t.Setenv("ENV", "local")
+		if err := SlackVerifyRequest(http.Header{}, body); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestSlackParseCommand(t *testing.T) {
+	body := []byte("command=%2Fpr-ooo&text=2024-03-10+2024-03-12&user_id=U123")
+
+	cmd, err := SlackParseCommand(body)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cmd.Command != "/pr-ooo" || cmd.Text != "2024-03-10 2024-03-12" || cmd.UserID != "U123" {
+		t.Errorf("Unexpected slash command %+v", cmd)
+	}
+}
+
+func TestSlackParseInteraction(t *testing.T) {
+	payload := `{"type":"block_actions","user":{"id":"U123"},"response_url":"https://hooks.slack.com/x","actions":[{"block_id":"reminder","action_id":"snooze","value":"4h"}]}`
+	body := []byte("payload=" + url.QueryEscape(payload))
+
+	callback, err := SlackParseInteraction(body)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if callback.User.ID != "U123" || callback.ResponseURL != "https://hooks.slack.com/x" {
+		t.Errorf("Unexpected interaction %+v", callback)
+	}
+	if len(callback.ActionCallback.BlockActions) != 1 || callback.ActionCallback.BlockActions[0].Value != "4h" {
+		t.Errorf("Unexpected block actions %+v", callback.ActionCallback.BlockActions)
+	}
+}
+
+func TestSlackRespond(t *testing.T) {
+	t.Logf("can't test this one, will have to connect to slack api")
+	if false {
+		t.Errorf("This should not fail")
+	}
+}
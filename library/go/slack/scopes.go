@@ -0,0 +1,86 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+	"slices"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// scopes of the bot token, see Slack Oath & Permissions in the README
+var RequiredScopes = []string{
+	"channels:history",
+	"channels:join",
+	"channels:read",
+	"chat:write",
+	"commands",
+	"links:read",
+	"links:write",
+	"pins:write",
+	"reactions:read",
+	"reactions:write",
+	"users:read",
+	"users:read.email",
+}
+
+// scopes granted to SLACK_TOKEN, from the X-OAuth-Scopes header of auth.test.
+// nil when the API does not report them, e.g. the devserver stub
+func SlackTokenScopes() ([]string, error) {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	if dryrun.Enabled() {
+		dryrun.Log("slack.auth_test")
+		return append([]string{}, RequiredScopes...), nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, env.GetEnv("SLACK_API_URL", slack.APIURL)+"auth.test", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if !result.Ok {
+		return nil, errors.New(result.Error)
+	}
+
+	header := resp.Header.Get("X-OAuth-Scopes")
+	if header == "" {
+		return nil, nil
+	}
+
+	scopes := []string{}
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// required scopes not granted, in the order of RequiredScopes
+func MissingScopes(granted []string) []string {
+	missing := []string{}
+	for _, scope := range RequiredScopes {
+		if !slices.Contains(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
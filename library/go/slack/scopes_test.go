@@ -0,0 +1,56 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestSlackTokenScopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/auth.test" || r.Header.Get("Authorization") != "Bearer xoxb-1" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-OAuth-Scopes", "chat:write, commands,reactions:write")
+		w.Write([]byte(`{"ok": true, "user_id": "U1"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_TOKEN", "xoxb-1")
+
+	scopes, err := SlackTokenScopes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(scopes, []string{"chat:write", "commands", "reactions:write"}) {
+		t.Errorf("Expected the granted scopes, got %v", scopes)
+	}
+}
+
+func TestSlackTokenScopesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": false, "error": "invalid_auth"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+
+	if _, err := SlackTokenScopes(); err == nil || err.Error() != "invalid_auth" {
+		t.Errorf("Expected invalid_auth, got %v", err)
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	missing := MissingScopes([]string{"channels:history", "channels:join", "channels:read", "chat:write", "commands", "reactions:read"})
+	if !slices.Equal(missing, []string{"links:read", "links:write", "pins:write", "reactions:write", "users:read", "users:read.email"}) {
+		t.Errorf("Expected the missing scopes, got %v", missing)
+	}
+
+	if missing := MissingScopes(RequiredScopes); len(missing) != 0 {
+		t.Errorf("Expected no missing scope, got %v", missing)
+	}
+}
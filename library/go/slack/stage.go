@@ -0,0 +1,33 @@
+package slack
+
+import (
+	"fmt"
+	"slack-pr-lambda/env"
+)
+
+// outside of prod every message goes to SANDBOX_CHANNEL (SLACK_CHANNEL when
+// unset), so a deployment is tested end to end without posting to the real
+// channels of the repository config and destinations
+func route(channel string) string {
+	if env.Stage() == "prod" {
+		return channel
+	}
+	if sandbox := env.GetEnv("SANDBOX_CHANNEL", ""); sandbox != "" {
+		return sandbox
+	}
+	return env.GetEnv("SLACK_CHANNEL", "")
+}
+
+// channel the pull request messages are posted to, SLACK_CHANNEL or the
+// sandbox outside of prod
+func Channel() string {
+	return route(env.GetEnv("SLACK_CHANNEL", ""))
+}
+
+// message prefixed with the stage outside of prod, e.g. "[staging] ..."
+func label(message string) string {
+	if stage := env.Stage(); stage != "prod" {
+		return fmt.Sprintf("[%s] %s", stage, message)
+	}
+	return message
+}
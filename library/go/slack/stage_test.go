@@ -0,0 +1,36 @@
+package slack
+
+import "testing"
+
+func TestRoute(t *testing.T) {
+	t.Setenv("SLACK_CHANNEL", "C0PR")
+	t.Setenv("SANDBOX_CHANNEL", "")
+
+	if channel := route("C0ALERT"); channel != "C0ALERT" {
+		t.Errorf("Expected prod to keep the channel, got %s", channel)
+	}
+	if channel := Channel(); channel != "C0PR" {
+		t.Errorf("Expected SLACK_CHANNEL in prod, got %s", channel)
+	}
+	if message := label("opened"); message != "opened" {
+		t.Errorf("Expected prod messages without prefix, got %s", message)
+	}
+
+	t.Setenv("ENVIRONMENT", "staging")
+	if channel := route("C0ALERT"); channel != "C0PR" {
+		t.Errorf("Expected SLACK_CHANNEL without a sandbox channel, got %s", channel)
+	}
+	if message := label("opened"); message != "[staging] opened" {
+		t.Errorf("got %q want %q", message, "[staging] opened")
+	}
+
+	t.Setenv("SANDBOX_CHANNEL", "C0SANDBOX")
+	for _, channel := range []string{"C0ALERT", "C0PR"} {
+		if routed := route(channel); routed != "C0SANDBOX" {
+			t.Errorf("Expected %s routed to the sandbox, got %s", channel, routed)
+		}
+	}
+	if channel := Channel(); channel != "C0SANDBOX" {
+		t.Errorf("Expected the sandbox, got %s", channel)
+	}
+}
@@ -0,0 +1,40 @@
+package slack
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// replace the unfurls of the links shared in a message with our own cards,
+// markdown text keyed by the url as shared. timeStamp is the message_ts of the
+// link_shared event. Outside of prod only the links of the sandbox channel are
+// unfurled
+func SlackUnfurl(channel string, timeStamp string, cards map[string]string) error {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	if route(channel) != channel {
+		return nil
+	}
+	if dryrun.Enabled() {
+		dryrun.Log("slack.unfurl", zap.String("channel", channel), zap.String("timeStamp", timeStamp), zap.Any("cards", cards))
+		return nil
+	}
+
+	api := slackClient(token)
+
+	unfurls := map[string]slack.Attachment{}
+	for url, card := range cards {
+		unfurls[url] = slack.Attachment{
+			Blocks: slack.Blocks{BlockSet: []slack.Block{
+				slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, card, false, false), nil, nil),
+			}},
+		}
+	}
+
+	if _, _, _, err := api.UnfurlMessage(channel, timeStamp, unfurls); err != nil {
+		return classify("chat.unfurl", err)
+	}
+	return nil
+}
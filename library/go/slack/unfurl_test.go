@@ -0,0 +1,53 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// answers every method with ok, returns the forms received by method
+func formServer(t *testing.T) map[string]url.Values {
+	forms := map[string]url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		forms[strings.TrimPrefix(r.URL.Path, "/api/")] = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true, "channel": "C1", "ts": "1.000001"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+	t.Setenv("SLACK_CHANNEL", "C1")
+	return forms
+}
+
+func TestPostMessageNoUnfurl(t *testing.T) {
+	forms := formServer(t)
+
+	if _, err := SlackSendMessageThread("1.000000", "https://github.com/acme/api/pull/7"); err != nil {
+		t.Fatal(err)
+	}
+	if unfurl := forms["chat.postMessage"].Get("unfurl_links"); unfurl != "false" {
+		t.Errorf("Expected links not to be unfurled, got %q", unfurl)
+	}
+}
+
+func TestSlackUnfurl(t *testing.T) {
+	forms := formServer(t)
+
+	link := "https://github.com/acme/api/pull/7"
+	if err := SlackUnfurl("C2", "1.000001", map[string]string{link: "*api#7*"}); err != nil {
+		t.Fatal(err)
+	}
+
+	form := forms["chat.unfurl"]
+	if form.Get("channel") != "C2" || form.Get("ts") != "1.000001" {
+		t.Errorf("Expected the message of C2, got %v", form)
+	}
+	if unfurls := form.Get("unfurls"); !strings.Contains(unfurls, link) || !strings.Contains(unfurls, "*api#7*") {
+		t.Errorf("Expected the card of the link, got %s", unfurls)
+	}
+}
@@ -0,0 +1,26 @@
+package slack
+
+import (
+	"slack-pr-lambda/dryrun"
+	"slack-pr-lambda/env"
+
+	"go.uber.org/zap"
+)
+
+// email of the Slack profile of the user, verified by Slack. Needs the
+// users:read.email scope
+func SlackUserEmail(userId string) (string, error) {
+	token := env.GetEnv("SLACK_TOKEN", "")
+	if dryrun.Enabled() {
+		dryrun.Log("slack.users_info", zap.String("user", userId))
+		return "", nil
+	}
+
+	api := slackClient(token)
+
+	user, err := api.GetUserInfo(userId)
+	if err != nil {
+		return "", err
+	}
+	return user.Profile.Email, nil
+}
@@ -0,0 +1,25 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSlackUserEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.URL.Path != "/api/users.info" || r.PostForm.Get("user") != "U1" {
+			t.Errorf("Unexpected request %s %v", r.URL.Path, r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true, "user": {"id": "U1", "profile": {"email": "alice@example.com"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("SLACK_API_URL", server.URL+"/api/")
+
+	email, err := SlackUserEmail("U1")
+	if err != nil || email != "alice@example.com" {
+		t.Errorf("Expected the profile email, got %q %v", email, err)
+	}
+}
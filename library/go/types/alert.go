@@ -0,0 +1,204 @@
+package types
+
+import "strings"
+
+// alert of a dependabot_alert, code_scanning_alert or secret_scanning_alert
+// delivery, repository_vulnerability_alert deliveries send the flat fields of
+// its predecessor instead
+type SecurityAlert struct {
+	Number                int                    `json:"number"`
+	ID                    int64                  `json:"id"`
+	State                 string                 `json:"state"`
+	HTMLURL               string                 `json:"html_url"`
+	CreatedAt             string                 `json:"created_at"`
+	SecurityAdvisory      *SecurityAdvisory      `json:"security_advisory"`
+	SecurityVulnerability *SecurityVulnerability `json:"security_vulnerability"`
+	Dependency            *AlertDependency       `json:"dependency"`
+	// code_scanning_alert
+	Rule               *AlertRule     `json:"rule"`
+	Tool               *AlertTool     `json:"tool"`
+	MostRecentInstance *AlertInstance `json:"most_recent_instance"`
+	// secret_scanning_alert
+	SecretType            string `json:"secret_type"`
+	SecretTypeDisplayName string `json:"secret_type_display_name"`
+	// repository_vulnerability_alert
+	Severity            string `json:"severity"`
+	AffectedPackageName string `json:"affected_package_name"`
+	AffectedRange       string `json:"affected_range"`
+	FixedIn             string `json:"fixed_in"`
+	ExternalIdentifier  string `json:"external_identifier"`
+	ExternalReference   string `json:"external_reference"`
+	GHSAID              string `json:"ghsa_id"`
+}
+
+type SecurityAdvisory struct {
+	GHSAID   string `json:"ghsa_id"`
+	CVEID    string `json:"cve_id"`
+	Summary  string `json:"summary"`
+	Severity string `json:"severity"`
+}
+
+type SecurityVulnerability struct {
+	Package                AlertPackage  `json:"package"`
+	Severity               string        `json:"severity"`
+	VulnerableVersionRange string        `json:"vulnerable_version_range"`
+	FirstPatchedVersion    *AlertVersion `json:"first_patched_version"`
+}
+
+type AlertDependency struct {
+	Package      AlertPackage `json:"package"`
+	ManifestPath string       `json:"manifest_path"`
+}
+
+type AlertRule struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// error, warning or note
+	Severity              string `json:"severity"`
+	SecuritySeverityLevel string `json:"security_severity_level"`
+}
+
+type AlertTool struct {
+	Name string `json:"name"`
+}
+
+type AlertInstance struct {
+	Ref string `json:"ref"`
+}
+
+// levels of code scanning rules without a security severity
+var ruleSeverities = map[string]string{
+	"error":   "high",
+	"warning": "medium",
+	"note":    "low",
+}
+
+type AlertPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type AlertVersion struct {
+	Identifier string `json:"identifier"`
+}
+
+// number of a dependabot alert, the id of the older vulnerability alerts
+func (a *SecurityAlert) Key() int64 {
+	if a == nil {
+		return 0
+	}
+	if a.Number > 0 {
+		return int64(a.Number)
+	}
+	return a.ID
+}
+
+// critical, high, medium or low, lowercased
+func (a *SecurityAlert) GetSeverity() string {
+	if a == nil {
+		return ""
+	}
+	// a leaked secret is usable right away
+	if a.SecretType != "" {
+		return "critical"
+	}
+	if a.Rule != nil {
+		if a.Rule.SecuritySeverityLevel != "" {
+			return strings.ToLower(a.Rule.SecuritySeverityLevel)
+		}
+		return ruleSeverities[strings.ToLower(a.Rule.Severity)]
+	}
+
+	severity := a.Severity
+	if a.SecurityVulnerability != nil && a.SecurityVulnerability.Severity != "" {
+		severity = a.SecurityVulnerability.Severity
+	} else if a.SecurityAdvisory != nil && a.SecurityAdvisory.Severity != "" {
+		severity = a.SecurityAdvisory.Severity
+	}
+	// "moderate" in the older payloads
+	severity = strings.ToLower(severity)
+	if severity == "moderate" {
+		return "medium"
+	}
+	return severity
+}
+
+// vulnerable package, the rule of a code scanning alert or the type of a
+// leaked secret
+func (a *SecurityAlert) PackageName() string {
+	if a == nil {
+		return ""
+	}
+	if a.Rule != nil {
+		return a.Rule.ID
+	}
+	if a.SecretType != "" {
+		return a.SecretType
+	}
+	if a.SecurityVulnerability != nil && a.SecurityVulnerability.Package.Name != "" {
+		return a.SecurityVulnerability.Package.Name
+	}
+	if a.Dependency != nil && a.Dependency.Package.Name != "" {
+		return a.Dependency.Package.Name
+	}
+	return a.AffectedPackageName
+}
+
+// advisory summary, the GHSA or CVE identifier when there is none
+func (a *SecurityAlert) Summary() string {
+	if a == nil {
+		return ""
+	}
+	if a.Rule != nil {
+		if a.Rule.Description != "" {
+			return a.Rule.Description
+		}
+		return a.Rule.Name
+	}
+	if a.SecretTypeDisplayName != "" {
+		return a.SecretTypeDisplayName
+	}
+	if a.SecurityAdvisory != nil {
+		if a.SecurityAdvisory.Summary != "" {
+			return a.SecurityAdvisory.Summary
+		}
+		if a.SecurityAdvisory.GHSAID != "" {
+			return a.SecurityAdvisory.GHSAID
+		}
+	}
+	if a.ExternalIdentifier != "" {
+		return a.ExternalIdentifier
+	}
+	return a.GHSAID
+}
+
+// first version without the vulnerability, empty when there is no fix yet
+func (a *SecurityAlert) PatchedVersion() string {
+	if a == nil {
+		return ""
+	}
+	if a.SecurityVulnerability != nil && a.SecurityVulnerability.FirstPatchedVersion != nil {
+		return a.SecurityVulnerability.FirstPatchedVersion.Identifier
+	}
+	return a.FixedIn
+}
+
+// link to the alert, the advisory reference of the older vulnerability alerts
+func (a *SecurityAlert) Url() string {
+	if a == nil {
+		return ""
+	}
+	if a.HTMLURL != "" {
+		return a.HTMLURL
+	}
+	return a.ExternalReference
+}
+
+// ref the code scanning alert was last seen on, e.g. refs/heads/feature
+func (a *SecurityAlert) InstanceRef() string {
+	if a == nil || a.MostRecentInstance == nil {
+		return ""
+	}
+	return a.MostRecentInstance.Ref
+}
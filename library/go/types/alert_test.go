@@ -0,0 +1,114 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSecurityAlert(t *testing.T) {
+	t.Run("dependabot alert", func(t *testing.T) {
+		body := `{
+			"action": "created",
+			"alert": {
+				"number": 3,
+				"state": "open",
+				"html_url": "https://github.com/o/api/security/dependabot/3",
+				"security_advisory": {"ghsa_id": "GHSA-1234", "summary": "Prototype pollution in lodash", "severity": "high"},
+				"security_vulnerability": {"package": {"name": "lodash", "ecosystem": "npm"}, "severity": "high", "first_patched_version": {"identifier": "4.17.21"}},
+				"dependency": {"package": {"name": "lodash", "ecosystem": "npm"}, "manifest_path": "package-lock.json"}
+			}
+		}`
+
+		var event WebhookEvent
+		if err := json.Unmarshal([]byte(body), &event); err != nil {
+			t.Fatal(err)
+		}
+
+		alert := event.Alert
+		if alert.Key() != 3 || alert.GetSeverity() != "high" || alert.PackageName() != "lodash" || alert.PatchedVersion() != "4.17.21" {
+			t.Errorf("Expected the high lodash alert #3, got %+v", alert)
+		}
+		if alert.Summary() != "Prototype pollution in lodash" || alert.Url() != "https://github.com/o/api/security/dependabot/3" {
+			t.Errorf("Expected the advisory summary and link, got %q %q", alert.Summary(), alert.Url())
+		}
+	})
+
+	t.Run("repository vulnerability alert", func(t *testing.T) {
+		body := `{
+			"action": "create",
+			"alert": {"id": 91, "affected_package_name": "requests", "affected_range": "<2.20.0", "fixed_in": "2.20.0", "severity": "moderate", "external_identifier": "CVE-2018-18074", "external_reference": "https://nvd.nist.gov/vuln/detail/CVE-2018-18074"}
+		}`
+
+		var event WebhookEvent
+		if err := json.Unmarshal([]byte(body), &event); err != nil {
+			t.Fatal(err)
+		}
+
+		alert := event.Alert
+		if alert.Key() != 91 || alert.GetSeverity() != "medium" || alert.PackageName() != "requests" || alert.PatchedVersion() != "2.20.0" {
+			t.Errorf("Expected the medium requests alert 91, got %+v", alert)
+		}
+		if alert.Summary() != "CVE-2018-18074" || alert.Url() != "https://nvd.nist.gov/vuln/detail/CVE-2018-18074" {
+			t.Errorf("Expected the CVE and its reference, got %q %q", alert.Summary(), alert.Url())
+		}
+	})
+
+	t.Run("code scanning alert", func(t *testing.T) {
+		body := `{
+			"action": "created",
+			"ref": "refs/heads/feature",
+			"alert": {
+				"number": 12,
+				"html_url": "https://github.com/o/api/security/code-scanning/12",
+				"rule": {"id": "js/sql-injection", "name": "SqlInjection", "description": "Database query built from user-controlled sources", "severity": "error", "security_severity_level": "high"},
+				"tool": {"name": "CodeQL"},
+				"most_recent_instance": {"ref": "refs/heads/feature"}
+			}
+		}`
+
+		var event WebhookEvent
+		if err := json.Unmarshal([]byte(body), &event); err != nil {
+			t.Fatal(err)
+		}
+
+		alert := event.Alert
+		if alert.Key() != 12 || alert.GetSeverity() != "high" || alert.PackageName() != "js/sql-injection" || alert.Summary() != "Database query built from user-controlled sources" {
+			t.Errorf("Expected the high sql injection alert #12, got %+v", alert)
+		}
+		if event.Ref != "refs/heads/feature" || alert.InstanceRef() != "refs/heads/feature" {
+			t.Errorf("Expected the feature branch, got %q %q", event.Ref, alert.InstanceRef())
+		}
+
+		alert.Rule.SecuritySeverityLevel = ""
+		if severity := alert.GetSeverity(); severity != "high" {
+			t.Errorf("Expected errors to be high, got %q", severity)
+		}
+	})
+
+	t.Run("secret scanning alert", func(t *testing.T) {
+		body := `{
+			"action": "created",
+			"alert": {"number": 2, "secret_type": "github_personal_access_token", "secret_type_display_name": "GitHub Personal Access Token", "html_url": "https://github.com/o/api/security/secret-scanning/2"}
+		}`
+
+		var event WebhookEvent
+		if err := json.Unmarshal([]byte(body), &event); err != nil {
+			t.Fatal(err)
+		}
+
+		alert := event.Alert
+		if alert.Key() != 2 || alert.GetSeverity() != "critical" || alert.PackageName() != "github_personal_access_token" || alert.Summary() != "GitHub Personal Access Token" {
+			t.Errorf("Expected the critical leaked token #2, got %+v", alert)
+		}
+		if alert.InstanceRef() != "" {
+			t.Errorf("Expected no ref, got %q", alert.InstanceRef())
+		}
+	})
+
+	t.Run("no alert", func(t *testing.T) {
+		var alert *SecurityAlert
+		if alert.Key() != 0 || alert.GetSeverity() != "" || alert.Url() != "" {
+			t.Errorf("Expected zero values")
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// full pull_request opened delivery as GitHub sends it, about 27 KB
+func openedPayload(b *testing.B) []byte {
+	body, err := os.ReadFile("testdata/pull_request_opened.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return body
+}
+
+func BenchmarkDecodeWebhook(b *testing.B) {
+	body := openedPayload(b)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		var event WebhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			b.Fatal(err)
+		}
+		_ = event.OpenPullRequest()
+	}
+}
+
+func BenchmarkDrift(b *testing.B) {
+	body := openedPayload(b)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Drift("opened", body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// the benchmark payload decodes like a real delivery
+func TestOpenedPayload(t *testing.T) {
+	body, err := os.ReadFile("testdata/pull_request_opened.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.PullRequestNumber() != 42 || event.PullRequest.GetUser().GetLogin() != "octocat" || event.Repository.GetName() != "api" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+
+	problems, err := Drift("opened", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range problems {
+		if strings.HasPrefix(problem, "missing field") {
+			t.Errorf("Expected the fields of opened, got %s", problem)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// fields each action reads, "a|b" is satisfied by either of them
+var requiredFields = map[string][]string{
+	"opened":           {"number", "pull_request.id", "pull_request.html_url", "pull_request.user.login", "repository.name", "sender.login"},
+	"reopened":         {"number", "pull_request.id", "pull_request.html_url", "pull_request.user.login", "repository.name", "sender.login"},
+	"review_requested": {"number", "pull_request.id", "pull_request.user.login", "requested_reviewer.login|requested_team.name"},
+	"created":          {"comment.user.login", "comment.html_url", "repository.name", "pull_request.id|issue.number"},
+	"closed":           {"number", "pull_request.id", "sender.login"},
+	"submitted":        {"pull_request.id", "pull_request.number", "pull_request.user.login", "review.state", "review.user.login", "review.html_url"},
+	"dismissed":        {"pull_request.id", "pull_request.number"},
+	"labeled":          {"pull_request.id", "pull_request.number"},
+	"unlabeled":        {"pull_request.id", "pull_request.number"},
+	"synchronize":      {"pull_request.id", "pull_request.number", "pull_request.html_url", "after", "sender.login"},
+	"completed":        {"check_run.name|workflow_run.name", "check_run.status|workflow_run.status", "check_run.html_url|workflow_run.html_url"},
+}
+
+// objects of the envelope checked against their go-github type
+var driftObjects = map[string]func() interface{}{
+	"pull_request":       func() interface{} { return &github.PullRequest{} },
+	"repository":         func() interface{} { return &github.Repository{} },
+	"sender":             func() interface{} { return &github.User{} },
+	"requested_reviewer": func() interface{} { return &github.User{} },
+	"requested_team":     func() interface{} { return &github.Team{} },
+	"issue":              func() interface{} { return &github.Issue{} },
+	"comment":            func() interface{} { return &github.PullRequestComment{} },
+	"review":             func() interface{} { return &github.PullRequestReview{} },
+	"check_run":          func() interface{} { return &github.CheckRun{} },
+	"workflow_run":       func() interface{} { return &github.WorkflowRun{} },
+	"changes":            func() interface{} { return &github.EditChange{} },
+}
+
+// payload fields GitHub added after the pinned go-github types, at any depth,
+// e.g. the user_view_type of every nested user. They are not reported as unknown
+var knownFields = map[string]bool{
+	// repositories
+	"web_commit_signoff_required": true, "has_discussions": true, "allow_update_branch": true, "use_squash_pr_title_as_default": true,
+	"squash_merge_commit_title": true, "squash_merge_commit_message": true, "merge_commit_title": true, "merge_commit_message": true,
+	"allow_forking": true, "custom_properties": true, "forks": true,
+	// users and teams
+	"user_view_type": true, "notification_setting": true,
+	// issues and comments
+	"performed_via_github_app": true, "state_reason": true, "draft": true, "type": true, "timeline_url": true, "sub_issues_summary": true, "issue_dependencies_summary": true,
+	"subject_type": true, "_links": true,
+	// check and workflow runs
+	"deployment": true, "display_title": true, "path": true, "actor": true, "triggering_actor": true, "referenced_workflows": true, "run_started_at": true,
+	"previous_attempt_url": true, "run_attempt": true,
+}
+
+// differences between a delivery and the types it is decoded into: fields the
+// action reads but are missing or null, e.g. `missing field review.state`, and
+// the first unknown field within each object other than the knownFields, e.g.
+// `unknown field "auto_merge" in pull_request`. Empty when the payload matches
+func Drift(action string, body []byte) ([]string, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	problems := []string{}
+	for _, field := range requiredFields[action] {
+		if !presentAny(payload, strings.Split(field, "|")) {
+			problems = append(problems, fmt.Sprintf("missing field %s", field))
+		}
+	}
+
+	var objects map[string]json.RawMessage
+	if err := json.Unmarshal(body, &objects); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		if _, ok := driftObjects[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := driftObjects[name]()
+		// issue_comment deliveries carry the issue, review comments the pull request
+		if _, ok := objects["issue"]; ok && name == "comment" {
+			target = &github.IssueComment{}
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(withoutKnownFields(objects[name])))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(target)
+		if err == nil {
+			continue
+		}
+
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			problems = append(problems, fmt.Sprintf("unknown field %s in %s", field, name))
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("invalid %s: %s", name, err))
+	}
+
+	return problems, nil
+}
+
+// object without the knownFields, it is returned as is when it has none
+func withoutKnownFields(object json.RawMessage) json.RawMessage {
+	// numbers are kept as written, e.g. ids beyond float64 precision
+	decoder := json.NewDecoder(bytes.NewReader(object))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || !stripKnownFields(value) {
+		return object
+	}
+
+	stripped, err := json.Marshal(value)
+	if err != nil {
+		return object
+	}
+	return stripped
+}
+
+// true when a field was removed
+func stripKnownFields(value interface{}) bool {
+	stripped := false
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if knownFields[key] {
+				delete(value, key)
+				stripped = true
+				continue
+			}
+			stripped = stripKnownFields(field) || stripped
+		}
+	case []interface{}:
+		for _, item := range value {
+			stripped = stripKnownFields(item) || stripped
+		}
+	}
+	return stripped
+}
+
+func presentAny(payload map[string]interface{}, paths []string) bool {
+	for _, path := range paths {
+		if present(payload, strings.Split(path, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// set and not null, nested objects are walked by key
+func present(value interface{}, keys []string) bool {
+	for _, key := range keys {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		value, ok = object[key]
+		if !ok || value == nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDrift(t *testing.T) {
+	tests := []struct {
+		action   string
+		body     string
+		expected []string
+	}{
+		{
+			action:   "submitted",
+			body:     `{"pull_request": {"id": 1, "number": 7, "user": {"login": "alice"}}, "review": {"state": "approved", "user": {"login": "bob"}, "html_url": "https://github.com/o/api/pull/7#review"}}`,
+			expected: []string{},
+		},
+		{
+			action:   "submitted",
+			body:     `{"pull_request": {"id": 1, "number": 7, "user": {"login": "alice"}}, "review": {"state": null, "user": {"login": "bob"}, "html_url": "https://github.com/o/api/pull/7#review"}}`,
+			expected: []string{"missing field review.state"},
+		},
+		{
+			action:   "review_requested",
+			body:     `{"number": 7, "pull_request": {"id": 1, "user": {"login": "alice"}}, "requested_team": {"name": "platform"}}`,
+			expected: []string{},
+		},
+		{
+			action:   "review_requested",
+			body:     `{"number": 7, "pull_request": {"id": 1, "user": {"login": "alice", "pronouns": "they"}, "auto_merge_v2": {}}}`,
+			expected: []string{"missing field requested_reviewer.login|requested_team.name", `unknown field "pronouns" in pull_request`},
+		},
+		{
+			action:   "created",
+			body:     `{"issue": {"number": 7, "state_reason": null, "user": {"login": "alice", "user_view_type": "public"}}, "comment": {"issue_url": "https://api.github.com/repos/o/api/issues/7", "html_url": "https://github.com/o/api/pull/7#issuecomment-1", "user": {"login": "bob"}}, "repository": {"name": "api", "allow_forking": true}}`,
+			expected: []string{},
+		},
+		{
+			action:   "created",
+			body:     `{"pull_request": {"id": 1}, "comment": {"issue_url": "https://api.github.com/repos/o/api/issues/7", "html_url": "https://github.com/o/api/pull/7#discussion_r1", "user": {"login": "bob"}}, "repository": {"name": "api"}}`,
+			expected: []string{`unknown field "issue_url" in comment`},
+		},
+		{
+			action:   "edited",
+			body:     `{"sender": {"login": "alice", "id": "1"}, "installation": {"id": 1}}`,
+			expected: []string{"invalid sender: json: cannot unmarshal string into Go struct field User.id of type int64"},
+		},
+	}
+
+	for _, tt := range tests {
+		problems, err := Drift(tt.action, []byte(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(problems, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.action, tt.expected, problems)
+		}
+	}
+
+	if _, err := Drift("opened", []byte(`not json`)); err == nil {
+		t.Errorf("Expected an error for an invalid body")
+	}
+}
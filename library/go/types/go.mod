@@ -1,3 +1,7 @@
 module slack-pr-lambda/types
 
 go 1.22
+
+require github.com/google/go-github/v39 v39.2.0
+
+require github.com/google/go-querystring v1.1.0 // indirect
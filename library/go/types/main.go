@@ -1,58 +1,297 @@
 package types
 
+import "github.com/google/go-github/v39/github"
+
 type TablePullRequestData struct {
-	ID             string `json:"id"`
-	PullRequestId  int    `json:"pullRequestId"`
-	SlackTimeStamp string `json:"slackTimeStamp"`
+	ID                string `json:"id"`
+	PullRequestId     int    `json:"pullRequestId"`
+	SlackTimeStamp    string `json:"slackTimeStamp"`
+	Repository        string `json:"repository"`
+	CreatedAt         string `json:"createdAt"`
+	ParentMessage     string `json:"parentMessage"`
+	Approvals         int    `json:"approvals"`
+	RequiredApprovals int    `json:"requiredApprovals"`
+	AgeBadge          string `json:"ageBadge"`
+	// comment notifications sent in the thread, later ones are rolled up
+	CommentNotifications int `json:"commentNotifications"`
+	// comments waiting for the next rollup since PendingSince
+	PendingComments int    `json:"pendingComments"`
+	PendingSince    string `json:"pendingSince"`
+	// changes requested since the last push, the author is nudged once it is old
+	ChangesRequestedAt  string `json:"changesRequestedAt"`
+	ChangesRequestedBy  string `json:"changesRequestedBy"`
+	ChangesRequestedUrl string `json:"changesRequestedUrl"`
+	Author              string `json:"author"`
+	// labels matched against the review SLA rules
+	Labels []string `json:"labels"`
+	// first review by someone else than the author, and when the SLA was breached
+	FirstReviewAt string `json:"firstReviewAt"`
+	SlaBreachedAt string `json:"slaBreachedAt"`
+	// escalation levels already notified
+	EscalationLevel int `json:"escalationLevel"`
+	// last reviewer reminder, the next one follows REMINDER_INTERVAL_HOURS later
+	RemindedAt string `json:"remindedAt"`
+	// WIP title, reviewers are pinged once the prefix is removed
+	WorkInProgress bool `json:"workInProgress"`
+	// workflows whose last run failed, their next passing run is announced
+	FailedWorkflows []string `json:"failedWorkflows" dynamodbav:"failedWorkflows,omitempty,stringset"`
+	// required checks of the base branch, those that passed on the head and
+	// when the ready to merge ping was posted
+	HeadSha        string   `json:"headSha"`
+	RequiredChecks []string `json:"requiredChecks"`
+	PassedChecks   []string `json:"passedChecks" dynamodbav:"passedChecks,omitempty,stringset"`
+	ReadyToMergeAt string   `json:"readyToMergeAt"`
+	// "<login>=<thread ts>" of the "Please review" pings waiting for a review
+	ReviewPings []string `json:"reviewPings" dynamodbav:"reviewPings,omitempty,stringset"`
+	// "org/repo#123" pull requests declared in the body and those merged since
+	Dependencies       []string `json:"dependencies"`
+	MergedDependencies []string `json:"mergedDependencies" dynamodbav:"mergedDependencies,omitempty,stringset"`
+	// changed files per top-level directory, RootDirectory for the root
+	ChangedDirectories map[string]int `json:"changedDirectories"`
+	// e.g. "Mostly generated files (14/16)", empty for a regular diff
+	DiffClass string `json:"diffClass"`
+	// checked and total checkboxes of the body, e.g. of the pull request template
+	ChecklistDone  int `json:"checklistDone"`
+	ChecklistTotal int `json:"checklistTotal"`
+	// chat.getPermalink of the parent message
+	Permalink string `json:"permalink"`
+	// bumped by the updates of the fields rendered on the parent message, 0
+	// for records stored before versions
+	Version int `json:"version"`
+	// when the pull request was closed, the soft-deleted record is purged
+	// SOFT_DELETE_DAYS later
+	DeletedAt string `json:"deletedAt" dynamodbav:"deletedAt,omitempty"`
 }
 
 type OpenPullRequest struct {
-	Action      string                `json:"action"`
-	Number      int                   `json:"number"`
-	PullRequest pullRequest           `json:"pull_request"`
-	Repository  pullRequestRepository `json:"repository"`
-	Sender      sender                `json:"sender"`
+	Action      string              `json:"action"`
+	Number      int                 `json:"number"`
+	PullRequest *github.PullRequest `json:"pull_request"`
+	Repository  *github.Repository  `json:"repository"`
+	Sender      *github.User        `json:"sender"`
 }
 
 type ReviewRequestPullRequest struct {
-	Action            string               `json:"action"`
-	Number            int                  `json:"number"`
-	PullRequest       pullRequest          `json:"pull_request"`
-	RequestedReviewer pullRequestReviewers `json:"requested_reviewer"`
+	Action            string              `json:"action"`
+	Number            int                 `json:"number"`
+	PullRequest       *github.PullRequest `json:"pull_request"`
+	RequestedReviewer *github.User        `json:"requested_reviewer"`
+	// set instead of the reviewer when a team is requested
+	RequestedTeam *github.Team `json:"requested_team"`
 }
 
 type CommentPullRequest struct {
-	Action     string                `json:"action"`
-	Issue      issue                 `json:"issue"`
-	Comment    comment               `json:"comment"`
-	Repository pullRequestRepository `json:"repository"`
+	Action     string               `json:"action"`
+	Issue      *github.Issue        `json:"issue"`
+	Comment    *github.IssueComment `json:"comment"`
+	Repository *github.Repository   `json:"repository"`
 }
 
 type ClosedPullRequest struct {
-	Action      string      `json:"action"`
-	Number      int         `json:"number"`
-	PullRequest pullRequest `json:"pull_request"`
-	Sender      sender      `json:"sender"`
+	Action      string              `json:"action"`
+	Number      int                 `json:"number"`
+	PullRequest *github.PullRequest `json:"pull_request"`
+	Sender      *github.User        `json:"sender"`
 }
 
 type SubmitReviewPullRequest struct {
-	Action      string      `json:"action"`
-	PullRequest pullRequest `json:"pull_request"`
-	Review      review      `json:"review"`
+	Action      string                    `json:"action"`
+	PullRequest *github.PullRequest       `json:"pull_request"`
+	Repository  *github.Repository        `json:"repository"`
+	Review      *github.PullRequestReview `json:"review"`
 }
 
 type PushPullRequestSync struct {
-	Action      string                `json:"action"`
-	Number      int                   `json:"number"`
-	PullRequest pullRequest           `json:"pull_request"`
-	Repository  pullRequestRepository `json:"repository"`
-	After       string                `json:"after"`
-	Sender      sender                `json:"sender"`
+	Action      string              `json:"action"`
+	Number      int                 `json:"number"`
+	PullRequest *github.PullRequest `json:"pull_request"`
+	Repository  *github.Repository  `json:"repository"`
+	After       string              `json:"after"`
+	Sender      *github.User        `json:"sender"`
 }
 
 type CheckRunPullRequest struct {
-	Action     string                `json:"action"`
-	Repository pullRequestRepository `json:"repository"`
-	Sender     sender                `json:"sender"`
-	CheckRun   checkRun              `json:"check_run"`
+	Action     string             `json:"action"`
+	Repository *github.Repository `json:"repository"`
+	Sender     *github.User       `json:"sender"`
+	CheckRun   *github.CheckRun   `json:"check_run"`
+}
+
+type TableOutOfOfficeData struct {
+	SlackUserId string `json:"slackUserId"`
+	GithubLogin string `json:"githubLogin"`
+	StartDate   string `json:"startDate"`
+	EndDate     string `json:"endDate"`
+}
+
+type TableSnoozeData struct {
+	ID          string `json:"id"`
+	GithubLogin string `json:"githubLogin"`
+	SnoozeUntil int64  `json:"snoozeUntil"`
+}
+
+// thread notifications of "<repository>#<number>" muted for a slack user, or
+// for the whole channel when slackUserId is "channel"
+type TableMuteData struct {
+	PullRequest string `json:"pullRequest"`
+	SlackUserId string `json:"slackUserId"`
+	MutedAt     string `json:"mutedAt"`
+}
+
+// mentions of a thread posted as plain names outside of the mention hours,
+// mentioned for real in the thread once DueAt (unix) passed
+type TableDeferredMentionData struct {
+	PullRequest     string   `json:"pullRequest"`
+	ThreadTimeStamp string   `json:"threadTimeStamp"`
+	Repository      string   `json:"repository"`
+	Number          int      `json:"number"`
+	SlackUserIds    []string `json:"slackUserIds"`
+	DueAt           int64    `json:"dueAt"`
+}
+
+// digest or report job triggered outside of the digest window, delivered by the
+// digests job once DueAt passed
+type TablePendingDigestData struct {
+	Job   string `json:"job"`
+	DueAt int64  `json:"dueAt"`
+}
+
+// open security alert posted to the security channel, alert is
+// "<repository>#<kind>-<number>". Reminded in its thread while it is past the
+// SLA of its severity
+type TableSecurityAlertData struct {
+	Alert          string `json:"alert"`
+	Kind           string `json:"kind"`
+	Repository     string `json:"repository"`
+	Number         int64  `json:"number"`
+	Severity       string `json:"severity"`
+	Summary        string `json:"summary"`
+	Package        string `json:"package"`
+	PatchedVersion string `json:"patchedVersion"`
+	HtmlUrl        string `json:"htmlUrl"`
+	CreatedAt      string `json:"createdAt"`
+	SlackTimeStamp string `json:"slackTimeStamp"`
+	RemindedAt     string `json:"remindedAt"`
+}
+
+// slack user direct messaged on approvals, failed checks and the merge of
+// "<repository>#<number>"
+type TableSubscriptionData struct {
+	PullRequest  string `json:"pullRequest"`
+	SlackUserId  string `json:"slackUserId"`
+	SubscribedAt string `json:"subscribedAt"`
+}
+
+// github user notified by email instead of Slack mentions
+type TableEmailPreferenceData struct {
+	GithubLogin string `json:"githubLogin"`
+	Email       string `json:"email"`
+	OptedInAt   string `json:"optedInAt"`
+}
+
+// one Slack message sent by the bot, pullRequest is "<repository>#<number>"
+type TableAuditData struct {
+	PullRequest     string `json:"pullRequest"`
+	SentAt          string `json:"sentAt"`
+	EventId         string `json:"eventId"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Channel         string `json:"channel"`
+	TimeStamp       string `json:"timeStamp"`
+	ThreadTimeStamp string `json:"threadTimeStamp"`
+	Text            string `json:"text"`
+}
+
+// author of a repository with their first known pull request, pullRequest is
+// "<repository>#<number>"
+type TableContributorData struct {
+	Repository  string `json:"repository"`
+	Author      string `json:"author"`
+	PullRequest string `json:"pullRequest"`
+	RecordedAt  string `json:"recordedAt"`
+}
+
+// lifecycle timestamps of a pull request kept after it is closed, pullRequest is
+// "<repository>#<number>"
+type TableReviewMetricsData struct {
+	PullRequest   string   `json:"pullRequest"`
+	Repository    string   `json:"repository"`
+	Number        int      `json:"number"`
+	Author        string   `json:"author"`
+	OpenedAt      string   `json:"openedAt"`
+	FirstReviewAt string   `json:"firstReviewAt"`
+	MergedAt      string   `json:"mergedAt"`
+	ClosedAt      string   `json:"closedAt"`
+	Reviewers     []string `json:"reviewers"`
+	// open, merged or abandoned (closed without merging)
+	State string `json:"state"`
+}
+
+// one processed webhook of a pull request, pullRequest is
+// "<repository>#<number>". Appended, never updated, so the lifecycle of the
+// pull request can be rebuilt in order
+type TableEventData struct {
+	PullRequest string `json:"pullRequest"`
+	// SortableTime, orders the events of the pull request
+	ReceivedAt string `json:"receivedAt"`
+	// X-GitHub-Delivery
+	EventId string `json:"eventId"`
+	// X-GitHub-Event, e.g. pull_request or pull_request_review
+	Event  string `json:"event"`
+	Action string `json:"action"`
+	// login of the sender of the webhook
+	Actor string `json:"actor"`
+}
+
+// review comments of a reviewer on a pull request batched into one thread
+// reply, id is "<repository>#<number>#<login>"
+type TableCommentBatchData struct {
+	ID             string   `json:"id"`
+	SlackTimeStamp string   `json:"slackTimeStamp"`
+	StartedAt      int64    `json:"startedAt"`
+	Comments       int      `json:"comments"`
+	Files          []string `json:"files" dynamodbav:"files,stringset"`
+	ExpiresAt      int64    `json:"expiresAt"`
+}
+
+// Slack message posted recently, keyed by the hash of its thread and text so
+// an identical message is not posted twice within the dedup window
+type TableRecentMessageData struct {
+	MessageHash string `json:"messageHash"`
+	// empty while the message is being sent
+	SlackTimeStamp string `json:"slackTimeStamp"`
+	PostedAt       string `json:"postedAt"`
+	ExpiresAt      int64  `json:"expiresAt"`
+}
+
+// completed step of the processing of a delivery, e.g. the parent message.
+// Redeliveries of the event id reuse it instead of doing the step again
+type TableCheckpointData struct {
+	EventId string `json:"eventId"`
+	Step    string `json:"step"`
+	// message posted by the step, if any
+	SlackTimeStamp string `json:"slackTimeStamp"`
+	CompletedAt    string `json:"completedAt"`
+	ExpiresAt      int64  `json:"expiresAt"`
+}
+
+// pinned "Open PRs" dashboard message of a channel
+type TableDashboardData struct {
+	Channel        string `json:"channel"`
+	SlackTimeStamp string `json:"slackTimeStamp"`
+	UpdatedAt      string `json:"updatedAt"`
+}
+
+// value of the buttons on reminder messages, the key of the pull request record
+type PullRequestActionValue struct {
+	ID            string `json:"id"`
+	PullRequestId int    `json:"pullRequestId"`
+}
+
+// versioned per repository / organization configuration document
+type TableConfigData struct {
+	ID        string `json:"id"`
+	Version   int    `json:"version"`
+	Document  string `json:"document"`
+	UpdatedAt string `json:"updatedAt"`
 }
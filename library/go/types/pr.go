@@ -1,77 +1,167 @@
 package types
 
-type pullRequestUser struct {
-	Login string `json:"login"`
-	ID    int    `json:"id"`
-}
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
 
-type pullRequestReviewers struct {
-	Login string `json:"login"`
-	ID    int    `json:"id"`
-}
+	"github.com/google/go-github/v39/github"
+)
 
-type pullRequest struct {
-	ID                 int                    `json:"id"`
-	Number             int                    `json:"number"`
-	HtmlUrl            string                 `json:"html_url"`
-	State              string                 `json:"state"`
-	Locked             bool                   `json:"locked"`
-	Title              string                 `json:"title"`
-	User               pullRequestUser        `json:"user"`
-	RequestedReviewers []pullRequestReviewers `json:"requested_reviewers"`
-	MergedAt           string                 `json:"merged_at"`
-}
+// the payload objects are the go-github types of the REST API, which share
+// their fields with the webhook deliveries. Optional and nullable fields are
+// pointers, read them with the Get accessors which are safe on nil
 
-type sender struct {
-	Login string `json:"login"`
-	ID    int    `json:"id"`
+// names of the labels of the pull request
+func LabelNames(pullRequest *github.PullRequest) []string {
+	names := []string{}
+	for _, label := range pullRequest.Labels {
+		names = append(names, label.GetName())
+	}
+	return names
 }
 
-type pullRequestRepository struct {
-	ID      int    `json:"id"`
-	Name    string `json:"name"`
-	HtmlUrl string `json:"html_url"`
+// layout of the timestamps used as range keys: RFC3339Nano trims the trailing
+// zeros of the fraction, ".5Z" would sort after ".45Z", the fixed nine digits
+// sort in time order as strings. Format UTC times with it
+const SortableTime = "2006-01-02T15:04:05.000000000Z07:00"
+
+// RFC3339 of an optional timestamp, empty when it is not set
+func FormatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
 }
 
-type issue struct {
-	ID      int    `json:"id"`
-	Number  int    `json:"number"`
-	HtmlUrl string `json:"html_url"`
+// titles starting with "WIP" or "[WIP]", in any case, are handled like drafts.
+// "Wipe the cache" is not
+func WorkInProgress(title string) bool {
+	title = strings.ToUpper(strings.TrimSpace(title))
+	if strings.HasPrefix(title, "[WIP]") {
+		return true
+	}
+
+	rest, ok := strings.CutPrefix(title, "WIP")
+	if !ok {
+		return false
+	}
+	next := []rune(rest)
+	return len(next) == 0 || !unicode.IsLetter(next[0]) && !unicode.IsDigit(next[0])
 }
 
-type comment struct {
-	ID      int             `json:"id"`
-	HtmlUrl string          `json:"html_url"`
-	Body    string          `json:"body"`
-	User    pullRequestUser `json:"user"`
+const dependencyReference = `[\w.-]+/[\w.-]+#\d+`
+
+var (
+	// "Depends on a/b#1, c/d#2 and e/f#3" declares all three
+	dependencyPattern = regexp.MustCompile(`(?i)depends on:?\s+(` + dependencyReference +
+		`(?:(?:\s*,\s*(?:and\s+)?|\s+and\s+|\s*&\s*)` + dependencyReference + `)*)`)
+	dependencyReferencePattern = regexp.MustCompile(dependencyReference)
+)
+
+// "org/repo#123" pull requests declared as "Depends on org/repo#123" in the
+// body, in order and without duplicates
+func Dependencies(body string) []string {
+	dependencies := []string{}
+	for _, match := range dependencyPattern.FindAllStringSubmatch(body, -1) {
+		for _, reference := range dependencyReferencePattern.FindAllString(match[1], -1) {
+			duplicate := false
+			for _, dependency := range dependencies {
+				duplicate = duplicate || strings.EqualFold(dependency, reference)
+			}
+			if !duplicate {
+				dependencies = append(dependencies, reference)
+			}
+		}
+	}
+	return dependencies
 }
 
-type review struct {
-	ID      int             `json:"id"`
-	HtmlUrl string          `json:"html_url"`
-	User    pullRequestUser `json:"user"`
-	Body    string          `json:"body"`
-	State   string          `json:"state"`
+var (
+	checklistItemPattern = regexp.MustCompile(`(?m)^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]`)
+	htmlCommentPattern   = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// checked and total markdown checkboxes of the body, e.g. the "Types of
+// changes" of a pull request template. Boxes left in HTML comments of the
+// template don't count
+func Checklist(body string) (int, int) {
+	done := 0
+	matches := checklistItemPattern.FindAllStringSubmatch(htmlCommentPattern.ReplaceAllString(body, ""), -1)
+	for _, match := range matches {
+		if match[1] != " " {
+			done++
+		}
+	}
+	return done, len(matches)
 }
 
-type checkRunPullRequest struct {
-	ID     int `json:"id"`
-	Number int `json:"number"`
+// image of a markdown text, e.g. a screenshot attached to a comment
+type Image struct {
+	Url string
+	Alt string
 }
 
-type checkSuite struct {
-	ID         int    `json:"id"`
-	Status     string `json:"status"`
-	Conclusion string `json:"conclusion"`
+var (
+	markdownImagePattern = regexp.MustCompile(`!\[([^\]]*)\]\((https?://[^\s)]+)(?:\s+"[^"]*")?\)`)
+	htmlImagePattern     = regexp.MustCompile(`(?i)<img\s[^>]*>`)
+	htmlSrcPattern       = regexp.MustCompile(`(?i)\bsrc\s*=\s*"(https?://[^"]+)"`)
+	htmlAltPattern       = regexp.MustCompile(`(?i)\balt\s*=\s*"([^"]*)"`)
+)
+
+// markdown (![alt](url)) and HTML (<img src>) images of the body in order of
+// appearance, without duplicates. Only http(s) urls are kept
+func Images(body string) []Image {
+	type found struct {
+		at    int
+		image Image
+	}
+	matches := []found{}
+	for _, match := range markdownImagePattern.FindAllStringSubmatchIndex(body, -1) {
+		matches = append(matches, found{match[0], Image{Url: body[match[4]:match[5]], Alt: body[match[2]:match[3]]}})
+	}
+	for _, match := range htmlImagePattern.FindAllStringIndex(body, -1) {
+		tag := body[match[0]:match[1]]
+		src := htmlSrcPattern.FindStringSubmatch(tag)
+		if src == nil {
+			continue
+		}
+		image := Image{Url: src[1]}
+		if alt := htmlAltPattern.FindStringSubmatch(tag); alt != nil {
+			image.Alt = alt[1]
+		}
+		matches = append(matches, found{match[0], image})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].at < matches[j].at
+	})
+
+	images := []Image{}
+	seen := map[string]bool{}
+	for _, match := range matches {
+		if !seen[match.image.Url] {
+			seen[match.image.Url] = true
+			images = append(images, match.image)
+		}
+	}
+	return images
 }
 
-type checkRun struct {
-	ID           int                   `json:"id"`
-	Name         string                `json:"name"`
-	HtmlUrl      string                `json:"html_url"`
-	Status       string                `json:"status"`
-	Conclusion   string                `json:"conclusion"`
-	CompletedAt  string                `json:"completed_at"`
-	PullRequests []checkRunPullRequest `json:"pull_requests"`
-	CheckSuite   checkSuite            `json:"check_suite"`
+// key of the files at the root in ChangedDirectories, dynamodb can't store an
+// empty map key
+const RootDirectory = "/"
+
+// changed files per top-level directory, RootDirectory for the files at the root
+func ChangedDirectories(files []string) map[string]int {
+	directories := map[string]int{}
+	for _, file := range files {
+		directory, _, ok := strings.Cut(file, "/")
+		if !ok {
+			directory = RootDirectory
+		}
+		directories[directory]++
+	}
+	return directories
 }
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestWorkInProgress(t *testing.T) {
+	tests := []struct {
+		title string
+		wip   bool
+	}{
+		{"WIP: add reminders", true},
+		{"[WIP] add reminders", true},
+		{"  wip add reminders", true},
+		{"WIP", true},
+		{"Wipe the cache", false},
+		{"Add WIP detection", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if result := WorkInProgress(tt.title); result != tt.wip {
+			t.Errorf("%q: got %v want %v", tt.title, result, tt.wip)
+		}
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	tests := []struct {
+		body         string
+		dependencies []string
+	}{
+		{"Depends on acme/api#123", []string{"acme/api#123"}},
+		{"Adds the client.\n\ndepends on: acme/api#12\nDepends on acme/web.app#7", []string{"acme/api#12", "acme/web.app#7"}},
+		{"Depends on acme/api#12, depends on Acme/API#12", []string{"acme/api#12"}},
+		{"Depends on acme/api#1, acme/web#2 and acme/cli#3", []string{"acme/api#1", "acme/web#2", "acme/cli#3"}},
+		{"Depends on acme/api#1 & acme/web#2, and see acme/docs#4", []string{"acme/api#1", "acme/web#2"}},
+		{"Depends on #12", []string{}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		if result := Dependencies(tt.body); !reflect.DeepEqual(result, tt.dependencies) {
+			t.Errorf("%q: got %v want %v", tt.body, result, tt.dependencies)
+		}
+	}
+}
+
+func TestChecklist(t *testing.T) {
+	tests := []struct {
+		body  string
+		done  int
+		total int
+	}{
+		{"## Types of changes\n- [x] Bug fix\n- [ ] New feature\n- [X] Breaking change", 2, 3},
+		{"* [ ] tests\n  + [x] nested\n1. [x] numbered", 2, 3},
+		{"<!-- - [ ] hidden -->\n- [x] shown", 1, 1},
+		{"not [x] a list item\n- [] empty", 0, 0},
+		{"", 0, 0},
+	}
+
+	for _, tt := range tests {
+		if done, total := Checklist(tt.body); done != tt.done || total != tt.total {
+			t.Errorf("%q: got %d/%d want %d/%d", tt.body, done, total, tt.done, tt.total)
+		}
+	}
+}
+
+func TestImages(t *testing.T) {
+	body := "## Screenshots\n" +
+		"Before ![before](https://github.com/user-attachments/assets/1 \"old\") after\n" +
+		"<img width=\"300\" alt=\"After\" src=\"https://github.com/user-attachments/assets/2\">\n" +
+		"![again](https://github.com/user-attachments/assets/1)\n" +
+		"![relative](docs/diagram.png) <img src=\"data:image/png;base64,AA\">"
+
+	expected := []Image{
+		{Url: "https://github.com/user-attachments/assets/1", Alt: "before"},
+		{Url: "https://github.com/user-attachments/assets/2", Alt: "After"},
+	}
+	if images := Images(body); !reflect.DeepEqual(images, expected) {
+		t.Errorf("got %v want %v", images, expected)
+	}
+	if images := Images("no images"); len(images) != 0 {
+		t.Errorf("Expected no images, got %v", images)
+	}
+}
+
+func TestChangedDirectories(t *testing.T) {
+	files := []string{"web/src/app.ts", "web/package.json", "api/main.go", "README.md"}
+
+	expected := map[string]int{"web": 2, "api": 1, "/": 1}
+	if result := ChangedDirectories(files); !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %v want %v", result, expected)
+	}
+}
+
+func TestSortableTime(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	times := []time.Time{
+		start.Add(450 * time.Millisecond),
+		start.Add(500 * time.Millisecond),
+		start.Add(time.Second),
+		start.Add(123 * time.Millisecond),
+	}
+
+	keys := []string{}
+	for _, at := range times {
+		keys = append(keys, at.Format(SortableTime))
+	}
+	sort.Strings(keys)
+
+	expected := []string{
+		"2024-03-01T10:00:00.123000000Z",
+		"2024-03-01T10:00:00.450000000Z",
+		"2024-03-01T10:00:00.500000000Z",
+		"2024-03-01T10:00:01.000000000Z",
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected the keys in time order, got %v", keys)
+	}
+}
@@ -0,0 +1,215 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// version of the WebhookEvent envelope, bumped when the handled actions or
+// the fields they read change so unhandled deliveries can be told apart
+const WebhookSchemaVersion = 3
+
+// every handled delivery decoded once, the fields of other event types are
+// left empty. Converted to the typed payload of the action it is handled as
+type WebhookEvent struct {
+	Action            string                     `json:"action"`
+	Number            int                        `json:"number"`
+	PullRequest       *github.PullRequest        `json:"pull_request"`
+	Repository        *github.Repository         `json:"repository"`
+	Sender            *github.User               `json:"sender"`
+	RequestedReviewer *github.User               `json:"requested_reviewer"`
+	RequestedTeam     *github.Team               `json:"requested_team"`
+	Issue             *github.Issue              `json:"issue"`
+	Comment           *github.PullRequestComment `json:"comment"`
+	Review            *github.PullRequestReview  `json:"review"`
+	After             string                     `json:"after"`
+	CheckRun          *github.CheckRun           `json:"check_run"`
+	WorkflowRun       *github.WorkflowRun        `json:"workflow_run"`
+	Changes           *github.EditChange         `json:"changes"`
+	// dependabot_alert, repository_vulnerability_alert, code_scanning_alert
+	// and secret_scanning_alert
+	Alert *SecurityAlert `json:"alert"`
+	// branch of a code scanning alert, e.g. refs/heads/main or refs/pull/7/merge
+	Ref string `json:"ref"`
+	// why a pull request left the merge queue, e.g. CI_FAILURE
+	Reason     string      `json:"reason"`
+	MergeGroup *MergeGroup `json:"merge_group"`
+}
+
+// merge_group payload, go-github v39 predates merge queues
+type MergeGroup struct {
+	HeadSha string `json:"head_sha"`
+	// refs/heads/gh-readonly-queue/<base>/pr-<number>-<sha>
+	HeadRef string `json:"head_ref"`
+	BaseRef string `json:"base_ref"`
+}
+
+// number of the last pull request of the merge group, 0 when the head ref
+// is not a merge queue branch
+func (g *MergeGroup) Number() int {
+	if g == nil {
+		return 0
+	}
+	_, entry, found := strings.Cut(g.HeadRef, "/pr-")
+	if !found {
+		return 0
+	}
+	number, _, _ := strings.Cut(entry, "-")
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func (e WebhookEvent) OpenPullRequest() OpenPullRequest {
+	return OpenPullRequest{
+		Action:      e.Action,
+		Number:      e.Number,
+		PullRequest: e.PullRequest,
+		Repository:  e.Repository,
+		Sender:      e.Sender,
+	}
+}
+
+func (e WebhookEvent) ReviewRequestPullRequest() ReviewRequestPullRequest {
+	return ReviewRequestPullRequest{
+		Action:            e.Action,
+		Number:            e.Number,
+		PullRequest:       e.PullRequest,
+		RequestedReviewer: e.RequestedReviewer,
+		RequestedTeam:     e.RequestedTeam,
+	}
+}
+
+func (e WebhookEvent) CommentPullRequest() CommentPullRequest {
+	// issue comments are delivered without the review comment fields
+	var comment *github.IssueComment
+	if e.Comment != nil {
+		comment = &github.IssueComment{
+			ID:      e.Comment.ID,
+			Body:    e.Comment.Body,
+			User:    e.Comment.User,
+			HTMLURL: e.Comment.HTMLURL,
+		}
+	}
+
+	return CommentPullRequest{
+		Action:     e.Action,
+		Issue:      e.Issue,
+		Comment:    comment,
+		Repository: e.Repository,
+	}
+}
+
+func (e WebhookEvent) ClosedPullRequest() ClosedPullRequest {
+	return ClosedPullRequest{
+		Action:      e.Action,
+		Number:      e.Number,
+		PullRequest: e.PullRequest,
+		Sender:      e.Sender,
+	}
+}
+
+func (e WebhookEvent) SubmitReviewPullRequest() SubmitReviewPullRequest {
+	return SubmitReviewPullRequest{
+		Action:      e.Action,
+		PullRequest: e.PullRequest,
+		Repository:  e.Repository,
+		Review:      e.Review,
+	}
+}
+
+func (e WebhookEvent) PushPullRequestSync() PushPullRequestSync {
+	return PushPullRequestSync{
+		Action:      e.Action,
+		Number:      e.Number,
+		PullRequest: e.PullRequest,
+		Repository:  e.Repository,
+		After:       e.After,
+		Sender:      e.Sender,
+	}
+}
+
+func (e WebhookEvent) CheckRunPullRequest() CheckRunPullRequest {
+	return CheckRunPullRequest{
+		Action:     e.Action,
+		Repository: e.Repository,
+		Sender:     e.Sender,
+		CheckRun:   e.CheckRun,
+	}
+}
+
+// number of the pull request the delivery is about, 0 when there is none
+func (e WebhookEvent) PullRequestNumber() int {
+	if e.Number > 0 {
+		return e.Number
+	}
+
+	// reviews and review comments
+	if number := e.PullRequest.GetNumber(); number > 0 {
+		return number
+	}
+
+	// check runs, should always only have one pull request
+	if e.CheckRun != nil && len(e.CheckRun.PullRequests) > 0 {
+		return e.CheckRun.PullRequests[0].GetNumber()
+	}
+
+	if e.WorkflowRun != nil && len(e.WorkflowRun.PullRequests) > 0 {
+		return e.WorkflowRun.PullRequests[0].GetNumber()
+	}
+
+	return e.MergeGroup.Number()
+}
+
+// normalized summary of a processed webhook, posted to the FORWARD_URLS
+type ForwardedEvent struct {
+	// X-GitHub-Delivery
+	DeliveryId string `json:"deliveryId"`
+	// X-GitHub-Event and action, e.g. pull_request_review submitted
+	Event      string `json:"event"`
+	Action     string `json:"action"`
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	// login of the sender of the webhook
+	Actor  string `json:"actor"`
+	Title  string `json:"title,omitempty"`
+	Url    string `json:"url,omitempty"`
+	Author string `json:"author,omitempty"`
+	// open, closed or merged, empty for events without the pull request
+	State string `json:"state,omitempty"`
+	// approved, commented or changes_requested on reviews
+	ReviewState string `json:"reviewState,omitempty"`
+	// RFC3339
+	ReceivedAt string `json:"receivedAt"`
+}
+
+// normalized summary of the event, state is the pull request state after it
+func (e WebhookEvent) Forwarded(githubEvent string, deliveryId string, receivedAt string) ForwardedEvent {
+	forwarded := ForwardedEvent{
+		DeliveryId: deliveryId,
+		Event:      githubEvent,
+		Action:     e.Action,
+		Repository: e.Repository.GetName(),
+		Number:     e.PullRequestNumber(),
+		Actor:      e.Sender.GetLogin(),
+		ReceivedAt: receivedAt,
+	}
+
+	if pullRequest := e.PullRequest; pullRequest != nil {
+		forwarded.Title = pullRequest.GetTitle()
+		forwarded.Url = pullRequest.GetHTMLURL()
+		forwarded.Author = pullRequest.GetUser().GetLogin()
+		forwarded.State = pullRequest.GetState()
+		if pullRequest.GetMerged() {
+			forwarded.State = "merged"
+		}
+	}
+	if e.Review != nil {
+		forwarded.ReviewState = strings.ToLower(e.Review.GetState())
+	}
+	return forwarded
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWebhookEventOptionalFields(t *testing.T) {
+	body := `{
+		"action": "closed",
+		"number": 7,
+		"pull_request": {"id": 42, "number": 7, "created_at": "2024-03-10T10:00:00Z", "closed_at": "2024-03-11T10:00:00Z", "merged_at": null, "labels": [{"name": "hotfix"}]},
+		"requested_team": {"name": "platform"}
+	}`
+
+	var event WebhookEvent
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	input := event.ClosedPullRequest()
+	if input.PullRequest.MergedAt != nil {
+		t.Errorf("Expected a pull request closed without merging, got %v", input.PullRequest.MergedAt)
+	}
+	if closedAt := FormatTime(input.PullRequest.ClosedAt); closedAt != "2024-03-11T10:00:00Z" {
+		t.Errorf("Expected the closing time, got %q", closedAt)
+	}
+	if labels := LabelNames(input.PullRequest); len(labels) != 1 || labels[0] != "hotfix" {
+		t.Errorf("Expected the hotfix label, got %v", labels)
+	}
+
+	// objects missing from the delivery are nil, their accessors are zero values
+	if input.Sender.GetLogin() != "" || event.Repository.GetName() != "" {
+		t.Errorf("Expected no sender nor repository, got %v %v", input.Sender, event.Repository)
+	}
+
+	request := event.ReviewRequestPullRequest()
+	if request.RequestedReviewer != nil || request.RequestedTeam.GetName() != "platform" {
+		t.Errorf("Expected a team review request, got %v %v", request.RequestedReviewer, request.RequestedTeam)
+	}
+
+	if comment := event.CommentPullRequest().Comment; comment != nil {
+		t.Errorf("Expected no comment, got %v", comment)
+	}
+}
+
+func TestWebhookEventForwarded(t *testing.T) {
+	body := `{
+		"action": "submitted",
+		"pull_request": {"number": 7, "title": "Fix login", "html_url": "https://github.com/o/api/pull/7", "state": "open", "user": {"login": "alice"}},
+		"review": {"state": "APPROVED"},
+		"repository": {"name": "api"},
+		"sender": {"login": "bob"}
+	}`
+
+	var event WebhookEvent
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	forwarded := event.Forwarded("pull_request_review", "delivery", "2024-03-10T10:00:00Z")
+	expected := ForwardedEvent{
+		DeliveryId:  "delivery",
+		Event:       "pull_request_review",
+		Action:      "submitted",
+		Repository:  "api",
+		Number:      7,
+		Actor:       "bob",
+		Title:       "Fix login",
+		Url:         "https://github.com/o/api/pull/7",
+		Author:      "alice",
+		State:       "open",
+		ReviewState: "approved",
+		ReceivedAt:  "2024-03-10T10:00:00Z",
+	}
+	if forwarded != expected {
+		t.Errorf("got %+v want %+v", forwarded, expected)
+	}
+}
+
+func TestWebhookEventMergeGroup(t *testing.T) {
+	body := `{
+		"action": "checks_requested",
+		"merge_group": {"head_sha": "ec26c3e", "head_ref": "refs/heads/gh-readonly-queue/main/pr-7-f95f852", "base_ref": "refs/heads/main"}
+	}`
+
+	var event WebhookEvent
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if number := event.PullRequestNumber(); number != 7 {
+		t.Errorf("Expected the pull request of the merge group, got %d", number)
+	}
+	if number := (&MergeGroup{HeadRef: "refs/heads/main"}).Number(); number != 0 {
+		t.Errorf("Expected no pull request outside of the merge queue, got %d", number)
+	}
+}
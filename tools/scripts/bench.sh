@@ -0,0 +1,11 @@
+#!/bin/bash
+
+set -e
+
+# packages with benchmarks, each module is benchmarked from its own directory
+PACKAGES="library/go/types library/go/redact library/go/slack app/api/messages"
+ROOT=$(git rev-parse --show-toplevel)
+
+for package in $PACKAGES; do
+  (cd "$ROOT/$package" && go test -run '^$' -bench . -benchmem -count 3 .)
+done | tee /dev/stderr | (cd "$ROOT/app/api" && go run ./cmd/benchcheck -thresholds "$ROOT/tools/scripts/benchmarks.json")